@@ -17,10 +17,19 @@ package main
 
 import (
 	"open-match.dev/open-match/internal/app"
+	"open-match.dev/open-match/internal/app/admin"
 	"open-match.dev/open-match/internal/app/backend"
 	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/rpc"
 )
 
 func main() {
-	app.RunApplication("backend", config.Read, backend.BindService)
+	app.RunApplication("backend", config.Read, func(p *rpc.ServerParams, cfg config.View) error {
+		// The admin service is served alongside the backend, which is only
+		// reachable by trusted operator components.
+		if err := backend.BindService(p, cfg); err != nil {
+			return err
+		}
+		return admin.BindService(p, cfg)
+	})
 }
@@ -0,0 +1,44 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main runs all of Open Match in a single process, configured by a
+// single config file.
+package main
+
+import (
+	"flag"
+
+	"open-match.dev/open-match/internal/app"
+	"open-match.dev/open-match/internal/app/standalone"
+	"open-match.dev/open-match/internal/config"
+)
+
+var (
+	configPath = flag.String("config", "", "Path to the YAML config file. If empty, defaults are used.")
+)
+
+func main() {
+	flag.Parse()
+	app.RunApplication(standalone.ServerName, func() (config.View, error) {
+		cfg, err := standalone.ReadConfig(*configPath)
+		if err != nil {
+			return nil, err
+		}
+		// The store lives as long as the process.
+		if _, err := standalone.StartEmbeddedStore(cfg); err != nil {
+			return nil, err
+		}
+		return cfg, nil
+	}, standalone.BindService)
+}
@@ -17,10 +17,19 @@ package main
 
 import (
 	"open-match.dev/open-match/internal/app"
+	"open-match.dev/open-match/internal/app/janitor"
 	"open-match.dev/open-match/internal/app/synchronizer"
 	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/rpc"
 )
 
 func main() {
-	app.RunApplication("synchronizer", config.Read, synchronizer.BindService)
+	app.RunApplication("synchronizer", config.Read, func(p *rpc.ServerParams, cfg config.View) error {
+		// The janitor runs alongside the synchronizer, of which there's a single
+		// replica.
+		if err := synchronizer.BindService(p, cfg); err != nil {
+			return err
+		}
+		return janitor.BindService(p, cfg)
+	})
 }
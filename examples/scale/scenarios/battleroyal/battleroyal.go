@@ -17,45 +17,42 @@ package battleroyal
 import (
 	"fmt"
 	"io"
-	"math/rand"
+	"sort"
 	"time"
 
+	"open-match.dev/open-match/examples/scale/scenarios/latency"
 	"open-match.dev/open-match/pkg/pb"
 )
 
 const (
-	poolName  = "all"
-	regionArg = "region"
+	poolName = "all"
 )
 
-func battleRoyalRegionName(i int) string {
-	return fmt.Sprintf("region_%d", i)
-}
-
 func Scenario() *BattleRoyalScenario {
 	return &BattleRoyalScenario{
-		regions: 20,
+		latency: latency.DefaultMatrix(),
+		maxPing: 150,
 	}
 }
 
 type BattleRoyalScenario struct {
-	regions int
+	// Latency between the regions players connect from and play in.
+	latency *latency.Matrix
+	// Maximum ping of a player to the region of its matches.
+	maxPing float64
 }
 
 func (b *BattleRoyalScenario) Profiles() []*pb.MatchProfile {
 	p := []*pb.MatchProfile{}
 
-	for i := 0; i < b.regions; i++ {
+	for _, region := range b.latency.Regions {
 		p = append(p, &pb.MatchProfile{
-			Name: battleRoyalRegionName(i),
+			Name: region,
 			Pools: []*pb.Pool{
 				{
 					Name: poolName,
-					StringEqualsFilters: []*pb.StringEqualsFilter{
-						{
-							StringArg: regionArg,
-							Value:     battleRoyalRegionName(i),
-						},
+					DoubleRangeFilters: []*pb.DoubleRangeFilter{
+						latency.Filter(region, b.maxPing),
 					},
 				},
 			},
@@ -65,25 +62,32 @@ func (b *BattleRoyalScenario) Profiles() []*pb.MatchProfile {
 }
 
 func (b *BattleRoyalScenario) Ticket() *pb.Ticket {
-	// Simple way to give an uneven distribution of region population.
-	a := rand.Intn(b.regions) + 1
-	r := rand.Intn(a)
-
-	return &pb.Ticket{
-		SearchFields: &pb.SearchFields{
-			StringArgs: map[string]string{
-				regionArg: battleRoyalRegionName(r),
-			},
-		},
-	}
+	ticket := &pb.Ticket{}
+	latency.SetPings(ticket, b.latency.RandomPings(b.maxPing))
+	return ticket
 }
 
+// MatchFunction fills matches with the players with the lowest ping to the
+// profile's region first.
 func (b *BattleRoyalScenario) MatchFunction(p *pb.MatchProfile, poolTickets map[string][]*pb.Ticket) ([]*pb.Match, error) {
 	const playersInMatch = 100
 
+	region, ok := latency.FilteredRegion(p.GetPools()[0])
+	if !ok {
+		return nil, fmt.Errorf("profile %s has no ping filter", p.GetName())
+	}
+	ping := func(t *pb.Ticket) float64 {
+		ping, _ := latency.Ping(t, region)
+		return ping
+	}
+
 	tickets := poolTickets[poolName]
 	var matches []*pb.Match
 
+	sort.Slice(tickets, func(i, j int) bool {
+		return ping(tickets[i]) < ping(tickets[j])
+	})
+
 	for i := 0; i+playersInMatch <= len(tickets); i += playersInMatch {
 		matches = append(matches, &pb.Match{
 			MatchId:       fmt.Sprintf("profile-%v-time-%v-%v", p.GetName(), time.Now().Format("2006-01-02T15:04:05.00"), len(matches)),
@@ -0,0 +1,152 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package latency simulates the ping of players to the regions hosting game
+// servers, from a matrix of the latency between regions, so that scenarios
+// exercise latency aware matchmaking.
+package latency
+
+import (
+	"math/rand"
+	"strings"
+
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// pingArgPrefix prefixes the region in the names of the DoubleArgs holding the
+// ping of players to a region.
+const pingArgPrefix = "ping."
+
+// Matrix describes the regions players connect from, and the latency between
+// them.
+type Matrix struct {
+	// Names of the regions.
+	Regions []string
+	// Relative number of players in each region.  Players are spread evenly if
+	// nil.
+	Population []int
+	// Latencies[i][j] is the round trip time, in milliseconds, from players in
+	// Regions[i] to servers in Regions[j].
+	Latencies [][]float64
+	// Mean of the latency, in milliseconds, added by each player's own
+	// connection to all regions.
+	LastMile float64
+	// Standard deviation, in milliseconds, of the ping to each region around
+	// the expected one.
+	Jitter float64
+}
+
+// DefaultMatrix returns a matrix approximating the latency between the regions
+// of a global game.
+func DefaultMatrix() *Matrix {
+	return &Matrix{
+		Regions:    []string{"us-west", "us-east", "sa-east", "eu-west", "eu-central", "asia-east", "asia-southeast", "australia"},
+		Population: []int{15, 20, 8, 15, 15, 15, 7, 5},
+		Latencies: [][]float64{
+			{10, 65, 170, 140, 150, 120, 170, 160},
+			{65, 10, 120, 75, 90, 180, 220, 200},
+			{170, 120, 10, 185, 200, 280, 320, 310},
+			{140, 75, 185, 10, 20, 230, 180, 270},
+			{150, 90, 200, 20, 10, 220, 170, 280},
+			{120, 180, 280, 230, 220, 10, 50, 130},
+			{170, 220, 320, 180, 170, 50, 10, 95},
+			{160, 200, 310, 270, 280, 130, 95, 10},
+		},
+		LastMile: 15,
+		Jitter:   5,
+	}
+}
+
+// RandomPings returns the pings of a random player to the regions, weighted by
+// their population.  Only the regions within maxPing are returned, except for
+// the closest one, so that every player can play somewhere.
+func (m *Matrix) RandomPings(maxPing float64) map[string]float64 {
+	home := m.randomHome()
+	lastMile := rand.ExpFloat64() * m.LastMile
+
+	pings := map[string]float64{}
+	closest, closestPing := "", 0.0
+	for j, region := range m.Regions {
+		ping := m.Latencies[home][j] + lastMile + rand.NormFloat64()*m.Jitter
+		if ping < 1 {
+			ping = 1
+		}
+		if closest == "" || ping < closestPing {
+			closest, closestPing = region, ping
+		}
+		if ping <= maxPing {
+			pings[region] = ping
+		}
+	}
+	pings[closest] = closestPing
+	return pings
+}
+
+func (m *Matrix) randomHome() int {
+	if len(m.Population) == 0 {
+		return rand.Intn(len(m.Regions))
+	}
+	total := 0
+	for _, p := range m.Population {
+		total += p
+	}
+	remainder := rand.Intn(total)
+	for i, p := range m.Population {
+		remainder -= p
+		if remainder < 0 {
+			return i
+		}
+	}
+	panic("latency.Matrix population is broken.")
+}
+
+// SetPings records the pings in the ticket's SearchFields.
+func SetPings(t *pb.Ticket, pings map[string]float64) {
+	if t.SearchFields == nil {
+		t.SearchFields = &pb.SearchFields{}
+	}
+	if t.SearchFields.DoubleArgs == nil {
+		t.SearchFields.DoubleArgs = map[string]float64{}
+	}
+	for region, ping := range pings {
+		t.SearchFields.DoubleArgs[pingArgPrefix+region] = ping
+	}
+}
+
+// Ping returns the ticket's ping to the region, and whether it was recorded.
+func Ping(t *pb.Ticket, region string) (float64, bool) {
+	ping, ok := t.GetSearchFields().GetDoubleArgs()[pingArgPrefix+region]
+	return ping, ok
+}
+
+// Filter returns a filter for the tickets with a ping to the region of at most
+// maxPing.
+func Filter(region string, maxPing float64) *pb.DoubleRangeFilter {
+	return &pb.DoubleRangeFilter{
+		DoubleArg: pingArgPrefix + region,
+		Min:       0,
+		Max:       maxPing,
+	}
+}
+
+// FilteredRegion returns the region of the first ping filter of the pool, as
+// returned by Filter.
+func FilteredRegion(pool *pb.Pool) (string, bool) {
+	for _, f := range pool.GetDoubleRangeFilters() {
+		if strings.HasPrefix(f.GetDoubleArg(), pingArgPrefix) {
+			return strings.TrimPrefix(f.GetDoubleArg(), pingArgPrefix), true
+		}
+	}
+	return "", false
+}
@@ -17,8 +17,9 @@
 // It doesn't try to provide good matchmaking for real players. There are three
 // arguments used:
 // mode: The game mode the players wants to play in. mode is a hard partition.
-// regions: Players may have good latency to one or more regions. A player will
-//   search for matches in all eligible regions.
+// pings: Players have a latency to each region, simulated from a matrix of the
+//   latency between regions. A player will search for matches in all regions
+//   within the maximum ping, and matches with lower pings are preferred.
 // skill: Players have a random skill based on a normal distribution. Players
 //   will only be matched with other players who have a close skill value. The
 //   match functions have overlapping partitions of the skill brackets.
@@ -35,6 +36,7 @@ import (
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/any"
 	"github.com/golang/protobuf/ptypes/wrappers"
+	"open-match.dev/open-match/examples/scale/scenarios/latency"
 	"open-match.dev/open-match/pkg/pb"
 )
 
@@ -46,10 +48,12 @@ const (
 
 // TeamShooterScenario provides the required methods for running a scenario.
 type TeamShooterScenario struct {
-	// Names of available region tags.
-	regions []string
-	// Maximum regions a player can search in.
-	maxRegions int
+	// Latency between the regions players connect from and play in.
+	latency *latency.Matrix
+	// Maximum ping of a player to the region of its matches.
+	maxPing float64
+	// Match quality lost per millisecond of average ping.
+	pingWeight float64
 	// Number of tickets which form a match.
 	playersPerGame int
 	// For each pair of consequitive values, the value to split profiles on by
@@ -71,14 +75,10 @@ func Scenario() *TeamShooterScenario {
 		"cp": 25,  // Capture point, 1/4 as popular.
 	})
 
-	regions := []string{}
-	for i := 0; i < 2; i++ {
-		regions = append(regions, fmt.Sprintf("region_%d", i))
-	}
-
 	return &TeamShooterScenario{
-		regions:            regions,
-		maxRegions:         1,
+		latency:            latency.DefaultMatrix(),
+		maxPing:            100,
+		pingWeight:         0.0001,
 		playersPerGame:     12,
 		skillBoundaries:    []float64{math.Inf(-1), 0, math.Inf(1)},
 		maxSkillDifference: 0.01,
@@ -91,7 +91,7 @@ func Scenario() *TeamShooterScenario {
 func (t *TeamShooterScenario) Profiles() []*pb.MatchProfile {
 	p := []*pb.MatchProfile{}
 
-	for _, region := range t.regions {
+	for _, region := range t.latency.Regions {
 		for _, mode := range t.modes {
 			for i := 0; i+1 < len(t.skillBoundaries); i++ {
 				skillMin := t.skillBoundaries[i] - t.maxSkillDifference/2
@@ -107,11 +107,7 @@ func (t *TeamShooterScenario) Profiles() []*pb.MatchProfile {
 									Min:       skillMin,
 									Max:       skillMax,
 								},
-							},
-							TagPresentFilters: []*pb.TagPresentFilter{
-								{
-									Tag: region,
-								},
+								latency.Filter(region, t.maxPing),
 							},
 							StringEqualsFilters: []*pb.StringEqualsFilter{
 								{
@@ -131,17 +127,7 @@ func (t *TeamShooterScenario) Profiles() []*pb.MatchProfile {
 
 // Ticket creates a randomized player.
 func (t *TeamShooterScenario) Ticket() *pb.Ticket {
-	region := rand.Intn(len(t.regions))
-	numRegions := rand.Intn(t.maxRegions) + 1
-
-	tags := []string{}
-	for i := 0; i < numRegions; i++ {
-		tags = append(tags, t.regions[region])
-		// The Earth is actually a circle.
-		region = (region + 1) % len(t.regions)
-	}
-
-	return &pb.Ticket{
+	ticket := &pb.Ticket{
 		SearchFields: &pb.SearchFields{
 			DoubleArgs: map[string]float64{
 				skillArg: clamp(rand.NormFloat64(), -3, 3),
@@ -149,17 +135,24 @@ func (t *TeamShooterScenario) Ticket() *pb.Ticket {
 			StringArgs: map[string]string{
 				modeArg: t.randomMode(),
 			},
-			Tags: tags,
 		},
 	}
+	latency.SetPings(ticket, t.latency.RandomPings(t.maxPing))
+	return ticket
 }
 
 // MatchFunction puts tickets into matches based on their skill, finding the
 // required number of tickets for a game within the maximum skill difference.
+// Matches with a lower average ping to the profile's region have a higher
+// quality.
 func (t *TeamShooterScenario) MatchFunction(p *pb.MatchProfile, poolTickets map[string][]*pb.Ticket) ([]*pb.Match, error) {
 	skill := func(t *pb.Ticket) float64 {
 		return t.SearchFields.DoubleArgs[skillArg]
 	}
+	region, ok := latency.FilteredRegion(p.GetPools()[0])
+	if !ok {
+		return nil, fmt.Errorf("profile %s has no ping filter", p.GetName())
+	}
 
 	tickets := poolTickets[poolName]
 	var matches []*pb.Match
@@ -178,10 +171,14 @@ func (t *TeamShooterScenario) MatchFunction(p *pb.MatchProfile, poolTickets map[
 			avg /= float64(len(mt))
 
 			q := float64(0)
+			ping := float64(0)
 			for _, t := range mt {
 				diff := skill(t) - avg
 				q -= diff * diff
+				tPing, _ := latency.Ping(t, region)
+				ping += tPing
 			}
+			q -= t.pingWeight * ping / float64(len(mt))
 
 			m, err := (&matchExt{
 				id:            fmt.Sprintf("profile-%v-time-%v-%v", p.GetName(), time.Now().Format("2006-01-02T15:04:05.00"), len(matches)),
@@ -35,7 +35,7 @@ heritage: {{ .Release.Service }}
 {{- define "prometheus.annotations" -}}
 {{- if and (.prometheus.serviceDiscovery) (.prometheus.enabled) -}}
 prometheus.io/scrape: "true"
-prometheus.io/port: {{ .port | quote }}
+prometheus.io/port: {{ .prometheus.port | default .port | quote }}
 prometheus.io/path: {{ .prometheus.endpoint }}
 {{- end -}}
 {{- end -}}
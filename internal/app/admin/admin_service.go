@@ -0,0 +1,156 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package admin provides the Admin service, used by operators to inspect and
+// manage an Open Match deployment.
+package admin
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/featuregate"
+	"open-match.dev/open-match/internal/statestore"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+const (
+	defaultSampleSize = 100
+	maxSampleSize     = 10000
+)
+
+var (
+	logger = logrus.WithFields(logrus.Fields{
+		"app":       "openmatch",
+		"component": "app.admin",
+	})
+)
+
+// adminService implements the Admin service.
+type adminService struct {
+	cfg   config.View
+	store statestore.Service
+}
+
+// GetStorageUsage reports ticket counts, index cardinality, ignore list size
+// and approximate memory used in state storage.
+func (s *adminService) GetStorageUsage(ctx context.Context, req *pb.GetStorageUsageRequest) (*pb.GetStorageUsageResponse, error) {
+	sampleSize := int(req.GetSampleSize())
+	switch {
+	case sampleSize < 0:
+		return nil, status.Error(codes.InvalidArgument, ".sample_size must not be negative")
+	case sampleSize == 0:
+		sampleSize = defaultSampleSize
+	case sampleSize > maxSampleSize:
+		return nil, status.Errorf(codes.InvalidArgument, ".sample_size must not exceed %d", maxSampleSize)
+	}
+
+	usage, err := s.store.GetStorageUsage(ctx, sampleSize)
+	if err != nil {
+		logger.WithError(err).Error("failed to get storage usage")
+		return nil, err
+	}
+	return &pb.GetStorageUsageResponse{Usage: usage}, nil
+}
+
+// GetConfig returns the configuration of the Admin service, which is deployed
+// with the same settings as the other components, and the feature gates with
+// their overrides.
+func (s *adminService) GetConfig(ctx context.Context, req *pb.GetConfigRequest) (*pb.GetConfigResponse, error) {
+	overrides, err := s.store.GetFeatureGateOverrides(ctx)
+	if err != nil {
+		logger.WithError(err).Error("failed to get feature gate overrides")
+		return nil, err
+	}
+
+	cfg := s.cfg
+	if v, ok := cfg.(*featuregate.View); ok {
+		cfg = v.View
+	}
+
+	resp := &pb.GetConfigResponse{Settings: config.Settings(cfg)}
+	for _, g := range featuregate.Gates {
+		gate := &pb.FeatureGate{
+			Name:        g.Name,
+			Description: g.Description,
+			Configured:  featuregate.Configured(s.cfg, g.Name),
+		}
+		for _, o := range overrides {
+			if strings.EqualFold(o.GetGate(), g.Name) {
+				gate.Overrides = append(gate.Overrides, o)
+			}
+		}
+		resp.FeatureGates = append(resp.FeatureGates, gate)
+	}
+	return resp, nil
+}
+
+// SetFeatureGate overrides a feature gate for one instance, or for all
+// instances if none is given.  Components apply it on their next refresh.
+func (s *adminService) SetFeatureGate(ctx context.Context, req *pb.SetFeatureGateRequest) (*pb.SetFeatureGateResponse, error) {
+	o := req.GetOverride()
+	if o == nil {
+		return nil, status.Error(codes.InvalidArgument, ".override is required")
+	}
+	gate, err := validateGate(o.GetGate(), o.GetInstance())
+	if err != nil {
+		return nil, err
+	}
+
+	override := &pb.FeatureGateOverride{Gate: gate.Name, Instance: o.GetInstance(), Enabled: o.GetEnabled()}
+	if err := s.store.SetFeatureGateOverride(ctx, override); err != nil {
+		logger.WithError(err).Error("failed to set feature gate override")
+		return nil, err
+	}
+	logger.WithFields(logrus.Fields{
+		"gate":     override.Gate,
+		"instance": override.Instance,
+		"enabled":  override.Enabled,
+	}).Info("Feature gate overridden.")
+	return &pb.SetFeatureGateResponse{}, nil
+}
+
+// ClearFeatureGate removes the override of a feature gate for one instance, or
+// for all instances if none is given, restoring the configured value.
+func (s *adminService) ClearFeatureGate(ctx context.Context, req *pb.ClearFeatureGateRequest) (*pb.ClearFeatureGateResponse, error) {
+	gate, err := validateGate(req.GetGate(), req.GetInstance())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.store.DeleteFeatureGateOverride(ctx, gate.Name, req.GetInstance()); err != nil {
+		logger.WithError(err).Error("failed to clear feature gate override")
+		return nil, err
+	}
+	logger.WithFields(logrus.Fields{
+		"gate":     gate.Name,
+		"instance": req.GetInstance(),
+	}).Info("Feature gate override cleared.")
+	return &pb.ClearFeatureGateResponse{}, nil
+}
+
+func validateGate(name, instance string) (featuregate.Gate, error) {
+	gate, ok := featuregate.Lookup(name)
+	if !ok {
+		return featuregate.Gate{}, status.Errorf(codes.InvalidArgument, "%s is not a feature gate which can be overridden at runtime", name)
+	}
+	if strings.Contains(instance, "@") {
+		return featuregate.Gate{}, status.Error(codes.InvalidArgument, ".instance must not contain '@'")
+	}
+	return gate, nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestSetFeatureGateValidation(t *testing.T) {
+	testCases := []struct {
+		name     string
+		override *pb.FeatureGateOverride
+	}{
+		{name: "missing override"},
+		{name: "unknown gate", override: &pb.FeatureGateOverride{Gate: "redis.hostname"}},
+		{name: "invalid instance", override: &pb.FeatureGateOverride{Gate: "synchronizer.verifyTicketsBeforeEvaluation", Instance: "a@b"}},
+	}
+
+	s := &adminService{}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := s.SetFeatureGate(context.Background(), &pb.SetFeatureGateRequest{Override: tc.override})
+			assert.Equal(t, codes.InvalidArgument, status.Convert(err).Code())
+		})
+	}
+}
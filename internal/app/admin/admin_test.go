@@ -0,0 +1,71 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/rpc"
+	rpcTesting "open-match.dev/open-match/internal/rpc/testing"
+	statestoreTesting "open-match.dev/open-match/internal/statestore/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// staticAuthenticator accepts a single token.
+type staticAuthenticator struct {
+	token string
+}
+
+func (a *staticAuthenticator) Authenticate(_ context.Context, token string) (*rpc.Identity, error) {
+	if token != a.token {
+		return nil, fmt.Errorf("unknown token")
+	}
+	return &rpc.Identity{Subject: "operator"}, nil
+}
+
+func TestBindServiceRequiresAuthentication(t *testing.T) {
+	assert := assert.New(t)
+	rpc.RegisterAuthenticator("admin-test", func(config.View) (rpc.Authenticator, error) {
+		return &staticAuthenticator{token: "secret"}, nil
+	})
+
+	cfg := viper.New()
+	closer := statestoreTesting.New(t, cfg)
+	defer closer()
+	cfg.Set("api.auth.type", "admin-test")
+
+	tc := rpcTesting.MustServe(t, func(p *rpc.ServerParams) {
+		assert.Nil(BindService(p, cfg))
+	})
+	defer tc.Close()
+
+	conn := tc.MustGRPC()
+	defer conn.Close()
+	client := pb.NewAdminServiceClient(conn)
+
+	_, err := client.GetConfig(tc.Context(), &pb.GetConfigRequest{})
+	assert.Equal(codes.Unauthenticated, status.Code(err))
+
+	_, err = client.GetConfig(metadata.AppendToOutgoingContext(tc.Context(), "authorization", "Bearer secret"), &pb.GetConfigRequest{})
+	assert.Nil(err)
+}
@@ -0,0 +1,127 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// MigrateSearchFields rewrites the SearchFields of all stored Tickets.
+func (s *adminService) MigrateSearchFields(ctx context.Context, req *pb.MigrateSearchFieldsRequest) (*pb.MigrateSearchFieldsResponse, error) {
+	if err := validateRenames(req.GetRenames()); err != nil {
+		return nil, err
+	}
+
+	var migrated int64
+	scanned, rewritten, err := s.store.RewriteTickets(ctx, func(t *pb.Ticket) bool {
+		if !renameSearchFields(t, req.GetRenames()) {
+			return false
+		}
+		migrated++
+		return !req.GetDryRun()
+	})
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"scanned":   scanned,
+			"rewritten": rewritten,
+		}).WithError(err).Error("failed to migrate search fields")
+		return nil, err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"renames":  req.GetRenames(),
+		"dryRun":   req.GetDryRun(),
+		"scanned":  scanned,
+		"migrated": migrated,
+	}).Info("Migrated search fields.")
+
+	return &pb.MigrateSearchFieldsResponse{
+		ScannedTicketCount:  scanned,
+		MigratedTicketCount: migrated,
+	}, nil
+}
+
+func validateRenames(renames []*pb.SearchFieldRename) error {
+	if len(renames) == 0 {
+		return status.Error(codes.InvalidArgument, ".renames is required")
+	}
+	for i, r := range renames {
+		if r.GetFrom() == "" {
+			return status.Errorf(codes.InvalidArgument, ".renames[%d].from is required", i)
+		}
+		if r.GetFrom() == r.GetTo() {
+			return status.Errorf(codes.InvalidArgument, ".renames[%d] renames %q to itself", i, r.GetFrom())
+		}
+	}
+	return nil
+}
+
+// renameSearchFields applies renames to the ticket's double args, string args
+// and tags in order, and returns whether the ticket changed.
+func renameSearchFields(t *pb.Ticket, renames []*pb.SearchFieldRename) bool {
+	sf := t.GetSearchFields()
+	if sf == nil {
+		return false
+	}
+
+	changed := false
+	for _, r := range renames {
+		from, to := r.GetFrom(), r.GetTo()
+
+		if v, ok := sf.DoubleArgs[from]; ok {
+			delete(sf.DoubleArgs, from)
+			if _, exists := sf.DoubleArgs[to]; to != "" && !exists {
+				sf.DoubleArgs[to] = v
+			}
+			changed = true
+		}
+
+		if v, ok := sf.StringArgs[from]; ok {
+			delete(sf.StringArgs, from)
+			if _, exists := sf.StringArgs[to]; to != "" && !exists {
+				sf.StringArgs[to] = v
+			}
+			changed = true
+		}
+
+		tags := sf.Tags[:0]
+		hasTo := false
+		renamed := false
+		for _, tag := range sf.Tags {
+			switch tag {
+			case from:
+				renamed = true
+				continue
+			case to:
+				hasTo = true
+			}
+			tags = append(tags, tag)
+		}
+		if renamed {
+			if to != "" && !hasTo {
+				tags = append(tags, to)
+			}
+			changed = true
+		}
+		sf.Tags = tags
+	}
+
+	return changed
+}
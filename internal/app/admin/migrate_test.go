@@ -0,0 +1,110 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestRenameSearchFields(t *testing.T) {
+	testCases := []struct {
+		name    string
+		renames []*pb.SearchFieldRename
+		before  *pb.SearchFields
+		after   *pb.SearchFields
+		changed bool
+	}{
+		{
+			name:    "renames all kinds of fields",
+			renames: []*pb.SearchFieldRename{{From: "mmr", To: "skill"}},
+			before: &pb.SearchFields{
+				DoubleArgs: map[string]float64{"mmr": 1200, "level": 3},
+				StringArgs: map[string]string{"mmr": "gold"},
+				Tags:       []string{"beta", "mmr"},
+			},
+			after: &pb.SearchFields{
+				DoubleArgs: map[string]float64{"skill": 1200, "level": 3},
+				StringArgs: map[string]string{"skill": "gold"},
+				Tags:       []string{"beta", "skill"},
+			},
+			changed: true,
+		},
+		{
+			name:    "keeps existing values",
+			renames: []*pb.SearchFieldRename{{From: "mmr", To: "skill"}},
+			before: &pb.SearchFields{
+				DoubleArgs: map[string]float64{"mmr": 1200, "skill": 1500},
+				Tags:       []string{"skill", "mmr"},
+			},
+			after: &pb.SearchFields{
+				DoubleArgs: map[string]float64{"skill": 1500},
+				Tags:       []string{"skill"},
+			},
+			changed: true,
+		},
+		{
+			name:    "removes fields",
+			renames: []*pb.SearchFieldRename{{From: "mmr"}},
+			before: &pb.SearchFields{
+				DoubleArgs: map[string]float64{"mmr": 1200},
+				Tags:       []string{"mmr"},
+			},
+			after: &pb.SearchFields{
+				DoubleArgs: map[string]float64{},
+				Tags:       []string{},
+			},
+			changed: true,
+		},
+		{
+			name:    "applies renames in order",
+			renames: []*pb.SearchFieldRename{{From: "a", To: "b"}, {From: "b", To: "c"}},
+			before:  &pb.SearchFields{StringArgs: map[string]string{"a": "x"}},
+			after:   &pb.SearchFields{StringArgs: map[string]string{"c": "x"}},
+			changed: true,
+		},
+		{
+			name:    "unchanged",
+			renames: []*pb.SearchFieldRename{{From: "mmr", To: "skill"}},
+			before:  &pb.SearchFields{DoubleArgs: map[string]float64{"level": 3}},
+			after:   &pb.SearchFields{DoubleArgs: map[string]float64{"level": 3}},
+			changed: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ticket := &pb.Ticket{SearchFields: tc.before}
+			assert.Equal(t, tc.changed, renameSearchFields(ticket, tc.renames))
+			assert.Equal(t, tc.after, ticket.SearchFields)
+		})
+	}
+
+	assert.False(t, renameSearchFields(&pb.Ticket{}, []*pb.SearchFieldRename{{From: "mmr"}}))
+}
+
+func TestValidateRenames(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(codes.InvalidArgument, status.Convert(validateRenames(nil)).Code())
+	assert.Equal(codes.InvalidArgument, status.Convert(validateRenames([]*pb.SearchFieldRename{{To: "skill"}})).Code())
+	assert.Equal(codes.InvalidArgument, status.Convert(validateRenames([]*pb.SearchFieldRename{{From: "mmr", To: "mmr"}})).Code())
+	assert.Nil(validateRenames([]*pb.SearchFieldRename{{From: "mmr", To: "skill"}, {From: "old"}}))
+}
@@ -0,0 +1,58 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// ExportTickets streams back a snapshot of every Ticket in state storage.
+func (s *adminService) ExportTickets(req *pb.ExportTicketsRequest, stream pb.AdminService_ExportTicketsServer) error {
+	var exported int
+	err := s.store.ExportTickets(stream.Context(), func(snapshots []*pb.TicketSnapshot) error {
+		exported += len(snapshots)
+		return stream.Send(&pb.ExportTicketsResponse{Snapshots: snapshots})
+	})
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"exported": exported,
+		}).WithError(err).Error("failed to export tickets")
+		return err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"exported": exported,
+	}).Info("Exported tickets.")
+	return nil
+}
+
+// ImportTickets saves Tickets exported by ExportTickets.
+func (s *adminService) ImportTickets(ctx context.Context, req *pb.ImportTicketsRequest) (*pb.ImportTicketsResponse, error) {
+	err := s.store.ImportTickets(ctx, req.GetSnapshots())
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"count": len(req.GetSnapshots()),
+		}).WithError(err).Error("failed to import tickets")
+		return nil, err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"imported": len(req.GetSnapshots()),
+	}).Info("Imported tickets.")
+	return &pb.ImportTicketsResponse{ImportedTicketCount: int64(len(req.GetSnapshots()))}, nil
+}
@@ -16,8 +16,11 @@
 package app
 
 import (
+	"os"
+
 	"github.com/sirupsen/logrus"
 	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/featuregate"
 	"open-match.dev/open-match/internal/logging"
 	"open-match.dev/open-match/internal/rpc"
 )
@@ -45,11 +48,23 @@ func RunApplication(serverName string, getCfg func() (config.View, error), bindS
 		}).Fatalf("cannot construct server.")
 	}
 
-	if err := bindService(p, cfg); err != nil {
+	// Services read feature gates through the statestore overrides, the rest of
+	// the harness keeps the deployed configuration.
+	if err := bindService(p, featuregate.Watch(cfg, instanceName(serverName))); err != nil {
 		logger.WithFields(logrus.Fields{
 			"error": err.Error(),
 		}).Fatalf("failed to bind %s service.", serverName)
 	}
 
+	startSkewCheck(serverName, cfg)
 	rpc.MustServeForever(p)
 }
+
+// instanceName identifies this process among the replicas of all components.
+func instanceName(serverName string) string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return hostname + "/" + serverName
+}
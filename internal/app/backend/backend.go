@@ -15,8 +15,11 @@
 package backend
 
 import (
+	"time"
+
 	"google.golang.org/grpc"
 	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/mmfauth"
 	"open-match.dev/open-match/internal/rpc"
 	"open-match.dev/open-match/internal/statestore"
 	"open-match.dev/open-match/pkg/pb"
@@ -24,10 +27,32 @@ import (
 
 // BindService creates the backend service and binds it to the serving harness.
 func BindService(p *rpc.ServerParams, cfg config.View) error {
+	mmfAuth, err := mmfauth.FromConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	store := statestore.New(cfg)
 	service := &backendService{
 		synchronizer: newSynchronizerClient(cfg),
-		store:        statestore.New(cfg),
-		cc:           rpc.NewClientCache(cfg),
+		store:        store,
+		cc:           rpc.NewClientCache(cfg, getMmfConnectionMaxAge(cfg)),
+		pools:        newPoolQuerier(cfg),
+		mmfAuth:      mmfAuth,
+
+		maxAssignmentFailures: getMaxAssignmentFailures(cfg),
+		cfg:                   cfg,
+		maxInFlightMatches:    getMaxInFlightMatches(cfg),
+		validator:             newProposalValidator(cfg, store),
+		mmfTimeout:            cfg.GetDuration("backend.mmfTimeout"),
+	}
+
+	authenticator, err := rpc.AuthenticatorFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+	if authenticator != nil {
+		p.RequireAuthentication(authenticator, "openmatch.BackendService")
 	}
 
 	p.AddHealthCheckFunc(service.store.HealthCheck)
@@ -37,3 +62,48 @@ func BindService(p *rpc.ServerParams, cfg config.View) error {
 
 	return nil
 }
+
+func getMaxAssignmentFailures(cfg config.View) int {
+	const (
+		name = "backend.maxAssignmentFailures"
+		// Default number of times a ticket can be requeued after its assignment
+		// failed.
+		defaultMaxAssignmentFailures = 3
+	)
+
+	if !cfg.IsSet(name) {
+		return defaultMaxAssignmentFailures
+	}
+	return cfg.GetInt(name)
+}
+
+func getMaxInFlightMatches(cfg config.View) int {
+	const (
+		name = "backend.maxInFlightMatches"
+		// Default number of evaluated matches a FetchMatches call holds for a
+		// slow caller.
+		defaultMaxInFlightMatches = 100
+	)
+
+	if !cfg.IsSet(name) {
+		return defaultMaxInFlightMatches
+	}
+	if n := cfg.GetInt(name); n > 0 {
+		return n
+	}
+	return 0
+}
+
+func getMmfConnectionMaxAge(cfg config.View) time.Duration {
+	const (
+		name = "backend.mmfConnectionMaxAge"
+		// Default age after which connections to match functions are redialed,
+		// so that new match function replicas get traffic.
+		defaultMmfConnectionMaxAge = 10 * time.Minute
+	)
+
+	if !cfg.IsSet(name) {
+		return defaultMmfConnectionMaxAge
+	}
+	return cfg.GetDuration(name)
+}
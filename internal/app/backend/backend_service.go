@@ -19,29 +19,65 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/sirupsen/logrus"
-	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/featuregate"
+	"open-match.dev/open-match/internal/filter"
 	"open-match.dev/open-match/internal/ipb"
+	"open-match.dev/open-match/internal/mmfauth"
 	"open-match.dev/open-match/internal/omerror"
 	"open-match.dev/open-match/internal/rpc"
 	"open-match.dev/open-match/internal/statestore"
 	"open-match.dev/open-match/internal/telemetry"
+	"open-match.dev/open-match/internal/util"
 	"open-match.dev/open-match/pkg/pb"
 )
 
+const (
+	// cycleIDExtensionKey is the Match extension holding the id of the
+	// synchronizer cycle which produced the match.
+	cycleIDExtensionKey = "openmatch.synchronizer_cycle_id"
+	// matchValidExtensionKey is the Match extension set to false when tickets
+	// of the match were deleted before it was returned.
+	matchValidExtensionKey = "openmatch.match_valid"
+	// maxMmfErrorBodyBytes is how much of the body of a failed HTTP match
+	// function response is included in the error.
+	maxMmfErrorBodyBytes = 1024
+)
+
 // The service implementing the Backend API that is called to generate matches
 // and make assignments for Tickets.
 type backendService struct {
+	cfg          config.View
 	synchronizer *synchronizerClient
 	store        statestore.Service
 	cc           *rpc.ClientCache
+	pools        *poolQuerier
+	// mmfAuth issues tokens to match functions, nil if mmf authentication is disabled.
+	mmfAuth *mmfauth.Authority
+	// maxAssignmentFailures is how many times RequeueTickets returns a ticket
+	// to the pool, unlimited if not positive.
+	maxAssignmentFailures int
+	// maxInFlightMatches is how many evaluated matches a FetchMatches call
+	// holds before the caller receives them.
+	maxInFlightMatches int
+	// validator drops invalid proposals before they are evaluated.
+	validator *proposalValidator
+	// mmfTimeout caps how long match functions run, unlimited if 0.
+	mmfTimeout time.Duration
 }
 
 var (
@@ -51,20 +87,40 @@ var (
 	})
 	mMatchesFetched          = telemetry.Counter("backend/matches_fetched", "matches fetched")
 	mMatchesSentToEvaluation = telemetry.Counter("backend/matches_sent_to_evaluation", "matches sent to evaluation")
+	mProposalsDroppedLeased  = telemetry.Counter("backend/proposals_dropped_leased", "proposals dropped because their tickets were leased by another synchronizer cycle")
+	mInvalidMatchesFetched   = telemetry.Counter("backend/invalid_matches_fetched", "matches fetched with tickets deleted after they were proposed")
 	mTicketsAssigned         = telemetry.Counter("backend/tickets_assigned", "tickets assigned")
+	mTicketsNotFound         = telemetry.Counter("backend/tickets_not_found", "tickets skipped by AssignTickets because they no longer exist")
 	mTicketsReleased         = telemetry.Counter("backend/tickets_released", "tickets released")
+	mReleaseAllTickets       = telemetry.Counter("backend/release_all_tickets", "ReleaseAllTickets calls clearing the ignore list")
+	mMmfTimeouts             = telemetry.Counter("backend/mmf_timeouts", "match function runs canceled for exceeding their timeout")
+	mTicketsRequeued         = telemetry.Counter("backend/tickets_requeued", "tickets returned to the pool after their assignment failed")
+	mTicketsExhausted        = telemetry.Counter("backend/tickets_exhausted", "tickets left assigned by RequeueTickets because their assignments failed too often")
+	mTicketsReserved         = telemetry.Counter("backend/tickets_reserved", "tickets reserved for a scheduled event")
 )
 
 // FetchMatches triggers a MatchFunction with the specified MatchProfiles, while each MatchProfile
 // returns a set of match proposals. FetchMatches method streams the results back to the caller.
 // FetchMatches immediately returns an error if it encounters any execution failures.
+//   - Matches are sent as they are evaluated, with at most backend.maxInFlightMatches held for a slow caller.
 //   - If the synchronizer is enabled, FetchMatch will then call the synchronizer to deduplicate proposals with overlapped tickets.
 func (s *backendService) FetchMatches(req *pb.FetchMatchesRequest, stream pb.BackendService_FetchMatchesServer) error {
 	if req.GetConfig() == nil {
 		return status.Error(codes.InvalidArgument, ".config is required")
 	}
-	if req.GetProfile() == nil {
-		return status.Error(codes.InvalidArgument, ".profile is required")
+	profiles, err := fetchProfiles(req)
+	if err != nil {
+		return err
+	}
+	timeout, err := s.fetchMmfTimeout(req)
+	if err != nil {
+		return err
+	}
+	for _, profile := range profiles {
+		// The profile registry is informational, don't fail matchmaking over it.
+		if err := s.store.RecordProfile(stream.Context(), profile); err != nil {
+			logger.WithError(err).WithField("profile", profile.GetName()).Warning("failed to record profile")
+		}
 	}
 
 	syncStream, err := s.synchronizer.synchronize(stream.Context())
@@ -73,15 +129,43 @@ func (s *backendService) FetchMatches(req *pb.FetchMatchesRequest, stream pb.Bac
 	}
 
 	mmfCtx, cancelMmfs := context.WithCancel(stream.Context())
+	// runCtx bounds the match function runs by their timeout, telling it apart
+	// from the synchronizer canceling them.
+	runCtx := mmfCtx
+	if timeout > 0 {
+		var cancelRun context.CancelFunc
+		runCtx, cancelRun = context.WithTimeout(mmfCtx, timeout)
+		defer cancelRun()
+	}
+	mmfTimedOut := func() bool {
+		return runCtx.Err() == context.DeadlineExceeded && stream.Context().Err() == nil
+	}
 	// Closed when mmfs should start.
 	startMmfs := make(chan struct{})
+	// Set before startMmfs is closed, only read after.
+	var cycleID string
 	proposals := make(chan *pb.Match)
 	m := &sync.Map{}
 
+	// Evaluated matches are streamed to the caller as the synchronizer returns
+	// them.  A slow caller pushes back on the synchronizer once
+	// maxInFlightMatches are waiting to be sent.
+	results := make(chan *pb.Match, s.maxInFlightMatches)
+
 	synchronizerWait := omerror.WaitOnErrors(logger, func() error {
-		return synchronizeSend(stream.Context(), syncStream, m, proposals)
+		return synchronizeSend(stream.Context(), s.store, s.validator, syncStream, m, &cycleID, proposals)
+	}, func() error {
+		defer close(results)
+		return synchronizeRecv(stream.Context(), syncStream, m, results, &cycleID, startMmfs, cancelMmfs)
 	}, func() error {
-		return synchronizeRecv(syncStream, m, stream, startMmfs, cancelMmfs)
+		return sendMatches(stream, results, func(match *pb.Match) bool {
+			// Without partial results, matches of a timed out run are dropped.
+			if req.GetIncludePartialResults() || !mmfTimedOut() {
+				return false
+			}
+			releaseMatchTickets(stream.Context(), s.store, cycleID, match)
+			return true
+		})
 	})
 
 	mmfWait := omerror.WaitOnErrors(logger, func() error {
@@ -91,7 +175,11 @@ func (s *backendService) FetchMatches(req *pb.FetchMatchesRequest, stream pb.Bac
 		case <-startMmfs:
 		}
 
-		return callMmf(mmfCtx, s.cc, req, proposals)
+		ctx, err := util.AppendSynchronizerContextID(runCtx, cycleID)
+		if err != nil {
+			return err
+		}
+		return callMmfs(ctx, s.cc, s.pools, s.mmfAuth, req.GetConfig(), profiles, proposals)
 	})
 
 	syncErr := synchronizerWait()
@@ -101,11 +189,23 @@ func (s *backendService) FetchMatches(req *pb.FetchMatchesRequest, stream pb.Bac
 	cancelMmfs()
 	mmfErr := mmfWait()
 
+	if mmfTimedOut() {
+		telemetry.RecordUnitMeasurement(stream.Context(), mMmfTimeouts)
+		logger.WithFields(logrus.Fields{
+			"cycleId": cycleID,
+			"timeout": timeout,
+		}).Warning("match function exceeded its timeout")
+		if syncErr == nil {
+			return status.Errorf(codes.DeadlineExceeded, "match function exceeded its %v timeout", timeout)
+		}
+	}
+
 	// TODO: Send mmf error in FetchSummary instead of erroring call.
 	if syncErr != nil || mmfErr != nil {
 		logger.WithFields(logrus.Fields{
 			"syncErr": syncErr,
 			"mmfErr":  mmfErr,
+			"cycleId": cycleID,
 		}).Error("error(s) in FetchMatches call.")
 
 		return fmt.Errorf(
@@ -118,7 +218,7 @@ func (s *backendService) FetchMatches(req *pb.FetchMatchesRequest, stream pb.Bac
 	return nil
 }
 
-func synchronizeSend(ctx context.Context, syncStream synchronizerStream, m *sync.Map, proposals <-chan *pb.Match) error {
+func synchronizeSend(ctx context.Context, store statestore.Service, validator *proposalValidator, syncStream synchronizerStream, m *sync.Map, cycleID *string, proposals <-chan *pb.Match) error {
 sendProposals:
 	for {
 		select {
@@ -128,6 +228,15 @@ sendProposals:
 			if !ok {
 				break sendProposals
 			}
+			if !validator.admit(ctx, p) {
+				continue
+			}
+			if !leaseProposalTickets(ctx, store, *cycleID, p) {
+				continue
+			}
+			if err := setCycleIDExtension(p, *cycleID); err != nil {
+				return err
+			}
 			m.Store(p.GetMatchId(), p)
 			telemetry.RecordUnitMeasurement(ctx, mMatchesSentToEvaluation)
 			err := syncStream.Send(&ipb.SynchronizeRequest{Proposal: p})
@@ -144,7 +253,39 @@ sendProposals:
 	return nil
 }
 
-func synchronizeRecv(syncStream synchronizerStream, m *sync.Map, stream pb.BackendService_FetchMatchesServer, startMmfs chan<- struct{}, cancelMmfs context.CancelFunc) error {
+// leaseProposalTickets leases the tickets of the proposal to the synchronizer
+// cycle, so that they aren't proposed by other cycles while it's evaluated.
+// The synchronizer releases them if the proposal is rejected.  Returns false
+// if another cycle already leased some of the tickets, in which case the
+// proposal is dropped.  If leasing fails, the proposal is sent anyway and its
+// tickets are leased once it's accepted.
+func leaseProposalTickets(ctx context.Context, store statestore.Service, cycleID string, p *pb.Match) bool {
+	if cycleID == "" {
+		return true
+	}
+
+	ids := make([]string, 0, len(p.GetTickets()))
+	for _, t := range p.GetTickets() {
+		ids = append(ids, t.GetId())
+	}
+	held, err := store.AcquireTicketLease(ctx, cycleID, ids)
+	if err != nil {
+		logger.WithError(err).WithField("cycleId", cycleID).Warning("failed to lease proposal tickets, sending the proposal unleased")
+		return true
+	}
+	if len(held) > 0 {
+		logger.WithFields(logrus.Fields{
+			"cycleId": cycleID,
+			"matchId": p.GetMatchId(),
+			"tickets": held,
+		}).Debug("dropping proposal with tickets leased by another cycle")
+		telemetry.RecordUnitMeasurement(ctx, mProposalsDroppedLeased)
+		return false
+	}
+	return true
+}
+
+func synchronizeRecv(ctx context.Context, syncStream synchronizerStream, m *sync.Map, results chan<- *pb.Match, cycleID *string, startMmfs chan<- struct{}, cancelMmfs context.CancelFunc) error {
 	var startMmfsOnce sync.Once
 
 	for {
@@ -157,7 +298,9 @@ func synchronizeRecv(syncStream synchronizerStream, m *sync.Map, stream pb.Backe
 		}
 
 		if resp.StartMmfs {
-			go startMmfsOnce.Do(func() {
+			startMmfsOnce.Do(func() {
+				*cycleID = resp.GetCycleId()
+				logger.WithField("cycleId", *cycleID).Debug("registered with synchronizer cycle, starting mmfs")
 				close(startMmfs)
 			})
 		}
@@ -167,33 +310,195 @@ func synchronizeRecv(syncStream synchronizerStream, m *sync.Map, stream pb.Backe
 		}
 
 		if match, ok := m.Load(resp.GetMatchId()); ok {
-			telemetry.RecordUnitMeasurement(stream.Context(), mMatchesFetched)
-			err = stream.Send(&pb.FetchMatchesResponse{Match: match.(*pb.Match)})
-			if err != nil {
-				return fmt.Errorf("error sending match to caller of backend: %w", err)
+			// The proposal isn't needed once it's returned, don't hold on to
+			// it for the rest of the call.
+			m.Delete(resp.GetMatchId())
+			if resp.GetMatchInvalid() {
+				if err = setMatchInvalidExtension(match.(*pb.Match)); err != nil {
+					return err
+				}
+				telemetry.RecordUnitMeasurement(ctx, mInvalidMatchesFetched)
+			}
+			select {
+			case results <- match.(*pb.Match):
+			case <-ctx.Done():
+				return ctx.Err()
 			}
 		}
 	}
 }
 
-// callMmf triggers execution of MMFs to fetch match proposals.
-func callMmf(ctx context.Context, cc *rpc.ClientCache, req *pb.FetchMatchesRequest, proposals chan<- *pb.Match) error {
+// sendMatches sends the evaluated matches to the caller of FetchMatches,
+// except those withheld.  It keeps draining results after a failed send, so
+// that synchronizeRecv doesn't block on a caller which went away.
+func sendMatches(stream pb.BackendService_FetchMatchesServer, results <-chan *pb.Match, withhold func(*pb.Match) bool) error {
+	var err error
+	for match := range results {
+		if err != nil || withhold(match) {
+			continue
+		}
+		telemetry.RecordUnitMeasurement(stream.Context(), mMatchesFetched)
+		if err = stream.Send(&pb.FetchMatchesResponse{Match: match}); err != nil {
+			err = fmt.Errorf("error sending match to caller of backend: %w", err)
+		}
+	}
+	return err
+}
+
+// setCycleIDExtension records the synchronizer cycle id on the match, so that
+// evaluators and FetchMatches callers can correlate it with the cycle's logs.
+func setCycleIDExtension(match *pb.Match, cycleID string) error {
+	if cycleID == "" {
+		return nil
+	}
+
+	a, err := ptypes.MarshalAny(&wrappers.StringValue{Value: cycleID})
+	if err != nil {
+		return fmt.Errorf("error marshaling cycle id extension: %w", err)
+	}
+	if match.Extensions == nil {
+		match.Extensions = make(map[string]*any.Any)
+	}
+	match.Extensions[cycleIDExtensionKey] = a
+	return nil
+}
+
+// setMatchInvalidExtension marks the match as invalid, so that the director
+// doesn't assign the remaining tickets of a match with deleted tickets.
+func setMatchInvalidExtension(match *pb.Match) error {
+	a, err := ptypes.MarshalAny(&wrappers.BoolValue{Value: false})
+	if err != nil {
+		return fmt.Errorf("error marshaling match valid extension: %w", err)
+	}
+	if match.Extensions == nil {
+		match.Extensions = make(map[string]*any.Any)
+	}
+	match.Extensions[matchValidExtensionKey] = a
+	return nil
+}
+
+// mmfAddress returns the address of the match function, which is the host
+// alone for gRPC match functions served on a unix socket, e.g. by a sidecar.
+func mmfAddress(fc *pb.FunctionConfig) string {
+	if strings.HasPrefix(fc.GetHost(), "unix:") {
+		return fc.GetHost()
+	}
+	return fmt.Sprintf("%s:%d", fc.GetHost(), fc.GetPort())
+}
+
+// fetchMmfTimeout returns how long the match functions of the request may run,
+// capped by backend.mmfTimeout.
+func (s *backendService) fetchMmfTimeout(req *pb.FetchMatchesRequest) (time.Duration, error) {
+	if req.GetMmfTimeout() == nil {
+		return s.mmfTimeout, nil
+	}
+	timeout, err := ptypes.Duration(req.GetMmfTimeout())
+	if err != nil || timeout <= 0 {
+		return 0, status.Error(codes.InvalidArgument, ".mmf_timeout must be a positive duration")
+	}
+	if s.mmfTimeout > 0 && timeout > s.mmfTimeout {
+		timeout = s.mmfTimeout
+	}
+	return timeout, nil
+}
+
+// releaseMatchTickets releases the leases the synchronizer cycle holds on the
+// tickets of a match which isn't returned, so that they can be matched again.
+func releaseMatchTickets(ctx context.Context, store statestore.Service, cycleID string, match *pb.Match) {
+	if cycleID == "" {
+		return
+	}
+	ids := make([]string, 0, len(match.GetTickets()))
+	for _, t := range match.GetTickets() {
+		ids = append(ids, t.GetId())
+	}
+	if err := store.ReleaseTicketLease(ctx, cycleID, ids); err != nil {
+		logger.WithError(err).WithField("matchId", match.GetMatchId()).Warning("failed to release the tickets of a dropped match")
+	}
+}
+
+// fetchProfiles returns the profiles of the request, validating them.
+func fetchProfiles(req *pb.FetchMatchesRequest) ([]*pb.MatchProfile, error) {
+	var profiles []*pb.MatchProfile
+	if req.GetProfile() != nil {
+		profiles = append(profiles, req.GetProfile())
+	}
+	profiles = append(profiles, req.GetProfiles()...)
+	if len(profiles) == 0 {
+		return nil, status.Error(codes.InvalidArgument, ".profile is required")
+	}
+
+	names := make(map[string]struct{}, len(profiles))
+	for _, profile := range profiles {
+		if profile == nil {
+			return nil, status.Error(codes.InvalidArgument, ".profiles must not contain empty profiles")
+		}
+		if _, ok := names[profile.GetName()]; ok {
+			return nil, status.Errorf(codes.InvalidArgument, "profile name %q is used more than once", profile.GetName())
+		}
+		names[profile.GetName()] = struct{}{}
+		if err := validateProfileBudget(profile); err != nil {
+			return nil, err
+		}
+		for _, pool := range profile.GetPools() {
+			if err := filter.ValidatePool(pool); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "pool %q of profile %q: %v", pool.GetName(), profile.GetName(), err)
+			}
+		}
+	}
+	return profiles, nil
+}
+
+// callMmfs calls the match function for each profile concurrently, closing
+// proposals once all of them are done.
+func callMmfs(ctx context.Context, cc *rpc.ClientCache, pools *poolQuerier, auth *mmfauth.Authority, fc *pb.FunctionConfig, profiles []*pb.MatchProfile, proposals chan<- *pb.Match) error {
 	defer close(proposals)
-	address := fmt.Sprintf("%s:%d", req.GetConfig().GetHost(), req.GetConfig().GetPort())
+
+	calls := make([]func() error, 0, len(profiles))
+	for _, profile := range profiles {
+		profile := profile
+		calls = append(calls, func() error {
+			ctx := ctx
+			if auth != nil {
+				token, err := auth.Issue(profile.GetName(), mmfAddress(fc))
+				if err != nil {
+					return err
+				}
+				ctx = mmfauth.AppendToOutgoingContext(ctx, token)
+			}
+			return callMmf(ctx, cc, pools, &pb.FetchMatchesRequest{Config: fc, Profile: profile}, proposals)
+		})
+	}
+	return omerror.WaitOnErrors(logger, calls...)()
+}
+
+// callMmf triggers execution of MMFs to fetch match proposals.
+func callMmf(ctx context.Context, cc *rpc.ClientCache, pools *poolQuerier, req *pb.FetchMatchesRequest, proposals chan<- *pb.Match) error {
+	address := mmfAddress(req.GetConfig())
+	budget := newProfileBudget(req.GetProfile())
+
+	runReq := &pb.RunRequest{Profile: req.GetProfile()}
+	if req.GetConfig().GetPassPoolTickets() {
+		var err error
+		runReq.PoolTickets, err = pools.query(ctx, req.GetProfile())
+		if err != nil {
+			logger.WithError(err).Error("failed to query pools for match function")
+			return err
+		}
+	}
 
 	switch req.GetConfig().GetType() {
 	case pb.FunctionConfig_GRPC:
-		return callGrpcMmf(ctx, cc, req.GetProfile(), address, proposals)
+		return callGrpcMmf(ctx, cc, runReq, address, budget, proposals)
 	case pb.FunctionConfig_REST:
-		return callHTTPMmf(ctx, cc, req.GetProfile(), address, proposals)
+		return callHTTPMmf(ctx, cc, runReq, address, budget, proposals)
 	default:
 		return status.Error(codes.InvalidArgument, "provided match function type is not supported")
 	}
 }
 
-func callGrpcMmf(ctx context.Context, cc *rpc.ClientCache, profile *pb.MatchProfile, address string, proposals chan<- *pb.Match) error {
-	var conn *grpc.ClientConn
-	conn, err := cc.GetGRPC(address)
+func callGrpcMmf(ctx context.Context, cc *rpc.ClientCache, runReq *pb.RunRequest, address string, budget *profileBudget, proposals chan<- *pb.Match) error {
+	conn, release, err := cc.GetGRPC(address)
 	if err != nil {
 		logger.WithFields(logrus.Fields{
 			"error":    err.Error(),
@@ -201,9 +506,10 @@ func callGrpcMmf(ctx context.Context, cc *rpc.ClientCache, profile *pb.MatchProf
 		}).Error("failed to establish grpc client connection to match function")
 		return status.Error(codes.InvalidArgument, "failed to connect to match function")
 	}
+	defer release()
 	client := pb.NewMatchFunctionClient(conn)
 
-	stream, err := client.Run(ctx, &pb.RunRequest{Profile: profile})
+	stream, err := client.Run(ctx, runReq)
 	if err != nil {
 		logger.WithError(err).Error("failed to run match function for profile")
 		return err
@@ -218,6 +524,12 @@ func callGrpcMmf(ctx context.Context, cc *rpc.ClientCache, profile *pb.MatchProf
 			logger.Errorf("%v.Run() error, %v\n", client, err)
 			return err
 		}
+		if !budget.admit(ctx, resp.GetProposal()) {
+			continue
+		}
+		if p := resp.GetProposal(); p != nil {
+			p.MatchProfile = runReq.GetProfile().GetName()
+		}
 		select {
 		case proposals <- resp.GetProposal():
 		case <-ctx.Done():
@@ -228,7 +540,7 @@ func callGrpcMmf(ctx context.Context, cc *rpc.ClientCache, profile *pb.MatchProf
 	return nil
 }
 
-func callHTTPMmf(ctx context.Context, cc *rpc.ClientCache, profile *pb.MatchProfile, address string, proposals chan<- *pb.Match) error {
+func callHTTPMmf(ctx context.Context, cc *rpc.ClientCache, runReq *pb.RunRequest, address string, budget *profileBudget, proposals chan<- *pb.Match) error {
 	client, baseURL, err := cc.GetHTTP(address)
 	if err != nil {
 		logger.WithFields(logrus.Fields{
@@ -238,8 +550,9 @@ func callHTTPMmf(ctx context.Context, cc *rpc.ClientCache, profile *pb.MatchProf
 		return status.Error(codes.InvalidArgument, "failed to connect to match function")
 	}
 
+	profile := runReq.GetProfile()
 	var m jsonpb.Marshaler
-	strReq, err := m.MarshalToString(&pb.RunRequest{Profile: profile})
+	strReq, err := m.MarshalToString(runReq)
 	if err != nil {
 		return status.Errorf(codes.FailedPrecondition, "failed to marshal profile pb to string for profile %s: %s", profile.GetName(), err.Error())
 	}
@@ -248,6 +561,9 @@ func callHTTPMmf(ctx context.Context, cc *rpc.ClientCache, profile *pb.MatchProf
 	if err != nil {
 		return status.Errorf(codes.FailedPrecondition, "failed to create mmf http request for profile %s: %s", profile.GetName(), err.Error())
 	}
+	req.Header.Set("Content-Type", "application/json")
+	util.SetSynchronizerContextIDHeader(ctx, req)
+	mmfauth.SetHeader(ctx, req)
 
 	resp, err := client.Do(req.WithContext(ctx))
 	if err != nil {
@@ -259,6 +575,12 @@ func callHTTPMmf(ctx context.Context, cc *rpc.ClientCache, profile *pb.MatchProf
 			logger.WithError(err).Warning("failed to close response body read closer")
 		}
 	}()
+	if resp.StatusCode != http.StatusOK {
+		// The body is an error rather than a stream of results, include its
+		// start for debugging.
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxMmfErrorBodyBytes))
+		return status.Errorf(codes.Unavailable, "match function returned %s for profile %s: %s", resp.Status, profile.GetName(), strings.TrimSpace(string(body)))
+	}
 
 	dec := json.NewDecoder(resp.Body)
 	for {
@@ -281,6 +603,12 @@ func callHTTPMmf(ctx context.Context, cc *rpc.ClientCache, profile *pb.MatchProf
 		if err := jsonpb.UnmarshalString(string(item.Result), resp); err != nil {
 			return status.Errorf(codes.Unavailable, "failed to execute json.Unmarshal(%s, &resp): %v", item.Result, err)
 		}
+		if !budget.admit(ctx, resp.GetProposal()) {
+			continue
+		}
+		if p := resp.GetProposal(); p != nil {
+			p.MatchProfile = runReq.GetProfile().GetName()
+		}
 		select {
 		case proposals <- resp.GetProposal():
 		case <-ctx.Done():
@@ -302,40 +630,194 @@ func (s *backendService) ReleaseTickets(ctx context.Context, req *pb.ReleaseTick
 	return &pb.ReleaseTicketsResponse{}, nil
 }
 
+// ReleaseAllTickets removes every ticket from the ignore list, eg. to recover
+// after a director crashed without releasing the tickets of its discarded
+// matches.  It's disabled unless backend.releaseAllTickets.enabled is set.
+func (s *backendService) ReleaseAllTickets(ctx context.Context, req *pb.ReleaseAllTicketsRequest) (*pb.ReleaseAllTicketsResponse, error) {
+	if !featuregate.Enabled(s.cfg, featuregate.ReleaseAllTickets) {
+		return nil, status.Error(codes.FailedPrecondition, "ReleaseAllTickets is disabled, set backend.releaseAllTickets.enabled to allow it")
+	}
+
+	released, err := s.store.ReleaseAllTickets(ctx)
+	if err != nil {
+		logger.WithError(err).Error("failed to release all tickets")
+		return nil, err
+	}
+
+	caller := "unknown"
+	if p, ok := peer.FromContext(ctx); ok {
+		caller = p.Addr.String()
+	}
+	logger.WithFields(logrus.Fields{
+		"caller":   caller,
+		"released": released,
+	}).Warning("Released all proposed tickets back to the pool.")
+	telemetry.RecordUnitMeasurement(ctx, mReleaseAllTickets)
+	telemetry.RecordNUnitMeasurement(ctx, mTicketsReleased, int64(released))
+	return &pb.ReleaseAllTicketsResponse{ReleasedTicketCount: int64(released)}, nil
+}
+
 // AssignTickets overwrites the Assignment field of the input TicketIds.
+//   - TicketIds which don't exist are skipped and listed in the response, rather than failing the whole call.
+//   - The response has the outcome for each TicketId, including whether it was already assigned.
 func (s *backendService) AssignTickets(ctx context.Context, req *pb.AssignTicketsRequest) (*pb.AssignTicketsResponse, error) {
-	err := doAssignTickets(ctx, req, s.store)
+	resp, err := doAssignTickets(ctx, req, s.store)
 	if err != nil {
 		logger.WithError(err).Error("failed to update assignments for requested tickets")
 		return nil, err
 	}
 
-	telemetry.RecordNUnitMeasurement(ctx, mTicketsAssigned, int64(len(req.TicketIds)))
-	return &pb.AssignTicketsResponse{}, nil
+	notFound := resp.GetNotFoundTicketIds()
+	telemetry.RecordNUnitMeasurement(ctx, mTicketsAssigned, int64(len(resp.GetResults())-len(notFound)))
+	if len(notFound) > 0 {
+		telemetry.RecordNUnitMeasurement(ctx, mTicketsNotFound, int64(len(notFound)))
+	}
+	return resp, nil
 }
 
-func doAssignTickets(ctx context.Context, req *pb.AssignTicketsRequest, store statestore.Service) error {
-	err := store.UpdateAssignments(ctx, req.GetTicketIds(), req.GetAssignment())
+func doAssignTickets(ctx context.Context, req *pb.AssignTicketsRequest, store statestore.Service) (*pb.AssignTicketsResponse, error) {
+	groups, ids, err := assignmentGroups(req)
+	if err != nil {
+		return nil, err
+	}
+
+	notFound, alreadyAssigned, err := store.UpdateAssignmentGroups(ctx, groups)
 	if err != nil {
 		logger.WithError(err).Error("failed to update assignments")
-		return err
+		return nil, err
 	}
-	for _, id := range req.GetTicketIds() {
-		err = store.DeindexTicket(ctx, id)
-		// Try to deindex all input tickets. Log without returning an error if the deindexing operation failed.
-		// TODO: consider retry the index operation
-		if err != nil {
-			logger.WithError(err).Errorf("failed to deindex ticket %s after updating the assignments", id)
+	if len(notFound) > 0 {
+		logger.WithField("ticket_ids", notFound).Warning("skipped assigning tickets which were deleted")
+	}
+	if len(alreadyAssigned) > 0 {
+		logger.WithField("ticket_ids", alreadyAssigned).Warning("replaced the assignments of tickets which were already assigned")
+	}
+	// Log without returning an error if the deindexing operation failed.
+	// TODO: consider retry the index operation
+	if err = store.DeindexTickets(ctx, ids); err != nil {
+		logger.WithFields(logrus.Fields{
+			"ticket_ids": ids,
+		}).WithError(err).Error("failed to deindex tickets after updating the assignments")
+	}
+
+	if err = store.DeleteTicketsFromIgnoreList(ctx, ids); err != nil {
+		logger.WithFields(logrus.Fields{
+			"ticket_ids": ids,
+		}).Error(err)
+	}
+
+	return &pb.AssignTicketsResponse{
+		NotFoundTicketIds: notFound,
+		Results:           assignmentResults(ids, notFound, alreadyAssigned),
+	}, nil
+}
+
+// assignmentGroups returns the groups of tickets to assign, starting with the
+// request's top level ticket ids unless only groups are given, and all of
+// their ids in order.
+func assignmentGroups(req *pb.AssignTicketsRequest) ([]*pb.AssignmentGroup, []string, error) {
+	var groups []*pb.AssignmentGroup
+	if len(req.GetAssignments()) == 0 || len(req.GetTicketIds()) > 0 || req.GetAssignment() != nil {
+		groups = append(groups, &pb.AssignmentGroup{TicketIds: req.GetTicketIds(), Assignment: req.GetAssignment()})
+	}
+	groups = append(groups, req.GetAssignments()...)
+
+	var ids []string
+	seen := make(map[string]struct{})
+	for _, group := range groups {
+		if group.GetAssignment() == nil {
+			return nil, nil, status.Error(codes.InvalidArgument, "assignment is nil")
 		}
+		for _, id := range group.GetTicketIds() {
+			if _, ok := seen[id]; ok {
+				return nil, nil, status.Errorf(codes.InvalidArgument, "ticket %s is in more than one assignment group", id)
+			}
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+		}
+	}
+	return groups, ids, nil
+}
+
+// assignmentResults returns the outcome of assigning each of the ids.
+func assignmentResults(ids, notFound, alreadyAssigned []string) []*pb.AssignmentResult {
+	statuses := make(map[string]pb.AssignmentResult_Status, len(notFound)+len(alreadyAssigned))
+	for _, id := range alreadyAssigned {
+		statuses[id] = pb.AssignmentResult_ALREADY_ASSIGNED
+	}
+	for _, id := range notFound {
+		statuses[id] = pb.AssignmentResult_NOT_FOUND
+	}
+
+	results := make([]*pb.AssignmentResult, 0, len(ids))
+	for _, id := range ids {
+		results = append(results, &pb.AssignmentResult{TicketId: id, Status: statuses[id]})
 	}
+	return results
+}
 
-	if err = store.DeleteTicketsFromIgnoreList(ctx, req.GetTicketIds()); err != nil {
+// RequeueTickets clears the Assignment of the input TicketIds and puts them back into the matchmaking
+// pool, incrementing their assignment_failures.
+//   - TicketIds which aren't assigned are skipped, so that failures can be reported again safely.
+//   - TicketIds whose Assignment failed too many times keep it and are listed in the response.
+func (s *backendService) RequeueTickets(ctx context.Context, req *pb.RequeueTicketsRequest) (*pb.RequeueTicketsResponse, error) {
+	resp, err := doRequeueTickets(ctx, req, s.store, s.maxAssignmentFailures)
+	if err != nil {
+		logger.WithError(err).Error("failed to requeue the requested tickets")
+		return nil, err
+	}
+	return resp, nil
+}
+
+func doRequeueTickets(ctx context.Context, req *pb.RequeueTicketsRequest, store statestore.Service, maxAssignmentFailures int) (*pb.RequeueTicketsResponse, error) {
+	requeued, notFound, exhausted, err := store.RequeueFailedAssignments(ctx, req.GetTicketIds(), maxAssignmentFailures)
+	if err != nil {
 		logger.WithFields(logrus.Fields{
 			"ticket_ids": req.GetTicketIds(),
-		}).Error(err)
+		}).WithError(err).Error("failed to requeue tickets")
+		return nil, err
+	}
+	// Tickets which aren't assigned are skipped, so only count those requeued.
+	telemetry.RecordNUnitMeasurement(ctx, mTicketsRequeued, requeued)
+	if len(exhausted) > 0 {
+		logger.WithField("ticket_ids", exhausted).Warning("left tickets assigned as their assignments failed too often")
+		telemetry.RecordNUnitMeasurement(ctx, mTicketsExhausted, int64(len(exhausted)))
 	}
 
-	return nil
+	return &pb.RequeueTicketsResponse{NotFoundTicketIds: notFound, ExhaustedTicketIds: exhausted}, nil
+}
+
+// ReserveTickets reserves Tickets for a scheduled event.
+//   - Until the start_time, the Tickets are excluded from every Pool queried.
+//   - From the start_time, the Tickets are only included in Pools with a TagPresentFilter on the event's tag.
+func (s *backendService) ReserveTickets(ctx context.Context, req *pb.ReserveTicketsRequest) (*pb.ReserveTicketsResponse, error) {
+	if req.GetEventId() == "" {
+		return nil, status.Error(codes.InvalidArgument, ".event_id is required")
+	}
+	if req.GetStartTime() == nil {
+		return nil, status.Error(codes.InvalidArgument, ".start_time is required")
+	}
+	start, err := ptypes.Timestamp(req.GetStartTime())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid .start_time: %v", err)
+	}
+	return doReserveTickets(ctx, req.GetEventId(), start, req.GetTicketIds(), s.store)
+}
+
+func doReserveTickets(ctx context.Context, eventID string, start time.Time, ids []string, store statestore.Service) (*pb.ReserveTicketsResponse, error) {
+	reserved, notFound, err := store.RewriteTicketsByID(ctx, ids, func(ticket *pb.Ticket) bool {
+		pb.ReserveForEvent(ticket, eventID, start)
+		return true
+	})
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"error":   err.Error(),
+			"eventId": eventID,
+		}).Error("failed to reserve the tickets")
+		return nil, err
+	}
+	telemetry.RecordNUnitMeasurement(ctx, mTicketsReserved, reserved)
+	return &pb.ReserveTicketsResponse{NotFoundTicketIds: notFound}, nil
 }
 
 func doReleasetickets(ctx context.Context, req *pb.ReleaseTicketsRequest, store statestore.Service) error {
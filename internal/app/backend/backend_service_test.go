@@ -0,0 +1,364 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/ipb"
+	"open-match.dev/open-match/internal/rpc"
+	statestoreTesting "open-match.dev/open-match/internal/statestore/testing"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestLeaseProposalTickets(t *testing.T) {
+	assert := assert.New(t)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, viper.New())
+	defer closer()
+	defer store.Close()
+	ctx := utilTesting.NewContext(t)
+
+	proposal := &pb.Match{MatchId: "1", Tickets: []*pb.Ticket{{Id: "a"}, {Id: "b"}}}
+	overlapping := &pb.Match{MatchId: "2", Tickets: []*pb.Ticket{{Id: "b"}, {Id: "c"}}}
+
+	assert.True(leaseProposalTickets(ctx, store, "cycle1", proposal))
+	// Proposals of the same cycle may overlap, the evaluator picks between them.
+	assert.True(leaseProposalTickets(ctx, store, "cycle1", overlapping))
+	assert.False(leaseProposalTickets(ctx, store, "cycle2", overlapping))
+
+	// Without a cycle id there's no lease owner, the synchronizer leases the
+	// tickets once the proposal is accepted.
+	assert.True(leaseProposalTickets(ctx, store, "", overlapping))
+}
+
+func TestReleaseMatchTickets(t *testing.T) {
+	assert := assert.New(t)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, viper.New())
+	defer closer()
+	defer store.Close()
+	ctx := utilTesting.NewContext(t)
+
+	match := &pb.Match{MatchId: "1", Tickets: []*pb.Ticket{{Id: "a"}, {Id: "b"}}}
+	assert.True(leaseProposalTickets(ctx, store, "cycle1", match))
+	assert.False(leaseProposalTickets(ctx, store, "cycle2", match))
+
+	// Tickets of a dropped match can be proposed by the next cycle.
+	releaseMatchTickets(ctx, store, "cycle1", match)
+	assert.True(leaseProposalTickets(ctx, store, "cycle2", match))
+}
+
+func TestFetchMmfTimeout(t *testing.T) {
+	assert := assert.New(t)
+	s := &backendService{mmfTimeout: time.Minute}
+
+	timeout, err := s.fetchMmfTimeout(&pb.FetchMatchesRequest{})
+	assert.Nil(err)
+	assert.Equal(time.Minute, timeout)
+
+	timeout, err = s.fetchMmfTimeout(&pb.FetchMatchesRequest{MmfTimeout: ptypes.DurationProto(time.Second)})
+	assert.Nil(err)
+	assert.Equal(time.Second, timeout)
+
+	// Requests can't extend the configured timeout.
+	timeout, err = s.fetchMmfTimeout(&pb.FetchMatchesRequest{MmfTimeout: ptypes.DurationProto(time.Hour)})
+	assert.Nil(err)
+	assert.Equal(time.Minute, timeout)
+
+	_, err = s.fetchMmfTimeout(&pb.FetchMatchesRequest{MmfTimeout: ptypes.DurationProto(-time.Second)})
+	assert.Equal(codes.InvalidArgument, status.Convert(err).Code())
+
+	s.mmfTimeout = 0
+	timeout, err = s.fetchMmfTimeout(&pb.FetchMatchesRequest{MmfTimeout: ptypes.DurationProto(time.Hour)})
+	assert.Nil(err)
+	assert.Equal(time.Hour, timeout)
+}
+
+func TestDoRequeueTickets(t *testing.T) {
+	assert := assert.New(t)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, viper.New())
+	defer closer()
+	defer store.Close()
+	ctx := utilTesting.NewContext(t)
+
+	tickets := []*pb.Ticket{{Id: "a"}, {Id: "b"}}
+	assert.Nil(store.CreateTickets(ctx, tickets))
+	assert.Nil(store.IndexTickets(ctx, tickets))
+	_, err := doAssignTickets(ctx, &pb.AssignTicketsRequest{TicketIds: []string{"a", "b"}, Assignment: &pb.Assignment{Connection: "1"}}, store)
+	assert.Nil(err)
+
+	req := &pb.RequeueTicketsRequest{TicketIds: []string{"a", "c"}}
+	resp, err := doRequeueTickets(ctx, req, store, 1)
+	assert.Nil(err)
+	assert.Equal([]string{"c"}, resp.GetNotFoundTicketIds())
+	assert.Empty(resp.GetExhaustedTicketIds())
+
+	ids, err := store.GetIndexedIDSet(ctx)
+	assert.Nil(err)
+	assert.Contains(ids, "a")
+	assert.NotContains(ids, "b")
+
+	// Failing again exceeds the limit and leaves the ticket assigned.
+	_, err = doAssignTickets(ctx, &pb.AssignTicketsRequest{TicketIds: []string{"a"}, Assignment: &pb.Assignment{Connection: "2"}}, store)
+	assert.Nil(err)
+	resp, err = doRequeueTickets(ctx, req, store, 1)
+	assert.Nil(err)
+	assert.Equal([]string{"a"}, resp.GetExhaustedTicketIds())
+
+	ticket, err := store.GetTicket(ctx, "a")
+	assert.Nil(err)
+	assert.Equal("2", ticket.GetAssignment().GetConnection())
+	assert.Equal(int32(1), ticket.GetAssignmentFailures())
+}
+
+func TestReleaseAllTickets(t *testing.T) {
+	assert := assert.New(t)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, viper.New())
+	defer closer()
+	defer store.Close()
+	ctx := utilTesting.NewContext(t)
+
+	tickets := []*pb.Ticket{{Id: "a"}, {Id: "b"}}
+	assert.Nil(store.CreateTickets(ctx, tickets))
+	assert.Nil(store.IndexTickets(ctx, tickets))
+	assert.True(leaseProposalTickets(ctx, store, "cycle", &pb.Match{MatchId: "1", Tickets: tickets}))
+
+	cfg := viper.New()
+	s := &backendService{cfg: cfg, store: store}
+	_, err := s.ReleaseAllTickets(ctx, &pb.ReleaseAllTicketsRequest{})
+	assert.Equal(codes.FailedPrecondition, status.Code(err))
+
+	cfg.Set("backend.releaseAllTickets.enabled", true)
+	resp, err := s.ReleaseAllTickets(ctx, &pb.ReleaseAllTicketsRequest{})
+	assert.Nil(err)
+	assert.Equal(int64(2), resp.GetReleasedTicketCount())
+
+	ids, err := store.GetIndexedIDSet(ctx)
+	assert.Nil(err)
+	assert.Len(ids, 2)
+}
+
+type fakeSynchronizerStream struct {
+	resps []*ipb.SynchronizeResponse
+}
+
+func (f *fakeSynchronizerStream) Send(*ipb.SynchronizeRequest) error { return nil }
+func (f *fakeSynchronizerStream) CloseSend() error                   { return nil }
+
+func (f *fakeSynchronizerStream) Recv() (*ipb.SynchronizeResponse, error) {
+	if len(f.resps) == 0 {
+		return nil, io.EOF
+	}
+	resp := f.resps[0]
+	f.resps = f.resps[1:]
+	return resp, nil
+}
+
+func TestSynchronizeRecvFlowControl(t *testing.T) {
+	assert := assert.New(t)
+	ctx, cancel := context.WithCancel(utilTesting.NewContext(t))
+	defer cancel()
+
+	m := &sync.Map{}
+	m.Store("1", &pb.Match{MatchId: "1"})
+	m.Store("2", &pb.Match{MatchId: "2"})
+	syncStream := &fakeSynchronizerStream{resps: []*ipb.SynchronizeResponse{
+		{StartMmfs: true, CycleId: "cycle"},
+		{MatchId: "1"},
+		{MatchId: "2"},
+	}}
+
+	results := make(chan *pb.Match, 1)
+	var cycleID string
+	errs := make(chan error)
+	go func() {
+		errs <- synchronizeRecv(ctx, syncStream, m, results, &cycleID, make(chan struct{}), cancel)
+	}()
+
+	// The first match is held for the caller, the second waits for room.
+	assert.Equal("1", (<-results).GetMatchId())
+	assert.Equal("2", (<-results).GetMatchId())
+	assert.Nil(<-errs)
+	assert.Equal("cycle", cycleID)
+
+	// Returned proposals are released.
+	_, ok := m.Load("1")
+	assert.False(ok)
+
+	// A caller which stops receiving doesn't block synchronizeRecv forever.
+	m.Store("3", &pb.Match{MatchId: "3"})
+	m.Store("4", &pb.Match{MatchId: "4"})
+	syncStream.resps = []*ipb.SynchronizeResponse{{MatchId: "3"}, {MatchId: "4"}}
+	go func() {
+		errs <- synchronizeRecv(ctx, syncStream, m, results, &cycleID, make(chan struct{}), cancel)
+	}()
+	assert.Eventually(func() bool { return len(results) == 1 }, time.Second, time.Millisecond)
+	cancel()
+	assert.Equal(context.Canceled, <-errs)
+}
+
+func TestDoAssignTickets(t *testing.T) {
+	assert := assert.New(t)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, viper.New())
+	defer closer()
+	defer store.Close()
+	ctx := utilTesting.NewContext(t)
+
+	assert.Nil(store.CreateTickets(ctx, []*pb.Ticket{{Id: "a"}, {Id: "b"}}))
+	_, err := doAssignTickets(ctx, &pb.AssignTicketsRequest{TicketIds: []string{"a"}, Assignment: &pb.Assignment{Connection: "1"}}, store)
+	assert.Nil(err)
+
+	resp, err := doAssignTickets(ctx, &pb.AssignTicketsRequest{TicketIds: []string{"a", "b", "c"}, Assignment: &pb.Assignment{Connection: "2"}}, store)
+	assert.Nil(err)
+	assert.Equal([]string{"c"}, resp.GetNotFoundTicketIds())
+	assert.Equal([]*pb.AssignmentResult{
+		{TicketId: "a", Status: pb.AssignmentResult_ALREADY_ASSIGNED},
+		{TicketId: "b", Status: pb.AssignmentResult_ASSIGNED},
+		{TicketId: "c", Status: pb.AssignmentResult_NOT_FOUND},
+	}, resp.GetResults())
+}
+
+func TestDoAssignTicketsGroups(t *testing.T) {
+	assert := assert.New(t)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, viper.New())
+	defer closer()
+	defer store.Close()
+	ctx := utilTesting.NewContext(t)
+
+	tickets := []*pb.Ticket{{Id: "a"}, {Id: "b"}, {Id: "c"}}
+	assert.Nil(store.CreateTickets(ctx, tickets))
+	assert.Nil(store.IndexTickets(ctx, tickets))
+
+	resp, err := doAssignTickets(ctx, &pb.AssignTicketsRequest{Assignments: []*pb.AssignmentGroup{
+		{TicketIds: []string{"a", "b"}, Assignment: &pb.Assignment{Connection: "red"}},
+		{TicketIds: []string{"c", "d"}, Assignment: &pb.Assignment{Connection: "blue"}},
+	}}, store)
+	assert.Nil(err)
+	assert.Equal([]string{"d"}, resp.GetNotFoundTicketIds())
+	assert.Len(resp.GetResults(), 4)
+
+	for id, connection := range map[string]string{"a": "red", "b": "red", "c": "blue"} {
+		ticket, err := store.GetTicket(ctx, id)
+		assert.Nil(err)
+		assert.Equal(connection, ticket.GetAssignment().GetConnection())
+	}
+	ids, err := store.GetIndexedIDSet(ctx)
+	assert.Nil(err)
+	assert.Empty(ids)
+
+	// A ticket can't get two assignments.
+	_, err = doAssignTickets(ctx, &pb.AssignTicketsRequest{
+		TicketIds:   []string{"a"},
+		Assignment:  &pb.Assignment{Connection: "red"},
+		Assignments: []*pb.AssignmentGroup{{TicketIds: []string{"a"}, Assignment: &pb.Assignment{Connection: "blue"}}},
+	}, store)
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+
+	_, err = doAssignTickets(ctx, &pb.AssignTicketsRequest{}, store)
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func TestCallHTTPMmf(t *testing.T) {
+	assert := assert.New(t)
+	ctx := utilTesting.NewContext(t)
+
+	var code int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("/v1/matchfunction:run", r.URL.Path)
+		assert.Equal("application/json", r.Header.Get("Content-Type"))
+		if code != http.StatusOK {
+			http.Error(w, "no such function", code)
+			return
+		}
+		// Results are streamed as the match function produces them.
+		for _, id := range []string{"1", "2"} {
+			fmt.Fprintf(w, `{"result":{"proposal":{"matchId":"%s"}}}`+"\n", id)
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	cc := rpc.NewClientCache(viper.New(), 0)
+	profile := &pb.MatchProfile{Name: "profile"}
+	call := func() ([]*pb.Match, error) {
+		proposals := make(chan *pb.Match, 10)
+		err := callHTTPMmf(ctx, cc, &pb.RunRequest{Profile: profile}, server.URL, newProfileBudget(profile), proposals)
+		close(proposals)
+		var got []*pb.Match
+		for p := range proposals {
+			got = append(got, p)
+		}
+		return got, err
+	}
+
+	code = http.StatusOK
+	got, err := call()
+	assert.Nil(err)
+	assert.Len(got, 2)
+	for _, m := range got {
+		assert.Equal("profile", m.GetMatchProfile())
+	}
+
+	code = http.StatusNotFound
+	_, err = call()
+	assert.Equal(codes.Unavailable, status.Code(err))
+	assert.Contains(err.Error(), "no such function")
+}
+
+func TestDoReserveTickets(t *testing.T) {
+	assert := assert.New(t)
+	ctx := utilTesting.NewContext(t)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, viper.New())
+	defer closer()
+
+	ticket := &pb.Ticket{
+		Id:           "1",
+		SearchFields: &pb.SearchFields{Tags: []string{"ranked"}},
+	}
+	assert.Nil(store.CreateTicket(ctx, ticket))
+	assert.Nil(store.IndexTicket(ctx, ticket))
+	revision, err := store.GetTicketsRevision(ctx)
+	assert.Nil(err)
+
+	start := time.Unix(1000, 0)
+	resp, err := doReserveTickets(ctx, "cup", start, []string{"1", "2"}, store)
+	assert.Nil(err)
+	assert.Equal([]string{"2"}, resp.GetNotFoundTicketIds())
+
+	got, err := store.GetTicket(ctx, "1")
+	assert.Nil(err)
+	eventID, gotStart, ok := pb.ReservedEvent(got)
+	assert.True(ok)
+	assert.Equal("cup", eventID)
+	assert.True(start.Equal(gotStart))
+	assert.ElementsMatch([]string{"ranked", pb.EventTag("cup")}, got.GetSearchFields().GetTags())
+
+	// The query service's ticket cache must refetch the reserved ticket.
+	newRevision, err := store.GetTicketsRevision(ctx)
+	assert.Nil(err)
+	assert.NotEqual(revision, newRevision)
+}
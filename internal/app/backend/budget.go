@@ -0,0 +1,82 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/telemetry"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+var (
+	mProposalsRejected = telemetry.Counter("backend/proposals_rejected", "proposals rejected for exceeding the match profile budget")
+)
+
+// validateProfileBudget checks that the budget declared by a MatchProfile is well formed.
+func validateProfileBudget(profile *pb.MatchProfile) error {
+	if profile.GetMaxTicketsPerMatch() < 0 {
+		return status.Error(codes.InvalidArgument, ".profile.max_tickets_per_match must not be negative")
+	}
+	if profile.GetMaxMatchesPerCycle() < 0 {
+		return status.Error(codes.InvalidArgument, ".profile.max_matches_per_cycle must not be negative")
+	}
+	return nil
+}
+
+// profileBudget tracks proposals received from a match function against the
+// limits declared by its MatchProfile, so that a misbehaving match function
+// can't flood the evaluator and assignment with oversized or excess matches.
+type profileBudget struct {
+	profile  *pb.MatchProfile
+	accepted int32
+}
+
+func newProfileBudget(profile *pb.MatchProfile) *profileBudget {
+	return &profileBudget{profile: profile}
+}
+
+// admit returns true if the proposal fits within the profile's budget, and
+// counts it against the budget.  Rejected proposals are logged and dropped.
+func (b *profileBudget) admit(ctx context.Context, proposal *pb.Match) bool {
+	maxTickets := b.profile.GetMaxTicketsPerMatch()
+	if maxTickets > 0 && int32(len(proposal.GetTickets())) > maxTickets {
+		logger.WithFields(logrus.Fields{
+			"profile":    b.profile.GetName(),
+			"matchId":    proposal.GetMatchId(),
+			"tickets":    len(proposal.GetTickets()),
+			"maxTickets": maxTickets,
+		}).Warning("rejected proposal with more tickets than allowed by the match profile")
+		telemetry.RecordUnitMeasurement(ctx, mProposalsRejected)
+		return false
+	}
+
+	maxMatches := b.profile.GetMaxMatchesPerCycle()
+	if maxMatches > 0 && b.accepted >= maxMatches {
+		logger.WithFields(logrus.Fields{
+			"profile":    b.profile.GetName(),
+			"matchId":    proposal.GetMatchId(),
+			"maxMatches": maxMatches,
+		}).Warning("rejected proposal exceeding the match profile's matches per cycle")
+		telemetry.RecordUnitMeasurement(ctx, mProposalsRejected)
+		return false
+	}
+
+	b.accepted++
+	return true
+}
@@ -0,0 +1,86 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestValidateProfileBudget(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(validateProfileBudget(&pb.MatchProfile{}))
+	assert.Nil(validateProfileBudget(&pb.MatchProfile{MaxTicketsPerMatch: 4, MaxMatchesPerCycle: 10}))
+	assert.Equal(codes.InvalidArgument, status.Convert(validateProfileBudget(&pb.MatchProfile{MaxTicketsPerMatch: -1})).Code())
+	assert.Equal(codes.InvalidArgument, status.Convert(validateProfileBudget(&pb.MatchProfile{MaxMatchesPerCycle: -1})).Code())
+}
+
+func TestProfileBudgetAdmit(t *testing.T) {
+	matchWithTickets := func(n int) *pb.Match {
+		m := &pb.Match{}
+		for i := 0; i < n; i++ {
+			m.Tickets = append(m.Tickets, &pb.Ticket{})
+		}
+		return m
+	}
+
+	tests := []struct {
+		description string
+		profile     *pb.MatchProfile
+		proposals   []*pb.Match
+		expected    []bool
+	}{
+		{
+			description: "no limits",
+			profile:     &pb.MatchProfile{},
+			proposals:   []*pb.Match{matchWithTickets(500), matchWithTickets(1)},
+			expected:    []bool{true, true},
+		},
+		{
+			description: "oversized matches are rejected",
+			profile:     &pb.MatchProfile{MaxTicketsPerMatch: 2},
+			proposals:   []*pb.Match{matchWithTickets(2), matchWithTickets(3), matchWithTickets(1)},
+			expected:    []bool{true, false, true},
+		},
+		{
+			description: "matches beyond the cycle limit are rejected",
+			profile:     &pb.MatchProfile{MaxMatchesPerCycle: 2},
+			proposals:   []*pb.Match{matchWithTickets(1), matchWithTickets(1), matchWithTickets(1)},
+			expected:    []bool{true, true, false},
+		},
+		{
+			description: "rejected matches don't count against the cycle limit",
+			profile:     &pb.MatchProfile{MaxTicketsPerMatch: 2, MaxMatchesPerCycle: 1},
+			proposals:   []*pb.Match{matchWithTickets(3), matchWithTickets(2), matchWithTickets(2)},
+			expected:    []bool{false, true, false},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			budget := newProfileBudget(test.profile)
+			for i, p := range test.proposals {
+				assert.Equal(t, test.expected[i], budget.admit(context.Background(), p), "proposal %d", i)
+			}
+		})
+	}
+}
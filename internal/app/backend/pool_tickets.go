@@ -0,0 +1,112 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/rpc"
+	"open-match.dev/open-match/internal/telemetry"
+	"open-match.dev/open-match/pkg/matchfunction"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+var (
+	mPoolTicketsPassed  = telemetry.Counter("backend/pool_tickets_passed", "match function runs passed the tickets of their pools by the backend")
+	mPoolTicketsSkipped = telemetry.Counter("backend/pool_tickets_skipped", "match function runs left to query their pools as a pool exceeded backend.maxPassedPoolSize")
+)
+
+// poolQuerier queries the pools of a profile on behalf of match functions
+// which have the backend pass them their pools' tickets.
+type poolQuerier struct {
+	cfg    config.View
+	cacher *config.Cacher
+}
+
+func newPoolQuerier(cfg config.View) *poolQuerier {
+	newInstance := func(cfg config.View) (interface{}, func(), error) {
+		conn, release, err := rpc.SharedGRPCClientFromConfig(cfg, "api.query")
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return pb.NewQueryServiceClient(conn), release, nil
+	}
+
+	return &poolQuerier{
+		cfg:    cfg,
+		cacher: config.NewCacher(cfg, newInstance),
+	}
+}
+
+// query returns the tickets of each of the profile's pools, keyed by pool
+// name.  Pools are queried in parallel, with any mmf token in ctx.  It returns
+// nil if a pool has more than backend.maxPassedPoolSize tickets, leaving the
+// match function to query the pools itself.
+func (q *poolQuerier) query(ctx context.Context, profile *pb.MatchProfile) (map[string]*pb.PoolTickets, error) {
+	client, err := q.cacher.Get()
+	if err != nil {
+		return nil, err
+	}
+	qsc := client.(pb.QueryServiceClient)
+
+	maxSize := getMaxPassedPoolSize(q.cfg)
+	for _, pool := range profile.GetPools() {
+		// Counted from the statestore's indices, without the query service's
+		// tagAbsent default filters, so it's an upper bound of the pool's size.
+		stats, err := qsc.GetPoolStats(ctx, &pb.GetPoolStatsRequest{Pool: pool})
+		if err != nil {
+			return nil, err
+		}
+		if stats.GetTicketCount() > maxSize {
+			logger.WithFields(logrus.Fields{
+				"profile":     profile.GetName(),
+				"pool":        pool.GetName(),
+				"ticketCount": stats.GetTicketCount(),
+				"maxSize":     maxSize,
+			}).Debug("pool is too large to pass its tickets to the match function")
+			telemetry.RecordUnitMeasurement(ctx, mPoolTicketsSkipped)
+			return nil, nil
+		}
+	}
+
+	poolMap, err := matchfunction.QueryPools(ctx, qsc, profile.GetPools())
+	if err != nil {
+		return nil, err
+	}
+
+	poolTickets := make(map[string]*pb.PoolTickets, len(poolMap))
+	for name, tickets := range poolMap {
+		poolTickets[name] = &pb.PoolTickets{Tickets: tickets}
+	}
+	telemetry.RecordUnitMeasurement(ctx, mPoolTicketsPassed)
+	return poolTickets, nil
+}
+
+func getMaxPassedPoolSize(cfg config.View) int64 {
+	const (
+		name = "backend.maxPassedPoolSize"
+		// Default number of tickets a pool may have for the backend to pass them
+		// to the match function.
+		defaultMaxPassedPoolSize int64 = 1000
+	)
+
+	if !cfg.IsSet(name) {
+		return defaultMaxPassedPoolSize
+	}
+	return cfg.GetInt64(name)
+}
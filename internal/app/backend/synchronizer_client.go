@@ -14,19 +14,12 @@ type synchronizerClient struct {
 
 func newSynchronizerClient(cfg config.View) *synchronizerClient {
 	newInstance := func(cfg config.View) (interface{}, func(), error) {
-		conn, err := rpc.GRPCClientFromConfig(cfg, "api.synchronizer")
+		conn, release, err := rpc.SharedGRPCClientFromConfig(cfg, "api.synchronizer")
 		if err != nil {
 			return nil, nil, err
 		}
 
-		close := func() {
-			err := conn.Close()
-			if err != nil {
-				logger.WithError(err).Warning("Error closing synchronizer client.")
-			}
-		}
-
-		return ipb.NewSynchronizerClient(conn), close, nil
+		return ipb.NewSynchronizerClient(conn), release, nil
 	}
 
 	return &synchronizerClient{
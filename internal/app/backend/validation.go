@@ -0,0 +1,108 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/featuregate"
+	"open-match.dev/open-match/internal/statestore"
+	"open-match.dev/open-match/internal/telemetry"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+var (
+	mProposalsInvalid = telemetry.Counter("backend/proposals_invalid", "proposals dropped by the backend's proposal validation")
+)
+
+// proposalValidator checks match function proposals before they are sent to
+// the evaluator, so that a misbehaving match function can't have matches with
+// unknown, assigned or repeated tickets accepted.
+type proposalValidator struct {
+	cfg   config.View
+	store statestore.Service
+	// maxTickets is the most tickets a proposal may have, unlimited if 0.
+	maxTickets int
+}
+
+// newProposalValidator returns the validator configured under
+// backend.proposalValidation.
+func newProposalValidator(cfg config.View, store statestore.Service) *proposalValidator {
+	return &proposalValidator{
+		cfg:        cfg,
+		store:      store,
+		maxTickets: cfg.GetInt("backend.proposalValidation.maxTickets"),
+	}
+}
+
+// admit returns true if the proposal is valid.  Invalid proposals are logged
+// and dropped.  Proposals are admitted if their tickets can't be looked up.
+func (v *proposalValidator) admit(ctx context.Context, p *pb.Match) bool {
+	if v == nil {
+		return true
+	}
+
+	reason := v.validate(ctx, p)
+	if reason == "" {
+		return true
+	}
+	logger.WithFields(logrus.Fields{
+		"profile": p.GetMatchProfile(),
+		"matchId": p.GetMatchId(),
+		"reason":  reason,
+	}).Warning("dropped invalid proposal")
+	telemetry.RecordUnitMeasurement(ctx, mProposalsInvalid)
+	return false
+}
+
+// validate returns why the proposal is invalid, or "" if it's valid.
+func (v *proposalValidator) validate(ctx context.Context, p *pb.Match) string {
+	if v.maxTickets > 0 && len(p.GetTickets()) > v.maxTickets {
+		return fmt.Sprintf("%d tickets, more than the %d allowed", len(p.GetTickets()), v.maxTickets)
+	}
+
+	ids := make([]string, 0, len(p.GetTickets()))
+	seen := make(map[string]struct{}, len(p.GetTickets()))
+	for _, t := range p.GetTickets() {
+		if _, ok := seen[t.GetId()]; ok {
+			return fmt.Sprintf("ticket %s is in the match more than once", t.GetId())
+		}
+		seen[t.GetId()] = struct{}{}
+		ids = append(ids, t.GetId())
+	}
+
+	// Tickets are looked up in state storage unless the gate is turned off.
+	if !featuregate.Enabled(v.cfg, featuregate.CheckProposalTickets) {
+		return ""
+	}
+	stored, err := v.store.GetTickets(ctx, ids)
+	if err != nil {
+		logger.WithError(err).WithField("matchId", p.GetMatchId()).Warning("failed to look up proposal tickets, admitting the proposal")
+		return ""
+	}
+	for _, t := range stored {
+		if t.GetAssignment() != nil {
+			return fmt.Sprintf("ticket %s is already assigned", t.GetId())
+		}
+		delete(seen, t.GetId())
+	}
+	for id := range seen {
+		return fmt.Sprintf("ticket %s doesn't exist", id)
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	statestoreTesting "open-match.dev/open-match/internal/statestore/testing"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestProposalValidator(t *testing.T) {
+	assert := assert.New(t)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, viper.New())
+	defer closer()
+	defer store.Close()
+	ctx := utilTesting.NewContext(t)
+
+	assert.Nil(store.CreateTickets(ctx, []*pb.Ticket{{Id: "a"}, {Id: "b"}, {Id: "assigned"}}))
+	_, _, err := store.UpdateAssignments(ctx, []string{"assigned"}, &pb.Assignment{Connection: "1"})
+	assert.Nil(err)
+
+	cfg := viper.New()
+	cfg.Set("backend.proposalValidation.maxTickets", 2)
+	v := newProposalValidator(cfg, store)
+
+	match := func(ids ...string) *pb.Match {
+		m := &pb.Match{MatchId: "m"}
+		for _, id := range ids {
+			m.Tickets = append(m.Tickets, &pb.Ticket{Id: id})
+		}
+		return m
+	}
+
+	assert.True(v.admit(ctx, match("a", "b")))
+	assert.False(v.admit(ctx, match("a", "b", "c")))
+	assert.False(v.admit(ctx, match("a", "a")))
+	assert.False(v.admit(ctx, match("a", "missing")))
+	assert.False(v.admit(ctx, match("a", "assigned")))
+
+	// Without looking tickets up, only the proposal itself is checked.
+	cfg.Set("backend.proposalValidation.checkTickets", false)
+	assert.True(v.admit(ctx, match("a", "missing")))
+	assert.False(v.admit(ctx, match("a", "a")))
+}
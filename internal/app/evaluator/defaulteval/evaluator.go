@@ -19,7 +19,6 @@ import (
 	"math"
 	"sort"
 
-	"github.com/golang/protobuf/ptypes"
 	"github.com/sirupsen/logrus"
 	"open-match.dev/open-match/internal/app/evaluator"
 	"open-match.dev/open-match/pkg/pb"
@@ -44,22 +43,29 @@ func Evaluate(p *evaluator.Params) ([]string, error) {
 	nilEvlautionInputs := 0
 
 	for _, m := range p.Matches {
+		if stale := evaluator.StaleTicketIDs(m); len(stale) > 0 {
+			logger.WithFields(logrus.Fields{
+				"match_id":   m.GetMatchId(),
+				"ticket_ids": stale,
+			}).Info("Match contains deleted or assigned tickets.  Rejecting match.")
+			continue
+		}
+
 		// Evaluation criteria is optional, but sort it lower than any matches which
 		// provided criteria.
 		inp := &pb.DefaultEvaluationCriteria{
 			Score: math.Inf(-1),
 		}
 
-		if a, ok := m.Extensions["evaluation_input"]; ok {
-			err := ptypes.UnmarshalAny(a, inp)
-			if err != nil {
-				logger.WithFields(logrus.Fields{
-					"match_id": m.MatchId,
-					"error":    err,
-				}).Error("Failed to unmarshal match's DefaultEvaluationCriteria.  Rejecting match.")
-				continue
-			}
-		} else {
+		ok, err := pb.GetExtension(m.Extensions, pb.EvaluationInputExtensionKey, inp)
+		if err != nil {
+			logger.WithFields(logrus.Fields{
+				"match_id": m.MatchId,
+				"error":    err,
+			}).Error("Failed to unmarshal match's DefaultEvaluationCriteria.  Rejecting match.")
+			continue
+		}
+		if !ok {
 			nilEvlautionInputs++
 		}
 		matches = append(matches, &matchInp{
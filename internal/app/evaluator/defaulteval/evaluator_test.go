@@ -78,6 +78,31 @@ func TestEvaluate(t *testing.T) {
 		},
 	}
 
+	staleTicket := &pb.Ticket{Id: "4"}
+	assert.Nil(t, evaluator.SetTicketValid(staleTicket, false))
+	freshTicket := &pb.Ticket{Id: "5"}
+	assert.Nil(t, evaluator.SetTicketValid(freshTicket, true))
+
+	ticket45Score100 := &pb.Match{
+		MatchId: "ticket45Score100",
+		Tickets: []*pb.Ticket{staleTicket, freshTicket},
+		Extensions: map[string]*any.Any{
+			"evaluation_input": mustAny(&pb.DefaultEvaluationCriteria{
+				Score: 100,
+			}),
+		},
+	}
+
+	ticket5Score1 := &pb.Match{
+		MatchId: "ticket5Score1",
+		Tickets: []*pb.Ticket{freshTicket},
+		Extensions: map[string]*any.Any{
+			"evaluation_input": mustAny(&pb.DefaultEvaluationCriteria{
+				Score: 1,
+			}),
+		},
+	}
+
 	tests := []struct {
 		description  string
 		testMatches  []*pb.Match
@@ -108,6 +133,11 @@ func TestEvaluate(t *testing.T) {
 			testMatches:  []*pb.Match{ticket12Score1, ticket12Score10, ticket123Score5, ticket3Score50},
 			wantMatchIDs: []string{ticket12Score10.GetMatchId(), ticket3Score50.GetMatchId()},
 		},
+		{
+			description:  "test rejects matches with stale tickets",
+			testMatches:  []*pb.Match{ticket45Score100, ticket5Score1},
+			wantMatchIDs: []string{ticket5Score1.GetMatchId()},
+		},
 	}
 
 	for _, test := range tests {
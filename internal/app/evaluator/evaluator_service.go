@@ -20,6 +20,7 @@ import (
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/util"
 	"open-match.dev/open-match/pkg/pb"
 
 	"github.com/sirupsen/logrus"
@@ -57,6 +58,9 @@ type Params struct {
 // api/evaluator.proto.
 func (s *evaluatorService) Evaluate(stream pb.Evaluator_EvaluateServer) error {
 	var matches = []*pb.Match{}
+	// Results are chunked like the largest chunk of proposals, if the
+	// synchronizer sent any.
+	chunkSize := 0
 	for {
 		req, err := stream.Recv()
 		if err == io.EOF {
@@ -65,7 +69,13 @@ func (s *evaluatorService) Evaluate(stream pb.Evaluator_EvaluateServer) error {
 		if err != nil {
 			return err
 		}
-		matches = append(matches, req.GetMatch())
+		if req.GetMatch() != nil {
+			matches = append(matches, req.GetMatch())
+		}
+		matches = append(matches, req.GetMatches()...)
+		if len(req.GetMatches()) > chunkSize {
+			chunkSize = len(req.GetMatches())
+		}
 	}
 
 	// Run the customized evaluator!
@@ -73,6 +83,7 @@ func (s *evaluatorService) Evaluate(stream pb.Evaluator_EvaluateServer) error {
 		Logger: logrus.WithFields(logrus.Fields{
 			"app":       "openmatch",
 			"component": "evaluator.implementation",
+			"cycleId":   util.GetSynchronizerContextID(stream.Context()),
 		}),
 		Matches: matches,
 	})
@@ -80,10 +91,8 @@ func (s *evaluatorService) Evaluate(stream pb.Evaluator_EvaluateServer) error {
 		return status.Error(codes.Aborted, err.Error())
 	}
 
-	for _, result := range results {
-		if err := stream.Send(&pb.EvaluateResponse{MatchId: result}); err != nil {
-			return err
-		}
+	if err := sendResults(stream, results, chunkSize); err != nil {
+		return err
 	}
 
 	logger.WithFields(logrus.Fields{
@@ -91,3 +100,28 @@ func (s *evaluatorService) Evaluate(stream pb.Evaluator_EvaluateServer) error {
 	}).Debug("matches accepted by the evaluator")
 	return nil
 }
+
+// sendResults sends the ids of the accepted matches in chunks of up to
+// chunkSize, or one per response if chunkSize is zero.
+func sendResults(stream pb.Evaluator_EvaluateServer, results []string, chunkSize int) error {
+	if chunkSize == 0 {
+		for _, result := range results {
+			if err := stream.Send(&pb.EvaluateResponse{MatchId: result}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for len(results) > 0 {
+		n := chunkSize
+		if n > len(results) {
+			n = len(results)
+		}
+		if err := stream.Send(&pb.EvaluateResponse{MatchIds: results[:n]}); err != nil {
+			return err
+		}
+		results = results[n:]
+	}
+	return nil
+}
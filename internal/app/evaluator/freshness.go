@@ -0,0 +1,62 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evaluator
+
+import (
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// TicketValidExtensionKey is the Ticket extension set by the synchronizer on
+// proposals when synchronizer.verifyTicketsBeforeEvaluation is enabled.  It
+// holds a google.protobuf.BoolValue which is false if the Ticket was deleted or
+// assigned by the time the proposal was sent to the evaluator.
+const TicketValidExtensionKey = "openmatch.ticket_valid"
+
+// SetTicketValid records on the ticket whether it is still valid.
+func SetTicketValid(t *pb.Ticket, valid bool) error {
+	a, err := ptypes.MarshalAny(&wrappers.BoolValue{Value: valid})
+	if err != nil {
+		return err
+	}
+	if t.Extensions == nil {
+		t.Extensions = make(map[string]*any.Any)
+	}
+	t.Extensions[TicketValidExtensionKey] = a
+	return nil
+}
+
+// StaleTicketIDs returns the ids of the match's tickets which the synchronizer
+// found to be deleted or assigned.  Tickets which weren't verified are assumed
+// to be valid.
+func StaleTicketIDs(m *pb.Match) []string {
+	var ids []string
+	for _, t := range m.GetTickets() {
+		a, ok := t.GetExtensions()[TicketValidExtensionKey]
+		if !ok {
+			continue
+		}
+		valid := &wrappers.BoolValue{}
+		if err := ptypes.UnmarshalAny(a, valid); err != nil {
+			continue
+		}
+		if !valid.GetValue() {
+			ids = append(ids, t.GetId())
+		}
+	}
+	return ids
+}
@@ -0,0 +1,132 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package frontend
+
+import (
+	"context"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"go.opencensus.io/tag"
+	"google.golang.org/grpc/metadata"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/featuregate"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+const (
+	// clientMetadataExtensionKey holds the client metadata annotated onto
+	// tickets when frontend.clientMetadata.annotateTickets is set.
+	clientMetadataExtensionKey = "openmatch.client_metadata"
+
+	// maxClientMetadataLength bounds the length of metric labels taken from
+	// caller supplied metadata.
+	maxClientMetadataLength = 64
+	unknownClientMetadata   = "unknown"
+)
+
+var (
+	clientVersionKey  = tag.MustNewKey("client_version")
+	clientPlatformKey = tag.MustNewKey("client_platform")
+)
+
+// clientMetadata is the build version and platform of the game client creating
+// tickets, read from the gRPC metadata of the call.  Ticket metrics are
+// labeled with it, so that queue health can be broken down by client version,
+// eg. when a bad game patch floods the queue with malformed tickets.
+type clientMetadata struct {
+	version  string
+	platform string
+	// annotate adds the metadata to the extensions of created tickets.
+	annotate bool
+}
+
+// readClientMetadata reads the client metadata from the keys configured by
+// frontend.clientMetadata.versionKey and frontend.clientMetadata.platformKey.
+// HTTP callers set them with Grpc-Metadata- prefixed headers.
+func readClientMetadata(ctx context.Context, cfg config.View) clientMetadata {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return clientMetadata{
+		version:  clientMetadataValue(md, cfg, "frontend.clientMetadata.versionKey", "x-client-version"),
+		platform: clientMetadataValue(md, cfg, "frontend.clientMetadata.platformKey", "x-client-platform"),
+		annotate: featuregate.Enabled(cfg, featuregate.AnnotateClientMetadata),
+	}
+}
+
+func clientMetadataValue(md metadata.MD, cfg config.View, name string, defaultKey string) string {
+	key := defaultKey
+	if cfg.IsSet(name) {
+		key = cfg.GetString(name)
+	}
+	values := md.Get(key)
+	if len(values) == 0 {
+		return unknownClientMetadata
+	}
+	return sanitizeClientMetadata(values[0])
+}
+
+// sanitizeClientMetadata makes a caller supplied value a valid metric label,
+// which must be printable ASCII.
+func sanitizeClientMetadata(v string) string {
+	v = strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(v))
+	if len(v) > maxClientMetadataLength {
+		v = v[:maxClientMetadataLength]
+	}
+	if v == "" {
+		return unknownClientMetadata
+	}
+	return v
+}
+
+// withTags returns a context labeling the metrics recorded with it with the
+// client metadata.
+func (c clientMetadata) withTags(ctx context.Context) context.Context {
+	tagged, err := tag.New(ctx, tag.Upsert(clientVersionKey, c.version), tag.Upsert(clientPlatformKey, c.platform))
+	if err != nil {
+		logger.WithError(err).Debug("failed to label metrics with client metadata")
+		return ctx
+	}
+	return tagged
+}
+
+// annotateTicket adds the client metadata to the ticket's extensions as a
+// google.protobuf.Struct, if enabled.
+func (c clientMetadata) annotateTicket(ticket *pb.Ticket) error {
+	if !c.annotate {
+		return nil
+	}
+
+	a, err := ptypes.MarshalAny(&structpb.Struct{
+		Fields: map[string]*structpb.Value{
+			"version":  {Kind: &structpb.Value_StringValue{StringValue: c.version}},
+			"platform": {Kind: &structpb.Value_StringValue{StringValue: c.platform}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if ticket.Extensions == nil {
+		ticket.Extensions = make(map[string]*any.Any)
+	}
+	ticket.Extensions[clientMetadataExtensionKey] = a
+	return nil
+}
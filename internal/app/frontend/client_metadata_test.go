@@ -0,0 +1,63 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package frontend
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestReadClientMetadata(t *testing.T) {
+	assert := assert.New(t)
+	cfg := viper.New()
+	cfg.Set("frontend.clientMetadata.platformKey", "x-game-platform")
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"x-client-version", " 1.2.3\n",
+		"x-game-platform", strings.Repeat("p", 100),
+	))
+	c := readClientMetadata(ctx, cfg)
+	assert.Equal("1.2.3", c.version)
+	assert.Equal(strings.Repeat("p", maxClientMetadataLength), c.platform)
+	assert.False(c.annotate)
+
+	c = readClientMetadata(context.Background(), cfg)
+	assert.Equal(unknownClientMetadata, c.version)
+	assert.Equal(unknownClientMetadata, c.platform)
+
+	assert.Equal("caf__", sanitizeClientMetadata("café\x00"))
+}
+
+func TestAnnotateTicket(t *testing.T) {
+	assert := assert.New(t)
+	ticket := &pb.Ticket{}
+
+	assert.Nil(clientMetadata{version: "1.2.3", platform: "pc"}.annotateTicket(ticket))
+	assert.Empty(ticket.GetExtensions())
+
+	assert.Nil(clientMetadata{version: "1.2.3", platform: "pc", annotate: true}.annotateTicket(ticket))
+	s := &structpb.Struct{}
+	assert.Nil(ptypes.UnmarshalAny(ticket.GetExtensions()[clientMetadataExtensionKey], s))
+	assert.Equal("1.2.3", s.GetFields()["version"].GetStringValue())
+	assert.Equal("pc", s.GetFields()["platform"].GetStringValue())
+}
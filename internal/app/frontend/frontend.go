@@ -15,6 +15,8 @@
 package frontend
 
 import (
+	"time"
+
 	"google.golang.org/grpc"
 	"open-match.dev/open-match/internal/config"
 	"open-match.dev/open-match/internal/rpc"
@@ -24,12 +26,39 @@ import (
 
 // BindService creates the frontend service and binds it to the serving harness.
 func BindService(p *rpc.ServerParams, cfg config.View) error {
+	validator, err := newTicketValidator(cfg)
+	if err != nil {
+		return err
+	}
 	service := &frontendService{
-		cfg:   cfg,
-		store: statestore.New(cfg),
+		cfg:       cfg,
+		store:     statestore.New(cfg),
+		validator: validator,
 	}
 
 	p.AddHealthCheckFunc(service.store.HealthCheck)
+	if !cfg.IsSet("frontend.loadShedding.enabled") || cfg.GetBool("frontend.loadShedding.enabled") {
+		interval := time.Second
+		if cfg.IsSet("frontend.loadShedding.healthCheckInterval") {
+			interval = cfg.GetDuration("frontend.loadShedding.healthCheckInterval")
+		}
+		service.shedder = newAssignmentShedder(cfg)
+		service.shedder.start(service.store.HealthCheck, interval)
+	}
+	if timeout := heartbeatTimeout(cfg); timeout > 0 {
+		interval := timeout / 2
+		if cfg.IsSet("frontend.heartbeat.reapInterval") {
+			interval = cfg.GetDuration("frontend.heartbeat.reapInterval")
+		}
+		startReaper(service.store, timeout, interval)
+	}
+	authenticator, err := rpc.AuthenticatorFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+	if authenticator != nil {
+		p.RequireAuthentication(authenticator, "openmatch.FrontendService")
+	}
 	p.AddHandleFunc(func(s *grpc.Server) {
 		pb.RegisterFrontendServiceServer(s, service)
 	}, pb.RegisterFrontendServiceHandlerFromEndpoint)
@@ -16,14 +16,22 @@ package frontend
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/golang/protobuf/ptypes/duration"
 	"github.com/rs/xid"
 	"github.com/sirupsen/logrus"
 	"go.opencensus.io/trace"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/filter"
 	"open-match.dev/open-match/internal/statestore"
 	"open-match.dev/open-match/internal/telemetry"
 	"open-match.dev/open-match/pkg/pb"
@@ -32,43 +40,125 @@ import (
 // frontendService implements the Frontend service that is used to create
 // Tickets and add, remove them from the pool for matchmaking.
 type frontendService struct {
-	cfg   config.View
-	store statestore.Service
+	cfg       config.View
+	store     statestore.Service
+	shedder   *assignmentShedder
+	validator *ticketValidator
 }
 
+// maxIdempotencyKeyLength bounds the idempotency keys of CreateTicket, which
+// are stored as part of a Redis key.
+const maxIdempotencyKeyLength = 256
+
 var (
 	logger = logrus.WithFields(logrus.Fields{
 		"app":       "openmatch",
 		"component": "app.frontend",
 	})
-	mTicketsCreated             = telemetry.Counter("frontend/tickets_created", "tickets created")
+	mTicketsCreated             = telemetry.Counter("frontend/tickets_created", "tickets created", clientVersionKey, clientPlatformKey)
+	mTicketCreationsFailed      = telemetry.Counter("frontend/ticket_creations_failed", "CreateTicket and CreateTickets calls which failed", clientVersionKey, clientPlatformKey)
 	mTicketsDeleted             = telemetry.Counter("frontend/tickets_deleted", "tickets deleted")
+	mTicketsUpdated             = telemetry.Counter("frontend/tickets_updated", "tickets updated")
 	mTicketsRetrieved           = telemetry.Counter("frontend/tickets_retrieved", "tickets retrieved")
+	mTicketStatesRetrieved      = telemetry.Counter("frontend/ticket_states_retrieved", "ticket states retrieved")
+	mTicketStatusesRetrieved    = telemetry.Counter("frontend/ticket_statuses_retrieved", "ticket queue statuses retrieved")
 	mTicketAssignmentsRetrieved = telemetry.Counter("frontend/tickets_assignments_retrieved", "ticket assignments retrieved")
+	mTicketsMatchingNoPools     = telemetry.Counter("frontend/tickets_matching_no_pools", "tickets created which fall into no pool of a recently used profile", clientVersionKey, clientPlatformKey)
+	mAssignmentWaitsTimedOut    = telemetry.Counter("frontend/assignment_waits_timed_out", "WaitForAssignment calls returned without a change")
+	mDuplicateCreations         = telemetry.Counter("frontend/duplicate_ticket_creations", "CreateTicket calls returning the ticket created earlier with the same idempotency key")
+
+	errAssignmentChanged = errors.New("assignment changed")
 )
 
 // CreateTicket assigns an unique TicketId to the input Ticket and record it in state storage.
 // A ticket is considered as ready for matchmaking once it is created.
 //   - If a TicketId exists in a Ticket request, an auto-generated TicketId will override this field.
 //   - If SearchFields exist in a Ticket, CreateTicket will also index these fields such that one can query the ticket with query.QueryTickets function.
+//   - Retries with the same idempotency key within frontend.idempotencyWindow return the Ticket created first.
 func (s *frontendService) CreateTicket(ctx context.Context, req *pb.CreateTicketRequest) (*pb.CreateTicketResponse, error) {
 	// Perform input validation.
 	if req.GetTicket() == nil {
 		return nil, status.Errorf(codes.InvalidArgument, ".ticket is required")
 	}
+	if len(req.GetIdempotencyKey()) > maxIdempotencyKeyLength {
+		return nil, status.Errorf(codes.InvalidArgument, ".idempotency_key must be at most %d bytes", maxIdempotencyKeyLength)
+	}
 
-	return doCreateTicket(ctx, req, s.store)
+	client := readClientMetadata(ctx, s.cfg)
+	ctx = client.withTags(ctx)
+	if err := s.validator.validate(ctx, req.GetTicket()); err != nil {
+		return nil, err
+	}
+	resp, err := doCreateTicket(ctx, req, s.store, client, idempotencyWindow(s.cfg))
+	if err == nil {
+		err = s.recordHeartbeats(ctx, resp.GetTicket())
+	}
+	if err != nil {
+		telemetry.RecordUnitMeasurement(ctx, mTicketCreationsFailed)
+		return nil, err
+	}
+	return resp, nil
 }
 
-func doCreateTicket(ctx context.Context, req *pb.CreateTicketRequest, store statestore.Service) (*pb.CreateTicketResponse, error) {
+func doCreateTicket(ctx context.Context, req *pb.CreateTicketRequest, store statestore.Service, client clientMetadata, idempotencyWindow time.Duration) (*pb.CreateTicketResponse, error) {
+	expiration, err := ticketExpiration(req.GetExpiration())
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate a ticket id and create a Ticket in state storage
 	ticket, ok := proto.Clone(req.Ticket).(*pb.Ticket)
 	if !ok {
 		return nil, status.Error(codes.Internal, "failed to clone input ticket proto")
 	}
-
+	if err := client.annotateTicket(ticket); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to annotate the ticket with client metadata: %v", err)
+	}
 	ticket.Id = xid.New().String()
-	err := store.CreateTicket(ctx, ticket)
+	ticket.AssignmentFailures = 0
+	ticket.CreateTime = ptypes.TimestampNow()
+
+	// Claim the idempotency key before creating anything, so that a retry
+	// racing the original request doesn't create a second ticket.
+	created := false
+	if key := req.GetIdempotencyKey(); key != "" && idempotencyWindow > 0 {
+		claimedID, err := store.ClaimIdempotencyKey(ctx, key, ticket.Id, idempotencyWindow)
+		if err != nil {
+			logger.WithFields(logrus.Fields{
+				"error": err.Error(),
+				"key":   key,
+			}).Error("failed to claim the idempotency key")
+			return nil, err
+		}
+		if claimedID != "" {
+			return replayCreateTicket(ctx, req, claimedID, store)
+		}
+		defer func() {
+			if created {
+				return
+			}
+			// Let a retry create the ticket instead.
+			if err := store.ReleaseIdempotencyKey(context.Background(), key, ticket.Id); err != nil {
+				logger.WithFields(logrus.Fields{
+					"error": err.Error(),
+					"key":   key,
+				}).Error("failed to release the idempotency key of a ticket which wasn't created")
+			}
+		}()
+	}
+
+	// Look up matched pools first, so that a failure doesn't leave behind a
+	// ticket the caller doesn't know about.
+	var matchedPools []*pb.MatchedPool
+	if req.GetIncludeMatchedPools() {
+		var err error
+		matchedPools, err = findMatchedPools(ctx, ticket, store)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = store.CreateTicketsWithExpiration(ctx, []*pb.Ticket{ticket}, expiration)
 	if err != nil {
 		logger.WithFields(logrus.Fields{
 			"error":  err.Error(),
@@ -86,8 +176,241 @@ func doCreateTicket(ctx context.Context, req *pb.CreateTicketRequest, store stat
 		return nil, err
 	}
 
+	created = true
 	telemetry.RecordUnitMeasurement(ctx, mTicketsCreated)
-	return &pb.CreateTicketResponse{Ticket: ticket}, nil
+	return &pb.CreateTicketResponse{Ticket: ticket, MatchedPools: matchedPools}, nil
+}
+
+// replayCreateTicket returns the ticket created earlier with the idempotency
+// key of the request, rather than creating it again.
+func replayCreateTicket(ctx context.Context, req *pb.CreateTicketRequest, id string, store statestore.Service) (*pb.CreateTicketResponse, error) {
+	ticket, err := store.GetTicket(ctx, id)
+	if status.Code(err) == codes.NotFound {
+		return nil, status.Errorf(codes.Aborted, "Ticket id:%s created with idempotency key %q is either still being created or already deleted", id, req.GetIdempotencyKey())
+	}
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"error": err.Error(),
+			"id":    id,
+		}).Error("failed to get the ticket created with the idempotency key")
+		return nil, err
+	}
+
+	var matchedPools []*pb.MatchedPool
+	if req.GetIncludeMatchedPools() {
+		matchedPools, err = findMatchedPools(ctx, ticket, store)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	telemetry.RecordUnitMeasurement(ctx, mDuplicateCreations)
+	return &pb.CreateTicketResponse{Ticket: ticket, MatchedPools: matchedPools}, nil
+}
+
+// idempotencyWindow returns frontend.idempotencyWindow, how long CreateTicket
+// returns the ticket created earlier with the same idempotency key.
+func idempotencyWindow(cfg config.View) time.Duration {
+	if !cfg.IsSet("frontend.idempotencyWindow") {
+		return 5 * time.Minute
+	}
+	return cfg.GetDuration("frontend.idempotencyWindow")
+}
+
+// CreateTickets assigns unique TicketIds to the input Tickets and records them
+// in state storage, as with CreateTicket, using a single round trip to create
+// and another to index all of them.
+func (s *frontendService) CreateTickets(ctx context.Context, req *pb.CreateTicketsRequest) (*pb.CreateTicketsResponse, error) {
+	if len(req.GetTickets()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, ".tickets is required")
+	}
+	for _, ticket := range req.GetTickets() {
+		if ticket == nil {
+			return nil, status.Errorf(codes.InvalidArgument, ".tickets must not contain null tickets")
+		}
+	}
+
+	client := readClientMetadata(ctx, s.cfg)
+	ctx = client.withTags(ctx)
+	for _, ticket := range req.GetTickets() {
+		if err := s.validator.validate(ctx, ticket); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := doCreateTickets(ctx, req, s.store, client)
+	if err == nil {
+		err = s.recordHeartbeats(ctx, resp.GetTickets()...)
+	}
+	if err != nil {
+		telemetry.RecordUnitMeasurement(ctx, mTicketCreationsFailed)
+		return nil, err
+	}
+	return resp, nil
+}
+
+func doCreateTickets(ctx context.Context, req *pb.CreateTicketsRequest, store statestore.Service, client clientMetadata) (*pb.CreateTicketsResponse, error) {
+	expiration, err := ticketExpiration(req.GetExpiration())
+	if err != nil {
+		return nil, err
+	}
+
+	tickets := make([]*pb.Ticket, 0, len(req.GetTickets()))
+	for _, t := range req.GetTickets() {
+		ticket, ok := proto.Clone(t).(*pb.Ticket)
+		if !ok {
+			return nil, status.Error(codes.Internal, "failed to clone input ticket proto")
+		}
+		if err := client.annotateTicket(ticket); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to annotate the ticket with client metadata: %v", err)
+		}
+		ticket.Id = xid.New().String()
+		ticket.AssignmentFailures = 0
+		ticket.CreateTime = ptypes.TimestampNow()
+		tickets = append(tickets, ticket)
+	}
+
+	err = store.CreateTicketsWithExpiration(ctx, tickets, expiration)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"error": err.Error(),
+			"count": len(tickets),
+		}).Error("failed to create the tickets")
+		return nil, err
+	}
+
+	err = store.IndexTickets(ctx, tickets)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"error": err.Error(),
+			"count": len(tickets),
+		}).Error("failed to index the tickets")
+		return nil, err
+	}
+
+	telemetry.RecordNUnitMeasurement(ctx, mTicketsCreated, int64(len(tickets)))
+	return &pb.CreateTicketsResponse{Tickets: tickets}, nil
+}
+
+// ticketExpiration converts the expiration requested for new tickets, where
+// zero means the configured default.
+func ticketExpiration(d *duration.Duration) (time.Duration, error) {
+	if d == nil {
+		return 0, nil
+	}
+	expiration, err := ptypes.Duration(d)
+	if err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid .expiration: %v", err)
+	}
+	if expiration < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, ".expiration must not be negative, got %v", expiration)
+	}
+	return expiration, nil
+}
+
+// findMatchedPools returns the pools of recently used profiles which the ticket
+// falls into.
+func findMatchedPools(ctx context.Context, ticket *pb.Ticket, store statestore.Service) ([]*pb.MatchedPool, error) {
+	profiles, err := store.GetProfiles(ctx)
+	if err != nil {
+		logger.WithError(err).Error("failed to get profiles to match the ticket against")
+		return nil, err
+	}
+
+	matched := []*pb.MatchedPool{}
+	for _, profile := range profiles {
+		for _, pool := range profile.GetPools() {
+			if filter.InPool(ticket, pool) {
+				matched = append(matched, &pb.MatchedPool{
+					Profile: profile.GetName(),
+					Pool:    pool.GetName(),
+				})
+			}
+		}
+	}
+	if len(matched) == 0 {
+		telemetry.RecordUnitMeasurement(ctx, mTicketsMatchingNoPools)
+	}
+	return matched, nil
+}
+
+// UpdateTicket replaces the SearchFields, extensions, player ids and avoid ids of a waiting Ticket, moving it to the
+// indices of its new SearchFields atomically.
+//   - Tickets which are already assigned fail with FailedPrecondition.
+//   - A reservation made with BackendService.ReserveTickets is kept.
+//   - The update counts as a heartbeat.
+func (s *frontendService) UpdateTicket(ctx context.Context, req *pb.UpdateTicketRequest) (*pb.UpdateTicketResponse, error) {
+	if req.GetTicket() == nil {
+		return nil, status.Error(codes.InvalidArgument, ".ticket is required")
+	}
+	if req.GetTicket().GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, ".ticket.id is required")
+	}
+	if req.GetTicket().GetAssignment() != nil {
+		return nil, status.Error(codes.InvalidArgument, ".ticket.assignment must not be set")
+	}
+	if err := s.validator.validate(ctx, req.GetTicket()); err != nil {
+		return nil, err
+	}
+	resp, err := doUpdateTicket(ctx, req.GetTicket(), s.store)
+	if err != nil {
+		return nil, err
+	}
+	// An update shows the client is still alive.
+	if err = s.recordHeartbeats(ctx, resp.GetTicket()); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func doUpdateTicket(ctx context.Context, update *pb.Ticket, store statestore.Service) (*pb.UpdateTicketResponse, error) {
+	next, ok := proto.Clone(update).(*pb.Ticket)
+	if !ok {
+		return nil, status.Error(codes.Internal, "failed to clone input ticket proto")
+	}
+
+	var updated *pb.Ticket
+	var assigned bool
+	_, notFound, err := store.RewriteTicketsByID(ctx, []string{update.GetId()}, func(ticket *pb.Ticket) bool {
+		assigned = ticket.GetAssignment() != nil
+		if assigned {
+			return false
+		}
+
+		// Keep what Open Match itself added to the ticket.
+		eventID, start, reserved := pb.ReservedEvent(ticket)
+		clientMetadata, annotated := ticket.GetExtensions()[clientMetadataExtensionKey]
+		ticket.SearchFields = next.GetSearchFields()
+		ticket.Extensions = next.GetExtensions()
+		ticket.PlayerIds = next.GetPlayerIds()
+		ticket.AvoidIds = next.GetAvoidIds()
+		if annotated {
+			if ticket.Extensions == nil {
+				ticket.Extensions = make(map[string]*any.Any)
+			}
+			ticket.Extensions[clientMetadataExtensionKey] = clientMetadata
+		}
+		if reserved {
+			pb.ReserveForEvent(ticket, eventID, start)
+		}
+
+		updated = ticket
+		return true
+	})
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"error": err.Error(),
+			"id":    update.GetId(),
+		}).Error("failed to update the ticket")
+		return nil, err
+	}
+	if len(notFound) > 0 {
+		return nil, status.Errorf(codes.NotFound, "Ticket id:%s not found", update.GetId())
+	}
+	if assigned {
+		return nil, status.Errorf(codes.FailedPrecondition, "Ticket id:%s is already assigned", update.GetId())
+	}
+	telemetry.RecordUnitMeasurement(ctx, mTicketsUpdated)
+	return &pb.UpdateTicketResponse{Ticket: updated}, nil
 }
 
 // DeleteTicket immediately stops Open Match from using the Ticket for matchmaking and removes the Ticket from state storage.
@@ -104,13 +427,31 @@ func (s *frontendService) DeleteTicket(ctx context.Context, req *pb.DeleteTicket
 }
 
 func doDeleteTicket(ctx context.Context, id string, store statestore.Service) error {
-	// Deindex this Ticket to remove it from matchmaking pool.
-	err := store.DeindexTicket(ctx, id)
+	return doDeleteTickets(ctx, []string{id}, store)
+}
+
+// DeleteTickets deletes multiple Tickets at once, as with DeleteTicket.
+//   - The Tickets are deindexed in a single transaction, and deleted lazily in a single round trip.
+func (s *frontendService) DeleteTickets(ctx context.Context, req *pb.DeleteTicketsRequest) (*pb.DeleteTicketsResponse, error) {
+	if len(req.GetTicketIds()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, ".ticket_ids is required")
+	}
+	err := doDeleteTickets(ctx, req.GetTicketIds(), s.store)
+	if err != nil {
+		return nil, err
+	}
+	telemetry.RecordNUnitMeasurement(ctx, mTicketsDeleted, int64(len(req.GetTicketIds())))
+	return &pb.DeleteTicketsResponse{}, nil
+}
+
+func doDeleteTickets(ctx context.Context, ids []string, store statestore.Service) error {
+	// Deindex the Tickets to remove them from matchmaking pool.
+	err := store.DeindexTickets(ctx, ids)
 	if err != nil {
 		logger.WithFields(logrus.Fields{
 			"error": err.Error(),
-			"id":    id,
-		}).Error("failed to deindex the ticket")
+			"ids":   ids,
+		}).Error("failed to deindex the tickets")
 		return err
 	}
 
@@ -119,19 +460,19 @@ func doDeleteTicket(ctx context.Context, id string, store statestore.Service) er
 	go func() {
 		ctx, span := trace.StartSpan(context.Background(), "open-match/frontend.DeleteTicketLazy")
 		defer span.End()
-		err := store.DeleteTicket(ctx, id)
+		err := store.DeleteTickets(ctx, ids)
 		if err != nil {
 			logger.WithFields(logrus.Fields{
 				"error": err.Error(),
-				"id":    id,
-			}).Error("failed to delete the ticket")
+				"ids":   ids,
+			}).Error("failed to delete the tickets")
 		}
-		err = store.DeleteTicketsFromIgnoreList(ctx, []string{id})
+		err = store.DeleteTicketsFromIgnoreList(ctx, ids)
 		if err != nil {
 			logger.WithFields(logrus.Fields{
 				"error": err.Error(),
-				"id":    id,
-			}).Error("failed to delete the ticket from ignorelist")
+				"ids":   ids,
+			}).Error("failed to delete the tickets from ignorelist")
 		}
 		// TODO: If other redis queues are implemented or we have custom index fields
 		// created by Open Match, those need to be cleaned up here.
@@ -158,8 +499,55 @@ func doGetTickets(ctx context.Context, id string, store statestore.Service) (*pb
 	return ticket, nil
 }
 
+// GetTicketState gets the lifecycle state of the Ticket associated with the specified TicketId.
+func (s *frontendService) GetTicketState(ctx context.Context, req *pb.GetTicketStateRequest) (*pb.GetTicketStateResponse, error) {
+	telemetry.RecordUnitMeasurement(ctx, mTicketStatesRetrieved)
+	return doGetTicketState(ctx, req.GetTicketId(), s.store)
+}
+
+func doGetTicketState(ctx context.Context, id string, store statestore.Service) (*pb.GetTicketStateResponse, error) {
+	state, err := store.GetTicketState(ctx, id)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"error": err.Error(),
+			"id":    id,
+		}).Error("failed to get the ticket state")
+		return nil, err
+	}
+
+	return &pb.GetTicketStateResponse{State: state}, nil
+}
+
+// GetTicketStatus gets queue diagnostics of the Ticket associated with the specified TicketId.
+func (s *frontendService) GetTicketStatus(ctx context.Context, req *pb.GetTicketStatusRequest) (*pb.GetTicketStatusResponse, error) {
+	telemetry.RecordUnitMeasurement(ctx, mTicketStatusesRetrieved)
+	return doGetTicketStatus(ctx, req.GetTicketId(), s.store, time.Now())
+}
+
+func doGetTicketStatus(ctx context.Context, id string, store statestore.Service, now time.Time) (*pb.GetTicketStatusResponse, error) {
+	ticketStatus, err := store.GetTicketStatus(ctx, id)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"error": err.Error(),
+			"id":    id,
+		}).Error("failed to get the ticket status")
+		return nil, err
+	}
+
+	resp := &pb.GetTicketStatusResponse{
+		Indexed:  ticketStatus.Indexed,
+		Proposed: ticketStatus.Proposed,
+		Assigned: ticketStatus.Assigned,
+	}
+	// Generated ticket ids embed their creation time.
+	if generated, err := xid.FromString(id); err == nil {
+		resp.Age = ptypes.DurationProto(now.Sub(generated.Time()))
+	}
+	return resp, nil
+}
+
 // GetAssignments stream back Assignment of the specified TicketId if it is updated.
-//   - If the Assignment is not updated, GetAssignment will retry using the configured backoff strategy.
+//   - If the Assignment is not updated, GetAssignment waits for an update to be published.
 func (s *frontendService) GetAssignments(req *pb.GetAssignmentsRequest, stream pb.FrontendService_GetAssignmentsServer) error {
 	ctx := stream.Context()
 	for {
@@ -171,7 +559,9 @@ func (s *frontendService) GetAssignments(req *pb.GetAssignmentsRequest, stream p
 				telemetry.RecordUnitMeasurement(ctx, mTicketAssignmentsRetrieved)
 				return stream.Send(&pb.GetAssignmentsResponse{Assignment: assignment})
 			}
-			return doGetAssignments(ctx, req.GetTicketId(), sender, s.store)
+			return s.shedder.run(ctx, func(ctx context.Context) error {
+				return doGetAssignments(ctx, req.GetTicketId(), sender, s.store)
+			})
 		}
 	}
 }
@@ -197,3 +587,82 @@ func doGetAssignments(ctx context.Context, id string, sender func(*pb.Assignment
 
 	return store.GetAssignments(ctx, id, callback)
 }
+
+// WaitForAssignment waits for the Assignment of the specified TicketId to change, then returns it.
+// It is a long-polling alternative to GetAssignments for clients which can't hold a stream open.
+//   - If the Assignment doesn't change within the timeout, the current Assignment is returned.
+func (s *frontendService) WaitForAssignment(ctx context.Context, req *pb.WaitForAssignmentRequest) (*pb.WaitForAssignmentResponse, error) {
+	if req.GetTicketId() == "" {
+		return nil, status.Error(codes.InvalidArgument, ".ticket_id is required")
+	}
+	if req.GetTimeoutSeconds() < 0 {
+		return nil, status.Error(codes.InvalidArgument, ".timeout_seconds must not be negative")
+	}
+
+	timeout := getAssignmentWaitTimeout(s.cfg)
+	if requested := time.Duration(req.GetTimeoutSeconds()) * time.Second; requested > 0 && requested < timeout {
+		timeout = requested
+	}
+	return doWaitForAssignment(ctx, req.GetTicketId(), req.GetAssignmentFingerprint(), timeout, s.store)
+}
+
+func doWaitForAssignment(ctx context.Context, id string, fingerprint string, timeout time.Duration, store statestore.Service) (*pb.WaitForAssignmentResponse, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var resp *pb.WaitForAssignmentResponse
+	callback := func(assignment *pb.Assignment) error {
+		current, err := assignmentFingerprint(assignment)
+		if err != nil {
+			return err
+		}
+		resp = &pb.WaitForAssignmentResponse{Assignment: assignment, AssignmentFingerprint: current}
+		if current != fingerprint {
+			return errAssignmentChanged
+		}
+		return nil
+	}
+
+	err := store.GetAssignments(waitCtx, id, callback)
+	switch {
+	case err == errAssignmentChanged:
+		telemetry.RecordUnitMeasurement(ctx, mTicketAssignmentsRetrieved)
+		return resp, nil
+	case resp != nil && waitCtx.Err() != nil && ctx.Err() == nil:
+		// Timed out waiting, the last seen state is still current.
+		telemetry.RecordUnitMeasurement(ctx, mAssignmentWaitsTimedOut)
+		return resp, nil
+	case err != nil:
+		return nil, err
+	}
+	return resp, nil
+}
+
+// assignmentFingerprint identifies the content of an assignment, "" if it's
+// unset.
+func assignmentFingerprint(assignment *pb.Assignment) (string, error) {
+	if assignment == nil {
+		return "", nil
+	}
+
+	buf := proto.NewBuffer(nil)
+	buf.SetDeterministic(true)
+	if err := buf.Marshal(assignment); err != nil {
+		return "", status.Errorf(codes.Internal, "failed to marshal the assignment: %v", err)
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+func getAssignmentWaitTimeout(cfg config.View) time.Duration {
+	const (
+		name = "frontend.assignmentWaitTimeout"
+		// Default and maximum time WaitForAssignment waits for a change.
+		defaultTimeout = 30 * time.Second
+	)
+
+	if !cfg.IsSet(name) {
+		return defaultTimeout
+	}
+	return cfg.GetDuration(name)
+}
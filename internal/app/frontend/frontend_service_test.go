@@ -22,6 +22,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/duration"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc/codes"
@@ -76,7 +78,7 @@ func TestDoCreateTickets(t *testing.T) {
 			ctx, cancel := context.WithCancel(utilTesting.NewContext(t))
 			test.preAction(cancel)
 
-			res, err := doCreateTicket(ctx, &pb.CreateTicketRequest{Ticket: test.ticket}, store)
+			res, err := doCreateTicket(ctx, &pb.CreateTicketRequest{Ticket: test.ticket}, store, clientMetadata{}, 0)
 			assert.Equal(t, test.wantCode, status.Convert(err).Code())
 			if err == nil {
 				matched, err := regexp.MatchString(`[0-9a-v]{20}`, res.GetTicket().GetId())
@@ -88,6 +90,133 @@ func TestDoCreateTickets(t *testing.T) {
 	}
 }
 
+func TestDoCreateTicketsBatch(t *testing.T) {
+	assert := assert.New(t)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, viper.New())
+	defer closer()
+	ctx := utilTesting.NewContext(t)
+
+	req := &pb.CreateTicketsRequest{
+		Tickets: []*pb.Ticket{
+			{SearchFields: &pb.SearchFields{Tags: []string{"first"}}},
+			{SearchFields: &pb.SearchFields{Tags: []string{"second"}}},
+		},
+	}
+	res, err := doCreateTickets(ctx, req, store, clientMetadata{})
+	assert.Nil(err)
+	assert.Len(res.GetTickets(), 2)
+	assert.NotEqual(res.GetTickets()[0].GetId(), res.GetTickets()[1].GetId())
+	// The request's tickets aren't modified.
+	assert.Empty(req.GetTickets()[0].GetId())
+
+	ids, err := store.GetIndexedIDSet(ctx)
+	assert.Nil(err)
+	for i, ticket := range res.GetTickets() {
+		assert.Equal(req.GetTickets()[i].GetSearchFields().GetTags(), ticket.GetSearchFields().GetTags())
+		assert.Contains(ids, ticket.GetId())
+	}
+}
+
+func TestDoCreateTicketExpiration(t *testing.T) {
+	assert := assert.New(t)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, viper.New())
+	defer closer()
+	ctx := utilTesting.NewContext(t)
+
+	_, err := doCreateTicket(ctx, &pb.CreateTicketRequest{Ticket: &pb.Ticket{}, Expiration: ptypes.DurationProto(-time.Second)}, store, clientMetadata{}, 0)
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+	_, err = doCreateTickets(ctx, &pb.CreateTicketsRequest{Tickets: []*pb.Ticket{{}}, Expiration: &duration.Duration{Nanos: -1e9}}, store, clientMetadata{})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+	ids, err := store.GetIndexedIDSet(ctx)
+	assert.Nil(err)
+	assert.Empty(ids)
+
+	res, err := doCreateTicket(ctx, &pb.CreateTicketRequest{Ticket: &pb.Ticket{}, Expiration: ptypes.DurationProto(time.Hour)}, store, clientMetadata{}, 0)
+	assert.Nil(err)
+	_, err = store.GetTicket(ctx, res.GetTicket().GetId())
+	assert.Nil(err)
+}
+
+func TestDoCreateTicketIdempotencyKey(t *testing.T) {
+	assert := assert.New(t)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, viper.New())
+	defer closer()
+	ctx := utilTesting.NewContext(t)
+
+	req := &pb.CreateTicketRequest{Ticket: &pb.Ticket{SearchFields: &pb.SearchFields{Tags: []string{"solo"}}}, IdempotencyKey: "retry"}
+	first, err := doCreateTicket(ctx, req, store, clientMetadata{}, time.Minute)
+	assert.Nil(err)
+	retried, err := doCreateTicket(ctx, req, store, clientMetadata{}, time.Minute)
+	assert.Nil(err)
+	assert.Equal(first.GetTicket().GetId(), retried.GetTicket().GetId())
+	ids, err := store.GetIndexedIDSet(ctx)
+	assert.Nil(err)
+	assert.Len(ids, 1)
+
+	// Other keys, or no key at all, create other tickets.
+	other, err := doCreateTicket(ctx, &pb.CreateTicketRequest{Ticket: &pb.Ticket{}, IdempotencyKey: "other"}, store, clientMetadata{}, time.Minute)
+	assert.Nil(err)
+	assert.NotEqual(first.GetTicket().GetId(), other.GetTicket().GetId())
+	unkeyed, err := doCreateTicket(ctx, &pb.CreateTicketRequest{Ticket: &pb.Ticket{}}, store, clientMetadata{}, time.Minute)
+	assert.Nil(err)
+	assert.NotEqual(first.GetTicket().GetId(), unkeyed.GetTicket().GetId())
+
+	// Retrying after the ticket was deleted doesn't resurrect it.
+	assert.Nil(store.DeleteTicket(ctx, first.GetTicket().GetId()))
+	_, err = doCreateTicket(ctx, req, store, clientMetadata{}, time.Minute)
+	assert.Equal(codes.Aborted, status.Code(err))
+
+	// A failed creation releases the key for the retry.
+	faulty := statestore.NewFaultInjector(store, map[string]statestore.Fault{"CreateTicketsWithExpiration": {ErrorRate: 1}})
+	failed := &pb.CreateTicketRequest{Ticket: &pb.Ticket{}, IdempotencyKey: "failed"}
+	_, err = doCreateTicket(ctx, failed, faulty, clientMetadata{}, time.Minute)
+	assert.Equal(codes.Unavailable, status.Code(err))
+	_, err = doCreateTicket(ctx, failed, store, clientMetadata{}, time.Minute)
+	assert.Nil(err)
+}
+
+func TestDoCreateTicketMatchedPools(t *testing.T) {
+	assert := assert.New(t)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, viper.New())
+	defer closer()
+	ctx := utilTesting.NewContext(t)
+
+	assert.Nil(store.RecordProfile(ctx, &pb.MatchProfile{
+		Name: "ranked",
+		Pools: []*pb.Pool{
+			{
+				Name:               "low",
+				DoubleRangeFilters: []*pb.DoubleRangeFilter{{DoubleArg: "mmr", Min: 0, Max: 100}},
+			},
+			{
+				Name:               "high",
+				DoubleRangeFilters: []*pb.DoubleRangeFilter{{DoubleArg: "mmr", Min: 100, Max: 200}},
+			},
+		},
+	}))
+	assert.Nil(store.RecordProfile(ctx, &pb.MatchProfile{
+		Name:  "casual",
+		Pools: []*pb.Pool{{Name: "everyone"}},
+	}))
+
+	ticket := &pb.Ticket{
+		SearchFields: &pb.SearchFields{
+			DoubleArgs: map[string]float64{"mmr": 50},
+		},
+	}
+
+	res, err := doCreateTicket(ctx, &pb.CreateTicketRequest{Ticket: ticket}, store, clientMetadata{}, 0)
+	assert.Nil(err)
+	assert.Empty(res.GetMatchedPools())
+
+	res, err = doCreateTicket(ctx, &pb.CreateTicketRequest{Ticket: ticket, IncludeMatchedPools: true}, store, clientMetadata{}, 0)
+	assert.Nil(err)
+	assert.ElementsMatch([]*pb.MatchedPool{
+		{Profile: "ranked", Pool: "low"},
+		{Profile: "casual", Pool: "everyone"},
+	}, res.GetMatchedPools())
+}
+
 func TestDoGetAssignments(t *testing.T) {
 	testTicket := &pb.Ticket{
 		Id: "test-id",
@@ -123,7 +252,8 @@ func TestDoGetAssignments(t *testing.T) {
 				go func(wg *sync.WaitGroup) {
 					for i := 0; i < len(wantAssignments); i++ {
 						time.Sleep(50 * time.Millisecond)
-						assert.Nil(t, store.UpdateAssignments(ctx, []string{testTicket.GetId()}, wantAssignments[i]))
+						_, _, err := store.UpdateAssignments(ctx, []string{testTicket.GetId()}, wantAssignments[i])
+						assert.Nil(t, err)
 						wg.Done()
 					}
 				}(wg)
@@ -157,6 +287,52 @@ func TestDoGetAssignments(t *testing.T) {
 	}
 }
 
+func TestDoWaitForAssignment(t *testing.T) {
+	assert := assert.New(t)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, viper.New())
+	defer closer()
+	ctx := utilTesting.NewContext(t)
+
+	_, err := doWaitForAssignment(ctx, "missing", "", time.Second, store)
+	assert.Equal(codes.NotFound, status.Code(err))
+
+	ticket := &pb.Ticket{Id: "test-id"}
+	assert.Nil(store.CreateTicket(ctx, ticket))
+
+	// Nothing changes, the unassigned state is returned after the timeout.
+	resp, err := doWaitForAssignment(ctx, ticket.GetId(), "", 200*time.Millisecond, store)
+	assert.Nil(err)
+	assert.Nil(resp.GetAssignment())
+	assert.Equal("", resp.GetAssignmentFingerprint())
+
+	// The call returns once the ticket is assigned.
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_, _, err := store.UpdateAssignments(ctx, []string{ticket.GetId()}, &pb.Assignment{Connection: "1"})
+		assert.Nil(err)
+	}()
+	resp, err = doWaitForAssignment(ctx, ticket.GetId(), "", time.Minute, store)
+	assert.Nil(err)
+	assert.Equal("1", resp.GetAssignment().GetConnection())
+	assert.NotEqual("", resp.GetAssignmentFingerprint())
+
+	// Passing back the fingerprint waits for a further change.
+	fingerprint := resp.GetAssignmentFingerprint()
+	resp, err = doWaitForAssignment(ctx, ticket.GetId(), fingerprint, 200*time.Millisecond, store)
+	assert.Nil(err)
+	assert.Equal(fingerprint, resp.GetAssignmentFingerprint())
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_, _, err := store.UpdateAssignments(ctx, []string{ticket.GetId()}, &pb.Assignment{Connection: "2"})
+		assert.Nil(err)
+	}()
+	resp, err = doWaitForAssignment(ctx, ticket.GetId(), fingerprint, time.Minute, store)
+	assert.Nil(err)
+	assert.Equal("2", resp.GetAssignment().GetConnection())
+	assert.NotEqual(fingerprint, resp.GetAssignmentFingerprint())
+}
+
 func TestDoDeleteTicket(t *testing.T) {
 	fakeTicket := &pb.Ticket{
 		Id: "1",
@@ -208,6 +384,30 @@ func TestDoDeleteTicket(t *testing.T) {
 	}
 }
 
+func TestDoDeleteTickets(t *testing.T) {
+	assert := assert.New(t)
+	ctx := utilTesting.NewContext(t)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, viper.New())
+	defer closer()
+
+	tickets := []*pb.Ticket{{Id: "1"}, {Id: "2"}, {Id: "3"}}
+	assert.Nil(store.CreateTickets(ctx, tickets))
+	assert.Nil(store.IndexTickets(ctx, tickets))
+
+	assert.Nil(doDeleteTickets(ctx, []string{"1", "2", "4"}, store))
+
+	ids, err := store.GetIndexedIDSet(ctx)
+	assert.Nil(err)
+	assert.Equal(map[string]struct{}{"3": {}}, ids)
+	assert.Eventually(func() bool {
+		_, err1 := store.GetTicket(ctx, "1")
+		_, err2 := store.GetTicket(ctx, "2")
+		return status.Code(err1) == codes.NotFound && status.Code(err2) == codes.NotFound
+	}, 5*time.Second, 10*time.Millisecond)
+	_, err = store.GetTicket(ctx, "3")
+	assert.Nil(err)
+}
+
 func TestDoGetTicket(t *testing.T) {
 	fakeTicket := &pb.Ticket{
 		Id: "1",
@@ -266,3 +466,107 @@ func TestDoGetTicket(t *testing.T) {
 		})
 	}
 }
+
+func TestDoGetTicketState(t *testing.T) {
+	assert := assert.New(t)
+	ctx := utilTesting.NewContext(t)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, viper.New())
+	defer closer()
+
+	_, err := doGetTicketState(ctx, "1", store)
+	assert.Equal(codes.NotFound, status.Convert(err).Code())
+
+	assert.Nil(store.CreateTicket(ctx, &pb.Ticket{Id: "1"}))
+	resp, err := doGetTicketState(ctx, "1", store)
+	assert.Nil(err)
+	assert.Equal(pb.Ticket_SEARCHING, resp.GetState())
+
+	_, _, err = store.UpdateAssignments(ctx, []string{"1"}, &pb.Assignment{Connection: "1.2.3.4:1234"})
+	assert.Nil(err)
+	resp, err = doGetTicketState(ctx, "1", store)
+	assert.Nil(err)
+	assert.Equal(pb.Ticket_ASSIGNED, resp.GetState())
+}
+
+func TestDoGetTicketStatus(t *testing.T) {
+	assert := assert.New(t)
+	ctx := utilTesting.NewContext(t)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, viper.New())
+	defer closer()
+
+	_, err := doGetTicketStatus(ctx, "1", store, time.Now())
+	assert.Equal(codes.NotFound, status.Convert(err).Code())
+
+	// Imported tickets have no known age.
+	imported := &pb.Ticket{Id: "1"}
+	assert.Nil(store.CreateTicket(ctx, imported))
+	assert.Nil(store.IndexTicket(ctx, imported))
+	resp, err := doGetTicketStatus(ctx, "1", store, time.Now())
+	assert.Nil(err)
+	assert.True(resp.GetIndexed())
+	assert.False(resp.GetProposed())
+	assert.False(resp.GetAssigned())
+	assert.Nil(resp.GetAge())
+
+	created, err := doCreateTicket(ctx, &pb.CreateTicketRequest{Ticket: &pb.Ticket{}}, store, clientMetadata{}, 0)
+	assert.Nil(err)
+	resp, err = doGetTicketStatus(ctx, created.GetTicket().GetId(), store, time.Now().Add(time.Minute))
+	assert.Nil(err)
+	assert.True(resp.GetIndexed())
+	age, err := ptypes.Duration(resp.GetAge())
+	assert.Nil(err)
+	assert.InDelta(time.Minute.Seconds(), age.Seconds(), 2)
+}
+
+func TestDoUpdateTicket(t *testing.T) {
+	assert := assert.New(t)
+	ctx := utilTesting.NewContext(t)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, viper.New())
+	defer closer()
+
+	ticket := &pb.Ticket{
+		Id:           "1",
+		SearchFields: &pb.SearchFields{Tags: []string{"solo"}, DoubleArgs: map[string]float64{"latency": 80}},
+	}
+	assert.Nil(store.CreateTicket(ctx, ticket))
+	assert.Nil(store.IndexTicket(ctx, ticket))
+	start := time.Unix(1000, 0)
+	_, _, err := store.RewriteTicketsByID(ctx, []string{"1"}, func(ticket *pb.Ticket) bool {
+		pb.ReserveForEvent(ticket, "cup", start)
+		return true
+	})
+	assert.Nil(err)
+
+	update := &pb.Ticket{
+		Id:           "1",
+		SearchFields: &pb.SearchFields{Tags: []string{"party"}, DoubleArgs: map[string]float64{"latency": 40}},
+		PlayerIds:    []string{"alice", "bob"},
+	}
+	resp, err := doUpdateTicket(ctx, update, store)
+	assert.Nil(err)
+	assert.ElementsMatch([]string{"alice", "bob"}, resp.GetTicket().GetPlayerIds())
+
+	got, err := store.GetTicket(ctx, "1")
+	assert.Nil(err)
+	assert.Equal(float64(40), got.GetSearchFields().GetDoubleArgs()["latency"])
+	assert.ElementsMatch([]string{"party", pb.EventTag("cup")}, got.GetSearchFields().GetTags())
+	eventID, _, ok := pb.ReservedEvent(got)
+	assert.True(ok)
+	assert.Equal("cup", eventID)
+
+	// The ticket moved to the indices of its new search fields.
+	count, err := store.CountTickets(ctx, &pb.Pool{TagPresentFilters: []*pb.TagPresentFilter{{Tag: "solo"}}})
+	assert.Nil(err)
+	assert.Equal(int64(0), count)
+	count, err = store.CountTickets(ctx, &pb.Pool{TagPresentFilters: []*pb.TagPresentFilter{{Tag: "party"}}})
+	assert.Nil(err)
+	assert.Equal(int64(1), count)
+
+	_, err = doUpdateTicket(ctx, &pb.Ticket{Id: "2"}, store)
+	assert.Equal(codes.NotFound, status.Convert(err).Code())
+
+	_, _, err = store.UpdateAssignments(ctx, []string{"1"}, &pb.Assignment{Connection: "1.2.3.4:1234"})
+	assert.Nil(err)
+	_, err = doUpdateTicket(ctx, update, store)
+	assert.Equal(codes.FailedPrecondition, status.Convert(err).Code())
+}
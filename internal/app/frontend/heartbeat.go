@@ -0,0 +1,120 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package frontend
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/statestore"
+	"open-match.dev/open-match/internal/telemetry"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// reapBatchSize bounds how many lapsed tickets are deindexed at once.
+const reapBatchSize = 1000
+
+var (
+	mTicketHeartbeats = telemetry.Counter("frontend/ticket_heartbeats", "ticket heartbeats recorded")
+	mTicketsReaped    = telemetry.Counter("frontend/tickets_reaped", "tickets deindexed because their heartbeat lapsed")
+)
+
+// heartbeatTimeout returns frontend.heartbeat.timeout, how long a ticket stays
+// indexed without a heartbeat, or zero if tickets don't need heartbeats.
+func heartbeatTimeout(cfg config.View) time.Duration {
+	if !cfg.IsSet("frontend.heartbeat.timeout") {
+		return 0
+	}
+	return cfg.GetDuration("frontend.heartbeat.timeout")
+}
+
+// Heartbeat keeps the Tickets in matchmaking while frontend.heartbeat.timeout
+// is set, returning the ids of those which are no longer in matchmaking.
+// Heartbeats are accepted but not recorded otherwise, so that clients can
+// start sending them before the timeout is set.
+func (s *frontendService) Heartbeat(ctx context.Context, req *pb.HeartbeatRequest) (*pb.HeartbeatResponse, error) {
+	if len(req.GetTicketIds()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, ".ticket_ids is required")
+	}
+	if heartbeatTimeout(s.cfg) <= 0 {
+		return &pb.HeartbeatResponse{}, nil
+	}
+	return doHeartbeat(ctx, req.GetTicketIds(), s.store)
+}
+
+func doHeartbeat(ctx context.Context, ids []string, store statestore.Service) (*pb.HeartbeatResponse, error) {
+	missing, err := store.RecordHeartbeats(ctx, ids)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"error": err.Error(),
+			"ids":   ids,
+		}).Error("failed to record the ticket heartbeats")
+		return nil, err
+	}
+	telemetry.RecordNUnitMeasurement(ctx, mTicketHeartbeats, int64(len(ids)-len(missing)))
+	return &pb.HeartbeatResponse{NotIndexedTicketIds: missing}, nil
+}
+
+// recordHeartbeats records the first heartbeat of new or updated tickets, if
+// tickets need heartbeats.
+func (s *frontendService) recordHeartbeats(ctx context.Context, tickets ...*pb.Ticket) error {
+	if heartbeatTimeout(s.cfg) <= 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(tickets))
+	for _, ticket := range tickets {
+		ids = append(ids, ticket.GetId())
+	}
+	_, err := doHeartbeat(ctx, ids, s.store)
+	return err
+}
+
+// startReaper deindexes the tickets whose heartbeat lapsed every interval,
+// rather than leaving them in the pool until they expire.  Every frontend runs
+// a reaper, but each lapsed ticket is reaped by only one of them.
+func startReaper(store statestore.Service, timeout, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			reapLapsedTickets(ctx, store, timeout)
+			cancel()
+		}
+	}()
+}
+
+// reapLapsedTickets deindexes all of the tickets whose heartbeat is older than
+// timeout, in batches.
+func reapLapsedTickets(ctx context.Context, store statestore.Service, timeout time.Duration) {
+	for {
+		ids, err := store.DeindexLapsedTickets(ctx, timeout, reapBatchSize)
+		if err != nil {
+			logger.WithError(err).Warning("failed to deindex tickets with lapsed heartbeats, retrying on the next interval")
+			return
+		}
+		if len(ids) > 0 {
+			telemetry.RecordNUnitMeasurement(ctx, mTicketsReaped, int64(len(ids)))
+			logger.WithField("count", len(ids)).Debug("Deindexed tickets with lapsed heartbeats.")
+		}
+		if len(ids) < reapBatchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package frontend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	statestoreTesting "open-match.dev/open-match/internal/statestore/testing"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestHeartbeat(t *testing.T) {
+	assert := assert.New(t)
+	ctx := utilTesting.NewContext(t)
+	cfg := viper.New()
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, cfg)
+	defer closer()
+	s := &frontendService{cfg: cfg, store: store}
+
+	_, err := s.Heartbeat(ctx, &pb.HeartbeatRequest{})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+
+	// Tickets created without heartbeats required are never reaped.
+	untracked, err := s.CreateTicket(ctx, &pb.CreateTicketRequest{Ticket: &pb.Ticket{}})
+	assert.Nil(err)
+
+	const timeout = 200 * time.Millisecond
+	cfg.Set("frontend.heartbeat.timeout", timeout)
+	resp, err := s.CreateTickets(ctx, &pb.CreateTicketsRequest{Tickets: []*pb.Ticket{{}, {}}})
+	assert.Nil(err)
+	alive, lapsed := resp.GetTickets()[0].GetId(), resp.GetTickets()[1].GetId()
+
+	time.Sleep(2 * timeout)
+	hb, err := s.Heartbeat(ctx, &pb.HeartbeatRequest{TicketIds: []string{alive, "unknown"}})
+	assert.Nil(err)
+	assert.Equal([]string{"unknown"}, hb.GetNotIndexedTicketIds())
+
+	reapLapsedTickets(ctx, store, timeout)
+	ids, err := store.GetIndexedIDSet(ctx)
+	assert.Nil(err)
+	assert.Equal(map[string]struct{}{alive: {}, untracked.GetTicket().GetId(): {}}, ids)
+
+	hb, err = s.Heartbeat(ctx, &pb.HeartbeatRequest{TicketIds: []string{alive, lapsed}})
+	assert.Nil(err)
+	assert.Equal([]string{lapsed}, hb.GetNotIndexedTicketIds())
+}
@@ -0,0 +1,231 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package frontend
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/telemetry"
+)
+
+const (
+	// shedTickInterval is how often streams are shed while degraded.
+	shedTickInterval = 100 * time.Millisecond
+)
+
+var (
+	mAssignmentStreamsShed    = telemetry.Counter("frontend/assignment_streams_shed", "GetAssignments streams completed with Unavailable to shed load from a degraded statestore")
+	mAssignmentStreamsRefused = telemetry.Counter("frontend/assignment_streams_refused", "GetAssignments streams refused while the statestore is degraded")
+	mStatestoreDegraded       = telemetry.Gauge("frontend/statestore_degraded", "whether the statestore is considered degraded and GetAssignments streams are shed")
+)
+
+// assignmentShedder completes GetAssignments streams while the statestore is
+// degraded, ie. after frontend.loadShedding.failureThreshold consecutive
+// failed health checks.  Streams are completed at
+// frontend.loadShedding.streamsPerSecond with Unavailable and a RetryInfo,
+// whose delays are spread so that the whole watcher population reconnects at
+// about the same rate, rather than every watcher piling retries onto Redis.
+type assignmentShedder struct {
+	failureThreshold int
+	streamsPerSecond float64
+	retryDelay       time.Duration
+
+	m        sync.Mutex
+	failures int
+	streams  map[chan struct{}]struct{}
+	// budget accumulates the fractional streams to shed between ticks.
+	budget float64
+}
+
+func newAssignmentShedder(cfg config.View) *assignmentShedder {
+	s := &assignmentShedder{
+		failureThreshold: 3,
+		streamsPerSecond: 100,
+		retryDelay:       5 * time.Second,
+		streams:          make(map[chan struct{}]struct{}),
+	}
+	if cfg.IsSet("frontend.loadShedding.failureThreshold") {
+		s.failureThreshold = cfg.GetInt("frontend.loadShedding.failureThreshold")
+	}
+	if cfg.IsSet("frontend.loadShedding.streamsPerSecond") {
+		s.streamsPerSecond = cfg.GetFloat64("frontend.loadShedding.streamsPerSecond")
+	}
+	if cfg.IsSet("frontend.loadShedding.retryDelay") {
+		s.retryDelay = cfg.GetDuration("frontend.loadShedding.retryDelay")
+	}
+	return s
+}
+
+// start checks the health of the statestore every interval, and sheds streams
+// while it's degraded.
+func (s *assignmentShedder) start(check func(context.Context) error, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			s.observe(ctx, check(ctx))
+			cancel()
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(shedTickInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			s.shed(context.Background(), shedTickInterval)
+		}
+	}()
+}
+
+// observe records the result of a health check.
+func (s *assignmentShedder) observe(ctx context.Context, err error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	wasDegraded := s.locklessDegraded()
+	if err == nil {
+		s.failures = 0
+		s.budget = 0
+	} else {
+		s.failures++
+	}
+
+	switch degraded := s.locklessDegraded(); {
+	case degraded && !wasDegraded:
+		logger.WithError(err).WithField("streams", len(s.streams)).Warning("statestore is degraded, shedding GetAssignments streams")
+		telemetry.SetGauge(ctx, mStatestoreDegraded, 1)
+	case !degraded && wasDegraded:
+		logger.Info("statestore recovered, no longer shedding GetAssignments streams")
+		telemetry.SetGauge(ctx, mStatestoreDegraded, 0)
+	}
+}
+
+func (s *assignmentShedder) locklessDegraded() bool {
+	return s.failureThreshold > 0 && s.failures >= s.failureThreshold
+}
+
+// shed completes the streams due for the elapsed time, if degraded.
+func (s *assignmentShedder) shed(ctx context.Context, elapsed time.Duration) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if !s.locklessDegraded() {
+		return
+	}
+	s.budget += s.streamsPerSecond * elapsed.Seconds()
+	for c := range s.streams {
+		if s.budget < 1 {
+			break
+		}
+		s.budget--
+		delete(s.streams, c)
+		close(c)
+		telemetry.RecordUnitMeasurement(ctx, mAssignmentStreamsShed)
+	}
+	if len(s.streams) == 0 {
+		// Don't save up budget to shed future streams in a burst.
+		s.budget = 0
+	}
+}
+
+// watch registers a stream, returning a channel closed when the stream must be
+// completed with unavailable(), and a function to unregister it.  Fails with
+// unavailable() if the statestore is degraded.
+func (s *assignmentShedder) watch(ctx context.Context) (<-chan struct{}, func(), error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.locklessDegraded() {
+		telemetry.RecordUnitMeasurement(ctx, mAssignmentStreamsRefused)
+		return nil, nil, s.locklessUnavailable()
+	}
+
+	c := make(chan struct{})
+	s.streams[c] = struct{}{}
+	return c, func() {
+		s.m.Lock()
+		defer s.m.Unlock()
+		delete(s.streams, c)
+	}, nil
+}
+
+// unavailable returns the error completing shed streams.
+func (s *assignmentShedder) unavailable() error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.locklessUnavailable()
+}
+
+// locklessUnavailable returns Unavailable with a RetryInfo delay picked at
+// random between retryDelay and the time it takes to reconnect all current
+// streams at streamsPerSecond, whichever is longer, on top of retryDelay.
+func (s *assignmentShedder) locklessUnavailable() error {
+	spread := s.retryDelay
+	if s.streamsPerSecond > 0 {
+		if d := time.Duration(float64(len(s.streams)) / s.streamsPerSecond * float64(time.Second)); d > spread {
+			spread = d
+		}
+	}
+	delay := s.retryDelay + time.Duration(rand.Int63n(int64(spread)+1))
+
+	st, err := status.New(codes.Unavailable, "statestore is degraded, retry later").WithDetails(&errdetails.RetryInfo{
+		RetryDelay: ptypes.DurationProto(delay),
+	})
+	if err != nil {
+		return status.Error(codes.Unavailable, "statestore is degraded, retry later")
+	}
+	return st.Err()
+}
+
+// run calls f with a context canceled when the stream is shed, in which case
+// unavailable() is returned instead of f's result.  A nil shedder only calls f.
+func (s *assignmentShedder) run(ctx context.Context, f func(context.Context) error) error {
+	if s == nil {
+		return f(ctx)
+	}
+
+	shed, done, err := s.watch(ctx)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-shed:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	err = f(ctx)
+	select {
+	case <-shed:
+		return s.unavailable()
+	default:
+		return err
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package frontend
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAssignmentShedder(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	cfg := viper.New()
+	cfg.Set("frontend.loadShedding.failureThreshold", 2)
+	cfg.Set("frontend.loadShedding.streamsPerSecond", 10)
+	cfg.Set("frontend.loadShedding.retryDelay", "1s")
+	s := newAssignmentShedder(cfg)
+
+	var streams []<-chan struct{}
+	for i := 0; i < 3; i++ {
+		c, _, err := s.watch(ctx)
+		assert.Nil(err)
+		streams = append(streams, c)
+	}
+	shedCount := func() int {
+		n := 0
+		for _, c := range streams {
+			select {
+			case <-c:
+				n++
+			default:
+			}
+		}
+		return n
+	}
+
+	// Healthy, or below the threshold: nothing is shed.
+	s.shed(ctx, time.Second)
+	s.observe(ctx, errors.New("redis is down"))
+	s.shed(ctx, time.Second)
+	assert.Equal(0, shedCount())
+
+	// Degraded: new streams are refused, and existing ones shed at the rate.
+	s.observe(ctx, errors.New("redis is down"))
+	_, _, err := s.watch(ctx)
+	assertRetryInfo(t, err, time.Second)
+
+	s.shed(ctx, 150*time.Millisecond)
+	assert.Equal(1, shedCount())
+	s.shed(ctx, 50*time.Millisecond)
+	assert.Equal(2, shedCount())
+
+	// Recovered: the remaining stream is kept.
+	s.observe(ctx, nil)
+	s.shed(ctx, time.Second)
+	assert.Equal(2, shedCount())
+	_, done, err := s.watch(ctx)
+	assert.Nil(err)
+	done()
+}
+
+func TestAssignmentShedderRun(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	cfg := viper.New()
+	cfg.Set("frontend.loadShedding.failureThreshold", 1)
+	s := newAssignmentShedder(cfg)
+
+	started := make(chan struct{})
+	errc := make(chan error)
+	go func() {
+		errc <- s.run(ctx, func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}()
+	<-started
+
+	s.observe(ctx, errors.New("redis is down"))
+	s.shed(ctx, time.Second)
+	assertRetryInfo(t, <-errc, 5*time.Second)
+	assert.Empty(s.streams)
+
+	var nilShedder *assignmentShedder
+	assert.Nil(nilShedder.run(ctx, func(context.Context) error { return nil }))
+}
+
+func assertRetryInfo(t *testing.T, err error, minDelay time.Duration) {
+	st, ok := status.FromError(err)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, codes.Unavailable, st.Code())
+	if !assert.Len(t, st.Details(), 1) {
+		return
+	}
+	info, ok := st.Details()[0].(*errdetails.RetryInfo)
+	if !assert.True(t, ok) {
+		return
+	}
+	delay, err := ptypes.Duration(info.RetryDelay)
+	assert.Nil(t, err)
+	assert.True(t, delay >= minDelay, "retry delay %v is shorter than %v", delay, minDelay)
+	assert.True(t, delay <= 2*minDelay, "retry delay %v is longer than %v", delay, 2*minDelay)
+}
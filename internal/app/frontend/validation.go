@@ -0,0 +1,201 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package frontend
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/rpc"
+	"open-match.dev/open-match/internal/telemetry"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+var (
+	mTicketsRejected           = telemetry.Counter("frontend/tickets_rejected", "tickets rejected by frontend.validation", clientVersionKey, clientPlatformKey)
+	mValidationWebhookFailures = telemetry.Counter("frontend/validation_webhook_failures", "calls to the frontend.validation.webhook which failed")
+)
+
+// ticketValidator rejects malformed or abusive tickets with InvalidArgument
+// before they're written to state storage, using the rules configured under
+// frontend.validation.  Its rules are rebuilt whenever they're reconfigured.
+type ticketValidator struct {
+	rules *config.Cacher
+}
+
+// ticketRules are the validation rules of a configuration.
+type ticketRules struct {
+	// maxTicketBytes and maxExtensionBytes bound the encoded size of tickets
+	// and of each of their extensions, if positive.
+	maxTicketBytes     int
+	maxExtensionBytes  int
+	requiredDoubleArgs []string
+	requiredStringArgs []string
+	// allowedTagPatterns match the whole tag.  Any tag is allowed if empty.
+	allowedTagPatterns []*regexp.Regexp
+
+	// webhook validates the tickets passing the other rules, if configured.
+	webhook         pb.TicketValidatorClient
+	webhookTimeout  time.Duration
+	webhookFailOpen bool
+}
+
+// newTicketValidator returns the validator of tickets sent to the frontend,
+// failing if its rules are misconfigured.
+func newTicketValidator(cfg config.View) (*ticketValidator, error) {
+	v := &ticketValidator{rules: config.NewCacher(cfg, newTicketRules)}
+	if _, err := v.rules.Get(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func newTicketRules(cfg config.View) (interface{}, func(), error) {
+	r := &ticketRules{
+		maxTicketBytes:     cfg.GetInt("frontend.validation.maxTicketBytes"),
+		maxExtensionBytes:  cfg.GetInt("frontend.validation.maxExtensionBytes"),
+		requiredDoubleArgs: cfg.GetStringSlice("frontend.validation.requiredDoubleArgs"),
+		requiredStringArgs: cfg.GetStringSlice("frontend.validation.requiredStringArgs"),
+		webhookTimeout:     time.Second,
+		webhookFailOpen:    cfg.GetBool("frontend.validation.webhook.failOpen"),
+	}
+	for _, pattern := range cfg.GetStringSlice("frontend.validation.allowedTagPatterns") {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid frontend.validation.allowedTagPatterns entry %q: %w", pattern, err)
+		}
+		r.allowedTagPatterns = append(r.allowedTagPatterns, re)
+	}
+	if cfg.IsSet("frontend.validation.webhook.timeout") {
+		r.webhookTimeout = cfg.GetDuration("frontend.validation.webhook.timeout")
+	}
+
+	if cfg.GetString("frontend.validation.webhook.hostname") == "" {
+		return r, nil, nil
+	}
+	conn, release, err := rpc.SharedGRPCClientFromConfig(cfg, "frontend.validation.webhook")
+	if err != nil {
+		return nil, nil, err
+	}
+	r.webhook = pb.NewTicketValidatorClient(conn)
+	return r, release, nil
+}
+
+// validate returns InvalidArgument, listing every violation, if the ticket
+// breaks the rules or is rejected by the webhook.  A nil validator accepts
+// every ticket.
+func (v *ticketValidator) validate(ctx context.Context, ticket *pb.Ticket) error {
+	if v == nil {
+		return nil
+	}
+	r, err := v.rules.Get()
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to read frontend.validation: %v", err)
+	}
+	return r.(*ticketRules).validate(ctx, ticket)
+}
+
+func (r *ticketRules) validate(ctx context.Context, ticket *pb.Ticket) error {
+	violations := r.check(ticket)
+	if len(violations) == 0 && r.webhook != nil {
+		var err error
+		violations, err = r.callWebhook(ctx, ticket)
+		if err != nil {
+			return err
+		}
+	}
+	if len(violations) > 0 {
+		telemetry.RecordUnitMeasurement(ctx, mTicketsRejected)
+		return status.Errorf(codes.InvalidArgument, "invalid ticket: %s", strings.Join(violations, "; "))
+	}
+	return nil
+}
+
+// check returns how the ticket breaks the configured rules.
+func (r *ticketRules) check(ticket *pb.Ticket) []string {
+	var violations []string
+	if size := proto.Size(ticket); r.maxTicketBytes > 0 && size > r.maxTicketBytes {
+		violations = append(violations, fmt.Sprintf("ticket is %d bytes, more than the allowed %d", size, r.maxTicketBytes))
+	}
+	if r.maxExtensionBytes > 0 {
+		keys := make([]string, 0, len(ticket.GetExtensions()))
+		for key := range ticket.GetExtensions() {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if size := proto.Size(ticket.GetExtensions()[key]); size > r.maxExtensionBytes {
+				violations = append(violations, fmt.Sprintf("extension %q is %d bytes, more than the allowed %d", key, size, r.maxExtensionBytes))
+			}
+		}
+	}
+
+	fields := ticket.GetSearchFields()
+	for _, arg := range r.requiredDoubleArgs {
+		if _, ok := fields.GetDoubleArgs()[arg]; !ok {
+			violations = append(violations, fmt.Sprintf("search_fields.double_args[%q] is required", arg))
+		}
+	}
+	for _, arg := range r.requiredStringArgs {
+		if _, ok := fields.GetStringArgs()[arg]; !ok {
+			violations = append(violations, fmt.Sprintf("search_fields.string_args[%q] is required", arg))
+		}
+	}
+	if len(r.allowedTagPatterns) > 0 {
+		for _, tag := range fields.GetTags() {
+			if !matchesAny(r.allowedTagPatterns, tag) {
+				violations = append(violations, fmt.Sprintf("search_fields.tags %q matches no allowed pattern", tag))
+			}
+		}
+	}
+	return violations
+}
+
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	for _, re := range patterns {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// callWebhook returns the violations reported by the webhook.  Failed calls
+// accept the ticket if frontend.validation.webhook.failOpen is set, and fail
+// with Unavailable otherwise.
+func (r *ticketRules) callWebhook(ctx context.Context, ticket *pb.Ticket) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.webhookTimeout)
+	defer cancel()
+	resp, err := r.webhook.ValidateTicket(ctx, &pb.ValidateTicketRequest{Ticket: ticket})
+	if err == nil {
+		return resp.GetViolations(), nil
+	}
+
+	telemetry.RecordUnitMeasurement(ctx, mValidationWebhookFailures)
+	if r.webhookFailOpen {
+		logger.WithError(err).Warning("ticket validation webhook failed, accepting the ticket")
+		return nil, nil
+	}
+	logger.WithError(err).Error("ticket validation webhook failed, rejecting the ticket")
+	return nil, status.Errorf(codes.Unavailable, "failed to validate the ticket: %v", err)
+}
@@ -0,0 +1,111 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package frontend
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestTicketValidator(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	cfg := viper.New()
+	cfg.Set("frontend.validation.maxTicketBytes", 200)
+	cfg.Set("frontend.validation.maxExtensionBytes", 50)
+	cfg.Set("frontend.validation.requiredDoubleArgs", []string{"mmr"})
+	cfg.Set("frontend.validation.requiredStringArgs", []string{"region"})
+	cfg.Set("frontend.validation.allowedTagPatterns", []string{"mode\\.[a-z]+", "beta"})
+	v, err := newTicketValidator(cfg)
+	assert.Nil(err)
+
+	valid := &pb.Ticket{SearchFields: &pb.SearchFields{
+		DoubleArgs: map[string]float64{"mmr": 1500},
+		StringArgs: map[string]string{"region": "europe"},
+		Tags:       []string{"mode.ranked", "beta"},
+	}}
+	assert.Nil(v.validate(ctx, valid))
+
+	err = v.validate(ctx, &pb.Ticket{SearchFields: &pb.SearchFields{Tags: []string{"mode.ranked.cheat", "betamax"}}})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+	for _, violation := range []string{`double_args["mmr"] is required`, `string_args["region"] is required`, `"mode.ranked.cheat" matches no allowed pattern`, `"betamax" matches no allowed pattern`} {
+		assert.Contains(status.Convert(err).Message(), violation)
+	}
+
+	large, err := ptypes.MarshalAny(&wrappers.StringValue{Value: strings.Repeat("x", 100)})
+	assert.Nil(err)
+	oversized := &pb.Ticket{SearchFields: valid.GetSearchFields(), Extensions: map[string]*any.Any{"profile": large}}
+	err = v.validate(ctx, oversized)
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+	assert.Contains(status.Convert(err).Message(), `extension "profile" is`)
+	oversized.Extensions["more"] = large
+	assert.Contains(status.Convert(v.validate(ctx, oversized)).Message(), "more than the allowed 200")
+
+	// Rules follow the configuration.
+	cfg.Set("frontend.validation.requiredDoubleArgs", []string{})
+	cfg.Set("frontend.validation.requiredStringArgs", []string{})
+	assert.Nil(v.validate(ctx, &pb.Ticket{}))
+
+	cfg.Set("frontend.validation.allowedTagPatterns", []string{"("})
+	_, err = newTicketValidator(cfg)
+	assert.NotNil(err)
+
+	// A nil validator accepts every ticket.
+	var none *ticketValidator
+	assert.Nil(none.validate(ctx, oversized))
+}
+
+type fakeTicketValidator struct {
+	violations []string
+	err        error
+}
+
+func (f *fakeTicketValidator) ValidateTicket(ctx context.Context, req *pb.ValidateTicketRequest, opts ...grpc.CallOption) (*pb.ValidateTicketResponse, error) {
+	return &pb.ValidateTicketResponse{Violations: f.violations}, f.err
+}
+
+func TestTicketValidatorWebhook(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	webhook := &fakeTicketValidator{}
+	r := &ticketRules{webhook: webhook, webhookTimeout: time.Second}
+
+	assert.Nil(r.validate(ctx, &pb.Ticket{}))
+
+	webhook.violations = []string{"banned player"}
+	err := r.validate(ctx, &pb.Ticket{})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+	assert.Contains(status.Convert(err).Message(), "banned player")
+
+	webhook.violations = nil
+	webhook.err = errors.New("connection refused")
+	assert.Equal(codes.Unavailable, status.Code(r.validate(ctx, &pb.Ticket{})))
+	r.webhookFailOpen = true
+	assert.Nil(r.validate(ctx, &pb.Ticket{}))
+}
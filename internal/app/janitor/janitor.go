@@ -0,0 +1,123 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package janitor periodically removes state leaked by Open Match, eg. tickets
+// whose lazy deletion was interrupted by a restart, or index entries of
+// expired tickets.
+package janitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/tag"
+	"open-match.dev/open-match/internal/clock"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/rpc"
+	"open-match.dev/open-match/internal/statestore"
+	"open-match.dev/open-match/internal/telemetry"
+)
+
+var (
+	logger = logrus.WithFields(logrus.Fields{
+		"app":       "openmatch",
+		"component": "app.janitor",
+	})
+
+	mExpiredLeasesReclaimed        = telemetry.Counter("janitor/expired_leases_reclaimed", "expired ticket leases removed")
+	mMissingTicketsReclaimed       = telemetry.Counter("janitor/missing_tickets_reclaimed", "indexed ids removed because their ticket no longer exists")
+	mDanglingIndexEntriesReclaimed = telemetry.Counter("janitor/dangling_index_entries_reclaimed", "field index entries removed because their ticket is no longer indexed")
+	mOrphanedTicketsReclaimed      = telemetry.Counter("janitor/orphaned_tickets_reclaimed", "tickets deleted because they were neither indexed nor assigned")
+	mExpiredBackfillsReclaimed     = telemetry.Counter("janitor/expired_backfills_reclaimed", "backfills deleted because they were no longer acknowledged")
+	mExpiredTicketsReclaimed       = telemetry.Counter("janitor/expired_tickets_reclaimed", "expired tickets removed from the index and ignore list as they expired")
+
+	stateKey = tag.MustNewKey("state")
+	mTickets = telemetry.Gauge("janitor/tickets", "number of tickets in each lifecycle state, as of the last garbage collection", stateKey)
+)
+
+// BindService starts the janitor, which has no API, in the serving harness.
+func BindService(p *rpc.ServerParams, cfg config.View) error {
+	return BindServiceWithClock(p, cfg, clock.Real())
+}
+
+// BindServiceWithClock starts the janitor, collecting garbage every
+// janitor.interval of clk.  The clock must be the one of the other components,
+// so that leases expire at the same time for all of them.
+func BindServiceWithClock(p *rpc.ServerParams, cfg config.View, clk clock.Clock) error {
+	if cfg.IsSet("janitor.enabled") && !cfg.GetBool("janitor.enabled") {
+		return nil
+	}
+
+	interval := 5 * time.Minute
+	if cfg.IsSet("janitor.interval") {
+		interval = cfg.GetDuration("janitor.interval")
+	}
+
+	store := statestore.NewWithClock(cfg, clk)
+	p.AddHealthCheckFunc(store.HealthCheck)
+
+	// Expired tickets are otherwise only deindexed by the next collection.
+	if cfg.GetBool("janitor.cleanUpExpiredTickets") {
+		go func() {
+			ctx := context.Background()
+			store.CleanUpExpiredTickets(ctx, func(string) {
+				telemetry.RecordUnitMeasurement(ctx, mExpiredTicketsReclaimed)
+			})
+		}()
+	}
+
+	go func() {
+		var orphanCandidates map[string]struct{}
+		for {
+			<-clk.After(interval)
+			orphanCandidates = collectGarbage(context.Background(), store, orphanCandidates)
+		}
+	}()
+	return nil
+}
+
+// collectGarbage runs a garbage collection, returning the orphan candidates of
+// the next one.
+func collectGarbage(ctx context.Context, store statestore.Service, orphanCandidates map[string]struct{}) map[string]struct{} {
+	gc, err := store.CollectGarbage(ctx, orphanCandidates)
+	if gc != nil {
+		telemetry.RecordNUnitMeasurement(ctx, mExpiredLeasesReclaimed, gc.ExpiredLeases)
+		telemetry.RecordNUnitMeasurement(ctx, mMissingTicketsReclaimed, gc.MissingTickets)
+		telemetry.RecordNUnitMeasurement(ctx, mDanglingIndexEntriesReclaimed, gc.DanglingIndexEntries)
+		telemetry.RecordNUnitMeasurement(ctx, mOrphanedTicketsReclaimed, gc.OrphanedTickets)
+		telemetry.RecordNUnitMeasurement(ctx, mExpiredBackfillsReclaimed, gc.ExpiredBackfills)
+	}
+	if err != nil {
+		logger.WithError(err).Warning("failed to collect garbage, retrying on the next interval")
+		// The previous candidates are only deleted if they're still orphaned
+		// on the next collection.
+		return orphanCandidates
+	}
+
+	for state, n := range gc.TicketStates {
+		telemetry.SetGauge(ctx, mTickets, n, tag.Upsert(stateKey, state))
+	}
+
+	logger.WithFields(logrus.Fields{
+		"expiredLeases":        gc.ExpiredLeases,
+		"missingTickets":       gc.MissingTickets,
+		"danglingIndexEntries": gc.DanglingIndexEntries,
+		"orphanedTickets":      gc.OrphanedTickets,
+		"expiredBackfills":     gc.ExpiredBackfills,
+		"storedTicketsDrift":   gc.StoredTicketsDrift,
+		"orphanCandidates":     len(gc.OrphanCandidates),
+	}).Debug("Collected garbage.")
+	return gc.OrphanCandidates
+}
@@ -0,0 +1,45 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package janitor
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	statestoreTesting "open-match.dev/open-match/internal/statestore/testing"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestCollectGarbageDeletesOrphansOnSecondPass(t *testing.T) {
+	assert := assert.New(t)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, viper.New())
+	defer closer()
+	defer store.Close()
+	ctx := utilTesting.NewContext(t)
+
+	assert.Nil(store.CreateTicket(ctx, &pb.Ticket{Id: "orphaned"}))
+
+	candidates := collectGarbage(ctx, store, nil)
+	assert.Equal(map[string]struct{}{"orphaned": {}}, candidates)
+	_, err := store.GetTicket(ctx, "orphaned")
+	assert.Nil(err)
+
+	candidates = collectGarbage(ctx, store, candidates)
+	assert.Empty(candidates)
+	_, err = store.GetTicket(ctx, "orphaned")
+	assert.NotNil(err)
+}
@@ -15,16 +15,27 @@
 package minimatch
 
 import (
+	"open-match.dev/open-match/internal/app/admin"
 	"open-match.dev/open-match/internal/app/backend"
 	"open-match.dev/open-match/internal/app/frontend"
+	"open-match.dev/open-match/internal/app/janitor"
 	"open-match.dev/open-match/internal/app/query"
 	"open-match.dev/open-match/internal/app/synchronizer"
+	"open-match.dev/open-match/internal/clock"
 	"open-match.dev/open-match/internal/config"
 	"open-match.dev/open-match/internal/rpc"
 )
 
 // BindService creates the minimatch service to the server Params.
 func BindService(p *rpc.ServerParams, cfg config.View) error {
+	return BindServiceWithClock(p, cfg, clock.Real())
+}
+
+// BindServiceWithClock creates the minimatch service driven by clk.  Passing a
+// clock.Virtual allows simulations and tests of time dependent behavior, such
+// as synchronizer cycles and ignore list expiration, to run without waiting on
+// wall clock time.
+func BindServiceWithClock(p *rpc.ServerParams, cfg config.View, clk clock.Clock) error {
 	if err := backend.BindService(p, cfg); err != nil {
 		return err
 	}
@@ -33,11 +44,19 @@ func BindService(p *rpc.ServerParams, cfg config.View) error {
 		return err
 	}
 
-	if err := query.BindService(p, cfg); err != nil {
+	if err := query.BindServiceWithClock(p, cfg, clk); err != nil {
+		return err
+	}
+
+	if err := synchronizer.BindServiceWithClock(p, cfg, clk); err != nil {
+		return err
+	}
+
+	if err := admin.BindService(p, cfg); err != nil {
 		return err
 	}
 
-	if err := synchronizer.BindService(p, cfg); err != nil {
+	if err := janitor.BindServiceWithClock(p, cfg, clk); err != nil {
 		return err
 	}
 
@@ -0,0 +1,133 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/sirupsen/logrus"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/filter"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+const (
+	configNameTagPresent   = "query.defaultFilters.tagPresent"
+	configNameTagAbsent    = "query.defaultFilters.tagAbsent"
+	configNameStringEquals = "query.defaultFilters.stringEquals"
+)
+
+// defaultFilters are deployment wide filters applied to every pool queried,
+// so that policies such as excluding synthetic tickets don't rely on every
+// profile author remembering them.
+type defaultFilters struct {
+	stringEquals []*pb.StringEqualsFilter
+	tagPresent   []*pb.TagPresentFilter
+	// tagAbsent excludes tickets with any of these tags, which pools can't
+	// express.
+	tagAbsent []string
+}
+
+// newDefaultFilters reads the default filters from the configuration.
+// query.defaultFilters.tagPresent lists tags tickets must have,
+// query.defaultFilters.tagAbsent lists tags tickets must not have, and
+// query.defaultFilters.stringEquals lists "arg=value" pairs tickets must match.
+func newDefaultFilters(cfg config.View) (*defaultFilters, error) {
+	d := &defaultFilters{
+		tagAbsent: cfg.GetStringSlice(configNameTagAbsent),
+	}
+
+	for _, tag := range cfg.GetStringSlice(configNameTagPresent) {
+		d.tagPresent = append(d.tagPresent, &pb.TagPresentFilter{Tag: tag})
+	}
+
+	for _, pair := range cfg.GetStringSlice(configNameStringEquals) {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid %s entry %q, expected arg=value", configNameStringEquals, pair)
+		}
+		d.stringEquals = append(d.stringEquals, &pb.StringEqualsFilter{StringArg: parts[0], Value: parts[1]})
+	}
+
+	if !d.empty() {
+		logger.WithFields(logrus.Fields{
+			"tagPresent":   cfg.GetStringSlice(configNameTagPresent),
+			"tagAbsent":    d.tagAbsent,
+			"stringEquals": cfg.GetStringSlice(configNameStringEquals),
+		}).Info("Applying default filters to every queried pool.")
+	}
+	return d, nil
+}
+
+func (d *defaultFilters) empty() bool {
+	return len(d.stringEquals) == 0 && len(d.tagPresent) == 0 && len(d.tagAbsent) == 0
+}
+
+// apply returns a copy of pool with the default filters appended.
+func (d *defaultFilters) apply(pool *pb.Pool) *pb.Pool {
+	if len(d.stringEquals) == 0 && len(d.tagPresent) == 0 {
+		return pool
+	}
+
+	p, ok := proto.Clone(pool).(*pb.Pool)
+	if !ok {
+		return pool
+	}
+	p.StringEqualsFilters = append(p.StringEqualsFilters, d.stringEquals...)
+	p.TagPresentFilters = append(p.TagPresentFilters, d.tagPresent...)
+	return p
+}
+
+// inPool returns whether the ticket meets all the criteria of the pool, and
+// of the default filters.
+func (d *defaultFilters) inPool(ticket *pb.Ticket, pool *pb.Pool) bool {
+	for _, excluded := range d.tagAbsent {
+		for _, tag := range ticket.GetSearchFields().GetTags() {
+			if tag == excluded {
+				return false
+			}
+		}
+	}
+	return filter.InPool(ticket, pool)
+}
+
+// explain returns the result of each default filter for the ticket.
+func (d *defaultFilters) explain(ticket *pb.Ticket) []*pb.FilterResult {
+	results := filter.Explain(ticket, &pb.Pool{
+		StringEqualsFilters: d.stringEquals,
+		TagPresentFilters:   d.tagPresent,
+	})
+	tags := ticket.GetSearchFields().GetTags()
+	for _, excluded := range d.tagAbsent {
+		r := &pb.FilterResult{
+			Filter:      "tag_absent_filter " + excluded,
+			Passed:      true,
+			TicketValue: strings.Join(tags, ","),
+		}
+		for _, tag := range tags {
+			if tag == excluded {
+				r.Passed = false
+				break
+			}
+		}
+		results = append(results, r)
+	}
+	for _, r := range results {
+		r.Filter = "default " + r.Filter
+	}
+	return results
+}
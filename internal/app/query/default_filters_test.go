@@ -0,0 +1,92 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestDefaultFilters(t *testing.T) {
+	assert := assert.New(t)
+	cfg := viper.New()
+	cfg.Set("query.defaultFilters.tagPresent", []string{"verified"})
+	cfg.Set("query.defaultFilters.tagAbsent", []string{"synthetic", "suspended"})
+	cfg.Set("query.defaultFilters.stringEquals", []string{"region=eu"})
+
+	d, err := newDefaultFilters(cfg)
+	assert.Nil(err)
+
+	pool := &pb.Pool{
+		Name:              "pool",
+		TagPresentFilters: []*pb.TagPresentFilter{{Tag: "ranked"}},
+	}
+	applied := d.apply(pool)
+	assert.Len(pool.GetTagPresentFilters(), 1, "the profile's pool must not be modified")
+	assert.Len(applied.GetTagPresentFilters(), 2)
+	assert.Len(applied.GetStringEqualsFilters(), 1)
+
+	ticket := func(tags ...string) *pb.Ticket {
+		return &pb.Ticket{SearchFields: &pb.SearchFields{
+			Tags:       tags,
+			StringArgs: map[string]string{"region": "eu"},
+		}}
+	}
+	assert.True(d.inPool(ticket("ranked", "verified"), applied))
+	assert.False(d.inPool(ticket("ranked"), applied))
+	assert.False(d.inPool(ticket("ranked", "verified", "synthetic"), applied))
+	assert.False(d.inPool(&pb.Ticket{SearchFields: &pb.SearchFields{Tags: []string{"ranked", "verified"}}}, applied))
+}
+
+func TestDefaultFiltersNotSet(t *testing.T) {
+	assert := assert.New(t)
+	d, err := newDefaultFilters(viper.New())
+	assert.Nil(err)
+
+	pool := &pb.Pool{Name: "pool"}
+	assert.Equal(pool, d.apply(pool))
+	assert.True(d.inPool(&pb.Ticket{}, pool))
+}
+
+func TestDefaultFiltersInvalid(t *testing.T) {
+	cfg := viper.New()
+	cfg.Set("query.defaultFilters.stringEquals", []string{"region"})
+	_, err := newDefaultFilters(cfg)
+	assert.NotNil(t, err)
+}
+
+func TestDefaultFiltersExplain(t *testing.T) {
+	assert := assert.New(t)
+	cfg := viper.New()
+	cfg.Set("query.defaultFilters.tagPresent", []string{"verified"})
+	cfg.Set("query.defaultFilters.tagAbsent", []string{"synthetic"})
+	cfg.Set("query.defaultFilters.stringEquals", []string{"region=eu"})
+
+	d, err := newDefaultFilters(cfg)
+	assert.Nil(err)
+
+	results := d.explain(&pb.Ticket{SearchFields: &pb.SearchFields{
+		Tags:       []string{"verified", "synthetic"},
+		StringArgs: map[string]string{"region": "us"},
+	}})
+	assert.Equal([]*pb.FilterResult{
+		{Filter: `default string_equals_filter region = "eu"`, Passed: false, TicketValue: "us"},
+		{Filter: "default tag_present_filter verified", Passed: true, TicketValue: "verified,synthetic"},
+		{Filter: "default tag_absent_filter synthetic", Passed: false, TicketValue: "verified,synthetic"},
+	}, results)
+}
@@ -0,0 +1,86 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"math"
+	"sort"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// maxHistogramBounds bounds the buckets of a GetPoolStats histogram.
+const maxHistogramBounds = 1000
+
+// histogram counts tickets per bucket of their value of a double arg.
+type histogram struct {
+	arg     string
+	bounds  []float64
+	counts  []int64
+	missing int64
+}
+
+// newHistogram returns the histogram requested with arg and bounds, or nil if
+// none was requested.
+func newHistogram(arg string, bounds []float64) (*histogram, error) {
+	if arg == "" {
+		if len(bounds) > 0 {
+			return nil, status.Error(codes.InvalidArgument, ".histogram_bounds requires .histogram_double_arg")
+		}
+		return nil, nil
+	}
+	if len(bounds) > maxHistogramBounds {
+		return nil, status.Errorf(codes.InvalidArgument, ".histogram_bounds has more than %d bounds", maxHistogramBounds)
+	}
+	for i, b := range bounds {
+		if math.IsNaN(b) || (i > 0 && b <= bounds[i-1]) {
+			return nil, status.Error(codes.InvalidArgument, ".histogram_bounds must be strictly ascending")
+		}
+	}
+	return &histogram{arg: arg, bounds: bounds, counts: make([]int64, len(bounds)+1)}, nil
+}
+
+// add counts the ticket in the bucket of its value.
+func (h *histogram) add(ticket *pb.Ticket) {
+	if h == nil {
+		return
+	}
+	v, ok := ticket.GetSearchFields().GetDoubleArgs()[h.arg]
+	if !ok || math.IsNaN(v) {
+		h.missing++
+		return
+	}
+	h.counts[sort.Search(len(h.bounds), func(i int) bool { return h.bounds[i] > v })]++
+}
+
+// fill sets the histogram of the response.
+func (h *histogram) fill(resp *pb.GetPoolStatsResponse) {
+	if h == nil {
+		return
+	}
+	for i, count := range h.counts {
+		bucket := &pb.HistogramBucket{Min: math.Inf(-1), Max: math.Inf(1), TicketCount: count}
+		if i > 0 {
+			bucket.Min = h.bounds[i-1]
+		}
+		if i < len(h.bounds) {
+			bucket.Max = h.bounds[i]
+		}
+		resp.Histogram = append(resp.Histogram, bucket)
+	}
+	resp.HistogramMissingCount = h.missing
+}
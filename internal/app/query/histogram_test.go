@@ -0,0 +1,71 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestHistogram(t *testing.T) {
+	assert := assert.New(t)
+	h, err := newHistogram("mmr", []float64{1000, 2000})
+	assert.Nil(err)
+
+	for _, v := range []float64{500, 1000, 1999, 2000, 3000, math.NaN()} {
+		h.add(&pb.Ticket{SearchFields: &pb.SearchFields{DoubleArgs: map[string]float64{"mmr": v}}})
+	}
+	h.add(&pb.Ticket{})
+
+	resp := &pb.GetPoolStatsResponse{}
+	h.fill(resp)
+	assert.Equal([]*pb.HistogramBucket{
+		{Min: math.Inf(-1), Max: 1000, TicketCount: 1},
+		{Min: 1000, Max: 2000, TicketCount: 2},
+		{Min: 2000, Max: math.Inf(1), TicketCount: 2},
+	}, resp.Histogram)
+	assert.Equal(int64(2), resp.HistogramMissingCount)
+}
+
+func TestNewHistogram(t *testing.T) {
+	assert := assert.New(t)
+
+	h, err := newHistogram("", nil)
+	assert.Nil(err)
+	assert.Nil(h)
+	// A nil histogram counts nothing.
+	h.add(&pb.Ticket{})
+	resp := &pb.GetPoolStatsResponse{}
+	h.fill(resp)
+	assert.Empty(resp.Histogram)
+
+	h, err = newHistogram("mmr", nil)
+	assert.Nil(err)
+	assert.Len(h.counts, 1)
+
+	_, err = newHistogram("", []float64{1})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+	_, err = newHistogram("mmr", []float64{2, 1})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+	_, err = newHistogram("mmr", []float64{1, 1})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+	_, err = newHistogram("mmr", []float64{math.NaN()})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+}
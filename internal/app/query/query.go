@@ -16,16 +16,41 @@ package query
 
 import (
 	"google.golang.org/grpc"
+	"open-match.dev/open-match/internal/clock"
 	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/mmfauth"
 	"open-match.dev/open-match/internal/rpc"
+	"open-match.dev/open-match/internal/statestore"
 	"open-match.dev/open-match/pkg/pb"
 )
 
 // BindService creates the query service and binds it to the serving harness.
 func BindService(p *rpc.ServerParams, cfg config.View) error {
+	return BindServiceWithClock(p, cfg, clock.Real())
+}
+
+// BindServiceWithClock creates the query service, using clk to expire the
+// ignore list, and binds it to the serving harness.
+func BindServiceWithClock(p *rpc.ServerParams, cfg config.View, clk clock.Clock) error {
+	mmfAuth, err := mmfauth.FromConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	defaults, err := newDefaultFilters(cfg)
+	if err != nil {
+		return err
+	}
+
+	store := statestore.NewWithClock(cfg, clk)
 	service := &queryService{
-		cfg: cfg,
-		tc:  newTicketCache(p, cfg),
+		cfg:       cfg,
+		store:     store,
+		tc:        newTicketCache(p, store),
+		mmfAuth:   mmfAuth,
+		defaults:  defaults,
+		clk:       clk,
+		snapshots: newSnapshotStore(cfg, store),
 	}
 
 	p.AddHandleFunc(func(s *grpc.Server) {
@@ -17,15 +17,20 @@ package query
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"go.opencensus.io/tag"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/clock"
 	"open-match.dev/open-match/internal/config"
 	"open-match.dev/open-match/internal/filter"
+	"open-match.dev/open-match/internal/mmfauth"
 	"open-match.dev/open-match/internal/rpc"
 	"open-match.dev/open-match/internal/statestore"
+	"open-match.dev/open-match/internal/telemetry"
 	"open-match.dev/open-match/pkg/pb"
 )
 
@@ -34,43 +39,166 @@ var (
 		"app":       "openmatch",
 		"component": "app.query",
 	})
+
+	profileKey  = tag.MustNewKey("profile")
+	functionKey = tag.MustNewKey("function")
+
+	mQueriesAuthenticated = telemetry.Counter("query/queries_authenticated", "QueryTickets calls made with a valid mmf token", profileKey, functionKey)
+	mQueriesRejected      = telemetry.Counter("query/queries_rejected", "QueryTickets calls rejected for a missing or invalid mmf token")
+	mSlowStreamsClosed    = telemetry.Counter("query/slow_streams_closed", "QueryTickets streams closed for not receiving pages in time")
 )
 
 // queryService API provides utility functions for common MMF functionality such
 // as retreiving Tickets from state storage.
 type queryService struct {
-	cfg config.View
-	tc  *ticketCache
+	cfg   config.View
+	store statestore.Service
+	tc    *ticketCache
+	// mmfAuth verifies tokens presented by match functions, nil if mmf
+	// authentication is disabled.
+	mmfAuth *mmfauth.Authority
+	// defaults are filters applied to every pool queried.
+	defaults *defaultFilters
+	// clk opens the event window of reserved tickets.
+	clk clock.Clock
+	// snapshots take the tickets queries are evaluated against when passing a
+	// consistency token.
+	snapshots *snapshotStore
 }
 
 func (s *queryService) QueryTickets(req *pb.QueryTicketsRequest, responseServer pb.QueryService_QueryTicketsServer) error {
-	pool := req.GetPool()
-	if pool == nil {
-		return status.Error(codes.InvalidArgument, ".pool is required")
+	mask, err := newTicketMask(req.GetFieldMask())
+	if err != nil {
+		return err
+	}
+	if err := validateOrder(req.GetOrderBy()); err != nil {
+		return err
+	}
+	pSize, err := requestPageSize(s.cfg, req)
+	if err != nil {
+		return err
+	}
+	if req.GetMaxResults() < 0 {
+		return status.Error(codes.InvalidArgument, ".max_results must not be negative")
+	}
+
+	// Bound the whole stream, so a match function which stops receiving can't
+	// hold on to the query results forever.
+	ctx, cancel := context.WithTimeout(responseServer.Context(), getStreamTimeout(s.cfg))
+	defer cancel()
+
+	results, token, err := s.queryPool(ctx, req.GetPool(), req.GetTakeSnapshot(), req.GetConsistencyToken())
+	if err != nil {
+		return err
+	}
+	sortTickets(results, req.GetOrderBy())
+	if max := int(req.GetMaxResults()); max > 0 && len(results) > max {
+		results = results[:max]
+	}
+
+	return s.sendPages(ctx, len(results), pSize, token != "", func(start, end int) error {
+		page := results[start:end]
+		if mask != nil {
+			page = make([]*pb.Ticket, 0, end-start)
+			for _, ticket := range results[start:end] {
+				page = append(page, mask.apply(ticket))
+			}
+		}
+		return responseServer.Send(&pb.QueryTicketsResponse{Tickets: page, ConsistencyToken: token})
+	})
+}
+
+// QueryTicketIds returns the ids of the tickets QueryTickets would return.
+func (s *queryService) QueryTicketIds(req *pb.QueryTicketIdsRequest, responseServer pb.QueryService_QueryTicketIdsServer) error {
+	ctx, cancel := context.WithTimeout(responseServer.Context(), getStreamTimeout(s.cfg))
+	defer cancel()
+
+	results, token, err := s.queryPool(ctx, req.GetPool(), req.GetTakeSnapshot(), req.GetConsistencyToken())
+	if err != nil {
+		return err
+	}
+
+	return s.sendPages(ctx, len(results), getPageSize(s.cfg), token != "", func(start, end int) error {
+		ids := make([]string, 0, end-start)
+		for _, ticket := range results[start:end] {
+			ids = append(ids, ticket.GetId())
+		}
+		return responseServer.Send(&pb.QueryTicketIdsResponse{Ids: ids, ConsistencyToken: token})
+	})
+}
+
+// queryPool returns the cached tickets in the pool, after authenticating the
+// caller.  The tickets are those of the snapshot with the consistency token if
+// one is given, and are snapshotted if takeSnapshot is set, returning the
+// snapshot's token.
+func (s *queryService) queryPool(ctx context.Context, pool *pb.Pool, takeSnapshot bool, token string) ([]*pb.Ticket, string, error) {
+	if err := validatePool(pool); err != nil {
+		return nil, "", err
+	}
+	if takeSnapshot && token != "" {
+		return nil, "", status.Error(codes.InvalidArgument, ".take_snapshot and .consistency_token are mutually exclusive")
+	}
+
+	if err := s.authenticate(ctx); err != nil {
+		return nil, "", err
 	}
 
+	pool = s.defaults.apply(pool)
+
 	var results []*pb.Ticket
-	err := s.tc.request(responseServer.Context(), func(tickets map[string]*pb.Ticket) {
+	now := s.clk.Now()
+	collect := func(tickets map[string]*pb.Ticket) {
 		for _, ticket := range tickets {
-			if filter.InPool(ticket, pool) {
+			if s.defaults.inPool(ticket, pool) && reservationAllows(ticket, pool, now) {
 				results = append(results, ticket)
 			}
 		}
-	})
+	}
+
+	// Queries taking a snapshot are evaluated against it, so that they see the
+	// same tickets as those passing its token.
+	if takeSnapshot {
+		var err error
+		if token, err = s.snapshots.take(ctx); err != nil {
+			logger.WithError(err).Error("Failed to take a snapshot.")
+			return nil, "", err
+		}
+	}
+	if token != "" {
+		tickets, err := s.snapshots.get(ctx, token, s.tc.request)
+		if err != nil {
+			return nil, "", err
+		}
+		collect(tickets)
+		return results, token, nil
+	}
+
+	err := s.tc.request(ctx, collect)
 	if err != nil {
 		logger.WithError(err).Error("Failed to run request.")
-		return err
+		return nil, "", err
 	}
+	return results, token, nil
+}
 
-	pSize := getPageSize(s.cfg)
-	for start := 0; start < len(results); start += pSize {
+// sendPages calls send with the bounds of each page of n results, pSize at a
+// time, giving up on slow clients as per sendPage.  An empty page is sent for
+// no results if sendEmpty is set, eg. to return a consistency token.
+func (s *queryService) sendPages(ctx context.Context, n int, pSize int, sendEmpty bool, send func(start, end int) error) error {
+	sendTimeout := getPageSendTimeout(s.cfg)
+	if n == 0 && sendEmpty {
+		return sendPage(ctx, sendTimeout, func() error {
+			return send(0, 0)
+		})
+	}
+	for start := 0; start < n; start += pSize {
 		end := start + pSize
-		if end > len(results) {
-			end = len(results)
+		if end > n {
+			end = n
 		}
 
-		err := responseServer.Send(&pb.QueryTicketsResponse{
-			Tickets: results[start:end],
+		err := sendPage(ctx, sendTimeout, func() error {
+			return send(start, end)
 		})
 		if err != nil {
 			return err
@@ -80,6 +208,153 @@ func (s *queryService) QueryTickets(req *pb.QueryTicketsRequest, responseServer
 	return nil
 }
 
+// GetPoolStats counts the tickets in the pool using the statestore's indices,
+// rather than the ticket cache, so that it's cheap to call frequently.  It
+// doesn't require an mmf token as no tickets are returned.  Default tagAbsent
+// filters aren't applied, as the indices can't count them.  Neither can they
+// count filter expressions, build histograms nor tell whether an event has
+// started, so those and pools selecting an event's reserved tickets are
+// computed from the ticket cache, without the tickets currently proposed.
+func (s *queryService) GetPoolStats(ctx context.Context, req *pb.GetPoolStatsRequest) (*pb.GetPoolStatsResponse, error) {
+	pool := req.GetPool()
+	if err := validatePool(pool); err != nil {
+		return nil, err
+	}
+	h, err := newHistogram(req.GetHistogramDoubleArg(), req.GetHistogramBounds())
+	if err != nil {
+		return nil, err
+	}
+
+	if pool.GetFilterExpression() != nil || h != nil || selectsEvent(pool) {
+		pool = s.defaults.apply(pool)
+		resp := &pb.GetPoolStatsResponse{}
+		now := s.clk.Now()
+		err := s.tc.request(ctx, func(tickets map[string]*pb.Ticket) {
+			for _, ticket := range tickets {
+				if s.defaults.inPool(ticket, pool) && reservationAllows(ticket, pool, now) {
+					resp.TicketCount++
+					h.add(ticket)
+				}
+			}
+		})
+		if err != nil {
+			logger.WithError(err).Error("Failed to run request.")
+			return nil, err
+		}
+		h.fill(resp)
+		return resp, nil
+	}
+
+	// Reserved tickets are never in pools which don't select their event.
+	pool = s.defaults.apply(pool)
+	count, err := s.store.CountTickets(ctx, pool)
+	if err != nil {
+		logger.WithError(err).Error("Failed to count tickets.")
+		return nil, err
+	}
+	reserved, err := s.store.CountTickets(ctx, onlyReserved(pool))
+	if err != nil {
+		logger.WithError(err).Error("Failed to count reserved tickets.")
+		return nil, err
+	}
+	return &pb.GetPoolStatsResponse{TicketCount: count - reserved}, nil
+}
+
+// ExplainTicket reports which criteria of the input Pool the Ticket passes or fails, with the Ticket's values.
+//   - Intended for debugging Tickets which never match, it checks the same criteria as QueryTickets.
+func (s *queryService) ExplainTicket(ctx context.Context, req *pb.ExplainTicketRequest) (*pb.ExplainTicketResponse, error) {
+	if req.GetTicketId() == "" {
+		return nil, status.Error(codes.InvalidArgument, ".ticket_id is required")
+	}
+	pool := req.GetPool()
+	if err := validatePool(pool); err != nil {
+		return nil, err
+	}
+
+	ticket, err := s.store.GetTicket(statestore.WithReplicaReads(ctx), req.GetTicketId())
+	if err != nil {
+		return nil, err
+	}
+
+	results := filter.Explain(ticket, pool)
+	results = append(results, s.defaults.explain(ticket)...)
+	if r := explainReservation(ticket, pool, s.clk.Now()); r != nil {
+		results = append(results, r)
+	}
+
+	resp := &pb.ExplainTicketResponse{InPool: true, FilterResults: results}
+	for _, r := range results {
+		resp.InPool = resp.InPool && r.GetPassed()
+	}
+
+	err = s.tc.request(ctx, func(tickets map[string]*pb.Ticket) {
+		_, resp.Available = tickets[ticket.GetId()]
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to run request.")
+		return nil, err
+	}
+	return resp, nil
+}
+
+// validatePool checks the pool of a request is present and well formed.
+func validatePool(pool *pb.Pool) error {
+	if pool == nil {
+		return status.Error(codes.InvalidArgument, ".pool is required")
+	}
+	if err := filter.ValidatePool(pool); err != nil {
+		return status.Errorf(codes.InvalidArgument, ".pool: %v", err)
+	}
+	return nil
+}
+
+// sendPage sends a page of the stream with send, giving up if the client
+// doesn't make room for it within timeout or the stream's deadline passes.
+// Returning the error ends the stream, which unblocks the pending Send.
+func sendPage(ctx context.Context, timeout time.Duration, send func() error) error {
+	sent := make(chan error, 1)
+	go func() {
+		sent <- send()
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-sent:
+		return err
+	case <-timer.C:
+		logger.Warningf("closing QueryTickets stream, client did not receive a page within %v", timeout)
+		telemetry.RecordUnitMeasurement(ctx, mSlowStreamsClosed)
+		return status.Errorf(codes.DeadlineExceeded, "client did not receive a page within %v, closing slow stream", timeout)
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			logger.Warning("closing QueryTickets stream, stream deadline exceeded")
+			telemetry.RecordUnitMeasurement(ctx, mSlowStreamsClosed)
+			return status.Error(codes.DeadlineExceeded, "stream deadline exceeded before all pages were received, closing slow stream")
+		}
+		return status.Errorf(codes.Canceled, "%v", ctx.Err())
+	}
+}
+
+// authenticate checks the caller presented a token issued by the backend for a
+// live FetchMatches call, when mmf authentication is enabled.
+func (s *queryService) authenticate(ctx context.Context) error {
+	if s.mmfAuth == nil {
+		return nil
+	}
+
+	claims, err := s.mmfAuth.VerifyIncoming(ctx)
+	if err != nil {
+		logger.WithError(err).Warning("rejected QueryTickets call without a valid mmf token")
+		telemetry.RecordUnitMeasurement(ctx, mQueriesRejected)
+		return err
+	}
+
+	telemetry.RecordUnitMeasurement(ctx, mQueriesAuthenticated, tag.Insert(profileKey, claims.Profile), tag.Insert(functionKey, claims.Function))
+	return nil
+}
+
 func getPageSize(cfg config.View) int {
 	const (
 		name = "storage.page.size"
@@ -112,6 +387,60 @@ func getPageSize(cfg config.View) int {
 	return pSize
 }
 
+// requestPageSize returns the page size asked for by the request, capped by
+// storage.page.maxSize, or the configured page size if it doesn't ask for one.
+func requestPageSize(cfg config.View, req *pb.QueryTicketsRequest) (int, error) {
+	const (
+		name = "storage.page.maxSize"
+		// Default largest page size requests may ask for.
+		defaultMaxSize = 10000
+	)
+
+	pSize := int(req.GetPageSize())
+	if pSize < 0 {
+		return 0, status.Error(codes.InvalidArgument, ".page_size must not be negative")
+	}
+	if pSize == 0 {
+		return getPageSize(cfg), nil
+	}
+
+	maxSize := defaultMaxSize
+	if cfg.IsSet(name) {
+		maxSize = cfg.GetInt(name)
+	}
+	if pSize > maxSize {
+		return maxSize, nil
+	}
+	return pSize, nil
+}
+
+func getPageSendTimeout(cfg config.View) time.Duration {
+	const (
+		name = "storage.page.sendTimeout"
+		// Default time a client has to receive each page of a QueryTickets stream.
+		defaultSendTimeout = 10 * time.Second
+	)
+
+	if !cfg.IsSet(name) {
+		return defaultSendTimeout
+	}
+	return cfg.GetDuration(name)
+}
+
+func getStreamTimeout(cfg config.View) time.Duration {
+	const (
+		name = "storage.page.streamTimeout"
+		// Default time a QueryTickets stream may stay open, including waiting
+		// for the ticket cache.
+		defaultStreamTimeout = time.Minute
+	)
+
+	if !cfg.IsSet(name) {
+		return defaultStreamTimeout
+	}
+	return cfg.GetDuration(name)
+}
+
 /////////////////////////////////////////////////////////////////////
 /////////////////////////////////////////////////////////////////////
 
@@ -132,12 +461,15 @@ type ticketCache struct {
 	// Mutlithreaded unsafe fields, only to be written by update, and read when
 	// request given the ok.
 	tickets map[string]*pb.Ticket
-	err     error
+	// revision of the stored tickets when the cache was filled.  Tickets are
+	// refetched when it changes, as they've been rewritten in place.
+	revision int64
+	err      error
 }
 
-func newTicketCache(p *rpc.ServerParams, cfg config.View) *ticketCache {
+func newTicketCache(p *rpc.ServerParams, store statestore.Service) *ticketCache {
 	tc := &ticketCache{
-		store:           statestore.New(cfg),
+		store:           store,
 		requests:        make(chan *cacheRequest),
 		startRunRequest: make(chan struct{}, 1),
 		tickets:         make(map[string]*pb.Ticket),
@@ -224,9 +556,23 @@ collectAllWaiting:
 }
 
 func (tc *ticketCache) update() {
+	revision, err := tc.store.GetTicketsRevision(context.Background())
+	if err != nil {
+		tc.err = err
+		return
+	}
+	if revision != tc.revision {
+		logger.Infof("Stored tickets were rewritten, refetching all %d cached tickets", len(tc.tickets))
+		tc.tickets = make(map[string]*pb.Ticket)
+		tc.revision = revision
+	}
+
 	previousCount := len(tc.tickets)
 
-	currentAll, err := tc.store.GetIndexedIDSet(context.Background())
+	// The cache tolerates lagging reads, which are made on the read replica
+	// if one is configured.
+	ctx := statestore.WithReplicaReads(context.Background())
+	currentAll, err := tc.store.GetIndexedIDSet(ctx)
 	if err != nil {
 		tc.err = err
 		return
@@ -248,7 +594,7 @@ func (tc *ticketCache) update() {
 		}
 	}
 
-	newTickets, err := tc.store.GetTickets(context.Background(), toFetch)
+	newTickets, err := tc.store.GetTickets(ctx, toFetch)
 	if err != nil {
 		tc.err = err
 		return
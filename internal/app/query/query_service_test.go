@@ -15,10 +15,21 @@
 package query
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/clock"
 	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/rpc"
+	statestoreTesting "open-match.dev/open-match/internal/statestore/testing"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
 )
 
 func TestGetPageSize(t *testing.T) {
@@ -67,3 +78,114 @@ func TestGetPageSize(t *testing.T) {
 		})
 	}
 }
+
+type blockingStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	receive chan struct{}
+}
+
+func (s *blockingStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *blockingStream) Send(*pb.QueryTicketsResponse) error {
+	select {
+	case <-s.receive:
+		return nil
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
+
+func TestRequestPageSize(t *testing.T) {
+	assert := assert.New(t)
+	cfg := viper.New()
+	cfg.Set("storage.page.size", 100)
+	cfg.Set("storage.page.maxSize", 500)
+
+	pSize, err := requestPageSize(cfg, &pb.QueryTicketsRequest{})
+	assert.Nil(err)
+	assert.Equal(100, pSize)
+
+	pSize, err = requestPageSize(cfg, &pb.QueryTicketsRequest{PageSize: 1})
+	assert.Nil(err)
+	assert.Equal(1, pSize)
+
+	pSize, err = requestPageSize(cfg, &pb.QueryTicketsRequest{PageSize: 1000})
+	assert.Nil(err)
+	assert.Equal(500, pSize)
+
+	_, err = requestPageSize(cfg, &pb.QueryTicketsRequest{PageSize: -1})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func sendEmptyPage(stream pb.QueryService_QueryTicketsServer) func() error {
+	return func() error {
+		return stream.Send(&pb.QueryTicketsResponse{})
+	}
+}
+
+func TestSendPage(t *testing.T) {
+	assert := assert.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := &blockingStream{ctx: ctx, receive: make(chan struct{}, 1)}
+
+	stream.receive <- struct{}{}
+	assert.Nil(sendPage(ctx, time.Second, sendEmptyPage(stream)))
+
+	// The client stops receiving, the page times out.
+	err := sendPage(ctx, 10*time.Millisecond, sendEmptyPage(stream))
+	assert.Equal(codes.DeadlineExceeded, status.Code(err))
+
+	// The stream deadline passes before the page times out.
+	streamCtx, streamCancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer streamCancel()
+	err = sendPage(streamCtx, time.Minute, sendEmptyPage(stream))
+	assert.Equal(codes.DeadlineExceeded, status.Code(err))
+	assert.Contains(status.Convert(err).Message(), "stream deadline exceeded")
+}
+
+func TestGetPoolStatsReservations(t *testing.T) {
+	assert := assert.New(t)
+	cfg := viper.New()
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, cfg)
+	defer closer()
+	defer store.Close()
+	ctx := utilTesting.NewContext(t)
+	clk := clock.NewVirtual(time.Unix(0, 0))
+	defaults, err := newDefaultFilters(cfg)
+	assert.Nil(err)
+	s := &queryService{
+		cfg:      cfg,
+		store:    store,
+		tc:       newTicketCache(&rpc.ServerParams{}, store),
+		defaults: defaults,
+		clk:      clk,
+	}
+
+	for _, id := range []string{"a", "b"} {
+		ticket := &pb.Ticket{Id: id, SearchFields: &pb.SearchFields{Tags: []string{"ranked"}}}
+		if id == "b" {
+			pb.ReserveForEvent(ticket, "cup", clk.Now().Add(time.Hour))
+		}
+		assert.Nil(store.CreateTicket(ctx, ticket))
+		assert.Nil(store.IndexTicket(ctx, ticket))
+	}
+
+	count := func(pool *pb.Pool) int64 {
+		resp, err := s.GetPoolStats(ctx, &pb.GetPoolStatsRequest{Pool: pool})
+		assert.Nil(err)
+		return resp.GetTicketCount()
+	}
+	normal := &pb.Pool{TagPresentFilters: []*pb.TagPresentFilter{{Tag: "ranked"}}}
+	event := &pb.Pool{TagPresentFilters: []*pb.TagPresentFilter{{Tag: pb.EventTag("cup")}}}
+
+	assert.Equal(int64(1), count(normal))
+	assert.Equal(int64(0), count(event))
+
+	clk.Advance(time.Hour)
+	assert.Equal(int64(1), count(normal))
+	assert.Equal(int64(1), count(event))
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// reservationAllows returns whether a ticket reserved for a scheduled event
+// may be included in pool at now.  Reserved tickets are held back until the
+// event starts, and are then only included in pools selecting the event's tag,
+// so that normal profiles never see them.  Unreserved tickets are always
+// allowed.
+func reservationAllows(ticket *pb.Ticket, pool *pb.Pool, now time.Time) bool {
+	eventID, start, ok := pb.ReservedEvent(ticket)
+	if !ok {
+		return true
+	}
+	if now.Before(start) {
+		return false
+	}
+
+	eventTag := pb.EventTag(eventID)
+	for _, f := range pool.GetTagPresentFilters() {
+		if f.GetTag() == eventTag {
+			return true
+		}
+	}
+	return false
+}
+
+// selectsEvent returns whether the pool selects the tickets reserved for an
+// event, which are only in it once the event started.
+func selectsEvent(pool *pb.Pool) bool {
+	for _, f := range pool.GetTagPresentFilters() {
+		if strings.HasPrefix(f.GetTag(), pb.EventTagPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// onlyReserved returns a copy of pool further restricted to reserved tickets.
+func onlyReserved(pool *pb.Pool) *pb.Pool {
+	reserved := proto.Clone(pool).(*pb.Pool)
+	reserved.DoubleRangeFilters = append(reserved.DoubleRangeFilters, &pb.DoubleRangeFilter{
+		DoubleArg: pb.EventStartArg,
+		Min:       math.Inf(-1),
+		Max:       math.Inf(1),
+	})
+	return reserved
+}
+
+// explainReservation returns the result of the ticket's event reservation for
+// pool at now, nil if the ticket isn't reserved.
+func explainReservation(ticket *pb.Ticket, pool *pb.Pool, now time.Time) *pb.FilterResult {
+	eventID, start, ok := pb.ReservedEvent(ticket)
+	if !ok {
+		return nil
+	}
+	return &pb.FilterResult{
+		Filter:      fmt.Sprintf("reserved for event %s from %s, requires tag_present_filter %s", eventID, start.UTC().Format(time.RFC3339), pb.EventTag(eventID)),
+		Passed:      reservationAllows(ticket, pool, now),
+		TicketValue: pb.EventTag(eventID),
+	}
+}
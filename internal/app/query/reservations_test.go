@@ -0,0 +1,66 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestReservationAllows(t *testing.T) {
+	assert := assert.New(t)
+	start := time.Unix(1000, 0)
+
+	reserved := &pb.Ticket{SearchFields: &pb.SearchFields{Tags: []string{"ranked"}}}
+	pb.ReserveForEvent(reserved, "cup", start)
+	normal := &pb.Pool{Name: "normal", TagPresentFilters: []*pb.TagPresentFilter{{Tag: "ranked"}}}
+	event := &pb.Pool{Name: "event", TagPresentFilters: []*pb.TagPresentFilter{{Tag: pb.EventTag("cup")}}}
+	other := &pb.Pool{Name: "other", TagPresentFilters: []*pb.TagPresentFilter{{Tag: pb.EventTag("league")}}}
+
+	assert.False(reservationAllows(reserved, event, start.Add(-time.Second)), "held back before the event starts")
+	assert.False(reservationAllows(reserved, normal, start.Add(-time.Second)))
+	assert.True(reservationAllows(reserved, event, start))
+	assert.False(reservationAllows(reserved, normal, start), "excluded from normal pools once the event starts")
+	assert.False(reservationAllows(reserved, other, start))
+
+	unreserved := &pb.Ticket{SearchFields: &pb.SearchFields{Tags: []string{"ranked"}}}
+	assert.True(reservationAllows(unreserved, normal, start))
+
+	// Reserving again replaces the previous reservation.
+	pb.ReserveForEvent(reserved, "league", start.Add(time.Hour))
+	assert.ElementsMatch([]string{"ranked", pb.EventTag("league")}, reserved.GetSearchFields().GetTags())
+	assert.False(reservationAllows(reserved, other, start))
+	assert.True(reservationAllows(reserved, other, start.Add(time.Hour)))
+	assert.False(reservationAllows(reserved, event, start.Add(time.Hour)))
+}
+
+func TestExplainReservation(t *testing.T) {
+	assert := assert.New(t)
+	start := time.Unix(1000, 0)
+	pool := &pb.Pool{Name: "event", TagPresentFilters: []*pb.TagPresentFilter{{Tag: pb.EventTag("cup")}}}
+
+	assert.Nil(explainReservation(&pb.Ticket{}, pool, start))
+
+	ticket := &pb.Ticket{}
+	pb.ReserveForEvent(ticket, "cup", start)
+	r := explainReservation(ticket, pool, start.Add(-time.Second))
+	assert.False(r.GetPassed())
+	assert.Equal(pb.EventTag("cup"), r.GetTicketValue())
+	assert.Contains(r.GetFilter(), "1970-01-01T00:16:40Z")
+	assert.True(explainReservation(ticket, pool, start).GetPassed())
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/xid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/statestore"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// snapshotStore takes snapshots of the indexed tickets, so that later queries
+// passing their consistency token see the same tickets, whichever replica
+// serves them.  Snapshots are kept in state storage, and only hold the ids of
+// the tickets, which are read from the ticket cache, or from state storage if
+// the cache doesn't hold them.
+type snapshotStore struct {
+	store statestore.Service
+	ttl   time.Duration
+}
+
+func newSnapshotStore(cfg config.View, store statestore.Service) *snapshotStore {
+	return &snapshotStore{
+		store: store,
+		ttl:   getSnapshotTTL(cfg),
+	}
+}
+
+// take snapshots the indexed tickets, returning the snapshot's consistency
+// token.
+func (ss *snapshotStore) take(ctx context.Context) (string, error) {
+	token := xid.New().String()
+	if err := ss.store.TakeIndexSnapshot(ctx, token, ss.ttl); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// get returns the tickets of the snapshot with the consistency token, taking
+// those held by the ticket cache from it, as passed to request's callback.
+// Tickets deleted since the snapshot was taken are left out.
+func (ss *snapshotStore) get(ctx context.Context, token string, request func(context.Context, func(map[string]*pb.Ticket)) error) (map[string]*pb.Ticket, error) {
+	ids, err := ss.store.GetIndexSnapshot(ctx, token)
+	if status.Code(err) == codes.NotFound {
+		return nil, status.Errorf(codes.FailedPrecondition, "snapshot %q is unknown or expired", token)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tickets := make(map[string]*pb.Ticket, len(ids))
+	var missing []string
+	err = request(ctx, func(cache map[string]*pb.Ticket) {
+		for id := range ids {
+			if ticket, ok := cache[id]; ok {
+				tickets[id] = ticket
+			} else {
+				missing = append(missing, id)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(missing) > 0 {
+		fetched, err := ss.store.GetTickets(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+		for _, ticket := range fetched {
+			tickets[ticket.GetId()] = ticket
+		}
+	}
+	return tickets, nil
+}
+
+func getSnapshotTTL(cfg config.View) time.Duration {
+	const (
+		name = "query.snapshotTTL"
+		// Default time snapshots of the indexed tickets are kept, covering a
+		// match function run.
+		defaultSnapshotTTL = 30 * time.Second
+	)
+
+	if !cfg.IsSet(name) {
+		return defaultSnapshotTTL
+	}
+	return cfg.GetDuration(name)
+}
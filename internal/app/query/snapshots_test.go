@@ -0,0 +1,66 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	statestoreTesting "open-match.dev/open-match/internal/statestore/testing"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestSnapshotStore(t *testing.T) {
+	assert := assert.New(t)
+	cfg := viper.New()
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, cfg)
+	defer closer()
+	defer store.Close()
+	ctx := utilTesting.NewContext(t)
+
+	tickets := []*pb.Ticket{{Id: "a"}, {Id: "b"}}
+	assert.Nil(store.CreateTickets(ctx, tickets))
+	assert.Nil(store.IndexTickets(ctx, tickets))
+
+	// Snapshots taken by one replica are seen by the others.
+	token, err := newSnapshotStore(cfg, store).take(ctx)
+	assert.Nil(err)
+	other := newSnapshotStore(cfg, store)
+
+	// Later changes to the index aren't seen through the snapshot, tickets
+	// missing from the cache are read from state storage, and deleted ones are
+	// left out.
+	assert.Nil(store.CreateTicket(ctx, &pb.Ticket{Id: "c"}))
+	assert.Nil(store.IndexTicket(ctx, &pb.Ticket{Id: "c"}))
+	assert.Nil(store.DeleteTicket(ctx, "b"))
+	cached := map[string]*pb.Ticket{"c": {Id: "c"}}
+	snapshot, err := other.get(ctx, token, func(_ context.Context, f func(map[string]*pb.Ticket)) error {
+		f(cached)
+		return nil
+	})
+	assert.Nil(err)
+	assert.Len(snapshot, 1)
+	assert.Contains(snapshot, "a")
+
+	_, err = other.get(ctx, "unknown", func(context.Context, func(map[string]*pb.Ticket)) error {
+		return nil
+	})
+	assert.Equal(codes.FailedPrecondition, status.Code(err))
+}
@@ -0,0 +1,104 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"strings"
+
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// searchFieldsPrefix prefixes field mask paths selecting fields of a ticket's
+// search fields.
+const searchFieldsPrefix = "search_fields."
+
+// ticketMask trims the tickets returned by QueryTickets down to the fields of
+// the request's field mask.
+type ticketMask struct {
+	paths map[string]bool
+}
+
+// newTicketMask returns the mask of the field mask's paths, or nil if all
+// fields are to be returned.
+func newTicketMask(fm *field_mask.FieldMask) (*ticketMask, error) {
+	if len(fm.GetPaths()) == 0 {
+		return nil, nil
+	}
+
+	m := &ticketMask{paths: make(map[string]bool, len(fm.GetPaths()))}
+	for _, path := range fm.GetPaths() {
+		switch path {
+		case "id", "assignment", "search_fields", "extensions", "player_ids", "avoid_ids", "assignment_failures", "create_time",
+			"search_fields.double_args", "search_fields.string_args", "search_fields.tags":
+			m.paths[path] = true
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, ".field_mask has unknown path %q", path)
+		}
+	}
+	return m, nil
+}
+
+// apply returns a copy of the ticket holding only the masked fields and its
+// id.  The fields are shared with the ticket, which must not be modified.
+func (m *ticketMask) apply(ticket *pb.Ticket) *pb.Ticket {
+	t := &pb.Ticket{Id: ticket.GetId()}
+	if m.paths["assignment"] {
+		t.Assignment = ticket.GetAssignment()
+	}
+	if m.paths["extensions"] {
+		t.Extensions = ticket.GetExtensions()
+	}
+	if m.paths["player_ids"] {
+		t.PlayerIds = ticket.GetPlayerIds()
+	}
+	if m.paths["avoid_ids"] {
+		t.AvoidIds = ticket.GetAvoidIds()
+	}
+	if m.paths["assignment_failures"] {
+		t.AssignmentFailures = ticket.GetAssignmentFailures()
+	}
+	if m.paths["create_time"] {
+		t.CreateTime = ticket.GetCreateTime()
+	}
+
+	if m.paths["search_fields"] {
+		t.SearchFields = ticket.GetSearchFields()
+		return t
+	}
+	s := ticket.GetSearchFields()
+	if s == nil {
+		return t
+	}
+	for path := range m.paths {
+		if !strings.HasPrefix(path, searchFieldsPrefix) {
+			continue
+		}
+		if t.SearchFields == nil {
+			t.SearchFields = &pb.SearchFields{}
+		}
+		switch strings.TrimPrefix(path, searchFieldsPrefix) {
+		case "double_args":
+			t.SearchFields.DoubleArgs = s.GetDoubleArgs()
+		case "string_args":
+			t.SearchFields.StringArgs = s.GetStringArgs()
+		case "tags":
+			t.SearchFields.Tags = s.GetTags()
+		}
+	}
+	return t
+}
@@ -0,0 +1,62 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestTicketMask(t *testing.T) {
+	assert := assert.New(t)
+	ticket := &pb.Ticket{
+		Id:         "1",
+		Assignment: &pb.Assignment{Connection: "127.0.0.1:7777"},
+		SearchFields: &pb.SearchFields{
+			DoubleArgs: map[string]float64{"mmr": 1500},
+			StringArgs: map[string]string{"mode": "ranked"},
+			Tags:       []string{"beta"},
+		},
+		PlayerIds: []string{"a", "b"},
+	}
+
+	m, err := newTicketMask(nil)
+	assert.Nil(err)
+	assert.Nil(m)
+
+	m, err = newTicketMask(&field_mask.FieldMask{Paths: []string{"player_ids", "search_fields.double_args"}})
+	assert.Nil(err)
+	assert.True(proto.Equal(&pb.Ticket{
+		Id:           "1",
+		SearchFields: &pb.SearchFields{DoubleArgs: map[string]float64{"mmr": 1500}},
+		PlayerIds:    []string{"a", "b"},
+	}, m.apply(ticket)))
+
+	m, err = newTicketMask(&field_mask.FieldMask{Paths: []string{"search_fields", "search_fields.tags"}})
+	assert.Nil(err)
+	assert.True(proto.Equal(&pb.Ticket{Id: "1", SearchFields: ticket.SearchFields}, m.apply(ticket)))
+
+	// Masking leaves the cached ticket intact.
+	assert.Equal("127.0.0.1:7777", ticket.GetAssignment().GetConnection())
+
+	_, err = newTicketMask(&field_mask.FieldMask{Paths: []string{"search_fields.unknown"}})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"math"
+	"sort"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// validateOrder checks the order of a QueryTickets request is well formed.
+func validateOrder(order *pb.TicketOrder) error {
+	if order == nil {
+		return nil
+	}
+	if (order.GetDoubleArg() != "") == order.GetCreateTime() {
+		return status.Error(codes.InvalidArgument, ".order_by must set exactly one of double_arg and create_time")
+	}
+	return nil
+}
+
+// sortTickets sorts the tickets in the order, breaking ties by id so that
+// the order is stable across calls.  Tickets without the sort key are last.
+func sortTickets(tickets []*pb.Ticket, order *pb.TicketOrder) {
+	if order == nil {
+		return
+	}
+
+	type keyed struct {
+		ticket *pb.Ticket
+		// key is the double arg or, for create time, nanos.
+		key   float64
+		nanos int64
+		ok    bool
+	}
+	ks := make([]keyed, len(tickets))
+	for i, t := range tickets {
+		ks[i].ticket = t
+		if order.GetCreateTime() {
+			if ct, err := ptypes.Timestamp(t.GetCreateTime()); err == nil {
+				ks[i].nanos, ks[i].ok = ct.UnixNano(), true
+			}
+			continue
+		}
+		v, ok := t.GetSearchFields().GetDoubleArgs()[order.GetDoubleArg()]
+		ks[i].key, ks[i].ok = v, ok && !math.IsNaN(v)
+	}
+
+	sort.Slice(ks, func(i, j int) bool {
+		a, b := ks[i], ks[j]
+		if a.ok != b.ok {
+			return a.ok
+		}
+		if a.ok && (a.key != b.key || a.nanos != b.nanos) {
+			less := a.key < b.key || (a.key == b.key && a.nanos < b.nanos)
+			return less != order.GetDescending()
+		}
+		return a.ticket.GetId() < b.ticket.GetId()
+	})
+
+	for i := range ks {
+		tickets[i] = ks[i].ticket
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestSortTickets(t *testing.T) {
+	assert := assert.New(t)
+	mmr := func(id string, v float64) *pb.Ticket {
+		return &pb.Ticket{Id: id, SearchFields: &pb.SearchFields{DoubleArgs: map[string]float64{"mmr": v}}}
+	}
+	ids := func(tickets []*pb.Ticket) []string {
+		var r []string
+		for _, t := range tickets {
+			r = append(r, t.GetId())
+		}
+		return r
+	}
+
+	tickets := []*pb.Ticket{mmr("c", 1500), {Id: "none"}, mmr("a", 1200), mmr("nan", math.NaN()), mmr("b", 1500)}
+	sortTickets(tickets, &pb.TicketOrder{DoubleArg: "mmr"})
+	assert.Equal([]string{"a", "b", "c", "nan", "none"}, ids(tickets))
+
+	// Tickets without the arg stay last, ties stay ordered by id.
+	sortTickets(tickets, &pb.TicketOrder{DoubleArg: "mmr", Descending: true})
+	assert.Equal([]string{"b", "c", "a", "nan", "none"}, ids(tickets))
+
+	tickets = []*pb.Ticket{
+		{Id: "new", CreateTime: &timestamp.Timestamp{Seconds: 10, Nanos: 2}},
+		{Id: "old", CreateTime: &timestamp.Timestamp{Seconds: 10, Nanos: 1}},
+		{Id: "unknown"},
+	}
+	sortTickets(tickets, &pb.TicketOrder{CreateTime: true})
+	assert.Equal([]string{"old", "new", "unknown"}, ids(tickets))
+}
+
+func TestValidateOrder(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(validateOrder(nil))
+	assert.Nil(validateOrder(&pb.TicketOrder{DoubleArg: "mmr"}))
+	assert.Nil(validateOrder(&pb.TicketOrder{CreateTime: true, Descending: true}))
+	assert.Equal(codes.InvalidArgument, status.Code(validateOrder(&pb.TicketOrder{})))
+	assert.Equal(codes.InvalidArgument, status.Code(validateOrder(&pb.TicketOrder{DoubleArg: "mmr", CreateTime: true})))
+}
@@ -0,0 +1,123 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/statestore"
+	"open-match.dev/open-match/internal/telemetry"
+)
+
+var (
+	// BuildVersion is the Open Match version the binary was built from, set at
+	// link time.
+	BuildVersion = "dev"
+
+	mVersionSkewDetected = telemetry.Counter("app/version_skew_detected", "skew checks which found components running a different build version")
+	mConfigSkewDetected  = telemetry.Counter("app/config_skew_detected", "skew checks which found components running a different configuration")
+)
+
+// startSkewCheck publishes the component's build version and config digest to
+// the statestore, and periodically warns about other components which run a
+// different build or configuration.  Mismatched components otherwise tend to
+// fail silently, eg. by returning empty pools.
+func startSkewCheck(serverName string, cfg config.View) {
+	if cfg.IsSet("skewCheck.enabled") && !cfg.GetBool("skewCheck.enabled") {
+		return
+	}
+
+	interval := time.Minute
+	if cfg.IsSet("skewCheck.interval") {
+		interval = cfg.GetDuration("skewCheck.interval")
+	}
+
+	instance := instanceName(serverName)
+
+	go func() {
+		store := statestore.New(cfg)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			self := &statestore.ComponentInfo{
+				Name:         serverName,
+				Instance:     instance,
+				BuildVersion: BuildVersion,
+				ConfigDigest: config.Digest(cfg),
+			}
+			if checkErr := checkSkew(context.Background(), store, self); checkErr != nil {
+				logger.WithError(checkErr).Warning("failed to check for version skew between components")
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// checkSkew publishes self and logs the components which differ from it.
+func checkSkew(ctx context.Context, store statestore.Service, self *statestore.ComponentInfo) error {
+	if err := store.PublishComponent(ctx, self); err != nil {
+		return err
+	}
+	components, err := store.GetComponents(ctx)
+	if err != nil {
+		return err
+	}
+
+	versions, configs := findSkew(self, components)
+	for _, c := range versions {
+		logger.WithFields(logrus.Fields{
+			"component":         c.Name,
+			"instance":          c.Instance,
+			"buildVersion":      c.BuildVersion,
+			"localBuildVersion": self.BuildVersion,
+		}).Warning("Component is running a different Open Match version, API mismatches may cause silent failures.")
+	}
+	for _, c := range configs {
+		logger.WithFields(logrus.Fields{
+			"component":         c.Name,
+			"instance":          c.Instance,
+			"configDigest":      c.ConfigDigest,
+			"localConfigDigest": self.ConfigDigest,
+		}).Warning("Component is running with a different configuration.")
+	}
+	if len(versions) > 0 {
+		telemetry.RecordUnitMeasurement(ctx, mVersionSkewDetected)
+	}
+	if len(configs) > 0 {
+		telemetry.RecordUnitMeasurement(ctx, mConfigSkewDetected)
+	}
+	return nil
+}
+
+// findSkew returns the components with a build version, and the components
+// with a config digest, different from self.
+func findSkew(self *statestore.ComponentInfo, components []*statestore.ComponentInfo) (versions, configs []*statestore.ComponentInfo) {
+	for _, c := range components {
+		if c.Instance == self.Instance {
+			continue
+		}
+		if c.BuildVersion != self.BuildVersion {
+			versions = append(versions, c)
+		}
+		// An empty digest means the component couldn't compute one.
+		if c.ConfigDigest != "" && self.ConfigDigest != "" && c.ConfigDigest != self.ConfigDigest {
+			configs = append(configs, c)
+		}
+	}
+	return versions, configs
+}
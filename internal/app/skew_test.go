@@ -0,0 +1,56 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"open-match.dev/open-match/internal/statestore"
+	statestoreTesting "open-match.dev/open-match/internal/statestore/testing"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+)
+
+func TestCheckSkew(t *testing.T) {
+	assert := assert.New(t)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, viper.New())
+	defer closer()
+	ctx := utilTesting.NewContext(t)
+
+	backend := &statestore.ComponentInfo{Name: "backend", Instance: "a/backend", BuildVersion: "0.9.0", ConfigDigest: "1"}
+	query := &statestore.ComponentInfo{Name: "query", Instance: "b/query", BuildVersion: "1.0.0", ConfigDigest: "1"}
+	frontend := &statestore.ComponentInfo{Name: "frontend", Instance: "c/frontend", BuildVersion: "1.0.0", ConfigDigest: "2"}
+
+	assert.Nil(checkSkew(ctx, store, backend))
+	assert.Nil(checkSkew(ctx, store, query))
+	assert.Nil(checkSkew(ctx, store, frontend))
+
+	components, err := store.GetComponents(ctx)
+	assert.Nil(err)
+	assert.ElementsMatch([]*statestore.ComponentInfo{backend, query, frontend}, components)
+
+	versions, configs := findSkew(query, components)
+	assert.Equal([]*statestore.ComponentInfo{backend}, versions)
+	assert.Equal([]*statestore.ComponentInfo{frontend}, configs)
+
+	// Republishing replaces the instance's previous state.
+	backend.BuildVersion = "1.0.0"
+	assert.Nil(checkSkew(ctx, store, backend))
+	components, err = store.GetComponents(ctx)
+	assert.Nil(err)
+	versions, _ = findSkew(query, components)
+	assert.Empty(versions)
+}
@@ -0,0 +1,170 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package standalone runs all of Open Match, an embedded state store, and
+// optionally a sample match function and the default evaluator in a single
+// process.  It's meant for laptops, CI, and small deployments where installing
+// each component separately is overkill.
+package standalone
+
+import (
+	"fmt"
+	"strings"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"open-match.dev/open-match/internal/app/evaluator"
+	"open-match.dev/open-match/internal/app/evaluator/defaulteval"
+	"open-match.dev/open-match/internal/app/minimatch"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/rpc"
+	internalMmf "open-match.dev/open-match/internal/testing/mmf"
+	"open-match.dev/open-match/test/matchfunction/mmf"
+)
+
+const (
+	// ServerName is the name of the standalone server, its ports are configured
+	// under api.standalone.
+	ServerName = "standalone"
+
+	defaultGRPCPort = 50501
+	defaultHTTPPort = 51501
+)
+
+var (
+	logger = logrus.WithFields(logrus.Fields{
+		"app":       "openmatch",
+		"component": "app.standalone",
+	})
+
+	// services are the components whose address defaults to the standalone
+	// server, as they are all served by it.
+	services = []string{"frontend", "backend", "query", "synchronizer", "evaluator", "functions"}
+)
+
+// ReadConfig reads the configuration from the YAML file at path, or only uses
+// the defaults if path is empty.  Components default to talking to each other
+// through the standalone server, but may be pointed elsewhere, eg. to use an
+// external evaluator.
+func ReadConfig(path string) (config.Mutable, error) {
+	cfg := viper.New()
+	setDefaults(cfg)
+
+	if path != "" {
+		cfg.SetConfigFile(path)
+		if err := cfg.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("error reading config file %s: %w", path, err)
+		}
+	}
+
+	hostname := cfg.GetString("api." + ServerName + ".hostname")
+	grpcPort := cfg.GetInt("api." + ServerName + ".grpcport")
+	httpPort := cfg.GetInt("api." + ServerName + ".httpport")
+	for _, s := range services {
+		cfg.SetDefault("api."+s+".hostname", hostname)
+		cfg.SetDefault("api."+s+".grpcport", grpcPort)
+		cfg.SetDefault("api."+s+".httpport", httpPort)
+	}
+
+	return cfg, nil
+}
+
+func setDefaults(cfg *viper.Viper) {
+	defaults := map[string]interface{}{
+		"logging.level":  "info",
+		"logging.format": "text",
+
+		"backoff.initialInterval": "100ms",
+		"backoff.maxInterval":     "500ms",
+		"backoff.multiplier":      1.5,
+		"backoff.randFactor":      0.5,
+		"backoff.maxElapsedTime":  "3000ms",
+
+		"api." + ServerName + ".hostname": "localhost",
+		"api." + ServerName + ".grpcport": defaultGRPCPort,
+		"api." + ServerName + ".httpport": defaultHTTPPort,
+
+		"synchronizer.enabled":                      true,
+		"synchronizer.registrationIntervalMs":       "250ms",
+		"synchronizer.proposalCollectionIntervalMs": "20000ms",
+
+		"storage.ignoreListTTL": "60000ms",
+		"storage.page.size":     10000,
+
+		"redis.pool.maxIdle":            200,
+		"redis.pool.maxActive":          0,
+		"redis.pool.idleTimeout":        0,
+		"redis.pool.healthCheckTimeout": "300ms",
+		"redis.expiration":              43200,
+
+		"standalone.embeddedStore.enabled": true,
+		"standalone.evaluator.enabled":     true,
+		"standalone.mmf.enabled":           false,
+	}
+	for k, v := range defaults {
+		cfg.SetDefault(k, v)
+	}
+}
+
+// StartEmbeddedStore starts an in memory Redis compatible store and points the
+// configuration at it, unless standalone.embeddedStore.enabled is false.  The
+// store is not persisted, all tickets are lost when the process exits.  The
+// returned function stops the store.
+func StartEmbeddedStore(cfg config.Mutable) (func(), error) {
+	if !cfg.GetBool("standalone.embeddedStore.enabled") {
+		return func() {}, nil
+	}
+
+	store, err := miniredis.Run()
+	if err != nil {
+		return nil, fmt.Errorf("error starting embedded store: %w", err)
+	}
+	cfg.Set("redis.hostname", store.Host())
+	cfg.Set("redis.port", store.Port())
+
+	logger.WithField("address", store.Addr()).Info("Started embedded state store.")
+	return store.Close, nil
+}
+
+// BindService binds all of the Open Match services to the server, along with
+// the default evaluator and the sample match function when enabled by
+// standalone.evaluator.enabled and standalone.mmf.enabled.
+func BindService(p *rpc.ServerParams, cfg config.View) error {
+	if err := minimatch.BindService(p, cfg); err != nil {
+		return err
+	}
+
+	enabled := []string{"frontend", "backend", "query", "synchronizer", "admin"}
+
+	if cfg.GetBool("standalone.evaluator.enabled") {
+		if err := evaluator.BindService(p, cfg, defaulteval.Evaluate); err != nil {
+			return err
+		}
+		enabled = append(enabled, "evaluator")
+	}
+
+	if cfg.GetBool("standalone.mmf.enabled") {
+		err := internalMmf.BindService(p, cfg, &internalMmf.FunctionSettings{
+			Func: mmf.MakeMatches,
+		})
+		if err != nil {
+			return err
+		}
+		enabled = append(enabled, "mmf")
+	}
+
+	logger.WithField("services", strings.Join(enabled, ",")).Info("Bound standalone services.")
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standalone
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"open-match.dev/open-match/internal/rpc"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestReadConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg, err := ReadConfig("")
+	assert.Nil(err)
+	assert.Equal(defaultGRPCPort, cfg.GetInt("api.backend.grpcport"))
+	assert.Equal("localhost", cfg.GetString("api.synchronizer.hostname"))
+
+	dir, err := ioutil.TempDir("", "standalone")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.yaml")
+	require.Nil(t, ioutil.WriteFile(path, []byte(`
+api:
+  standalone:
+    grpcport: 1234
+  evaluator:
+    hostname: my-evaluator
+`), 0644))
+
+	cfg, err = ReadConfig(path)
+	assert.Nil(err)
+	assert.Equal(1234, cfg.GetInt("api.query.grpcport"))
+	assert.Equal("localhost", cfg.GetString("api.query.hostname"))
+	assert.Equal("my-evaluator", cfg.GetString("api.evaluator.hostname"))
+
+	_, err = ReadConfig(filepath.Join(dir, "missing.yaml"))
+	assert.NotNil(err)
+}
+
+func TestStandaloneMatchmaking(t *testing.T) {
+	cfg, err := ReadConfig("")
+	require.Nil(t, err)
+	cfg.Set("standalone.mmf.enabled", true)
+	cfg.Set("synchronizer.registrationIntervalMs", "200ms")
+	cfg.Set("synchronizer.proposalCollectionIntervalMs", "200ms")
+	closeStore, err := StartEmbeddedStore(cfg)
+	require.Nil(t, err)
+	defer closeStore()
+
+	// The match function connects to the query service when it's bound, so the
+	// ports must be known before binding.
+	grpcLh := rpc.MustListen()
+	proxyLh := rpc.MustListen()
+	for _, s := range services {
+		cfg.Set("api."+s+".grpcport", grpcLh.Number())
+		cfg.Set("api."+s+".httpport", proxyLh.Number())
+	}
+
+	p := rpc.NewServerParamsFromListeners(grpcLh, proxyLh)
+	require.Nil(t, BindService(p, cfg))
+	s := &rpc.Server{}
+	waitForStart, err := s.Start(p)
+	require.Nil(t, err)
+	defer s.Stop()
+	waitForStart()
+
+	conn, err := grpc.Dial(fmt.Sprintf("localhost:%d", grpcLh.Number()), grpc.WithInsecure())
+	require.Nil(t, err)
+	defer conn.Close()
+	fe := pb.NewFrontendServiceClient(conn)
+	be := pb.NewBackendServiceClient(conn)
+	ctx := context.Background()
+
+	resp, err := fe.CreateTicket(ctx, &pb.CreateTicketRequest{Ticket: &pb.Ticket{}})
+	require.Nil(t, err)
+
+	stream, err := be.FetchMatches(ctx, &pb.FetchMatchesRequest{
+		Config: &pb.FunctionConfig{
+			Host: "localhost",
+			Port: int32(grpcLh.Number()),
+			Type: pb.FunctionConfig_GRPC,
+		},
+		Profile: &pb.MatchProfile{
+			Name:  "everyone",
+			Pools: []*pb.Pool{{Name: "all"}},
+		},
+	}, grpc.WaitForReady(true))
+	require.Nil(t, err)
+
+	var matches []*pb.Match
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.Nil(t, err)
+		matches = append(matches, resp.GetMatch())
+	}
+
+	require.Len(t, matches, 1)
+	require.Len(t, matches[0].GetTickets(), 1)
+	assert.Equal(t, resp.GetTicket().GetId(), matches[0].GetTickets()[0].GetId())
+}
@@ -0,0 +1,169 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synchronizer
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// clusteringEvaluator evaluates proposals which share no tickets in
+// parallel.  Proposals are partitioned into clusters, the connected components
+// of proposals sharing tickets, which can't affect each other's evaluation.
+// The clusters are spread over up to parallelism concurrent evaluator calls.
+//
+// Unlike a single evaluator call, evaluation only starts once all proposals
+// are collected, since a late proposal may join two clusters.
+type clusteringEvaluator struct {
+	evaluator
+	parallelism int
+}
+
+// withClustering wraps eval to evaluate proposals with up to
+// synchronizer.evaluationParallelism concurrent calls, if greater than one.
+func withClustering(cfg config.View, eval evaluator) evaluator {
+	const name = "synchronizer.evaluationParallelism"
+	if !cfg.IsSet(name) || cfg.GetInt(name) <= 1 {
+		return eval
+	}
+	return &clusteringEvaluator{
+		evaluator:   eval,
+		parallelism: cfg.GetInt(name),
+	}
+}
+
+func (ce *clusteringEvaluator) evaluate(ctx context.Context, pc <-chan []*pb.Match, results chan<- string) error {
+	proposals := []*pb.Match{}
+	for matches := range pc {
+		proposals = append(proposals, matches...)
+	}
+
+	groups := groupClusters(clusterProposals(proposals), ce.parallelism)
+	if len(groups) <= 1 {
+		return ce.evaluator.evaluate(ctx, sendAll(proposals), results)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var m sync.Mutex
+	var firstErr error
+	for _, group := range groups {
+		wg.Add(1)
+		go func(group []*pb.Match) {
+			defer wg.Done()
+			err := ce.evaluator.evaluate(ctx, sendAll(group), results)
+			if err == nil {
+				return
+			}
+
+			m.Lock()
+			defer m.Unlock()
+			if firstErr == nil {
+				firstErr = err
+				cancel()
+			}
+		}(group)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// sendAll returns a closed channel holding the matches.
+func sendAll(matches []*pb.Match) <-chan []*pb.Match {
+	c := make(chan []*pb.Match, 1)
+	c <- matches
+	close(c)
+	return c
+}
+
+// clusterProposals partitions the proposals into clusters which share no
+// tickets, in the order of their first proposal.
+func clusterProposals(proposals []*pb.Match) [][]*pb.Match {
+	// parent is a union-find forest over proposal indexes.
+	parent := make([]int, len(proposals))
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	proposalOfTicket := make(map[string]int)
+	for i, p := range proposals {
+		parent[i] = i
+		for _, t := range p.GetTickets() {
+			j, ok := proposalOfTicket[t.GetId()]
+			if !ok {
+				proposalOfTicket[t.GetId()] = i
+				continue
+			}
+			if ri, rj := find(i), find(j); ri != rj {
+				// Keep the earliest proposal as the root.
+				if ri < rj {
+					parent[rj] = ri
+				} else {
+					parent[ri] = rj
+				}
+			}
+		}
+	}
+
+	clusterOfRoot := make(map[int]int)
+	clusters := [][]*pb.Match{}
+	for i, p := range proposals {
+		root := find(i)
+		c, ok := clusterOfRoot[root]
+		if !ok {
+			c = len(clusters)
+			clusterOfRoot[root] = c
+			clusters = append(clusters, nil)
+		}
+		clusters[c] = append(clusters[c], p)
+	}
+	return clusters
+}
+
+// groupClusters spreads the clusters over up to n groups of about the same
+// number of proposals, placing the largest clusters first.
+func groupClusters(clusters [][]*pb.Match, n int) [][]*pb.Match {
+	if len(clusters) < n {
+		n = len(clusters)
+	}
+	sorted := make([][]*pb.Match, len(clusters))
+	copy(sorted, clusters)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return len(sorted[i]) > len(sorted[j])
+	})
+
+	groups := make([][]*pb.Match, n)
+	for _, c := range sorted {
+		smallest := 0
+		for i := range groups {
+			if len(groups[i]) < len(groups[smallest]) {
+				smallest = i
+			}
+		}
+		groups[smallest] = append(groups[smallest], c...)
+	}
+	return groups
+}
@@ -0,0 +1,127 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synchronizer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func proposal(id string, ticketIDs ...string) *pb.Match {
+	m := &pb.Match{MatchId: id}
+	for _, t := range ticketIDs {
+		m.Tickets = append(m.Tickets, &pb.Ticket{Id: t})
+	}
+	return m
+}
+
+func matchIDs(matches []*pb.Match) []string {
+	ids := []string{}
+	for _, m := range matches {
+		ids = append(ids, m.GetMatchId())
+	}
+	return ids
+}
+
+func TestClusterProposals(t *testing.T) {
+	proposals := []*pb.Match{
+		proposal("1", "a", "b"),
+		proposal("2", "c"),
+		proposal("3", "d", "e"),
+		proposal("4", "b", "f"),
+		proposal("5", "e", "c"),
+		proposal("6", "g"),
+	}
+
+	clusters := [][]string{}
+	for _, c := range clusterProposals(proposals) {
+		clusters = append(clusters, matchIDs(c))
+	}
+	assert.Equal(t, [][]string{{"1", "4"}, {"2", "3", "5"}, {"6"}}, clusters)
+}
+
+func TestGroupClusters(t *testing.T) {
+	clusters := [][]*pb.Match{
+		{proposal("1")},
+		{proposal("2"), proposal("3"), proposal("4")},
+		{proposal("5"), proposal("6")},
+		{proposal("7")},
+	}
+
+	groups := [][]string{}
+	for _, g := range groupClusters(clusters, 2) {
+		groups = append(groups, matchIDs(g))
+	}
+	assert.Equal(t, [][]string{{"2", "3", "4", "7"}, {"5", "6", "1"}}, groups)
+
+	assert.Len(t, groupClusters(clusters, 10), 4)
+	assert.Len(t, groupClusters(nil, 10), 0)
+}
+
+// recordingEvaluator accepts every proposal, then fails with err if set,
+// recording the calls made.
+type recordingEvaluator struct {
+	m     sync.Mutex
+	calls [][]string
+	err   error
+}
+
+func (re *recordingEvaluator) evaluate(ctx context.Context, pc <-chan []*pb.Match, results chan<- string) error {
+	ids := []string{}
+	for matches := range pc {
+		ids = append(ids, matchIDs(matches)...)
+	}
+	for _, id := range ids {
+		results <- id
+	}
+	re.m.Lock()
+	defer re.m.Unlock()
+	re.calls = append(re.calls, ids)
+	return re.err
+}
+
+func TestClusteringEvaluator(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := viper.New()
+	inner := &recordingEvaluator{}
+	assert.Equal(inner, withClustering(cfg, inner))
+
+	cfg.Set("synchronizer.evaluationParallelism", 4)
+	eval := withClustering(cfg, inner)
+
+	pc := make(chan []*pb.Match, 2)
+	pc <- []*pb.Match{proposal("1", "a"), proposal("2", "b")}
+	pc <- []*pb.Match{proposal("3", "a", "c")}
+	close(pc)
+
+	ids, err := evaluateAll(eval, pc)
+	assert.Nil(err)
+	assert.ElementsMatch([]string{"1", "2", "3"}, ids)
+	assert.ElementsMatch([][]string{{"1", "3"}, {"2"}}, inner.calls)
+
+	inner.err = errors.New("evaluator failed")
+	pc = make(chan []*pb.Match, 1)
+	pc <- []*pb.Match{proposal("1", "a"), proposal("2", "b")}
+	close(pc)
+	_, err = evaluateAll(eval, pc)
+	assert.Equal(inner.err, err)
+}
@@ -15,20 +15,23 @@
 package synchronizer
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"sync"
 
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	harness "open-match.dev/open-match/internal/app/evaluator"
+	"open-match.dev/open-match/internal/app/evaluator/defaulteval"
 	"open-match.dev/open-match/internal/config"
 	"open-match.dev/open-match/internal/omerror"
 	"open-match.dev/open-match/internal/rpc"
+	"open-match.dev/open-match/internal/util"
 	"open-match.dev/open-match/pkg/pb"
 )
 
@@ -39,22 +42,58 @@ var (
 	})
 )
 
+// evaluator sends the proposals read from its input to the evaluator, and the
+// ids of the matches it accepts to the results channel as they're received.
+// The results channel is closed by the caller once evaluate returns.
 type evaluator interface {
-	evaluate(context.Context, <-chan []*pb.Match) ([]string, error)
+	evaluate(ctx context.Context, pc <-chan []*pb.Match, results chan<- string) error
 }
 
-var errNoEvaluatorType = status.Errorf(codes.FailedPrecondition, "unable to determine evaluator type, either api.evaluator.grpcport or api.evaluator.httpport must be specified in the config")
+// filterResults returns a channel of results to pass to an evaluator, whose
+// ids are sent on to results if keep returns true, and a function to call once
+// the evaluator returned, which closes it and waits for the ids to be sent.
+func filterResults(results chan<- string, keep func(id string) bool) (chan<- string, func()) {
+	c := make(chan string)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for id := range c {
+			if keep(id) {
+				results <- id
+			}
+		}
+	}()
+	return c, func() {
+		close(c)
+		<-done
+	}
+}
 
 func newEvaluator(cfg config.View) evaluator {
+	if cfg.GetBool("synchronizer.defaultEvaluator.enabled") {
+		evaluatorClientLogger.Info("Using the built-in default evaluator.")
+		return &inProcessEvaluator{eval: defaulteval.Evaluate}
+	}
+
+	eval := newEndpointEvaluator(cfg, "api.evaluator")
+	if names := cfg.GetStringSlice("synchronizer.evaluatorShards"); len(names) > 0 {
+		return newShardingEvaluator(cfg, names, eval)
+	}
+	return eval
+}
+
+// newEndpointEvaluator returns an evaluator calling the endpoint configured
+// under key, such as api.evaluator.
+func newEndpointEvaluator(cfg config.View, key string) evaluator {
 	newInstance := func(cfg config.View) (interface{}, func(), error) {
 		// grpc is preferred over http.
-		if cfg.IsSet("api.evaluator.grpcport") {
-			return newGrpcEvaluator(cfg)
+		if cfg.IsSet(key+".grpcport") || cfg.IsSet(key+".grpcsocket") {
+			return newGrpcEvaluator(cfg, key)
 		}
-		if cfg.IsSet("api.evaluator.httpport") {
-			return newHTTPEvaluator(cfg)
+		if cfg.IsSet(key + ".httpport") {
+			return newHTTPEvaluator(cfg, key)
 		}
-		return nil, nil, errNoEvaluatorType
+		return nil, nil, status.Errorf(codes.FailedPrecondition, "unable to determine evaluator type, either %[1]s.grpcport, %[1]s.grpcsocket or %[1]s.httpport must be specified in the config", key)
 	}
 
 	return &deferredEvaluator{
@@ -66,26 +105,87 @@ type deferredEvaluator struct {
 	cacher *config.Cacher
 }
 
-func (de *deferredEvaluator) evaluate(ctx context.Context, pc <-chan []*pb.Match) ([]string, error) {
+func (de *deferredEvaluator) evaluate(ctx context.Context, pc <-chan []*pb.Match, results chan<- string) error {
 	e, err := de.cacher.Get()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	matches, err := e.(evaluator).evaluate(ctx, pc)
+	err = e.(evaluator).evaluate(ctx, pc, results)
 	if err != nil {
 		de.cacher.ForceReset()
 	}
-	return matches, err
+	return err
+}
+
+// inProcessEvaluator runs an evaluator inside the synchronizer, instead of
+// streaming the proposals to an evaluator service.
+type inProcessEvaluator struct {
+	eval harness.Evaluator
+}
+
+func (ie *inProcessEvaluator) evaluate(ctx context.Context, pc <-chan []*pb.Match, results chan<- string) error {
+	matches := []*pb.Match{}
+	for proposals := range pc {
+		matches = append(matches, proposals...)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	ids, err := ie.eval(&harness.Params{
+		Logger:  evaluatorClientLogger,
+		Matches: matches,
+	})
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		results <- id
+	}
+	return nil
+}
+
+// evaluateRequests splits proposals into requests of up to chunkSize matches,
+// or a request per match if chunkSize is at most one, as expected by
+// evaluators which predate chunking.
+func evaluateRequests(proposals []*pb.Match, chunkSize int) []*pb.EvaluateRequest {
+	reqs := []*pb.EvaluateRequest{}
+	if chunkSize <= 1 {
+		for _, proposal := range proposals {
+			reqs = append(reqs, &pb.EvaluateRequest{Match: proposal})
+		}
+		return reqs
+	}
+
+	for len(proposals) > 0 {
+		n := chunkSize
+		if n > len(proposals) {
+			n = len(proposals)
+		}
+		reqs = append(reqs, &pb.EvaluateRequest{Matches: proposals[:n]})
+		proposals = proposals[n:]
+	}
+	return reqs
+}
+
+// evaluateResultIDs returns the match ids of an evaluator response, which
+// holds either a single id or a chunk of them.
+func evaluateResultIDs(resp *pb.EvaluateResponse) []string {
+	if resp.GetMatchId() != "" {
+		return append([]string{resp.GetMatchId()}, resp.GetMatchIds()...)
+	}
+	return resp.GetMatchIds()
 }
 
 type grcpEvaluatorClient struct {
 	evaluator pb.EvaluatorClient
+	chunkSize int
 }
 
-func newGrpcEvaluator(cfg config.View) (evaluator, func(), error) {
-	grpcAddr := fmt.Sprintf("%s:%d", cfg.GetString("api.evaluator.hostname"), cfg.GetInt64("api.evaluator.grpcport"))
-	conn, err := rpc.GRPCClientFromEndpoint(cfg, grpcAddr)
+func newGrpcEvaluator(cfg config.View, key string) (evaluator, func(), error) {
+	grpcAddr := fmt.Sprintf("%s:%d", cfg.GetString(key+".hostname"), cfg.GetInt64(key+".grpcport"))
+	conn, release, err := rpc.SharedGRPCClientFromEndpoint(cfg, grpcAddr)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create grpc evaluator client: %w", err)
 	}
@@ -94,34 +194,26 @@ func newGrpcEvaluator(cfg config.View) (evaluator, func(), error) {
 		"endpoint": grpcAddr,
 	}).Info("Created a GRPC client for evaluator endpoint.")
 
-	close := func() {
-		err := conn.Close()
-		if err != nil {
-			logger.WithError(err).Warning("Error closing synchronizer client.")
-		}
-	}
-
 	return &grcpEvaluatorClient{
 		evaluator: pb.NewEvaluatorClient(conn),
-	}, close, nil
+		chunkSize: cfg.GetInt("synchronizer.evaluatorChunkSize"),
+	}, release, nil
 }
 
-func (ec *grcpEvaluatorClient) evaluate(ctx context.Context, pc <-chan []*pb.Match) ([]string, error) {
+func (ec *grcpEvaluatorClient) evaluate(ctx context.Context, pc <-chan []*pb.Match, results chan<- string) error {
 	var stream pb.Evaluator_EvaluateClient
 	{ // prevent shadowing err later
 		var err error
 		stream, err = ec.evaluator.Evaluate(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("error starting evaluator call: %w", err)
+			return fmt.Errorf("error starting evaluator call: %w", err)
 		}
 	}
 
-	results := []string{}
-
 	wait := omerror.WaitOnErrors(evaluatorClientLogger, func() error {
 		for proposals := range pc {
-			for _, proposal := range proposals {
-				if err := stream.Send(&pb.EvaluateRequest{Match: proposal}); err != nil {
+			for _, req := range evaluateRequests(proposals, ec.chunkSize) {
+				if err := stream.Send(req); err != nil {
 					return fmt.Errorf("failed to send request to evaluator, desc: %w", err)
 				}
 			}
@@ -133,7 +225,6 @@ func (ec *grcpEvaluatorClient) evaluate(ctx context.Context, pc <-chan []*pb.Mat
 		return nil
 	}, func() error {
 		for {
-			// TODO: add grpc timeouts for this call.
 			resp, err := stream.Recv()
 			if err == io.EOF {
 				return nil
@@ -141,24 +232,23 @@ func (ec *grcpEvaluatorClient) evaluate(ctx context.Context, pc <-chan []*pb.Mat
 			if err != nil {
 				return fmt.Errorf("failed to get response from evaluator client, desc: %w", err)
 			}
-			results = append(results, resp.GetMatchId())
+			for _, id := range evaluateResultIDs(resp) {
+				results <- id
+			}
 		}
 	})
 
-	err := wait()
-	if err != nil {
-		return nil, err
-	}
-	return results, nil
+	return wait()
 }
 
 type httpEvaluatorClient struct {
 	httpClient *http.Client
 	baseURL    string
+	chunkSize  int
 }
 
-func newHTTPEvaluator(cfg config.View) (evaluator, func(), error) {
-	httpAddr := fmt.Sprintf("%s:%d", cfg.GetString("api.evaluator.hostname"), cfg.GetInt64("api.evaluator.httpport"))
+func newHTTPEvaluator(cfg config.View, key string) (evaluator, func(), error) {
+	httpAddr := fmt.Sprintf("%s:%d", cfg.GetString(key+".hostname"), cfg.GetInt64(key+".httpport"))
 	client, baseURL, err := rpc.HTTPClientFromEndpoint(cfg, httpAddr)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get a HTTP client from the endpoint %v: %w", httpAddr, err)
@@ -175,50 +265,33 @@ func newHTTPEvaluator(cfg config.View) (evaluator, func(), error) {
 	return &httpEvaluatorClient{
 		httpClient: client,
 		baseURL:    baseURL,
+		chunkSize:  cfg.GetInt("synchronizer.evaluatorChunkSize"),
 	}, close, nil
 }
 
-func (ec *httpEvaluatorClient) evaluate(ctx context.Context, pc <-chan []*pb.Match) ([]string, error) {
+func (ec *httpEvaluatorClient) evaluate(ctx context.Context, pc <-chan []*pb.Match, results chan<- string) error {
 	reqr, reqw := io.Pipe()
-	var wg sync.WaitGroup
-	wg.Add(1)
-
 	sc := make(chan error, 1)
-	defer close(sc)
 	go func() {
-		var m jsonpb.Marshaler
-		defer func() {
-			wg.Done()
-			if reqw.Close() != nil {
-				logger.Warning("failed to close response body read closer")
-			}
-		}()
-		for proposals := range pc {
-			for _, proposal := range proposals {
-				buf, err := m.MarshalToString(&pb.EvaluateRequest{Match: proposal})
-				if err != nil {
-					sc <- status.Errorf(codes.FailedPrecondition, "failed to marshal proposal to string: %s", err.Error())
-					return
-				}
-				_, err = io.WriteString(reqw, buf)
-				if err != nil {
-					sc <- status.Errorf(codes.FailedPrecondition, "failed to write proto string to io writer: %s", err.Error())
-					return
-				}
-			}
-		}
+		sc <- ec.sendProposals(reqw, pc)
 	}()
 
 	req, err := http.NewRequest("POST", ec.baseURL+"/v1/evaluator/matches:evaluate", reqr)
 	if err != nil {
-		return nil, status.Errorf(codes.Aborted, "failed to create evaluator http request, desc: %s", err.Error())
+		reqr.CloseWithError(err)
+		return status.Errorf(codes.Aborted, "failed to create evaluator http request, desc: %s", err.Error())
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Transfer-Encoding", "chunked")
+	// Setting Accept-Encoding keeps the transport from decompressing the
+	// response itself, which is done by readResults instead.
+	req.Header.Set("Accept-Encoding", "gzip")
+	util.SetSynchronizerContextIDHeader(ctx, req)
 
 	resp, err := ec.httpClient.Do(req.WithContext(ctx))
 	if err != nil {
-		return nil, status.Errorf(codes.Aborted, "failed to get response from evaluator, desc: %s", err.Error())
+		reqr.CloseWithError(err)
+		return status.Errorf(codes.Unavailable, "failed to get response from evaluator, desc: %s", err.Error())
 	}
 	defer func() {
 		if resp.Body.Close() != nil {
@@ -226,46 +299,101 @@ func (ec *httpEvaluatorClient) evaluate(ctx context.Context, pc <-chan []*pb.Mat
 		}
 	}()
 
-	wg.Add(1)
-	var results = []string{}
-	rc := make(chan error, 1)
-	defer close(rc)
-	go func() {
-		defer wg.Done()
+	if err := readResults(resp, results); err != nil {
+		// Stop sending proposals to an evaluator which won't read them.
+		reqr.CloseWithError(err)
+		return err
+	}
+	return <-sc
+}
 
-		dec := json.NewDecoder(resp.Body)
-		for {
-			var item struct {
-				Result json.RawMessage        `json:"result"`
-				Error  map[string]interface{} `json:"error"`
-			}
-			err := dec.Decode(&item)
-			if err == io.EOF {
-				break
-			}
+// sendProposals writes the proposals to w as newline delimited JSON, closing
+// w once pc is closed.
+func (ec *httpEvaluatorClient) sendProposals(w *io.PipeWriter, pc <-chan []*pb.Match) error {
+	var m jsonpb.Marshaler
+	for proposals := range pc {
+		for _, req := range evaluateRequests(proposals, ec.chunkSize) {
+			buf, err := m.MarshalToString(req)
 			if err != nil {
-				rc <- status.Errorf(codes.Unavailable, "failed to read response from HTTP JSON stream: %s", err.Error())
-				return
-			}
-			if len(item.Error) != 0 {
-				rc <- status.Errorf(codes.Unavailable, "failed to execute evaluator.Evaluate: %v", item.Error)
-				return
+				err = status.Errorf(codes.FailedPrecondition, "failed to marshal proposal to string: %s", err.Error())
+				w.CloseWithError(err)
+				return err
 			}
-			resp := &pb.EvaluateResponse{}
-			if err = jsonpb.UnmarshalString(string(item.Result), resp); err != nil {
-				rc <- status.Errorf(codes.Unavailable, "failed to execute jsonpb.UnmarshalString(%s, &proposal): %v.", item.Result, err)
-				return
+			if _, err = io.WriteString(w, buf+"\n"); err != nil {
+				err = status.Errorf(codes.FailedPrecondition, "failed to write proto string to io writer: %s", err.Error())
+				w.CloseWithError(err)
+				return err
 			}
-			results = append(results, resp.GetMatchId())
 		}
-	}()
+	}
+	if err := w.Close(); err != nil {
+		logger.Warning("failed to close the evaluator request body writer")
+	}
+	return nil
+}
+
+// gatewayStreamError is the error frame written by grpc-gateway when a
+// streaming call fails.
+type gatewayStreamError struct {
+	GrpcCode   int32  `json:"grpc_code"`
+	HTTPCode   int32  `json:"http_code"`
+	Message    string `json:"message"`
+	HTTPStatus string `json:"http_status"`
+}
+
+func (e *gatewayStreamError) err() error {
+	return status.Errorf(codes.Code(e.GrpcCode), "evaluator failed with %d %s: %s", e.HTTPCode, e.HTTPStatus, e.Message)
+}
 
-	wg.Wait()
-	if len(sc) != 0 {
-		return nil, <-sc
+// readResults sends the match ids from the evaluator's stream of newline
+// delimited JSON responses, which may be gzip encoded, to results as they're
+// decoded.
+func readResults(resp *http.Response, results chan<- string) error {
+	var body io.Reader = resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return status.Errorf(codes.Unavailable, "failed to read gzip encoded response from evaluator: %s", err.Error())
+		}
+		defer gz.Close()
+		body = gz
 	}
-	if len(rc) != 0 {
-		return nil, <-rc
+
+	dec := json.NewDecoder(body)
+	if resp.StatusCode != http.StatusOK {
+		// The call failed before streaming started, and grpc-gateway wrote
+		// the error as the whole body.
+		var e struct {
+			Code    int32  `json:"code"`
+			Message string `json:"message"`
+		}
+		if err := dec.Decode(&e); err != nil || e.Code == 0 {
+			return status.Errorf(codes.Unavailable, "evaluator returned HTTP status %s", resp.Status)
+		}
+		return status.Errorf(codes.Code(e.Code), "evaluator returned HTTP status %s: %s", resp.Status, e.Message)
+	}
+
+	for {
+		var item struct {
+			Result json.RawMessage     `json:"result"`
+			Error  *gatewayStreamError `json:"error"`
+		}
+		err := dec.Decode(&item)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return status.Errorf(codes.Unavailable, "failed to read response from HTTP JSON stream: %s", err.Error())
+		}
+		if item.Error != nil {
+			return item.Error.err()
+		}
+		resp := &pb.EvaluateResponse{}
+		if err = jsonpb.UnmarshalString(string(item.Result), resp); err != nil {
+			return status.Errorf(codes.Unavailable, "failed to execute jsonpb.UnmarshalString(%s, &proposal): %v.", item.Result, err)
+		}
+		for _, id := range evaluateResultIDs(resp) {
+			results <- id
+		}
 	}
-	return results, nil
 }
@@ -0,0 +1,181 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synchronizer
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/jsonpb"
+	"google.golang.org/grpc/codes"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// evaluateAll calls the evaluator with the proposals, returning the ids it
+// sent.
+func evaluateAll(eval evaluator, pc <-chan []*pb.Match) ([]string, error) {
+	results := make(chan string)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- eval.evaluate(context.Background(), pc, results)
+		close(results)
+	}()
+
+	ids := []string{}
+	for id := range results {
+		ids = append(ids, id)
+	}
+	return ids, <-errs
+}
+
+func scoredProposal(t *testing.T, id string, score float64, ticketIDs ...string) *pb.Match {
+	m := proposal(id, ticketIDs...)
+	var err error
+	m.Extensions, err = pb.SetExtension(m.Extensions, pb.EvaluationInputExtensionKey, &pb.DefaultEvaluationCriteria{Score: score})
+	require.Nil(t, err)
+	return m
+}
+
+func TestDefaultEvaluator(t *testing.T) {
+	cfg := viper.New()
+	cfg.Set("synchronizer.defaultEvaluator.enabled", true)
+	eval := newEvaluator(cfg)
+
+	pc := make(chan []*pb.Match, 2)
+	pc <- []*pb.Match{scoredProposal(t, "1", 1, "a", "b"), scoredProposal(t, "2", 3, "b", "c")}
+	pc <- []*pb.Match{scoredProposal(t, "3", 2, "d"), scoredProposal(t, "4", 0, "c", "d")}
+	close(pc)
+
+	ids, err := evaluateAll(eval, pc)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"2", "3"}, ids)
+}
+
+func TestEvaluateRequests(t *testing.T) {
+	assert := assert.New(t)
+	proposals := []*pb.Match{proposal("1"), proposal("2"), proposal("3")}
+
+	reqs := evaluateRequests(proposals, 1)
+	assert.Len(reqs, 3)
+	for i, req := range reqs {
+		assert.Equal(proposals[i], req.GetMatch())
+		assert.Empty(req.GetMatches())
+	}
+
+	reqs = evaluateRequests(proposals, 2)
+	assert.Len(reqs, 2)
+	assert.Equal([]string{"1", "2"}, matchIDs(reqs[0].GetMatches()))
+	assert.Equal([]string{"3"}, matchIDs(reqs[1].GetMatches()))
+	assert.Nil(reqs[0].GetMatch())
+
+	assert.Empty(evaluateRequests(nil, 2))
+}
+
+func TestEvaluateResultIDs(t *testing.T) {
+	assert.Equal(t, []string{"1"}, evaluateResultIDs(&pb.EvaluateResponse{MatchId: "1"}))
+	assert.Equal(t, []string{"1", "2"}, evaluateResultIDs(&pb.EvaluateResponse{MatchIds: []string{"1", "2"}}))
+	assert.Empty(t, evaluateResultIDs(&pb.EvaluateResponse{}))
+}
+
+func TestHTTPEvaluator(t *testing.T) {
+	assert := assert.New(t)
+
+	var response func(w io.Writer)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Proposals are sent as newline delimited JSON.
+		ids := []string{}
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			req := &pb.EvaluateRequest{}
+			assert.Nil(jsonpb.UnmarshalString(scanner.Text(), req))
+			ids = append(ids, req.GetMatch().GetMatchId())
+		}
+		assert.Equal([]string{"1", "2"}, ids)
+
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			response(w)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+		response(gz)
+	}))
+	defer srv.Close()
+
+	ec := &httpEvaluatorClient{httpClient: srv.Client(), baseURL: srv.URL}
+	evaluate := func() ([]string, error) {
+		return evaluateAll(ec, sendAll([]*pb.Match{proposal("1"), proposal("2")}))
+	}
+
+	response = func(w io.Writer) {
+		_, err := io.WriteString(w, `{"result":{"match_id":"1"}}`+"\n"+`{"result":{"match_ids":["2"]}}`+"\n")
+		assert.Nil(err)
+	}
+	ids, err := evaluate()
+	assert.Nil(err)
+	assert.Equal([]string{"1", "2"}, ids)
+
+	response = func(w io.Writer) {
+		_, err := io.WriteString(w, `{"result":{"match_id":"1"}}`+"\n"+`{"error":{"grpc_code":9,"http_code":400,"message":"bad proposals","http_status":"Bad Request"}}`+"\n")
+		assert.Nil(err)
+	}
+	_, err = evaluate()
+	assert.Equal(codes.FailedPrecondition, evaluatorErrorCode(err))
+	assert.Contains(err.Error(), "bad proposals")
+}
+
+func TestHTTPEvaluatorStreamsResults(t *testing.T) {
+	assert := assert.New(t)
+
+	received := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := io.Copy(ioutil.Discard, r.Body)
+		assert.Nil(err)
+
+		_, err = io.WriteString(w, `{"result":{"match_id":"1"}}`+"\n")
+		assert.Nil(err)
+		w.(http.Flusher).Flush()
+		// The second result is only sent once the first was received.
+		<-received
+		_, err = io.WriteString(w, `{"result":{"match_id":"2"}}`+"\n")
+		assert.Nil(err)
+	}))
+	defer srv.Close()
+
+	ec := &httpEvaluatorClient{httpClient: srv.Client(), baseURL: srv.URL}
+	results := make(chan string)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- ec.evaluate(context.Background(), sendAll([]*pb.Match{proposal("1"), proposal("2")}), results)
+		close(results)
+	}()
+
+	assert.Equal("1", <-results)
+	close(received)
+	assert.Equal("2", <-results)
+	assert.Nil(<-errs)
+}
@@ -0,0 +1,261 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synchronizer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/clock"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// policyEvaluator calls the evaluator with a deadline, retries it with the
+// exponential backoff strategy while it's Unavailable, and fails fast while
+// its circuit breaker is open.  Evaluations which are still Unavailable after
+// the retries, or exceed the deadline, count towards opening the breaker.  A failed evaluation cancels the cycle, which
+// releases the proposed tickets, so the pipeline keeps running while the
+// evaluator endpoint is down.  Since results are streamed as they're received,
+// a retry only sends the ids which earlier attempts didn't.
+type policyEvaluator struct {
+	evaluator
+	clk        clock.Clock
+	timeout    time.Duration
+	newBackOff func() backoff.BackOff
+	breaker    *circuitBreaker
+}
+
+// withEvaluatorPolicy wraps eval with the synchronizer.evaluatorTimeout,
+// synchronizer.evaluatorMaxRetries and synchronizer.evaluatorCircuitBreaker
+// settings.
+func withEvaluatorPolicy(cfg config.View, clk clock.Clock, eval evaluator) evaluator {
+	maxRetries := cfg.GetInt("synchronizer.evaluatorMaxRetries")
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+
+	return &policyEvaluator{
+		evaluator: eval,
+		clk:       clk,
+		timeout:   cfg.GetDuration("synchronizer.evaluatorTimeout"),
+		newBackOff: func() backoff.BackOff {
+			// WithMaxRetries doesn't limit retries when given zero.
+			if maxRetries == 0 {
+				return &backoff.StopBackOff{}
+			}
+			b := backoff.NewExponentialBackOff()
+			b.InitialInterval = cfg.GetDuration("backoff.initialInterval")
+			b.RandomizationFactor = cfg.GetFloat64("backoff.randFactor")
+			b.Multiplier = cfg.GetFloat64("backoff.multiplier")
+			b.MaxInterval = cfg.GetDuration("backoff.maxInterval")
+			b.MaxElapsedTime = cfg.GetDuration("backoff.maxElapsedTime")
+			b.Clock = clk
+			b.Reset()
+			return backoff.WithMaxRetries(b, uint64(maxRetries))
+		},
+		breaker: &circuitBreaker{
+			clk:          clk,
+			threshold:    cfg.GetInt("synchronizer.evaluatorCircuitBreaker.failureThreshold"),
+			openDuration: cfg.GetDuration("synchronizer.evaluatorCircuitBreaker.openDuration"),
+		},
+	}
+}
+
+func (pe *policyEvaluator) evaluate(ctx context.Context, pc <-chan []*pb.Match, results chan<- string) error {
+	if !pe.breaker.allow() {
+		return status.Error(codes.Unavailable, "evaluator circuit breaker is open after repeated failures, failing evaluation fast")
+	}
+
+	r := &proposalReplay{in: pc}
+	sent := make(map[string]struct{})
+	b := pe.newBackOff()
+	for {
+		err := pe.attempt(ctx, r, sent, results)
+		if err == nil {
+			pe.breaker.record(true)
+			return nil
+		}
+		switch evaluatorErrorCode(err) {
+		case codes.Unavailable:
+		case codes.DeadlineExceeded:
+			pe.breaker.record(false)
+			return err
+		default:
+			return err
+		}
+
+		wait := b.NextBackOff()
+		if wait == backoff.Stop || ctx.Err() != nil {
+			pe.breaker.record(false)
+			return err
+		}
+		evaluatorClientLogger.WithFields(logrus.Fields{
+			"error":   err,
+			"backoff": wait,
+		}).Warning("evaluator is unavailable, retrying")
+
+		select {
+		case <-pe.clk.After(wait):
+		case <-ctx.Done():
+			pe.breaker.record(false)
+			return err
+		}
+	}
+}
+
+// attempt makes a single evaluator call, sending the proposals read by
+// earlier attempts before reading more, and forwarding the ids not yet in sent
+// to results.  The call fails with DeadlineExceeded if it's still running
+// timeout after all proposals were sent.
+func (pe *policyEvaluator) attempt(ctx context.Context, r *proposalReplay, sent map[string]struct{}, results chan<- string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	in, allSent, stopped := r.replay(ctx)
+
+	ids, flush := filterResults(results, func(id string) bool {
+		if _, ok := sent[id]; ok {
+			return false
+		}
+		sent[id] = struct{}{}
+		return true
+	})
+
+	var timedOut int32
+	if pe.timeout > 0 {
+		go func() {
+			select {
+			case <-allSent:
+				t := pe.clk.AfterFunc(pe.timeout, func() {
+					atomic.StoreInt32(&timedOut, 1)
+					cancel()
+				})
+				<-ctx.Done()
+				t.Stop()
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	err := pe.evaluator.evaluate(ctx, in, ids)
+	cancel()
+	flush()
+	<-stopped
+
+	if atomic.LoadInt32(&timedOut) == 1 {
+		return status.Errorf(codes.DeadlineExceeded, "evaluator didn't return results within %v of the last proposal", pe.timeout)
+	}
+	return err
+}
+
+// evaluatorErrorCode returns the code of the gRPC status wrapped by err, or
+// Unknown if there's none.
+func evaluatorErrorCode(err error) codes.Code {
+	var s interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &s) {
+		return codes.Unknown
+	}
+	return s.GRPCStatus().Code()
+}
+
+// proposalReplay records the proposals read from in, so that they can be sent
+// again when the evaluator call is retried.  Only one replay may run at a
+// time.
+type proposalReplay struct {
+	in   <-chan []*pb.Match
+	read []*pb.Match
+	done bool
+}
+
+// replay returns a channel of the proposals read so far followed by those
+// still to be read from the input.  sent is closed once the input is
+// exhausted and every proposal was sent, stopped once the replay stops
+// because of either that or ctx being done.
+func (r *proposalReplay) replay(ctx context.Context) (out <-chan []*pb.Match, sent <-chan struct{}, stopped <-chan struct{}) {
+	o := make(chan []*pb.Match)
+	s := make(chan struct{})
+	st := make(chan struct{})
+	go func() {
+		defer close(st)
+		defer close(o)
+
+		if len(r.read) > 0 {
+			select {
+			case o <- append([]*pb.Match{}, r.read...):
+			case <-ctx.Done():
+				return
+			}
+		}
+		for !r.done {
+			select {
+			case ms, ok := <-r.in:
+				if !ok {
+					r.done = true
+					continue
+				}
+				r.read = append(r.read, ms...)
+				select {
+				case o <- ms:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+		close(s)
+	}()
+	return o, s, st
+}
+
+// circuitBreaker opens after threshold consecutive failed evaluations, for
+// openDuration.  Afterwards, a single evaluation is let through: its success
+// closes the breaker and its failure opens it again.  It's disabled if the
+// threshold isn't positive.
+type circuitBreaker struct {
+	clk          clock.Clock
+	threshold    int
+	openDuration time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+func (cb *circuitBreaker) allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.threshold <= 0 || cb.failures < cb.threshold || !cb.clk.Now().Before(cb.openUntil)
+}
+
+func (cb *circuitBreaker) record(success bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if success {
+		cb.failures = 0
+		return
+	}
+	cb.failures++
+	if cb.threshold > 0 && cb.failures >= cb.threshold {
+		cb.openUntil = cb.clk.Now().Add(cb.openDuration)
+		evaluatorClientLogger.WithField("openDuration", cb.openDuration).Warning("evaluator failed repeatedly, opening its circuit breaker")
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synchronizer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/clock"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// failingEvaluator returns the errors of errs in turn, accepting every
+// proposal once they run out.
+type failingEvaluator struct {
+	recordingEvaluator
+	errs []error
+}
+
+func (fe *failingEvaluator) evaluate(ctx context.Context, pc <-chan []*pb.Match, results chan<- string) error {
+	fe.err = nil
+	if len(fe.errs) > 0 {
+		fe.err, fe.errs = fe.errs[0], fe.errs[1:]
+	}
+	return fe.recordingEvaluator.evaluate(ctx, pc, results)
+}
+
+func proposalChannel(ids ...string) <-chan []*pb.Match {
+	pc := make(chan []*pb.Match, len(ids))
+	for _, id := range ids {
+		pc <- []*pb.Match{proposal(id)}
+	}
+	close(pc)
+	return pc
+}
+
+func TestEvaluatorPolicyRetriesUnavailable(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := viper.New()
+	cfg.Set("synchronizer.evaluatorMaxRetries", 2)
+	unavailable := fmt.Errorf("failed to get response from evaluator client, desc: %w", status.Error(codes.Unavailable, "down"))
+
+	inner := &failingEvaluator{errs: []error{unavailable, unavailable}}
+	ids, err := evaluateAll(withEvaluatorPolicy(cfg, clock.NewVirtual(time.Unix(0, 0)), inner), proposalChannel("1", "2"))
+	assert.Nil(err)
+	// The ids sent by the failed calls aren't sent again.
+	assert.Equal([]string{"1", "2"}, ids)
+	// Retries are sent the proposals read by the failed calls.
+	assert.Equal([][]string{{"1", "2"}, {"1", "2"}, {"1", "2"}}, inner.calls)
+
+	inner = &failingEvaluator{errs: []error{unavailable, unavailable, unavailable}}
+	_, err = evaluateAll(withEvaluatorPolicy(cfg, clock.NewVirtual(time.Unix(0, 0)), inner), proposalChannel("1"))
+	assert.Equal(codes.Unavailable, evaluatorErrorCode(err))
+	assert.Len(inner.calls, 3)
+
+	inner = &failingEvaluator{errs: []error{status.Error(codes.InvalidArgument, "bad proposal")}}
+	_, err = evaluateAll(withEvaluatorPolicy(cfg, clock.NewVirtual(time.Unix(0, 0)), inner), proposalChannel("1"))
+	assert.Equal(codes.InvalidArgument, evaluatorErrorCode(err))
+	assert.Len(inner.calls, 1)
+}
+
+// blockingEvaluator reads every proposal, then waits for its context.
+type blockingEvaluator struct{}
+
+func (blockingEvaluator) evaluate(ctx context.Context, pc <-chan []*pb.Match, results chan<- string) error {
+	for range pc {
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestEvaluatorPolicyTimeout(t *testing.T) {
+	cfg := viper.New()
+	cfg.Set("synchronizer.evaluatorTimeout", "10s")
+	clk := clock.NewVirtual(time.Unix(0, 0))
+	eval := withEvaluatorPolicy(cfg, clk, blockingEvaluator{})
+
+	errs := make(chan error)
+	go func() {
+		_, err := evaluateAll(eval, proposalChannel("1"))
+		errs <- err
+	}()
+
+	for clk.Pending() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	clk.Advance(10 * time.Second)
+	assert.Equal(t, codes.DeadlineExceeded, evaluatorErrorCode(<-errs))
+}
+
+func TestEvaluatorPolicyCircuitBreaker(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := viper.New()
+	cfg.Set("synchronizer.evaluatorCircuitBreaker.failureThreshold", 2)
+	cfg.Set("synchronizer.evaluatorCircuitBreaker.openDuration", "30s")
+	clk := clock.NewVirtual(time.Unix(0, 0))
+	unavailable := status.Error(codes.Unavailable, "down")
+	inner := &failingEvaluator{errs: []error{unavailable, unavailable, unavailable}}
+	eval := withEvaluatorPolicy(cfg, clk, inner)
+
+	for i := 0; i < 2; i++ {
+		_, err := evaluateAll(eval, proposalChannel("1"))
+		assert.Equal(codes.Unavailable, evaluatorErrorCode(err))
+	}
+	assert.Len(inner.calls, 2)
+
+	// The breaker is open, so evaluation fails without calling the evaluator.
+	_, err := evaluateAll(eval, proposalChannel("1"))
+	assert.Equal(codes.Unavailable, evaluatorErrorCode(err))
+	assert.Len(inner.calls, 2)
+
+	// A failed trial call opens it again.
+	clk.Advance(30 * time.Second)
+	_, err = evaluateAll(eval, proposalChannel("1"))
+	assert.NotNil(err)
+	assert.Len(inner.calls, 3)
+	_, err = evaluateAll(eval, proposalChannel("1"))
+	assert.NotNil(err)
+	assert.Len(inner.calls, 3)
+
+	// A successful trial call closes it.
+	clk.Advance(30 * time.Second)
+	for i := 0; i < 2; i++ {
+		ids, err := evaluateAll(eval, proposalChannel("1"))
+		assert.Nil(err)
+		assert.Equal([]string{"1"}, ids)
+	}
+	assert.Len(inner.calls, 5)
+}
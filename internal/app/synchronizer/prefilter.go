@@ -0,0 +1,151 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synchronizer
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"open-match.dev/open-match/internal/statestore"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// ProposalFilter preprocesses proposals before they're sent to the evaluator,
+// so that hygiene common to most evaluators doesn't need to live in each of
+// them.  A filter is created for every cycle, and called with each batch of
+// the cycle's proposals in turn, so it may keep state across batches.
+// Dropped proposals are rejected like those the evaluator doesn't return.
+type ProposalFilter interface {
+	// FilterProposals returns the proposals to keep.  If it fails, the batch
+	// is passed on unfiltered.
+	FilterProposals(ctx context.Context, store statestore.Service, proposals []*pb.Match) ([]*pb.Match, error)
+}
+
+var (
+	proposalFiltersMu sync.Mutex
+	proposalFilters   = map[string]func() ProposalFilter{
+		"dropStaleProposals": func() ProposalFilter { return staleProposalFilter{} },
+		"dedupeProposals":    func() ProposalFilter { return &duplicateProposalFilter{seen: make(map[string]struct{})} },
+	}
+)
+
+// RegisterProposalFilter makes a proposal filter available under name, to be
+// enabled by listing it in synchronizer.proposalFilters.  newFilter is called
+// at the start of each cycle running the filter.
+func RegisterProposalFilter(name string, newFilter func() ProposalFilter) {
+	proposalFiltersMu.Lock()
+	defer proposalFiltersMu.Unlock()
+	proposalFilters[name] = newFilter
+}
+
+// newProposalFilters creates the filters listed in
+// synchronizer.proposalFilters, in order, skipping unknown names.
+func (s *synchronizerService) newProposalFilters(cycleLogger *logrus.Entry) []ProposalFilter {
+	proposalFiltersMu.Lock()
+	defer proposalFiltersMu.Unlock()
+
+	filters := []ProposalFilter{}
+	for _, name := range s.cfg.GetStringSlice("synchronizer.proposalFilters") {
+		newFilter, ok := proposalFilters[name]
+		if !ok {
+			cycleLogger.WithField("filter", name).Error("unknown proposal filter in synchronizer.proposalFilters, skipping it")
+			continue
+		}
+		filters = append(filters, newFilter())
+	}
+	return filters
+}
+
+// filterProposals runs the cycle's proposals through the configured filters.
+func (s *synchronizerService) filterProposals(ctx context.Context, cycleLogger *logrus.Entry, in chan []*pb.Match) chan []*pb.Match {
+	filters := s.newProposalFilters(cycleLogger)
+	if len(filters) == 0 {
+		return in
+	}
+
+	out := make(chan []*pb.Match)
+	go func() {
+		defer close(out)
+		for matches := range in {
+			for _, f := range filters {
+				filtered, err := f.FilterProposals(ctx, s.store, matches)
+				if err != nil {
+					cycleLogger.WithError(err).Warning("proposal filter failed, passing the proposals on unfiltered")
+					continue
+				}
+				matches = filtered
+			}
+			if len(matches) > 0 {
+				out <- matches
+			}
+		}
+	}()
+	return out
+}
+
+// staleProposalFilter drops proposals with tickets which were deleted or
+// assigned since the match function read them.
+type staleProposalFilter struct{}
+
+func (staleProposalFilter) FilterProposals(ctx context.Context, store statestore.Service, proposals []*pb.Match) ([]*pb.Match, error) {
+	ids := []string{}
+	for _, m := range proposals {
+		ids = append(ids, getTicketIds(m.GetTickets())...)
+	}
+	stored, err := store.GetTickets(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	valid := make(map[string]bool, len(stored))
+	for _, t := range stored {
+		valid[t.GetId()] = t.GetAssignment() == nil
+	}
+	kept := []*pb.Match{}
+Proposals:
+	for _, m := range proposals {
+		for _, t := range m.GetTickets() {
+			if !valid[t.GetId()] {
+				continue Proposals
+			}
+		}
+		kept = append(kept, m)
+	}
+	return kept, nil
+}
+
+// duplicateProposalFilter keeps only the first of the proposals made by the
+// same profile and match function with the same tickets.
+type duplicateProposalFilter struct {
+	seen map[string]struct{}
+}
+
+func (f *duplicateProposalFilter) FilterProposals(ctx context.Context, store statestore.Service, proposals []*pb.Match) ([]*pb.Match, error) {
+	kept := []*pb.Match{}
+	for _, m := range proposals {
+		ids := getTicketIds(m.GetTickets())
+		sort.Strings(ids)
+		key := strings.Join(append([]string{m.GetMatchProfile(), m.GetMatchFunction()}, ids...), "\x00")
+		if _, ok := f.seen[key]; ok {
+			continue
+		}
+		f.seen[key] = struct{}{}
+		kept = append(kept, m)
+	}
+	return kept, nil
+}
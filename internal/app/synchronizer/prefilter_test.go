@@ -0,0 +1,111 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synchronizer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"open-match.dev/open-match/internal/clock"
+	"open-match.dev/open-match/internal/statestore"
+	statestoreTesting "open-match.dev/open-match/internal/statestore/testing"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestStaleProposalFilter(t *testing.T) {
+	assert := assert.New(t)
+	ctx := utilTesting.NewContext(t)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, viper.New())
+	defer closer()
+
+	assert.Nil(store.CreateTickets(ctx, []*pb.Ticket{{Id: "a"}, {Id: "b"}, {Id: "c"}}))
+	_, _, err := store.UpdateAssignments(ctx, []string{"c"}, &pb.Assignment{Connection: "server"})
+	assert.Nil(err)
+
+	kept, err := staleProposalFilter{}.FilterProposals(ctx, store, []*pb.Match{
+		proposal("1", "a", "b"),
+		proposal("2", "a", "deleted"),
+		proposal("3", "b", "c"),
+	})
+	assert.Nil(err)
+	assert.Equal([]string{"1"}, matchIDs(kept))
+}
+
+func TestDuplicateProposalFilter(t *testing.T) {
+	assert := assert.New(t)
+	fromProfile := func(id, profile string, ticketIDs ...string) *pb.Match {
+		m := proposal(id, ticketIDs...)
+		m.MatchProfile = profile
+		m.MatchFunction = "mmf"
+		return m
+	}
+
+	f := &duplicateProposalFilter{seen: make(map[string]struct{})}
+	kept, err := f.FilterProposals(context.Background(), nil, []*pb.Match{
+		fromProfile("1", "p", "a", "b"),
+		fromProfile("2", "p", "b", "a"),
+		fromProfile("3", "q", "a", "b"),
+	})
+	assert.Nil(err)
+	assert.Equal([]string{"1", "3"}, matchIDs(kept))
+
+	// Duplicates are dropped across the cycle's batches.
+	kept, err = f.FilterProposals(context.Background(), nil, []*pb.Match{
+		fromProfile("4", "q", "b", "a"),
+		fromProfile("5", "q", "a"),
+	})
+	assert.Nil(err)
+	assert.Equal([]string{"5"}, matchIDs(kept))
+}
+
+// evenProposalFilter drops every other proposal of the cycle.
+type evenProposalFilter struct {
+	n int
+}
+
+func (f *evenProposalFilter) FilterProposals(ctx context.Context, store statestore.Service, proposals []*pb.Match) ([]*pb.Match, error) {
+	kept := []*pb.Match{}
+	for _, m := range proposals {
+		if f.n%2 == 0 {
+			kept = append(kept, m)
+		}
+		f.n++
+	}
+	return kept, nil
+}
+
+func TestFilterProposals(t *testing.T) {
+	assert := assert.New(t)
+	RegisterProposalFilter("even", func() ProposalFilter { return &evenProposalFilter{} })
+
+	cfg := viper.New()
+	cfg.Set("synchronizer.proposalFilters", []string{"unknown", "dedupeProposals", "even"})
+	s := newSynchronizerService(cfg, &recordingEvaluator{}, nil, clock.Real())
+
+	in := make(chan []*pb.Match, 2)
+	in <- []*pb.Match{proposal("1", "a"), proposal("2", "a"), proposal("3", "b")}
+	in <- []*pb.Match{proposal("4", "b"), proposal("5", "c"), proposal("6", "d")}
+	close(in)
+
+	ids := []string{}
+	for matches := range s.filterProposals(context.Background(), logrus.NewEntry(logrus.New()), in) {
+		ids = append(ids, matchIDs(matches)...)
+	}
+	assert.Equal([]string{"1", "5"}, ids)
+}
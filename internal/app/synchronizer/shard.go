@@ -0,0 +1,194 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synchronizer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/sirupsen/logrus"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// shardingEvaluator routes each proposal to one of several evaluators, so that
+// game modes can be evaluated by separate services.  A proposal goes to the
+// shard named by its pb.EvaluatorShardExtensionKey extension, else to the first
+// shard whose profile prefix starts its MatchProfile, else to the fallback
+// evaluator.  Evaluators are only called if they're routed proposals.
+//
+// Each shard only sees its own proposals, so proposals sharing tickets may be
+// accepted by different shards.  Of those, only the match accepted first is
+// kept.
+type shardingEvaluator struct {
+	shards   []*evaluatorShard
+	fallback evaluator
+}
+
+type evaluatorShard struct {
+	name          string
+	profilePrefix string
+	evaluator
+}
+
+// newShardingEvaluator creates a shard for each name, evaluated by the
+// endpoint configured under api.evaluatorShards.<name> and routed proposals
+// of profiles starting with api.evaluatorShards.<name>.profilePrefix, if set.
+func newShardingEvaluator(cfg config.View, names []string, fallback evaluator) *shardingEvaluator {
+	se := &shardingEvaluator{fallback: fallback}
+	for _, name := range names {
+		key := "api.evaluatorShards." + name
+		se.shards = append(se.shards, &evaluatorShard{
+			name:          name,
+			profilePrefix: cfg.GetString(key + ".profilePrefix"),
+			evaluator:     newEndpointEvaluator(cfg, key),
+		})
+	}
+	return se
+}
+
+// route returns the index of the proposal's shard, or len(se.shards) for the
+// fallback evaluator.
+func (se *shardingEvaluator) route(m *pb.Match) int {
+	name := &wrappers.StringValue{}
+	ok, err := pb.GetExtension(m.GetExtensions(), pb.EvaluatorShardExtensionKey, name)
+	if err != nil {
+		evaluatorClientLogger.WithFields(logrus.Fields{
+			"match_id": m.GetMatchId(),
+			"error":    err,
+		}).Warning("failed to unpack the match's evaluator shard, routing it by profile")
+	}
+	if ok && err == nil {
+		for i, s := range se.shards {
+			if s.name == name.GetValue() {
+				return i
+			}
+		}
+		evaluatorClientLogger.WithFields(logrus.Fields{
+			"match_id": m.GetMatchId(),
+			"shard":    name.GetValue(),
+		}).Warning("match names an unknown evaluator shard, routing it by profile")
+	}
+
+	for i, s := range se.shards {
+		if s.profilePrefix != "" && strings.HasPrefix(m.GetMatchProfile(), s.profilePrefix) {
+			return i
+		}
+	}
+	return len(se.shards)
+}
+
+func (se *shardingEvaluator) evaluate(ctx context.Context, pc <-chan []*pb.Match, results chan<- string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var m sync.Mutex
+	var firstErr error
+	// ticketsOfMatch holds the ticket ids of the routed proposals, and
+	// acceptedTickets those of the matches accepted so far.
+	ticketsOfMatch := make(map[string][]string)
+	acceptedTickets := make(map[string]struct{})
+
+	// Proposals are buffered per shard so that a slow evaluator doesn't hold up
+	// the others.
+	inputs := make([]chan *pb.Match, len(se.shards)+1)
+	start := func(i int) {
+		eval, name := se.fallback, ""
+		if i < len(se.shards) {
+			eval, name = se.shards[i].evaluator, se.shards[i].name
+		}
+		in := bufferMatchChannel(inputs[i], 0)
+
+		ids, flush := filterResults(results, func(id string) bool {
+			m.Lock()
+			defer m.Unlock()
+			if overlaps(acceptedTickets, ticketsOfMatch[id]) {
+				evaluatorClientLogger.WithFields(logrus.Fields{
+					"match_id": id,
+					"shard":    name,
+				}).Warning("dropped a match sharing tickets with a match accepted by another shard")
+				return false
+			}
+			for _, t := range ticketsOfMatch[id] {
+				acceptedTickets[t] = struct{}{}
+			}
+			return true
+		})
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := eval.evaluate(ctx, in, ids)
+			flush()
+			// Keep routing from blocking on an evaluator which stopped early.
+			for range in {
+			}
+			if err == nil {
+				return
+			}
+
+			if name != "" {
+				err = fmt.Errorf("evaluator shard %s failed: %w", name, err)
+			}
+			m.Lock()
+			defer m.Unlock()
+			if firstErr == nil {
+				firstErr = err
+				cancel()
+			}
+		}()
+	}
+
+	for matches := range pc {
+		for _, match := range matches {
+			ids := make([]string, 0, len(match.GetTickets()))
+			for _, t := range match.GetTickets() {
+				ids = append(ids, t.GetId())
+			}
+			m.Lock()
+			ticketsOfMatch[match.GetMatchId()] = ids
+			m.Unlock()
+
+			i := se.route(match)
+			if inputs[i] == nil {
+				inputs[i] = make(chan *pb.Match)
+				start(i)
+			}
+			inputs[i] <- match
+		}
+	}
+	for _, in := range inputs {
+		if in != nil {
+			close(in)
+		}
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// overlaps returns whether any of the ids is in set.
+func overlaps(set map[string]struct{}, ids []string) bool {
+	for _, id := range ids {
+		if _, ok := set[id]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synchronizer
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func shardProposal(t *testing.T, id, profile, shard string) *pb.Match {
+	m := proposal(id)
+	m.MatchProfile = profile
+	if shard != "" {
+		var err error
+		m.Extensions, err = pb.SetExtension(m.Extensions, pb.EvaluatorShardExtensionKey, &wrappers.StringValue{Value: shard})
+		require.Nil(t, err)
+	}
+	return m
+}
+
+func TestNewShardingEvaluator(t *testing.T) {
+	cfg := viper.New()
+	cfg.Set("synchronizer.evaluatorShards", []string{"ranked", "casual"})
+	cfg.Set("api.evaluatorShards.ranked.profilePrefix", "ranked-")
+
+	se, ok := newEvaluator(cfg).(*shardingEvaluator)
+	require.True(t, ok)
+	require.Len(t, se.shards, 2)
+	assert.Equal(t, "ranked", se.shards[0].name)
+	assert.Equal(t, "ranked-", se.shards[0].profilePrefix)
+	assert.Equal(t, "casual", se.shards[1].name)
+	assert.Equal(t, "", se.shards[1].profilePrefix)
+}
+
+func TestShardingEvaluator(t *testing.T) {
+	assert := assert.New(t)
+
+	ranked, casual, unused, fallback := &recordingEvaluator{}, &recordingEvaluator{}, &recordingEvaluator{}, &recordingEvaluator{}
+	se := &shardingEvaluator{
+		shards: []*evaluatorShard{
+			{name: "ranked", profilePrefix: "ranked-", evaluator: ranked},
+			{name: "casual", profilePrefix: "casual-", evaluator: casual},
+			{name: "unused", evaluator: unused},
+		},
+		fallback: fallback,
+	}
+
+	pc := make(chan []*pb.Match, 2)
+	pc <- []*pb.Match{
+		shardProposal(t, "1", "ranked-1v1", ""),
+		shardProposal(t, "2", "casual-ffa", ""),
+		shardProposal(t, "3", "ranked-2v2", "casual"),
+	}
+	pc <- []*pb.Match{
+		shardProposal(t, "4", "arcade", ""),
+		shardProposal(t, "5", "ranked-5v5", "missing"),
+	}
+	close(pc)
+
+	ids, err := evaluateAll(se, pc)
+	assert.Nil(err)
+	sort.Strings(ids)
+	assert.Equal([]string{"1", "2", "3", "4", "5"}, ids)
+
+	assert.Equal([][]string{{"1", "5"}}, ranked.calls)
+	assert.Equal([][]string{{"2", "3"}}, casual.calls)
+	assert.Equal([][]string{{"4"}}, fallback.calls)
+	assert.Empty(unused.calls)
+
+	casual.err = errors.New("casual evaluator is down")
+	_, err = evaluateAll(se, sendAll([]*pb.Match{
+		shardProposal(t, "1", "ranked-1v1", ""),
+		shardProposal(t, "2", "casual-ffa", ""),
+	}))
+	assert.True(errors.Is(err, casual.err))
+}
+
+func TestShardingEvaluatorOverlappingShards(t *testing.T) {
+	assert := assert.New(t)
+
+	se := &shardingEvaluator{
+		shards: []*evaluatorShard{
+			{name: "ranked", profilePrefix: "ranked-", evaluator: &recordingEvaluator{}},
+			{name: "casual", profilePrefix: "casual-", evaluator: &recordingEvaluator{}},
+		},
+		fallback: &recordingEvaluator{},
+	}
+
+	ranked := shardProposal(t, "1", "ranked-1v1", "")
+	ranked.Tickets = proposal("", "a", "b").GetTickets()
+	casual := shardProposal(t, "2", "casual-ffa", "")
+	casual.Tickets = proposal("", "b", "c").GetTickets()
+	arcade := shardProposal(t, "3", "arcade", "")
+	arcade.Tickets = proposal("", "d").GetTickets()
+
+	// Each shard accepts its proposal, but only one of those sharing ticket b
+	// is kept.
+	ids, err := evaluateAll(se, sendAll([]*pb.Match{ranked, casual, arcade}))
+	assert.Nil(err)
+	assert.Len(ids, 2)
+	assert.Contains(ids, "3")
+	assert.True(contains(ids, "1") != contains(ids, "2"), "kept %v", ids)
+}
+
+func contains(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -16,6 +16,7 @@ package synchronizer
 
 import (
 	"google.golang.org/grpc"
+	"open-match.dev/open-match/internal/clock"
 	"open-match.dev/open-match/internal/config"
 	"open-match.dev/open-match/internal/ipb"
 	"open-match.dev/open-match/internal/rpc"
@@ -24,8 +25,16 @@ import (
 
 // BindService creates the synchronizer service and binds it to the serving harness.
 func BindService(p *rpc.ServerParams, cfg config.View) error {
-	store := statestore.New(cfg)
-	service := newSynchronizerService(cfg, newEvaluator(cfg), store)
+	return BindServiceWithClock(p, cfg, clock.Real())
+}
+
+// BindServiceWithClock creates the synchronizer service, using clk to time
+// registration and proposal collection windows, and binds it to the serving
+// harness.
+func BindServiceWithClock(p *rpc.ServerParams, cfg config.View, clk clock.Clock) error {
+	store := statestore.NewWithClock(cfg, clk)
+	service := newSynchronizerService(cfg, withEvaluatorPolicy(cfg, clk, withClustering(cfg, newEvaluator(cfg))), store, clk)
+	service.releaseAbandonedCycles(clk.Now())
 	p.AddHealthCheckFunc(store.HealthCheck)
 	p.AddHandleFunc(func(s *grpc.Server) {
 		ipb.RegisterSynchronizerServer(s, service)
@@ -21,10 +21,15 @@ import (
 	"sync"
 	"time"
 
+	"github.com/rs/xid"
 	"github.com/sirupsen/logrus"
+	harness "open-match.dev/open-match/internal/app/evaluator"
+	"open-match.dev/open-match/internal/clock"
 	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/featuregate"
 	"open-match.dev/open-match/internal/ipb"
 	"open-match.dev/open-match/internal/statestore"
+	"open-match.dev/open-match/internal/util"
 	"open-match.dev/open-match/pkg/pb"
 )
 
@@ -39,8 +44,9 @@ var (
 // are used to be consistent between function calls to help track everything.
 
 // Streams from multiple GRPC calls of matches are combined on a single channel.
-// These matches are sent to the evaluator, then the tickets are added to the
-// ignore list.  Finally the matches are returned to the calling stream.
+// These matches are sent to the evaluator, then the leases the backend took on
+// the tickets of accepted matches are extended, and those of rejected matches
+// released.  Finally the matches are returned to the calling stream.
 
 // receive from backend                  | Synchronize
 //  -> m1c ->
@@ -50,9 +56,13 @@ var (
 //   -> m3c ->
 // setmappings from matchIDs to ticketIDs| cacheMatchIDToTicketIDs
 //   -> m4c -> (buffered)
+// optionally mark stale tickets         | verifyTickets
+// run configured proposal filters       | filterProposals
 // send to evaluator                     | wrapEvaluator
 //   -> m5c -> (buffered)
-// add tickets to ignore list            | addMatchesToIgnoreList
+// mark matches with deleted tickets     | leaseMatchTickets
+// extend leases of match tickets        | leaseMatchTickets
+// release leases of rejected proposals  | leaseMatchTickets
 //   -> m6c ->
 // fan out to origin synchronize call    | fanInFanOut
 //   -> (Synchronize call specific ) m7c -> (buffered)
@@ -62,6 +72,7 @@ type synchronizerService struct {
 	cfg   config.View
 	store statestore.Service
 	eval  evaluator
+	clk   clock.Clock
 
 	synchronizeRegistration chan *registrationRequest
 
@@ -70,11 +81,12 @@ type synchronizerService struct {
 	startCycle chan struct{}
 }
 
-func newSynchronizerService(cfg config.View, eval evaluator, store statestore.Service) *synchronizerService {
+func newSynchronizerService(cfg config.View, eval evaluator, store statestore.Service, clk clock.Clock) *synchronizerService {
 	s := &synchronizerService{
 		cfg:   cfg,
 		store: store,
 		eval:  eval,
+		clk:   clk,
 
 		synchronizeRegistration: make(chan *registrationRequest),
 		startCycle:              make(chan struct{}, 1),
@@ -85,6 +97,23 @@ func newSynchronizerService(cfg config.View, eval evaluator, store statestore.Se
 	return s
 }
 
+// releaseAbandonedCycles releases the leases of the cycles a previous
+// synchronizer process was running when it stopped, so that their proposed
+// tickets don't wait for storage.ignoreListTTL to be queried again.
+func (s *synchronizerService) releaseAbandonedCycles(startedBefore time.Time) {
+	cycles, released, err := s.store.ReleaseAbandonedCycles(context.Background(), startedBefore)
+	if err != nil {
+		logger.WithError(err).Warning("failed to release the leases of abandoned cycles, they expire after storage.ignoreListTTL")
+		return
+	}
+	if len(cycles) > 0 {
+		logger.WithFields(logrus.Fields{
+			"cycles":   cycles,
+			"released": released,
+		}).Info("released the leases of cycles abandoned by a previous synchronizer")
+	}
+}
+
 func (s *synchronizerService) Synchronize(stream ipb.Synchronizer_SynchronizeServer) error {
 	// Synchronize first registers against a cycle.  Then it creates two go
 	// routines:
@@ -114,7 +143,7 @@ func (s *synchronizerService) Synchronize(stream ipb.Synchronizer_SynchronizeSer
 		}
 	}()
 
-	err := stream.Send(&ipb.SynchronizeResponse{StartMmfs: true})
+	err := stream.Send(&ipb.SynchronizeResponse{StartMmfs: true, CycleId: registration.cycleID})
 	if err != nil {
 		return err
 	}
@@ -126,7 +155,8 @@ func (s *synchronizerService) Synchronize(stream ipb.Synchronizer_SynchronizeSer
 				return nil
 			}
 			for _, mID := range mIDs {
-				err = stream.Send(&ipb.SynchronizeResponse{MatchId: mID})
+				_, invalid := registration.invalidMatches.Load(mID)
+				err = stream.Send(&ipb.SynchronizeResponse{MatchId: mID, MatchInvalid: invalid})
 				if err != nil {
 					logger.WithFields(logrus.Fields{
 						"error": err.Error(),
@@ -174,6 +204,9 @@ type registration struct {
 	m7c        chan string
 	cancelMmfs chan struct{}
 	cycleCtx   context.Context
+	cycleID    string
+	// invalidMatches holds the ids of the cycle's matches with deleted tickets.
+	invalidMatches *sync.Map
 }
 
 func (s synchronizerService) register(ctx context.Context) *registration {
@@ -199,7 +232,20 @@ func (s synchronizerService) register(ctx context.Context) *registration {
 
 func (s *synchronizerService) runCycle() {
 	/////////////////////////////////////// Initialize cycle
-	ctx, cancel := withCancelCause(context.Background())
+	cycleID := xid.New().String()
+	cycleLogger := logger.WithField("cycleId", cycleID)
+	// The cycle id is passed to the evaluator as request metadata.
+	idCtx, err := util.AppendSynchronizerContextID(context.Background(), cycleID)
+	if err != nil {
+		cycleLogger.WithError(err).Error("failed to add cycle id to the cycle context")
+	}
+	ctx, cancel := withCancelCause(idCtx)
+	cycleLogger.Debug("starting synchronizer cycle")
+	// The leases of a recorded cycle are released by the next synchronizer if
+	// this one stops mid-cycle.
+	if err := s.store.RecordCycle(context.Background(), cycleID); err != nil {
+		cycleLogger.WithError(err).Warning("failed to record the cycle, its leases expire after storage.ignoreListTTL if the synchronizer stops mid-cycle")
+	}
 
 	m2c := make(chan mAndM6c)
 	m3c := make(chan *pb.Match)
@@ -226,17 +272,21 @@ func (s *synchronizerService) runCycle() {
 	}()
 
 	matchTickets := &sync.Map{}
+	invalidMatches := &sync.Map{}
 	go s.cacheMatchIDToTicketIDs(matchTickets, m3c, m4c)
-	go s.wrapEvaluator(ctx, cancel, bufferMatchChannel(m4c), m5c)
+	go s.wrapEvaluator(ctx, cycleLogger, cancel, s.filterProposals(ctx, cycleLogger, s.verifyTickets(ctx, cycleLogger, bufferMatchChannel(m4c, s.maxBufferedProposals()))), m5c)
 	go func() {
-		s.addMatchesToIgnoreList(ctx, matchTickets, cancel, bufferStringChannel(m5c), m6c)
-		// Wait for ignore list, but not all matches returned, the next cycle
-		// can start now.
+		s.leaseMatchTickets(ctx, cycleLogger, cycleID, matchTickets, invalidMatches, cancel, bufferStringChannel(m5c), m6c)
+		if err := s.store.FinishCycle(context.Background(), cycleID); err != nil {
+			cycleLogger.WithError(err).Warning("failed to finish the cycle, a restarted synchronizer may release the leases of its matches")
+		}
+		// Wait for leases, but not all matches returned, the next cycle can
+		// start now.
 		close(closedOnCycleEnd)
 	}()
 
 	/////////////////////////////////////// Run Registration Period
-	closeRegistration := time.After(s.registrationInterval())
+	closeRegistration := s.clk.After(s.registrationInterval())
 Registration:
 	for {
 		select {
@@ -248,7 +298,10 @@ Registration:
 				m7c:        make(chan string),
 				cancelMmfs: make(chan struct{}, 1),
 				cycleCtx:   ctx,
+				cycleID:    cycleID,
 				allM1cSent: &allM1cSent,
+
+				invalidMatches: invalidMatches,
 			}
 			registrations = append(registrations, r)
 			req.resp <- r
@@ -270,7 +323,7 @@ Registration:
 		m1c.cutoff()
 	}()
 
-	cancelProposalCollection := time.AfterFunc(s.proposalCollectionInterval(), func() {
+	cancelProposalCollection := s.clk.AfterFunc(s.proposalCollectionInterval(), func() {
 		m1c.cutoff()
 		for _, r := range registrations {
 			r.cancelMmfs <- struct{}{}
@@ -280,6 +333,7 @@ Registration:
 
 	// Clean up in case it was never needed.
 	cancelProposalCollection.Stop()
+	cycleLogger.WithField("registrations", len(registrations)).Debug("finished synchronizer cycle")
 }
 
 ///////////////////////////////////////
@@ -386,18 +440,60 @@ func (c *cutoffSender) cutoff() {
 ///////////////////////////////////////
 ///////////////////////////////////////
 
-// Calls the evaluator with the matches.
-func (s *synchronizerService) wrapEvaluator(ctx context.Context, cancel cancelErrFunc, m3c <-chan []*pb.Match, m5c chan<- string) {
-	matchIDs, err := s.eval.evaluate(ctx, m3c)
-	if err == nil {
-		for _, mID := range matchIDs {
-			m5c <- mID
+// verifyTickets marks the tickets of each proposal with whether they still
+// exist unassigned in state storage, so that evaluators can cheaply drop stale
+// proposals.  Enabled by synchronizer.verifyTicketsBeforeEvaluation.  If the
+// lookup fails, proposals are passed to the evaluator unmarked.
+func (s *synchronizerService) verifyTickets(ctx context.Context, cycleLogger *logrus.Entry, in chan []*pb.Match) chan []*pb.Match {
+	if !featuregate.Enabled(s.cfg, featuregate.VerifyTicketsBeforeEvaluation) {
+		return in
+	}
+
+	out := make(chan []*pb.Match)
+	go func() {
+		for matches := range in {
+			ids := []string{}
+			for _, m := range matches {
+				ids = append(ids, getTicketIds(m.GetTickets())...)
+			}
+
+			stored, err := s.store.GetTickets(ctx, ids)
+			if err != nil {
+				cycleLogger.WithError(err).Warning("failed to verify proposal tickets, sending them to the evaluator unverified")
+				out <- matches
+				continue
+			}
+
+			valid := make(map[string]bool, len(stored))
+			for _, t := range stored {
+				valid[t.GetId()] = t.GetAssignment() == nil
+			}
+			for _, m := range matches {
+				for _, t := range m.GetTickets() {
+					if err := harness.SetTicketValid(t, valid[t.GetId()]); err != nil {
+						cycleLogger.WithError(err).Error("failed to mark ticket validity")
+					}
+				}
+			}
+			out <- matches
 		}
-	} else {
-		logger.WithFields(logrus.Fields{
+		close(out)
+	}()
+	return out
+}
+
+// Calls the evaluator with the matches, sending the ids of the matches it
+// accepts to m5c as they're received.
+func (s *synchronizerService) wrapEvaluator(ctx context.Context, cycleLogger *logrus.Entry, cancel cancelErrFunc, m3c <-chan []*pb.Match, m5c chan<- string) {
+	if err := s.eval.evaluate(ctx, m3c, m5c); err != nil {
+		cycleLogger.WithFields(logrus.Fields{
 			"error": err,
 		}).Error("error calling evaluator, canceling cycle")
 		cancel(fmt.Errorf("error calling evaluator: %w", err))
+		// Wait for the remaining proposals so that their tickets are cached, and
+		// released along with the rest once m5c is closed.
+		for range m3c {
+		}
 	}
 	close(m5c)
 }
@@ -424,30 +520,55 @@ func getTicketIds(tickets []*pb.Ticket) []string {
 ///////////////////////////////////////
 ///////////////////////////////////////
 
-// Calls statestore to add all of the tickets returned by the evaluator to the
-// ignorelist.  If it partially fails for whatever reason (not all tickets will
-// nessisarily be in the same call), only the matches which can be safely
-// returned to the Synchronize calls are.
-func (s *synchronizerService) addMatchesToIgnoreList(ctx context.Context, m *sync.Map, cancel cancelErrFunc, m5c <-chan []string, m6c chan<- string) {
+// Calls statestore to extend the leases on all of the tickets returned by the
+// evaluator, which the backend took when they were proposed.  Tickets which
+// aren't leased yet are leased now.  If it partially fails for whatever reason
+// (not all tickets will nessisarily be in the same call), only the matches
+// which can be safely returned to the Synchronize calls are.  Matches with
+// tickets deleted since they were proposed, or leased by another cycle, are
+// recorded in invalidMatches.  Once all matches are returned, the leases on the
+// tickets of rejected and invalid matches are released, so that they're
+// matched again right away.
+func (s *synchronizerService) leaseMatchTickets(ctx context.Context, cycleLogger *logrus.Entry, cycleID string, m *sync.Map, invalidMatches *sync.Map, cancel cancelErrFunc, m5c <-chan []string, m6c chan<- string) {
 	totalMatches := 0
 	successfulMatches := 0
+	accepted := make(map[string]struct{})
 	var lastErr error
 	for mIDs := range m5c {
+		matchTicketIDs := make(map[string][]string, len(mIDs))
 		ids := []string{}
 		for _, mID := range mIDs {
 			tids, ok := m.Load(mID)
 			if ok {
+				matchTicketIDs[mID] = tids.([]string)
 				ids = append(ids, tids.([]string)...)
 			} else {
-				logger.Errorf("failed to get MatchId %s with its corresponding tickets from the cache", mID)
+				cycleLogger.Errorf("failed to get MatchId %s with its corresponding tickets from the cache", mID)
 			}
 		}
 
-		err := s.store.AddTicketsToIgnoreList(ctx, ids)
+		deleted := s.findDeletedTickets(ctx, cycleLogger, ids)
+		if len(deleted) > 0 {
+			cycleLogger.WithField("deletedTickets", len(deleted)).Warning("tickets were deleted after being proposed, their matches are returned as invalid")
+		}
+
+		held, err := s.extendLeases(ctx, cycleID, validTicketIDs(matchTicketIDs, deleted))
+		if len(held) > 0 {
+			cycleLogger.WithField("heldTickets", len(held)).Warning("tickets were leased by another cycle after being proposed, their matches are returned as invalid")
+		}
 
 		totalMatches += len(mIDs)
 		if err == nil {
 			successfulMatches += len(mIDs)
+			for mID, tids := range matchTicketIDs {
+				if containsAny(deleted, tids) || containsAny(held, tids) {
+					invalidMatches.Store(mID, struct{}{})
+					continue
+				}
+				for _, id := range tids {
+					accepted[id] = struct{}{}
+				}
+			}
 		} else {
 			lastErr = err
 		}
@@ -458,19 +579,113 @@ func (s *synchronizerService) addMatchesToIgnoreList(ctx context.Context, m *syn
 	}
 
 	if lastErr != nil {
-		logger.WithFields(logrus.Fields{
+		cycleLogger.WithFields(logrus.Fields{
 			"error":             lastErr.Error(),
 			"totalMatches":      totalMatches,
 			"successfulMatches": successfulMatches,
-		}).Error("some or all matches were not successfully added to the ignore list, failed matches dropped")
+		}).Error("some or all matches were not successfully leased, failed matches dropped")
 
 		if successfulMatches == 0 {
-			cancel(fmt.Errorf("no matches successfully added to the ignore list.  Last error: %w", lastErr))
+			cancel(fmt.Errorf("no matches successfully leased.  Last error: %w", lastErr))
 		}
 	}
+
+	s.releaseRejectedTickets(cycleLogger, cycleID, m, accepted)
 	close(m6c)
 }
 
+// extendLeases extends the cycle's leases on the tickets, acquiring those it
+// doesn't hold yet.  Returns the set of tickets leased by another cycle.
+func (s *synchronizerService) extendLeases(ctx context.Context, cycleID string, ids []string) (map[string]struct{}, error) {
+	missing, err := s.store.ExtendLease(ctx, cycleID, ids)
+	if err != nil || len(missing) == 0 {
+		return nil, err
+	}
+	held, err := s.store.AcquireTicketLease(ctx, cycleID, missing)
+	if err != nil {
+		return nil, err
+	}
+	r := make(map[string]struct{}, len(held))
+	for _, id := range held {
+		r[id] = struct{}{}
+	}
+	return r, nil
+}
+
+// releaseRejectedTickets releases the cycle's leases on all proposed tickets
+// which aren't part of an accepted match.  Failing to release them only delays
+// matching them again until the leases expire.
+func (s *synchronizerService) releaseRejectedTickets(cycleLogger *logrus.Entry, cycleID string, m *sync.Map, accepted map[string]struct{}) {
+	rejected := []string{}
+	seen := make(map[string]struct{})
+	m.Range(func(_, tids interface{}) bool {
+		for _, id := range tids.([]string) {
+			if _, ok := accepted[id]; ok {
+				continue
+			}
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			rejected = append(rejected, id)
+		}
+		return true
+	})
+
+	// The cycle context is canceled once all callers are done, which must not
+	// keep the leases from being released.
+	if err := s.store.ReleaseTicketLease(context.Background(), cycleID, rejected); err != nil {
+		cycleLogger.WithError(err).Warning("failed to release the leases of rejected proposals, they expire after storage.ignoreListTTL")
+	}
+}
+
+// validTicketIDs returns the ids of the tickets of the matches without any
+// deleted tickets.
+func validTicketIDs(matchTicketIDs map[string][]string, deleted map[string]struct{}) []string {
+	ids := []string{}
+	for _, tids := range matchTicketIDs {
+		if containsAny(deleted, tids) {
+			continue
+		}
+		ids = append(ids, tids...)
+	}
+	return ids
+}
+
+// findDeletedTickets returns the set of ids which no longer exist in state
+// storage.  If the lookup fails, all tickets are assumed to exist.
+func (s *synchronizerService) findDeletedTickets(ctx context.Context, cycleLogger *logrus.Entry, ids []string) map[string]struct{} {
+	if len(ids) == 0 {
+		return nil
+	}
+	stored, err := s.store.GetTickets(ctx, ids)
+	if err != nil {
+		cycleLogger.WithError(err).Warning("failed to check for deleted tickets in matches")
+		return nil
+	}
+	if len(stored) == len(ids) {
+		return nil
+	}
+
+	deleted := make(map[string]struct{}, len(ids)-len(stored))
+	for _, id := range ids {
+		deleted[id] = struct{}{}
+	}
+	for _, t := range stored {
+		delete(deleted, t.GetId())
+	}
+	return deleted
+}
+
+func containsAny(set map[string]struct{}, ids []string) bool {
+	for _, id := range ids {
+		if _, ok := set[id]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 ///////////////////////////////////////
 ///////////////////////////////////////
 
@@ -487,6 +702,12 @@ func (s *synchronizerService) registrationInterval() time.Duration {
 	return s.cfg.GetDuration(name)
 }
 
+// maxBufferedProposals bounds the proposals of a cycle buffered while the
+// evaluator is busy, unbounded if 0.
+func (s *synchronizerService) maxBufferedProposals() int {
+	return s.cfg.GetInt("synchronizer.maxBufferedProposals")
+}
+
 func (s *synchronizerService) proposalCollectionInterval() time.Duration {
 	const (
 		name            = "synchronizer.proposalCollectionIntervalMs"
@@ -506,9 +727,10 @@ func (s *synchronizerService) proposalCollectionInterval() time.Duration {
 // bufferMatchChannel collects matches from the input, and sends
 // slice of matches on the output.  It never (for long) blocks
 // the input channel, always appending to the slice which will
-// next be used for output.  Used before external calls, so that
-// network won't back up internal processing.
-func bufferMatchChannel(in chan *pb.Match) chan []*pb.Match {
+// next be used for output, until it holds max matches.  Used
+// before external calls, so that network won't back up internal
+// processing.
+func bufferMatchChannel(in chan *pb.Match, max int) chan []*pb.Match {
 	out := make(chan []*pb.Match)
 	go func() {
 		var a []*pb.Match
@@ -522,6 +744,13 @@ func bufferMatchChannel(in chan *pb.Match) chan []*pb.Match {
 			a = []*pb.Match{m}
 
 			for len(a) > 0 {
+				// Stop reading the input while full, which pushes back on the
+				// backend streams sending proposals.
+				if max > 0 && len(a) >= max {
+					out <- a
+					a = nil
+					break
+				}
 				select {
 				case m, ok := <-in:
 					if !ok {
@@ -0,0 +1,49 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synchronizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestBufferMatchChannelMax(t *testing.T) {
+	assert := assert.New(t)
+	in := make(chan *pb.Match)
+	out := bufferMatchChannel(in, 2)
+
+	in <- &pb.Match{MatchId: "1"}
+	in <- &pb.Match{MatchId: "2"}
+
+	// The buffer is full, so the input is no longer read.
+	select {
+	case in <- &pb.Match{MatchId: "3"}:
+		assert.Fail("sent a match beyond the buffer's max")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	matches := <-out
+	assert.Len(matches, 2)
+
+	in <- &pb.Match{MatchId: "3"}
+	close(in)
+	matches = <-out
+	assert.Equal("3", matches[0].GetMatchId())
+	_, ok := <-out
+	assert.False(ok)
+}
@@ -0,0 +1,171 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package clock abstracts the passage of time so that time dependent Open
+// Match behavior can be driven by a virtual clock in simulations and tests.
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock provides the subset of the time package used by Open Match services.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After waits for the duration to elapse and then sends the current time
+	// on the returned channel.
+	After(d time.Duration) <-chan time.Time
+
+	// AfterFunc waits for the duration to elapse and then calls f in its own
+	// goroutine.
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer represents a single pending event created by Clock.AfterFunc.
+type Timer interface {
+	// Stop prevents the Timer from firing. It returns false if the timer has
+	// already fired or been stopped.
+	Stop() bool
+}
+
+// Real returns a Clock backed by the time package.
+func Real() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+// Virtual is a Clock which only moves when Advance is called. Timers are fired
+// in deadline order as the clock passes them, which lets hours of matchmaking
+// be simulated in seconds.
+type Virtual struct {
+	m       sync.Mutex
+	now     time.Time
+	waiters []*virtualTimer
+}
+
+// NewVirtual returns a Virtual clock starting at start.
+func NewVirtual(start time.Time) *Virtual {
+	return &Virtual{now: start}
+}
+
+// Now returns the current virtual time.
+func (v *Virtual) Now() time.Time {
+	v.m.Lock()
+	defer v.m.Unlock()
+	return v.now
+}
+
+// After returns a channel which receives the virtual time once the clock has
+// been advanced by at least d.
+func (v *Virtual) After(d time.Duration) <-chan time.Time {
+	c := make(chan time.Time, 1)
+	v.schedule(d, func(now time.Time) {
+		c <- now
+	})
+	return c
+}
+
+// AfterFunc calls f in its own goroutine once the clock has been advanced by
+// at least d.
+func (v *Virtual) AfterFunc(d time.Duration, f func()) Timer {
+	return v.schedule(d, func(time.Time) {
+		go f()
+	})
+}
+
+// Advance moves the clock forward by d, firing all timers whose deadline has
+// been reached.
+func (v *Virtual) Advance(d time.Duration) {
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	target := v.now.Add(d)
+	for len(v.waiters) > 0 && !v.waiters[0].deadline.After(target) {
+		w := v.waiters[0]
+		v.waiters = v.waiters[1:]
+		// Timers observe the time they were scheduled for, not the target, so
+		// that a large step behaves like a series of small ones.
+		v.now = w.deadline
+		w.fire(v.now)
+	}
+	v.now = target
+}
+
+// Pending returns the number of timers which have not yet fired. Simulation
+// drivers can use it to wait for services to reach their next timer before
+// advancing the clock.
+func (v *Virtual) Pending() int {
+	v.m.Lock()
+	defer v.m.Unlock()
+	return len(v.waiters)
+}
+
+func (v *Virtual) schedule(d time.Duration, fire func(time.Time)) *virtualTimer {
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	w := &virtualTimer{
+		v:        v,
+		deadline: v.now.Add(d),
+		fire:     fire,
+	}
+	if d <= 0 {
+		w.fire(v.now)
+		return w
+	}
+
+	i := sort.Search(len(v.waiters), func(i int) bool {
+		return v.waiters[i].deadline.After(w.deadline)
+	})
+	v.waiters = append(v.waiters, nil)
+	copy(v.waiters[i+1:], v.waiters[i:])
+	v.waiters[i] = w
+	return w
+}
+
+type virtualTimer struct {
+	v        *Virtual
+	deadline time.Time
+	fire     func(time.Time)
+}
+
+func (w *virtualTimer) Stop() bool {
+	w.v.m.Lock()
+	defer w.v.m.Unlock()
+
+	for i, other := range w.v.waiters {
+		if other == w {
+			w.v.waiters = append(w.v.waiters[:i], w.v.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
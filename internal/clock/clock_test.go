@@ -0,0 +1,75 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVirtualAfter(t *testing.T) {
+	assert := assert.New(t)
+	start := time.Unix(0, 0)
+	v := NewVirtual(start)
+
+	c := v.After(time.Minute)
+	assert.Equal(1, v.Pending())
+
+	v.Advance(time.Second)
+	select {
+	case <-c:
+		assert.Fail("fired before deadline")
+	default:
+	}
+
+	v.Advance(time.Hour)
+	assert.Equal(start.Add(time.Minute), <-c)
+	assert.Equal(start.Add(time.Hour+time.Second), v.Now())
+	assert.Equal(0, v.Pending())
+}
+
+func TestVirtualAfterFuncOrderAndStop(t *testing.T) {
+	assert := assert.New(t)
+	v := NewVirtual(time.Unix(0, 0))
+
+	fired := make(chan int, 3)
+	v.AfterFunc(3*time.Second, func() { fired <- 3 })
+	v.AfterFunc(1*time.Second, func() { fired <- 1 })
+	stopped := v.AfterFunc(2*time.Second, func() { fired <- 2 })
+
+	assert.True(stopped.Stop())
+	assert.False(stopped.Stop())
+	assert.Equal(2, v.Pending())
+
+	v.Advance(time.Second)
+	assert.Equal(1, <-fired)
+	v.Advance(time.Minute)
+	assert.Equal(3, <-fired)
+	assert.Len(fired, 0)
+}
+
+func TestVirtualNonPositiveDurationFiresImmediately(t *testing.T) {
+	assert := assert.New(t)
+	v := NewVirtual(time.Unix(0, 0))
+
+	select {
+	case now := <-v.After(0):
+		assert.Equal(time.Unix(0, 0), now)
+	default:
+		assert.Fail("expected After(0) to fire immediately")
+	}
+}
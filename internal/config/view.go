@@ -15,6 +15,10 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/spf13/viper"
@@ -47,3 +51,33 @@ func Sub(v View, key string) View {
 	}
 	return nil
 }
+
+// Settings returns every setting in the configuration as a string, keyed by
+// its flattened name, or nil if they can't be listed.
+func Settings(v View) map[string]string {
+	vcfg, ok := v.(*viper.Viper)
+	if !ok {
+		return nil
+	}
+	settings := make(map[string]string)
+	for _, k := range vcfg.AllKeys() {
+		settings[k] = fmt.Sprint(vcfg.Get(k))
+	}
+	return settings
+}
+
+// Digest returns a hash of all settings in the configuration, or "" if it
+// can't be computed.  Processes with the same digest have the same settings.
+func Digest(v View) string {
+	vcfg, ok := v.(*viper.Viper)
+	if !ok {
+		return ""
+	}
+	// Map keys are sorted when marshalled, so the result is stable.
+	b, err := json.Marshal(vcfg.AllSettings())
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
+}
@@ -45,3 +45,32 @@ func TestSubFromViper(t *testing.T) {
 		t.Errorf("av.GetString('c') = %s, expected ''", av.GetString(""))
 	}
 }
+
+func TestDigest(t *testing.T) {
+	a := viper.New()
+	a.Set("x.y", 1)
+	a.Set("z", "z")
+	b := viper.New()
+	b.Set("z", "z")
+	b.Set("x.y", 1)
+
+	if Digest(a) == "" || Digest(a) != Digest(b) {
+		t.Errorf("Digest(a) = %s, Digest(b) = %s, expected equal and non empty", Digest(a), Digest(b))
+	}
+
+	b.Set("x.y", 2)
+	if Digest(a) == Digest(b) {
+		t.Errorf("Digest(a) = Digest(b) = %s, expected different digests", Digest(a))
+	}
+}
+
+func TestSettings(t *testing.T) {
+	v := viper.New()
+	v.Set("x.y", 1)
+	v.Set("z", "z")
+
+	settings := Settings(v)
+	if len(settings) != 2 || settings["x.y"] != "1" || settings["z"] != "z" {
+		t.Errorf("Settings(v) = %v, expected map[x.y:1 z:z]", settings)
+	}
+}
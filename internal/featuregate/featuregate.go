@@ -0,0 +1,199 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package featuregate lets operators toggle boolean settings of running
+// components without a rollout, eg. to canary a feature on a single replica.
+package featuregate
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/statestore"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+const (
+	configNameEnabled         = "featureGates.enabled"
+	configNameRefreshInterval = "featureGates.refreshInterval"
+
+	defaultRefreshInterval = 10 * time.Second
+)
+
+var (
+	logger = logrus.WithFields(logrus.Fields{
+		"app":       "openmatch",
+		"component": "featuregate",
+	})
+)
+
+// Names of the gates.
+const (
+	VerifyTicketsBeforeEvaluation = "synchronizer.verifyTicketsBeforeEvaluation"
+	CheckProposalTickets          = "backend.proposalValidation.checkTickets"
+	ReleaseAllTickets             = "backend.releaseAllTickets.enabled"
+	AnnotateClientMetadata        = "frontend.clientMetadata.annotateTickets"
+)
+
+// Gate is a boolean setting which may be overridden at runtime.
+type Gate struct {
+	// Name is the configuration key of the setting.
+	Name        string
+	Description string
+	// Default is the value of the setting when it isn't configured.
+	Default bool
+}
+
+// Gates are the settings which may be overridden at runtime.  A setting is only
+// safe to add if components read it with Enabled whenever it's used, rather
+// than once at startup.
+var Gates = []Gate{
+	{
+		Name:        VerifyTicketsBeforeEvaluation,
+		Description: "Marks proposals with tickets which were deleted or assigned before evaluation, so that evaluators can drop them.",
+	},
+	{
+		Name:        CheckProposalTickets,
+		Description: "Drops proposals with tickets which don't exist or are already assigned before evaluation.",
+		Default:     true,
+	},
+	{
+		Name:        ReleaseAllTickets,
+		Description: "Allows ReleaseAllTickets, which returns every proposed ticket to the pool at once.",
+	},
+	{
+		Name:        AnnotateClientMetadata,
+		Description: "Records the client version and platform on created tickets.",
+	},
+}
+
+// Lookup returns the gate with the name.  Names are case insensitive, like
+// configuration keys.
+func Lookup(name string) (Gate, bool) {
+	for _, g := range Gates {
+		if strings.EqualFold(g.Name, name) {
+			return g, true
+		}
+	}
+	return Gate{}, false
+}
+
+// View is a config.View in which feature gates are overridden by the
+// overrides saved in the statestore.
+type View struct {
+	config.View
+
+	m         sync.RWMutex
+	overrides map[string]bool
+}
+
+// IsSet returns true for overridden gates, or if the key is set in the
+// underlying View.
+func (v *View) IsSet(k string) bool {
+	if _, ok := v.override(k); ok {
+		return true
+	}
+	return v.View.IsSet(k)
+}
+
+// GetBool returns the override of a gate, or else the value of the key in the
+// underlying View.
+func (v *View) GetBool(k string) bool {
+	if b, ok := v.override(k); ok {
+		return b
+	}
+	return v.View.GetBool(k)
+}
+
+func (v *View) override(k string) (bool, bool) {
+	v.m.RLock()
+	defer v.m.RUnlock()
+	b, ok := v.overrides[strings.ToLower(k)]
+	return b, ok
+}
+
+// apply replaces the overrides with those of the gates for the instance.  An
+// override for the instance takes precedence over one for all instances.
+func (v *View) apply(overrides []*pb.FeatureGateOverride, instance string) {
+	m := make(map[string]bool)
+	for _, forInstance := range []bool{false, true} {
+		for _, o := range overrides {
+			if _, ok := Lookup(o.GetGate()); !ok {
+				continue
+			}
+			if (o.GetInstance() == instance) != forInstance || (!forInstance && o.GetInstance() != "") {
+				continue
+			}
+			m[strings.ToLower(o.GetGate())] = o.GetEnabled()
+		}
+	}
+
+	v.m.Lock()
+	defer v.m.Unlock()
+	v.overrides = m
+}
+
+// Enabled returns the value of the gate: its override if cfg is a View, or
+// else its value in the configuration, or else its default.
+func Enabled(cfg config.View, name string) bool {
+	if cfg.IsSet(name) {
+		return cfg.GetBool(name)
+	}
+	g, _ := Lookup(name)
+	return g.Default
+}
+
+// Configured returns the value of the gate in the configuration, ignoring
+// overrides.
+func Configured(cfg config.View, name string) bool {
+	if v, ok := cfg.(*View); ok {
+		cfg = v.View
+	}
+	return Enabled(cfg, name)
+}
+
+// Watch returns a View of cfg applying the overrides for the instance, which
+// are refreshed from the statestore every featureGates.refreshInterval.  cfg
+// is returned unchanged unless featureGates.enabled is set.
+func Watch(cfg config.View, instance string) config.View {
+	if !cfg.GetBool(configNameEnabled) {
+		return cfg
+	}
+
+	interval := defaultRefreshInterval
+	if cfg.IsSet(configNameRefreshInterval) {
+		interval = cfg.GetDuration(configNameRefreshInterval)
+	}
+
+	v := &View{View: cfg}
+	go func() {
+		store := statestore.New(cfg)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			overrides, err := store.GetFeatureGateOverrides(context.Background())
+			if err != nil {
+				logger.WithError(err).Warning("failed to refresh feature gate overrides, keeping the previous ones")
+			} else {
+				v.apply(overrides, instance)
+			}
+			<-ticker.C
+		}
+	}()
+	return v
+}
@@ -0,0 +1,86 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package featuregate
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+const gate = "synchronizer.verifyTicketsBeforeEvaluation"
+
+func TestViewOverrides(t *testing.T) {
+	assert := assert.New(t)
+	cfg := viper.New()
+	cfg.Set(gate, true)
+	cfg.Set("other", true)
+	v := &View{View: cfg}
+
+	assert.True(v.GetBool(gate))
+	assert.False(Configured(v, "missing"))
+
+	v.apply([]*pb.FeatureGateOverride{
+		{Gate: gate, Enabled: false},
+		{Gate: "other", Enabled: false},
+	}, "host/backend")
+	assert.False(v.GetBool(gate))
+	assert.False(v.GetBool("synchronizer.VERIFYTICKETSBEFOREEVALUATION"))
+	assert.True(Configured(v, gate))
+	// Unknown gates are never overridden.
+	assert.True(v.GetBool("other"))
+
+	// Overrides for the instance take precedence, wherever they are listed.
+	v.apply([]*pb.FeatureGateOverride{
+		{Gate: gate, Instance: "host/backend", Enabled: true},
+		{Gate: gate, Enabled: false},
+		{Gate: gate, Instance: "other/backend", Enabled: false},
+	}, "host/backend")
+	assert.True(v.GetBool(gate))
+
+	v.apply([]*pb.FeatureGateOverride{
+		{Gate: gate, Instance: "other/backend", Enabled: false},
+	}, "host/backend")
+	assert.True(v.GetBool(gate))
+	assert.True(v.IsSet(gate))
+}
+
+func TestWatchDisabled(t *testing.T) {
+	cfg := viper.New()
+	if Watch(cfg, "host/backend") != cfg {
+		t.Error("Watch(cfg) should return cfg unless feature gates are enabled")
+	}
+}
+
+func TestEnabledDefaults(t *testing.T) {
+	assert := assert.New(t)
+	cfg := viper.New()
+	v := &View{View: cfg}
+
+	assert.True(Enabled(v, CheckProposalTickets))
+	assert.True(Configured(v, CheckProposalTickets))
+	assert.False(Enabled(v, ReleaseAllTickets))
+
+	// A replica may turn off a gate which is on by default.
+	v.apply([]*pb.FeatureGateOverride{{Gate: CheckProposalTickets, Instance: "host/backend", Enabled: false}}, "host/backend")
+	assert.False(Enabled(v, CheckProposalTickets))
+	assert.True(Configured(v, CheckProposalTickets))
+
+	cfg.Set(CheckProposalTickets, false)
+	v.apply(nil, "host/backend")
+	assert.False(Enabled(v, CheckProposalTickets))
+}
@@ -18,6 +18,13 @@
 package filter
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"open-match.dev/open-match/pkg/pb"
 )
 
@@ -30,35 +37,281 @@ func InPool(ticket *pb.Ticket, pool *pb.Pool) bool {
 		s = emptySearchFields
 	}
 	for _, f := range pool.GetDoubleRangeFilters() {
-		v, ok := s.DoubleArgs[f.DoubleArg]
-		if !ok {
+		if !inDoubleRange(s, f) {
 			return false
 		}
-		// Not simplified so that NaN cases are handled correctly.
-		if !(v >= f.Min && v <= f.Max) {
+	}
+
+	for _, f := range pool.GetStringEqualsFilters() {
+		if !stringEquals(s, f) {
 			return false
 		}
 	}
 
-	for _, f := range pool.GetStringEqualsFilters() {
-		v, ok := s.StringArgs[f.StringArg]
+	for _, f := range pool.GetTagPresentFilters() {
+		if !tagPresent(s, f) {
+			return false
+		}
+	}
+
+	if e := pool.GetFilterExpression(); e != nil && !matches(s, e) {
+		return false
+	}
+
+	if pool.GetCreatedBefore() != nil || pool.GetCreatedAfter() != nil {
+		ct, ok := createTime(ticket)
 		if !ok {
 			return false
 		}
-		if f.Value != v {
+		if before, ok := poolTime(pool.GetCreatedBefore()); ok && !ct.Before(before) {
 			return false
 		}
+		if after, ok := poolTime(pool.GetCreatedAfter()); ok && !ct.After(after) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func inDoubleRange(s *pb.SearchFields, f *pb.DoubleRangeFilter) bool {
+	v, ok := s.DoubleArgs[f.DoubleArg]
+	// Not simplified so that NaN cases are handled correctly.
+	return ok && v >= f.Min && v <= f.Max
+}
+
+func stringEquals(s *pb.SearchFields, f *pb.StringEqualsFilter) bool {
+	v, ok := s.StringArgs[f.StringArg]
+	return ok && v == f.Value
+}
+
+func tagPresent(s *pb.SearchFields, f *pb.TagPresentFilter) bool {
+	for _, v := range s.Tags {
+		if v == f.Tag {
+			return true
+		}
+	}
+	return false
+}
+
+// matches returns whether the search fields match every criterion set on the
+// expression.
+func matches(s *pb.SearchFields, e *pb.FilterExpression) bool {
+	for _, sub := range e.GetAll() {
+		if !matches(s, sub) {
+			return false
+		}
+	}
+
+	if len(e.GetAny()) > 0 {
+		found := false
+		for _, sub := range e.GetAny() {
+			if matches(s, sub) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if e.GetNot() != nil && matches(s, e.GetNot()) {
+		return false
+	}
+	if f := e.GetDoubleRangeFilter(); f != nil && !inDoubleRange(s, f) {
+		return false
+	}
+	if f := e.GetStringEqualsFilter(); f != nil && !stringEquals(s, f) {
+		return false
+	}
+	if f := e.GetTagPresentFilter(); f != nil && !tagPresent(s, f) {
+		return false
+	}
+	return true
+}
+
+// MaxExpressionDepth is how deeply filter expressions may nest.
+const MaxExpressionDepth = 16
+
+// ValidatePool returns an error describing the first malformed filter of the
+// pool, if any.
+func ValidatePool(pool *pb.Pool) error {
+	if expressionDepth(pool.GetFilterExpression()) > MaxExpressionDepth {
+		return fmt.Errorf("filter_expression nests deeper than %d levels", MaxExpressionDepth)
+	}
+	if t := pool.GetCreatedBefore(); t != nil {
+		if _, err := ptypes.Timestamp(t); err != nil {
+			return fmt.Errorf("invalid created_before: %v", err)
+		}
+	}
+	if t := pool.GetCreatedAfter(); t != nil {
+		if _, err := ptypes.Timestamp(t); err != nil {
+			return fmt.Errorf("invalid created_after: %v", err)
+		}
+	}
+	return nil
+}
+
+// expressionDepth returns how deeply the expression nests, 0 for nil.
+func expressionDepth(e *pb.FilterExpression) int {
+	if e == nil {
+		return 0
+	}
+	depth := expressionDepth(e.GetNot())
+	for _, subs := range [][]*pb.FilterExpression{e.GetAll(), e.GetAny()} {
+		for _, sub := range subs {
+			if d := expressionDepth(sub); d > depth {
+				depth = d
+			}
+		}
+	}
+	return depth + 1
+}
+
+// describeExpression formats the expression for Explain, eg.
+// `all(any(string_equals_filter region = "EU", ...), not(tag_present_filter x))`.
+func describeExpression(e *pb.FilterExpression) string {
+	var parts []string
+	if len(e.GetAll()) > 0 {
+		parts = append(parts, "all("+describeExpressions(e.GetAll())+")")
+	}
+	if len(e.GetAny()) > 0 {
+		parts = append(parts, "any("+describeExpressions(e.GetAny())+")")
+	}
+	if e.GetNot() != nil {
+		parts = append(parts, "not("+describeExpression(e.GetNot())+")")
+	}
+	if f := e.GetDoubleRangeFilter(); f != nil {
+		parts = append(parts, describeDoubleRange(f))
+	}
+	if f := e.GetStringEqualsFilter(); f != nil {
+		parts = append(parts, describeStringEquals(f))
+	}
+	if f := e.GetTagPresentFilter(); f != nil {
+		parts = append(parts, describeTagPresent(f))
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return "all(" + strings.Join(parts, ", ") + ")"
+}
+
+func describeExpressions(es []*pb.FilterExpression) string {
+	parts := make([]string, 0, len(es))
+	for _, e := range es {
+		parts = append(parts, describeExpression(e))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func describeDoubleRange(f *pb.DoubleRangeFilter) string {
+	return fmt.Sprintf("double_range_filter %s [%v, %v]", f.DoubleArg, f.Min, f.Max)
+}
+
+func describeStringEquals(f *pb.StringEqualsFilter) string {
+	return fmt.Sprintf("string_equals_filter %s = %q", f.StringArg, f.Value)
+}
+
+func describeTagPresent(f *pb.TagPresentFilter) string {
+	return "tag_present_filter " + f.Tag
+}
+
+// createTime returns when the ticket was created.  Tickets without a valid
+// create time are in no pool filtering on it.
+func createTime(ticket *pb.Ticket) (time.Time, bool) {
+	if ticket.GetCreateTime() == nil {
+		return time.Time{}, false
+	}
+	t, err := ptypes.Timestamp(ticket.GetCreateTime())
+	return t, err == nil
+}
+
+// poolTime returns the time of a pool's created_before or created_after
+// filter, or false if it isn't set.  Malformed times are rejected by
+// ValidatePool and ignored here.
+func poolTime(ts *timestamp.Timestamp) (time.Time, bool) {
+	if ts == nil {
+		return time.Time{}, false
+	}
+	t, err := ptypes.Timestamp(ts)
+	return t, err == nil
+}
+
+// Explain returns the result of each filter of the pool for the ticket, in the
+// order of the pool's double range, string equals, tag present, created before
+// and created after filters, then the filter expression.  The expression's
+// result has no ticket value.
+// The ticket is in the pool, as per InPool, iff every result passed.  The
+// ticket value of a tag present filter lists all of the ticket's tags.
+func Explain(ticket *pb.Ticket, pool *pb.Pool) []*pb.FilterResult {
+	s := ticket.GetSearchFields()
+	if s == nil {
+		s = emptySearchFields
+	}
+
+	var results []*pb.FilterResult
+	for _, f := range pool.GetDoubleRangeFilters() {
+		r := &pb.FilterResult{
+			Filter: describeDoubleRange(f),
+		}
+		if v, ok := s.DoubleArgs[f.DoubleArg]; ok {
+			r.TicketValue = strconv.FormatFloat(v, 'g', -1, 64)
+			r.Passed = v >= f.Min && v <= f.Max
+		}
+		results = append(results, r)
+	}
+
+	for _, f := range pool.GetStringEqualsFilters() {
+		r := &pb.FilterResult{
+			Filter: describeStringEquals(f),
+		}
+		if v, ok := s.StringArgs[f.StringArg]; ok {
+			r.TicketValue = v
+			r.Passed = v == f.Value
+		}
+		results = append(results, r)
 	}
 
-outer:
 	for _, f := range pool.GetTagPresentFilters() {
+		r := &pb.FilterResult{
+			Filter:      describeTagPresent(f),
+			TicketValue: strings.Join(s.Tags, ","),
+		}
 		for _, v := range s.Tags {
 			if v == f.Tag {
-				continue outer
+				r.Passed = true
+				break
 			}
 		}
-		return false
+		results = append(results, r)
 	}
 
-	return true
+	ct, hasCreateTime := createTime(ticket)
+	ticketValue := ""
+	if hasCreateTime {
+		ticketValue = ct.Format(time.RFC3339Nano)
+	}
+	if before, ok := poolTime(pool.GetCreatedBefore()); ok {
+		results = append(results, &pb.FilterResult{
+			Filter:      "created_before " + before.Format(time.RFC3339Nano),
+			TicketValue: ticketValue,
+			Passed:      hasCreateTime && ct.Before(before),
+		})
+	}
+	if after, ok := poolTime(pool.GetCreatedAfter()); ok {
+		results = append(results, &pb.FilterResult{
+			Filter:      "created_after " + after.Format(time.RFC3339Nano),
+			TicketValue: ticketValue,
+			Passed:      hasCreateTime && ct.After(after),
+		})
+	}
+	if e := pool.GetFilterExpression(); e != nil {
+		results = append(results, &pb.FilterResult{
+			Filter: "filter_expression " + describeExpression(e),
+			Passed: matches(s, e),
+		})
+	}
+
+	return results
 }
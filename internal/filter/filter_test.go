@@ -17,7 +17,10 @@ package filter
 import (
 	"testing"
 
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"open-match.dev/open-match/internal/filter/testcases"
+	"open-match.dev/open-match/pkg/pb"
 )
 
 func TestInPool(t *testing.T) {
@@ -39,3 +42,81 @@ func TestInPool(t *testing.T) {
 		})
 	}
 }
+
+func TestExplainAgreesWithInPool(t *testing.T) {
+	all := append(testcases.IncludedTestCases(), testcases.ExcludedTestCases()...)
+	for _, tc := range all {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			results := Explain(tc.Ticket, tc.Pool)
+			want := len(tc.Pool.GetDoubleRangeFilters()) + len(tc.Pool.GetStringEqualsFilters()) + len(tc.Pool.GetTagPresentFilters())
+			if tc.Pool.GetCreatedBefore() != nil {
+				want++
+			}
+			if tc.Pool.GetCreatedAfter() != nil {
+				want++
+			}
+			if tc.Pool.GetFilterExpression() != nil {
+				want++
+			}
+			if len(results) != want {
+				t.Fatalf("got %d results, want one per filter (%d)", len(results), want)
+			}
+
+			passed := true
+			for _, r := range results {
+				passed = passed && r.GetPassed()
+			}
+			if passed != InPool(tc.Ticket, tc.Pool) {
+				t.Errorf("Explain passed all filters = %v, which disagrees with InPool", passed)
+			}
+		})
+	}
+}
+
+func TestCreatedFiltersWithoutCreateTime(t *testing.T) {
+	pool := &pb.Pool{CreatedAfter: &timestamp.Timestamp{}}
+	if InPool(&pb.Ticket{}, pool) {
+		t.Error("ticket without a create time should be excluded from pools filtering on it")
+	}
+	if !InPool(&pb.Ticket{}, &pb.Pool{}) {
+		t.Error("ticket without a create time should be included in pools not filtering on it")
+	}
+}
+
+func TestValidatePool(t *testing.T) {
+	if err := ValidatePool(&pb.Pool{CreatedBefore: ptypes.TimestampNow()}); err != nil {
+		t.Errorf("unexpected error for a valid pool: %v", err)
+	}
+	if err := ValidatePool(&pb.Pool{CreatedAfter: &timestamp.Timestamp{Nanos: -1}}); err == nil {
+		t.Error("expected an error for a malformed created_after")
+	}
+}
+
+func TestValidatePoolExpressionDepth(t *testing.T) {
+	e := &pb.FilterExpression{}
+	for i := 1; i < MaxExpressionDepth; i++ {
+		e = &pb.FilterExpression{Any: []*pb.FilterExpression{{}, e}}
+	}
+	if err := ValidatePool(&pb.Pool{FilterExpression: e}); err != nil {
+		t.Errorf("unexpected error for an expression %d levels deep: %v", MaxExpressionDepth, err)
+	}
+	e = &pb.FilterExpression{Not: e}
+	if err := ValidatePool(&pb.Pool{FilterExpression: e}); err == nil {
+		t.Error("expected an error for an expression nesting too deeply")
+	}
+}
+
+func TestExplainFilterExpression(t *testing.T) {
+	pool := &pb.Pool{FilterExpression: &pb.FilterExpression{
+		Any: []*pb.FilterExpression{
+			{StringEqualsFilter: &pb.StringEqualsFilter{StringArg: "region", Value: "EU"}},
+			{Not: &pb.FilterExpression{TagPresentFilter: &pb.TagPresentFilter{Tag: "beta"}}},
+		},
+	}}
+	results := Explain(&pb.Ticket{}, pool)
+	want := `filter_expression any(string_equals_filter region = "EU", not(tag_present_filter beta))`
+	if len(results) != 1 || results[0].GetFilter() != want || !results[0].GetPassed() {
+		t.Errorf("got %v, want a passed result for %s", results, want)
+	}
+}
@@ -18,7 +18,10 @@ package testcases
 import (
 	"fmt"
 	"math"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"open-match.dev/open-match/pkg/pb"
 )
 
@@ -106,6 +109,18 @@ func IncludedTestCases() []TestCase {
 		},
 
 		multipleFilters(true, true, true),
+
+		createdRange("created after", 0, -time.Hour, 0),
+		createdRange("created before", 0, 0, time.Hour),
+		createdRange("created between", 0, -time.Hour, time.Hour),
+
+		{
+			"expression empty",
+			&pb.Ticket{},
+			&pb.Pool{FilterExpression: &pb.FilterExpression{}},
+		},
+		regionExpression("expression first disjunct", "EU"),
+		regionExpression("expression second disjunct", "NA", "veteran"),
 	}
 }
 
@@ -262,6 +277,18 @@ func ExcludedTestCases() []TestCase {
 		multipleFilters(false, true, true),
 		multipleFilters(true, false, true),
 		multipleFilters(true, true, false),
+
+		createdRange("created too early", 0, time.Hour, 0),
+		createdRange("created too late", 0, 0, -time.Hour),
+		createdRange("created outside of range", 0, time.Hour, 2*time.Hour),
+
+		{
+			"expression no SearchFields",
+			&pb.Ticket{},
+			regionExpression("", "").Pool,
+		},
+		regionExpression("expression no disjunct", "AS"),
+		regionExpression("expression negated", "EU", "in_penalty"),
 	}
 }
 
@@ -287,6 +314,58 @@ func simpleDoubleRange(name string, value, min, max float64) TestCase {
 	}
 }
 
+// regionExpression returns a test case for a ticket in the region with the
+// tags, and the pool of "(EU OR NA) AND NOT in_penalty" tickets.
+func regionExpression(name string, region string, tags ...string) TestCase {
+	inRegion := func(r string) *pb.FilterExpression {
+		return &pb.FilterExpression{StringEqualsFilter: &pb.StringEqualsFilter{StringArg: "region", Value: r}}
+	}
+	return TestCase{
+		name,
+		&pb.Ticket{
+			SearchFields: &pb.SearchFields{
+				StringArgs: map[string]string{"region": region},
+				Tags:       tags,
+			},
+		},
+		&pb.Pool{
+			FilterExpression: &pb.FilterExpression{
+				All: []*pb.FilterExpression{
+					{Any: []*pb.FilterExpression{inRegion("EU"), inRegion("NA")}},
+					{Not: &pb.FilterExpression{TagPresentFilter: &pb.TagPresentFilter{Tag: "in_penalty"}}},
+				},
+			},
+		},
+	}
+}
+
+// createdRange returns a test case for a ticket created at now+created, and a
+// pool of the tickets created after now+after and before now+before, where
+// they aren't 0.  Tickets created through the frontend are created at now.
+func createdRange(name string, created, after, before time.Duration) TestCase {
+	now := time.Now()
+	pool := &pb.Pool{}
+	if after != 0 {
+		pool.CreatedAfter = mustTimestampProto(now.Add(after))
+	}
+	if before != 0 {
+		pool.CreatedBefore = mustTimestampProto(now.Add(before))
+	}
+	return TestCase{
+		name,
+		&pb.Ticket{CreateTime: mustTimestampProto(now.Add(created))},
+		pool,
+	}
+}
+
+func mustTimestampProto(t time.Time) *timestamp.Timestamp {
+	ts, err := ptypes.TimestampProto(t)
+	if err != nil {
+		panic(err)
+	}
+	return ts
+}
+
 func multipleFilters(doubleRange, stringEquals, tagPresent bool) TestCase {
 	a := float64(0)
 	if !doubleRange {
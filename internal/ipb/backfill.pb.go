@@ -0,0 +1,93 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: internal/api/backfill.proto
+
+package ipb
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+	pb "open-match.dev/open-match/pkg/pb"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// BackfillInternal is how a Backfill is kept in state storage, along with the
+// Tickets already matched to it.
+type BackfillInternal struct {
+	Backfill *pb.Backfill `protobuf:"bytes,1,opt,name=backfill,proto3" json:"backfill,omitempty"`
+	// Ids of the Tickets matched to the Backfill.
+	TicketIds            []string `protobuf:"bytes,2,rep,name=ticket_ids,json=ticketIds,proto3" json:"ticket_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BackfillInternal) Reset()         { *m = BackfillInternal{} }
+func (m *BackfillInternal) String() string { return proto.CompactTextString(m) }
+func (*BackfillInternal) ProtoMessage()    {}
+func (*BackfillInternal) Descriptor() ([]byte, []int) {
+	return fileDescriptor_43f05401fc79d851, []int{0}
+}
+
+func (m *BackfillInternal) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BackfillInternal.Unmarshal(m, b)
+}
+func (m *BackfillInternal) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BackfillInternal.Marshal(b, m, deterministic)
+}
+func (m *BackfillInternal) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BackfillInternal.Merge(m, src)
+}
+func (m *BackfillInternal) XXX_Size() int {
+	return xxx_messageInfo_BackfillInternal.Size(m)
+}
+func (m *BackfillInternal) XXX_DiscardUnknown() {
+	xxx_messageInfo_BackfillInternal.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BackfillInternal proto.InternalMessageInfo
+
+func (m *BackfillInternal) GetBackfill() *pb.Backfill {
+	if m != nil {
+		return m.Backfill
+	}
+	return nil
+}
+
+func (m *BackfillInternal) GetTicketIds() []string {
+	if m != nil {
+		return m.TicketIds
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*BackfillInternal)(nil), "openmatch.internal.BackfillInternal")
+}
+
+func init() { proto.RegisterFile("internal/api/backfill.proto", fileDescriptor_43f05401fc79d851) }
+
+var fileDescriptor_43f05401fc79d851 = []byte{
+	// 163 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x92, 0xce, 0xcc, 0x2b, 0x49,
+	0x2d, 0xca, 0x4b, 0xcc, 0xd1, 0x4f, 0x2c, 0xc8, 0xd4, 0x4f, 0x4a, 0x4c, 0xce, 0x4e, 0xcb, 0xcc,
+	0xc9, 0xd1, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x12, 0xca, 0x2f, 0x48, 0xcd, 0xcb, 0x4d, 0x2c,
+	0x49, 0xce, 0xd0, 0x83, 0x29, 0x93, 0x12, 0x02, 0xa9, 0xcb, 0x4d, 0x2d, 0x2e, 0x4e, 0x4c, 0x4f,
+	0x2d, 0x86, 0xa8, 0x53, 0x4a, 0xe2, 0x12, 0x70, 0x82, 0xea, 0xf4, 0x84, 0xaa, 0x13, 0xd2, 0xe7,
+	0xe2, 0x80, 0x99, 0x26, 0xc1, 0xa8, 0xc0, 0xa8, 0xc1, 0x6d, 0x24, 0xac, 0x87, 0x30, 0x0e, 0xa6,
+	0x3c, 0x08, 0xae, 0x48, 0x48, 0x96, 0x8b, 0xab, 0x24, 0x33, 0x39, 0x3b, 0xb5, 0x24, 0x3e, 0x33,
+	0xa5, 0x58, 0x82, 0x49, 0x81, 0x59, 0x83, 0x33, 0x88, 0x13, 0x22, 0xe2, 0x99, 0x52, 0xec, 0xa4,
+	0x11, 0xa5, 0x06, 0xd2, 0xae, 0x0b, 0xd1, 0x9f, 0x92, 0x5a, 0xa6, 0x8f, 0xe0, 0xea, 0xc3, 0x3d,
+	0x91, 0x59, 0x90, 0x94, 0xc4, 0x06, 0x76, 0x94, 0x31, 0x60, 0x00, 0x5b, 0x71, 0xa1, 0x9b, 0xdb,
+	0x00, 0x00, 0x00,
+}
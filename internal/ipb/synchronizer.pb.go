@@ -73,7 +73,13 @@ type SynchronizeResponse struct {
 	CancelMmfs bool `protobuf:"varint,2,opt,name=cancel_mmfs,json=cancelMmfs,proto3" json:"cancel_mmfs,omitempty"`
 	// A match ID returned by the evaluator and should be returned to the FetchMatches
 	// caller.
-	MatchId              string   `protobuf:"bytes,4,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	MatchId string `protobuf:"bytes,4,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	// Set along with match_id if Tickets of the match were deleted after it was
+	// proposed.  The match's remaining Tickets were not added to the ignore list.
+	MatchInvalid bool `protobuf:"varint,6,opt,name=match_invalid,json=matchInvalid,proto3" json:"match_invalid,omitempty"`
+	// Unique id of the synchronizer cycle the backend call is registered with.
+	// Sent along with start_mmfs, and used to correlate all artifacts of a cycle.
+	CycleId              string   `protobuf:"bytes,5,opt,name=cycle_id,json=cycleId,proto3" json:"cycle_id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -125,6 +131,20 @@ func (m *SynchronizeResponse) GetMatchId() string {
 	return ""
 }
 
+func (m *SynchronizeResponse) GetMatchInvalid() bool {
+	if m != nil {
+		return m.MatchInvalid
+	}
+	return false
+}
+
+func (m *SynchronizeResponse) GetCycleId() string {
+	if m != nil {
+		return m.CycleId
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*SynchronizeRequest)(nil), "openmatch.internal.SynchronizeRequest")
 	proto.RegisterType((*SynchronizeResponse)(nil), "openmatch.internal.SynchronizeResponse")
@@ -133,24 +153,26 @@ func init() {
 func init() { proto.RegisterFile("internal/api/synchronizer.proto", fileDescriptor_35ff6b85fea1c4b7) }
 
 var fileDescriptor_35ff6b85fea1c4b7 = []byte{
-	// 263 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x91, 0x4f, 0x4b, 0xc3, 0x40,
-	0x10, 0xc5, 0x89, 0x16, 0x4d, 0x27, 0x1e, 0xca, 0x7a, 0xa9, 0x05, 0x69, 0xe9, 0xa1, 0xe6, 0xa0,
-	0x1b, 0xa9, 0xdf, 0xa0, 0x37, 0x85, 0x5e, 0xe2, 0xcd, 0x4b, 0xd9, 0x24, 0x53, 0xbb, 0x90, 0xfd,
-	0xe3, 0xce, 0x5a, 0xd0, 0x4f, 0x2f, 0xd9, 0xc5, 0xa6, 0xd2, 0x83, 0x97, 0x85, 0x37, 0xf3, 0xdb,
-	0x37, 0xcc, 0x1b, 0x98, 0x4a, 0xed, 0xd1, 0x69, 0xd1, 0x16, 0xc2, 0xca, 0x82, 0xbe, 0x74, 0xbd,
-	0x73, 0x46, 0xcb, 0x6f, 0x74, 0xdc, 0x3a, 0xe3, 0x0d, 0x63, 0xc6, 0xa2, 0x56, 0xc2, 0xd7, 0x3b,
-	0xfe, 0x8b, 0x4e, 0x58, 0xc7, 0x2a, 0x24, 0x12, 0xef, 0x48, 0x91, 0x9b, 0xaf, 0x80, 0xbd, 0xf6,
-	0xbf, 0x4b, 0xfc, 0xf8, 0x44, 0xf2, 0xec, 0x1e, 0x52, 0xeb, 0x8c, 0x35, 0x24, 0xda, 0x71, 0x32,
-	0x4b, 0xf2, 0x6c, 0x39, 0xe2, 0xbd, 0xe1, 0xba, 0x7b, 0xcb, 0x03, 0x31, 0xdf, 0xc3, 0xf5, 0x1f,
-	0x0f, 0xb2, 0x46, 0x13, 0xb2, 0x5b, 0x00, 0xf2, 0xc2, 0xf9, 0x8d, 0x52, 0x5b, 0x0a, 0x36, 0x69,
-	0x39, 0x0c, 0x95, 0xb5, 0xda, 0x12, 0x9b, 0x42, 0x56, 0x0b, 0x5d, 0x63, 0x1b, 0xfb, 0x67, 0xa1,
-	0x0f, 0xb1, 0x14, 0x80, 0x1b, 0x48, 0xc3, 0xbc, 0x8d, 0x6c, 0xc6, 0x83, 0x59, 0x92, 0x0f, 0xcb,
-	0xcb, 0xa0, 0x9f, 0x9b, 0x97, 0x41, 0x7a, 0x3e, 0x1a, 0x2c, 0x1d, 0x5c, 0x1d, 0xcd, 0x75, 0xac,
-	0x82, 0xec, 0x48, 0xb3, 0x05, 0x3f, 0xcd, 0x80, 0x9f, 0x2e, 0x3b, 0xb9, 0xfb, 0x97, 0x8b, 0x0b,
-	0xe5, 0xc9, 0x63, 0xb2, 0xca, 0xdf, 0x16, 0x1d, 0xfd, 0x10, 0xf1, 0x06, 0xf7, 0x45, 0x2f, 0x8b,
-	0xc3, 0x51, 0xa4, 0xad, 0xaa, 0x8b, 0x10, 0xf0, 0xd3, 0x4f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x84,
-	0xba, 0xf6, 0x41, 0xab, 0x01, 0x00, 0x00,
+	// 294 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x91, 0x51, 0x4f, 0xfa, 0x30,
+	0x14, 0xc5, 0xd3, 0xff, 0x1f, 0x71, 0x5c, 0x30, 0x21, 0xf5, 0x65, 0x92, 0x18, 0x08, 0x26, 0xb8,
+	0x07, 0xdd, 0x0c, 0x7e, 0x03, 0xde, 0x30, 0xe1, 0x65, 0xbe, 0xf9, 0x42, 0xba, 0xae, 0x48, 0x93,
+	0xad, 0xad, 0xbd, 0x95, 0x04, 0x3f, 0x98, 0x9f, 0xcf, 0xac, 0x55, 0x86, 0xe1, 0xc1, 0x97, 0x25,
+	0xe7, 0x9e, 0xdf, 0xb9, 0x6b, 0xce, 0x85, 0xb1, 0x54, 0x4e, 0x58, 0xc5, 0xaa, 0x8c, 0x19, 0x99,
+	0xe1, 0x5e, 0xf1, 0xad, 0xd5, 0x4a, 0x7e, 0x08, 0x9b, 0x1a, 0xab, 0x9d, 0xa6, 0x54, 0x1b, 0xa1,
+	0x6a, 0xe6, 0xf8, 0x36, 0xfd, 0x41, 0x47, 0xb4, 0x61, 0x6b, 0x81, 0xc8, 0x5e, 0x05, 0x06, 0x6e,
+	0xba, 0x00, 0xfa, 0xdc, 0xa6, 0x73, 0xf1, 0xf6, 0x2e, 0xd0, 0xd1, 0x3b, 0x88, 0x8c, 0xd5, 0x46,
+	0x23, 0xab, 0x62, 0x32, 0x21, 0x49, 0x7f, 0x3e, 0x4c, 0xdb, 0x85, 0xab, 0xe6, 0x9b, 0x1f, 0x88,
+	0xe9, 0x27, 0x81, 0xcb, 0x5f, 0x4b, 0xd0, 0x68, 0x85, 0x82, 0x5e, 0x03, 0xa0, 0x63, 0xd6, 0xad,
+	0xeb, 0x7a, 0x83, 0x7e, 0x4f, 0x94, 0xf7, 0xfc, 0x64, 0x55, 0x6f, 0x90, 0x8e, 0xa1, 0xcf, 0x99,
+	0xe2, 0xa2, 0x0a, 0xfe, 0x3f, 0xef, 0x43, 0x18, 0x79, 0xe0, 0x0a, 0x22, 0xff, 0xc3, 0xb5, 0x2c,
+	0xe3, 0xce, 0x84, 0x24, 0xbd, 0xfc, 0xdc, 0xeb, 0x65, 0x49, 0x6f, 0xe0, 0xe2, 0xdb, 0x52, 0x3b,
+	0x56, 0xc9, 0x32, 0xee, 0xfa, 0xf4, 0x20, 0xf8, 0x61, 0xd6, 0xe4, 0xf9, 0x9e, 0x57, 0xa2, 0xc9,
+	0x9f, 0x85, 0xbc, 0xd7, 0xcb, 0xf2, 0xa9, 0x13, 0xfd, 0x1f, 0x76, 0xe6, 0x16, 0x06, 0x47, 0xef,
+	0xb6, 0xb4, 0x80, 0xfe, 0x91, 0xa6, 0xb3, 0xf4, 0xb4, 0xc4, 0xf4, 0xb4, 0xad, 0xd1, 0xed, 0x9f,
+	0x5c, 0x28, 0x24, 0x21, 0x0f, 0x64, 0x91, 0xbc, 0xcc, 0x1a, 0xfa, 0x3e, 0xe0, 0xa5, 0xd8, 0x65,
+	0xad, 0xcc, 0x0e, 0x57, 0x95, 0xa6, 0x28, 0xba, 0xfe, 0x42, 0x8f, 0x5f, 0x03, 0x00, 0x48, 0xbd,
+	0xbf, 0x1e, 0xec, 0x01, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -0,0 +1,177 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mmfauth issues and verifies the short lived tokens the backend hands
+// to match functions, which match functions present back to the query service.
+package mmfauth
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/pkg/matchfunction"
+)
+
+const (
+	configNameEnabled    = "mmfAuth.enabled"
+	configNameSecretPath = "mmfAuth.secretPath"
+	configNameTokenTTL   = "mmfAuth.tokenTTL"
+
+	defaultTokenTTL = time.Minute
+)
+
+// Claims identify the FetchMatches call a token was issued for.
+type Claims struct {
+	// Profile is the name of the MatchProfile the match function was run with.
+	Profile string `json:"profile"`
+	// Function is the address of the match function.
+	Function string `json:"function"`
+	// Expiry is the time after which the token is no longer accepted.
+	Expiry time.Time `json:"expiry"`
+}
+
+// Authority signs and verifies match function tokens with a shared secret.
+type Authority struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// FromConfig returns the Authority described by the mmfAuth config, or nil if
+// match function authentication is disabled.
+func FromConfig(cfg config.View) (*Authority, error) {
+	if !cfg.GetBool(configNameEnabled) {
+		return nil, nil
+	}
+
+	path := cfg.GetString(configNameSecretPath)
+	if path == "" {
+		return nil, fmt.Errorf("%s must be set when %s is enabled", configNameSecretPath, configNameEnabled)
+	}
+	secret, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mmf token secret from %s: %w", path, err)
+	}
+	secret = bytes.TrimSpace(secret)
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("mmf token secret in %s is empty", path)
+	}
+
+	ttl := defaultTokenTTL
+	if cfg.IsSet(configNameTokenTTL) {
+		ttl = cfg.GetDuration(configNameTokenTTL)
+	}
+	return New(secret, ttl), nil
+}
+
+// New creates an Authority issuing tokens valid for ttl.
+func New(secret []byte, ttl time.Duration) *Authority {
+	return &Authority{
+		secret: secret,
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+// Issue returns a token for a match function run for the named profile.
+func (a *Authority) Issue(profile, function string) (string, error) {
+	payload, err := json.Marshal(&Claims{
+		Profile:  profile,
+		Function: function,
+		Expiry:   a.now().Add(a.ttl),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal mmf token claims: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(a.sign(encoded)), nil
+}
+
+// Verify checks the token's signature and expiry, returning its claims.
+func (a *Authority) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil, status.Error(codes.Unauthenticated, "malformed mmf token")
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(sig, a.sign(parts[0])) {
+		return nil, status.Error(codes.Unauthenticated, "invalid mmf token signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "malformed mmf token")
+	}
+	claims := &Claims{}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return nil, status.Error(codes.Unauthenticated, "malformed mmf token")
+	}
+
+	if a.now().After(claims.Expiry) {
+		return nil, status.Error(codes.Unauthenticated, "mmf token expired")
+	}
+	return claims, nil
+}
+
+// VerifyIncoming verifies the token carried by the incoming request context.
+func (a *Authority) VerifyIncoming(ctx context.Context) (*Claims, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "mmf token is required")
+	}
+	values := md.Get(matchfunction.TokenMetadataName)
+	if len(values) != 1 {
+		return nil, status.Error(codes.Unauthenticated, "mmf token is required")
+	}
+	return a.Verify(values[0])
+}
+
+func (a *Authority) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// AppendToOutgoingContext adds the token to the metadata of gRPC calls made with ctx.
+func AppendToOutgoingContext(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, matchfunction.TokenMetadataName, token)
+}
+
+// SetHeader copies the token from the outgoing context metadata onto an HTTP
+// request, which the grpc-gateway forwards to the gRPC handler as metadata.
+func SetHeader(ctx context.Context, req *http.Request) {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if !ok {
+		return
+	}
+	if values := md.Get(matchfunction.TokenMetadataName); len(values) == 1 {
+		req.Header.Set(runtime.MetadataHeaderPrefix+matchfunction.TokenMetadataName, values[0])
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mmfauth
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestIssueAndVerify(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Unix(1000, 0)
+	a := New([]byte("secret"), time.Minute)
+	a.now = func() time.Time { return now }
+
+	token, err := a.Issue("profile", "mmf:50502")
+	assert.Nil(err)
+
+	claims, err := a.Verify(token)
+	assert.Nil(err)
+	assert.Equal("profile", claims.Profile)
+	assert.Equal("mmf:50502", claims.Function)
+
+	// Tokens signed with a different secret are rejected.
+	other := New([]byte("other"), time.Minute)
+	_, err = other.Verify(token)
+	assert.Equal(codes.Unauthenticated, status.Convert(err).Code())
+
+	// Tampering with the claims invalidates the signature.
+	_, err = a.Verify(strings.Replace(token, token[:4], "AAAA", 1))
+	assert.Equal(codes.Unauthenticated, status.Convert(err).Code())
+
+	_, err = a.Verify("garbage")
+	assert.Equal(codes.Unauthenticated, status.Convert(err).Code())
+
+	now = now.Add(2 * time.Minute)
+	_, err = a.Verify(token)
+	assert.Equal(codes.Unauthenticated, status.Convert(err).Code())
+}
+
+func TestVerifyIncoming(t *testing.T) {
+	assert := assert.New(t)
+	a := New([]byte("secret"), time.Minute)
+
+	_, err := a.VerifyIncoming(context.Background())
+	assert.Equal(codes.Unauthenticated, status.Convert(err).Code())
+
+	token, err := a.Issue("profile", "mmf:50502")
+	assert.Nil(err)
+	md, _ := metadata.FromOutgoingContext(AppendToOutgoingContext(context.Background(), token))
+	claims, err := a.VerifyIncoming(metadata.NewIncomingContext(context.Background(), md))
+	assert.Nil(err)
+	assert.Equal("profile", claims.Profile)
+}
+
+func TestFromConfig(t *testing.T) {
+	assert := assert.New(t)
+	cfg := viper.New()
+
+	a, err := FromConfig(cfg)
+	assert.Nil(err)
+	assert.Nil(a)
+
+	cfg.Set("mmfAuth.enabled", true)
+	_, err = FromConfig(cfg)
+	assert.NotNil(err)
+
+	dir, err := ioutil.TempDir("", "mmfauth")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "secret")
+	assert.Nil(ioutil.WriteFile(path, []byte("secret\n"), 0600))
+	cfg.Set("mmfAuth.secretPath", path)
+	cfg.Set("mmfAuth.tokenTTL", "5s")
+
+	a, err = FromConfig(cfg)
+	assert.Nil(err)
+	assert.Equal([]byte("secret"), a.secret)
+	assert.Equal(5*time.Second, a.ttl)
+}
@@ -0,0 +1,184 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/config"
+)
+
+const (
+	// configNameAuthType names the registered Authenticator verifying the bearer
+	// tokens of callers of the public services.  Authentication is disabled if
+	// it isn't set.
+	configNameAuthType = "api.auth.type"
+
+	authorizationMetadataName = "authorization"
+	bearerPrefix              = "bearer "
+)
+
+// Identity is the authenticated caller of a request.
+type Identity struct {
+	// Subject identifies the caller, e.g. the "sub" claim of a JWT.
+	Subject string
+	// Issuer is the authority which vouched for the caller.
+	Issuer string
+	// Claims holds everything else the token asserted about the caller.
+	Claims map[string]interface{}
+}
+
+// Authenticator verifies the bearer tokens presented by callers.
+type Authenticator interface {
+	// Authenticate returns the identity the token was issued to.  Errors should
+	// carry codes.Unauthenticated, or codes.Unavailable if the token couldn't
+	// be checked at all.
+	Authenticate(ctx context.Context, token string) (*Identity, error)
+}
+
+var (
+	authenticatorsMu sync.Mutex
+	authenticators   = map[string]func(config.View) (Authenticator, error){
+		"jwt": newJWTAuthenticator,
+	}
+)
+
+// RegisterAuthenticator makes an Authenticator selectable by name with the
+// api.auth.type config.
+func RegisterAuthenticator(name string, newAuthenticator func(config.View) (Authenticator, error)) {
+	authenticatorsMu.Lock()
+	defer authenticatorsMu.Unlock()
+	authenticators[name] = newAuthenticator
+}
+
+// AuthenticatorFromConfig returns the Authenticator selected by the api.auth
+// config, or nil if authentication is disabled.
+func AuthenticatorFromConfig(cfg config.View) (Authenticator, error) {
+	name := cfg.GetString(configNameAuthType)
+	if name == "" {
+		return nil, nil
+	}
+
+	authenticatorsMu.Lock()
+	newAuthenticator, ok := authenticators[name]
+	authenticatorsMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown %s %q", configNameAuthType, name)
+	}
+	return newAuthenticator(cfg)
+}
+
+// RequireAuthentication makes the named gRPC services, e.g.
+// "openmatch.FrontendService", and their HTTP proxies reject requests without
+// a bearer token accepted by the Authenticator.
+func (p *ServerParams) RequireAuthentication(a Authenticator, services ...string) *ServerParams {
+	if p.authenticators == nil {
+		p.authenticators = map[string]Authenticator{}
+	}
+	for _, service := range services {
+		p.authenticators[service] = a
+	}
+	return p
+}
+
+type identityKey struct{}
+
+// IdentityFromContext returns the authenticated caller of the request, if the
+// service requires authentication.
+func IdentityFromContext(ctx context.Context) (*Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(*Identity)
+	return id, ok
+}
+
+// authenticate verifies the caller of fullMethod, returning the context to
+// handle the request with.
+func authenticate(ctx context.Context, authenticators map[string]Authenticator, fullMethod string) (context.Context, error) {
+	a, ok := authenticators[serviceName(fullMethod)]
+	if !ok {
+		return ctx, nil
+	}
+
+	token, err := bearerToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := a.Authenticate(ctx, token)
+	if err != nil {
+		if _, ok := status.FromError(err); !ok {
+			err = status.Error(codes.Unauthenticated, err.Error())
+		}
+		return nil, err
+	}
+	return context.WithValue(ctx, identityKey{}, id), nil
+}
+
+// serviceName returns the service of a "/package.Service/Method" name.
+func serviceName(fullMethod string) string {
+	s := strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(s, "/"); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// bearerToken returns the token of the authorization header.  The HTTP proxy
+// forwards the header of HTTP requests as is.
+func bearerToken(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(authorizationMetadataName)
+	if len(values) != 1 {
+		return "", status.Error(codes.Unauthenticated, "a bearer token is required")
+	}
+	if len(values[0]) <= len(bearerPrefix) || !strings.EqualFold(values[0][:len(bearerPrefix)], bearerPrefix) {
+		return "", status.Error(codes.Unauthenticated, "authorization is not a bearer token")
+	}
+	return values[0][len(bearerPrefix):], nil
+}
+
+func authUnaryServerInterceptor(authenticators map[string]Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, authenticators, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func authStreamServerInterceptor(authenticators map[string]Authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(stream.Context(), authenticators, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedServerStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+type authenticatedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedServerStream) Context() context.Context {
+	return s.ctx
+}
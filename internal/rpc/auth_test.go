@@ -0,0 +1,118 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	shellTesting "open-match.dev/open-match/internal/testing"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// staticAuthenticator accepts a single token.
+type staticAuthenticator struct {
+	token string
+}
+
+func (a *staticAuthenticator) Authenticate(_ context.Context, token string) (*Identity, error) {
+	if token != a.token {
+		return nil, fmt.Errorf("unknown token")
+	}
+	return &Identity{Subject: "director"}, nil
+}
+
+// identityFrontend records the caller of CreateTicket.
+type identityFrontend struct {
+	shellTesting.FakeFrontend
+	callers chan string
+}
+
+func (s *identityFrontend) CreateTicket(ctx context.Context, req *pb.CreateTicketRequest) (*pb.CreateTicketResponse, error) {
+	id, ok := IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "no caller identity")
+	}
+	s.callers <- id.Subject
+	return &pb.CreateTicketResponse{}, nil
+}
+
+func TestRequireAuthentication(t *testing.T) {
+	assert := assert.New(t)
+	grpcLh := MustListen()
+	httpLh := MustListen()
+	fe := &identityFrontend{callers: make(chan string, 2)}
+
+	params := NewServerParamsFromListeners(grpcLh, httpLh)
+	params.RequireAuthentication(&staticAuthenticator{token: "secret"}, "openmatch.FrontendService")
+	params.AddHandleFunc(func(s *grpc.Server) {
+		pb.RegisterFrontendServiceServer(s, fe)
+	}, pb.RegisterFrontendServiceHandlerFromEndpoint)
+	s := newInsecureServer(grpcLh, httpLh)
+	defer s.stop()
+	waitForStart, err := s.start(params)
+	assert.Nil(err)
+	waitForStart()
+
+	conn, err := grpc.Dial(fmt.Sprintf(":%d", grpcLh.Number()), grpc.WithInsecure())
+	assert.Nil(err)
+	defer conn.Close()
+	client := pb.NewFrontendServiceClient(conn)
+	ctx := utilTesting.NewContext(t)
+
+	for _, authorization := range []string{"", "secret", "Bearer wrong"} {
+		callCtx := ctx
+		if authorization != "" {
+			callCtx = metadata.AppendToOutgoingContext(ctx, "authorization", authorization)
+		}
+		_, err = client.CreateTicket(callCtx, &pb.CreateTicketRequest{})
+		assert.Equal(codes.Unauthenticated, status.Code(err), "authorization %q", authorization)
+	}
+
+	_, err = client.CreateTicket(metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer secret"), &pb.CreateTicketRequest{})
+	assert.Nil(err)
+	assert.Equal("director", <-fe.callers)
+
+	// The HTTP proxy forwards the authorization header.
+	httpClient := &http.Client{Timeout: time.Second}
+	endpoint := fmt.Sprintf("http://localhost:%d/v1/frontendservice/tickets", httpLh.Number())
+	for authorization, want := range map[string]int{"": http.StatusUnauthorized, "bearer secret": http.StatusOK} {
+		req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader("{}"))
+		assert.Nil(err)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp, err := httpClient.Do(req)
+		assert.Nil(err)
+		resp.Body.Close()
+		assert.Equal(want, resp.StatusCode, "authorization %q", authorization)
+	}
+	assert.Equal("director", <-fe.callers)
+}
+
+func TestServiceName(t *testing.T) {
+	assert.Equal(t, "openmatch.FrontendService", serviceName("/openmatch.FrontendService/CreateTicket"))
+	assert.Equal(t, "openmatch.BackendService", serviceName("/openmatch.BackendService/FetchMatches"))
+}
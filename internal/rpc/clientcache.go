@@ -17,19 +17,19 @@ package rpc
 import (
 	"net/http"
 	"sync"
+	"time"
 
 	"google.golang.org/grpc"
 	"open-match.dev/open-match/internal/config"
 )
 
-// ClientCache holds GRPC and HTTP clients based on an address.
+// ClientCache holds GRPC and HTTP clients based on an address.  GRPC
+// connections are kept in the shared connection registry, which evicts them
+// once they are unhealthy or older than the cache's max age.
 type ClientCache struct {
-	cfg   config.View
-	cache *sync.Map
-}
-
-type cachedGRPCClient struct {
-	client *grpc.ClientConn
+	cfg    config.View
+	cache  *sync.Map
+	maxAge time.Duration
 }
 
 type cachedHTTPClient struct {
@@ -37,25 +37,18 @@ type cachedHTTPClient struct {
 	baseURL string
 }
 
-// GetGRPC gets a GRPC client with the address.
-func (cc *ClientCache) GetGRPC(address string) (*grpc.ClientConn, error) {
-	val, exists := cc.cache.Load(address)
-	c, ok := val.(cachedGRPCClient)
-	if !ok || !exists {
-		conn, err := GRPCClientFromEndpoint(cc.cfg, address)
-		if err != nil {
-			return nil, err
-		}
-		c = cachedGRPCClient{
-			client: conn,
-		}
-		cc.cache.Store(address, c)
+// GetGRPC gets a GRPC client with the address.  The returned function must be
+// called once the client is no longer used, rather than closing it.
+func (cc *ClientCache) GetGRPC(address string) (*grpc.ClientConn, func(), error) {
+	params, err := clientParamsFromEndpoint(cc.cfg, address)
+	if err != nil {
+		return nil, nil, err
 	}
-
-	return c.client, nil
+	return defaultConnRegistry.acquireWithMaxAge(params, cc.maxAge)
 }
 
-// GetHTTP gets a HTTP client with the address.
+// GetHTTP gets a HTTP client with the address.  Its requests aren't timed out,
+// they must be bound by their contexts.
 func (cc *ClientCache) GetHTTP(address string) (*http.Client, string, error) {
 	val, exists := cc.cache.Load(address)
 	c, ok := val.(cachedHTTPClient)
@@ -64,16 +57,21 @@ func (cc *ClientCache) GetHTTP(address string) (*http.Client, string, error) {
 		if err != nil {
 			return nil, "", err
 		}
+		// Responses stream for as long as the call runs, bound it by the
+		// request's context rather than a fixed timeout.
+		client.Timeout = 0
 		c = cachedHTTPClient{client, baseURL}
 		cc.cache.Store(address, c)
 	}
 	return c.client, c.baseURL, nil
 }
 
-// NewClientCache creates a cache with all the clients.
-func NewClientCache(cfg config.View) *ClientCache {
+// NewClientCache creates a cache with all the clients.  GRPC connections are
+// redialed once they are older than maxAge, unless it's 0.
+func NewClientCache(cfg config.View, maxAge time.Duration) *ClientCache {
 	return &ClientCache{
-		cfg:   cfg,
-		cache: &sync.Map{},
+		cfg:    cfg,
+		cache:  &sync.Map{},
+		maxAge: maxAge,
 	}
 }
@@ -29,12 +29,14 @@ const (
 func TestGetGRPC(t *testing.T) {
 	assert := assert.New(t)
 
-	cc := NewClientCache(viper.New())
-	client, err := cc.GetGRPC(fakeGRPCAddress)
+	cc := NewClientCache(viper.New(), 0)
+	client, release1, err := cc.GetGRPC(fakeGRPCAddress)
 	assert.Nil(err)
+	defer release1()
 
-	cachedClient, err := cc.GetGRPC(fakeGRPCAddress)
+	cachedClient, release2, err := cc.GetGRPC(fakeGRPCAddress)
 	assert.Nil(err)
+	defer release2()
 
 	// Test caching by comparing pointer value
 	assert.EqualValues(client, cachedClient)
@@ -43,7 +45,7 @@ func TestGetGRPC(t *testing.T) {
 func TestGetHTTP(t *testing.T) {
 	assert := assert.New(t)
 
-	cc := NewClientCache(viper.New())
+	cc := NewClientCache(viper.New(), 0)
 	client, address, err := cc.GetHTTP(fakeHTTPAddress)
 	assert.Nil(err)
 	assert.Equal(fakeHTTPAddress, address)
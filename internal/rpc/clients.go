@@ -19,6 +19,7 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
@@ -37,7 +38,6 @@ import (
 	"go.opencensus.io/plugin/ochttp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/resolver"
 	"open-match.dev/open-match/internal/config"
 	"open-match.dev/open-match/internal/logging"
@@ -49,6 +49,10 @@ const (
 	ConfigNameEnableRPCLogging = "logging.rpc"
 	// configNameClientTrustedCertificatePath is the same as the root CA cert that the server trusts.
 	configNameClientTrustedCertificatePath = configNameServerRootCertificatePath
+	// configNameClientCertificateFile and configNameClientPrivateKeyFile are the certificate clients present for
+	// mutual TLS, the same as the server's.
+	configNameClientCertificateFile = configNameServerPublicCertificateFile
+	configNameClientPrivateKeyFile  = configNameServerPrivateKeyFile
 )
 
 var (
@@ -60,8 +64,21 @@ var (
 
 // ClientParams contains the connection parameters to connect to an Open Match service.
 type ClientParams struct {
-	Address                 string
-	TrustedCertificate      []byte
+	Address            string
+	TrustedCertificate []byte
+	// TrustedCertificateFile is the file TrustedCertificate was read from, if any.  It's read again whenever it
+	// changes, so that servers are verified against a rotated root CA.
+	TrustedCertificateFile string
+	// CertificateFile and PrivateKeyFile hold the client certificate presented to servers requiring mutual TLS.
+	// They're read again whenever they change.
+	CertificateFile string
+	PrivateKeyFile  string
+	// GRPC holds the keepalive and message size settings of gRPC connections.
+	GRPC GRPCTuning
+	// Retry retries the calls to idempotent methods.
+	Retry RetryPolicy
+	// Compression of gRPC requests, "gzip" or empty for none.
+	Compression             string
 	EnableRPCLogging        bool
 	EnableRPCPayloadLogging bool
 	EnableMetrics           bool
@@ -80,109 +97,159 @@ func (p *ClientParams) usingTLS() bool {
 	return len(p.TrustedCertificate) > 0
 }
 
-// GRPCClientFromConfig creates a gRPC client connection from a configuration.
-func GRPCClientFromConfig(cfg config.View, prefix string) (*grpc.ClientConn, error) {
-	clientParams := &ClientParams{
-		Address:                 toAddress(cfg.GetString(prefix+".hostname"), cfg.GetInt(prefix+".grpcport")),
-		EnableRPCLogging:        cfg.GetBool(ConfigNameEnableRPCLogging),
-		EnableRPCPayloadLogging: logging.IsDebugEnabled(cfg),
-		EnableMetrics:           cfg.GetBool(telemetry.ConfigNameEnableMetrics),
-	}
-
-	// If TLS support is enabled in the config, fill in the trusted certificates for decrpting server certificate.
-	if cfg.GetString(configNameClientTrustedCertificatePath) != "" {
-		_, err := os.Stat(cfg.GetString(configNameClientTrustedCertificatePath))
+// clientTLSConfig returns the TLS configuration of a client trusting the server certificate for serverName, and
+// presenting the client certificate if there's one.
+func (p *ClientParams) clientTLSConfig(serverName string) (*tls.Config, error) {
+	c := &tls.Config{ServerName: serverName}
+	if len(p.TrustedCertificateFile) == 0 {
+		pool, err := trustedCertificateFromFileData(p.TrustedCertificate)
 		if err != nil {
-			clientLogger.WithError(err).Error("trusted certificate file may not exists.")
 			return nil, err
 		}
-
-		clientParams.TrustedCertificate, err = ioutil.ReadFile(cfg.GetString(configNameClientTrustedCertificatePath))
+		c.RootCAs = pool
+	} else {
+		roots, err := newCertPoolReloader(p.TrustedCertificateFile)
 		if err != nil {
-			clientLogger.WithError(err).Error("failed to read tls trusted certificate to establish a secure grpc client.")
 			return nil, err
 		}
+		c.InsecureSkipVerify = true
+		c.VerifyPeerCertificate = verifyServerCertificate(serverName, roots.get)
+	}
+	if len(p.CertificateFile) == 0 || len(p.PrivateKeyFile) == 0 {
+		return c, nil
+	}
+
+	certificates, err := newCertificateReloader(p.CertificateFile, p.PrivateKeyFile)
+	if err != nil {
+		return nil, err
 	}
+	c.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		return certificates.get()
+	}
+	return c, nil
+}
 
+// GRPCClientFromConfig creates a gRPC client connection from a configuration.
+func GRPCClientFromConfig(cfg config.View, prefix string) (*grpc.ClientConn, error) {
+	clientParams, err := clientParamsFromConfig(cfg, prefix, "grpcport")
+	if err != nil {
+		return nil, err
+	}
 	return GRPCClientFromParams(clientParams)
 }
 
 // GRPCClientFromEndpoint creates a gRPC client connection from endpoint.
 func GRPCClientFromEndpoint(cfg config.View, address string) (*grpc.ClientConn, error) {
 	// TODO: investigate if it is possible to keep a cache of the certpool and transport credentials
-	grpcOptions := newGRPCDialOptions(cfg.GetBool(telemetry.ConfigNameEnableMetrics), cfg.GetBool(ConfigNameEnableRPCLogging), logging.IsDebugEnabled(cfg))
-
-	if cfg.GetString(configNameClientTrustedCertificatePath) != "" {
-		_, err := os.Stat(cfg.GetString(configNameClientTrustedCertificatePath))
-		if err != nil {
-			clientLogger.WithError(err).Error("trusted certificate file may not exists.")
-			return nil, err
-		}
-
-		trustedCertificate, err := ioutil.ReadFile(cfg.GetString(configNameClientTrustedCertificatePath))
-		if err != nil {
-			clientLogger.WithError(err).Error("failed to read tls trusted certificate to establish a secure grpc client.")
-			return nil, err
-		}
-
-		pool, err := trustedCertificateFromFileData(trustedCertificate)
-		if err != nil {
-			clientLogger.WithError(err).Error("failed to get transport credentials from file.")
-			return nil, errors.WithStack(err)
-		}
-		tc := credentials.NewClientTLSFromCert(pool, "")
-
-		grpcOptions = append(grpcOptions, grpc.WithTransportCredentials(tc))
-	} else {
-		grpcOptions = append(grpcOptions, grpc.WithInsecure())
+	params, err := clientParamsFromEndpoint(cfg, address)
+	if err != nil {
+		return nil, err
 	}
-
-	return grpc.Dial(address, grpcOptions...)
+	return GRPCClientFromParams(params)
 }
 
 // GRPCClientFromParams creates a gRPC client connection from the parameters.
 func GRPCClientFromParams(params *ClientParams) (*grpc.ClientConn, error) {
-	grpcOptions := newGRPCDialOptions(params.EnableMetrics, params.EnableRPCLogging, params.EnableRPCPayloadLogging)
+	grpcOptions := newGRPCDialOptions(params.GRPC, params.Retry, params.EnableMetrics, params.EnableRPCLogging, params.EnableRPCPayloadLogging)
+	grpcOptions = append(grpcOptions, compressionDialOptions(params.Compression)...)
+	target := params.Address
+	// The server name is the authority without its port, as gRPC would set it.
+	serverName := params.Address
+	if host, _, err := net.SplitHostPort(params.Address); err == nil {
+		serverName = host
+	}
+	if path, ok := unixSocketPath(params.Address); ok {
+		target = "passthrough:///" + path
+		serverName = localAuthority
+		grpcOptions = append(grpcOptions, grpc.WithContextDialer(unixDialer), grpc.WithAuthority(localAuthority))
+	}
 
 	if params.usingTLS() {
-		trustedCertPool, err := trustedCertificateFromFileData(params.TrustedCertificate)
+		tlsConfig, err := params.clientTLSConfig(serverName)
 		if err != nil {
 			clientLogger.WithError(err).Error("failed to get transport credentials from file.")
 			return nil, errors.WithStack(err)
 		}
-		grpcOptions = append(grpcOptions, grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(trustedCertPool, "")))
+		grpcOptions = append(grpcOptions, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 	} else {
 		grpcOptions = append(grpcOptions, grpc.WithInsecure())
 	}
 
-	return grpc.Dial(params.Address, grpcOptions...)
+	return grpc.Dial(target, grpcOptions...)
+}
+
+// unixSocketPath returns the path of "unix:path" and "unix:///path" addresses.
+func unixSocketPath(address string) (string, bool) {
+	if !strings.HasPrefix(address, "unix:") {
+		return "", false
+	}
+	path := strings.TrimPrefix(address, "unix:")
+	if strings.HasPrefix(path, "//") {
+		path = strings.TrimPrefix(path, "//")
+	}
+	return path, true
 }
 
 // HTTPClientFromConfig creates a HTTP client from from a configuration.
 func HTTPClientFromConfig(cfg config.View, prefix string) (*http.Client, string, error) {
-	clientParams := &ClientParams{
-		Address:                 toAddress(cfg.GetString(prefix+".hostname"), cfg.GetInt(prefix+".httpport")),
+	clientParams, err := clientParamsFromConfig(cfg, prefix, "httpport")
+	if err != nil {
+		return nil, "", err
+	}
+	return HTTPClientFromParams(clientParams)
+}
+
+// clientParamsFromConfig reads the connection parameters of the service configured under prefix.
+func clientParamsFromConfig(cfg config.View, prefix string, portName string) (*ClientParams, error) {
+	address := toAddress(cfg.GetString(prefix+".hostname"), cfg.GetInt(prefix+"."+portName))
+	if path := cfg.GetString(prefix + ".grpcsocket"); path != "" && portName == "grpcport" {
+		address = "unix://" + path
+	}
+	params, err := clientParamsFromEndpoint(cfg, address)
+	if err != nil {
+		return nil, err
+	}
+	params.Compression, err = compressionFromConfig(cfg, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// clientParamsFromEndpoint reads the connection parameters shared by all clients of the config.
+func clientParamsFromEndpoint(cfg config.View, address string) (*ClientParams, error) {
+	params := &ClientParams{
+		Address:                 address,
+		GRPC:                    grpcTuningFromConfig(cfg),
 		EnableRPCLogging:        cfg.GetBool(ConfigNameEnableRPCLogging),
 		EnableRPCPayloadLogging: logging.IsDebugEnabled(cfg),
 		EnableMetrics:           cfg.GetBool(telemetry.ConfigNameEnableMetrics),
 	}
 
+	var err error
+	params.Retry, err = retryPolicyFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	// If TLS support is enabled in the config, fill in the trusted certificates for decrpting server certificate.
 	if cfg.GetString(configNameClientTrustedCertificatePath) != "" {
 		_, err := os.Stat(cfg.GetString(configNameClientTrustedCertificatePath))
 		if err != nil {
 			clientLogger.WithError(err).Error("trusted certificate file may not exists.")
-			return nil, "", err
+			return nil, err
 		}
 
-		clientParams.TrustedCertificate, err = ioutil.ReadFile(cfg.GetString(configNameClientTrustedCertificatePath))
+		params.TrustedCertificate, err = ioutil.ReadFile(cfg.GetString(configNameClientTrustedCertificatePath))
 		if err != nil {
 			clientLogger.WithError(err).Error("failed to read tls trusted certificate to establish a secure grpc client.")
-			return nil, "", err
+			return nil, err
 		}
+		params.TrustedCertificateFile = cfg.GetString(configNameClientTrustedCertificatePath)
+		params.CertificateFile = cfg.GetString(configNameClientCertificateFile)
+		params.PrivateKeyFile = cfg.GetString(configNameClientPrivateKeyFile)
 	}
-
-	return HTTPClientFromParams(clientParams)
+	return params, nil
 }
 
 func sanitizeHTTPAddress(address string, preferHTTPS bool) (string, error) {
@@ -205,25 +272,9 @@ func sanitizeHTTPAddress(address string, preferHTTPS bool) (string, error) {
 // HTTPClientFromEndpoint creates a HTTP client from from endpoint.
 func HTTPClientFromEndpoint(cfg config.View, address string) (*http.Client, string, error) {
 	// TODO: investigate if it is possible to keep a cache of the certpool and transport credentials
-	params := &ClientParams{
-		Address:                 address,
-		EnableRPCLogging:        cfg.GetBool(ConfigNameEnableRPCLogging),
-		EnableRPCPayloadLogging: logging.IsDebugEnabled(cfg),
-		EnableMetrics:           cfg.GetBool(telemetry.ConfigNameEnableMetrics),
-	}
-	if cfg.GetString(configNameClientTrustedCertificatePath) != "" {
-		_, err := os.Stat(cfg.GetString(configNameClientTrustedCertificatePath))
-		if err != nil {
-			clientLogger.WithError(err).Error("trusted certificate file may not exists.")
-			return nil, "", err
-		}
-
-		trustedCertificate, err := ioutil.ReadFile(cfg.GetString(configNameClientTrustedCertificatePath))
-		if err != nil {
-			clientLogger.WithError(err).Error("failed to read tls trusted certificate to establish a secure grpc client.")
-			return nil, "", err
-		}
-		params.TrustedCertificate = trustedCertificate
+	params, err := clientParamsFromEndpoint(cfg, address)
+	if err != nil {
+		return nil, "", err
 	}
 	return HTTPClientFromParams(params)
 }
@@ -242,17 +293,14 @@ func HTTPClientFromParams(params *ClientParams) (*http.Client, string, error) {
 			return nil, "", err
 		}
 
-		pool, err := trustedCertificateFromFileData(params.TrustedCertificate)
+		tlsConfig, err := params.clientTLSConfig(params.Address)
 		if err != nil {
 			clientLogger.WithError(err).Error("failed to get cert pool from file.")
 			return nil, "", err
 		}
 
 		httpClient.Transport = &http.Transport{
-			TLSClientConfig: &tls.Config{
-				ServerName: params.Address,
-				RootCAs:    pool,
-			},
+			TLSClientConfig: tlsConfig,
 		}
 	} else {
 		var err error
@@ -283,13 +331,16 @@ func HTTPClientFromParams(params *ClientParams) (*http.Client, string, error) {
 	return httpClient, baseURL, nil
 }
 
-func newGRPCDialOptions(enableMetrics bool, enableRPCLogging bool, enableRPCPayloadLogging bool) []grpc.DialOption {
-	si := []grpc.StreamClientInterceptor{
-		grpc_tracing.StreamClientInterceptor(),
-	}
-	ui := []grpc.UnaryClientInterceptor{
-		grpc_tracing.UnaryClientInterceptor(),
+func newGRPCDialOptions(tuning GRPCTuning, retry RetryPolicy, enableMetrics bool, enableRPCLogging bool, enableRPCPayloadLogging bool) []grpc.DialOption {
+	si := []grpc.StreamClientInterceptor{}
+	ui := []grpc.UnaryClientInterceptor{}
+	// Retries come first, so that each attempt is traced and logged.
+	if rsi, rui := retry.interceptors(); rsi != nil {
+		si = append(si, rsi)
+		ui = append(ui, rui)
 	}
+	si = append(si, grpc_tracing.StreamClientInterceptor())
+	ui = append(ui, grpc_tracing.UnaryClientInterceptor())
 	if enableRPCLogging {
 		grpcLogger := logrus.WithFields(logrus.Fields{
 			"app":       "openmatch",
@@ -311,12 +362,8 @@ func newGRPCDialOptions(enableMetrics bool, enableRPCLogging bool, enableRPCPayl
 		grpc.WithStreamInterceptor(grpc_middleware.ChainStreamClient(si...)),
 		grpc.WithUnaryInterceptor(grpc_middleware.ChainUnaryClient(ui...)),
 		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy":"round_robin"}`),
-		grpc.WithKeepaliveParams(keepalive.ClientParameters{
-			Time:                20 * time.Second,
-			Timeout:             10 * time.Second,
-			PermitWithoutStream: true,
-		}),
 	}
+	opts = append(opts, tuning.dialOptions()...)
 	if enableMetrics {
 		opts = append(opts, grpc.WithStatsHandler(new(ocgrpc.ClientHandler)))
 	}
@@ -0,0 +1,118 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	grpcgzip "google.golang.org/grpc/encoding/gzip" // Registers the gzip compressor with gRPC servers.
+	"open-match.dev/open-match/internal/config"
+)
+
+const (
+	// compressionGzip compresses gRPC messages and HTTP responses with gzip.
+	compressionGzip = "gzip"
+)
+
+// compressionFromConfig reads the compression of the service configured under
+// prefix, e.g. api.query.compression.  Clients compress their requests, and
+// servers reply in kind to compressed requests, so both ends take the setting
+// from the service's config.
+func compressionFromConfig(cfg config.View, prefix string) (string, error) {
+	switch compression := cfg.GetString(prefix + ".compression"); compression {
+	case "", "none":
+		return "", nil
+	case compressionGzip:
+		return compression, nil
+	default:
+		return "", fmt.Errorf("unsupported %s.compression %q", prefix, compression)
+	}
+}
+
+func compressionDialOptions(compression string) []grpc.DialOption {
+	if compression != compressionGzip {
+		return nil
+	}
+	return []grpc.DialOption{grpc.WithDefaultCallOptions(grpc.UseCompressor(grpcgzip.Name))}
+}
+
+// compressHTTPHandler gzips the responses of the grpc-gateway proxy for
+// clients accepting it, flushing streamed responses message by message.
+func compressHTTPHandler(handler http.Handler, compression string) http.Handler {
+	if compression != compressionGzip {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !acceptsGzip(req) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Add("Vary", "Accept-Encoding")
+		gz := gzipWriters.Get().(*gzip.Writer)
+		gz.Reset(w)
+		defer func() {
+			gz.Close()
+			gzipWriters.Put(gz)
+		}()
+		handler.ServeHTTP(&gzipResponseWriter{ResponseWriter: w, gz: gz}, req)
+	})
+}
+
+var gzipWriters = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(nil)
+	},
+}
+
+func acceptsGzip(req *http.Request) bool {
+	for _, encoding := range strings.Split(req.Header.Get("Accept-Encoding"), ",") {
+		if strings.TrimSpace(strings.SplitN(encoding, ";", 2)[0]) == "gzip" {
+			return true
+		}
+	}
+	return false
+}
+
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gz *gzip.Writer
+}
+
+func (w *gzipResponseWriter) WriteHeader(code int) {
+	// The length of the uncompressed body no longer applies.
+	w.Header().Del("Content-Length")
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	w.Header().Del("Content-Length")
+	return w.gz.Write(b)
+}
+
+// Flush sends what's been written so far, so that streaming RPCs proxied over
+// HTTP still deliver each message as it arrives.
+func (w *gzipResponseWriter) Flush() {
+	w.gz.Flush()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	shellTesting "open-match.dev/open-match/internal/testing"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestCompressionFromConfig(t *testing.T) {
+	assert := assert.New(t)
+	cfg := viper.New()
+	compression, err := compressionFromConfig(cfg, "api.query")
+	assert.Nil(err)
+	assert.Equal("", compression)
+
+	cfg.Set("api.query.compression", "gzip")
+	compression, err = compressionFromConfig(cfg, "api.query")
+	assert.Nil(err)
+	assert.Equal("gzip", compression)
+
+	cfg.Set("api.query.hostname", "om-query")
+	cfg.Set("api.query.grpcport", 50503)
+	params, err := clientParamsFromConfig(cfg, "api.query", "grpcport")
+	assert.Nil(err)
+	assert.Equal("gzip", params.Compression)
+
+	cfg.Set("api.query.compression", "brotli")
+	_, err = compressionFromConfig(cfg, "api.query")
+	assert.NotNil(err)
+}
+
+func TestCompression(t *testing.T) {
+	assert := assert.New(t)
+	grpcLh := MustListen()
+	httpLh := MustListen()
+	params := NewServerParamsFromListeners(grpcLh, httpLh)
+	params.compression = compressionGzip
+	params.AddHandleFunc(func(s *grpc.Server) {
+		pb.RegisterFrontendServiceServer(s, &shellTesting.FakeFrontend{})
+	}, pb.RegisterFrontendServiceHandlerFromEndpoint)
+	s := newInsecureServer(grpcLh, httpLh)
+	defer s.stop()
+	waitForStart, err := s.start(params)
+	assert.Nil(err)
+	waitForStart()
+
+	conn, err := GRPCClientFromParams(&ClientParams{
+		Address:     fmt.Sprintf("localhost:%d", grpcLh.Number()),
+		Compression: compressionGzip,
+	})
+	assert.Nil(err)
+	defer conn.Close()
+	_, err = pb.NewFrontendServiceClient(conn).CreateTicket(utilTesting.NewContext(t), &pb.CreateTicketRequest{})
+	assert.Nil(err)
+
+	// Transparent decompression is disabled to look at the response as sent.
+	httpClient := &http.Client{Timeout: time.Second, Transport: &http.Transport{DisableCompression: true}}
+	endpoint := fmt.Sprintf("http://localhost:%d/v1/frontendservice/tickets", httpLh.Number())
+	for _, acceptEncoding := range []string{"", "deflate, gzip;q=0.8"} {
+		req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader("{}"))
+		assert.Nil(err)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		resp, err := httpClient.Do(req)
+		assert.Nil(err)
+		defer resp.Body.Close()
+		assert.Equal(http.StatusOK, resp.StatusCode)
+
+		body := resp.Body
+		if acceptEncoding != "" {
+			assert.Equal("gzip", resp.Header.Get("Content-Encoding"))
+			gz, err := gzip.NewReader(resp.Body)
+			assert.Nil(err)
+			body = gz
+		} else {
+			assert.Equal("", resp.Header.Get("Content-Encoding"))
+		}
+		data, err := ioutil.ReadAll(body)
+		assert.Nil(err)
+		assert.Equal("{}", string(data))
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"open-match.dev/open-match/internal/clock"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/telemetry"
+)
+
+var (
+	mClientConnections        = telemetry.Gauge("rpc/client_connections", "number of gRPC client connections held by the shared registry")
+	mClientConnectionsDialed  = telemetry.Counter("rpc/client_connections_dialed", "gRPC client connections dialed by the shared registry")
+	mClientConnectionsEvicted = telemetry.Counter("rpc/client_connections_evicted", "unhealthy, expired or idle gRPC client connections evicted from the shared registry")
+
+	defaultConnRegistry = newConnRegistry(GRPCClientFromParams)
+)
+
+const (
+	// defaultConnIdleTimeout is how long a connection no caller holds is kept
+	// for the next one.
+	defaultConnIdleTimeout = 5 * time.Minute
+	// defaultMaxConns is the number of connections the registry holds before
+	// evicting the longest idle ones, eg. when MMFs are called at many
+	// addresses.
+	defaultMaxConns = 64
+)
+
+// SharedGRPCClientFromConfig returns a gRPC client connection for the service
+// configured under prefix. Connections are shared between all callers using the
+// same address and TLS configuration. The returned function releases the
+// caller's reference and must be used instead of closing the connection.
+func SharedGRPCClientFromConfig(cfg config.View, prefix string) (*grpc.ClientConn, func(), error) {
+	params, err := clientParamsFromConfig(cfg, prefix, "grpcport")
+	if err != nil {
+		return nil, nil, err
+	}
+	return defaultConnRegistry.acquire(params)
+}
+
+// SharedGRPCClientFromEndpoint returns a shared gRPC client connection for the
+// address. See SharedGRPCClientFromConfig.
+func SharedGRPCClientFromEndpoint(cfg config.View, address string) (*grpc.ClientConn, func(), error) {
+	params, err := clientParamsFromEndpoint(cfg, address)
+	if err != nil {
+		return nil, nil, err
+	}
+	return defaultConnRegistry.acquire(params)
+}
+
+// connKey identifies connections which may be shared.  Two callers only share a
+// connection if they would have dialed it with identical options.
+type connKey struct {
+	address                string
+	trustedCertificate     string
+	trustedCertificateFile string
+	certificateFile        string
+	privateKeyFile         string
+	grpcTuning             GRPCTuning
+	compression            string
+	// retryPolicy is the printed RetryPolicy, which isn't comparable.
+	retryPolicy             string
+	enableRPCLogging        bool
+	enableRPCPayloadLogging bool
+	enableMetrics           bool
+}
+
+func newConnKey(params *ClientParams) connKey {
+	return connKey{
+		address:                 params.Address,
+		trustedCertificate:      string(params.TrustedCertificate),
+		trustedCertificateFile:  params.TrustedCertificateFile,
+		certificateFile:         params.CertificateFile,
+		privateKeyFile:          params.PrivateKeyFile,
+		grpcTuning:              params.GRPC,
+		compression:             params.Compression,
+		retryPolicy:             fmt.Sprint(params.Retry),
+		enableRPCLogging:        params.EnableRPCLogging,
+		enableRPCPayloadLogging: params.EnableRPCPayloadLogging,
+		enableMetrics:           params.EnableMetrics,
+	}
+}
+
+type sharedConn struct {
+	conn   *grpc.ClientConn
+	refs   int
+	dialed time.Time
+	// idleSince is when the last caller released the connection.
+	idleSince time.Time
+}
+
+// connRegistry keeps gRPC client connections alive across config.Cacher
+// resets, so that a burst of errors doesn't turn into a burst of new dials.
+// Connections are only redialed once they are observed to be unhealthy, or
+// once they are older than the max age asked for by the caller.  Connections
+// no caller holds are closed after idleTimeout, or earlier to keep the
+// registry within maxConns; those in use are never closed.
+type connRegistry struct {
+	dial        func(*ClientParams) (*grpc.ClientConn, error)
+	clk         clock.Clock
+	idleTimeout time.Duration
+	maxConns    int
+	m           sync.Mutex
+	conns       map[connKey]*sharedConn
+}
+
+func newConnRegistry(dial func(*ClientParams) (*grpc.ClientConn, error)) *connRegistry {
+	return &connRegistry{
+		dial:        dial,
+		clk:         clock.Real(),
+		idleTimeout: defaultConnIdleTimeout,
+		maxConns:    defaultMaxConns,
+		conns:       make(map[connKey]*sharedConn),
+	}
+}
+
+func (r *connRegistry) acquire(params *ClientParams) (*grpc.ClientConn, func(), error) {
+	return r.acquireWithMaxAge(params, 0)
+}
+
+// acquireWithMaxAge acquires a connection like acquire, redialing it if it was
+// dialed more than maxAge ago, unless maxAge is 0.  Callers still using the
+// old connection keep it until they release it.
+func (r *connRegistry) acquireWithMaxAge(params *ClientParams, maxAge time.Duration) (*grpc.ClientConn, func(), error) {
+	key := newConnKey(params)
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	sc, ok := r.conns[key]
+	if ok && !isHealthy(sc.conn) {
+		r.locklessEvict(key, sc, "unhealthy")
+		ok = false
+	}
+	if ok && maxAge > 0 && r.clk.Now().Sub(sc.dialed) > maxAge {
+		r.locklessEvict(key, sc, "expired")
+		ok = false
+	}
+
+	if !ok {
+		conn, err := r.dial(params)
+		if err != nil {
+			return nil, nil, err
+		}
+		sc = &sharedConn{conn: conn, dialed: r.clk.Now()}
+		r.conns[key] = sc
+		telemetry.RecordUnitMeasurement(context.Background(), mClientConnectionsDialed)
+		r.locklessRecordCount()
+	}
+
+	sc.refs++
+	r.locklessEvictOverflow()
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			r.release(key, sc)
+		})
+	}
+	return sc.conn, release, nil
+}
+
+func (r *connRegistry) release(key connKey, sc *sharedConn) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	sc.refs--
+	if r.conns[key] != sc {
+		// Already evicted, close once the last user is gone.
+		if sc.refs <= 0 {
+			closeConn(sc.conn)
+		}
+		return
+	}
+	if sc.refs > 0 {
+		return
+	}
+	if !isHealthy(sc.conn) {
+		r.locklessEvict(key, sc, "unhealthy")
+		return
+	}
+
+	sc.idleSince = r.clk.Now()
+	r.clk.AfterFunc(r.idleTimeout, func() {
+		r.evictIdle(key, sc)
+	})
+}
+
+// evictIdle evicts the connection if it's still registered and no caller
+// acquired it within the idle timeout.
+func (r *connRegistry) evictIdle(key connKey, sc *sharedConn) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if r.conns[key] == sc && sc.refs <= 0 && r.clk.Now().Sub(sc.idleSince) >= r.idleTimeout {
+		r.locklessEvict(key, sc, "idle")
+	}
+}
+
+// locklessEvictOverflow evicts the longest idle connections while there are
+// more than maxConns.
+func (r *connRegistry) locklessEvictOverflow() {
+	for len(r.conns) > r.maxConns {
+		var oldestKey connKey
+		var oldest *sharedConn
+		for key, sc := range r.conns {
+			if sc.refs <= 0 && (oldest == nil || sc.idleSince.Before(oldest.idleSince)) {
+				oldestKey, oldest = key, sc
+			}
+		}
+		if oldest == nil {
+			return
+		}
+		r.locklessEvict(oldestKey, oldest, "overflow")
+	}
+}
+
+func (r *connRegistry) locklessEvict(key connKey, sc *sharedConn, reason string) {
+	delete(r.conns, key)
+	if sc.refs <= 0 {
+		closeConn(sc.conn)
+	}
+
+	clientLogger.WithFields(logrus.Fields{
+		"address": key.address,
+		"state":   sc.conn.GetState().String(),
+		"reason":  reason,
+	}).Info("Evicted gRPC client connection.")
+	telemetry.RecordUnitMeasurement(context.Background(), mClientConnectionsEvicted)
+	r.locklessRecordCount()
+}
+
+func (r *connRegistry) locklessRecordCount() {
+	telemetry.SetGauge(context.Background(), mClientConnections, int64(len(r.conns)))
+}
+
+func isHealthy(conn *grpc.ClientConn) bool {
+	switch conn.GetState() {
+	case connectivity.TransientFailure, connectivity.Shutdown:
+		return false
+	default:
+		return true
+	}
+}
+
+func closeConn(conn *grpc.ClientConn) {
+	if err := conn.Close(); err != nil {
+		clientLogger.WithError(err).Debug("error closing evicted gRPC client connection")
+	}
+}
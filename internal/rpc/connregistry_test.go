@@ -0,0 +1,194 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"open-match.dev/open-match/internal/clock"
+)
+
+func newCountingConnRegistry(dials *int) *connRegistry {
+	return newConnRegistry(func(params *ClientParams) (*grpc.ClientConn, error) {
+		*dials++
+		return grpc.Dial(params.Address, grpc.WithInsecure())
+	})
+}
+
+func TestConnRegistrySharesConnections(t *testing.T) {
+	assert := assert.New(t)
+	dials := 0
+	r := newCountingConnRegistry(&dials)
+
+	conn1, release1, err := r.acquire(&ClientParams{Address: fakeGRPCAddress})
+	assert.Nil(err)
+	conn2, release2, err := r.acquire(&ClientParams{Address: fakeGRPCAddress})
+	assert.Nil(err)
+	assert.Equal(conn1, conn2)
+	assert.Equal(1, dials)
+
+	// Releasing a healthy connection keeps it around for the next caller.
+	release1()
+	release2()
+	conn3, release3, err := r.acquire(&ClientParams{Address: fakeGRPCAddress})
+	assert.Nil(err)
+	assert.Equal(conn1, conn3)
+	assert.Equal(1, dials)
+	release3()
+}
+
+func TestConnRegistryKeysOnTLSConfig(t *testing.T) {
+	assert := assert.New(t)
+	dials := 0
+	r := newCountingConnRegistry(&dials)
+
+	insecureConn, release1, err := r.acquire(&ClientParams{Address: fakeGRPCAddress})
+	assert.Nil(err)
+	defer release1()
+	secureConn, release2, err := r.acquire(&ClientParams{Address: fakeGRPCAddress, TrustedCertificate: []byte("cert")})
+	assert.Nil(err)
+	defer release2()
+
+	assert.NotEqual(insecureConn, secureConn)
+	assert.Equal(2, dials)
+}
+
+func TestConnRegistryEvictsUnhealthyConnections(t *testing.T) {
+	assert := assert.New(t)
+	dials := 0
+	r := newCountingConnRegistry(&dials)
+
+	conn1, release1, err := r.acquire(&ClientParams{Address: fakeGRPCAddress})
+	assert.Nil(err)
+	assert.Nil(conn1.Close())
+	assert.Equal(connectivity.Shutdown, conn1.GetState())
+
+	conn2, release2, err := r.acquire(&ClientParams{Address: fakeGRPCAddress})
+	assert.Nil(err)
+	assert.NotEqual(conn1, conn2)
+	assert.Equal(2, dials)
+
+	// Releasing the evicted connection must not affect its replacement.
+	release1()
+	release1()
+	assert.NotEqual(connectivity.Shutdown, conn2.GetState())
+	release2()
+	assert.Len(r.conns, 1)
+}
+
+func TestConnRegistryRedialsExpiredConnections(t *testing.T) {
+	assert := assert.New(t)
+	dials := 0
+	r := newCountingConnRegistry(&dials)
+	clk := clock.NewVirtual(time.Unix(0, 0))
+	r.clk = clk
+
+	conn1, release1, err := r.acquireWithMaxAge(&ClientParams{Address: fakeGRPCAddress}, time.Minute)
+	assert.Nil(err)
+	clk.Advance(time.Minute)
+	conn2, release2, err := r.acquireWithMaxAge(&ClientParams{Address: fakeGRPCAddress}, time.Minute)
+	assert.Nil(err)
+	assert.Equal(conn1, conn2)
+	assert.Equal(1, dials)
+
+	// An expired connection is redialed, and closed once its last user is gone.
+	clk.Advance(time.Second)
+	conn3, release3, err := r.acquireWithMaxAge(&ClientParams{Address: fakeGRPCAddress}, time.Minute)
+	assert.Nil(err)
+	assert.NotEqual(conn1, conn3)
+	assert.Equal(2, dials)
+	release1()
+	assert.NotEqual(connectivity.Shutdown, conn1.GetState())
+	release2()
+	assert.Equal(connectivity.Shutdown, conn1.GetState())
+
+	// Without a max age, the connection is kept.
+	clk.Advance(time.Hour)
+	conn4, release4, err := r.acquire(&ClientParams{Address: fakeGRPCAddress})
+	assert.Nil(err)
+	assert.Equal(conn3, conn4)
+	release3()
+	release4()
+}
+
+func TestConnRegistryEvictsIdleConnections(t *testing.T) {
+	assert := assert.New(t)
+	dials := 0
+	r := newCountingConnRegistry(&dials)
+	clk := clock.NewVirtual(time.Unix(0, 0))
+	r.clk = clk
+
+	conn1, release1, err := r.acquire(&ClientParams{Address: fakeGRPCAddress})
+	assert.Nil(err)
+	release1()
+
+	// Reacquiring the connection restarts its idle timeout.
+	clk.Advance(r.idleTimeout / 2)
+	conn2, release2, err := r.acquire(&ClientParams{Address: fakeGRPCAddress})
+	assert.Nil(err)
+	assert.Equal(conn1, conn2)
+	release2()
+	clk.Advance(r.idleTimeout / 2)
+	assert.NotEqual(connectivity.Shutdown, conn1.GetState())
+
+	clk.Advance(r.idleTimeout / 2)
+	// The eviction runs in the timer's goroutine.
+	for deadline := time.Now().Add(time.Second); conn1.GetState() != connectivity.Shutdown && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(connectivity.Shutdown, conn1.GetState())
+	r.m.Lock()
+	assert.Empty(r.conns)
+	r.m.Unlock()
+}
+
+func TestConnRegistryEvictsLongestIdleOverMaxConns(t *testing.T) {
+	assert := assert.New(t)
+	dials := 0
+	r := newCountingConnRegistry(&dials)
+	clk := clock.NewVirtual(time.Unix(0, 0))
+	r.clk = clk
+	r.maxConns = 2
+
+	idle1, release1, err := r.acquire(&ClientParams{Address: fakeGRPCAddress})
+	assert.Nil(err)
+	release1()
+	clk.Advance(time.Second)
+	idle2, release2, err := r.acquire(&ClientParams{Address: fakeGRPCAddress, TrustedCertificate: []byte("cert")})
+	assert.Nil(err)
+	release2()
+	inUse, release3, err := r.acquire(&ClientParams{Address: fakeGRPCAddress, CertificateFile: "cert"})
+	assert.Nil(err)
+	defer release3()
+
+	assert.Len(r.conns, 2)
+	assert.Equal(connectivity.Shutdown, idle1.GetState())
+	assert.NotEqual(connectivity.Shutdown, idle2.GetState())
+	assert.NotEqual(connectivity.Shutdown, inUse.GetState())
+
+	// Connections in use are kept over the limit.
+	_, release4, err := r.acquire(&ClientParams{Address: fakeGRPCAddress, PrivateKeyFile: "key"})
+	assert.Nil(err)
+	defer release4()
+	_, release5, err := r.acquire(&ClientParams{Address: fakeGRPCAddress, Compression: "gzip"})
+	assert.Nil(err)
+	defer release5()
+	assert.Len(r.conns, 3)
+	assert.Equal(connectivity.Shutdown, idle2.GetState())
+}
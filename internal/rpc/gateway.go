@@ -0,0 +1,128 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"open-match.dev/open-match/internal/config"
+)
+
+const (
+	configNameCORSAllowedOrigins = "api.http.cors.allowedOrigins"
+	configNameCORSAllowedMethods = "api.http.cors.allowedMethods"
+	configNameCORSAllowedHeaders = "api.http.cors.allowedHeaders"
+	configNameCORSMaxAge         = "api.http.cors.maxAge"
+	// configNameForwardedHeaders lists the HTTP headers the proxy passes on to
+	// the gRPC services as metadata, besides Authorization and Grpc-Metadata-*
+	// headers, which the proxy always forwards.
+	configNameForwardedHeaders = "api.http.forwardedHeaders"
+)
+
+var (
+	defaultCORSAllowedMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	defaultCORSAllowedHeaders = []string{"Authorization", "Content-Type"}
+)
+
+// corsPolicy lets browsers on other origins, e.g. a hosted Swagger UI, call
+// the HTTP proxy.
+type corsPolicy struct {
+	// allowedOrigins holds the origins allowed, or "*" for any origin.
+	allowedOrigins map[string]bool
+	allowedMethods string
+	allowedHeaders string
+	maxAge         time.Duration
+}
+
+// corsPolicyFromConfig reads the api.http.cors config, returning nil if no
+// origin is allowed.
+func corsPolicyFromConfig(cfg config.View) *corsPolicy {
+	origins := cfg.GetStringSlice(configNameCORSAllowedOrigins)
+	if len(origins) == 0 {
+		return nil
+	}
+	p := &corsPolicy{
+		allowedOrigins: map[string]bool{},
+		allowedMethods: strings.Join(defaultCORSAllowedMethods, ", "),
+		allowedHeaders: strings.Join(defaultCORSAllowedHeaders, ", "),
+		maxAge:         cfg.GetDuration(configNameCORSMaxAge),
+	}
+	for _, origin := range origins {
+		p.allowedOrigins[strings.TrimSuffix(origin, "/")] = true
+	}
+	if methods := cfg.GetStringSlice(configNameCORSAllowedMethods); len(methods) > 0 {
+		p.allowedMethods = strings.Join(methods, ", ")
+	}
+	if headers := cfg.GetStringSlice(configNameCORSAllowedHeaders); len(headers) > 0 {
+		p.allowedHeaders = strings.Join(headers, ", ")
+	}
+	return p
+}
+
+func (p *corsPolicy) allows(origin string) bool {
+	return p.allowedOrigins["*"] || p.allowedOrigins[origin]
+}
+
+// corsHTTPHandler adds the CORS headers to responses to allowed origins, and
+// answers their preflight requests itself.
+func corsHTTPHandler(handler http.Handler, p *corsPolicy) http.Handler {
+	if p == nil {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		origin := req.Header.Get("Origin")
+		if origin == "" || !p.allows(origin) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		h := w.Header()
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Add("Vary", "Origin")
+		if req.Method != http.MethodOptions || req.Header.Get("Access-Control-Request-Method") == "" {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		h.Set("Access-Control-Allow-Methods", p.allowedMethods)
+		h.Set("Access-Control-Allow-Headers", p.allowedHeaders)
+		if p.maxAge > 0 {
+			h.Set("Access-Control-Max-Age", strconv.Itoa(int(p.maxAge.Seconds())))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// newProxyMux returns the mux of the grpc-gateway proxy, forwarding the
+// configured headers as gRPC metadata.
+func newProxyMux(forwardedHeaders []string) *runtime.ServeMux {
+	if len(forwardedHeaders) == 0 {
+		return runtime.NewServeMux()
+	}
+	forwarded := map[string]bool{}
+	for _, header := range forwardedHeaders {
+		forwarded[http.CanonicalHeaderKey(header)] = true
+	}
+	return runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(func(key string) (string, bool) {
+		if forwarded[http.CanonicalHeaderKey(key)] {
+			return strings.ToLower(key), true
+		}
+		return runtime.DefaultHeaderMatcher(key)
+	}))
+}
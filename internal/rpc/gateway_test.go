@@ -0,0 +1,117 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	shellTesting "open-match.dev/open-match/internal/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// metadataFrontend records the metadata of CreateTicket calls.
+type metadataFrontend struct {
+	shellTesting.FakeFrontend
+	md chan metadata.MD
+}
+
+func (s *metadataFrontend) CreateTicket(ctx context.Context, req *pb.CreateTicketRequest) (*pb.CreateTicketResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.md <- md
+	return &pb.CreateTicketResponse{}, nil
+}
+
+func TestGatewayCORSAndForwardedHeaders(t *testing.T) {
+	assert := assert.New(t)
+	cfg := viper.New()
+	cfg.Set("api.http.cors.allowedOrigins", []string{"https://swagger.example.com/"})
+	cfg.Set("api.http.cors.allowedHeaders", []string{"Content-Type", "X-Tenant"})
+	cfg.Set("api.http.cors.maxAge", "10m")
+	cfg.Set("api.http.forwardedHeaders", []string{"x-tenant"})
+
+	grpcLh := MustListen()
+	httpLh := MustListen()
+	fe := &metadataFrontend{md: make(chan metadata.MD, 1)}
+	params := NewServerParamsFromListeners(grpcLh, httpLh)
+	params.cors = corsPolicyFromConfig(cfg)
+	params.forwardedHeaders = cfg.GetStringSlice("api.http.forwardedHeaders")
+	params.AddHandleFunc(func(s *grpc.Server) {
+		pb.RegisterFrontendServiceServer(s, fe)
+	}, pb.RegisterFrontendServiceHandlerFromEndpoint)
+	s := newInsecureServer(grpcLh, httpLh)
+	defer s.stop()
+	waitForStart, err := s.start(params)
+	require.Nil(t, err)
+	waitForStart()
+
+	httpClient := &http.Client{Timeout: time.Second}
+	endpoint := fmt.Sprintf("http://localhost:%d/v1/frontendservice/tickets", httpLh.Number())
+	do := func(method, origin string, headers map[string]string) *http.Response {
+		req, err := http.NewRequest(method, endpoint, strings.NewReader("{}"))
+		require.Nil(t, err)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := httpClient.Do(req)
+		require.Nil(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	// Preflight requests are answered by the proxy.
+	resp := do(http.MethodOptions, "https://swagger.example.com", map[string]string{"Access-Control-Request-Method": "POST"})
+	assert.Equal(http.StatusNoContent, resp.StatusCode)
+	assert.Equal("https://swagger.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Equal("GET, POST, PUT, PATCH, DELETE", resp.Header.Get("Access-Control-Allow-Methods"))
+	assert.Equal("Content-Type, X-Tenant", resp.Header.Get("Access-Control-Allow-Headers"))
+	assert.Equal("600", resp.Header.Get("Access-Control-Max-Age"))
+
+	resp = do(http.MethodPost, "https://swagger.example.com", map[string]string{"X-Tenant": "blue", "X-Other": "dropped"})
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal("https://swagger.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+	md := <-fe.md
+	assert.Equal([]string{"blue"}, md.Get("x-tenant"))
+	assert.Empty(md.Get("x-other"))
+
+	// Other origins get no CORS headers, so browsers block them.
+	resp = do(http.MethodPost, "https://evil.example.com", nil)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal("", resp.Header.Get("Access-Control-Allow-Origin"))
+	<-fe.md
+}
+
+func TestCORSPolicyFromConfig(t *testing.T) {
+	assert := assert.New(t)
+	cfg := viper.New()
+	assert.Nil(corsPolicyFromConfig(cfg))
+
+	cfg.Set("api.http.cors.allowedOrigins", []string{"*"})
+	p := corsPolicyFromConfig(cfg)
+	assert.True(p.allows("https://anywhere.example.com"))
+	assert.Equal("Authorization, Content-Type", p.allowedHeaders)
+}
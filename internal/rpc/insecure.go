@@ -43,7 +43,7 @@ func (s *insecureServer) start(params *ServerParams) (func(), error) {
 	var serverStartWaiter sync.WaitGroup
 
 	s.httpMux = params.ServeMux
-	s.proxyMux = runtime.NewServeMux()
+	s.proxyMux = newProxyMux(params.forwardedHeaders)
 
 	// Configure the gRPC server.
 	grpcListener, err := s.grpcLh.Obtain()
@@ -54,9 +54,7 @@ func (s *insecureServer) start(params *ServerParams) (func(), error) {
 
 	s.grpcServer = grpc.NewServer(newGRPCServerOptions(params)...)
 	// Bind gRPC handlers
-	for _, handlerFunc := range params.handlersForGrpc {
-		handlerFunc(s.grpcServer)
-	}
+	bindGRPCHandlers(s.grpcServer, params)
 
 	serverStartWaiter.Add(1)
 	go func() {
@@ -79,16 +77,18 @@ func (s *insecureServer) start(params *ServerParams) (func(), error) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	for _, handlerFunc := range params.handlersForGrpcProxy {
-		dialOpts := newGRPCDialOptions(params.enableMetrics, params.enableRPCLogging, params.enableRPCPayloadLogging)
+		dialOpts := newGRPCDialOptions(params.grpcTuning, RetryPolicy{}, params.enableMetrics, params.enableRPCLogging, params.enableRPCPayloadLogging)
 		dialOpts = append(dialOpts, grpc.WithInsecure())
-		if err = handlerFunc(ctx, s.proxyMux, grpcListener.Addr().String(), dialOpts); err != nil {
+		dialOpts = append(dialOpts, s.grpcLh.DialOptions()...)
+		dialOpts = append(dialOpts, params.proxyDialOptions...)
+		if err = handlerFunc(ctx, s.proxyMux, s.grpcLh.Target(), dialOpts); err != nil {
 			cancel()
 			return func() {}, errors.WithStack(err)
 		}
 	}
 
 	s.httpMux.Handle(telemetry.HealthCheckEndpoint, telemetry.NewHealthCheck(params.handlersForHealthCheck))
-	s.httpMux.Handle("/", s.proxyMux)
+	s.httpMux.Handle("/", corsHTTPHandler(compressHTTPHandler(s.proxyMux, params.compression), params.cors))
 	s.httpServer = &http.Server{
 		Addr:    s.httpListener.Addr().String(),
 		Handler: instrumentHTTPHandler(s.httpMux, params),
@@ -0,0 +1,388 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256" // Registers the JWT hashes.
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/config"
+)
+
+const (
+	configNameJWTIssuer    = "api.auth.jwt.issuer"
+	configNameJWTAudience  = "api.auth.jwt.audience"
+	configNameJWTJWKSURL   = "api.auth.jwt.jwksUrl"
+	configNameJWTClockSkew = "api.auth.jwt.clockSkew"
+
+	defaultJWTClockSkew = time.Minute
+	// jwksMinRefreshInterval limits how often tokens signed with unknown keys
+	// make the authenticator fetch the issuer's keys again.
+	jwksMinRefreshInterval = time.Minute
+)
+
+// jwtAuthenticator verifies JWTs signed by an OIDC issuer, with the keys
+// published at its JWKS endpoint.
+type jwtAuthenticator struct {
+	issuer    string
+	audience  string
+	clockSkew time.Duration
+	keys      *jwksCache
+	now       func() time.Time
+}
+
+func newJWTAuthenticator(cfg config.View) (Authenticator, error) {
+	issuer := cfg.GetString(configNameJWTIssuer)
+	audience := cfg.GetString(configNameJWTAudience)
+	if issuer == "" || audience == "" {
+		return nil, fmt.Errorf("%s and %s must be set to authenticate with JWTs", configNameJWTIssuer, configNameJWTAudience)
+	}
+	clockSkew := defaultJWTClockSkew
+	if cfg.IsSet(configNameJWTClockSkew) {
+		clockSkew = cfg.GetDuration(configNameJWTClockSkew)
+	}
+
+	return &jwtAuthenticator{
+		issuer:    issuer,
+		audience:  audience,
+		clockSkew: clockSkew,
+		keys: &jwksCache{
+			issuer: issuer,
+			url:    cfg.GetString(configNameJWTJWKSURL),
+			client: &http.Client{Timeout: 10 * time.Second},
+			now:    time.Now,
+		},
+		now: time.Now,
+	}, nil
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Authenticate implements Authenticator.
+func (a *jwtAuthenticator) Authenticate(ctx context.Context, token string) (*Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, status.Error(codes.Unauthenticated, "malformed JWT")
+	}
+	header := &jwtHeader{}
+	if err := decodeJWTSegment(parts[0], header); err != nil {
+		return nil, err
+	}
+	claims := map[string]interface{}{}
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "malformed JWT signature")
+	}
+
+	key, err := a.keys.get(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyJWTSignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	if err := a.verifyClaims(claims); err != nil {
+		return nil, err
+	}
+	sub, _ := claims["sub"].(string)
+	return &Identity{
+		Subject: sub,
+		Issuer:  a.issuer,
+		Claims:  claims,
+	}, nil
+}
+
+func (a *jwtAuthenticator) verifyClaims(claims map[string]interface{}) error {
+	if iss, _ := claims["iss"].(string); iss != a.issuer {
+		return status.Errorf(codes.Unauthenticated, "JWT issued by %q, want %q", iss, a.issuer)
+	}
+	if !jwtHasAudience(claims["aud"], a.audience) {
+		return status.Errorf(codes.Unauthenticated, "JWT is not issued for %q", a.audience)
+	}
+
+	now := a.now()
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "JWT has no expiry")
+	}
+	if now.Add(-a.clockSkew).After(time.Unix(int64(exp), 0)) {
+		return status.Error(codes.Unauthenticated, "JWT expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(a.clockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return status.Error(codes.Unauthenticated, "JWT is not valid yet")
+	}
+	return nil
+}
+
+// jwtHasAudience returns true if the aud claim, a string or a list of them,
+// names audience.
+func jwtHasAudience(aud interface{}, audience string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, a := range aud {
+			if a == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func decodeJWTSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, "malformed JWT")
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return status.Error(codes.Unauthenticated, "malformed JWT")
+	}
+	return nil
+}
+
+// verifyJWTSignature checks an RSA or ECDSA signature.  Symmetric and "none"
+// algorithms are refused, as the keys are public.
+func verifyJWTSignature(alg string, key crypto.PublicKey, signed string, signature []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "ES512":
+		hash = crypto.SHA512
+	default:
+		return status.Errorf(codes.Unauthenticated, "unsupported JWT algorithm %q", alg)
+	}
+	h := hash.New()
+	h.Write([]byte(signed))
+	digest := h.Sum(nil)
+
+	valid := false
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		valid = strings.HasPrefix(alg, "RS") && rsa.VerifyPKCS1v15(key, hash, digest, signature) == nil
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if strings.HasPrefix(alg, "ES") && len(signature) == 2*size {
+			r := new(big.Int).SetBytes(signature[:size])
+			s := new(big.Int).SetBytes(signature[size:])
+			valid = ecdsa.Verify(key, digest, r, s)
+		}
+	}
+	if !valid {
+		return status.Error(codes.Unauthenticated, "invalid JWT signature")
+	}
+	return nil
+}
+
+// jwksCache holds the signing keys of an issuer, fetching them again when a
+// token is signed with a key it doesn't know, e.g. after the keys rotated.
+type jwksCache struct {
+	issuer string
+	// url of the JWKS, discovered from the issuer's OIDC configuration if empty.
+	url    string
+	client *http.Client
+	now    func() time.Time
+
+	mu   sync.Mutex
+	keys map[string]crypto.PublicKey
+	// lastRefresh is when the keys were last fetched, successfully or not, and
+	// lastErr why that failed.
+	lastRefresh time.Time
+	lastErr     error
+	// refreshing is closed once the fetch in flight, if any, completes.
+	refreshing chan struct{}
+}
+
+func (c *jwksCache) get(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	for {
+		c.mu.Lock()
+		if key, ok := c.keys[kid]; ok {
+			c.mu.Unlock()
+			return key, nil
+		}
+		refreshing := c.refreshing
+		if refreshing == nil {
+			if !c.lastRefresh.IsZero() && c.now().Sub(c.lastRefresh) < jwksMinRefreshInterval {
+				err := c.lastErr
+				c.mu.Unlock()
+				if err != nil {
+					return nil, status.Errorf(codes.Unavailable, "failed to fetch the JWT signing keys of %s: %s", c.issuer, err)
+				}
+				return nil, status.Errorf(codes.Unauthenticated, "JWT signed with unknown key %q", kid)
+			}
+			c.refreshing = make(chan struct{})
+			c.mu.Unlock()
+			c.refresh()
+			continue
+		}
+		c.mu.Unlock()
+
+		// Only one caller fetches the keys, the others wait for it.
+		select {
+		case <-refreshing:
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+// refresh fetches the keys, without holding the lock so that tokens signed
+// with known keys are verified meanwhile.  The fetch isn't bound to the
+// context of the caller which happened to start it, as others wait for it too.
+func (c *jwksCache) refresh() {
+	keys, err := c.fetch(context.Background())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		c.keys = keys
+	}
+	c.lastRefresh = c.now()
+	c.lastErr = err
+	close(c.refreshing)
+	c.refreshing = nil
+}
+
+func (c *jwksCache) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	if c.url == "" {
+		discovery := &struct {
+			JWKSURI string `json:"jwks_uri"`
+		}{}
+		if err := c.getJSON(ctx, strings.TrimSuffix(c.issuer, "/")+"/.well-known/openid-configuration", discovery); err != nil {
+			return nil, err
+		}
+		if discovery.JWKSURI == "" {
+			return nil, fmt.Errorf("the OIDC configuration has no jwks_uri")
+		}
+		c.url = discovery.JWKSURI
+	}
+
+	jwks := &struct {
+		Keys []jwk `json:"keys"`
+	}{}
+	if err := c.getJSON(ctx, c.url, jwks); err != nil {
+		return nil, err
+	}
+	keys := map[string]crypto.PublicKey{}
+	for _, k := range jwks.Keys {
+		// Keys of unsupported types are skipped; tokens signed with them are rejected.
+		if key, err := k.publicKey(); err == nil {
+			keys[k.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+func (c *jwksCache) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// jwk is a JSON Web Key, as published in a JWKS.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	// RSA keys.
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC keys.
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k *jwk) publicKey() (crypto.PublicKey, error) {
+	if k.Use != "" && k.Use != "sig" {
+		return nil, fmt.Errorf("key %q is not a signing key", k.Kid)
+	}
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, fmt.Errorf("key %q is not on its curve", k.Kid)
+		}
+		return key, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
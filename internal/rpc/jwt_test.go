@@ -0,0 +1,301 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testIssuer is an OIDC issuer serving its configuration and keys over HTTP.
+type testIssuer struct {
+	server     *httptest.Server
+	rsaKey     *rsa.PrivateKey
+	ecKey      *ecdsa.PrivateKey
+	jwks       atomic.Value
+	jwksServed int32
+	// unavailable makes the keys endpoint fail while set.
+	unavailable int32
+	// stall, when it holds a channel, makes the keys endpoint wait for it to
+	// close after announcing the request on stalled.
+	stall   atomic.Value
+	stalled chan struct{}
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+
+	iss := &testIssuer{rsaKey: rsaKey, ecKey: ecKey, stalled: make(chan struct{}, 1)}
+	iss.stall.Store((chan struct{})(nil))
+	iss.publish("rsa", "ec")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"jwks_uri": iss.server.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&iss.jwksServed, 1)
+		if stall := iss.stall.Load().(chan struct{}); stall != nil {
+			iss.stalled <- struct{}{}
+			<-stall
+		}
+		if atomic.LoadInt32(&iss.unavailable) != 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(iss.jwks.Load().([]byte))
+	})
+	iss.server = httptest.NewServer(mux)
+	t.Cleanup(iss.server.Close)
+	return iss
+}
+
+// publish makes the issuer serve its RSA and EC public keys with the given key IDs.
+func (iss *testIssuer) publish(rsaKid, ecKid string) {
+	b64 := base64.RawURLEncoding.EncodeToString
+	data, _ := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{"kty": "RSA", "kid": rsaKid, "use": "sig", "n": b64(iss.rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(iss.rsaKey.E)).Bytes())},
+			{"kty": "EC", "kid": ecKid, "crv": "P-256", "x": b64(iss.ecKey.X.Bytes()), "y": b64(iss.ecKey.Y.Bytes())},
+			{"kty": "oct", "kid": "symmetric", "k": "c2VjcmV0"},
+		},
+	})
+	iss.jwks.Store(data)
+}
+
+// sign returns a JWT with the claims, signed with alg ("RS256" or "ES256").
+func (iss *testIssuer) sign(t *testing.T, alg, kid string, claims map[string]interface{}) string {
+	b64 := base64.RawURLEncoding.EncodeToString
+	header, err := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	require.Nil(t, err)
+	payload, err := json.Marshal(claims)
+	require.Nil(t, err)
+	signed := b64(header) + "." + b64(payload)
+
+	digest := crypto.SHA256.New()
+	digest.Write([]byte(signed))
+	var sig []byte
+	switch alg {
+	case "RS256":
+		sig, err = rsa.SignPKCS1v15(rand.Reader, iss.rsaKey, crypto.SHA256, digest.Sum(nil))
+		require.Nil(t, err)
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, iss.ecKey, digest.Sum(nil))
+		require.Nil(t, err)
+		sig = make([]byte, 64)
+		copy(sig[32-len(r.Bytes()):32], r.Bytes())
+		copy(sig[64-len(s.Bytes()):], s.Bytes())
+	}
+	return signed + "." + b64(sig)
+}
+
+func (iss *testIssuer) claims(now time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"iss":  iss.server.URL,
+		"aud":  []string{"other", "open-match"},
+		"sub":  "director",
+		"exp":  now.Add(time.Hour).Unix(),
+		"role": "admin",
+	}
+}
+
+func (iss *testIssuer) authenticator(t *testing.T, now time.Time) *jwtAuthenticator {
+	cfg := viper.New()
+	cfg.Set("api.auth.type", "jwt")
+	cfg.Set("api.auth.jwt.issuer", iss.server.URL)
+	cfg.Set("api.auth.jwt.audience", "open-match")
+	a, err := AuthenticatorFromConfig(cfg)
+	require.Nil(t, err)
+	jwtA := a.(*jwtAuthenticator)
+	jwtA.now = func() time.Time { return now }
+	jwtA.keys.now = jwtA.now
+	return jwtA
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	iss := newTestIssuer(t)
+	now := time.Now()
+	a := iss.authenticator(t, now)
+
+	with := func(f func(map[string]interface{})) map[string]interface{} {
+		c := iss.claims(now)
+		f(c)
+		return c
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		code  codes.Code
+	}{
+		{"rsa", iss.sign(t, "RS256", "rsa", iss.claims(now)), codes.OK},
+		{"ecdsa", iss.sign(t, "ES256", "ec", iss.claims(now)), codes.OK},
+		{"single audience", iss.sign(t, "RS256", "rsa", with(func(c map[string]interface{}) { c["aud"] = "open-match" })), codes.OK},
+		{"within clock skew", iss.sign(t, "RS256", "rsa", with(func(c map[string]interface{}) { c["exp"] = now.Add(-30 * time.Second).Unix() })), codes.OK},
+		{"expired", iss.sign(t, "RS256", "rsa", with(func(c map[string]interface{}) { c["exp"] = now.Add(-2 * time.Minute).Unix() })), codes.Unauthenticated},
+		{"no expiry", iss.sign(t, "RS256", "rsa", with(func(c map[string]interface{}) { delete(c, "exp") })), codes.Unauthenticated},
+		{"not yet valid", iss.sign(t, "RS256", "rsa", with(func(c map[string]interface{}) { c["nbf"] = now.Add(time.Hour).Unix() })), codes.Unauthenticated},
+		{"wrong issuer", iss.sign(t, "RS256", "rsa", with(func(c map[string]interface{}) { c["iss"] = "https://evil" })), codes.Unauthenticated},
+		{"wrong audience", iss.sign(t, "RS256", "rsa", with(func(c map[string]interface{}) { c["aud"] = "other" })), codes.Unauthenticated},
+		{"key of another type", iss.sign(t, "ES256", "rsa", iss.claims(now)), codes.Unauthenticated},
+		{"symmetric algorithm", iss.sign(t, "HS256", "symmetric", iss.claims(now)), codes.Unauthenticated},
+		{"malformed", "not.a-jwt", codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := a.Authenticate(context.Background(), tt.token)
+			assert.Equal(t, tt.code, status.Code(err), "%v", err)
+			if tt.code == codes.OK {
+				assert.Equal(t, "director", id.Subject)
+				assert.Equal(t, iss.server.URL, id.Issuer)
+				assert.Equal(t, "admin", id.Claims["role"])
+			}
+		})
+	}
+
+	t.Run("tampered", func(t *testing.T) {
+		token := iss.sign(t, "RS256", "rsa", iss.claims(now))
+		other := iss.sign(t, "RS256", "rsa", with(func(c map[string]interface{}) { c["sub"] = "someone else" }))
+		_, err := a.Authenticate(context.Background(), token[:len(token)-20]+other[len(other)-20:])
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestJWTAuthenticatorKeyRotation(t *testing.T) {
+	assert := assert.New(t)
+	iss := newTestIssuer(t)
+	now := time.Now()
+	a := iss.authenticator(t, now)
+	ctx := context.Background()
+
+	_, err := a.Authenticate(ctx, iss.sign(t, "RS256", "rsa", iss.claims(now)))
+	assert.Nil(err)
+	assert.Equal(int32(1), atomic.LoadInt32(&iss.jwksServed))
+
+	// Keys are fetched again for an unknown key, but at most once a minute.
+	iss.publish("rsa2", "ec")
+	rotated := iss.sign(t, "RS256", "rsa2", iss.claims(now))
+	_, err = a.Authenticate(ctx, rotated)
+	assert.Equal(codes.Unauthenticated, status.Code(err))
+	assert.Equal(int32(1), atomic.LoadInt32(&iss.jwksServed))
+
+	later := func() time.Time { return now.Add(jwksMinRefreshInterval) }
+	a.now, a.keys.now = later, later
+	_, err = a.Authenticate(ctx, rotated)
+	assert.Nil(err)
+	assert.Equal(int32(2), atomic.LoadInt32(&iss.jwksServed))
+}
+
+func TestJWTAuthenticatorIssuerUnavailable(t *testing.T) {
+	assert := assert.New(t)
+	iss := newTestIssuer(t)
+	now := time.Now()
+	a := iss.authenticator(t, now)
+	ctx := context.Background()
+
+	// A failed fetch counts against the refresh interval too.
+	atomic.StoreInt32(&iss.unavailable, 1)
+	token := iss.sign(t, "RS256", "rsa", iss.claims(now))
+	for i := 0; i < 3; i++ {
+		_, err := a.Authenticate(ctx, token)
+		assert.Equal(codes.Unavailable, status.Code(err))
+	}
+	assert.Equal(int32(1), atomic.LoadInt32(&iss.jwksServed))
+
+	atomic.StoreInt32(&iss.unavailable, 0)
+	later := func() time.Time { return now.Add(jwksMinRefreshInterval) }
+	a.now, a.keys.now = later, later
+	_, err := a.Authenticate(ctx, token)
+	assert.Nil(err)
+	assert.Equal(int32(2), atomic.LoadInt32(&iss.jwksServed))
+}
+
+func TestJWTAuthenticatorSlowIssuer(t *testing.T) {
+	assert := assert.New(t)
+	iss := newTestIssuer(t)
+	now := time.Now()
+	a := iss.authenticator(t, now)
+	ctx := context.Background()
+
+	known := iss.sign(t, "RS256", "rsa", iss.claims(now))
+	_, err := a.Authenticate(ctx, known)
+	assert.Nil(err)
+
+	later := func() time.Time { return now.Add(jwksMinRefreshInterval) }
+	a.now, a.keys.now = later, later
+	stall := make(chan struct{})
+	iss.stall.Store(stall)
+	iss.publish("rsa2", "ec")
+	rotated := iss.sign(t, "RS256", "rsa2", iss.claims(now))
+
+	errs := make(chan error, 2)
+	go func() {
+		_, err := a.Authenticate(ctx, rotated)
+		errs <- err
+	}()
+	<-iss.stalled
+	go func() {
+		_, err := a.Authenticate(ctx, rotated)
+		errs <- err
+	}()
+
+	// Tokens signed with known keys don't wait for the fetch in flight.
+	_, err = a.Authenticate(ctx, known)
+	assert.Nil(err)
+
+	iss.stall.Store((chan struct{})(nil))
+	close(stall)
+	assert.Nil(<-errs)
+	assert.Nil(<-errs)
+	assert.Equal(int32(2), atomic.LoadInt32(&iss.jwksServed))
+}
+
+func TestAuthenticatorFromConfig(t *testing.T) {
+	assert := assert.New(t)
+	cfg := viper.New()
+	a, err := AuthenticatorFromConfig(cfg)
+	assert.Nil(err)
+	assert.Nil(a)
+
+	cfg.Set("api.auth.type", "jwt")
+	_, err = AuthenticatorFromConfig(cfg)
+	assert.NotNil(err)
+
+	cfg.Set("api.auth.type", "unknown")
+	_, err = AuthenticatorFromConfig(cfg)
+	assert.NotNil(err)
+}
@@ -15,12 +15,24 @@
 package rpc
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"os"
 
 	"sync"
 
 	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	// inMemoryListenerBufferSize is the buffer of each direction of in-memory connections.
+	inMemoryListenerBufferSize = 1024 * 1024
+	// localAuthority is the authority, and so the TLS server name, of gRPC connections over unix sockets and
+	// in-memory listeners.
+	localAuthority = "localhost"
 )
 
 // ListenerHolder holds an opened port that can only be handed off to 1 go routine.
@@ -28,6 +40,8 @@ type ListenerHolder struct {
 	number   int
 	listener net.Listener
 	addr     string
+	// dial connects to listeners which aren't TCP ports, e.g. unix sockets.
+	dial func(context.Context, string) (net.Conn, error)
 	sync.RWMutex
 }
 
@@ -43,7 +57,7 @@ func (lh *ListenerHolder) Obtain() (net.Listener, error) {
 	return listener, nil
 }
 
-// Number returns the port number.
+// Number returns the port number, or 0 if the listener isn't a TCP port.
 func (lh *ListenerHolder) Number() int {
 	return lh.number
 }
@@ -55,6 +69,26 @@ func (lh *ListenerHolder) AddrString() string {
 	return lh.addr
 }
 
+// Target returns the address gRPC clients dial to reach the listener, along with its DialOptions.
+func (lh *ListenerHolder) Target() string {
+	if lh.dial == nil {
+		return fmt.Sprintf("localhost:%d", lh.number)
+	}
+	// The passthrough resolver hands the address to the dialer as is.
+	return "passthrough:///" + lh.addr
+}
+
+// DialOptions returns the options gRPC clients need to connect to the listener.
+func (lh *ListenerHolder) DialOptions() []grpc.DialOption {
+	if lh.dial == nil {
+		return nil
+	}
+	return []grpc.DialOption{
+		grpc.WithContextDialer(lh.dial),
+		grpc.WithAuthority(localAuthority),
+	}
+}
+
 // Close shutsdown the TCP listener.
 func (lh *ListenerHolder) Close() error {
 	lh.Lock()
@@ -94,6 +128,42 @@ func newFromPortNumber(portNumber int) (*ListenerHolder, error) {
 	}, nil
 }
 
+// NewUnixListener listens on a unix socket at path, e.g. to serve a match function sidecar without allocating
+// a port.  A socket left behind by a previous process is removed first.
+func NewUnixListener(path string) (*ListenerHolder, error) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, errors.WithStack(fmt.Errorf("cannot remove stale unix socket %s, %s", path, err))
+	}
+	conn, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListenerHolder{
+		listener: conn,
+		addr:     path,
+		dial:     unixDialer,
+	}, nil
+}
+
+func unixDialer(ctx context.Context, path string) (net.Conn, error) {
+	var d net.Dialer
+	return d.DialContext(ctx, "unix", path)
+}
+
+// NewInMemoryListener returns a listener served and dialed within the process, so that tests don't race for
+// ports.  Clients connect to it with its Target and DialOptions.
+func NewInMemoryListener() *ListenerHolder {
+	conn := bufconn.Listen(inMemoryListenerBufferSize)
+	return &ListenerHolder{
+		listener: conn,
+		addr:     conn.Addr().String(),
+		dial: func(ctx context.Context, _ string) (net.Conn, error) {
+			return conn.Dial()
+		},
+	}
+}
+
 // MustListen finds the next available port to open for TCP connections, used in tests to make them isolated.
 func MustListen() *ListenerHolder {
 	// Port 0 in Go is a special port number to randomly choose an available port.
@@ -16,10 +16,23 @@ package rpc
 
 import (
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	shellTesting "open-match.dev/open-match/internal/testing"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
 )
 
 const (
@@ -102,3 +115,70 @@ func TestMustListen(t *testing.T) {
 		})
 	}
 }
+
+// TestUnixListener verifies that gRPC is served on a unix socket, both to clients and to the HTTP proxy.
+func TestUnixListener(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rpc")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "grpc.sock")
+	// A socket left behind is replaced.
+	require.Nil(t, ioutil.WriteFile(path, nil, 0600))
+
+	cfg := viper.New()
+	cfg.Set("api.frontend.grpcsocket", path)
+	grpcLh, err := grpcListenerFromConfig(cfg, "api.frontend")
+	require.Nil(t, err)
+	assert.Equal(t, path, grpcLh.AddrString())
+	assert.Equal(t, 0, grpcLh.Number())
+	httpLh := serveFakeFrontend(t, grpcLh)
+
+	params, err := clientParamsFromConfig(cfg, "api.frontend", "grpcport")
+	require.Nil(t, err)
+	assert.Equal(t, "unix://"+path, params.Address)
+	conn, err := GRPCClientFromParams(params)
+	require.Nil(t, err)
+	defer conn.Close()
+	_, err = pb.NewFrontendServiceClient(conn).CreateTicket(utilTesting.NewContext(t), &pb.CreateTicketRequest{})
+	assert.Nil(t, err)
+
+	assertProxyServes(t, httpLh)
+}
+
+// TestInMemoryListener verifies that gRPC is served on an in-memory listener, both to clients and to the HTTP
+// proxy.
+func TestInMemoryListener(t *testing.T) {
+	grpcLh := NewInMemoryListener()
+	httpLh := serveFakeFrontend(t, grpcLh)
+
+	conn, err := grpc.Dial(grpcLh.Target(), append(grpcLh.DialOptions(), grpc.WithInsecure())...)
+	require.Nil(t, err)
+	defer conn.Close()
+	_, err = pb.NewFrontendServiceClient(conn).CreateTicket(utilTesting.NewContext(t), &pb.CreateTicketRequest{})
+	assert.Nil(t, err)
+
+	assertProxyServes(t, httpLh)
+}
+
+// serveFakeFrontend serves a fake frontend on the gRPC listener, and returns the listener of its HTTP proxy.
+func serveFakeFrontend(t *testing.T, grpcLh *ListenerHolder) *ListenerHolder {
+	httpLh := MustListen()
+	params := NewServerParamsFromListeners(grpcLh, httpLh)
+	params.AddHandleFunc(func(s *grpc.Server) {
+		pb.RegisterFrontendServiceServer(s, &shellTesting.FakeFrontend{})
+	}, pb.RegisterFrontendServiceHandlerFromEndpoint)
+	s := newInsecureServer(grpcLh, httpLh)
+	t.Cleanup(s.stop)
+	waitForStart, err := s.start(params)
+	require.Nil(t, err)
+	waitForStart()
+	return httpLh
+}
+
+func assertProxyServes(t *testing.T, httpLh *ListenerHolder) {
+	httpClient := &http.Client{Timeout: time.Second}
+	resp, err := httpClient.Post(fmt.Sprintf("http://localhost:%d/v1/frontendservice/tickets", httpLh.Number()), "application/json", strings.NewReader("{}"))
+	require.Nil(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
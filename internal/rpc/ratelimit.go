@@ -0,0 +1,166 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"go.opencensus.io/tag"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/telemetry"
+)
+
+const (
+	// configNameRateLimitRules lists the names of the rate limits, each
+	// configured under api.rateLimit.<name>.
+	configNameRateLimitRules = "api.rateLimit.rules"
+)
+
+var (
+	rateLimitMethodKey = tag.MustNewKey("method")
+	mRateLimited       = telemetry.Counter("rpc/rate_limited", "gRPC calls rejected by a rate limit", rateLimitMethodKey)
+)
+
+// rateLimit caps the calls to a gRPC method, or to all methods of a service.
+type rateLimit struct {
+	name string
+	// method is either a full method name, "/openmatch.QueryService/QueryTickets",
+	// or a service name, "openmatch.QueryService".  The limits are shared by all
+	// the methods matched.
+	method string
+	// limiter admits qps calls per second, in bursts of up to burst calls.
+	limiter *rate.Limiter
+	// streams holds a token for each stream in flight, if they're limited.
+	streams chan struct{}
+}
+
+// rateLimitsFromConfig reads the rate limits named by api.rateLimit.rules.
+//
+//	api:
+//	  rateLimit:
+//	    rules: [queryTickets]
+//	    queryTickets:
+//	      method: /openmatch.QueryService/QueryTickets
+//	      qps: 50
+//	      burst: 100
+//	      maxConcurrentStreams: 20
+//
+// qps and maxConcurrentStreams are unlimited if they're not set, and burst
+// defaults to qps.
+func rateLimitsFromConfig(cfg config.View) ([]*rateLimit, error) {
+	var limits []*rateLimit
+	for _, name := range cfg.GetStringSlice(configNameRateLimitRules) {
+		prefix := "api.rateLimit." + name
+		method := cfg.GetString(prefix + ".method")
+		if method == "" {
+			return nil, fmt.Errorf("%s.method must be set", prefix)
+		}
+		qps := cfg.GetFloat64(prefix + ".qps")
+		burst := cfg.GetInt(prefix + ".burst")
+		streams := cfg.GetInt(prefix + ".maxConcurrentStreams")
+		if qps < 0 || burst < 0 || streams < 0 {
+			return nil, fmt.Errorf("%s limits must not be negative", prefix)
+		}
+		limits = append(limits, newRateLimit(name, method, qps, burst, streams))
+	}
+	return limits, nil
+}
+
+func newRateLimit(name, method string, qps float64, burst, maxConcurrentStreams int) *rateLimit {
+	l := &rateLimit{
+		name:   name,
+		method: method,
+	}
+	if qps > 0 {
+		if burst == 0 {
+			burst = int(math.Ceil(qps))
+		}
+		l.limiter = rate.NewLimiter(rate.Limit(qps), burst)
+	}
+	if maxConcurrentStreams > 0 {
+		l.streams = make(chan struct{}, maxConcurrentStreams)
+	}
+	return l
+}
+
+func (l *rateLimit) matches(fullMethod string) bool {
+	return l.method == fullMethod || l.method == serviceName(fullMethod)
+}
+
+// allow admits a call, returning ResourceExhausted if it's over the limit.
+func (l *rateLimit) allow(ctx context.Context, fullMethod string) error {
+	if l.limiter != nil && !l.limiter.Allow() {
+		return rateLimited(ctx, fullMethod, "rate limit %s exceeded", l.name)
+	}
+	return nil
+}
+
+// acquireStream admits a stream, returning the function ending it.
+func (l *rateLimit) acquireStream(ctx context.Context, fullMethod string) (func(), error) {
+	if l.streams == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.streams <- struct{}{}:
+		return func() { <-l.streams }, nil
+	default:
+		return nil, rateLimited(ctx, fullMethod, "rate limit %s allows at most %d concurrent streams", l.name, cap(l.streams))
+	}
+}
+
+func rateLimited(ctx context.Context, fullMethod string, format string, args ...interface{}) error {
+	telemetry.RecordUnitMeasurement(ctx, mRateLimited, tag.Upsert(rateLimitMethodKey, fullMethod))
+	return status.Errorf(codes.ResourceExhausted, format, args...)
+}
+
+func rateLimitUnaryServerInterceptor(limits []*rateLimit) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		for _, l := range limits {
+			if !l.matches(info.FullMethod) {
+				continue
+			}
+			if err := l.allow(ctx, info.FullMethod); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+func rateLimitStreamServerInterceptor(limits []*rateLimit) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := stream.Context()
+		for _, l := range limits {
+			if !l.matches(info.FullMethod) {
+				continue
+			}
+			if err := l.allow(ctx, info.FullMethod); err != nil {
+				return err
+			}
+			release, err := l.acquireStream(ctx, info.FullMethod)
+			if err != nil {
+				return err
+			}
+			defer release()
+		}
+		return handler(srv, stream)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRateLimitsFromConfig(t *testing.T) {
+	assert := assert.New(t)
+	cfg := viper.New()
+	limits, err := rateLimitsFromConfig(cfg)
+	assert.Nil(err)
+	assert.Empty(limits)
+
+	cfg.Set("api.rateLimit.rules", []string{"queryTickets", "synchronizer"})
+	cfg.Set("api.rateLimit.queryTickets.method", "/openmatch.QueryService/QueryTickets")
+	cfg.Set("api.rateLimit.queryTickets.qps", 2.5)
+	cfg.Set("api.rateLimit.synchronizer.method", "openmatch.Synchronizer")
+	cfg.Set("api.rateLimit.synchronizer.maxConcurrentStreams", 4)
+	limits, err = rateLimitsFromConfig(cfg)
+	require.Nil(t, err)
+	require.Len(t, limits, 2)
+	assert.Equal(3, limits[0].limiter.Burst())
+	assert.Nil(limits[0].streams)
+	assert.Nil(limits[1].limiter)
+	assert.Equal(4, cap(limits[1].streams))
+
+	assert.True(limits[0].matches("/openmatch.QueryService/QueryTickets"))
+	assert.False(limits[0].matches("/openmatch.QueryService/QueryTicketIds"))
+	assert.True(limits[1].matches("/openmatch.Synchronizer/Synchronize"))
+
+	cfg.Set("api.rateLimit.synchronizer.method", "")
+	_, err = rateLimitsFromConfig(cfg)
+	assert.NotNil(err)
+}
+
+func TestRateLimitUnaryServerInterceptor(t *testing.T) {
+	assert := assert.New(t)
+	// The bucket refills too slowly for the test to notice.
+	interceptor := rateLimitUnaryServerInterceptor([]*rateLimit{
+		newRateLimit("frontend", "openmatch.FrontendService", 0.001, 2, 0),
+	})
+	handler := func(context.Context, interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	call := func(method string) error {
+		_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+
+	assert.Nil(call("/openmatch.FrontendService/CreateTicket"))
+	assert.Nil(call("/openmatch.FrontendService/GetTicket"))
+	assert.Equal(codes.ResourceExhausted, status.Code(call("/openmatch.FrontendService/CreateTicket")))
+	assert.Nil(call("/openmatch.BackendService/FetchMatches"))
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+}
+
+func (fakeServerStream) Context() context.Context {
+	return context.Background()
+}
+
+func TestRateLimitStreamServerInterceptor(t *testing.T) {
+	assert := assert.New(t)
+	interceptor := rateLimitStreamServerInterceptor([]*rateLimit{
+		newRateLimit("query", "/openmatch.QueryService/QueryTickets", 0, 0, 2),
+	})
+	info := &grpc.StreamServerInfo{FullMethod: "/openmatch.QueryService/QueryTickets"}
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	done := make(chan error)
+	for i := 0; i < 2; i++ {
+		go func() {
+			done <- interceptor(nil, fakeServerStream{}, info, func(interface{}, grpc.ServerStream) error {
+				started <- struct{}{}
+				<-release
+				return nil
+			})
+		}()
+		<-started
+	}
+
+	err := interceptor(nil, fakeServerStream{}, info, func(interface{}, grpc.ServerStream) error {
+		return nil
+	})
+	assert.Equal(codes.ResourceExhausted, status.Code(err))
+
+	// Ended streams make room for new ones.
+	release <- struct{}{}
+	assert.Nil(<-done)
+	err = interceptor(nil, fakeServerStream{}, info, func(interface{}, grpc.ServerStream) error {
+		return nil
+	})
+	assert.Nil(err)
+
+	close(release)
+	assert.Nil(<-done)
+}
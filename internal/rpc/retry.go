@@ -0,0 +1,389 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/config"
+)
+
+const (
+	configNameRetryMethods        = "api.grpc.retry.methods"
+	configNameRetryCodes          = "api.grpc.retry.codes"
+	configNameRetryMaxAttempts    = "api.grpc.retry.maxAttempts"
+	configNameRetryPerTryTimeout  = "api.grpc.retry.perTryTimeout"
+	configNameRetryInitialBackoff = "api.grpc.retry.initialBackoff"
+	configNameRetryHedgingDelay   = "api.grpc.retry.hedgingDelay"
+
+	defaultRetryInitialBackoff = 100 * time.Millisecond
+	maxRetryBackoff            = 5 * time.Second
+)
+
+// RetryPolicy retries, or hedges, the calls of gRPC clients to idempotent
+// methods.  Unary calls and server streams are retried until they return, or
+// until the first message of the stream arrives; later stream errors aren't
+// retried since messages have been handed to the caller.
+type RetryPolicy struct {
+	// Methods are the full method names, e.g.
+	// "/openmatch.QueryService/QueryTickets", or service names, e.g.
+	// "openmatch.QueryService", of the calls which may be retried.
+	Methods []string
+	// Codes are the status codes of the attempts which are retried.
+	Codes []codes.Code
+	// MaxAttempts is the number of attempts of a call.  Calls aren't retried
+	// if it's less than 2.
+	MaxAttempts int
+	// PerTryTimeout bounds each attempt, if set.  Attempts timing out are
+	// always retried.
+	PerTryTimeout time.Duration
+	// InitialBackoff is the delay before the first retry, doubling for each
+	// further retry.
+	InitialBackoff time.Duration
+	// HedgingDelay, if set, starts another attempt whenever the attempts in
+	// flight haven't returned within the delay, instead of waiting for them to
+	// fail.  The first attempt to succeed is used and the others are canceled.
+	HedgingDelay time.Duration
+}
+
+// retryPolicyFromConfig reads the api.grpc.retry config.
+func retryPolicyFromConfig(cfg config.View) (RetryPolicy, error) {
+	p := RetryPolicy{
+		Methods:        cfg.GetStringSlice(configNameRetryMethods),
+		Codes:          []codes.Code{codes.Unavailable},
+		MaxAttempts:    cfg.GetInt(configNameRetryMaxAttempts),
+		PerTryTimeout:  cfg.GetDuration(configNameRetryPerTryTimeout),
+		InitialBackoff: defaultRetryInitialBackoff,
+		HedgingDelay:   cfg.GetDuration(configNameRetryHedgingDelay),
+	}
+	if cfg.IsSet(configNameRetryInitialBackoff) {
+		p.InitialBackoff = cfg.GetDuration(configNameRetryInitialBackoff)
+	}
+	if cfg.IsSet(configNameRetryCodes) {
+		p.Codes = nil
+		for _, name := range cfg.GetStringSlice(configNameRetryCodes) {
+			var c codes.Code
+			if err := c.UnmarshalJSON([]byte(strconv.Quote(name))); err != nil {
+				return RetryPolicy{}, fmt.Errorf("invalid %s %q, want names like UNAVAILABLE", configNameRetryCodes, name)
+			}
+			p.Codes = append(p.Codes, c)
+		}
+	}
+	return p, nil
+}
+
+func (p RetryPolicy) enabled() bool {
+	return p.MaxAttempts > 1 && len(p.Methods) > 0
+}
+
+func (p RetryPolicy) applies(fullMethod string) bool {
+	for _, m := range p.Methods {
+		if m == fullMethod || m == serviceName(fullMethod) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	code := status.Code(err)
+	for _, c := range p.Codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < retry && d < maxRetryBackoff; i++ {
+		d *= 2
+	}
+	if d > maxRetryBackoff {
+		d = maxRetryBackoff
+	}
+	return d
+}
+
+func (p RetryPolicy) interceptors() (grpc.StreamClientInterceptor, grpc.UnaryClientInterceptor) {
+	if !p.enabled() {
+		return nil, nil
+	}
+	return p.streamClientInterceptor, p.unaryClientInterceptor
+}
+
+type attemptResult struct {
+	index    int
+	value    interface{}
+	err      error
+	timedOut bool
+}
+
+// do runs attempts until one succeeds, fails with an error which isn't
+// retried, or the attempts run out.  It returns the value of the successful
+// attempt and the function canceling its context.
+func (p RetryPolicy) do(ctx context.Context, attempt func(context.Context) (interface{}, error)) (interface{}, context.CancelFunc, error) {
+	results := make(chan attemptResult, p.MaxAttempts)
+	var cancels []context.CancelFunc
+	cancelAll := func(except int) {
+		for i, cancel := range cancels {
+			if i != except {
+				cancel()
+			}
+		}
+	}
+	start := func() {
+		actx, cancel := context.WithCancel(ctx)
+		cancels = append(cancels, cancel)
+		index := len(cancels) - 1
+		go func() {
+			v, timedOut, err := p.attempt(actx, attempt)
+			results <- attemptResult{index: index, value: v, err: err, timedOut: timedOut}
+		}()
+	}
+
+	var hedge <-chan time.Time
+	var retry <-chan time.Time
+	startNext := func() {
+		start()
+		if p.HedgingDelay > 0 && len(cancels) < p.MaxAttempts {
+			hedge = time.After(p.HedgingDelay)
+		} else {
+			hedge = nil
+		}
+	}
+
+	startNext()
+	inFlight := 1
+	for {
+		select {
+		case r := <-results:
+			inFlight--
+			if r.err == nil {
+				cancelAll(r.index)
+				return r.value, cancels[r.index], nil
+			}
+			if ctx.Err() != nil || !(r.timedOut || p.retryable(r.err)) {
+				cancelAll(-1)
+				return nil, nil, r.err
+			}
+			if len(cancels) == p.MaxAttempts {
+				if inFlight == 0 {
+					cancelAll(-1)
+					return nil, nil, r.err
+				}
+				continue
+			}
+			if p.HedgingDelay > 0 {
+				// Hedged attempts don't back off; the delay already spaces them out.
+				startNext()
+				inFlight++
+			} else {
+				retry = time.After(p.backoff(len(cancels)))
+			}
+		case <-hedge:
+			startNext()
+			inFlight++
+		case <-retry:
+			retry = nil
+			startNext()
+			inFlight++
+		case <-ctx.Done():
+			cancelAll(-1)
+			return nil, nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+// attempt runs one attempt, bounded by the per-try timeout.
+func (p RetryPolicy) attempt(ctx context.Context, attempt func(context.Context) (interface{}, error)) (interface{}, bool, error) {
+	if p.PerTryTimeout <= 0 {
+		v, err := attempt(ctx)
+		return v, false, err
+	}
+
+	tctx, cancel := context.WithCancel(ctx)
+	timer := time.AfterFunc(p.PerTryTimeout, cancel)
+	v, err := attempt(tctx)
+	if !timer.Stop() && err != nil && ctx.Err() == nil {
+		return nil, true, status.Errorf(codes.DeadlineExceeded, "attempt timed out after %s: %s", p.PerTryTimeout, err)
+	}
+	return v, false, err
+}
+
+func (p RetryPolicy) unaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	replyMsg, ok := reply.(proto.Message)
+	if !p.applies(method) || !ok {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+
+	v, cancel, err := p.do(ctx, func(actx context.Context) (interface{}, error) {
+		// Hedged attempts can't share the reply.
+		r := proto.Clone(replyMsg)
+		if err := invoker(actx, method, req, r, cc, opts...); err != nil {
+			return nil, err
+		}
+		return r, nil
+	})
+	if err != nil {
+		return err
+	}
+	cancel()
+	replyMsg.Reset()
+	proto.Merge(replyMsg, v.(proto.Message))
+	return nil
+}
+
+func (p RetryPolicy) streamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	// Only server streams, sending a single request, can be replayed.
+	if !p.applies(method) || desc.ClientStreams || !desc.ServerStreams {
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+	return &retryingClientStream{
+		ctx:    ctx,
+		policy: p,
+		start: func(actx context.Context) (grpc.ClientStream, error) {
+			return streamer(actx, desc, cc, method, opts...)
+		},
+	}, nil
+}
+
+// retryingClientStream holds the request of a server stream until the first
+// message is received, and then opens the stream with the retry policy.
+// Header and Trailer are empty until then.
+type retryingClientStream struct {
+	ctx    context.Context
+	policy RetryPolicy
+	start  func(context.Context) (grpc.ClientStream, error)
+
+	req    interface{}
+	stream grpc.ClientStream
+	cancel context.CancelFunc
+	first  proto.Message
+	eof    bool
+	err    error
+}
+
+type streamAttempt struct {
+	stream grpc.ClientStream
+	first  proto.Message
+	eof    bool
+}
+
+func (s *retryingClientStream) SendMsg(m interface{}) error {
+	s.req = m
+	return nil
+}
+
+func (s *retryingClientStream) CloseSend() error {
+	return nil
+}
+
+func (s *retryingClientStream) RecvMsg(m interface{}) error {
+	if s.stream == nil && s.err == nil {
+		s.open(m)
+	}
+	if s.err != nil {
+		return s.err
+	}
+	if s.first != nil {
+		msg := m.(proto.Message)
+		msg.Reset()
+		proto.Merge(msg, s.first)
+		s.first = nil
+		return nil
+	}
+	if s.eof {
+		s.err = io.EOF
+		s.cancel()
+		return s.err
+	}
+
+	if err := s.stream.RecvMsg(m); err != nil {
+		s.err = err
+		s.cancel()
+		return err
+	}
+	return nil
+}
+
+func (s *retryingClientStream) open(m interface{}) {
+	template, ok := m.(proto.Message)
+	if !ok {
+		s.err = status.Errorf(codes.Internal, "cannot retry streams of %T", m)
+		return
+	}
+
+	v, cancel, err := s.policy.do(s.ctx, func(actx context.Context) (interface{}, error) {
+		cs, err := s.start(actx)
+		if err != nil {
+			return nil, err
+		}
+		// io.EOF means the stream failed, and RecvMsg returns why.
+		if err := cs.SendMsg(s.req); err != nil && err != io.EOF {
+			return nil, err
+		}
+		if err := cs.CloseSend(); err != nil {
+			return nil, err
+		}
+		first := proto.Clone(template)
+		first.Reset()
+		switch err := cs.RecvMsg(first); err {
+		case nil:
+			return &streamAttempt{stream: cs, first: first}, nil
+		case io.EOF:
+			return &streamAttempt{stream: cs, eof: true}, nil
+		default:
+			return nil, err
+		}
+	})
+	if err != nil {
+		s.err = err
+		return
+	}
+	a := v.(*streamAttempt)
+	s.stream, s.cancel, s.first, s.eof = a.stream, cancel, a.first, a.eof
+}
+
+func (s *retryingClientStream) Header() (metadata.MD, error) {
+	if s.stream == nil {
+		return metadata.MD{}, nil
+	}
+	return s.stream.Header()
+}
+
+func (s *retryingClientStream) Trailer() metadata.MD {
+	if s.stream == nil {
+		return metadata.MD{}
+	}
+	return s.stream.Trailer()
+}
+
+func (s *retryingClientStream) Context() context.Context {
+	if s.stream == nil {
+		return s.ctx
+	}
+	return s.stream.Context()
+}
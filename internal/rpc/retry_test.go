@@ -0,0 +1,245 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// flakyQuery fails, hangs or succeeds in turn for each call.
+type flakyQuery struct {
+	pb.UnimplementedQueryServiceServer
+	mu       sync.Mutex
+	attempts []error
+	calls    int
+	canceled int
+}
+
+// errHang makes an attempt block until it's canceled.
+var errHang = status.Error(codes.Unknown, "hang")
+
+func (q *flakyQuery) next(ctx context.Context) error {
+	q.mu.Lock()
+	err := error(nil)
+	if q.calls < len(q.attempts) {
+		err = q.attempts[q.calls]
+	}
+	q.calls++
+	q.mu.Unlock()
+
+	if err == errHang {
+		<-ctx.Done()
+		q.mu.Lock()
+		q.canceled++
+		q.mu.Unlock()
+		return ctx.Err()
+	}
+	return err
+}
+
+func (q *flakyQuery) GetPoolStats(ctx context.Context, _ *pb.GetPoolStatsRequest) (*pb.GetPoolStatsResponse, error) {
+	if err := q.next(ctx); err != nil {
+		return nil, err
+	}
+	return &pb.GetPoolStatsResponse{TicketCount: 7}, nil
+}
+
+func (q *flakyQuery) QueryTickets(_ *pb.QueryTicketsRequest, stream pb.QueryService_QueryTicketsServer) error {
+	if err := q.next(stream.Context()); err != nil {
+		return err
+	}
+	for _, id := range []string{"1", "2"} {
+		if err := stream.Send(&pb.QueryTicketsResponse{Tickets: []*pb.Ticket{{Id: id}}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (q *flakyQuery) stats() (int, int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.calls, q.canceled
+}
+
+func serveFlakyQuery(t *testing.T, q *flakyQuery, policy RetryPolicy) pb.QueryServiceClient {
+	grpcLh := MustListen()
+	httpLh := MustListen()
+	params := NewServerParamsFromListeners(grpcLh, httpLh)
+	params.AddHandleFunc(func(s *grpc.Server) {
+		pb.RegisterQueryServiceServer(s, q)
+	}, nil)
+	s := newInsecureServer(grpcLh, httpLh)
+	t.Cleanup(s.stop)
+	waitForStart, err := s.start(params)
+	require.Nil(t, err)
+	waitForStart()
+
+	conn, err := GRPCClientFromParams(&ClientParams{Address: grpcLh.Target(), Retry: policy})
+	require.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewQueryServiceClient(conn)
+}
+
+func queryTicketIDs(ctx context.Context, client pb.QueryServiceClient) ([]string, error) {
+	stream, err := client.QueryTickets(ctx, &pb.QueryTicketsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return ids, nil
+		}
+		if err != nil {
+			return ids, err
+		}
+		for _, ticket := range resp.GetTickets() {
+			ids = append(ids, ticket.GetId())
+		}
+	}
+}
+
+func TestRetryPolicyFromConfig(t *testing.T) {
+	assert := assert.New(t)
+	cfg := viper.New()
+	p, err := retryPolicyFromConfig(cfg)
+	assert.Nil(err)
+	assert.False(p.enabled())
+	assert.Equal([]codes.Code{codes.Unavailable}, p.Codes)
+
+	cfg.Set("api.grpc.retry.methods", []string{"openmatch.QueryService", "/openmatch.FrontendService/GetTicket"})
+	cfg.Set("api.grpc.retry.maxAttempts", 3)
+	cfg.Set("api.grpc.retry.codes", []string{"UNAVAILABLE", "DEADLINE_EXCEEDED"})
+	p, err = retryPolicyFromConfig(cfg)
+	assert.Nil(err)
+	assert.True(p.enabled())
+	assert.Equal([]codes.Code{codes.Unavailable, codes.DeadlineExceeded}, p.Codes)
+	assert.True(p.applies("/openmatch.QueryService/QueryTickets"))
+	assert.True(p.applies("/openmatch.FrontendService/GetTicket"))
+	assert.False(p.applies("/openmatch.FrontendService/CreateTicket"))
+
+	cfg.Set("api.grpc.retry.codes", []string{"Unavailable"})
+	_, err = retryPolicyFromConfig(cfg)
+	assert.NotNil(err)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: time.Second}
+	assert.Equal(t, time.Second, p.backoff(1))
+	assert.Equal(t, 4*time.Second, p.backoff(3))
+	assert.Equal(t, maxRetryBackoff, p.backoff(10))
+}
+
+func TestRetry(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "restarting")
+	policy := RetryPolicy{
+		Methods:        []string{"openmatch.QueryService"},
+		Codes:          []codes.Code{codes.Unavailable},
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+	}
+
+	tests := []struct {
+		name     string
+		policy   RetryPolicy
+		attempts []error
+		code     codes.Code
+		calls    int
+	}{
+		{"succeeds", policy, nil, codes.OK, 1},
+		{"retried", policy, []error{unavailable, unavailable}, codes.OK, 3},
+		{"out of attempts", policy, []error{unavailable, unavailable, unavailable}, codes.Unavailable, 3},
+		{"not retryable", policy, []error{status.Error(codes.InvalidArgument, "bad")}, codes.InvalidArgument, 1},
+		{"disabled", RetryPolicy{}, []error{unavailable}, codes.Unavailable, 1},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Run("unary", func(t *testing.T) {
+				q := &flakyQuery{attempts: tt.attempts}
+				client := serveFlakyQuery(t, q, tt.policy)
+				resp, err := client.GetPoolStats(utilTesting.NewContext(t), &pb.GetPoolStatsRequest{})
+				assert.Equal(t, tt.code, status.Code(err), "%v", err)
+				if err == nil {
+					assert.Equal(t, int64(7), resp.GetTicketCount())
+				}
+				calls, _ := q.stats()
+				assert.Equal(t, tt.calls, calls)
+			})
+			t.Run("stream", func(t *testing.T) {
+				q := &flakyQuery{attempts: tt.attempts}
+				client := serveFlakyQuery(t, q, tt.policy)
+				ids, err := queryTicketIDs(utilTesting.NewContext(t), client)
+				assert.Equal(t, tt.code, status.Code(err), "%v", err)
+				if err == nil {
+					assert.Equal(t, []string{"1", "2"}, ids)
+				}
+				calls, _ := q.stats()
+				assert.Equal(t, tt.calls, calls)
+			})
+		})
+	}
+}
+
+func TestRetryPerTryTimeout(t *testing.T) {
+	q := &flakyQuery{attempts: []error{errHang}}
+	client := serveFlakyQuery(t, q, RetryPolicy{
+		Methods:       []string{"openmatch.QueryService"},
+		MaxAttempts:   2,
+		PerTryTimeout: 50 * time.Millisecond,
+	})
+
+	ids, err := queryTicketIDs(utilTesting.NewContext(t), client)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1", "2"}, ids)
+	calls, _ := q.stats()
+	assert.Equal(t, 2, calls)
+}
+
+func TestHedging(t *testing.T) {
+	q := &flakyQuery{attempts: []error{errHang}}
+	client := serveFlakyQuery(t, q, RetryPolicy{
+		Methods:      []string{"openmatch.QueryService"},
+		Codes:        []codes.Code{codes.Unavailable},
+		MaxAttempts:  3,
+		HedgingDelay: 20 * time.Millisecond,
+	})
+
+	resp, err := client.GetPoolStats(utilTesting.NewContext(t), &pb.GetPoolStatsRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(7), resp.GetTicketCount())
+
+	// The hung attempt is canceled once the hedged one succeeds.
+	assert.Eventually(t, func() bool {
+		calls, canceled := q.stats()
+		return calls == 2 && canceled == 1
+	}, time.Second, 5*time.Millisecond)
+}
@@ -16,11 +16,12 @@ package rpc
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httputil"
-	"time"
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_logrus "github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus"
@@ -34,7 +35,8 @@ import (
 	"go.opencensus.io/plugin/ochttp"
 	"go.opencensus.io/plugin/ochttp/propagation/b3"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/keepalive"
+	channelz "google.golang.org/grpc/channelz/service"
+	"google.golang.org/grpc/reflection"
 	"open-match.dev/open-match/internal/config"
 	"open-match.dev/open-match/internal/logging"
 	"open-match.dev/open-match/internal/signal"
@@ -45,6 +47,14 @@ const (
 	configNameServerPublicCertificateFile = "api.tls.certificateFile"
 	configNameServerPrivateKeyFile        = "api.tls.privateKey"
 	configNameServerRootCertificatePath   = "api.tls.rootCertificateFile"
+	// configNameRequireClientCertificate enables mutual TLS, where clients
+	// must present a certificate signed by the root CA.
+	configNameRequireClientCertificate = "api.tls.requireClientCertificate"
+	// configNameEnableReflection and configNameEnableChannelz serve the gRPC
+	// reflection and channelz services alongside every service, for debugging
+	// with tools like grpcurl.
+	configNameEnableReflection = "api.grpc.reflection"
+	configNameEnableChannelz   = "api.grpc.channelz"
 )
 
 var (
@@ -79,16 +89,40 @@ type ServerParams struct {
 	publicCertificateFileData []byte
 	// Private key in PEM format.
 	privateKeyFileData []byte
-
+	// getCertificate and getRootCAs return the certificate and the trusted root CA, when they're reloaded from
+	// files as they're rotated.  Otherwise the file data above is used.
+	getCertificate func() (*tls.Certificate, error)
+	getRootCAs     func() (*x509.CertPool, error)
+	// requireClientCertificate makes the servers verify client certificates against the root CA.
+	requireClientCertificate bool
+	// authenticators verify the callers of the gRPC services they're keyed by.
+	authenticators map[string]Authenticator
+	// rateLimits cap the calls to the methods they match.
+	rateLimits []*rateLimit
+	// unaryInterceptors, streamInterceptors and proxyDialOptions are added by
+	// the services, see AddUnaryInterceptor.
+	unaryInterceptors  []grpc.UnaryServerInterceptor
+	streamInterceptors []grpc.StreamServerInterceptor
+	proxyDialOptions   []grpc.DialOption
+
+	grpcTuning GRPCTuning
+	// compression of the HTTP proxy's responses, see compressionFromConfig.
+	compression string
+	// cors lets browsers on other origins call the HTTP proxy, if it's set.
+	cors *corsPolicy
+	// forwardedHeaders are the HTTP headers the proxy passes on as gRPC metadata.
+	forwardedHeaders        []string
 	enableRPCLogging        bool
 	enableRPCPayloadLogging bool
 	enableMetrics           bool
+	enableReflection        bool
+	enableChannelz          bool
 	closer                  func()
 }
 
 // NewServerParamsFromConfig returns server Params initialized from the configuration file.
 func NewServerParamsFromConfig(cfg config.View, prefix string) (*ServerParams, error) {
-	grpcLh, err := newFromPortNumber(cfg.GetInt(prefix + ".grpcport"))
+	grpcLh, err := grpcListenerFromConfig(cfg, prefix)
 	if err != nil {
 		serverLogger.Fatal(err)
 		return nil, err
@@ -133,9 +167,41 @@ func NewServerParamsFromConfig(cfg config.View, prefix string) (*ServerParams, e
 			}
 		}
 		p.SetTLSConfiguration(rootPublicCertData, publicCertData, privateKeyData)
+
+		certificates, err := newCertificateReloader(certFile, privateKeyFile)
+		if err != nil {
+			p.invalidate()
+			return nil, err
+		}
+		if len(rootCertFile) == 0 {
+			rootCertFile = certFile
+		}
+		rootCAs, err := newCertPoolReloader(rootCertFile)
+		if err != nil {
+			p.invalidate()
+			return nil, err
+		}
+		p.getCertificate = certificates.get
+		p.getRootCAs = rootCAs.get
+		p.requireClientCertificate = cfg.GetBool(configNameRequireClientCertificate)
 	}
 
+	p.grpcTuning = grpcTuningFromConfig(cfg)
+	p.cors = corsPolicyFromConfig(cfg)
+	p.forwardedHeaders = cfg.GetStringSlice(configNameForwardedHeaders)
+	p.rateLimits, err = rateLimitsFromConfig(cfg)
+	if err != nil {
+		p.invalidate()
+		return nil, err
+	}
+	p.compression, err = compressionFromConfig(cfg, prefix)
+	if err != nil {
+		p.invalidate()
+		return nil, err
+	}
 	p.enableMetrics = cfg.GetBool(telemetry.ConfigNameEnableMetrics)
+	p.enableReflection = cfg.GetBool(configNameEnableReflection)
+	p.enableChannelz = cfg.GetBool(configNameEnableChannelz)
 	p.enableRPCLogging = cfg.GetBool(ConfigNameEnableRPCLogging)
 	p.enableRPCPayloadLogging = logging.IsDebugEnabled(cfg)
 	// TODO: This isn't ideal since telemetry requires config for it to be initialized.
@@ -144,6 +210,15 @@ func NewServerParamsFromConfig(cfg config.View, prefix string) (*ServerParams, e
 	return p, nil
 }
 
+// grpcListenerFromConfig listens on the unix socket at prefix.grpcsocket if it's set, and on prefix.grpcport
+// otherwise.
+func grpcListenerFromConfig(cfg config.View, prefix string) (*ListenerHolder, error) {
+	if path := cfg.GetString(prefix + ".grpcsocket"); path != "" {
+		return NewUnixListener(path)
+	}
+	return newFromPortNumber(cfg.GetInt(prefix + ".grpcport"))
+}
+
 // NewServerParamsFromListeners returns server Params initialized with the ListenerHolder variables.
 func NewServerParamsFromListeners(grpcLh *ListenerHolder, proxyLh *ListenerHolder) *ServerParams {
 	return &ServerParams{
@@ -166,6 +241,64 @@ func (p *ServerParams) SetTLSConfiguration(rootCaPublicCertificateFileData []byt
 	return p
 }
 
+// RequireClientCertificate configures the server, if running in TLS mode, to only accept clients presenting a
+// certificate signed by the root CA.
+func (p *ServerParams) RequireClientCertificate() *ServerParams {
+	p.requireClientCertificate = true
+	return p
+}
+
+// tlsSources returns the functions providing the server certificate and the trusted root CA.
+func (p *ServerParams) tlsSources() (func() (*tls.Certificate, error), func() (*x509.CertPool, error), error) {
+	getCertificate, getRootCAs := p.getCertificate, p.getRootCAs
+	if getCertificate == nil {
+		cert, err := certificateFromFileData(p.publicCertificateFileData, p.privateKeyFileData)
+		if err != nil {
+			return nil, nil, err
+		}
+		getCertificate = func() (*tls.Certificate, error) {
+			return cert, nil
+		}
+	}
+	if getRootCAs == nil {
+		pool, err := trustedCertificateFromFileData(p.rootCaPublicCertificateFileData)
+		if err != nil {
+			return nil, nil, err
+		}
+		getRootCAs = func() (*x509.CertPool, error) {
+			return pool, nil
+		}
+	}
+	return getCertificate, getRootCAs, nil
+}
+
+// serverTLSConfig returns the TLS configuration shared by the gRPC and HTTPS servers.  The certificate and, for
+// mutual TLS, the root CA are fetched on each handshake, so that rotated files are picked up.
+func (p *ServerParams) serverTLSConfig(getCertificate func() (*tls.Certificate, error), getRootCAs func() (*x509.CertPool, error)) *tls.Config {
+	c := &tls.Config{
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return getCertificate()
+		},
+		NextProtos: []string{http2WithTLSVersionID}, // https://github.com/grpc-ecosystem/grpc-gateway/issues/220
+	}
+	if !p.requireClientCertificate {
+		return c
+	}
+
+	c.ClientAuth = tls.RequireAndVerifyClientCert
+	c.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		pool, err := getRootCAs()
+		if err != nil {
+			return nil, err
+		}
+		cc := c.Clone()
+		cc.GetConfigForClient = nil
+		cc.ClientCAs = pool
+		return cc, nil
+	}
+	return c
+}
+
 // usingTLS returns true if a certificate is set.
 func (p *ServerParams) usingTLS() bool {
 	return len(p.publicCertificateFileData) > 0
@@ -181,6 +314,31 @@ func (p *ServerParams) AddHandleFunc(handlerFunc GrpcHandler, grpcProxyHandler G
 	}
 }
 
+// AddUnaryInterceptor appends interceptors to the unary calls of the gRPC
+// server, e.g. for auth, quota or tenant routing middleware.  They run after
+// Open Match's own interceptors, in the order they're added, so the caller's
+// Identity is known to them if authentication is enabled.
+func (p *ServerParams) AddUnaryInterceptor(interceptors ...grpc.UnaryServerInterceptor) *ServerParams {
+	p.unaryInterceptors = append(p.unaryInterceptors, interceptors...)
+	return p
+}
+
+// AddStreamInterceptor appends interceptors to the streaming calls of the gRPC
+// server, see AddUnaryInterceptor.
+func (p *ServerParams) AddStreamInterceptor(interceptors ...grpc.StreamServerInterceptor) *ServerParams {
+	p.streamInterceptors = append(p.streamInterceptors, interceptors...)
+	return p
+}
+
+// AddProxyDialOptions appends options to the connection the HTTP proxy makes
+// to the gRPC server, e.g. client interceptors setting metadata.  Options
+// setting a client interceptor replace those of Open Match, so use
+// grpc.WithChainUnaryInterceptor and grpc.WithChainStreamInterceptor instead.
+func (p *ServerParams) AddProxyDialOptions(opts ...grpc.DialOption) *ServerParams {
+	p.proxyDialOptions = append(p.proxyDialOptions, opts...)
+	return p
+}
+
 // AddHealthCheckFunc adds a readiness probe to tell Kubernetes the service is able to handle traffic.
 func (p *ServerParams) AddHealthCheckFunc(handlerFunc func(context.Context) error) {
 	if handlerFunc != nil {
@@ -302,18 +460,46 @@ func instrumentHTTPHandler(handler http.Handler, params *ServerParams) http.Hand
 	return handler
 }
 
+// bindGRPCHandlers registers the services of the server, and the debugging
+// services if they're enabled.
+func bindGRPCHandlers(s *grpc.Server, params *ServerParams) {
+	for _, handlerFunc := range params.handlersForGrpc {
+		handlerFunc(s)
+	}
+	if params.enableReflection {
+		reflection.Register(s)
+	}
+	if params.enableChannelz {
+		channelz.RegisterChannelzServiceToServer(s)
+	}
+}
+
 func newGRPCServerOptions(params *ServerParams) []grpc.ServerOption {
 	opts := []grpc.ServerOption{}
 	si := []grpc.StreamServerInterceptor{
 		grpc_recovery.StreamServerInterceptor(),
-		grpc_validator.StreamServerInterceptor(),
-		grpc_tracing.StreamServerInterceptor(),
 	}
 	ui := []grpc.UnaryServerInterceptor{
 		grpc_recovery.UnaryServerInterceptor(),
+	}
+	// Calls over their rate limit are rejected before anything else.
+	if len(params.rateLimits) > 0 {
+		si = append(si, rateLimitStreamServerInterceptor(params.rateLimits))
+		ui = append(ui, rateLimitUnaryServerInterceptor(params.rateLimits))
+	}
+	// Unauthenticated callers are rejected before anything about their request is looked at.
+	if len(params.authenticators) > 0 {
+		si = append(si, authStreamServerInterceptor(params.authenticators))
+		ui = append(ui, authUnaryServerInterceptor(params.authenticators))
+	}
+	si = append(si,
+		grpc_validator.StreamServerInterceptor(),
+		grpc_tracing.StreamServerInterceptor(),
+	)
+	ui = append(ui,
 		grpc_validator.UnaryServerInterceptor(),
 		grpc_tracing.UnaryServerInterceptor(),
-	}
+	)
 	if params.enableRPCLogging {
 		grpcLogger := logrus.WithFields(logrus.Fields{
 			"app":       "openmatch",
@@ -332,17 +518,16 @@ func newGRPCServerOptions(params *ServerParams) []grpc.ServerOption {
 		}
 	}
 
+	si = append(si, params.streamInterceptors...)
+	ui = append(ui, params.unaryInterceptors...)
+
 	if params.enableMetrics {
 		opts = append(opts, grpc.StatsHandler(&ocgrpc.ServerHandler{}))
 	}
 
+	opts = append(opts, params.grpcTuning.serverOptions()...)
 	return append(opts,
 		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(si...)),
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(ui...)),
-		grpc.KeepaliveEnforcementPolicy(
-			keepalive.EnforcementPolicy{
-				MinTime:             10 * time.Second,
-				PermitWithoutStream: true,
-			},
-		))
+	)
 }
@@ -15,6 +15,7 @@
 package rpc
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -23,7 +24,13 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	channelzpb "google.golang.org/grpc/channelz/grpc_channelz_v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
 	"open-match.dev/open-match/internal/telemetry"
 	shellTesting "open-match.dev/open-match/internal/testing"
 	utilTesting "open-match.dev/open-match/internal/util/testing"
@@ -58,6 +65,105 @@ func TestStartStopServer(t *testing.T) {
 	runGrpcWithProxyTests(t, assert, s.serverWithProxy, conn, httpClient, endpoint)
 }
 
+func TestDebugServices(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		enabled := enabled
+		t.Run(fmt.Sprintf("enabled=%t", enabled), func(t *testing.T) {
+			grpcLh := MustListen()
+			httpLh := MustListen()
+			params := NewServerParamsFromListeners(grpcLh, httpLh)
+			params.enableReflection = enabled
+			params.enableChannelz = enabled
+			params.AddHandleFunc(func(s *grpc.Server) {
+				pb.RegisterFrontendServiceServer(s, &shellTesting.FakeFrontend{})
+			}, pb.RegisterFrontendServiceHandlerFromEndpoint)
+			s := &Server{}
+			defer s.Stop()
+			waitForStart, err := s.Start(params)
+			require.Nil(t, err)
+			waitForStart()
+
+			conn, err := grpc.Dial(grpcLh.Target(), grpc.WithInsecure())
+			require.Nil(t, err)
+			defer conn.Close()
+			ctx := utilTesting.NewContext(t)
+
+			_, err = channelzpb.NewChannelzClient(conn).GetServers(ctx, &channelzpb.GetServersRequest{})
+			assert.Equal(t, enabled, err == nil, "%v", err)
+
+			stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+			require.Nil(t, err)
+			err = stream.Send(&reflectionpb.ServerReflectionRequest{
+				MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+			})
+			require.Nil(t, err)
+			resp, err := stream.Recv()
+			if !enabled {
+				assert.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			services := []string{}
+			for _, service := range resp.GetListServicesResponse().GetService() {
+				services = append(services, service.GetName())
+			}
+			assert.Contains(t, services, "openmatch.FrontendService")
+		})
+	}
+}
+
+func TestCustomInterceptors(t *testing.T) {
+	assert := assert.New(t)
+	grpcLh := MustListen()
+	httpLh := MustListen()
+	params := NewServerParamsFromListeners(grpcLh, httpLh)
+	params.AddHandleFunc(func(s *grpc.Server) {
+		pb.RegisterFrontendServiceServer(s, &shellTesting.FakeFrontend{})
+		pb.RegisterQueryServiceServer(s, &pb.UnimplementedQueryServiceServer{})
+	}, pb.RegisterFrontendServiceHandlerFromEndpoint)
+
+	calls := make(chan string, 2)
+	params.AddUnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		calls <- info.FullMethod + " " + strings.Join(md.Get("x-proxied"), "")
+		return handler(ctx, req)
+	})
+	params.AddStreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return status.Error(codes.PermissionDenied, "streams are disabled")
+	})
+	params.AddProxyDialOptions(grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(metadata.AppendToOutgoingContext(ctx, "x-proxied", "true"), method, req, reply, cc, opts...)
+	}))
+
+	s := &Server{}
+	defer s.Stop()
+	waitForStart, err := s.Start(params)
+	require.Nil(t, err)
+	waitForStart()
+
+	conn, err := grpc.Dial(grpcLh.Target(), grpc.WithInsecure())
+	require.Nil(t, err)
+	defer conn.Close()
+	client := pb.NewFrontendServiceClient(conn)
+	ctx := utilTesting.NewContext(t)
+
+	_, err = client.CreateTicket(ctx, &pb.CreateTicketRequest{})
+	assert.Nil(err)
+	assert.Equal("/openmatch.FrontendService/CreateTicket ", <-calls)
+
+	stream, err := pb.NewQueryServiceClient(conn).QueryTickets(ctx, &pb.QueryTicketsRequest{})
+	require.Nil(t, err)
+	_, err = stream.Recv()
+	assert.Equal(codes.PermissionDenied, status.Code(err))
+
+	httpClient := &http.Client{Timeout: time.Second}
+	resp, err := httpClient.Post(fmt.Sprintf("http://localhost:%d/v1/frontendservice/tickets", httpLh.Number()), "application/json", strings.NewReader("{}"))
+	require.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal("/openmatch.FrontendService/CreateTicket true", <-calls)
+}
+
 func TestMustServeForever(t *testing.T) {
 	assert := assert.New(t)
 	grpcLh := MustListen()
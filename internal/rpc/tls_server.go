@@ -16,7 +16,6 @@ package rpc
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"sync"
 
@@ -51,9 +50,9 @@ func (s *tlsServer) start(params *ServerParams) (func(), error) {
 	var serverStartWaiter sync.WaitGroup
 
 	s.httpMux = params.ServeMux
-	s.proxyMux = runtime.NewServeMux()
+	s.proxyMux = newProxyMux(params.forwardedHeaders)
 
-	grpcAddress := fmt.Sprintf("localhost:%d", s.grpcLh.Number())
+	grpcAddress := s.grpcLh.Target()
 
 	grpcListener, err := s.grpcLh.Obtain()
 	if err != nil {
@@ -61,28 +60,25 @@ func (s *tlsServer) start(params *ServerParams) (func(), error) {
 	}
 	s.grpcListener = grpcListener
 
-	rootCaCert, err := trustedCertificateFromFileData(params.rootCaPublicCertificateFileData)
-	if err != nil {
-		return func() {}, errors.WithStack(err)
-	}
 	certPoolForGrpcEndpoint, err := trustedCertificateFromFileData(params.publicCertificateFileData)
 	if err != nil {
 		return func() {}, errors.WithStack(err)
 	}
+	// Trusting the root CA as well lets the proxy verify rotated certificates.
+	certPoolForGrpcEndpoint.AppendCertsFromPEM(params.rootCaPublicCertificateFileData)
 
-	grpcTLSCertificate, err := certificateFromFileData(params.publicCertificateFileData, params.privateKeyFileData)
+	getCertificate, getRootCAs, err := params.tlsSources()
 	if err != nil {
 		return func() {}, errors.WithStack(err)
 	}
-	creds := credentials.NewServerTLSFromCert(grpcTLSCertificate)
+	tlsConfig := params.serverTLSConfig(getCertificate, getRootCAs)
+	creds := credentials.NewTLS(tlsConfig)
 	serverOpts := newGRPCServerOptions(params)
 	serverOpts = append(serverOpts, grpc.Creds(creds))
 	s.grpcServer = grpc.NewServer(serverOpts...)
 
 	// Bind gRPC handlers
-	for _, handlerFunc := range params.handlersForGrpc {
-		handlerFunc(s.grpcServer)
-	}
+	bindGRPCHandlers(s.grpcServer, params)
 
 	serverStartWaiter.Add(1)
 	go func() {
@@ -103,8 +99,16 @@ func (s *tlsServer) start(params *ServerParams) (func(), error) {
 	// Bind gRPC handlers
 	ctx, cancel := context.WithCancel(context.Background())
 
-	httpsToGrpcProxyOptions := newGRPCDialOptions(params.enableMetrics, params.enableRPCLogging, params.enableRPCPayloadLogging)
-	httpsToGrpcProxyOptions = append(httpsToGrpcProxyOptions, grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(certPoolForGrpcEndpoint, "")))
+	httpsToGrpcProxyOptions := newGRPCDialOptions(params.grpcTuning, RetryPolicy{}, params.enableMetrics, params.enableRPCLogging, params.enableRPCPayloadLogging)
+	// The proxy presents the server's own certificate when mutual TLS is required.
+	httpsToGrpcProxyOptions = append(httpsToGrpcProxyOptions, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+		RootCAs: certPoolForGrpcEndpoint,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return getCertificate()
+		},
+	})))
+	httpsToGrpcProxyOptions = append(httpsToGrpcProxyOptions, s.grpcLh.DialOptions()...)
+	httpsToGrpcProxyOptions = append(httpsToGrpcProxyOptions, params.proxyDialOptions...)
 
 	for _, handlerFunc := range params.handlersForGrpcProxy {
 		if err = handlerFunc(ctx, s.proxyMux, grpcAddress, httpsToGrpcProxyOptions); err != nil {
@@ -115,17 +119,11 @@ func (s *tlsServer) start(params *ServerParams) (func(), error) {
 
 	// Bind HTTPS handlers
 	s.httpMux.Handle(telemetry.HealthCheckEndpoint, telemetry.NewHealthCheck(params.handlersForHealthCheck))
-	s.httpMux.Handle("/", s.proxyMux)
+	s.httpMux.Handle("/", corsHTTPHandler(compressHTTPHandler(s.proxyMux, params.compression), params.cors))
 	s.httpServer = &http.Server{
-		Addr:    s.httpListener.Addr().String(),
-		Handler: instrumentHTTPHandler(s.httpMux, params),
-		TLSConfig: &tls.Config{
-			Certificates: []tls.Certificate{*grpcTLSCertificate},
-			ClientCAs:    rootCaCert,
-			// Commented as open-match does not support mutual authentication yet
-			// ClientAuth:   tls.RequireAndVerifyClientCert,
-			NextProtos: []string{http2WithTLSVersionID}, // https://github.com/grpc-ecosystem/grpc-gateway/issues/220
-		},
+		Addr:      s.httpListener.Addr().String(),
+		Handler:   instrumentHTTPHandler(s.httpMux, params),
+		TLSConfig: tlsConfig,
 	}
 	serverStartWaiter.Add(1)
 	go func() {
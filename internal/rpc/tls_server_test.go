@@ -25,6 +25,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	shellTesting "open-match.dev/open-match/internal/testing"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
 	"open-match.dev/open-match/pkg/pb"
 	certgenTesting "open-match.dev/open-match/tools/certgen/testing"
 )
@@ -77,6 +78,34 @@ func TestStartStopTlsServerWithCARootedCertificate(t *testing.T) {
 	})
 }
 
+// TestStartStopTlsServerWithClientCertificates verifies that a server requiring client certificates only accepts
+// clients presenting one signed by the root CA, including its own HTTP proxy.
+func TestStartStopTlsServerWithClientCertificates(t *testing.T) {
+	assert := assert.New(t)
+	grpcLh := MustListen()
+	proxyLh := MustListen()
+	grpcAddress := fmt.Sprintf("localhost:%d", grpcLh.Number())
+	proxyAddress := fmt.Sprintf("localhost:%d", proxyLh.Number())
+	allHostnames := []string{grpcAddress, proxyAddress}
+	rootPub, rootPriv, err := certgenTesting.CreateRootCertificateAndPrivateKeyForTesting(allHostnames)
+	assert.Nil(err)
+
+	pub, priv, err := certgenTesting.CreateDerivedCertificateAndPrivateKeyForTesting(rootPub, rootPriv, allHostnames)
+	assert.Nil(err)
+
+	runTestStartStopTLSServer(t, &tlsServerTestParams{
+		rootPublicCertificateFileData: rootPub,
+		rootPrivateKeyFileData:        rootPriv,
+		publicCertificateFileData:     pub,
+		privateKeyFileData:            priv,
+		grpcLh:                        grpcLh,
+		proxyLh:                       proxyLh,
+		grpcAddress:                   grpcAddress,
+		proxyAddress:                  proxyAddress,
+		requireClientCertificate:      true,
+	})
+}
+
 type tlsServerTestParams struct {
 	rootPublicCertificateFileData []byte
 	rootPrivateKeyFileData        []byte
@@ -86,6 +115,7 @@ type tlsServerTestParams struct {
 	proxyLh                       *ListenerHolder
 	grpcAddress                   string
 	proxyAddress                  string
+	requireClientCertificate      bool
 }
 
 func runTestStartStopTLSServer(t *testing.T, tp *tlsServerTestParams) {
@@ -99,6 +129,9 @@ func runTestStartStopTLSServer(t *testing.T, tp *tlsServerTestParams) {
 	}, pb.RegisterFrontendServiceHandlerFromEndpoint)
 
 	serverParams.SetTLSConfiguration(tp.rootPublicCertificateFileData, tp.publicCertificateFileData, tp.privateKeyFileData)
+	if tp.requireClientCertificate {
+		serverParams.RequireClientCertificate()
+	}
 	s := newTLSServer(serverParams.grpcListener, serverParams.grpcProxyListener)
 	defer s.stop()
 
@@ -109,10 +142,22 @@ func runTestStartStopTLSServer(t *testing.T, tp *tlsServerTestParams) {
 	pool, err := trustedCertificateFromFileData(tp.rootPublicCertificateFileData)
 	assert.Nil(err)
 
-	conn, err := grpc.Dial(tp.grpcAddress, grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(pool, tp.grpcAddress)))
+	tlsCert, err := certificateFromFileData(tp.publicCertificateFileData, tp.privateKeyFileData)
 	assert.Nil(err)
 
-	tlsCert, err := certificateFromFileData(tp.publicCertificateFileData, tp.privateKeyFileData)
+	if tp.requireClientCertificate {
+		conn, err := grpc.Dial(tp.grpcAddress, grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(pool, tp.grpcAddress)))
+		assert.Nil(err)
+		_, err = pb.NewFrontendServiceClient(conn).CreateTicket(utilTesting.NewContext(t), &pb.CreateTicketRequest{})
+		assert.NotNil(err, "client without a certificate was accepted")
+		conn.Close()
+	}
+
+	conn, err := grpc.Dial(tp.grpcAddress, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+		ServerName:   tp.grpcAddress,
+		RootCAs:      pool,
+		Certificates: []tls.Certificate{*tlsCert},
+	})))
 	assert.Nil(err)
 	tlsTransport := &http.Transport{
 		TLSClientConfig: &tls.Config{
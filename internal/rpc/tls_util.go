@@ -16,6 +16,10 @@ package rpc
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
 
 	"crypto/tls"
 	"crypto/x509"
@@ -42,3 +46,160 @@ func certificateFromFileData(publicCertFileData []byte, privateKeyFileData []byt
 	}
 	return &cert, nil
 }
+
+// fileVersions detects changes to files by their latest modification time.
+type fileVersions struct {
+	files   []string
+	modTime time.Time
+}
+
+// changed returns true if any of the files was modified since the last call
+// which returned true, or if it's the first call.  Files which can't be read
+// are treated as unchanged, so that the previous contents are kept.
+func (v *fileVersions) changed() bool {
+	var latest time.Time
+	for _, f := range v.files {
+		info, err := os.Stat(f)
+		if err != nil {
+			return v.modTime.IsZero()
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	if !v.modTime.IsZero() && latest.Equal(v.modTime) {
+		return false
+	}
+	v.modTime = latest
+	return true
+}
+
+// certificateReloader reads a certificate and its private key from files,
+// reading them again whenever either file changes, so that a rotated
+// certificate is used without restarting.
+type certificateReloader struct {
+	m        sync.Mutex
+	versions *fileVersions
+	certFile string
+	keyFile  string
+	cert     *tls.Certificate
+}
+
+func newCertificateReloader(certFile, keyFile string) (*certificateReloader, error) {
+	r := &certificateReloader{
+		versions: &fileVersions{files: []string{certFile, keyFile}},
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+	if _, err := r.get(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// get returns the certificate, reloading it if its files changed.  If
+// reloading fails, the previous certificate is kept.
+func (r *certificateReloader) get() (*tls.Certificate, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if !r.versions.changed() && r.cert != nil {
+		return r.cert, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		if r.cert == nil {
+			return nil, errors.WithStack(fmt.Errorf("cannot load TLS certificate %s and private key %s, %s", r.certFile, r.keyFile, err))
+		}
+		serverLogger.WithError(err).Warningf("failed to reload TLS certificate %s, using the previous one", r.certFile)
+		return r.cert, nil
+	}
+	if r.cert != nil {
+		serverLogger.Infof("Reloaded TLS certificate %s", r.certFile)
+	}
+	r.cert = &cert
+	return r.cert, nil
+}
+
+// verifyServerCertificate returns a tls.Config.VerifyPeerCertificate checking
+// the server's certificate chain, and that it's valid for serverName, against
+// the trusted certificates getRootCAs returns on each handshake.  It takes the
+// place of the verification turned off by InsecureSkipVerify, which only uses a
+// fixed RootCAs pool.
+func verifyServerCertificate(serverName string, getRootCAs func() (*x509.CertPool, error)) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("tls: server didn't present a certificate")
+		}
+		roots, err := getRootCAs()
+		if err != nil {
+			return err
+		}
+
+		opts := x509.VerifyOptions{
+			Roots:         roots,
+			DNSName:       serverName,
+			Intermediates: x509.NewCertPool(),
+		}
+		certs := make([]*x509.Certificate, len(rawCerts))
+		for i, raw := range rawCerts {
+			certs[i], err = x509.ParseCertificate(raw)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if i > 0 {
+				opts.Intermediates.AddCert(certs[i])
+			}
+		}
+		_, err = certs[0].Verify(opts)
+		return err
+	}
+}
+
+// certPoolReloader reads trusted certificates from a file, reading them again
+// whenever the file changes.
+type certPoolReloader struct {
+	m        sync.Mutex
+	versions *fileVersions
+	file     string
+	pool     *x509.CertPool
+}
+
+func newCertPoolReloader(file string) (*certPoolReloader, error) {
+	r := &certPoolReloader{
+		versions: &fileVersions{files: []string{file}},
+		file:     file,
+	}
+	if _, err := r.get(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// get returns the trusted certificates, reloading them if their file changed.
+// If reloading fails, the previous certificates are kept.
+func (r *certPoolReloader) get() (*x509.CertPool, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if !r.versions.changed() && r.pool != nil {
+		return r.pool, nil
+	}
+
+	data, err := ioutil.ReadFile(r.file)
+	var pool *x509.CertPool
+	if err == nil {
+		pool, err = trustedCertificateFromFileData(data)
+	}
+	if err != nil {
+		if r.pool == nil {
+			return nil, errors.WithStack(fmt.Errorf("cannot load TLS root certificate %s, %s", r.file, err))
+		}
+		serverLogger.WithError(err).Warningf("failed to reload TLS root certificate %s, using the previous one", r.file)
+		return r.pool, nil
+	}
+	if r.pool != nil {
+		serverLogger.Infof("Reloaded TLS root certificate %s", r.file)
+	}
+	r.pool = pool
+	return r.pool, nil
+}
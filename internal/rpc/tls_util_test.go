@@ -0,0 +1,124 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	certgenTesting "open-match.dev/open-match/tools/certgen/testing"
+)
+
+func TestCertificateReloader(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := ioutil.TempDir("", "certs")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+
+	write := func(modTime time.Time) []byte {
+		pub, priv, err := certgenTesting.CreateCertificateAndPrivateKeyForTesting([]string{"localhost"})
+		require.Nil(t, err)
+		require.Nil(t, ioutil.WriteFile(certFile, pub, 0600))
+		require.Nil(t, ioutil.WriteFile(keyFile, priv, 0600))
+		require.Nil(t, os.Chtimes(certFile, modTime, modTime))
+		require.Nil(t, os.Chtimes(keyFile, modTime, modTime))
+		return pub
+	}
+	leaf := func(r *certificateReloader) []byte {
+		cert, err := r.get()
+		assert.Nil(err)
+		return cert.Certificate[0]
+	}
+
+	_, err = newCertificateReloader(certFile, keyFile)
+	assert.NotNil(err)
+
+	write(time.Unix(1000, 0))
+	r, err := newCertificateReloader(certFile, keyFile)
+	require.Nil(t, err)
+	first := leaf(r)
+	assert.Equal(first, leaf(r))
+
+	// A rotated certificate is picked up.
+	write(time.Unix(2000, 0))
+	second := leaf(r)
+	assert.NotEqual(first, second)
+
+	// A broken rotation keeps the previous certificate.
+	require.Nil(t, ioutil.WriteFile(keyFile, []byte("broken"), 0600))
+	require.Nil(t, os.Chtimes(keyFile, time.Unix(3000, 0), time.Unix(3000, 0)))
+	assert.Equal(second, leaf(r))
+}
+
+func TestCertPoolReloader(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := ioutil.TempDir("", "certs")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "root.pem")
+
+	pub, _, err := certgenTesting.CreateRootCertificateAndPrivateKeyForTesting([]string{"localhost"})
+	require.Nil(t, err)
+	require.Nil(t, ioutil.WriteFile(file, pub, 0600))
+	r, err := newCertPoolReloader(file)
+	require.Nil(t, err)
+	first, err := r.get()
+	assert.Nil(err)
+	assert.Len(first.Subjects(), 1)
+
+	require.Nil(t, ioutil.WriteFile(file, []byte("broken"), 0600))
+	require.Nil(t, os.Chtimes(file, time.Unix(3000, 0), time.Unix(3000, 0)))
+	pool, err := r.get()
+	assert.Nil(err)
+	assert.Equal(first, pool)
+}
+
+func TestVerifyServerCertificate(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := ioutil.TempDir("", "certs")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "root.pem")
+
+	oldRoot, _, err := certgenTesting.CreateRootCertificateAndPrivateKeyForTesting([]string{"localhost"})
+	require.Nil(t, err)
+	newRoot, newRootKey, err := certgenTesting.CreateRootCertificateAndPrivateKeyForTesting([]string{"localhost"})
+	require.Nil(t, err)
+	pub, _, err := certgenTesting.CreateDerivedCertificateAndPrivateKeyForTesting(newRoot, newRootKey, []string{"localhost"})
+	require.Nil(t, err)
+	block, _ := pem.Decode(pub)
+	require.NotNil(t, block)
+
+	require.Nil(t, ioutil.WriteFile(file, oldRoot, 0600))
+	require.Nil(t, os.Chtimes(file, time.Unix(1000, 0), time.Unix(1000, 0)))
+	roots, err := newCertPoolReloader(file)
+	require.Nil(t, err)
+	verify := verifyServerCertificate("localhost", roots.get)
+	assert.NotNil(verify([][]byte{block.Bytes}, nil))
+	assert.NotNil(verify(nil, nil))
+
+	// The server is trusted once the rotated root CA is written.
+	require.Nil(t, ioutil.WriteFile(file, newRoot, 0600))
+	require.Nil(t, os.Chtimes(file, time.Unix(2000, 0), time.Unix(2000, 0)))
+	assert.Nil(verify([][]byte{block.Bytes}, nil))
+	assert.NotNil(verifyServerCertificate("example.com", roots.get)([][]byte{block.Bytes}, nil))
+}
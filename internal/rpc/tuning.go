@@ -0,0 +1,127 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+	"open-match.dev/open-match/internal/config"
+)
+
+const (
+	configNameKeepaliveTime          = "api.grpc.keepalive.time"
+	configNameKeepaliveTimeout       = "api.grpc.keepalive.timeout"
+	configNameKeepaliveServerTime    = "api.grpc.keepalive.serverTime"
+	configNameKeepaliveServerTimeout = "api.grpc.keepalive.serverTimeout"
+	configNameKeepaliveMinTime       = "api.grpc.keepalive.minTime"
+	configNameMaxSendMessageSize     = "api.grpc.maxSendMessageSize"
+	configNameMaxRecvMessageSize     = "api.grpc.maxRecvMessageSize"
+
+	defaultKeepaliveTime          = 20 * time.Second
+	defaultKeepaliveTimeout       = 10 * time.Second
+	defaultKeepaliveServerTime    = 30 * time.Second
+	defaultKeepaliveServerTimeout = 10 * time.Second
+	defaultKeepaliveMinTime       = 10 * time.Second
+	// defaultMaxRecvMessageSize is gRPC's own default.
+	defaultMaxRecvMessageSize = 4 * 1024 * 1024
+	defaultMaxSendMessageSize = 1<<31 - 1
+)
+
+// GRPCTuning holds the keepalive and message size settings of gRPC servers
+// and clients.  Zero fields take the defaults.
+type GRPCTuning struct {
+	// KeepaliveTime is how long clients wait on an idle connection before
+	// pinging the server, and KeepaliveTimeout how long they wait for the ack.
+	KeepaliveTime    time.Duration
+	KeepaliveTimeout time.Duration
+	// KeepaliveServerTime and KeepaliveServerTimeout are the same for servers
+	// pinging clients, which keeps idle streams like WatchAssignments from
+	// being cut by load balancers.
+	KeepaliveServerTime    time.Duration
+	KeepaliveServerTimeout time.Duration
+	// KeepaliveMinTime is the shortest ping interval servers allow clients.
+	KeepaliveMinTime time.Duration
+	// MaxSendMessageSize and MaxRecvMessageSize bound the size in bytes of
+	// single messages, e.g. profiles and tickets with large extensions.
+	MaxSendMessageSize int
+	MaxRecvMessageSize int
+}
+
+// grpcTuningFromConfig reads the api.grpc config shared by servers and clients.
+func grpcTuningFromConfig(cfg config.View) GRPCTuning {
+	return GRPCTuning{
+		KeepaliveTime:          cfg.GetDuration(configNameKeepaliveTime),
+		KeepaliveTimeout:       cfg.GetDuration(configNameKeepaliveTimeout),
+		KeepaliveServerTime:    cfg.GetDuration(configNameKeepaliveServerTime),
+		KeepaliveServerTimeout: cfg.GetDuration(configNameKeepaliveServerTimeout),
+		KeepaliveMinTime:       cfg.GetDuration(configNameKeepaliveMinTime),
+		MaxSendMessageSize:     cfg.GetInt(configNameMaxSendMessageSize),
+		MaxRecvMessageSize:     cfg.GetInt(configNameMaxRecvMessageSize),
+	}
+}
+
+// withDefaults returns the tuning with its zero fields set to the defaults.
+func (t GRPCTuning) withDefaults() GRPCTuning {
+	orDefault := func(d *time.Duration, def time.Duration) {
+		if *d <= 0 {
+			*d = def
+		}
+	}
+	orDefault(&t.KeepaliveTime, defaultKeepaliveTime)
+	orDefault(&t.KeepaliveTimeout, defaultKeepaliveTimeout)
+	orDefault(&t.KeepaliveServerTime, defaultKeepaliveServerTime)
+	orDefault(&t.KeepaliveServerTimeout, defaultKeepaliveServerTimeout)
+	orDefault(&t.KeepaliveMinTime, defaultKeepaliveMinTime)
+	if t.MaxSendMessageSize <= 0 {
+		t.MaxSendMessageSize = defaultMaxSendMessageSize
+	}
+	if t.MaxRecvMessageSize <= 0 {
+		t.MaxRecvMessageSize = defaultMaxRecvMessageSize
+	}
+	return t
+}
+
+func (t GRPCTuning) dialOptions() []grpc.DialOption {
+	t = t.withDefaults()
+	return []grpc.DialOption{
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                t.KeepaliveTime,
+			Timeout:             t.KeepaliveTimeout,
+			PermitWithoutStream: true,
+		}),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallSendMsgSize(t.MaxSendMessageSize),
+			grpc.MaxCallRecvMsgSize(t.MaxRecvMessageSize),
+		),
+	}
+}
+
+func (t GRPCTuning) serverOptions() []grpc.ServerOption {
+	t = t.withDefaults()
+	return []grpc.ServerOption{
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             t.KeepaliveMinTime,
+			PermitWithoutStream: true,
+		}),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    t.KeepaliveServerTime,
+			Timeout: t.KeepaliveServerTimeout,
+		}),
+		grpc.MaxSendMsgSize(t.MaxSendMessageSize),
+		grpc.MaxRecvMsgSize(t.MaxRecvMessageSize),
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	shellTesting "open-match.dev/open-match/internal/testing"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestGRPCTuningFromConfig(t *testing.T) {
+	assert := assert.New(t)
+	cfg := viper.New()
+	assert.Equal(GRPCTuning{
+		KeepaliveTime:          defaultKeepaliveTime,
+		KeepaliveTimeout:       defaultKeepaliveTimeout,
+		KeepaliveServerTime:    defaultKeepaliveServerTime,
+		KeepaliveServerTimeout: defaultKeepaliveServerTimeout,
+		KeepaliveMinTime:       defaultKeepaliveMinTime,
+		MaxSendMessageSize:     defaultMaxSendMessageSize,
+		MaxRecvMessageSize:     defaultMaxRecvMessageSize,
+	}, grpcTuningFromConfig(cfg).withDefaults())
+
+	cfg.Set("api.grpc.keepalive.time", "1m")
+	cfg.Set("api.grpc.keepalive.serverTime", "15s")
+	cfg.Set("api.grpc.maxRecvMessageSize", 16*1024*1024)
+	tuning := grpcTuningFromConfig(cfg).withDefaults()
+	assert.Equal(time.Minute, tuning.KeepaliveTime)
+	assert.Equal(defaultKeepaliveTimeout, tuning.KeepaliveTimeout)
+	assert.Equal(15*time.Second, tuning.KeepaliveServerTime)
+	assert.Equal(16*1024*1024, tuning.MaxRecvMessageSize)
+
+	params, err := clientParamsFromEndpoint(cfg, "om-frontend:50504")
+	assert.Nil(err)
+	assert.Equal(16*1024*1024, params.GRPC.MaxRecvMessageSize)
+}
+
+func TestGRPCMaxMessageSize(t *testing.T) {
+	grpcLh := MustListen()
+	httpLh := MustListen()
+	params := NewServerParamsFromListeners(grpcLh, httpLh)
+	params.grpcTuning = GRPCTuning{MaxRecvMessageSize: 64 * 1024}
+	params.AddHandleFunc(func(s *grpc.Server) {
+		pb.RegisterFrontendServiceServer(s, &shellTesting.FakeFrontend{})
+	}, pb.RegisterFrontendServiceHandlerFromEndpoint)
+	s := newInsecureServer(grpcLh, httpLh)
+	defer s.stop()
+	waitForStart, err := s.start(params)
+	assert.Nil(t, err)
+	waitForStart()
+
+	ticket := func(size int) *pb.CreateTicketRequest {
+		ext, err := ptypes.MarshalAny(&wrappers.StringValue{Value: strings.Repeat("x", size)})
+		assert.Nil(t, err)
+		return &pb.CreateTicketRequest{Ticket: &pb.Ticket{Extensions: map[string]*any.Any{"big": ext}}}
+	}
+
+	tests := []struct {
+		name       string
+		clientSend int
+		size       int
+		code       codes.Code
+	}{
+		{"within limits", 0, 32 * 1024, codes.OK},
+		{"over server limit", 0, 128 * 1024, codes.ResourceExhausted},
+		{"over client limit", 16 * 1024, 32 * 1024, codes.ResourceExhausted},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			conn, err := GRPCClientFromParams(&ClientParams{
+				Address: fmt.Sprintf("localhost:%d", grpcLh.Number()),
+				GRPC:    GRPCTuning{MaxSendMessageSize: tt.clientSend},
+			})
+			assert.Nil(t, err)
+			defer conn.Close()
+			_, err = pb.NewFrontendServiceClient(conn).CreateTicket(utilTesting.NewContext(t), ticket(tt.size))
+			assert.Equal(t, tt.code, status.Code(err), "%v", err)
+		})
+	}
+}
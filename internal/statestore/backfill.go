@@ -0,0 +1,324 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/gomodule/redigo/redis"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/ipb"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// Backfills are stored under their own keys as an ipb.BackfillInternal, along
+// with the ids of their tickets.  Indexed backfills are kept in a hash of ids
+// to generations, and the last acknowledgment of each backfill in a sorted
+// set, so that those whose game server stopped acknowledging them are found
+// without scanning.  Backfills are only kept in the prefixed keyspace.
+
+// createBackfillScript saves a new backfill, marking it as acknowledged,
+// returning 0 if it already exists.  KEYS are the backfill and the last
+// acknowledgments, ARGV are the marshalled backfill, the current time and the
+// backfill id.
+var createBackfillScript = redis.NewScript(2, `
+if redis.call("EXISTS", KEYS[1]) == 1 then
+  return 0
+end
+redis.call("SET", KEYS[1], ARGV[1])
+redis.call("ZADD", KEYS[2], ARGV[2], ARGV[3])
+return 1
+`)
+
+// acknowledgeBackfillScript records an acknowledgment of an existing
+// backfill, returning 0 if it doesn't exist.  KEYS are the backfill and the
+// last acknowledgments, ARGV are the current time and the backfill id.
+var acknowledgeBackfillScript = redis.NewScript(2, `
+if redis.call("EXISTS", KEYS[1]) == 0 then
+  return 0
+end
+redis.call("ZADD", KEYS[2], ARGV[1], ARGV[2])
+return 1
+`)
+
+// deleteExpiredBackfillsScript deletes the backfills which weren't
+// acknowledged since the given time, returning how many were deleted.  KEYS
+// are the indexed backfills and the last acknowledgments, ARGV are the time
+// before which backfills have expired and the prefix of backfill keys.
+var deleteExpiredBackfillsScript = redis.NewScript(2, `
+local ids = redis.call("ZRANGEBYSCORE", KEYS[2], "-inf", "(" .. ARGV[1])
+for _, id in ipairs(ids) do
+  redis.call("DEL", ARGV[2] .. id)
+  redis.call("HDEL", KEYS[1], id)
+  redis.call("ZREM", KEYS[2], id)
+end
+return #ids
+`)
+
+// CreateBackfill creates a new Backfill in state storage, along with the ids of the Tickets already matched to it.
+// The Backfill counts as acknowledged on creation.  Fails with AlreadyExists if the id exists.
+func (rb *redisBackend) CreateBackfill(ctx context.Context, backfill *pb.Backfill, ticketIDs []string) error {
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	value, err := marshalBackfill(backfill, ticketIDs)
+	if err != nil {
+		return err
+	}
+
+	created, err := redis.Bool(createBackfillScript.Do(redisConn, rb.keys.backfill(backfill.GetId()), rb.keys.backfillLastAck(), value, rb.clk.Now().UnixNano(), backfill.GetId()))
+	if err != nil {
+		redisLogger.WithError(err).Error("failed to execute create backfill script")
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	if !created {
+		return status.Errorf(codes.AlreadyExists, "Backfill id:%s already exists", backfill.GetId())
+	}
+	return nil
+}
+
+// GetBackfill gets the Backfill with the specified id, and the ids of its Tickets.  Fails with NotFound if it doesn't
+// exist.
+func (rb *redisBackend) GetBackfill(ctx context.Context, id string) (*pb.Backfill, []string, error) {
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	value, err := redis.Bytes(redisConn.Do("GET", rb.keys.backfill(id)))
+	if err == redis.ErrNil {
+		return nil, nil, status.Errorf(codes.NotFound, "Backfill id:%s not found", id)
+	}
+	if err != nil {
+		redisLogger.WithFields(logrus.Fields{
+			"cmd":   "GET",
+			"key":   id,
+			"error": err.Error(),
+		}).Error("failed to get the backfill from state storage")
+		return nil, nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	internal := &ipb.BackfillInternal{}
+	err = proto.Unmarshal(value, internal)
+	if err != nil {
+		redisLogger.WithFields(logrus.Fields{
+			"key":   id,
+			"error": err.Error(),
+		}).Error("failed to unmarshal the backfill proto")
+		return nil, nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	return internal.GetBackfill(), internal.GetTicketIds(), nil
+}
+
+// UpdateBackfill overwrites the Backfill and the ids of its Tickets.  The generation is saved as given, callers are
+// expected to increment it.  Fails with NotFound if the Backfill doesn't exist.
+func (rb *redisBackend) UpdateBackfill(ctx context.Context, backfill *pb.Backfill, ticketIDs []string) error {
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	value, err := marshalBackfill(backfill, ticketIDs)
+	if err != nil {
+		return err
+	}
+
+	reply, err := redisConn.Do("SET", rb.keys.backfill(backfill.GetId()), value, "XX")
+	if err != nil {
+		redisLogger.WithFields(logrus.Fields{
+			"cmd":   "SET",
+			"key":   backfill.GetId(),
+			"error": err.Error(),
+		}).Error("failed to update the backfill")
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	if reply == nil {
+		return status.Errorf(codes.NotFound, "Backfill id:%s not found", backfill.GetId())
+	}
+	return nil
+}
+
+// DeleteBackfill removes the Backfill, its index entry and its acknowledgments.  Succeeds if it doesn't exist.
+func (rb *redisBackend) DeleteBackfill(ctx context.Context, id string) error {
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	err = redisConn.Send("MULTI")
+	if err == nil {
+		err = redisConn.Send("DEL", rb.keys.backfill(id))
+	}
+	if err == nil {
+		err = redisConn.Send("HDEL", rb.keys.allBackfills(), id)
+	}
+	if err == nil {
+		err = redisConn.Send("ZREM", rb.keys.backfillLastAck(), id)
+	}
+	if err != nil {
+		redisLogger.WithError(err).Error("failed to pipeline commands for DeleteBackfill")
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	if _, err = redisConn.Do("EXEC"); err != nil {
+		redisLogger.WithFields(logrus.Fields{
+			"key":   id,
+			"error": err.Error(),
+		}).Error("failed to delete the backfill")
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	return nil
+}
+
+// AcknowledgeBackfill records that the game server of the Backfill still wants it filled.  Backfills which aren't
+// acknowledged within storage.backfillAckTimeout are returned by GetExpiredBackfillIDs.  Fails with NotFound if the
+// Backfill doesn't exist.
+func (rb *redisBackend) AcknowledgeBackfill(ctx context.Context, id string) error {
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	acknowledged, err := redis.Bool(acknowledgeBackfillScript.Do(redisConn, rb.keys.backfill(id), rb.keys.backfillLastAck(), rb.clk.Now().UnixNano(), id))
+	if err != nil {
+		redisLogger.WithError(err).Error("failed to execute acknowledge backfill script")
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	if !acknowledged {
+		return status.Errorf(codes.NotFound, "Backfill id:%s not found", id)
+	}
+	return nil
+}
+
+// deleteExpiredBackfills deletes the backfills which weren't acknowledged
+// within storage.backfillAckTimeout.
+func (rb *redisBackend) deleteExpiredBackfills(redisConn redis.Conn, gc *GarbageCollection) error {
+	expiredBefore := rb.clk.Now().Add(-rb.backfillAckTimeout()).UnixNano()
+	deleted, err := redis.Int64(deleteExpiredBackfillsScript.Do(redisConn, rb.keys.allBackfills(), rb.keys.backfillLastAck(), expiredBefore, rb.keys.backfill("")))
+	if err != nil {
+		redisLogger.WithError(err).Error("failed to delete expired backfills")
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	gc.ExpiredBackfills += deleted
+	return nil
+}
+
+// GetExpiredBackfillIDs returns the ids of the Backfills which weren't acknowledged within storage.backfillAckTimeout.
+func (rb *redisBackend) GetExpiredBackfillIDs(ctx context.Context) ([]string, error) {
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	expiredBefore := rb.clk.Now().Add(-rb.backfillAckTimeout()).UnixNano()
+	ids, err := redis.Strings(redisConn.Do("ZRANGEBYSCORE", rb.keys.backfillLastAck(), "-inf", fmt.Sprintf("(%d", expiredBefore)))
+	if err != nil {
+		redisLogger.WithError(err).Error("failed to get expired backfills")
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	return ids, nil
+}
+
+// IndexBackfill makes the Backfill available to be filled, recording its generation.
+func (rb *redisBackend) IndexBackfill(ctx context.Context, backfill *pb.Backfill) error {
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	_, err = redisConn.Do("HSET", rb.keys.allBackfills(), backfill.GetId(), backfill.GetGeneration())
+	if err != nil {
+		redisLogger.WithFields(logrus.Fields{
+			"cmd":   "HSET",
+			"key":   backfill.GetId(),
+			"error": err.Error(),
+		}).Error("failed to index the backfill")
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	return nil
+}
+
+// DeindexBackfill removes the Backfill from the index.  The Backfill continues to exist.
+func (rb *redisBackend) DeindexBackfill(ctx context.Context, id string) error {
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	_, err = redisConn.Do("HDEL", rb.keys.allBackfills(), id)
+	if err != nil {
+		redisLogger.WithFields(logrus.Fields{
+			"cmd":   "HDEL",
+			"key":   id,
+			"error": err.Error(),
+		}).Error("failed to deindex the backfill")
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	return nil
+}
+
+// GetIndexedBackfills returns the ids of the indexed Backfills, mapped to the generation they were indexed with.
+func (rb *redisBackend) GetIndexedBackfills(ctx context.Context) (map[string]int64, error) {
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	backfills, err := redis.Int64Map(redisConn.Do("HGETALL", rb.keys.allBackfills()))
+	if err != nil {
+		redisLogger.WithError(err).Error("failed to get the indexed backfills")
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	return backfills, nil
+}
+
+func marshalBackfill(backfill *pb.Backfill, ticketIDs []string) ([]byte, error) {
+	value, err := proto.Marshal(&ipb.BackfillInternal{Backfill: backfill, TicketIds: ticketIDs})
+	if err != nil {
+		redisLogger.WithFields(logrus.Fields{
+			"key":   backfill.GetId(),
+			"error": err.Error(),
+		}).Error("failed to marshal the backfill proto")
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	return value, nil
+}
+
+func (rb *redisBackend) backfillAckTimeout() time.Duration {
+	const (
+		name           = "storage.backfillAckTimeout"
+		defaultTimeout = time.Minute
+	)
+
+	if !rb.cfg.IsSet(name) {
+		return defaultTimeout
+	}
+	return rb.cfg.GetDuration(name)
+}
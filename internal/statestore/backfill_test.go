@@ -0,0 +1,127 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/clock"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestBackfillLifecycle(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	service := New(cfg)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	backfill := &pb.Backfill{
+		Id:           "1",
+		SearchFields: &pb.SearchFields{Tags: []string{"beta"}},
+		Generation:   1,
+	}
+	assert.Nil(service.CreateBackfill(ctx, backfill, []string{"a", "b"}))
+	err := service.CreateBackfill(ctx, backfill, nil)
+	assert.Equal(codes.AlreadyExists, status.Code(err))
+
+	got, ticketIDs, err := service.GetBackfill(ctx, "1")
+	assert.Nil(err)
+	assert.True(proto.Equal(backfill, got))
+	assert.Equal([]string{"a", "b"}, ticketIDs)
+
+	backfill.Generation = 2
+	assert.Nil(service.UpdateBackfill(ctx, backfill, []string{"a"}))
+	got, ticketIDs, err = service.GetBackfill(ctx, "1")
+	assert.Nil(err)
+	assert.Equal(int64(2), got.GetGeneration())
+	assert.Equal([]string{"a"}, ticketIDs)
+	err = service.UpdateBackfill(ctx, &pb.Backfill{Id: "missing"}, nil)
+	assert.Equal(codes.NotFound, status.Code(err))
+
+	assert.Nil(service.IndexBackfill(ctx, backfill))
+	indexed, err := service.GetIndexedBackfills(ctx)
+	assert.Nil(err)
+	assert.Equal(map[string]int64{"1": 2}, indexed)
+	assert.Nil(service.DeindexBackfill(ctx, "1"))
+	indexed, err = service.GetIndexedBackfills(ctx)
+	assert.Nil(err)
+	assert.Empty(indexed)
+
+	// Backfills aren't tickets.
+	usage, err := service.GetStorageUsage(ctx, 10)
+	assert.Nil(err)
+	assert.Equal(int64(0), usage.GetTicketCount())
+
+	assert.Nil(service.IndexBackfill(ctx, backfill))
+	assert.Nil(service.DeleteBackfill(ctx, "1"))
+	_, _, err = service.GetBackfill(ctx, "1")
+	assert.Equal(codes.NotFound, status.Code(err))
+	indexed, err = service.GetIndexedBackfills(ctx)
+	assert.Nil(err)
+	assert.Empty(indexed)
+	assert.Nil(service.DeleteBackfill(ctx, "1"))
+}
+
+func TestExpiredBackfills(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	cfg.Set("storage.backfillAckTimeout", "10s")
+	clk := clock.NewVirtual(time.Unix(0, 0))
+	service := NewWithClock(cfg, clk)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	for _, id := range []string{"acknowledged", "abandoned"} {
+		backfill := &pb.Backfill{Id: id}
+		assert.Nil(service.CreateBackfill(ctx, backfill, nil))
+		assert.Nil(service.IndexBackfill(ctx, backfill))
+	}
+	clk.Advance(6 * time.Second)
+	assert.Nil(service.AcknowledgeBackfill(ctx, "acknowledged"))
+	err := service.AcknowledgeBackfill(ctx, "missing")
+	assert.Equal(codes.NotFound, status.Code(err))
+
+	ids, err := service.GetExpiredBackfillIDs(ctx)
+	assert.Nil(err)
+	assert.Empty(ids)
+
+	clk.Advance(6 * time.Second)
+	ids, err = service.GetExpiredBackfillIDs(ctx)
+	assert.Nil(err)
+	assert.Equal([]string{"abandoned"}, ids)
+
+	gc, err := service.CollectGarbage(ctx, nil)
+	assert.Nil(err)
+	assert.Equal(int64(1), gc.ExpiredBackfills)
+	_, _, err = service.GetBackfill(ctx, "abandoned")
+	assert.Equal(codes.NotFound, status.Code(err))
+	_, _, err = service.GetBackfill(ctx, "acknowledged")
+	assert.Nil(err)
+	indexed, err := service.GetIndexedBackfills(ctx)
+	assert.Nil(err)
+	assert.Equal(map[string]int64{"acknowledged": 0}, indexed)
+	ids, err = service.GetExpiredBackfillIDs(ctx)
+	assert.Nil(err)
+	assert.Empty(ids)
+}
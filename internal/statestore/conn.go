@@ -0,0 +1,185 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"open-match.dev/open-match/internal/config"
+)
+
+// commandTimeouts looks up the read timeout of Redis commands, configured by
+// their lowercase name under redis.timeouts, eg. redis.timeouts.mget, or else
+// by redis.timeouts.default.  Zero means the read timeout of the connection.
+type commandTimeouts struct {
+	cfg config.View
+	// cache maps command names to their timeout.
+	cache sync.Map
+}
+
+func newCommandTimeouts(cfg config.View) *commandTimeouts {
+	return &commandTimeouts{cfg: cfg}
+}
+
+func (ct *commandTimeouts) get(cmd string) time.Duration {
+	if t, ok := ct.cache.Load(cmd); ok {
+		return t.(time.Duration)
+	}
+
+	var t time.Duration
+	if key := "redis.timeouts." + strings.ToLower(cmd); cmd != "" && ct.cfg.IsSet(key) {
+		t = ct.cfg.GetDuration(key)
+	} else if ct.cfg.IsSet("redis.timeouts.default") {
+		t = ct.cfg.GetDuration("redis.timeouts.default")
+	}
+	ct.cache.Store(cmd, t)
+	return t
+}
+
+// contextConn is a redis.Conn whose commands honor the deadline and
+// cancellation of ctx, which redigo ignores once a command is sent.  Replies
+// are read with the command's timeout, shortened to the deadline of ctx.  On
+// cancellation the command is abandoned and ctx.Err() returned right away; it
+// completes in the background, after which the connection is closed.
+type contextConn struct {
+	redis.Conn
+	ctx      context.Context
+	timeouts *commandTimeouts
+	// pending are the names of the commands sent but not yet received.
+	pending []string
+	// abandoned is closed when the abandoned command completes, nil if none.
+	abandoned chan struct{}
+}
+
+func newContextConn(ctx context.Context, conn redis.Conn, timeouts *commandTimeouts) redis.Conn {
+	return &contextConn{Conn: conn, ctx: ctx, timeouts: timeouts}
+}
+
+// Do sends the command and reads its reply, as well as those of all pending
+// commands.
+func (c *contextConn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	var reply interface{}
+	err := c.run(cmd, func(timeout time.Duration) (err error) {
+		if timeout == 0 {
+			reply, err = c.Conn.Do(cmd, args...)
+		} else {
+			reply, err = redis.DoWithTimeout(c.Conn, timeout, cmd, args...)
+		}
+		return err
+	})
+	c.pending = nil
+	if err != nil {
+		// The reply may still be written by an abandoned command.
+		return nil, err
+	}
+	return reply, nil
+}
+
+// Send queues the command, whose reply is read by Receive.
+func (c *contextConn) Send(cmd string, args ...interface{}) error {
+	if err := c.ctx.Err(); err != nil {
+		return err
+	}
+	if err := c.Conn.Send(cmd, args...); err != nil {
+		return err
+	}
+	c.pending = append(c.pending, cmd)
+	return nil
+}
+
+// Flush writes the queued commands.
+func (c *contextConn) Flush() error {
+	return c.run("", func(time.Duration) error {
+		return c.Conn.Flush()
+	})
+}
+
+// Receive reads the reply of the first pending command.
+func (c *contextConn) Receive() (interface{}, error) {
+	cmd := ""
+	if len(c.pending) > 0 {
+		cmd = c.pending[0]
+		c.pending = c.pending[1:]
+	}
+
+	var reply interface{}
+	err := c.run(cmd, func(timeout time.Duration) (err error) {
+		if timeout == 0 {
+			reply, err = c.Conn.Receive()
+		} else {
+			reply, err = redis.ReceiveWithTimeout(c.Conn, timeout)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// Close returns the connection to the pool, or closes it once the abandoned
+// command completes.
+func (c *contextConn) Close() error {
+	if c.abandoned == nil {
+		return c.Conn.Close()
+	}
+	abandoned := c.abandoned
+	go func() {
+		<-abandoned
+		handleConnectionClose(&c.Conn)
+	}()
+	return nil
+}
+
+// run calls f with the timeout of cmd, abandoning it if ctx is done first.
+func (c *contextConn) run(cmd string, f func(timeout time.Duration) error) error {
+	if err := c.ctx.Err(); err != nil {
+		return err
+	}
+
+	timeout := c.timeouts.get(cmd)
+	if deadline, ok := c.ctx.Deadline(); ok {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return context.DeadlineExceeded
+		}
+		if timeout == 0 || remaining < timeout {
+			timeout = remaining
+		}
+	}
+
+	if c.ctx.Done() == nil {
+		return f(timeout)
+	}
+
+	done := make(chan struct{})
+	var err error
+	go func() {
+		defer close(done)
+		err = f(timeout)
+	}()
+
+	select {
+	case <-done:
+		return err
+	case <-c.ctx.Done():
+		c.abandoned = done
+		return c.ctx.Err()
+	}
+}
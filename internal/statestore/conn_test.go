@@ -0,0 +1,125 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/gomodule/redigo/redis"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandTimeouts(t *testing.T) {
+	assert := assert.New(t)
+	cfg := viper.New()
+	assert.Equal(time.Duration(0), newCommandTimeouts(cfg).get("GET"))
+
+	cfg.Set("redis.timeouts.default", "2s")
+	cfg.Set("redis.timeouts.mget", "5s")
+	ct := newCommandTimeouts(cfg)
+	assert.Equal(2*time.Second, ct.get("GET"))
+	assert.Equal(5*time.Second, ct.get("MGET"))
+	assert.Equal(2*time.Second, ct.get(""))
+}
+
+func TestContextConn(t *testing.T) {
+	assert := assert.New(t)
+	mredis, err := miniredis.Run()
+	assert.Nil(err)
+	defer mredis.Close()
+
+	raw, err := redis.Dial("tcp", mredis.Addr())
+	assert.Nil(err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn := newContextConn(ctx, raw, newCommandTimeouts(viper.New()))
+
+	_, err = conn.Do("SET", "a", "1")
+	assert.Nil(err)
+	assert.Nil(conn.Send("GET", "a"))
+	assert.Nil(conn.Send("GET", "b"))
+	assert.Nil(conn.Flush())
+	a, err := redis.String(conn.Receive())
+	assert.Nil(err)
+	assert.Equal("1", a)
+	_, err = redis.String(conn.Receive())
+	assert.Equal(redis.ErrNil, err)
+	assert.Nil(conn.Close())
+
+	// Commands fail right away once the context is done.
+	cancel()
+	conn = newContextConn(ctx, raw, newCommandTimeouts(viper.New()))
+	_, err = conn.Do("GET", "a")
+	assert.Equal(context.Canceled, err)
+}
+
+func TestContextConnUnresponsiveRedis(t *testing.T) {
+	assert := assert.New(t)
+
+	// A server which accepts connections but never replies.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(err)
+	defer l.Close()
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer c.Close()
+		}
+	}()
+
+	dial := func() redis.Conn {
+		conn, err := redis.Dial("tcp", l.Addr().String())
+		assert.Nil(err)
+		return conn
+	}
+
+	// Canceled mid-command.
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	conn := newContextConn(ctx, dial(), newCommandTimeouts(viper.New()))
+	start := time.Now()
+	_, err = conn.Do("MGET", "a", "b")
+	assert.Equal(context.Canceled, err)
+	assert.True(time.Since(start) < 5*time.Second)
+	assert.Nil(conn.Close())
+
+	// Past the context deadline.
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	conn = newContextConn(ctx, dial(), newCommandTimeouts(viper.New()))
+	start = time.Now()
+	_, err = conn.Do("MGET", "a", "b")
+	assert.NotNil(err)
+	assert.True(time.Since(start) < 5*time.Second)
+	conn.Close()
+
+	// Past the command timeout.
+	cfg := viper.New()
+	cfg.Set("redis.timeouts.mget", "50ms")
+	conn = newContextConn(context.Background(), dial(), newCommandTimeouts(cfg))
+	start = time.Now()
+	_, err = conn.Do("MGET", "a", "b")
+	assert.NotNil(err)
+	assert.True(time.Since(start) < 5*time.Second)
+	conn.Close()
+}
@@ -0,0 +1,171 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/sirupsen/logrus"
+)
+
+// expiredEventPattern matches the channels Redis publishes the names of
+// expired keys to, for every database.
+const expiredEventPattern = "__keyevent@*__:expired"
+
+// cleanUpExpiredTicketScript removes an expired ticket from the index and the
+// ignore list, deletes its assignment and version, no longer counts it as
+// stored, and moves it to the EXPIRED state for a while, unless the ticket was
+// created again since.  KEYS are the ticket key, the indexed tickets, the
+// ignore list, the assignment key, the version key, the state key and the
+// stored tickets counter, ARGV are the ticket id and how many milliseconds the
+// EXPIRED state is kept.  Returns 1 if the ticket was cleaned up.
+var cleanUpExpiredTicketScript = redis.NewScript(7, `
+if redis.call("EXISTS", KEYS[1]) == 1 then
+  return 0
+end
+-- Every instance is notified of the expiration, count it once.
+if redis.call("GET", KEYS[6]) ~= "EXPIRED" and tonumber(redis.call("GET", KEYS[7]) or "0") > 0 then
+  redis.call("DECR", KEYS[7])
+end
+redis.call("SREM", KEYS[2], ARGV[1])
+redis.call("ZREM", KEYS[3], ARGV[1])
+redis.call("DEL", KEYS[4], KEYS[5])
+redis.call("SET", KEYS[6], "EXPIRED", "PX", ARGV[2])
+return 1
+`)
+
+// CleanUpExpiredTickets removes Tickets from the index and the ignore list as soon as redis.expiration evicts them,
+// calling cleaned with the id of each.  It subscribes to the expired key notifications of Redis, enabling them if the
+// server allows it, and returns when ctx is done.  CollectGarbage deindexes the Tickets whose expiration was missed,
+// eg. while disconnected, removes the field index entries of expired Tickets, and recounts the stored Tickets to free
+// up the quota of those missed.
+func (rb *redisBackend) CleanUpExpiredTickets(ctx context.Context, cleaned func(id string)) error {
+	rb.enableExpiredNotifications(ctx)
+	for {
+		err := rb.watchExpiredTickets(ctx, cleaned)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		redisLogger.WithError(err).Warning("lost the expired key notifications connection, reconnecting")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(notifierReconnectDelay):
+		}
+	}
+}
+
+// enableExpiredNotifications turns on the expired key events, which Redis
+// doesn't publish by default, keeping any other notifications enabled.
+func (rb *redisBackend) enableExpiredNotifications(ctx context.Context) {
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return
+	}
+	defer handleConnectionClose(&redisConn)
+
+	const warning = "failed to enable expired key notifications, set notify-keyspace-events to include Ex on the Redis server"
+	reply, err := redis.Strings(redisConn.Do("CONFIG", "GET", "notify-keyspace-events"))
+	if err != nil || len(reply) != 2 {
+		redisLogger.WithError(err).Warning(warning)
+		return
+	}
+
+	flags := reply[1]
+	enabled := flags
+	if !strings.Contains(enabled, "E") {
+		enabled += "E"
+	}
+	if !strings.Contains(enabled, "x") && !strings.Contains(enabled, "A") {
+		enabled += "x"
+	}
+	if enabled == flags {
+		return
+	}
+	if _, err = redisConn.Do("CONFIG", "SET", "notify-keyspace-events", enabled); err != nil {
+		redisLogger.WithError(err).Warning(warning)
+		return
+	}
+	redisLogger.WithFields(logrus.Fields{
+		"notify-keyspace-events": enabled,
+	}).Info("Enabled expired key notifications.")
+}
+
+// watchExpiredTickets cleans up the tickets of the expired key notifications
+// until the connection fails or ctx is done.
+func (rb *redisBackend) watchExpiredTickets(ctx context.Context, cleaned func(id string)) error {
+	conn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	psc := &redis.PubSubConn{Conn: conn}
+	defer psc.Close()
+
+	if err = psc.PSubscribe(expiredEventPattern); err != nil {
+		return err
+	}
+
+	// Unblock the receive below once ctx is done.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			psc.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		switch v := psc.ReceiveWithTimeout(0).(type) {
+		case redis.Message:
+			rb.cleanUpExpiredKey(ctx, string(v.Data), cleaned)
+		case error:
+			return v
+		}
+	}
+}
+
+// cleanUpExpiredKey cleans up the ticket stored under key, if it's one.
+func (rb *redisBackend) cleanUpExpiredKey(ctx context.Context, key string, cleaned func(id string)) {
+	for _, keys := range rb.keyspaces() {
+		id, ok := keys.ticketID(key)
+		if !ok {
+			continue
+		}
+
+		redisConn, err := rb.connect(ctx)
+		if err != nil {
+			return
+		}
+		defer handleConnectionClose(&redisConn)
+
+		n, err := redis.Int(cleanUpExpiredTicketScript.Do(redisConn, keys.ticket(id), keys.allTickets(), keys.ignoreList(), keys.assignment(id), keys.version(id), keys.state(id), keys.storedTickets(), id, rb.expiredTicketStateTTL().Milliseconds()))
+		if err != nil {
+			redisLogger.WithFields(logrus.Fields{
+				"id":    id,
+				"error": err.Error(),
+			}).Warning("failed to clean up an expired ticket, leaving it to garbage collection")
+			return
+		}
+		if n == 1 && cleaned != nil {
+			cleaned(id)
+		}
+		return
+	}
+}
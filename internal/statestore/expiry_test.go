@@ -0,0 +1,97 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+	"open-match.dev/open-match/internal/clock"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestCleanUpExpiredTickets(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	service := New(cfg)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	for _, id := range []string{"1", "2"} {
+		ticket := &pb.Ticket{Id: id}
+		assert.Nil(service.CreateTicket(ctx, ticket))
+		assert.Nil(service.IndexTicket(ctx, ticket))
+	}
+	leaseTickets(t, service, []string{"1", "2"})
+
+	rb := newRedis(cfg, clock.Real()).(*redisBackend)
+	defer rb.Close()
+	redisConn, err := rb.connect(ctx)
+	assert.Nil(err)
+	defer redisConn.Close()
+	// Miniredis doesn't publish expired key events, expire ticket 1 by hand.
+	_, err = redisConn.Do("DEL", "1")
+	assert.Nil(err)
+
+	cleaned := make(chan string, 10)
+	watchCtx, cancel := context.WithCancel(ctx)
+	stopped := make(chan error)
+	go func() {
+		stopped <- service.CleanUpExpiredTickets(watchCtx, func(id string) {
+			cleaned <- id
+		})
+	}()
+
+	// Wait for the subscription, then publish events for a ticket which still
+	// exists, a key which isn't a ticket and the expired ticket.
+	for {
+		n, err := redis.Int(redisConn.Do("PUBLISH", "__keyevent@0__:expired", "2"))
+		assert.Nil(err)
+		if n > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	_, err = redisConn.Do("PUBLISH", "__keyevent@0__:expired", "2:version")
+	assert.Nil(err)
+	_, err = redisConn.Do("PUBLISH", "__keyevent@0__:expired", "1")
+	assert.Nil(err)
+
+	select {
+	case id := <-cleaned:
+		assert.Equal("1", id)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expired ticket wasn't cleaned up")
+	}
+
+	indexed, err := redis.Strings(redisConn.Do("SMEMBERS", "allTickets"))
+	assert.Nil(err)
+	assert.Equal([]string{"2"}, indexed)
+	leased, err := redis.Strings(redisConn.Do("ZRANGE", "proposed_ticket_ids", 0, -1))
+	assert.Nil(err)
+	assert.Equal([]string{"2"}, leased)
+	stored, err := redis.Int(redisConn.Do("GET", "storedTickets"))
+	assert.Nil(err)
+	assert.Equal(1, stored)
+
+	cancel()
+	assert.Equal(context.Canceled, <-stopped)
+	assert.Empty(cleaned)
+}
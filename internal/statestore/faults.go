@@ -0,0 +1,698 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+const (
+	// configNameFaults lists the faults New injects into calls to the storage
+	// backend, for testing error handling.
+	configNameFaults = "storage.faults"
+	// allMethods is the method name of a fault injected into every method
+	// without a fault of its own.
+	allMethods = "*"
+)
+
+// Fault describes failures injected into calls to a method of a Service.
+type Fault struct {
+	// Latency delays every call before it's made.
+	Latency time.Duration
+	// ErrorRate is the fraction of calls, between 0 and 1, which fail with
+	// Unavailable without being made.
+	ErrorRate float64
+	// PartialRate is the fraction of calls, between 0 and 1, which are made
+	// but fail with Unavailable even if they succeeded, as when the connection
+	// drops before the reply is read.
+	PartialRate float64
+}
+
+// NewFaultInjector wraps s, injecting the faults of each method named in
+// faults into its calls, eg.
+//
+//	s = statestore.NewFaultInjector(s, map[string]statestore.Fault{"CreateTicket": {ErrorRate: 1}})
+//
+// The fault named "*" is injected into every method without one of its own.
+func NewFaultInjector(s Service, faults map[string]Fault) Service {
+	return &faultInjector{
+		s:      s,
+		faults: faults,
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// ParseFaults parses faults from entries of the form
+// "method:latency=50ms,errorRate=0.1,partialRate=0.05", where method is a
+// method of Service or "*", and any of the settings may be left out.
+func ParseFaults(entries []string) (map[string]Fault, error) {
+	serviceType := reflect.TypeOf((*Service)(nil)).Elem()
+	faults := make(map[string]Fault)
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, ":", 2)
+		method := parts[0]
+		if _, ok := serviceType.MethodByName(method); !ok && method != allMethods {
+			return nil, fmt.Errorf("invalid fault %q, %q isn't a statestore method", entry, method)
+		}
+
+		var fault Fault
+		if len(parts) == 2 && parts[1] != "" {
+			for _, setting := range strings.Split(parts[1], ",") {
+				kv := strings.SplitN(setting, "=", 2)
+				if len(kv) != 2 {
+					return nil, fmt.Errorf("invalid fault %q, expected setting=value", entry)
+				}
+				var err error
+				switch kv[0] {
+				case "latency":
+					fault.Latency, err = time.ParseDuration(kv[1])
+				case "errorRate":
+					fault.ErrorRate, err = strconv.ParseFloat(kv[1], 64)
+				case "partialRate":
+					fault.PartialRate, err = strconv.ParseFloat(kv[1], 64)
+				default:
+					err = fmt.Errorf("unknown setting %q", kv[0])
+				}
+				if err != nil {
+					return nil, fmt.Errorf("invalid fault %q: %w", entry, err)
+				}
+			}
+		}
+		faults[method] = fault
+	}
+	return faults, nil
+}
+
+// withFaultsFromConfig wraps s in a fault injector if storage.faults is set.
+func withFaultsFromConfig(cfg config.View, s Service) Service {
+	entries := cfg.GetStringSlice(configNameFaults)
+	if len(entries) == 0 {
+		return s
+	}
+
+	faults, err := ParseFaults(entries)
+	if err != nil {
+		logger.WithError(err).Fatal("Invalid storage faults.")
+	}
+	logger.WithFields(logrus.Fields{
+		"faults": entries,
+	}).Warning("Injecting faults into state storage calls, this should only be configured for testing.")
+	return NewFaultInjector(s, faults)
+}
+
+// faultInjector is a Service injecting faults into the calls to another.
+type faultInjector struct {
+	s      Service
+	faults map[string]Fault
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// call makes the call to method with do, injecting the method's fault.
+func (fi *faultInjector) call(ctx context.Context, method string, do func() error) error {
+	fault, ok := fi.faults[method]
+	if !ok {
+		fault, ok = fi.faults[allMethods]
+	}
+	if !ok {
+		return do()
+	}
+
+	if fault.Latency > 0 {
+		timer := time.NewTimer(fault.Latency)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return status.FromContextError(ctx.Err()).Err()
+		case <-timer.C:
+		}
+	}
+
+	fi.mu.Lock()
+	roll := fi.rand.Float64()
+	fi.mu.Unlock()
+
+	if roll < fault.ErrorRate {
+		return status.Errorf(codes.Unavailable, "injected fault: %s failed", method)
+	}
+	err := do()
+	if err == nil && roll < fault.ErrorRate+fault.PartialRate {
+		return status.Errorf(codes.Unavailable, "injected fault: %s succeeded but reported a failure", method)
+	}
+	return err
+}
+
+// Close closes the wrapped Service, without injecting faults.
+func (fi *faultInjector) Close() error {
+	return fi.s.Close()
+}
+
+// HealthCheck indicates if the database is reachable.
+func (fi *faultInjector) HealthCheck(ctx context.Context) error {
+	return fi.call(ctx, "HealthCheck", func() error {
+		return fi.s.HealthCheck(ctx)
+	})
+}
+
+// CreateTicket creates a new Ticket in the state storage. If the id already exists, it will be overwritten.
+func (fi *faultInjector) CreateTicket(ctx context.Context, ticket *pb.Ticket) error {
+	return fi.call(ctx, "CreateTicket", func() error {
+		return fi.s.CreateTicket(ctx, ticket)
+	})
+}
+
+// CreateTickets creates multiple Tickets in a single round trip.
+func (fi *faultInjector) CreateTickets(ctx context.Context, tickets []*pb.Ticket) error {
+	return fi.call(ctx, "CreateTickets", func() error {
+		return fi.s.CreateTickets(ctx, tickets)
+	})
+}
+
+// CreateTicketsWithExpiration creates Tickets which expire after expiration.
+func (fi *faultInjector) CreateTicketsWithExpiration(ctx context.Context, tickets []*pb.Ticket, expiration time.Duration) error {
+	return fi.call(ctx, "CreateTicketsWithExpiration", func() error {
+		return fi.s.CreateTicketsWithExpiration(ctx, tickets, expiration)
+	})
+}
+
+// GetTicket gets the Ticket with the specified id from state storage. This method fails if the Ticket does not exist.
+func (fi *faultInjector) GetTicket(ctx context.Context, id string) (*pb.Ticket, error) {
+	var ticket *pb.Ticket
+	err := fi.call(ctx, "GetTicket", func() (err error) {
+		ticket, err = fi.s.GetTicket(ctx, id)
+		return err
+	})
+	return ticket, err
+}
+
+// GetTicketWithVersion gets the Ticket with the specified id along with its version.
+func (fi *faultInjector) GetTicketWithVersion(ctx context.Context, id string) (*pb.Ticket, int64, error) {
+	var ticket *pb.Ticket
+	var version int64
+	err := fi.call(ctx, "GetTicketWithVersion", func() (err error) {
+		ticket, version, err = fi.s.GetTicketWithVersion(ctx, id)
+		return err
+	})
+	return ticket, version, err
+}
+
+// GetTicketState returns the lifecycle state of the Ticket.
+func (fi *faultInjector) GetTicketState(ctx context.Context, id string) (pb.Ticket_State, error) {
+	var state pb.Ticket_State
+	err := fi.call(ctx, "GetTicketState", func() (err error) {
+		state, err = fi.s.GetTicketState(ctx, id)
+		return err
+	})
+	return state, err
+}
+
+// GetTicketStatus returns where the Ticket is in the matchmaking queue.
+func (fi *faultInjector) GetTicketStatus(ctx context.Context, id string) (*TicketStatus, error) {
+	var ticketStatus *TicketStatus
+	err := fi.call(ctx, "GetTicketStatus", func() (err error) {
+		ticketStatus, err = fi.s.GetTicketStatus(ctx, id)
+		return err
+	})
+	return ticketStatus, err
+}
+
+// CompareAndSetTicket overwrites the Ticket if its version is still version.
+func (fi *faultInjector) CompareAndSetTicket(ctx context.Context, ticket *pb.Ticket, version int64) (int64, error) {
+	var newVersion int64
+	err := fi.call(ctx, "CompareAndSetTicket", func() (err error) {
+		newVersion, err = fi.s.CompareAndSetTicket(ctx, ticket, version)
+		return err
+	})
+	return newVersion, err
+}
+
+// DeleteTicket removes the Ticket with the specified id from state storage.
+func (fi *faultInjector) DeleteTicket(ctx context.Context, id string) error {
+	return fi.call(ctx, "DeleteTicket", func() error {
+		return fi.s.DeleteTicket(ctx, id)
+	})
+}
+
+// DeleteTickets removes the Tickets with the specified ids from state storage in a single round trip.
+func (fi *faultInjector) DeleteTickets(ctx context.Context, ids []string) error {
+	return fi.call(ctx, "DeleteTickets", func() error {
+		return fi.s.DeleteTickets(ctx, ids)
+	})
+}
+
+// ClaimIdempotencyKey records that the Ticket is created with the idempotency key.
+func (fi *faultInjector) ClaimIdempotencyKey(ctx context.Context, key string, id string, ttl time.Duration) (string, error) {
+	var claimedID string
+	err := fi.call(ctx, "ClaimIdempotencyKey", func() (err error) {
+		claimedID, err = fi.s.ClaimIdempotencyKey(ctx, key, id, ttl)
+		return err
+	})
+	return claimedID, err
+}
+
+// ReleaseIdempotencyKey deletes the idempotency key if it's still claimed for the Ticket.
+func (fi *faultInjector) ReleaseIdempotencyKey(ctx context.Context, key string, id string) error {
+	return fi.call(ctx, "ReleaseIdempotencyKey", func() error {
+		return fi.s.ReleaseIdempotencyKey(ctx, key, id)
+	})
+}
+
+// IndexTicket indexes the Ticket id for the configured index fields.
+func (fi *faultInjector) IndexTicket(ctx context.Context, ticket *pb.Ticket) error {
+	return fi.call(ctx, "IndexTicket", func() error {
+		return fi.s.IndexTicket(ctx, ticket)
+	})
+}
+
+// IndexTickets adds multiple tickets to the index in a single round trip.
+func (fi *faultInjector) IndexTickets(ctx context.Context, tickets []*pb.Ticket) error {
+	return fi.call(ctx, "IndexTickets", func() error {
+		return fi.s.IndexTickets(ctx, tickets)
+	})
+}
+
+// DeindexTicket removes the indexing for the specified Ticket. Only the indexes are removed but the Ticket continues to exist.
+func (fi *faultInjector) DeindexTicket(ctx context.Context, id string) error {
+	return fi.call(ctx, "DeindexTicket", func() error {
+		return fi.s.DeindexTicket(ctx, id)
+	})
+}
+
+// DeindexTickets removes the indexing for the specified Tickets in a single transaction.
+func (fi *faultInjector) DeindexTickets(ctx context.Context, ids []string) error {
+	return fi.call(ctx, "DeindexTickets", func() error {
+		return fi.s.DeindexTickets(ctx, ids)
+	})
+}
+
+// RecordHeartbeats records a heartbeat for each of the indexed Tickets.
+func (fi *faultInjector) RecordHeartbeats(ctx context.Context, ids []string) ([]string, error) {
+	var missing []string
+	err := fi.call(ctx, "RecordHeartbeats", func() (err error) {
+		missing, err = fi.s.RecordHeartbeats(ctx, ids)
+		return err
+	})
+	return missing, err
+}
+
+// DeindexLapsedTickets deindexes up to limit Tickets whose last heartbeat is older than timeout.
+func (fi *faultInjector) DeindexLapsedTickets(ctx context.Context, timeout time.Duration, limit int) ([]string, error) {
+	var ids []string
+	err := fi.call(ctx, "DeindexLapsedTickets", func() (err error) {
+		ids, err = fi.s.DeindexLapsedTickets(ctx, timeout, limit)
+		return err
+	})
+	return ids, err
+}
+
+// CountTickets returns the number of indexed Tickets matching all filters of the pool.
+func (fi *faultInjector) CountTickets(ctx context.Context, pool *pb.Pool) (int64, error) {
+	var count int64
+	err := fi.call(ctx, "CountTickets", func() (err error) {
+		count, err = fi.s.CountTickets(ctx, pool)
+		return err
+	})
+	return count, err
+}
+
+// GetTickets returns multiple tickets from storage.  Missing tickets are
+// silently ignored.
+func (fi *faultInjector) GetTickets(ctx context.Context, ids []string) ([]*pb.Ticket, error) {
+	var tickets []*pb.Ticket
+	err := fi.call(ctx, "GetTickets", func() (err error) {
+		tickets, err = fi.s.GetTickets(ctx, ids)
+		return err
+	})
+	return tickets, err
+}
+
+// GetIndexedIds returns the ids of all tickets currently indexed.
+func (fi *faultInjector) GetIndexedIDSet(ctx context.Context) (map[string]struct{}, error) {
+	var ids map[string]struct{}
+	err := fi.call(ctx, "GetIndexedIDSet", func() (err error) {
+		ids, err = fi.s.GetIndexedIDSet(ctx)
+		return err
+	})
+	return ids, err
+}
+
+// TakeIndexSnapshot copies the ids of all tickets currently indexed under token.
+func (fi *faultInjector) TakeIndexSnapshot(ctx context.Context, token string, ttl time.Duration) error {
+	return fi.call(ctx, "TakeIndexSnapshot", func() error {
+		return fi.s.TakeIndexSnapshot(ctx, token, ttl)
+	})
+}
+
+// GetIndexSnapshot returns the ids copied by TakeIndexSnapshot under token.
+func (fi *faultInjector) GetIndexSnapshot(ctx context.Context, token string) (map[string]struct{}, error) {
+	var ids map[string]struct{}
+	err := fi.call(ctx, "GetIndexSnapshot", func() (err error) {
+		ids, err = fi.s.GetIndexSnapshot(ctx, token)
+		return err
+	})
+	return ids, err
+}
+
+// ScanIndexedIDs calls f with pages of the ids of all tickets currently indexed.
+func (fi *faultInjector) ScanIndexedIDs(ctx context.Context, f func([]string) error) error {
+	return fi.call(ctx, "ScanIndexedIDs", func() error {
+		return fi.s.ScanIndexedIDs(ctx, f)
+	})
+}
+
+// UpdateAssignments update the match assignments for the input ticket ids, and returns the ids which don't exist,
+// and those which were already assigned.
+func (fi *faultInjector) UpdateAssignments(ctx context.Context, ids []string, assignment *pb.Assignment) ([]string, []string, error) {
+	var notFound, alreadyAssigned []string
+	err := fi.call(ctx, "UpdateAssignments", func() (err error) {
+		notFound, alreadyAssigned, err = fi.s.UpdateAssignments(ctx, ids, assignment)
+		return err
+	})
+	return notFound, alreadyAssigned, err
+}
+
+// UpdateAssignmentGroups sets the assignment of each group on its tickets.
+func (fi *faultInjector) UpdateAssignmentGroups(ctx context.Context, groups []*pb.AssignmentGroup) ([]string, []string, error) {
+	var notFound, alreadyAssigned []string
+	err := fi.call(ctx, "UpdateAssignmentGroups", func() (err error) {
+		notFound, alreadyAssigned, err = fi.s.UpdateAssignmentGroups(ctx, groups)
+		return err
+	})
+	return notFound, alreadyAssigned, err
+}
+
+// RequeueFailedAssignments clears the assignments of the Tickets and indexes them again.
+func (fi *faultInjector) RequeueFailedAssignments(ctx context.Context, ids []string, maxFailures int) (int64, []string, []string, error) {
+	var requeued int64
+	var notFound, exhausted []string
+	err := fi.call(ctx, "RequeueFailedAssignments", func() (err error) {
+		requeued, notFound, exhausted, err = fi.s.RequeueFailedAssignments(ctx, ids, maxFailures)
+		return err
+	})
+	return requeued, notFound, exhausted, err
+}
+
+// GetAssignments returns the assignment associated with the input ticket id
+func (fi *faultInjector) GetAssignments(ctx context.Context, id string, callback func(*pb.Assignment) error) error {
+	return fi.call(ctx, "GetAssignments", func() error {
+		return fi.s.GetAssignments(ctx, id, callback)
+	})
+}
+
+// RewriteTickets calls rewrite on every Ticket in state storage, and saves the Tickets for which it returns true.
+func (fi *faultInjector) RewriteTickets(ctx context.Context, rewrite func(*pb.Ticket) bool) (int64, int64, error) {
+	var scanned int64
+	var rewritten int64
+	err := fi.call(ctx, "RewriteTickets", func() (err error) {
+		scanned, rewritten, err = fi.s.RewriteTickets(ctx, rewrite)
+		return err
+	})
+	return scanned, rewritten, err
+}
+
+// RewriteTicketsByID calls rewrite on each of the Tickets with the given ids, and saves the Tickets for which it returns true.
+func (fi *faultInjector) RewriteTicketsByID(ctx context.Context, ids []string, rewrite func(*pb.Ticket) bool) (int64, []string, error) {
+	var rewritten int64
+	var notFound []string
+	err := fi.call(ctx, "RewriteTicketsByID", func() (err error) {
+		rewritten, notFound, err = fi.s.RewriteTicketsByID(ctx, ids, rewrite)
+		return err
+	})
+	return rewritten, notFound, err
+}
+
+// ExportTickets calls f with pages of snapshots of every Ticket in state storage.
+func (fi *faultInjector) ExportTickets(ctx context.Context, f func([]*pb.TicketSnapshot) error) error {
+	return fi.call(ctx, "ExportTickets", func() error {
+		return fi.s.ExportTickets(ctx, f)
+	})
+}
+
+// ImportTickets saves the Tickets of the snapshots, and indexes those which were indexed.
+func (fi *faultInjector) ImportTickets(ctx context.Context, snapshots []*pb.TicketSnapshot) error {
+	return fi.call(ctx, "ImportTickets", func() error {
+		return fi.s.ImportTickets(ctx, snapshots)
+	})
+}
+
+// GetTicketsRevision returns a number which changes whenever RewriteTickets modifies Tickets.
+func (fi *faultInjector) GetTicketsRevision(ctx context.Context) (int64, error) {
+	var revision int64
+	err := fi.call(ctx, "GetTicketsRevision", func() (err error) {
+		revision, err = fi.s.GetTicketsRevision(ctx)
+		return err
+	})
+	return revision, err
+}
+
+// RecordProfile saves the profile in the profile registry, marking it as recently used.
+func (fi *faultInjector) RecordProfile(ctx context.Context, profile *pb.MatchProfile) error {
+	return fi.call(ctx, "RecordProfile", func() error {
+		return fi.s.RecordProfile(ctx, profile)
+	})
+}
+
+// GetProfiles returns the profiles recently recorded in the profile registry.
+func (fi *faultInjector) GetProfiles(ctx context.Context) ([]*pb.MatchProfile, error) {
+	var profiles []*pb.MatchProfile
+	err := fi.call(ctx, "GetProfiles", func() (err error) {
+		profiles, err = fi.s.GetProfiles(ctx)
+		return err
+	})
+	return profiles, err
+}
+
+// PublishComponent saves the build version and config digest of a running component.
+func (fi *faultInjector) PublishComponent(ctx context.Context, component *ComponentInfo) error {
+	return fi.call(ctx, "PublishComponent", func() error {
+		return fi.s.PublishComponent(ctx, component)
+	})
+}
+
+// GetComponents returns the components recently published with PublishComponent.
+func (fi *faultInjector) GetComponents(ctx context.Context) ([]*ComponentInfo, error) {
+	var components []*ComponentInfo
+	err := fi.call(ctx, "GetComponents", func() (err error) {
+		components, err = fi.s.GetComponents(ctx)
+		return err
+	})
+	return components, err
+}
+
+// SetFeatureGateOverride saves the override, replacing any override of the same gate and instance.
+func (fi *faultInjector) SetFeatureGateOverride(ctx context.Context, override *pb.FeatureGateOverride) error {
+	return fi.call(ctx, "SetFeatureGateOverride", func() error {
+		return fi.s.SetFeatureGateOverride(ctx, override)
+	})
+}
+
+// DeleteFeatureGateOverride removes the override of the gate for the instance, if any.
+func (fi *faultInjector) DeleteFeatureGateOverride(ctx context.Context, gate string, instance string) error {
+	return fi.call(ctx, "DeleteFeatureGateOverride", func() error {
+		return fi.s.DeleteFeatureGateOverride(ctx, gate, instance)
+	})
+}
+
+// GetFeatureGateOverrides returns all feature gate overrides.
+func (fi *faultInjector) GetFeatureGateOverrides(ctx context.Context) ([]*pb.FeatureGateOverride, error) {
+	var overrides []*pb.FeatureGateOverride
+	err := fi.call(ctx, "GetFeatureGateOverrides", func() (err error) {
+		overrides, err = fi.s.GetFeatureGateOverrides(ctx)
+		return err
+	})
+	return overrides, err
+}
+
+// CollectGarbage removes expired leases, index entries of missing tickets, and orphaned tickets.
+func (fi *faultInjector) CollectGarbage(ctx context.Context, orphanCandidates map[string]struct{}) (*GarbageCollection, error) {
+	var collection *GarbageCollection
+	err := fi.call(ctx, "CollectGarbage", func() (err error) {
+		collection, err = fi.s.CollectGarbage(ctx, orphanCandidates)
+		return err
+	})
+	return collection, err
+}
+
+// AcquireTicketLease leases the tickets to owner, returning the ids of the tickets leased to another owner.
+func (fi *faultInjector) AcquireTicketLease(ctx context.Context, owner string, ids []string) ([]string, error) {
+	var held []string
+	err := fi.call(ctx, "AcquireTicketLease", func() (err error) {
+		held, err = fi.s.AcquireTicketLease(ctx, owner, ids)
+		return err
+	})
+	return held, err
+}
+
+// ExtendLease restarts the leases owner holds on the tickets, returning the ids of the tickets it doesn't hold.
+func (fi *faultInjector) ExtendLease(ctx context.Context, owner string, ids []string) ([]string, error) {
+	var missing []string
+	err := fi.call(ctx, "ExtendLease", func() (err error) {
+		missing, err = fi.s.ExtendLease(ctx, owner, ids)
+		return err
+	})
+	return missing, err
+}
+
+// ReleaseTicketLease releases the leases owner holds on the tickets.
+func (fi *faultInjector) ReleaseTicketLease(ctx context.Context, owner string, ids []string) error {
+	return fi.call(ctx, "ReleaseTicketLease", func() error {
+		return fi.s.ReleaseTicketLease(ctx, owner, ids)
+	})
+}
+
+// RecordCycle records that the synchronizer cycle is in flight.
+func (fi *faultInjector) RecordCycle(ctx context.Context, cycleID string) error {
+	return fi.call(ctx, "RecordCycle", func() error {
+		return fi.s.RecordCycle(ctx, cycleID)
+	})
+}
+
+// FinishCycle records that the synchronizer cycle is done with its leases.
+func (fi *faultInjector) FinishCycle(ctx context.Context, cycleID string) error {
+	return fi.call(ctx, "FinishCycle", func() error {
+		return fi.s.FinishCycle(ctx, cycleID)
+	})
+}
+
+// ReleaseAbandonedCycles releases the leases of the cycles in flight since before startedBefore.
+func (fi *faultInjector) ReleaseAbandonedCycles(ctx context.Context, startedBefore time.Time) ([]string, int, error) {
+	var cycles []string
+	var released int
+	err := fi.call(ctx, "ReleaseAbandonedCycles", func() (err error) {
+		cycles, released, err = fi.s.ReleaseAbandonedCycles(ctx, startedBefore)
+		return err
+	})
+	return cycles, released, err
+}
+
+// DeleteTicketsFromIgnoreList releases the leases on the tickets, whoever owns them.
+func (fi *faultInjector) DeleteTicketsFromIgnoreList(ctx context.Context, ids []string) error {
+	return fi.call(ctx, "DeleteTicketsFromIgnoreList", func() error {
+		return fi.s.DeleteTicketsFromIgnoreList(ctx, ids)
+	})
+}
+
+// CleanUpExpiredTickets removes Tickets from the index and the ignore list as soon as they expire, until ctx is done.
+// It runs for the lifetime of the caller, so faults aren't injected.
+func (fi *faultInjector) CleanUpExpiredTickets(ctx context.Context, cleaned func(id string)) error {
+	return fi.s.CleanUpExpiredTickets(ctx, cleaned)
+}
+
+// ReleaseAllTickets empties the ignore list.
+func (fi *faultInjector) ReleaseAllTickets(ctx context.Context) (int, error) {
+	var released int
+	err := fi.call(ctx, "ReleaseAllTickets", func() (err error) {
+		released, err = fi.s.ReleaseAllTickets(ctx)
+		return err
+	})
+	return released, err
+}
+
+// GetStorageUsage reports the number of tickets and approximate memory used in state storage.
+func (fi *faultInjector) GetStorageUsage(ctx context.Context, sampleSize int) (*pb.StorageUsage, error) {
+	var usage *pb.StorageUsage
+	err := fi.call(ctx, "GetStorageUsage", func() (err error) {
+		usage, err = fi.s.GetStorageUsage(ctx, sampleSize)
+		return err
+	})
+	return usage, err
+}
+
+// CreateBackfill creates a new Backfill in state storage, along with the ids of its Tickets.
+func (fi *faultInjector) CreateBackfill(ctx context.Context, backfill *pb.Backfill, ticketIDs []string) error {
+	return fi.call(ctx, "CreateBackfill", func() error {
+		return fi.s.CreateBackfill(ctx, backfill, ticketIDs)
+	})
+}
+
+// GetBackfill gets the Backfill with the specified id, and the ids of its Tickets.
+func (fi *faultInjector) GetBackfill(ctx context.Context, id string) (*pb.Backfill, []string, error) {
+	var backfill *pb.Backfill
+	var ticketIDs []string
+	err := fi.call(ctx, "GetBackfill", func() (err error) {
+		backfill, ticketIDs, err = fi.s.GetBackfill(ctx, id)
+		return err
+	})
+	return backfill, ticketIDs, err
+}
+
+// UpdateBackfill overwrites the Backfill and the ids of its Tickets.
+func (fi *faultInjector) UpdateBackfill(ctx context.Context, backfill *pb.Backfill, ticketIDs []string) error {
+	return fi.call(ctx, "UpdateBackfill", func() error {
+		return fi.s.UpdateBackfill(ctx, backfill, ticketIDs)
+	})
+}
+
+// DeleteBackfill removes the Backfill from state storage.
+func (fi *faultInjector) DeleteBackfill(ctx context.Context, id string) error {
+	return fi.call(ctx, "DeleteBackfill", func() error {
+		return fi.s.DeleteBackfill(ctx, id)
+	})
+}
+
+// AcknowledgeBackfill records that the game server of the Backfill still wants it filled.
+func (fi *faultInjector) AcknowledgeBackfill(ctx context.Context, id string) error {
+	return fi.call(ctx, "AcknowledgeBackfill", func() error {
+		return fi.s.AcknowledgeBackfill(ctx, id)
+	})
+}
+
+// GetExpiredBackfillIDs returns the ids of the Backfills which are no longer acknowledged.
+func (fi *faultInjector) GetExpiredBackfillIDs(ctx context.Context) ([]string, error) {
+	var ids []string
+	err := fi.call(ctx, "GetExpiredBackfillIDs", func() (err error) {
+		ids, err = fi.s.GetExpiredBackfillIDs(ctx)
+		return err
+	})
+	return ids, err
+}
+
+// IndexBackfill makes the Backfill available to be filled.
+func (fi *faultInjector) IndexBackfill(ctx context.Context, backfill *pb.Backfill) error {
+	return fi.call(ctx, "IndexBackfill", func() error {
+		return fi.s.IndexBackfill(ctx, backfill)
+	})
+}
+
+// DeindexBackfill removes the Backfill from the index.
+func (fi *faultInjector) DeindexBackfill(ctx context.Context, id string) error {
+	return fi.call(ctx, "DeindexBackfill", func() error {
+		return fi.s.DeindexBackfill(ctx, id)
+	})
+}
+
+// GetIndexedBackfills returns the ids of the indexed Backfills with their generation.
+func (fi *faultInjector) GetIndexedBackfills(ctx context.Context) (map[string]int64, error) {
+	var backfills map[string]int64
+	err := fi.call(ctx, "GetIndexedBackfills", func() (err error) {
+		backfills, err = fi.s.GetIndexedBackfills(ctx)
+		return err
+	})
+	return backfills, err
+}
@@ -0,0 +1,108 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestFaultInjector(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	service := New(cfg)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	faulty := NewFaultInjector(service, map[string]Fault{
+		"CreateTicket": {ErrorRate: 1},
+		"IndexTicket":  {PartialRate: 1},
+		"*":            {Latency: 50 * time.Millisecond},
+	})
+
+	// Failed calls aren't made.
+	err := faulty.CreateTicket(ctx, &pb.Ticket{Id: "1"})
+	assert.Equal(codes.Unavailable, status.Code(err))
+	_, err = service.GetTicket(ctx, "1")
+	assert.Equal(codes.NotFound, status.Code(err))
+
+	// Partially failed calls are made.
+	assert.Nil(service.CreateTicket(ctx, &pb.Ticket{Id: "1"}))
+	err = faulty.IndexTicket(ctx, &pb.Ticket{Id: "1"})
+	assert.Equal(codes.Unavailable, status.Code(err))
+	ids, err := service.GetIndexedIDSet(ctx)
+	assert.Nil(err)
+	assert.Contains(ids, "1")
+
+	// Other methods are delayed by the fault of all methods.
+	start := time.Now()
+	ticket, err := faulty.GetTicket(ctx, "1")
+	assert.Nil(err)
+	assert.Equal("1", ticket.GetId())
+	assert.True(time.Since(start) >= 50*time.Millisecond)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = faulty.GetTicket(canceled, "1")
+	assert.Equal(codes.Canceled, status.Code(err))
+}
+
+func TestParseFaults(t *testing.T) {
+	assert := assert.New(t)
+
+	faults, err := ParseFaults([]string{
+		"CreateTicket:latency=50ms,errorRate=0.1,partialRate=0.05",
+		"GetTicket",
+		"*:errorRate=0.5",
+	})
+	assert.Nil(err)
+	assert.Equal(map[string]Fault{
+		"CreateTicket": {Latency: 50 * time.Millisecond, ErrorRate: 0.1, PartialRate: 0.05},
+		"GetTicket":    {},
+		"*":            {ErrorRate: 0.5},
+	}, faults)
+
+	for _, entry := range []string{
+		"CreateTickte:errorRate=1",
+		"CreateTicket:errorRate",
+		"CreateTicket:errorRate=often",
+		"CreateTicket:timeout=1s",
+	} {
+		_, err = ParseFaults([]string{entry})
+		assert.NotNil(err, entry)
+	}
+}
+
+func TestNewInjectsConfiguredFaults(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	cfg.Set("storage.faults", []string{"GetTicket:errorRate=1"})
+	service := New(cfg)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	assert.Nil(service.CreateTicket(ctx, &pb.Ticket{Id: "1"}))
+	_, err := service.GetTicket(ctx, "1")
+	assert.Equal(codes.Unavailable, status.Code(err))
+}
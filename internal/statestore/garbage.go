@@ -0,0 +1,345 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// garbageScanCount is the COUNT hint of the scans over keys and set members
+// while collecting garbage, which bounds how long each command blocks Redis.
+const garbageScanCount = 1000
+
+// removeExpiredLeasesScript removes the expired leases and their owners,
+// returning how many were removed.  KEYS are the ignore list and the lease
+// owners, ARGV is the time before which leases have expired.
+var removeExpiredLeasesScript = redis.NewScript(2, `
+local ids = redis.call("ZRANGEBYSCORE", KEYS[1], "-inf", "(" .. ARGV[1])
+for _, id in ipairs(ids) do
+  redis.call("ZREM", KEYS[1], id)
+  redis.call("HDEL", KEYS[2], id)
+end
+return #ids
+`)
+
+// removeDanglingIndexEntriesScript removes the field index entries of tickets
+// which aren't indexed, returning how many were removed.  KEYS are the field
+// index and the indexed ids, ARGV are the command removing members from the
+// index followed by pairs of members and their ticket ids.
+var removeDanglingIndexEntriesScript = redis.NewScript(2, `
+local removed = 0
+for i = 2, #ARGV, 2 do
+  if redis.call("SISMEMBER", KEYS[2], ARGV[i + 1]) == 0 then
+    removed = removed + redis.call(ARGV[1], KEYS[1], ARGV[i])
+  end
+end
+return removed
+`)
+
+// deleteOrphanedTicketScript deletes the ticket if it's neither indexed nor
+// assigned, returning whether it was deleted.  KEYS are the ticket, its
+// assignment, the indexed ids, its version, its state and the stored tickets
+// counter, ARGV is the ticket id.
+var deleteOrphanedTicketScript = redis.NewScript(6, `
+if redis.call("SISMEMBER", KEYS[3], ARGV[1]) == 1 or redis.call("EXISTS", KEYS[2]) == 1 then
+  return 0
+end
+redis.call("DEL", KEYS[4], KEYS[5])
+local deleted = redis.call("DEL", KEYS[1])
+if deleted == 1 and tonumber(redis.call("GET", KEYS[6]) or "0") > 0 then
+  redis.call("DECR", KEYS[6])
+end
+return deleted
+`)
+
+// CollectGarbage removes expired leases, index entries of missing tickets,
+// tickets which have been orphaned since the previous collection, and
+// backfills which are no longer acknowledged, and corrects the stored tickets
+// counter.
+func (rb *redisBackend) CollectGarbage(ctx context.Context, orphanCandidates map[string]struct{}) (*GarbageCollection, error) {
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	gc := &GarbageCollection{
+		OrphanCandidates: make(map[string]struct{}),
+		TicketStates: map[string]int64{
+			TicketSearching:     0,
+			TicketProposed:      0,
+			TicketAssigned:      0,
+			TicketPendingDelete: 0,
+		},
+	}
+	expiredBefore := rb.clk.Now().Add(-rb.cfg.GetDuration("storage.ignoreListTTL")).UnixNano()
+	for _, keys := range rb.keyspaces() {
+		expired, err := redis.Int64(removeExpiredLeasesScript.Do(redisConn, keys.ignoreList(), keys.leaseOwners(), expiredBefore))
+		if err != nil {
+			redisLogger.WithError(err).Error("failed to remove expired ticket leases")
+			return gc, status.Errorf(codes.Internal, "%v", err)
+		}
+		gc.ExpiredLeases += expired
+
+		// Missing tickets are deindexed first, so that their field index entries
+		// are found dangling right after.
+		if err = rb.deindexMissingTickets(ctx, redisConn, keys, gc); err != nil {
+			return gc, err
+		}
+		if err = removeDanglingIndexEntries(ctx, redisConn, keys, gc); err != nil {
+			return gc, err
+		}
+		if err = deleteOrphanedTickets(ctx, redisConn, keys, orphanCandidates, gc); err != nil {
+			return gc, err
+		}
+	}
+	if err = rb.deleteExpiredBackfills(redisConn, gc); err != nil {
+		return gc, err
+	}
+	if err = rb.recountStoredTickets(redisConn, gc); err != nil {
+		return gc, err
+	}
+	return gc, nil
+}
+
+// recountStoredTickets corrects the stored tickets counter, which misses the
+// tickets expiring while CleanUpExpiredTickets isn't watching.  Tickets created
+// or deleted while counting keep counting, as the counter is adjusted by how
+// far it was off when the count started rather than overwritten.
+func (rb *redisBackend) recountStoredTickets(redisConn redis.Conn, gc *GarbageCollection) error {
+	before, err := redis.Int64(redisConn.Do("GET", rb.keys.storedTickets()))
+	if err != nil && err != redis.ErrNil {
+		redisLogger.WithError(err).Error("failed to get the stored tickets counter")
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	count, err := countTicketKeys(redisConn, rb.keys)
+	if err != nil {
+		redisLogger.WithError(err).Error("failed to count the stored tickets")
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	gc.StoredTicketsDrift = before - count
+	if gc.StoredTicketsDrift == 0 {
+		return nil
+	}
+	if _, err = redisConn.Do("DECRBY", rb.keys.storedTickets(), gc.StoredTicketsDrift); err != nil {
+		redisLogger.WithError(err).Error("failed to correct the stored tickets counter")
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	return nil
+}
+
+// deindexMissingTickets removes the indexed ids whose ticket doesn't exist in
+// any keyspace.
+func (rb *redisBackend) deindexMissingTickets(ctx context.Context, redisConn redis.Conn, keys keyspace, gc *GarbageCollection) error {
+	return scanMembers(ctx, redisConn, "SSCAN", keys.allTickets(), garbageScanCount, func(ids []string) error {
+		for _, id := range ids {
+			if err := redisConn.Send("EXISTS", rb.ticketKeys(id)...); err != nil {
+				return err
+			}
+		}
+		if err := redisConn.Flush(); err != nil {
+			return err
+		}
+		missing := []string{}
+		for _, id := range ids {
+			n, err := redis.Int(redisConn.Receive())
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				missing = append(missing, id)
+			}
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		removed, err := redis.Int64(redisConn.Do("SREM", redis.Args{keys.allTickets()}.AddFlat(missing)...))
+		gc.MissingTickets += removed
+		return err
+	})
+}
+
+// removeDanglingIndexEntries removes the field index entries of tickets which
+// aren't indexed.
+func removeDanglingIndexEntries(ctx context.Context, redisConn redis.Conn, keys keyspace, gc *GarbageCollection) error {
+	return scanKeys(ctx, redisConn, keys.pattern(indexKeyPrefix+"*"), func(indexKeys []string) error {
+		for _, key := range indexKeys {
+			name := strings.TrimPrefix(key, keys.prefix+indexKeyPrefix)
+			scan, remove := "ZSCAN", "ZREM"
+			if strings.HasPrefix(name, "tag:") {
+				scan, remove = "SSCAN", "SREM"
+			}
+			err := scanMembers(ctx, redisConn, scan, key, garbageScanCount, func(members []string) error {
+				args := redis.Args{key, keys.allTickets(), remove}
+				for _, member := range members {
+					id := member
+					if strings.HasPrefix(name, "string:") {
+						id = member[strings.LastIndex(member, "\x00")+1:]
+					}
+					args = args.Add(member, id)
+				}
+				removed, err := redis.Int64(removeDanglingIndexEntriesScript.Do(redisConn, args...))
+				gc.DanglingIndexEntries += removed
+				return err
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// deleteOrphanedTickets deletes the tickets of orphanCandidates which are
+// still neither indexed nor assigned, and records the others in gc along with
+// the lifecycle states of the remaining tickets.
+func deleteOrphanedTickets(ctx context.Context, redisConn redis.Conn, keys keyspace, orphanCandidates map[string]struct{}, gc *GarbageCollection) error {
+	return scanKeys(ctx, redisConn, keys.pattern("*"), func(scanned []string) error {
+		ids := []string{}
+		for _, key := range scanned {
+			if id, ok := keys.ticketID(key); ok {
+				ids = append(ids, id)
+			}
+		}
+
+		for _, id := range ids {
+			if err := redisConn.Send("SISMEMBER", keys.allTickets(), id); err != nil {
+				return err
+			}
+			if err := redisConn.Send("EXISTS", keys.assignment(id)); err != nil {
+				return err
+			}
+			if err := redisConn.Send("ZSCORE", keys.ignoreList(), id); err != nil {
+				return err
+			}
+			if err := redisConn.Send("GET", keys.ticket(id)); err != nil {
+				return err
+			}
+		}
+		if err := redisConn.Flush(); err != nil {
+			return err
+		}
+		orphaned := []string{}
+		for _, id := range ids {
+			indexed, err := redis.Bool(redisConn.Receive())
+			if err != nil {
+				return err
+			}
+			assigned, err := redis.Bool(redisConn.Receive())
+			if err != nil {
+				return err
+			}
+			lease, err := redisConn.Receive()
+			if err != nil {
+				return err
+			}
+			// Keys which don't hold a ticket, eg. those of another installation
+			// sharing the Redis instance, fail to be read or parsed.
+			value, err := redis.Bytes(redisConn.Receive())
+			if err != nil || !isTicket(value, id) {
+				continue
+			}
+			switch {
+			case assigned:
+				gc.TicketStates[TicketAssigned]++
+			case indexed && lease != nil:
+				gc.TicketStates[TicketProposed]++
+			case indexed:
+				gc.TicketStates[TicketSearching]++
+			default:
+				orphaned = append(orphaned, id)
+			}
+		}
+
+		for _, id := range orphaned {
+			if _, ok := orphanCandidates[id]; !ok {
+				gc.OrphanCandidates[id] = struct{}{}
+				gc.TicketStates[TicketPendingDelete]++
+				continue
+			}
+			deleted, err := redis.Int64(deleteOrphanedTicketScript.Do(redisConn, keys.ticket(id), keys.assignment(id), keys.allTickets(), keys.version(id), keys.state(id), keys.storedTickets(), id))
+			if err != nil {
+				return err
+			}
+			gc.OrphanedTickets += deleted
+		}
+		return nil
+	})
+}
+
+func isTicket(value []byte, id string) bool {
+	ticket := &pb.Ticket{}
+	return unmarshalTicket(value, ticket) == nil && ticket.GetId() == id
+}
+
+// scanKeys calls f with each batch of keys matching pattern.
+func scanKeys(ctx context.Context, redisConn redis.Conn, pattern string, f func([]string) error) error {
+	return scan(ctx, redisConn, func(cursor int64) (interface{}, error) {
+		return redisConn.Do("SCAN", cursor, "MATCH", pattern, "COUNT", garbageScanCount)
+	}, f)
+}
+
+// scanMembers calls f with each batch of members of the set or sorted set
+// scanned by the command, count at a time.  Scores of sorted sets are dropped.
+func scanMembers(ctx context.Context, redisConn redis.Conn, cmd string, key string, count int, f func([]string) error) error {
+	return scan(ctx, redisConn, func(cursor int64) (interface{}, error) {
+		return redisConn.Do(cmd, key, cursor, "COUNT", count)
+	}, func(values []string) error {
+		if cmd != "ZSCAN" {
+			return f(values)
+		}
+		members := make([]string, 0, len(values)/2)
+		for i := 0; i < len(values); i += 2 {
+			members = append(members, values[i])
+		}
+		return f(members)
+	})
+}
+
+func scan(ctx context.Context, redisConn redis.Conn, do func(cursor int64) (interface{}, error), f func([]string) error) error {
+	cursor := int64(0)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		reply, err := redis.Values(do(cursor))
+		if err != nil {
+			redisLogger.WithError(err).Error("failed to scan")
+			return status.Errorf(codes.Internal, "%v", err)
+		}
+		var values []string
+		if _, err = redis.Scan(reply, &cursor, &values); err != nil {
+			redisLogger.WithError(err).Error("failed to parse scan reply")
+			return status.Errorf(codes.Internal, "%v", err)
+		}
+		if len(values) > 0 {
+			if err = f(values); err != nil {
+				if _, ok := status.FromError(err); ok {
+					return err
+				}
+				return status.Errorf(codes.Internal, "%v", err)
+			}
+		}
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
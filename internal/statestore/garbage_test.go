@@ -0,0 +1,141 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/clock"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestCollectGarbage(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	clk := clock.NewVirtual(time.Unix(0, 0))
+	service := NewWithClock(cfg, clk)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	indexed := &pb.Ticket{Id: "indexed", SearchFields: &pb.SearchFields{Tags: []string{"beta"}}}
+	assert.Nil(service.CreateTicket(ctx, indexed))
+	assert.Nil(service.IndexTicket(ctx, indexed))
+	// Only indexed, eg. because the ticket expired.
+	missing := &pb.Ticket{
+		Id: "missing",
+		SearchFields: &pb.SearchFields{
+			DoubleArgs: map[string]float64{"mmr": 1},
+			StringArgs: map[string]string{"mode": "ranked"},
+			Tags:       []string{"beta"},
+		},
+	}
+	assert.Nil(service.IndexTicket(ctx, missing))
+	// Deindexed, but the lazy delete never happened.
+	orphaned := &pb.Ticket{Id: "orphaned"}
+	assert.Nil(service.CreateTicket(ctx, orphaned))
+	assigned := &pb.Ticket{Id: "assigned"}
+	assert.Nil(service.CreateTicket(ctx, assigned))
+	_, _, err := service.UpdateAssignments(ctx, []string{assigned.GetId()}, &pb.Assignment{Connection: "1.2.3.4:5678"})
+	assert.Nil(err)
+
+	leaseTickets(t, service, []string{indexed.GetId()})
+	clk.Advance(2 * cfg.GetDuration("storage.ignoreListTTL"))
+
+	gc, err := service.CollectGarbage(ctx, nil)
+	assert.Nil(err)
+	assert.Equal(int64(1), gc.ExpiredLeases)
+	assert.Equal(int64(1), gc.MissingTickets)
+	assert.Equal(int64(3), gc.DanglingIndexEntries)
+	assert.Equal(int64(0), gc.OrphanedTickets)
+	assert.Equal(map[string]struct{}{"orphaned": {}}, gc.OrphanCandidates)
+	assert.Equal(map[string]int64{
+		TicketSearching:     1,
+		TicketProposed:      0,
+		TicketAssigned:      1,
+		TicketPendingDelete: 1,
+	}, gc.TicketStates)
+
+	ids, err := service.GetIndexedIDSet(ctx)
+	assert.Nil(err)
+	assert.Equal(map[string]struct{}{"indexed": {}}, ids)
+	count, err := service.CountTickets(ctx, &pb.Pool{TagPresentFilters: []*pb.TagPresentFilter{{Tag: "beta"}}})
+	assert.Nil(err)
+	assert.Equal(int64(1), count)
+
+	// Orphaned tickets are only deleted if still orphaned on the next
+	// collection.
+	gc, err = service.CollectGarbage(ctx, gc.OrphanCandidates)
+	assert.Nil(err)
+	assert.Equal(int64(1), gc.OrphanedTickets)
+	assert.Empty(gc.OrphanCandidates)
+	assert.Equal(int64(0), gc.TicketStates[TicketPendingDelete])
+
+	leaseTickets(t, service, []string{indexed.GetId()})
+	gc, err = service.CollectGarbage(ctx, nil)
+	assert.Nil(err)
+	assert.Equal(int64(1), gc.TicketStates[TicketProposed])
+	assert.Equal(int64(0), gc.TicketStates[TicketSearching])
+
+	_, err = service.GetTicket(ctx, orphaned.GetId())
+	assert.NotNil(err)
+	for _, id := range []string{indexed.GetId(), assigned.GetId()} {
+		_, err = service.GetTicket(ctx, id)
+		assert.Nil(err)
+	}
+	// Deleting the orphaned ticket freed up its quota.
+	cfg.Set("storage.ticketQuota", 3)
+	assert.Nil(service.CreateTicket(ctx, &pb.Ticket{Id: "created"}))
+	err = service.CreateTicket(ctx, &pb.Ticket{Id: "exceeding"})
+	assert.Equal(codes.ResourceExhausted, status.Convert(err).Code())
+}
+
+func TestCollectGarbageRecountsStoredTickets(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	cfg.Set("storage.ticketQuota", 2)
+	service := New(cfg)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	assert.Nil(service.CreateTickets(ctx, []*pb.Ticket{{Id: "1"}, {Id: "2"}}))
+
+	rb := newRedis(cfg, clock.Real()).(*redisBackend)
+	defer rb.Close()
+	redisConn, err := rb.connect(ctx)
+	assert.Nil(err)
+	defer redisConn.Close()
+	// Expire ticket 1 without anyone watching the expiration.
+	_, err = redisConn.Do("DEL", "1")
+	assert.Nil(err)
+
+	err = service.CreateTicket(ctx, &pb.Ticket{Id: "3"})
+	assert.Equal(codes.ResourceExhausted, status.Convert(err).Code())
+
+	gc, err := service.CollectGarbage(ctx, nil)
+	assert.Nil(err)
+	assert.Equal(int64(1), gc.StoredTicketsDrift)
+	assert.Nil(service.CreateTicket(ctx, &pb.Ticket{Id: "3"}))
+
+	gc, err = service.CollectGarbage(ctx, nil)
+	assert.Nil(err)
+	assert.Equal(int64(0), gc.StoredTicketsDrift)
+}
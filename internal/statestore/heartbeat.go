@@ -0,0 +1,117 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ticketHeartbeats is a sorted set of the ids of tickets which must be kept
+// alive, scored by their last heartbeat.
+const ticketHeartbeats = "ticketHeartbeats"
+
+// recordHeartbeatsScript records a heartbeat for each of the indexed tickets,
+// returning the ids of those which aren't indexed.  KEYS are the heartbeats
+// and the indexed ids, ARGV is the time of the heartbeat followed by the ids.
+var recordHeartbeatsScript = redis.NewScript(2, `
+local missing = {}
+for i = 2, #ARGV do
+  if redis.call("SISMEMBER", KEYS[2], ARGV[i]) == 1 then
+    redis.call("ZADD", KEYS[1], ARGV[1], ARGV[i])
+  else
+    missing[#missing + 1] = ARGV[i]
+  end
+end
+return missing
+`)
+
+// claimLapsedHeartbeatsScript removes up to ARGV[2] heartbeats older than
+// ARGV[1] along with their tickets' ids from the indexed ids, returning the
+// ids.  KEYS are the heartbeats followed by the indexed ids of each keyspace.
+// Claiming them atomically ensures each lapsed ticket is reaped by a single
+// frontend, and that a claimed ticket can't be left indexed.
+var claimLapsedHeartbeatsScript = redis.NewScript(-1, `
+local ids = redis.call("ZRANGEBYSCORE", KEYS[1], "-inf", "(" .. ARGV[1], "LIMIT", 0, ARGV[2])
+for _, id in ipairs(ids) do
+  redis.call("ZREM", KEYS[1], id)
+  for i = 2, #KEYS do
+    redis.call("SREM", KEYS[i], id)
+  end
+end
+return ids
+`)
+
+// RecordHeartbeats records a heartbeat for each of the indexed tickets,
+// returning the ids of those which aren't indexed, eg. because they were
+// assigned, deleted or reaped.
+func (rb *redisBackend) RecordHeartbeats(ctx context.Context, ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	args := redis.Args{rb.keys.ticketHeartbeats(), rb.keys.allTickets(), rb.clk.Now().UnixNano()}.AddFlat(ids)
+	missing, err := redis.Strings(recordHeartbeatsScript.Do(redisConn, args...))
+	if err != nil {
+		redisLogger.WithFields(logrus.Fields{
+			"ids":   ids,
+			"error": err.Error(),
+		}).Error("failed to record ticket heartbeats")
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	return missing, nil
+}
+
+// DeindexLapsedTickets deindexes up to limit tickets whose last heartbeat is
+// older than timeout, returning their ids.  Tickets which never had a
+// heartbeat recorded are left alone.  The tickets leave the pool as their
+// heartbeats are claimed, so failing to remove them from the field indices
+// afterwards only leaves dangling entries for CollectGarbage to remove.
+func (rb *redisBackend) DeindexLapsedTickets(ctx context.Context, timeout time.Duration, limit int) ([]string, error) {
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	args := redis.Args{1 + len(rb.keyspaces()), rb.keys.ticketHeartbeats()}
+	for _, keys := range rb.keyspaces() {
+		args = args.Add(keys.allTickets())
+	}
+	args = args.Add(rb.clk.Now().Add(-timeout).UnixNano(), limit)
+	ids, err := redis.Strings(claimLapsedHeartbeatsScript.Do(redisConn, args...))
+	if err != nil {
+		redisLogger.WithError(err).Error("failed to claim lapsed ticket heartbeats")
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	if err = rb.DeindexTickets(ctx, ids); err != nil {
+		redisLogger.WithFields(logrus.Fields{
+			"ids":   ids,
+			"error": err.Error(),
+		}).Warning("failed to remove the lapsed tickets from the field indices, leaving them to garbage collection")
+	}
+	return ids, nil
+}
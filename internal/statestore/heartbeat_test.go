@@ -0,0 +1,106 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+	"open-match.dev/open-match/internal/clock"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestDeindexLapsedTickets(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	clk := clock.NewVirtual(time.Unix(0, 0))
+	service := NewWithClock(cfg, clk)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	tickets := []*pb.Ticket{{Id: "alive"}, {Id: "lapsed"}, {Id: "untracked"}, {Id: "other"}}
+	assert.Nil(service.CreateTickets(ctx, tickets))
+	assert.Nil(service.IndexTickets(ctx, tickets))
+
+	missing, err := service.RecordHeartbeats(ctx, []string{"alive", "lapsed", "other", "unknown"})
+	assert.Nil(err)
+	assert.Equal([]string{"unknown"}, missing)
+
+	clk.Advance(time.Minute)
+	missing, err = service.RecordHeartbeats(ctx, []string{"alive"})
+	assert.Nil(err)
+	assert.Empty(missing)
+	clk.Advance(time.Second)
+
+	// Batches are bounded by the limit.
+	ids, err := service.DeindexLapsedTickets(ctx, 30*time.Second, 1)
+	assert.Nil(err)
+	assert.Len(ids, 1)
+	reaped := ids
+	ids, err = service.DeindexLapsedTickets(ctx, 30*time.Second, 10)
+	assert.Nil(err)
+	assert.ElementsMatch([]string{"lapsed", "other"}, append(reaped, ids...))
+
+	indexed, err := service.GetIndexedIDSet(ctx)
+	assert.Nil(err)
+	assert.Equal(map[string]struct{}{"alive": {}, "untracked": {}}, indexed)
+
+	// Reaped tickets are claimed once, and no longer accept heartbeats.
+	ids, err = service.DeindexLapsedTickets(ctx, 30*time.Second, 10)
+	assert.Nil(err)
+	assert.Empty(ids)
+	missing, err = service.RecordHeartbeats(ctx, []string{"alive", "lapsed"})
+	assert.Nil(err)
+	assert.Equal([]string{"lapsed"}, missing)
+}
+
+func TestClaimLapsedHeartbeatsDeindexes(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	service := New(cfg)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	tickets := []*pb.Ticket{{Id: "1"}, {Id: "2"}}
+	assert.Nil(service.CreateTickets(ctx, tickets))
+	assert.Nil(service.IndexTickets(ctx, tickets))
+	_, err := service.RecordHeartbeats(ctx, []string{"1", "2"})
+	assert.Nil(err)
+
+	rb := newRedis(cfg, clock.Real()).(*redisBackend)
+	defer rb.Close()
+	redisConn, err := rb.connect(ctx)
+	assert.Nil(err)
+	defer redisConn.Close()
+
+	// The claimed tickets leave the pool along with their heartbeats, even if
+	// deindexing them is never completed.
+	ids, err := redis.Strings(claimLapsedHeartbeatsScript.Do(redisConn, 2, rb.keys.ticketHeartbeats(), rb.keys.allTickets(), time.Now().Add(time.Hour).UnixNano(), 1))
+	assert.Nil(err)
+	assert.Len(ids, 1)
+
+	indexed, err := service.GetIndexedIDSet(ctx)
+	assert.Nil(err)
+	assert.Len(indexed, 1)
+	assert.NotContains(indexed, ids[0])
+	n, err := redis.Int(redisConn.Do("ZCARD", rb.keys.ticketHeartbeats()))
+	assert.Nil(err)
+	assert.Equal(1, n)
+}
@@ -0,0 +1,96 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// idempotencyKeyPrefix is prepended to a client supplied idempotency key to
+// get the key holding the id of the ticket created with it.
+const idempotencyKeyPrefix = "idempotency:"
+
+// claimIdempotencyKeyScript sets the key to the ticket id for ttl ms, unless
+// it's already set, returning the ticket id it was set to before.  KEYS are
+// the idempotency key, ARGV are the ticket id and the ttl.
+var claimIdempotencyKeyScript = redis.NewScript(1, `
+local id = redis.call("GET", KEYS[1])
+if id then
+  return id
+end
+redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+return false
+`)
+
+// releaseIdempotencyKeyScript deletes the key if it's still set to the
+// ticket id.  KEYS are the idempotency key, ARGV is the ticket id.
+var releaseIdempotencyKeyScript = redis.NewScript(1, `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+  return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// ClaimIdempotencyKey records that the ticket id is created with the key for
+// ttl.  Returns the id of the ticket the key was claimed for before, if any,
+// in which case the key is left untouched.
+func (rb *redisBackend) ClaimIdempotencyKey(ctx context.Context, key string, id string, ttl time.Duration) (string, error) {
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	claimedID, err := redis.String(claimIdempotencyKeyScript.Do(redisConn, rb.keys.idempotency(key), id, ttl.Milliseconds()))
+	if err == redis.ErrNil {
+		return "", nil
+	}
+	if err != nil {
+		redisLogger.WithFields(logrus.Fields{
+			"key":   key,
+			"id":    id,
+			"error": err.Error(),
+		}).Error("failed to claim the idempotency key")
+		return "", status.Errorf(codes.Internal, "%v", err)
+	}
+	return claimedID, nil
+}
+
+// ReleaseIdempotencyKey deletes the key if it's still claimed for the ticket
+// id, eg. because the ticket couldn't be created after all.
+func (rb *redisBackend) ReleaseIdempotencyKey(ctx context.Context, key string, id string) error {
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	_, err = releaseIdempotencyKeyScript.Do(redisConn, rb.keys.idempotency(key), id)
+	if err != nil {
+		redisLogger.WithFields(logrus.Fields{
+			"key":   key,
+			"id":    id,
+			"error": err.Error(),
+		}).Error("failed to release the idempotency key")
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	return nil
+}
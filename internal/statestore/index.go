@@ -0,0 +1,269 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/gomodule/redigo/redis"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// indexKeyPrefix prefixes the keys of the search field indices.  Indexed
+// tickets are added to a sorted set per double arg scored by the arg's value, a
+// sorted set per string arg holding "value\x00id" members so that equal values
+// can be counted lexicographically, a set per tag, and a sorted set scored by
+// their create time in nanoseconds.
+const indexKeyPrefix = "index:"
+
+func stringIndexMember(value, id string) string {
+	return value + "\x00" + id
+}
+
+// formatScore formats v as a sorted set score or range bound.
+func formatScore(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+inf"
+	case math.IsInf(v, -1):
+		return "-inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+// sendFieldIndexAdd pipelines adding the ticket's search fields to the field
+// indices.
+func sendFieldIndexAdd(redisConn redis.Conn, keys keyspace, ticket *pb.Ticket) error {
+	id := ticket.GetId()
+	s := ticket.GetSearchFields()
+	for arg, v := range s.GetDoubleArgs() {
+		// NaN can't be scored, and never matches a range anyway.
+		if math.IsNaN(v) {
+			continue
+		}
+		if err := redisConn.Send("ZADD", keys.doubleIndex(arg), formatScore(v), id); err != nil {
+			return err
+		}
+	}
+	for arg, v := range s.GetStringArgs() {
+		if err := redisConn.Send("ZADD", keys.stringIndex(arg), 0, stringIndexMember(v, id)); err != nil {
+			return err
+		}
+	}
+	for _, tag := range s.GetTags() {
+		if err := redisConn.Send("SADD", keys.tagIndex(tag), id); err != nil {
+			return err
+		}
+	}
+	if ticket.GetCreateTime() != nil {
+		ct, err := ptypes.Timestamp(ticket.GetCreateTime())
+		if err != nil {
+			return err
+		}
+		if err := redisConn.Send("ZADD", keys.createTimeIndex(), ct.UnixNano(), id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendFieldIndexRemove pipelines removing the ticket's search fields from the
+// field indices.
+func sendFieldIndexRemove(redisConn redis.Conn, keys keyspace, ticket *pb.Ticket) error {
+	id := ticket.GetId()
+	s := ticket.GetSearchFields()
+	for arg := range s.GetDoubleArgs() {
+		if err := redisConn.Send("ZREM", keys.doubleIndex(arg), id); err != nil {
+			return err
+		}
+	}
+	for arg, v := range s.GetStringArgs() {
+		if err := redisConn.Send("ZREM", keys.stringIndex(arg), stringIndexMember(v, id)); err != nil {
+			return err
+		}
+	}
+	for _, tag := range s.GetTags() {
+		if err := redisConn.Send("SREM", keys.tagIndex(tag), id); err != nil {
+			return err
+		}
+	}
+	if ticket.GetCreateTime() != nil {
+		if err := redisConn.Send("ZREM", keys.createTimeIndex(), id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// countTicketsScript counts the indexed tickets matching every filter.  A
+// single filter is answered from its index's cardinality, otherwise the members
+// of the smallest index are checked against the others.  KEYS are the ticket
+// index followed by a field index per filter, ARGV holds a kind, lower and
+// upper bound triple per filter.  String filters pass the value as the lower
+// bound, tag filters ignore the bounds, and time filters exclude theirs.
+var countTicketsScript = redis.NewScript(-1, `
+local n = #KEYS - 1
+if n == 0 then
+  return redis.call("SCARD", KEYS[1])
+end
+
+local function bound(v)
+  if v == "-inf" then
+    return -math.huge
+  elseif v == "+inf" then
+    return math.huge
+  end
+  return tonumber(v)
+end
+
+local function card(i)
+  local kind, key, a, b = ARGV[3 * i - 2], KEYS[i + 1], ARGV[3 * i - 1], ARGV[3 * i]
+  if kind == "double" then
+    return redis.call("ZCOUNT", key, a, b)
+  elseif kind == "time" then
+    return redis.call("ZCOUNT", key, "(" .. a, "(" .. b)
+  elseif kind == "string" then
+    return redis.call("ZLEXCOUNT", key, "[" .. a .. "\0", "(" .. a .. "\1")
+  end
+  return redis.call("SCARD", key)
+end
+
+local function members(i)
+  local kind, key, a, b = ARGV[3 * i - 2], KEYS[i + 1], ARGV[3 * i - 1], ARGV[3 * i]
+  if kind == "double" then
+    return redis.call("ZRANGEBYSCORE", key, a, b)
+  elseif kind == "time" then
+    return redis.call("ZRANGEBYSCORE", key, "(" .. a, "(" .. b)
+  elseif kind == "string" then
+    local ids = redis.call("ZRANGEBYLEX", key, "[" .. a .. "\0", "(" .. a .. "\1")
+    for j, m in ipairs(ids) do
+      ids[j] = string.sub(m, #a + 2)
+    end
+    return ids
+  end
+  return redis.call("SMEMBERS", key)
+end
+
+local function contains(i, id)
+  local kind, key, a, b = ARGV[3 * i - 2], KEYS[i + 1], ARGV[3 * i - 1], ARGV[3 * i]
+  if kind == "double" then
+    local score = redis.call("ZSCORE", key, id)
+    if not score then
+      return false
+    end
+    score = tonumber(score)
+    return score >= bound(a) and score <= bound(b)
+  elseif kind == "time" then
+    local score = redis.call("ZSCORE", key, id)
+    if not score then
+      return false
+    end
+    score = tonumber(score)
+    return score > bound(a) and score < bound(b)
+  elseif kind == "string" then
+    return redis.call("ZSCORE", key, a .. "\0" .. id) ~= false
+  end
+  return redis.call("SISMEMBER", key, id) == 1
+end
+
+local smallest, count = 1, card(1)
+for i = 2, n do
+  local c = card(i)
+  if c < count then
+    smallest, count = i, c
+  end
+end
+if n == 1 or count == 0 then
+  return count
+end
+
+count = 0
+for _, id in ipairs(members(smallest)) do
+  local matches = true
+  for i = 1, n do
+    if i ~= smallest and not contains(i, id) then
+      matches = false
+      break
+    end
+  end
+  if matches then
+    count = count + 1
+  end
+end
+return count
+`)
+
+// CountTickets returns the number of indexed Tickets matching all filters of
+// the pool, including Tickets in the ignore list.  Tickets stored without the
+// key prefix aren't counted.  Create times are indexed as doubles, so tickets
+// created within a microsecond of a created_before or created_after bound may
+// be miscounted.  Pools with a filter expression aren't supported.
+func (rb *redisBackend) CountTickets(ctx context.Context, pool *pb.Pool) (int64, error) {
+	if pool.GetFilterExpression() != nil {
+		return 0, status.Error(codes.InvalidArgument, "pools with a filter expression can't be counted from the indices")
+	}
+	keys := redis.Args{rb.keys.allTickets()}
+	var argv redis.Args
+	for _, f := range pool.GetDoubleRangeFilters() {
+		if math.IsNaN(f.GetMin()) || math.IsNaN(f.GetMax()) {
+			return 0, nil
+		}
+		keys = append(keys, rb.keys.doubleIndex(f.GetDoubleArg()))
+		argv = append(argv, "double", formatScore(f.GetMin()), formatScore(f.GetMax()))
+	}
+	for _, f := range pool.GetStringEqualsFilters() {
+		keys = append(keys, rb.keys.stringIndex(f.GetStringArg()))
+		argv = append(argv, "string", f.GetValue(), "")
+	}
+	for _, f := range pool.GetTagPresentFilters() {
+		keys = append(keys, rb.keys.tagIndex(f.GetTag()))
+		argv = append(argv, "tag", "", "")
+	}
+	if pool.GetCreatedBefore() != nil || pool.GetCreatedAfter() != nil {
+		after, before := "-inf", "+inf"
+		if t, err := ptypes.Timestamp(pool.GetCreatedAfter()); err == nil {
+			after = strconv.FormatInt(t.UnixNano(), 10)
+		}
+		if t, err := ptypes.Timestamp(pool.GetCreatedBefore()); err == nil {
+			before = strconv.FormatInt(t.UnixNano(), 10)
+		}
+		keys = append(keys, rb.keys.createTimeIndex())
+		argv = append(argv, "time", after, before)
+	}
+
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	args := redis.Args{len(keys)}.Add(keys...).Add(argv...)
+	count, err := redis.Int64(countTicketsScript.Do(redisConn, args...))
+	if err != nil {
+		redisLogger.WithFields(logrus.Fields{
+			"pool":  pool.GetName(),
+			"error": err.Error(),
+		}).Error("failed to count tickets")
+		return 0, status.Errorf(codes.Internal, "%v", err)
+	}
+	return count, nil
+}
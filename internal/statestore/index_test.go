@@ -0,0 +1,175 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/filter"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestCountTickets(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	service := New(cfg)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	created := func(i int) *timestamp.Timestamp {
+		return &timestamp.Timestamp{Seconds: 1600000000 + int64(i)}
+	}
+
+	var tickets []*pb.Ticket
+	for i := 0; i < 20; i++ {
+		ticket := &pb.Ticket{
+			Id: fmt.Sprintf("ticket%d", i),
+			SearchFields: &pb.SearchFields{
+				DoubleArgs: map[string]float64{"level": float64(i)},
+				StringArgs: map[string]string{"mode": []string{"ranked", "casual"}[i%2]},
+			},
+		}
+		if i%3 == 0 {
+			ticket.SearchFields.Tags = []string{"beta"}
+		}
+		if i == 19 {
+			ticket.SearchFields.DoubleArgs["level"] = math.Inf(1)
+		}
+		// Tickets created before create times were recorded have none.
+		if i != 4 {
+			ticket.CreateTime = created(i)
+		}
+		tickets = append(tickets, ticket)
+		assert.Nil(service.CreateTicket(ctx, ticket))
+		assert.Nil(service.IndexTicket(ctx, ticket))
+	}
+	// Not indexed, so never counted.
+	assert.Nil(service.CreateTicket(ctx, &pb.Ticket{Id: "unindexed", SearchFields: &pb.SearchFields{Tags: []string{"beta"}}}))
+
+	pools := []*pb.Pool{
+		{},
+		{DoubleRangeFilters: []*pb.DoubleRangeFilter{{DoubleArg: "level", Min: 5, Max: 10}}},
+		{DoubleRangeFilters: []*pb.DoubleRangeFilter{{DoubleArg: "level", Min: 15, Max: math.Inf(1)}}},
+		{DoubleRangeFilters: []*pb.DoubleRangeFilter{{DoubleArg: "level", Min: math.NaN(), Max: 10}}},
+		{DoubleRangeFilters: []*pb.DoubleRangeFilter{{DoubleArg: "missing", Min: 0, Max: 10}}},
+		{StringEqualsFilters: []*pb.StringEqualsFilter{{StringArg: "mode", Value: "ranked"}}},
+		{StringEqualsFilters: []*pb.StringEqualsFilter{{StringArg: "mode", Value: "rank"}}},
+		{TagPresentFilters: []*pb.TagPresentFilter{{Tag: "beta"}}},
+		{
+			DoubleRangeFilters:  []*pb.DoubleRangeFilter{{DoubleArg: "level", Min: 2, Max: 17}},
+			StringEqualsFilters: []*pb.StringEqualsFilter{{StringArg: "mode", Value: "casual"}},
+			TagPresentFilters:   []*pb.TagPresentFilter{{Tag: "beta"}},
+		},
+		{
+			DoubleRangeFilters: []*pb.DoubleRangeFilter{
+				{DoubleArg: "level", Min: 0, Max: 12},
+				{DoubleArg: "level", Min: 8, Max: 20},
+			},
+		},
+		{CreatedBefore: created(10)},
+		{CreatedAfter: created(10)},
+		{CreatedAfter: created(2), CreatedBefore: created(12)},
+		{
+			StringEqualsFilters: []*pb.StringEqualsFilter{{StringArg: "mode", Value: "ranked"}},
+			CreatedAfter:        created(5),
+		},
+	}
+
+	expectCounts := func() {
+		for i, pool := range pools {
+			var want int64
+			for _, ticket := range tickets {
+				if filter.InPool(ticket, pool) {
+					want++
+				}
+			}
+			got, err := service.CountTickets(ctx, pool)
+			assert.Nil(err)
+			assert.Equal(want, got, "pool %d", i)
+		}
+	}
+	expectCounts()
+
+	// Deindexed tickets are no longer counted.
+	assert.Nil(service.DeindexTicket(ctx, "ticket6"))
+	assert.Nil(service.DeindexTicket(ctx, "ticket7"))
+	tickets = append(tickets[:6], tickets[8:]...)
+	expectCounts()
+
+	// As are tickets deindexed in a batch, which may include missing tickets.
+	assert.Nil(service.DeindexTickets(ctx, []string{"ticket9", "ticket10", "ticket11", "missing"}))
+	tickets = append(tickets[:7], tickets[10:]...)
+	expectCounts()
+	ids, err := service.GetIndexedIDSet(ctx)
+	assert.Nil(err)
+	assert.Len(ids, len(tickets))
+
+	// Rewritten search fields are counted in their new indices.
+	_, _, err = service.RewriteTickets(ctx, func(ticket *pb.Ticket) bool {
+		if ticket.GetSearchFields().GetStringArgs()["mode"] != "casual" {
+			return false
+		}
+		ticket.SearchFields.StringArgs["mode"] = "ranked"
+		ticket.SearchFields.Tags = append(ticket.SearchFields.Tags, "migrated")
+		return true
+	})
+	assert.Nil(err)
+	for _, ticket := range tickets {
+		if ticket.SearchFields.StringArgs["mode"] == "casual" {
+			ticket.SearchFields.StringArgs["mode"] = "ranked"
+			ticket.SearchFields.Tags = append(ticket.SearchFields.Tags, "migrated")
+		}
+	}
+	pools = append(pools, &pb.Pool{TagPresentFilters: []*pb.TagPresentFilter{{Tag: "migrated"}}})
+	expectCounts()
+}
+
+func TestIndexSnapshot(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	service := New(cfg)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	// Snapshots of an empty index exist too.
+	assert.Nil(service.TakeIndexSnapshot(ctx, "empty", time.Minute))
+	ids, err := service.GetIndexSnapshot(ctx, "empty")
+	assert.Nil(err)
+	assert.Empty(ids)
+
+	tickets := []*pb.Ticket{{Id: "a"}, {Id: "b"}}
+	assert.Nil(service.CreateTickets(ctx, tickets))
+	assert.Nil(service.IndexTickets(ctx, tickets))
+	assert.Nil(service.TakeIndexSnapshot(ctx, "token", time.Minute))
+
+	// Later changes to the index aren't seen through the snapshot.
+	assert.Nil(service.DeindexTicket(ctx, "a"))
+	ids, err = service.GetIndexSnapshot(ctx, "token")
+	assert.Nil(err)
+	assert.Equal(map[string]struct{}{"a": {}, "b": {}}, ids)
+
+	_, err = service.GetIndexSnapshot(ctx, "unknown")
+	assert.Equal(codes.NotFound, status.Code(err))
+}
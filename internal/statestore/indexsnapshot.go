@@ -0,0 +1,91 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// indexSnapshotKeyPrefix is prepended to a snapshot's token to get the key of
+// the set holding the ids indexed when it was taken.
+const indexSnapshotKeyPrefix = "indexSnapshot:"
+
+// takeIndexSnapshotScript copies the indexed ids to the snapshot set, which
+// expires after ttl ms.  The empty id is added, since Redis doesn't keep empty
+// sets, so that snapshots taken while nothing is indexed still exist.  KEYS are
+// the snapshot set and the indexed ids, ARGV is the ttl.
+var takeIndexSnapshotScript = redis.NewScript(2, `
+redis.call("SUNIONSTORE", KEYS[1], KEYS[2])
+redis.call("SADD", KEYS[1], "")
+redis.call("PEXPIRE", KEYS[1], ARGV[1])
+return 1
+`)
+
+// TakeIndexSnapshot copies the ids of the indexed tickets under token, for
+// ttl.
+func (rb *redisBackend) TakeIndexSnapshot(ctx context.Context, token string, ttl time.Duration) error {
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	_, err = takeIndexSnapshotScript.Do(redisConn, rb.keys.indexSnapshot(token), rb.keys.allTickets(), ttl.Milliseconds())
+	if err != nil {
+		redisLogger.WithFields(logrus.Fields{
+			"token": token,
+			"error": err.Error(),
+		}).Error("failed to snapshot the indexed ids")
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	return nil
+}
+
+// GetIndexSnapshot returns the ids of the snapshot taken under token.  Fails
+// with NotFound if it doesn't exist or expired.  The snapshot is read from the
+// primary, since a replica may not have it yet.
+func (rb *redisBackend) GetIndexSnapshot(ctx context.Context, token string) (map[string]struct{}, error) {
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	members, err := redis.Strings(redisConn.Do("SMEMBERS", rb.keys.indexSnapshot(token)))
+	if err != nil {
+		redisLogger.WithFields(logrus.Fields{
+			"token": token,
+			"error": err.Error(),
+		}).Error("failed to get the snapshot of the indexed ids")
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	if len(members) == 0 {
+		return nil, status.Errorf(codes.NotFound, "snapshot %q doesn't exist", token)
+	}
+
+	ids := make(map[string]struct{}, len(members)-1)
+	for _, id := range members {
+		if id != "" {
+			ids[id] = struct{}{}
+		}
+	}
+	return ids, nil
+}
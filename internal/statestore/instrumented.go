@@ -16,26 +16,142 @@ package statestore
 
 import (
 	"context"
+	"time"
 
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
 	"go.opencensus.io/trace"
+	"google.golang.org/grpc/status"
 	"open-match.dev/open-match/internal/telemetry"
 	"open-match.dev/open-match/pkg/pb"
 )
 
 var (
+	// outcomeKey tags latencies with the status code name of the call, eg. OK or Unavailable.
+	outcomeKey = tag.MustNewKey("outcome")
+
+	mStateStoreCreateTicketLatencyMs                = telemetry.HistogramWithBounds("statestore/createticketlatency", "latency of CreateTicket calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreCreateTicketsLatencyMs               = telemetry.HistogramWithBounds("statestore/createticketslatency", "latency of CreateTickets calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreCreateTicketsWithExpirationLatencyMs = telemetry.HistogramWithBounds("statestore/createticketswithexpirationlatency", "latency of CreateTicketsWithExpiration calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreGetTicketLatencyMs                   = telemetry.HistogramWithBounds("statestore/getticketlatency", "latency of GetTicket calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreGetTicketWithVersionLatencyMs        = telemetry.HistogramWithBounds("statestore/getticketwithversionlatency", "latency of GetTicketWithVersion calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreGetTicketStateLatencyMs              = telemetry.HistogramWithBounds("statestore/getticketstatelatency", "latency of GetTicketState calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreGetTicketStatusLatencyMs             = telemetry.HistogramWithBounds("statestore/getticketstatuslatency", "latency of GetTicketStatus calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreCompareAndSetTicketLatencyMs         = telemetry.HistogramWithBounds("statestore/compareandsetticketlatency", "latency of CompareAndSetTicket calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreDeleteTicketLatencyMs                = telemetry.HistogramWithBounds("statestore/deleteticketlatency", "latency of DeleteTicket calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreDeleteTicketsLatencyMs               = telemetry.HistogramWithBounds("statestore/deleteticketslatency", "latency of DeleteTickets calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreClaimIdempotencyKeyLatencyMs         = telemetry.HistogramWithBounds("statestore/claimidempotencykeylatency", "latency of ClaimIdempotencyKey calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreReleaseIdempotencyKeyLatencyMs       = telemetry.HistogramWithBounds("statestore/releaseidempotencykeylatency", "latency of ReleaseIdempotencyKey calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreIndexTicketLatencyMs                 = telemetry.HistogramWithBounds("statestore/indexticketlatency", "latency of IndexTicket calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreIndexTicketsLatencyMs                = telemetry.HistogramWithBounds("statestore/indexticketslatency", "latency of IndexTickets calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreDeindexTicketLatencyMs               = telemetry.HistogramWithBounds("statestore/deindexticketlatency", "latency of DeindexTicket calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreDeindexTicketsLatencyMs              = telemetry.HistogramWithBounds("statestore/deindexticketslatency", "latency of DeindexTickets calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreRecordHeartbeatsLatencyMs            = telemetry.HistogramWithBounds("statestore/recordheartbeatslatency", "latency of RecordHeartbeats calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreDeindexLapsedTicketsLatencyMs        = telemetry.HistogramWithBounds("statestore/deindexlapsedticketslatency", "latency of DeindexLapsedTickets calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreCountTicketsLatencyMs                = telemetry.HistogramWithBounds("statestore/countticketslatency", "latency of CountTickets calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreGetTicketsLatencyMs                  = telemetry.HistogramWithBounds("statestore/getticketslatency", "latency of GetTickets calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreGetIndexedIDSetLatencyMs             = telemetry.HistogramWithBounds("statestore/getindexedidsetlatency", "latency of GetIndexedIDSet calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreTakeIndexSnapshotLatencyMs           = telemetry.HistogramWithBounds("statestore/takeindexsnapshotlatency", "latency of TakeIndexSnapshot calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreGetIndexSnapshotLatencyMs            = telemetry.HistogramWithBounds("statestore/getindexsnapshotlatency", "latency of GetIndexSnapshot calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreScanIndexedIDsLatencyMs              = telemetry.HistogramWithBounds("statestore/scanindexedidslatency", "latency of ScanIndexedIDs calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreUpdateAssignmentsLatencyMs           = telemetry.HistogramWithBounds("statestore/updateassignmentslatency", "latency of UpdateAssignments calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreUpdateAssignmentGroupsLatencyMs      = telemetry.HistogramWithBounds("statestore/updateassignmentgroupslatency", "latency of UpdateAssignmentGroups calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreRequeueFailedAssignmentsLatencyMs    = telemetry.HistogramWithBounds("statestore/requeuefailedassignmentslatency", "latency of RequeueFailedAssignments calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreReleaseAllTicketsLatencyMs           = telemetry.HistogramWithBounds("statestore/releaseallticketslatency", "latency of ReleaseAllTickets calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreRewriteTicketsLatencyMs              = telemetry.HistogramWithBounds("statestore/rewriteticketslatency", "latency of RewriteTickets calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreRewriteTicketsByIDLatencyMs          = telemetry.HistogramWithBounds("statestore/rewriteticketsbyidlatency", "latency of RewriteTicketsByID calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreExportTicketsLatencyMs               = telemetry.HistogramWithBounds("statestore/exportticketslatency", "latency of ExportTickets calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreImportTicketsLatencyMs               = telemetry.HistogramWithBounds("statestore/importticketslatency", "latency of ImportTickets calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreGetTicketsRevisionLatencyMs          = telemetry.HistogramWithBounds("statestore/getticketsrevisionlatency", "latency of GetTicketsRevision calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreRecordProfileLatencyMs               = telemetry.HistogramWithBounds("statestore/recordprofilelatency", "latency of RecordProfile calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreGetProfilesLatencyMs                 = telemetry.HistogramWithBounds("statestore/getprofileslatency", "latency of GetProfiles calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStorePublishComponentLatencyMs            = telemetry.HistogramWithBounds("statestore/publishcomponentlatency", "latency of PublishComponent calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreGetComponentsLatencyMs               = telemetry.HistogramWithBounds("statestore/getcomponentslatency", "latency of GetComponents calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreSetFeatureGateOverrideLatencyMs      = telemetry.HistogramWithBounds("statestore/setfeaturegateoverridelatency", "latency of SetFeatureGateOverride calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreDeleteFeatureGateOverrideLatencyMs   = telemetry.HistogramWithBounds("statestore/deletefeaturegateoverridelatency", "latency of DeleteFeatureGateOverride calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreGetFeatureGateOverridesLatencyMs     = telemetry.HistogramWithBounds("statestore/getfeaturegateoverrideslatency", "latency of GetFeatureGateOverrides calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreCollectGarbageLatencyMs              = telemetry.HistogramWithBounds("statestore/collectgarbagelatency", "latency of CollectGarbage calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreAcquireTicketLeaseLatencyMs          = telemetry.HistogramWithBounds("statestore/acquireticketleaselatency", "latency of AcquireTicketLease calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreExtendLeaseLatencyMs                 = telemetry.HistogramWithBounds("statestore/extendleaselatency", "latency of ExtendLease calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreReleaseTicketLeaseLatencyMs          = telemetry.HistogramWithBounds("statestore/releaseticketleaselatency", "latency of ReleaseTicketLease calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreRecordCycleLatencyMs                 = telemetry.HistogramWithBounds("statestore/recordcyclelatency", "latency of RecordCycle calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreFinishCycleLatencyMs                 = telemetry.HistogramWithBounds("statestore/finishcyclelatency", "latency of FinishCycle calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreReleaseAbandonedCyclesLatencyMs      = telemetry.HistogramWithBounds("statestore/releaseabandonedcycleslatency", "latency of ReleaseAbandonedCycles calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreDeleteTicketsFromIgnoreListLatencyMs = telemetry.HistogramWithBounds("statestore/deleteticketsfromignorelistlatency", "latency of DeleteTicketsFromIgnoreList calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreGetStorageUsageLatencyMs             = telemetry.HistogramWithBounds("statestore/getstorageusagelatency", "latency of GetStorageUsage calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreCreateBackfillLatencyMs              = telemetry.HistogramWithBounds("statestore/createbackfilllatency", "latency of CreateBackfill calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreGetBackfillLatencyMs                 = telemetry.HistogramWithBounds("statestore/getbackfilllatency", "latency of GetBackfill calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreUpdateBackfillLatencyMs              = telemetry.HistogramWithBounds("statestore/updatebackfilllatency", "latency of UpdateBackfill calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreDeleteBackfillLatencyMs              = telemetry.HistogramWithBounds("statestore/deletebackfilllatency", "latency of DeleteBackfill calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreAcknowledgeBackfillLatencyMs         = telemetry.HistogramWithBounds("statestore/acknowledgebackfilllatency", "latency of AcknowledgeBackfill calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreGetExpiredBackfillIDsLatencyMs       = telemetry.HistogramWithBounds("statestore/getexpiredbackfillidslatency", "latency of GetExpiredBackfillIDs calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreIndexBackfillLatencyMs               = telemetry.HistogramWithBounds("statestore/indexbackfilllatency", "latency of IndexBackfill calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreDeindexBackfillLatencyMs             = telemetry.HistogramWithBounds("statestore/deindexbackfilllatency", "latency of DeindexBackfill calls", "ms", telemetry.HistogramBounds, outcomeKey)
+	mStateStoreGetIndexedBackfillsLatencyMs         = telemetry.HistogramWithBounds("statestore/getindexedbackfillslatency", "latency of GetIndexedBackfills calls", "ms", telemetry.HistogramBounds, outcomeKey)
+
 	mStateStoreCreateTicketCount               = telemetry.Counter("statestore/createticketcount", "number of tickets created")
 	mStateStoreGetTicketCount                  = telemetry.Counter("statestore/getticketcount", "number of tickets retrieved")
+	mStateStoreGetTicketWithVersionCount       = telemetry.Counter("statestore/getticketwithversioncount", "number of versioned tickets retrieved")
+	mStateStoreGetTicketStateCount             = telemetry.Counter("statestore/getticketstatecount", "number of ticket states retrieved")
+	mStateStoreGetTicketStatusCount            = telemetry.Counter("statestore/getticketstatuscount", "number of ticket statuses retrieved")
+	mStateStoreCompareAndSetTicketCount        = telemetry.Counter("statestore/compareandsetticketcount", "number of tickets compared and set")
 	mStateStoreDeleteTicketCount               = telemetry.Counter("statestore/deleteticketcount", "number of tickets deleted")
+	mStateStoreDeleteTicketsCount              = telemetry.Counter("statestore/deleteticketscount", "number of bulk ticket deletions")
+	mStateStoreClaimIdempotencyKeyCount        = telemetry.Counter("statestore/claimidempotencykeycount", "number of idempotency keys claimed")
+	mStateStoreReleaseIdempotencyKeyCount      = telemetry.Counter("statestore/releaseidempotencykeycount", "number of idempotency keys released")
+	mStateStoreCreateTicketsCount              = telemetry.Counter("statestore/createticketscount", "number of bulk ticket creations")
+	mStateStoreCreateExpiringTicketsCount      = telemetry.Counter("statestore/createticketswithexpirationcount", "number of ticket creations with an expiration")
+	mStateStoreIndexTicketsCount               = telemetry.Counter("statestore/indexticketscount", "number of bulk ticket indexings")
 	mStateStoreIndexTicketCount                = telemetry.Counter("statestore/indexticketcount", "number of tickets indexed")
 	mStateStoreDeindexTicketCount              = telemetry.Counter("statestore/deindexticketcount", "number of tickets deindexed")
+	mStateStoreDeindexTicketsCount             = telemetry.Counter("statestore/deindexticketscount", "number of bulk ticket deindexings")
+	mStateStoreRecordHeartbeatsCount           = telemetry.Counter("statestore/recordheartbeatscount", "number of bulk ticket heartbeats")
+	mStateStoreDeindexLapsedTicketsCount       = telemetry.Counter("statestore/deindexlapsedticketscount", "number of lapsed ticket deindexings")
+	mStateStoreCountTicketsCount               = telemetry.Counter("statestore/countticketscount", "number of pool ticket counts")
 	mStateStoreGetTicketsCount                 = telemetry.Counter("statestore/getticketscount", "number of bulk ticket retrievals")
 	mStateStoreGetIndexedIDSetCount            = telemetry.Counter("statestore/getindexedidsetcount", "number of bulk indexed id retrievals")
+	mStateStoreTakeIndexSnapshotCount          = telemetry.Counter("statestore/takeindexsnapshotcount", "number of indexed id snapshots taken")
+	mStateStoreGetIndexSnapshotCount           = telemetry.Counter("statestore/getindexsnapshotcount", "number of indexed id snapshot retrievals")
+	mStateStoreScanIndexedIDsCount             = telemetry.Counter("statestore/scanindexedidscount", "number of indexed id scans")
 	mStateStoreUpdateAssignmentsCount          = telemetry.Counter("statestore/updateassignmentcount", "number of tickets assigned")
+	mStateStoreUpdateAssignmentGroupsCount     = telemetry.Counter("statestore/updateassignmentgroupscount", "number of grouped ticket assignments")
+	mStateStoreRequeueFailedAssignmentsCount   = telemetry.Counter("statestore/requeuefailedassignmentscount", "number of bulk failed assignment requeues")
+	mStateStoreReleaseAllTicketsCount          = telemetry.Counter("statestore/releaseallticketscount", "number of ignore list clears")
 	mStateStoreGetAssignmentsCount             = telemetry.Counter("statestore/getassignmentscount", "number of ticket assigned retrieved")
-	mStateStoreAddTicketsToIgnoreListCount     = telemetry.Counter("statestore/addticketstoignorelistcount", "number of tickets moved to ignore list")
+	mStateStoreAcquireTicketLeaseCount         = telemetry.Counter("statestore/acquireticketleasecount", "number of tickets leased")
+	mStateStoreExtendLeaseCount                = telemetry.Counter("statestore/extendleasecount", "number of ticket leases extended")
+	mStateStoreReleaseTicketLeaseCount         = telemetry.Counter("statestore/releaseticketleasecount", "number of ticket leases released")
+	mStateStoreReleaseAbandonedCyclesCount     = telemetry.Counter("statestore/releaseabandonedcyclescount", "number of abandoned synchronizer cycles released")
 	mStateStoreDeleteTicketFromIgnoreListCount = telemetry.Counter("statestore/deleteticketfromignorelistcount", "number of tickets removed from ignore list")
+	mStateStoreGetStorageUsageCount            = telemetry.Counter("statestore/getstorageusagecount", "number of storage usage reports")
+	mStateStoreRewriteTicketsCount             = telemetry.Counter("statestore/rewriteticketscount", "number of tickets rewritten")
+	mStateStoreRewriteTicketsByIDCount         = telemetry.Counter("statestore/rewriteticketsbyidcount", "number of tickets rewritten by id")
+	mStateStoreExportTicketsCount              = telemetry.Counter("statestore/exportticketscount", "number of tickets exported")
+	mStateStoreImportTicketsCount              = telemetry.Counter("statestore/importticketscount", "number of tickets imported")
+	mStateStoreRecordProfileCount              = telemetry.Counter("statestore/recordprofilecount", "number of profiles recorded in the profile registry")
+	mStateStoreGetProfilesCount                = telemetry.Counter("statestore/getprofilescount", "number of profile registry retrievals")
+	mStateStorePublishComponentCount           = telemetry.Counter("statestore/publishcomponentcount", "number of components published to the component registry")
+	mStateStoreGetComponentsCount              = telemetry.Counter("statestore/getcomponentscount", "number of component registry retrievals")
+	mStateStoreSetFeatureGateOverrideCount     = telemetry.Counter("statestore/setfeaturegateoverridecount", "number of feature gate overrides set")
+	mStateStoreDeleteFeatureGateOverrideCount  = telemetry.Counter("statestore/deletefeaturegateoverridecount", "number of feature gate overrides deleted")
+	mStateStoreCollectGarbageCount             = telemetry.Counter("statestore/collectgarbagecount", "number of garbage collections")
+	mStateStoreGetFeatureGateOverridesCount    = telemetry.Counter("statestore/getfeaturegateoverridescount", "number of feature gate override retrievals")
+	mStateStoreCreateBackfillCount             = telemetry.Counter("statestore/createbackfillcount", "number of backfills created")
+	mStateStoreGetBackfillCount                = telemetry.Counter("statestore/getbackfillcount", "number of backfills retrieved")
+	mStateStoreUpdateBackfillCount             = telemetry.Counter("statestore/updatebackfillcount", "number of backfills updated")
+	mStateStoreDeleteBackfillCount             = telemetry.Counter("statestore/deletebackfillcount", "number of backfills deleted")
+	mStateStoreAcknowledgeBackfillCount        = telemetry.Counter("statestore/acknowledgebackfillcount", "number of backfill acknowledgments")
+	mStateStoreGetExpiredBackfillIDsCount      = telemetry.Counter("statestore/getexpiredbackfillidscount", "number of expired backfill retrievals")
+	mStateStoreIndexBackfillCount              = telemetry.Counter("statestore/indexbackfillcount", "number of backfills indexed")
+	mStateStoreDeindexBackfillCount            = telemetry.Counter("statestore/deindexbackfillcount", "number of backfills deindexed")
+	mStateStoreGetIndexedBackfillsCount        = telemetry.Counter("statestore/getindexedbackfillscount", "number of indexed backfill retrievals")
 )
 
+// recordLatency records the time since start, tagged with the outcome of err.
+func recordLatency(ctx context.Context, m *stats.Int64Measure, start time.Time, err error) {
+	telemetry.RecordNUnitMeasurement(ctx, m, time.Since(start).Milliseconds(), tag.Upsert(outcomeKey, status.Code(err).String()))
+}
+
 // instrumentedService is a wrapper for a statestore service that provides instrumentation (metrics and tracing) of the database.
 type instrumentedService struct {
 	s Service
@@ -57,7 +173,32 @@ func (is *instrumentedService) CreateTicket(ctx context.Context, ticket *pb.Tick
 	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.CreateTicket")
 	defer span.End()
 	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreCreateTicketCount)
-	return is.s.CreateTicket(ctx, ticket)
+	start := time.Now()
+	err := is.s.CreateTicket(ctx, ticket)
+	recordLatency(ctx, mStateStoreCreateTicketLatencyMs, start, err)
+	return err
+}
+
+// CreateTickets creates multiple Tickets in a single round trip.
+func (is *instrumentedService) CreateTickets(ctx context.Context, tickets []*pb.Ticket) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.CreateTickets")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreCreateTicketsCount)
+	start := time.Now()
+	err := is.s.CreateTickets(ctx, tickets)
+	recordLatency(ctx, mStateStoreCreateTicketsLatencyMs, start, err)
+	return err
+}
+
+// CreateTicketsWithExpiration creates Tickets which expire after expiration.
+func (is *instrumentedService) CreateTicketsWithExpiration(ctx context.Context, tickets []*pb.Ticket, expiration time.Duration) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.CreateTicketsWithExpiration")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreCreateExpiringTicketsCount)
+	start := time.Now()
+	err := is.s.CreateTicketsWithExpiration(ctx, tickets, expiration)
+	recordLatency(ctx, mStateStoreCreateTicketsWithExpirationLatencyMs, start, err)
+	return err
 }
 
 // GetTicket gets the Ticket with the specified id from state storage. This method fails if the Ticket does not exist.
@@ -65,7 +206,54 @@ func (is *instrumentedService) GetTicket(ctx context.Context, id string) (*pb.Ti
 	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.GetTicket")
 	defer span.End()
 	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreGetTicketCount)
-	return is.s.GetTicket(ctx, id)
+	start := time.Now()
+	ticket, err := is.s.GetTicket(ctx, id)
+	recordLatency(ctx, mStateStoreGetTicketLatencyMs, start, err)
+	return ticket, err
+}
+
+// GetTicketWithVersion gets the Ticket with the specified id along with its version.
+func (is *instrumentedService) GetTicketWithVersion(ctx context.Context, id string) (*pb.Ticket, int64, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.GetTicketWithVersion")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreGetTicketWithVersionCount)
+	start := time.Now()
+	ticket, version, err := is.s.GetTicketWithVersion(ctx, id)
+	recordLatency(ctx, mStateStoreGetTicketWithVersionLatencyMs, start, err)
+	return ticket, version, err
+}
+
+// GetTicketState returns the lifecycle state of the Ticket.
+func (is *instrumentedService) GetTicketState(ctx context.Context, id string) (pb.Ticket_State, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.GetTicketState")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreGetTicketStateCount)
+	start := time.Now()
+	state, err := is.s.GetTicketState(ctx, id)
+	recordLatency(ctx, mStateStoreGetTicketStateLatencyMs, start, err)
+	return state, err
+}
+
+// GetTicketStatus returns where the Ticket is in the matchmaking queue.
+func (is *instrumentedService) GetTicketStatus(ctx context.Context, id string) (*TicketStatus, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.GetTicketStatus")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreGetTicketStatusCount)
+	start := time.Now()
+	ticketStatus, err := is.s.GetTicketStatus(ctx, id)
+	recordLatency(ctx, mStateStoreGetTicketStatusLatencyMs, start, err)
+	return ticketStatus, err
+}
+
+// CompareAndSetTicket overwrites the Ticket if its version is still version.
+func (is *instrumentedService) CompareAndSetTicket(ctx context.Context, ticket *pb.Ticket, version int64) (int64, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.CompareAndSetTicket")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreCompareAndSetTicketCount)
+	start := time.Now()
+	newVersion, err := is.s.CompareAndSetTicket(ctx, ticket, version)
+	recordLatency(ctx, mStateStoreCompareAndSetTicketLatencyMs, start, err)
+	return newVersion, err
 }
 
 // DeleteTicket removes the Ticket with the specified id from state storage.
@@ -73,7 +261,43 @@ func (is *instrumentedService) DeleteTicket(ctx context.Context, id string) erro
 	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.DeleteTicket")
 	defer span.End()
 	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreDeleteTicketCount)
-	return is.s.DeleteTicket(ctx, id)
+	start := time.Now()
+	err := is.s.DeleteTicket(ctx, id)
+	recordLatency(ctx, mStateStoreDeleteTicketLatencyMs, start, err)
+	return err
+}
+
+// DeleteTickets removes the Tickets with the specified ids from state storage in a single round trip.
+func (is *instrumentedService) DeleteTickets(ctx context.Context, ids []string) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.DeleteTickets")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreDeleteTicketsCount)
+	start := time.Now()
+	err := is.s.DeleteTickets(ctx, ids)
+	recordLatency(ctx, mStateStoreDeleteTicketsLatencyMs, start, err)
+	return err
+}
+
+// ClaimIdempotencyKey records that the Ticket is created with the idempotency key.
+func (is *instrumentedService) ClaimIdempotencyKey(ctx context.Context, key string, id string, ttl time.Duration) (string, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.ClaimIdempotencyKey")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreClaimIdempotencyKeyCount)
+	start := time.Now()
+	claimedID, err := is.s.ClaimIdempotencyKey(ctx, key, id, ttl)
+	recordLatency(ctx, mStateStoreClaimIdempotencyKeyLatencyMs, start, err)
+	return claimedID, err
+}
+
+// ReleaseIdempotencyKey deletes the idempotency key if it's still claimed for the Ticket.
+func (is *instrumentedService) ReleaseIdempotencyKey(ctx context.Context, key string, id string) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.ReleaseIdempotencyKey")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreReleaseIdempotencyKeyCount)
+	start := time.Now()
+	err := is.s.ReleaseIdempotencyKey(ctx, key, id)
+	recordLatency(ctx, mStateStoreReleaseIdempotencyKeyLatencyMs, start, err)
+	return err
 }
 
 // IndexTicket indexes the Ticket id for the configured index fields.
@@ -81,7 +305,21 @@ func (is *instrumentedService) IndexTicket(ctx context.Context, ticket *pb.Ticke
 	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.IndexTicket")
 	defer span.End()
 	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreIndexTicketCount)
-	return is.s.IndexTicket(ctx, ticket)
+	start := time.Now()
+	err := is.s.IndexTicket(ctx, ticket)
+	recordLatency(ctx, mStateStoreIndexTicketLatencyMs, start, err)
+	return err
+}
+
+// IndexTickets adds multiple tickets to the index in a single round trip.
+func (is *instrumentedService) IndexTickets(ctx context.Context, tickets []*pb.Ticket) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.IndexTickets")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreIndexTicketsCount)
+	start := time.Now()
+	err := is.s.IndexTickets(ctx, tickets)
+	recordLatency(ctx, mStateStoreIndexTicketsLatencyMs, start, err)
+	return err
 }
 
 // DeindexTicket removes the indexing for the specified Ticket. Only the indexes are removed but the Ticket continues to exist.
@@ -89,7 +327,54 @@ func (is *instrumentedService) DeindexTicket(ctx context.Context, id string) err
 	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.DeindexTicket")
 	defer span.End()
 	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreDeindexTicketCount)
-	return is.s.DeindexTicket(ctx, id)
+	start := time.Now()
+	err := is.s.DeindexTicket(ctx, id)
+	recordLatency(ctx, mStateStoreDeindexTicketLatencyMs, start, err)
+	return err
+}
+
+// DeindexTickets removes the indexing for the specified Tickets in a single transaction.
+func (is *instrumentedService) DeindexTickets(ctx context.Context, ids []string) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.DeindexTickets")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreDeindexTicketsCount)
+	start := time.Now()
+	err := is.s.DeindexTickets(ctx, ids)
+	recordLatency(ctx, mStateStoreDeindexTicketsLatencyMs, start, err)
+	return err
+}
+
+// RecordHeartbeats records a heartbeat for each of the indexed Tickets.
+func (is *instrumentedService) RecordHeartbeats(ctx context.Context, ids []string) ([]string, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.RecordHeartbeats")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreRecordHeartbeatsCount)
+	start := time.Now()
+	missing, err := is.s.RecordHeartbeats(ctx, ids)
+	recordLatency(ctx, mStateStoreRecordHeartbeatsLatencyMs, start, err)
+	return missing, err
+}
+
+// DeindexLapsedTickets deindexes up to limit Tickets whose last heartbeat is older than timeout.
+func (is *instrumentedService) DeindexLapsedTickets(ctx context.Context, timeout time.Duration, limit int) ([]string, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.DeindexLapsedTickets")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreDeindexLapsedTicketsCount)
+	start := time.Now()
+	ids, err := is.s.DeindexLapsedTickets(ctx, timeout, limit)
+	recordLatency(ctx, mStateStoreDeindexLapsedTicketsLatencyMs, start, err)
+	return ids, err
+}
+
+// CountTickets returns the number of indexed Tickets matching all filters of the pool.
+func (is *instrumentedService) CountTickets(ctx context.Context, pool *pb.Pool) (int64, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.CountTickets")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreCountTicketsCount)
+	start := time.Now()
+	count, err := is.s.CountTickets(ctx, pool)
+	recordLatency(ctx, mStateStoreCountTicketsLatencyMs, start, err)
+	return count, err
 }
 
 // GetTickets returns multiple tickets from storage.  Missing tickets are
@@ -98,7 +383,10 @@ func (is *instrumentedService) GetTickets(ctx context.Context, ids []string) ([]
 	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.GetTickets")
 	defer span.End()
 	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreGetTicketsCount)
-	return is.s.GetTickets(ctx, ids)
+	start := time.Now()
+	tickets, err := is.s.GetTickets(ctx, ids)
+	recordLatency(ctx, mStateStoreGetTicketsLatencyMs, start, err)
+	return tickets, err
 }
 
 // GetIndexedIds returns the ids of all tickets currently indexed.
@@ -106,18 +394,77 @@ func (is *instrumentedService) GetIndexedIDSet(ctx context.Context) (map[string]
 	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.GetIndexedIDSet")
 	defer span.End()
 	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreGetIndexedIDSetCount)
-	return is.s.GetIndexedIDSet(ctx)
+	start := time.Now()
+	ids, err := is.s.GetIndexedIDSet(ctx)
+	recordLatency(ctx, mStateStoreGetIndexedIDSetLatencyMs, start, err)
+	return ids, err
 }
 
-// UpdateAssignments update the match assignments for the input ticket ids.
-// This function guarantees if any of the input ids does not exists, the state of the storage service won't be altered.
-// However, since Redis does not support transaction roll backs (see https://redis.io/topics/transactions), some of the
-// assignment fields might be partially updated if this function encounters an error halfway through the execution.
-func (is *instrumentedService) UpdateAssignments(ctx context.Context, ids []string, assignment *pb.Assignment) error {
+// TakeIndexSnapshot copies the ids of all tickets currently indexed under token.
+func (is *instrumentedService) TakeIndexSnapshot(ctx context.Context, token string, ttl time.Duration) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.TakeIndexSnapshot")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreTakeIndexSnapshotCount)
+	start := time.Now()
+	err := is.s.TakeIndexSnapshot(ctx, token, ttl)
+	recordLatency(ctx, mStateStoreTakeIndexSnapshotLatencyMs, start, err)
+	return err
+}
+
+// GetIndexSnapshot returns the ids copied by TakeIndexSnapshot under token.
+func (is *instrumentedService) GetIndexSnapshot(ctx context.Context, token string) (map[string]struct{}, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.GetIndexSnapshot")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreGetIndexSnapshotCount)
+	start := time.Now()
+	ids, err := is.s.GetIndexSnapshot(ctx, token)
+	recordLatency(ctx, mStateStoreGetIndexSnapshotLatencyMs, start, err)
+	return ids, err
+}
+
+// ScanIndexedIDs calls f with pages of the ids of all tickets currently indexed.
+func (is *instrumentedService) ScanIndexedIDs(ctx context.Context, f func([]string) error) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.ScanIndexedIDs")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreScanIndexedIDsCount)
+	start := time.Now()
+	err := is.s.ScanIndexedIDs(ctx, f)
+	recordLatency(ctx, mStateStoreScanIndexedIDsLatencyMs, start, err)
+	return err
+}
+
+// UpdateAssignments update the match assignments for the input ticket ids, and returns the ids which don't exist,
+// and those which were already assigned.
+func (is *instrumentedService) UpdateAssignments(ctx context.Context, ids []string, assignment *pb.Assignment) ([]string, []string, error) {
 	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.UpdateAssignments")
 	defer span.End()
 	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreUpdateAssignmentsCount)
-	return is.s.UpdateAssignments(ctx, ids, assignment)
+	start := time.Now()
+	notFound, alreadyAssigned, err := is.s.UpdateAssignments(ctx, ids, assignment)
+	recordLatency(ctx, mStateStoreUpdateAssignmentsLatencyMs, start, err)
+	return notFound, alreadyAssigned, err
+}
+
+// UpdateAssignmentGroups sets the assignment of each group on its tickets.
+func (is *instrumentedService) UpdateAssignmentGroups(ctx context.Context, groups []*pb.AssignmentGroup) ([]string, []string, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.UpdateAssignmentGroups")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreUpdateAssignmentGroupsCount)
+	start := time.Now()
+	notFound, alreadyAssigned, err := is.s.UpdateAssignmentGroups(ctx, groups)
+	recordLatency(ctx, mStateStoreUpdateAssignmentGroupsLatencyMs, start, err)
+	return notFound, alreadyAssigned, err
+}
+
+// RequeueFailedAssignments clears the assignments of the Tickets and indexes them again.
+func (is *instrumentedService) RequeueFailedAssignments(ctx context.Context, ids []string, maxFailures int) (int64, []string, []string, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.RequeueFailedAssignments")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreRequeueFailedAssignmentsCount)
+	start := time.Now()
+	requeued, notFound, exhausted, err := is.s.RequeueFailedAssignments(ctx, ids, maxFailures)
+	recordLatency(ctx, mStateStoreRequeueFailedAssignmentsLatencyMs, start, err)
+	return requeued, notFound, exhausted, err
 }
 
 // GetAssignments returns the assignment associated with the input ticket id
@@ -130,18 +477,350 @@ func (is *instrumentedService) GetAssignments(ctx context.Context, id string, ca
 	})
 }
 
-// AddTicketsToIgnoreList appends new proposed tickets to the proposed sorted set with current timestamp
-func (is *instrumentedService) AddTicketsToIgnoreList(ctx context.Context, ids []string) error {
-	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.AddTicketsToIgnoreList")
+// RewriteTickets calls rewrite on every Ticket in state storage, and saves the Tickets for which it returns true.
+func (is *instrumentedService) RewriteTickets(ctx context.Context, rewrite func(*pb.Ticket) bool) (int64, int64, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.RewriteTickets")
+	defer span.End()
+	start := time.Now()
+	scanned, rewritten, err := is.s.RewriteTickets(ctx, rewrite)
+	recordLatency(ctx, mStateStoreRewriteTicketsLatencyMs, start, err)
+	telemetry.RecordNUnitMeasurement(ctx, mStateStoreRewriteTicketsCount, rewritten)
+	return scanned, rewritten, err
+}
+
+// RewriteTicketsByID calls rewrite on each of the Tickets with the given ids, and saves the Tickets for which it returns true.
+func (is *instrumentedService) RewriteTicketsByID(ctx context.Context, ids []string, rewrite func(*pb.Ticket) bool) (int64, []string, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.RewriteTicketsByID")
+	defer span.End()
+	start := time.Now()
+	rewritten, notFound, err := is.s.RewriteTicketsByID(ctx, ids, rewrite)
+	recordLatency(ctx, mStateStoreRewriteTicketsByIDLatencyMs, start, err)
+	telemetry.RecordNUnitMeasurement(ctx, mStateStoreRewriteTicketsByIDCount, rewritten)
+	return rewritten, notFound, err
+}
+
+// ExportTickets calls f with pages of snapshots of every Ticket in state storage.
+func (is *instrumentedService) ExportTickets(ctx context.Context, f func([]*pb.TicketSnapshot) error) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.ExportTickets")
+	defer span.End()
+	start := time.Now()
+	err := is.s.ExportTickets(ctx, func(snapshots []*pb.TicketSnapshot) error {
+		telemetry.RecordNUnitMeasurement(ctx, mStateStoreExportTicketsCount, int64(len(snapshots)))
+		return f(snapshots)
+	})
+	recordLatency(ctx, mStateStoreExportTicketsLatencyMs, start, err)
+	return err
+}
+
+// ImportTickets saves the Tickets of the snapshots, and indexes those which were indexed.
+func (is *instrumentedService) ImportTickets(ctx context.Context, snapshots []*pb.TicketSnapshot) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.ImportTickets")
 	defer span.End()
-	defer telemetry.RecordNUnitMeasurement(ctx, mStateStoreAddTicketsToIgnoreListCount, int64(len(ids)))
-	return is.s.AddTicketsToIgnoreList(ctx, ids)
+	start := time.Now()
+	err := is.s.ImportTickets(ctx, snapshots)
+	recordLatency(ctx, mStateStoreImportTicketsLatencyMs, start, err)
+	if err == nil {
+		telemetry.RecordNUnitMeasurement(ctx, mStateStoreImportTicketsCount, int64(len(snapshots)))
+	}
+	return err
 }
 
-// DeleteTicketsFromIgnoreList deletes tickets from the proposed sorted set
+// GetTicketsRevision returns a number which changes whenever RewriteTickets modifies Tickets.
+func (is *instrumentedService) GetTicketsRevision(ctx context.Context) (int64, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.GetTicketsRevision")
+	defer span.End()
+	start := time.Now()
+	revision, err := is.s.GetTicketsRevision(ctx)
+	recordLatency(ctx, mStateStoreGetTicketsRevisionLatencyMs, start, err)
+	return revision, err
+}
+
+// RecordProfile saves the profile in the profile registry, marking it as recently used.
+func (is *instrumentedService) RecordProfile(ctx context.Context, profile *pb.MatchProfile) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.RecordProfile")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreRecordProfileCount)
+	start := time.Now()
+	err := is.s.RecordProfile(ctx, profile)
+	recordLatency(ctx, mStateStoreRecordProfileLatencyMs, start, err)
+	return err
+}
+
+// GetProfiles returns the profiles recently recorded in the profile registry.
+func (is *instrumentedService) GetProfiles(ctx context.Context) ([]*pb.MatchProfile, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.GetProfiles")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreGetProfilesCount)
+	start := time.Now()
+	profiles, err := is.s.GetProfiles(ctx)
+	recordLatency(ctx, mStateStoreGetProfilesLatencyMs, start, err)
+	return profiles, err
+}
+
+// PublishComponent saves the build version and config digest of a running component.
+func (is *instrumentedService) PublishComponent(ctx context.Context, component *ComponentInfo) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.PublishComponent")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStorePublishComponentCount)
+	start := time.Now()
+	err := is.s.PublishComponent(ctx, component)
+	recordLatency(ctx, mStateStorePublishComponentLatencyMs, start, err)
+	return err
+}
+
+// GetComponents returns the components recently published with PublishComponent.
+func (is *instrumentedService) GetComponents(ctx context.Context) ([]*ComponentInfo, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.GetComponents")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreGetComponentsCount)
+	start := time.Now()
+	components, err := is.s.GetComponents(ctx)
+	recordLatency(ctx, mStateStoreGetComponentsLatencyMs, start, err)
+	return components, err
+}
+
+// SetFeatureGateOverride saves the override, replacing any override of the same gate and instance.
+func (is *instrumentedService) SetFeatureGateOverride(ctx context.Context, override *pb.FeatureGateOverride) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.SetFeatureGateOverride")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreSetFeatureGateOverrideCount)
+	start := time.Now()
+	err := is.s.SetFeatureGateOverride(ctx, override)
+	recordLatency(ctx, mStateStoreSetFeatureGateOverrideLatencyMs, start, err)
+	return err
+}
+
+// DeleteFeatureGateOverride removes the override of the gate for the instance, if any.
+func (is *instrumentedService) DeleteFeatureGateOverride(ctx context.Context, gate string, instance string) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.DeleteFeatureGateOverride")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreDeleteFeatureGateOverrideCount)
+	start := time.Now()
+	err := is.s.DeleteFeatureGateOverride(ctx, gate, instance)
+	recordLatency(ctx, mStateStoreDeleteFeatureGateOverrideLatencyMs, start, err)
+	return err
+}
+
+// GetFeatureGateOverrides returns all feature gate overrides.
+func (is *instrumentedService) GetFeatureGateOverrides(ctx context.Context) ([]*pb.FeatureGateOverride, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.GetFeatureGateOverrides")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreGetFeatureGateOverridesCount)
+	start := time.Now()
+	overrides, err := is.s.GetFeatureGateOverrides(ctx)
+	recordLatency(ctx, mStateStoreGetFeatureGateOverridesLatencyMs, start, err)
+	return overrides, err
+}
+
+// CollectGarbage removes expired leases, index entries of missing tickets, and orphaned tickets.
+func (is *instrumentedService) CollectGarbage(ctx context.Context, orphanCandidates map[string]struct{}) (*GarbageCollection, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.CollectGarbage")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreCollectGarbageCount)
+	start := time.Now()
+	collection, err := is.s.CollectGarbage(ctx, orphanCandidates)
+	recordLatency(ctx, mStateStoreCollectGarbageLatencyMs, start, err)
+	return collection, err
+}
+
+// AcquireTicketLease leases the tickets to owner, returning the ids of the tickets leased to another owner.
+func (is *instrumentedService) AcquireTicketLease(ctx context.Context, owner string, ids []string) ([]string, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.AcquireTicketLease")
+	defer span.End()
+	defer telemetry.RecordNUnitMeasurement(ctx, mStateStoreAcquireTicketLeaseCount, int64(len(ids)))
+	start := time.Now()
+	held, err := is.s.AcquireTicketLease(ctx, owner, ids)
+	recordLatency(ctx, mStateStoreAcquireTicketLeaseLatencyMs, start, err)
+	return held, err
+}
+
+// ExtendLease restarts the leases owner holds on the tickets, returning the ids of the tickets it doesn't hold.
+func (is *instrumentedService) ExtendLease(ctx context.Context, owner string, ids []string) ([]string, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.ExtendLease")
+	defer span.End()
+	defer telemetry.RecordNUnitMeasurement(ctx, mStateStoreExtendLeaseCount, int64(len(ids)))
+	start := time.Now()
+	missing, err := is.s.ExtendLease(ctx, owner, ids)
+	recordLatency(ctx, mStateStoreExtendLeaseLatencyMs, start, err)
+	return missing, err
+}
+
+// ReleaseTicketLease releases the leases owner holds on the tickets.
+func (is *instrumentedService) ReleaseTicketLease(ctx context.Context, owner string, ids []string) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.ReleaseTicketLease")
+	defer span.End()
+	defer telemetry.RecordNUnitMeasurement(ctx, mStateStoreReleaseTicketLeaseCount, int64(len(ids)))
+	start := time.Now()
+	err := is.s.ReleaseTicketLease(ctx, owner, ids)
+	recordLatency(ctx, mStateStoreReleaseTicketLeaseLatencyMs, start, err)
+	return err
+}
+
+// DeleteTicketsFromIgnoreList releases the leases on the tickets, whoever owns them.
 func (is *instrumentedService) DeleteTicketsFromIgnoreList(ctx context.Context, ids []string) error {
 	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.DeleteTicketsFromIgnoreList")
 	defer span.End()
 	defer telemetry.RecordNUnitMeasurement(ctx, mStateStoreDeleteTicketFromIgnoreListCount, int64(len(ids)))
-	return is.s.DeleteTicketsFromIgnoreList(ctx, ids)
+	start := time.Now()
+	err := is.s.DeleteTicketsFromIgnoreList(ctx, ids)
+	recordLatency(ctx, mStateStoreDeleteTicketsFromIgnoreListLatencyMs, start, err)
+	return err
+}
+
+// CleanUpExpiredTickets removes Tickets from the index and the ignore list as soon as they expire, until ctx is done.
+// It runs for the lifetime of the caller, so isn't measured.
+func (is *instrumentedService) CleanUpExpiredTickets(ctx context.Context, cleaned func(id string)) error {
+	return is.s.CleanUpExpiredTickets(ctx, cleaned)
+}
+
+// RecordCycle records that the synchronizer cycle is in flight.
+func (is *instrumentedService) RecordCycle(ctx context.Context, cycleID string) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.RecordCycle")
+	defer span.End()
+	start := time.Now()
+	err := is.s.RecordCycle(ctx, cycleID)
+	recordLatency(ctx, mStateStoreRecordCycleLatencyMs, start, err)
+	return err
+}
+
+// FinishCycle records that the synchronizer cycle is done with its leases.
+func (is *instrumentedService) FinishCycle(ctx context.Context, cycleID string) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.FinishCycle")
+	defer span.End()
+	start := time.Now()
+	err := is.s.FinishCycle(ctx, cycleID)
+	recordLatency(ctx, mStateStoreFinishCycleLatencyMs, start, err)
+	return err
+}
+
+// ReleaseAbandonedCycles releases the leases of the cycles in flight since before startedBefore.
+func (is *instrumentedService) ReleaseAbandonedCycles(ctx context.Context, startedBefore time.Time) ([]string, int, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.ReleaseAbandonedCycles")
+	defer span.End()
+	start := time.Now()
+	cycles, released, err := is.s.ReleaseAbandonedCycles(ctx, startedBefore)
+	recordLatency(ctx, mStateStoreReleaseAbandonedCyclesLatencyMs, start, err)
+	telemetry.RecordNUnitMeasurement(ctx, mStateStoreReleaseAbandonedCyclesCount, int64(len(cycles)))
+	return cycles, released, err
+}
+
+// ReleaseAllTickets empties the ignore list.
+func (is *instrumentedService) ReleaseAllTickets(ctx context.Context) (int, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.ReleaseAllTickets")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreReleaseAllTicketsCount)
+	start := time.Now()
+	released, err := is.s.ReleaseAllTickets(ctx)
+	recordLatency(ctx, mStateStoreReleaseAllTicketsLatencyMs, start, err)
+	return released, err
+}
+
+// GetStorageUsage reports the number of tickets and approximate memory used in state storage.
+func (is *instrumentedService) GetStorageUsage(ctx context.Context, sampleSize int) (*pb.StorageUsage, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.GetStorageUsage")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreGetStorageUsageCount)
+	start := time.Now()
+	usage, err := is.s.GetStorageUsage(ctx, sampleSize)
+	recordLatency(ctx, mStateStoreGetStorageUsageLatencyMs, start, err)
+	return usage, err
+}
+
+// CreateBackfill creates a new Backfill in state storage, along with the ids of its Tickets.
+func (is *instrumentedService) CreateBackfill(ctx context.Context, backfill *pb.Backfill, ticketIDs []string) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.CreateBackfill")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreCreateBackfillCount)
+	start := time.Now()
+	err := is.s.CreateBackfill(ctx, backfill, ticketIDs)
+	recordLatency(ctx, mStateStoreCreateBackfillLatencyMs, start, err)
+	return err
+}
+
+// GetBackfill gets the Backfill with the specified id, and the ids of its Tickets.
+func (is *instrumentedService) GetBackfill(ctx context.Context, id string) (*pb.Backfill, []string, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.GetBackfill")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreGetBackfillCount)
+	start := time.Now()
+	backfill, ticketIDs, err := is.s.GetBackfill(ctx, id)
+	recordLatency(ctx, mStateStoreGetBackfillLatencyMs, start, err)
+	return backfill, ticketIDs, err
+}
+
+// UpdateBackfill overwrites the Backfill and the ids of its Tickets.
+func (is *instrumentedService) UpdateBackfill(ctx context.Context, backfill *pb.Backfill, ticketIDs []string) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.UpdateBackfill")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreUpdateBackfillCount)
+	start := time.Now()
+	err := is.s.UpdateBackfill(ctx, backfill, ticketIDs)
+	recordLatency(ctx, mStateStoreUpdateBackfillLatencyMs, start, err)
+	return err
+}
+
+// DeleteBackfill removes the Backfill from state storage.
+func (is *instrumentedService) DeleteBackfill(ctx context.Context, id string) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.DeleteBackfill")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreDeleteBackfillCount)
+	start := time.Now()
+	err := is.s.DeleteBackfill(ctx, id)
+	recordLatency(ctx, mStateStoreDeleteBackfillLatencyMs, start, err)
+	return err
+}
+
+// AcknowledgeBackfill records that the game server of the Backfill still wants it filled.
+func (is *instrumentedService) AcknowledgeBackfill(ctx context.Context, id string) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.AcknowledgeBackfill")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreAcknowledgeBackfillCount)
+	start := time.Now()
+	err := is.s.AcknowledgeBackfill(ctx, id)
+	recordLatency(ctx, mStateStoreAcknowledgeBackfillLatencyMs, start, err)
+	return err
+}
+
+// GetExpiredBackfillIDs returns the ids of the Backfills which are no longer acknowledged.
+func (is *instrumentedService) GetExpiredBackfillIDs(ctx context.Context) ([]string, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.GetExpiredBackfillIDs")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreGetExpiredBackfillIDsCount)
+	start := time.Now()
+	ids, err := is.s.GetExpiredBackfillIDs(ctx)
+	recordLatency(ctx, mStateStoreGetExpiredBackfillIDsLatencyMs, start, err)
+	return ids, err
+}
+
+// IndexBackfill makes the Backfill available to be filled.
+func (is *instrumentedService) IndexBackfill(ctx context.Context, backfill *pb.Backfill) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.IndexBackfill")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreIndexBackfillCount)
+	start := time.Now()
+	err := is.s.IndexBackfill(ctx, backfill)
+	recordLatency(ctx, mStateStoreIndexBackfillLatencyMs, start, err)
+	return err
+}
+
+// DeindexBackfill removes the Backfill from the index.
+func (is *instrumentedService) DeindexBackfill(ctx context.Context, id string) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.DeindexBackfill")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreDeindexBackfillCount)
+	start := time.Now()
+	err := is.s.DeindexBackfill(ctx, id)
+	recordLatency(ctx, mStateStoreDeindexBackfillLatencyMs, start, err)
+	return err
+}
+
+// GetIndexedBackfills returns the ids of the indexed Backfills with their generation.
+func (is *instrumentedService) GetIndexedBackfills(ctx context.Context) (map[string]int64, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.GetIndexedBackfills")
+	defer span.End()
+	defer telemetry.RecordUnitMeasurement(ctx, mStateStoreGetIndexedBackfillsCount)
+	start := time.Now()
+	backfills, err := is.s.GetIndexedBackfills(ctx)
+	recordLatency(ctx, mStateStoreGetIndexedBackfillsLatencyMs, start, err)
+	return backfills, err
 }
@@ -0,0 +1,190 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"strings"
+
+	"open-match.dev/open-match/internal/config"
+)
+
+// keyspace names the Redis keys and pub/sub channels of an Open Match
+// installation.  Everything is prefixed with redis.keyPrefix, so that multiple
+// installations, eg. staging and production, can share a Redis instance.
+// Ticket ids are only prefixed where used as key names, set members hold the
+// plain ids.
+type keyspace struct {
+	prefix string
+}
+
+// newKeyspaces returns the keyspace configured by redis.keyPrefix.  While
+// redis.readUnprefixedKeys is set, it also returns the unprefixed keyspace,
+// so that tickets written before the prefix was introduced can still be read,
+// assigned and deleted during the rollout.
+func newKeyspaces(cfg config.View) (keyspace, *keyspace) {
+	keys := keyspace{prefix: cfg.GetString("redis.keyPrefix")}
+	if keys.prefix == "" || !cfg.GetBool("redis.readUnprefixedKeys") {
+		return keys, nil
+	}
+	return keys, &keyspace{}
+}
+
+func (k keyspace) ticket(id string) string {
+	return k.prefix + id
+}
+
+func (k keyspace) assignment(id string) string {
+	return k.prefix + id + assignmentKeySuffix
+}
+
+func (k keyspace) version(id string) string {
+	return k.prefix + id + versionKeySuffix
+}
+
+func (k keyspace) state(id string) string {
+	return k.prefix + id + stateKeySuffix
+}
+
+func (k keyspace) backfill(id string) string {
+	return k.prefix + backfillKeyPrefix + id
+}
+
+func (k keyspace) idempotency(key string) string {
+	return k.prefix + idempotencyKeyPrefix + key
+}
+
+func (k keyspace) indexSnapshot(token string) string {
+	return k.prefix + indexSnapshotKeyPrefix + token
+}
+
+func (k keyspace) allBackfills() string {
+	return k.prefix + allBackfills
+}
+
+func (k keyspace) backfillLastAck() string {
+	return k.prefix + backfillLastAck
+}
+
+func (k keyspace) allTickets() string {
+	return k.prefix + allTickets
+}
+
+func (k keyspace) ignoreList() string {
+	return k.prefix + ignoreList
+}
+
+func (k keyspace) leaseOwners() string {
+	return k.prefix + leaseOwners
+}
+
+func (k keyspace) synchronizerCycles() string {
+	return k.prefix + synchronizerCycles
+}
+
+func (k keyspace) ticketsRevision() string {
+	return k.prefix + ticketsRevision
+}
+
+func (k keyspace) storedTickets() string {
+	return k.prefix + storedTickets
+}
+
+func (k keyspace) replicationHeartbeat() string {
+	return k.prefix + replicationHeartbeat
+}
+
+func (k keyspace) ticketHeartbeats() string {
+	return k.prefix + ticketHeartbeats
+}
+
+func (k keyspace) profiles() string {
+	return k.prefix + profiles
+}
+
+func (k keyspace) profilesLastSeen() string {
+	return k.prefix + profilesLastSeen
+}
+
+func (k keyspace) components() string {
+	return k.prefix + components
+}
+
+func (k keyspace) componentsLastSeen() string {
+	return k.prefix + componentsLastSeen
+}
+
+func (k keyspace) featureGates() string {
+	return k.prefix + featureGates
+}
+
+func (k keyspace) doubleIndex(arg string) string {
+	return k.prefix + indexKeyPrefix + "double:" + arg
+}
+
+func (k keyspace) stringIndex(arg string) string {
+	return k.prefix + indexKeyPrefix + "string:" + arg
+}
+
+func (k keyspace) tagIndex(tag string) string {
+	return k.prefix + indexKeyPrefix + "tag:" + tag
+}
+
+func (k keyspace) createTimeIndex() string {
+	return k.prefix + indexKeyPrefix + "createTime"
+}
+
+func (k keyspace) assignmentChannelPrefix() string {
+	return k.prefix + assignmentChannelPrefix
+}
+
+// nonTicketKeys returns all of the fixed keys which don't hold a ticket.
+func (k keyspace) nonTicketKeys() []string {
+	keys := make([]string, 0, len(nonTicketKeys))
+	for _, key := range nonTicketKeys {
+		keys = append(keys, k.prefix+key)
+	}
+	return keys
+}
+
+// pattern returns a SCAN MATCH pattern for the keys of the keyspace matching
+// glob once the prefix is removed.
+func (k keyspace) pattern(glob string) string {
+	var b strings.Builder
+	for _, r := range k.prefix {
+		if strings.ContainsRune(`*?[]\`, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String() + glob
+}
+
+// ticketID returns the id of the ticket stored under key, or false if key
+// doesn't hold a ticket of the keyspace.
+func (k keyspace) ticketID(key string) (string, bool) {
+	if !strings.HasPrefix(key, k.prefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(key, k.prefix)
+	if strings.HasSuffix(name, assignmentKeySuffix) || strings.HasSuffix(name, versionKeySuffix) || strings.HasSuffix(name, stateKeySuffix) || strings.HasPrefix(name, indexKeyPrefix) || strings.HasPrefix(name, backfillKeyPrefix) || strings.HasPrefix(name, idempotencyKeyPrefix) || strings.HasPrefix(name, indexSnapshotKeyPrefix) {
+		return "", false
+	}
+	for _, n := range nonTicketKeys {
+		if name == n {
+			return "", false
+		}
+	}
+	return name, true
+}
@@ -0,0 +1,158 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/config"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestKeyPrefix(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	ctx := utilTesting.NewContext(t)
+
+	stagingCfg := copyConfig(cfg)
+	stagingCfg.Set("redis.keyPrefix", "staging:")
+	staging := New(stagingCfg)
+	defer staging.Close()
+	prodCfg := copyConfig(cfg)
+	prodCfg.Set("redis.keyPrefix", "prod:")
+	prod := New(prodCfg)
+	defer prod.Close()
+
+	ticket := &pb.Ticket{Id: "1", SearchFields: &pb.SearchFields{Tags: []string{"beta"}}}
+	assert.Nil(staging.CreateTicket(ctx, ticket))
+	assert.Nil(staging.IndexTicket(ctx, ticket))
+
+	_, err := prod.GetTicket(ctx, "1")
+	assert.Equal(codes.NotFound, status.Convert(err).Code())
+	ids, err := prod.GetIndexedIDSet(ctx)
+	assert.Nil(err)
+	assert.Empty(ids)
+	count, err := prod.CountTickets(ctx, &pb.Pool{TagPresentFilters: []*pb.TagPresentFilter{{Tag: "beta"}}})
+	assert.Nil(err)
+	assert.Equal(int64(0), count)
+	notFound, _, err := prod.UpdateAssignments(ctx, []string{"1"}, &pb.Assignment{Connection: "prod"})
+	assert.Nil(err)
+	assert.Equal([]string{"1"}, notFound)
+	usage, err := prod.GetStorageUsage(ctx, 10)
+	assert.Nil(err)
+	assert.Equal(int64(0), usage.GetTicketCount())
+
+	ids, err = staging.GetIndexedIDSet(ctx)
+	assert.Nil(err)
+	assert.Equal(map[string]struct{}{"1": {}}, ids)
+	usage, err = staging.GetStorageUsage(ctx, 10)
+	assert.Nil(err)
+	assert.Equal(int64(1), usage.GetTicketCount())
+	assert.Equal(int64(1), usage.GetIndexedTicketCount())
+	scanned, _, err := staging.RewriteTickets(ctx, func(*pb.Ticket) bool { return false })
+	assert.Nil(err)
+	assert.Equal(int64(1), scanned)
+
+	// The same id in another installation is a different ticket.
+	assert.Nil(prod.CreateTicket(ctx, &pb.Ticket{Id: "1"}))
+	assert.Nil(prod.DeleteTicket(ctx, "1"))
+	got, err := staging.GetTicket(ctx, "1")
+	assert.Nil(err)
+	assert.Equal([]string{"beta"}, got.GetSearchFields().GetTags())
+}
+
+func TestReadUnprefixedKeys(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	ctx := utilTesting.NewContext(t)
+
+	unprefixed := New(cfg)
+	defer unprefixed.Close()
+	ticket := &pb.Ticket{Id: "1"}
+	assert.Nil(unprefixed.CreateTicket(ctx, ticket))
+	assert.Nil(unprefixed.IndexTicket(ctx, ticket))
+
+	migratingCfg := copyConfig(cfg)
+	migratingCfg.Set("redis.keyPrefix", "om:")
+	migratingCfg.Set("redis.readUnprefixedKeys", true)
+	migrating := New(migratingCfg)
+	defer migrating.Close()
+	assert.Nil(migrating.CreateTicket(ctx, &pb.Ticket{Id: "2"}))
+	assert.Nil(migrating.IndexTicket(ctx, &pb.Ticket{Id: "2"}))
+
+	tickets, err := migrating.GetTickets(ctx, []string{"1", "2"})
+	assert.Nil(err)
+	assert.Len(tickets, 2)
+	ids, err := migrating.GetIndexedIDSet(ctx)
+	assert.Nil(err)
+	assert.Equal(map[string]struct{}{"1": {}, "2": {}}, ids)
+
+	// Tickets proposed by components which don't use the prefix yet are ignored.
+	leaseTickets(t, unprefixed, []string{"1"})
+	ids, err = migrating.GetIndexedIDSet(ctx)
+	assert.Nil(err)
+	assert.Equal(map[string]struct{}{"2": {}}, ids)
+	assert.Nil(migrating.DeleteTicketsFromIgnoreList(ctx, []string{"1"}))
+
+	notFound, _, err := migrating.UpdateAssignments(ctx, []string{"1", "2", "3"}, &pb.Assignment{Connection: "a"})
+	assert.Nil(err)
+	assert.Equal([]string{"3"}, notFound)
+	got, err := unprefixed.GetTicket(ctx, "1")
+	assert.Nil(err)
+	assert.Equal("a", got.GetAssignment().GetConnection())
+	got, err = migrating.GetTicket(ctx, "1")
+	assert.Nil(err)
+	assert.Equal("a", got.GetAssignment().GetConnection())
+
+	assert.Nil(migrating.DeindexTicket(ctx, "1"))
+	ids, err = unprefixed.GetIndexedIDSet(ctx)
+	assert.Nil(err)
+	assert.Empty(ids)
+	assert.Nil(migrating.DeleteTicket(ctx, "1"))
+	_, err = unprefixed.GetTicket(ctx, "1")
+	assert.Equal(codes.NotFound, status.Convert(err).Code())
+}
+
+func TestKeyspace(t *testing.T) {
+	assert := assert.New(t)
+	k := keyspace{prefix: "om[1]:"}
+
+	id, ok := k.ticketID("om[1]:abc")
+	assert.True(ok)
+	assert.Equal("abc", id)
+	for _, key := range []string{"abc", "om[1]:" + allTickets, k.assignment("abc"), k.tagIndex("beta"), k.indexSnapshot("abc")} {
+		_, ok = k.ticketID(key)
+		assert.False(ok, key)
+	}
+	assert.Equal(`om\[1\]:*`, k.pattern("*"))
+}
+
+// copyConfig returns a copy of cfg, so that multiple statestores with
+// different settings can share a Redis instance.
+func copyConfig(cfg config.Mutable) config.Mutable {
+	v := cfg.(*viper.Viper)
+	c := viper.New()
+	for _, key := range v.AllKeys() {
+		c.Set(key, v.Get(key))
+	}
+	return c
+}
@@ -0,0 +1,226 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Leases are stored in the ignore list, scored by when they were acquired or
+// last extended, with their owners in a separate hash.  Entries without an
+// owner were added by components predating leases, and are only released by
+// expiry or DeleteTicketsFromIgnoreList.
+
+// acquireTicketLeaseScript leases the tickets which aren't leased to another
+// owner, returning the ids of those which are.  KEYS are the ignore list and
+// the lease owners, ARGV are the owner, the current time, the time before
+// which leases have expired and the ticket ids.
+var acquireTicketLeaseScript = redis.NewScript(2, `
+local held = {}
+for i = 4, #ARGV do
+  local score = redis.call("ZSCORE", KEYS[1], ARGV[i])
+  local owner = redis.call("HGET", KEYS[2], ARGV[i])
+  if score and tonumber(score) > tonumber(ARGV[3]) and owner ~= ARGV[1] then
+    table.insert(held, ARGV[i])
+  else
+    redis.call("ZADD", KEYS[1], ARGV[2], ARGV[i])
+    redis.call("HSET", KEYS[2], ARGV[i], ARGV[1])
+  end
+end
+return held
+`)
+
+// extendLeaseScript restarts the unexpired leases of the owner, returning the
+// ids of the tickets it doesn't hold.  KEYS and ARGV are those of
+// acquireTicketLeaseScript.
+var extendLeaseScript = redis.NewScript(2, `
+local missing = {}
+for i = 4, #ARGV do
+  local score = redis.call("ZSCORE", KEYS[1], ARGV[i])
+  local owner = redis.call("HGET", KEYS[2], ARGV[i])
+  if score and tonumber(score) > tonumber(ARGV[3]) and owner == ARGV[1] then
+    redis.call("ZADD", KEYS[1], ARGV[2], ARGV[i])
+  else
+    table.insert(missing, ARGV[i])
+  end
+end
+return missing
+`)
+
+// releaseTicketLeaseScript releases the leases of the owner.  KEYS are the
+// ignore list and the lease owners, ARGV are the owner and the ticket ids.
+var releaseTicketLeaseScript = redis.NewScript(2, `
+for i = 2, #ARGV do
+  if redis.call("HGET", KEYS[2], ARGV[i]) == ARGV[1] then
+    redis.call("ZREM", KEYS[1], ARGV[i])
+    redis.call("HDEL", KEYS[2], ARGV[i])
+  end
+end
+return 0
+`)
+
+// releaseAbandonedCyclesScript releases the leases of the cycles started
+// before a time, and removes the cycles.  KEYS are the in-flight cycles, the
+// ignore list and the lease owners, ARGV is the time.  It returns the number
+// of leases released followed by the ids of the cycles.
+var releaseAbandonedCyclesScript = redis.NewScript(3, `
+local cycles = redis.call("ZRANGEBYSCORE", KEYS[1], "-inf", "(" .. ARGV[1])
+if #cycles == 0 then
+  return {0}
+end
+local abandoned = {}
+for _, id in ipairs(cycles) do
+  abandoned[id] = true
+end
+local released = 0
+local owners = redis.call("HGETALL", KEYS[3])
+for i = 1, #owners, 2 do
+  if abandoned[owners[i + 1]] then
+    redis.call("ZREM", KEYS[2], owners[i])
+    redis.call("HDEL", KEYS[3], owners[i])
+    released = released + 1
+  end
+end
+redis.call("ZREM", KEYS[1], unpack(cycles))
+local result = {released}
+for _, id in ipairs(cycles) do
+  table.insert(result, id)
+end
+return result
+`)
+
+// AcquireTicketLease leases the tickets to owner, returning the ids of the
+// tickets leased to another owner.
+func (rb *redisBackend) AcquireTicketLease(ctx context.Context, owner string, ids []string) ([]string, error) {
+	held, err := rb.runLeaseScript(ctx, acquireTicketLeaseScript, owner, ids)
+	if err != nil {
+		redisLogger.WithError(err).Error("failed to acquire ticket leases")
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	return held, nil
+}
+
+// ExtendLease restarts the leases owner holds on the tickets, returning the
+// ids of the tickets it doesn't hold.
+func (rb *redisBackend) ExtendLease(ctx context.Context, owner string, ids []string) ([]string, error) {
+	missing, err := rb.runLeaseScript(ctx, extendLeaseScript, owner, ids)
+	if err != nil {
+		redisLogger.WithError(err).Error("failed to extend ticket leases")
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	return missing, nil
+}
+
+// ReleaseTicketLease releases the leases owner holds on the tickets.
+func (rb *redisBackend) ReleaseTicketLease(ctx context.Context, owner string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	_, err = releaseTicketLeaseScript.Do(redisConn, redis.Args{rb.keys.ignoreList(), rb.keys.leaseOwners(), owner}.AddFlat(ids)...)
+	if err != nil {
+		redisLogger.WithError(err).Error("failed to release ticket leases")
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	return nil
+}
+
+func (rb *redisBackend) runLeaseScript(ctx context.Context, script *redis.Script, owner string, ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	now := rb.clk.Now()
+	expiredBefore := now.Add(-rb.cfg.GetDuration("storage.ignoreListTTL"))
+	args := redis.Args{rb.keys.ignoreList(), rb.keys.leaseOwners(), owner, now.UnixNano(), expiredBefore.UnixNano()}.AddFlat(ids)
+	return redis.Strings(script.Do(redisConn, args...))
+}
+
+// RecordCycle records that the synchronizer cycle is in flight.
+func (rb *redisBackend) RecordCycle(ctx context.Context, cycleID string) error {
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	if _, err = redisConn.Do("ZADD", rb.keys.synchronizerCycles(), rb.clk.Now().UnixNano(), cycleID); err != nil {
+		redisLogger.WithError(err).Error("failed to record synchronizer cycle")
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	return nil
+}
+
+// FinishCycle records that the synchronizer cycle is done with its leases.
+func (rb *redisBackend) FinishCycle(ctx context.Context, cycleID string) error {
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	if _, err = redisConn.Do("ZREM", rb.keys.synchronizerCycles(), cycleID); err != nil {
+		redisLogger.WithError(err).Error("failed to finish synchronizer cycle")
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	return nil
+}
+
+// ReleaseAbandonedCycles releases the leases of the cycles in flight since
+// before startedBefore, and forgets them.
+func (rb *redisBackend) ReleaseAbandonedCycles(ctx context.Context, startedBefore time.Time) ([]string, int, error) {
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	reply, err := redis.Values(releaseAbandonedCyclesScript.Do(redisConn, rb.keys.synchronizerCycles(), rb.keys.ignoreList(), rb.keys.leaseOwners(), startedBefore.UnixNano()))
+	if err == nil && len(reply) == 0 {
+		err = errors.New("empty reply")
+	}
+	if err != nil {
+		redisLogger.WithError(err).Error("failed to release abandoned synchronizer cycles")
+		return nil, 0, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	released, err := redis.Int(reply[0], nil)
+	if err != nil {
+		return nil, 0, status.Errorf(codes.Internal, "%v", err)
+	}
+	cycles, err := redis.Strings(reply[1:], nil)
+	if err != nil {
+		return nil, 0, status.Errorf(codes.Internal, "%v", err)
+	}
+	return cycles, released, nil
+}
@@ -0,0 +1,358 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	// assignmentChannelPrefix prefixes the pub/sub channel which is published
+	// to when a ticket's assignment is updated.
+	assignmentChannelPrefix = "assignment."
+	// notifierReconnectDelay is how long to wait before retrying a failed
+	// pub/sub connection.
+	notifierReconnectDelay = time.Second
+	// maxNotifierPingInterval bounds how long the pub/sub connection may be
+	// idle before it's checked with a ping.
+	maxNotifierPingInterval = 30 * time.Second
+)
+
+var errNotifierClosed = errors.New("assignment notifier is closed")
+
+// assignmentNotifier shares a single pub/sub connection between all
+// GetAssignments calls of the process, subscribing to the channels of the
+// tickets currently watched.  While the connection is down updates are
+// missed, so all watchers are woken up once it's reestablished to read the
+// current assignment.
+type assignmentNotifier struct {
+	dial         func() (redis.Conn, error)
+	pingInterval time.Duration
+	// channelPrefixes are prepended to a ticket's id to get the channels its
+	// updates are published to.
+	channelPrefixes []string
+
+	m sync.Mutex
+	// psc is nil while disconnected.
+	psc      *redis.PubSubConn
+	watchers map[string]*ticketWatchers
+	// pending counts the unconfirmed channel subscriptions per ticket on psc.
+	pending map[string]int
+	closed  bool
+}
+
+type ticketWatchers struct {
+	chans map[chan struct{}]struct{}
+	// ready is closed once the ticket's channel is subscribed to.
+	ready chan struct{}
+}
+
+// newAssignmentNotifier returns a notifier subscribing to the channels of
+// each of channelPrefixes, or of assignmentChannelPrefix if none are given.
+func newAssignmentNotifier(dial func() (redis.Conn, error), readTimeout time.Duration, channelPrefixes ...string) *assignmentNotifier {
+	pingInterval := maxNotifierPingInterval
+	if readTimeout > 0 && readTimeout/2 < pingInterval {
+		pingInterval = readTimeout / 2
+	}
+	if len(channelPrefixes) == 0 {
+		channelPrefixes = []string{assignmentChannelPrefix}
+	}
+	return &assignmentNotifier{
+		dial:            dial,
+		pingInterval:    pingInterval,
+		channelPrefixes: channelPrefixes,
+		watchers:        make(map[string]*ticketWatchers),
+		pending:         make(map[string]int),
+	}
+}
+
+// channels returns the channels the ticket's updates are published to.
+func (n *assignmentNotifier) channels(id string) []interface{} {
+	channels := make([]interface{}, 0, len(n.channelPrefixes))
+	for _, prefix := range n.channelPrefixes {
+		channels = append(channels, prefix+id)
+	}
+	return channels
+}
+
+// ticketID returns the id of the ticket whose updates are published to channel.
+func (n *assignmentNotifier) ticketID(channel string) string {
+	for _, prefix := range n.channelPrefixes {
+		if strings.HasPrefix(channel, prefix) {
+			return strings.TrimPrefix(channel, prefix)
+		}
+	}
+	return channel
+}
+
+// watch returns a channel which receives a value whenever the assignment of
+// the ticket may have changed, and a function to stop watching.  It returns
+// once updates are subscribed to, so that none are missed by reading the
+// assignment afterwards.
+func (n *assignmentNotifier) watch(ctx context.Context, id string) (<-chan struct{}, func(), error) {
+	c, ready, err := n.add(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			n.m.Lock()
+			defer n.m.Unlock()
+			n.locklessRemove(id, c)
+		})
+	}
+
+	select {
+	case <-ctx.Done():
+		stop()
+		return nil, nil, ctx.Err()
+	case <-ready:
+	}
+	// The caller reads the current assignment anyway, drop the wake up from
+	// subscribing.
+	select {
+	case <-c:
+	default:
+	}
+	return c, stop, nil
+}
+
+func (n *assignmentNotifier) add(id string) (chan struct{}, chan struct{}, error) {
+	n.m.Lock()
+	defer n.m.Unlock()
+
+	if n.closed {
+		return nil, nil, errNotifierClosed
+	}
+
+	c := make(chan struct{}, 1)
+	w, ok := n.watchers[id]
+	if !ok {
+		w = &ticketWatchers{
+			chans: make(map[chan struct{}]struct{}),
+			ready: make(chan struct{}),
+		}
+		n.watchers[id] = w
+	}
+	w.chans[c] = struct{}{}
+
+	var err error
+	if n.psc == nil {
+		// Subscribes to all watched tickets, including this one.
+		err = n.locklessConnect()
+	} else if !ok {
+		err = n.locklessSubscribe(id)
+	}
+	if err != nil {
+		n.locklessRemove(id, c)
+		return nil, nil, err
+	}
+	return c, w.ready, nil
+}
+
+func (n *assignmentNotifier) locklessRemove(id string, c chan struct{}) {
+	w, ok := n.watchers[id]
+	if !ok {
+		return
+	}
+	delete(w.chans, c)
+	if len(w.chans) > 0 {
+		return
+	}
+	delete(n.watchers, id)
+	if n.psc != nil {
+		err := n.locklessSend(func(psc *redis.PubSubConn) error {
+			return psc.Unsubscribe(n.channels(id)...)
+		})
+		if err != nil {
+			redisLogger.WithError(err).Warning("failed to unsubscribe from assignment updates")
+		}
+	}
+}
+
+func (n *assignmentNotifier) locklessSubscribe(ids ...string) error {
+	channels := make([]interface{}, 0, len(ids)*len(n.channelPrefixes))
+	for _, id := range ids {
+		channels = append(channels, n.channels(id)...)
+		n.pending[id] += len(n.channelPrefixes)
+	}
+	return n.locklessSend(func(psc *redis.PubSubConn) error {
+		return psc.Subscribe(channels...)
+	})
+}
+
+// locklessSend writes to the pub/sub connection, dropping it if that fails.
+func (n *assignmentNotifier) locklessSend(f func(*redis.PubSubConn) error) error {
+	err := f(n.psc)
+	if err != nil {
+		n.locklessDisconnect(err)
+	}
+	return err
+}
+
+// locklessConnect dials a new pub/sub connection subscribed to all watched
+// tickets.  Watchers are woken up once subscribed again, as updates may have
+// been missed.
+func (n *assignmentNotifier) locklessConnect() error {
+	conn, err := n.dial()
+	if err != nil {
+		return err
+	}
+	psc := &redis.PubSubConn{Conn: conn}
+	n.psc = psc
+	n.pending = make(map[string]int)
+
+	if len(n.watchers) > 0 {
+		ids := make([]string, 0, len(n.watchers))
+		for id := range n.watchers {
+			ids = append(ids, id)
+		}
+		if err = n.locklessSubscribe(ids...); err != nil {
+			return err
+		}
+	}
+
+	go n.receive(psc)
+	go n.ping(psc)
+	return nil
+}
+
+func (n *assignmentNotifier) locklessDisconnect(err error) {
+	if n.psc == nil {
+		return
+	}
+	conn := n.psc.Conn
+	handleConnectionClose(&conn)
+	n.psc = nil
+
+	if n.closed {
+		return
+	}
+	redisLogger.WithError(err).Warning("lost the assignment updates connection, reconnecting")
+	time.AfterFunc(notifierReconnectDelay, n.reconnect)
+}
+
+func (n *assignmentNotifier) reconnect() {
+	n.m.Lock()
+	defer n.m.Unlock()
+
+	if n.closed || n.psc != nil || len(n.watchers) == 0 {
+		return
+	}
+	if err := n.locklessConnect(); err != nil {
+		redisLogger.WithError(err).Warning("failed to reconnect for assignment updates")
+		if n.psc == nil {
+			time.AfterFunc(notifierReconnectDelay, n.reconnect)
+		}
+	}
+}
+
+// locklessSubscribed handles a subscription confirmation for the ticket.
+func (n *assignmentNotifier) locklessSubscribed(id string) {
+	n.pending[id]--
+	if n.pending[id] > 0 {
+		return
+	}
+	delete(n.pending, id)
+
+	w, ok := n.watchers[id]
+	if !ok {
+		return
+	}
+	select {
+	case <-w.ready:
+	default:
+		close(w.ready)
+	}
+	n.locklessNotify(id)
+}
+
+func (n *assignmentNotifier) locklessNotify(id string) {
+	w, ok := n.watchers[id]
+	if !ok {
+		return
+	}
+	for c := range w.chans {
+		select {
+		case c <- struct{}{}:
+		default:
+			// Already has a pending notification.
+		}
+	}
+}
+
+func (n *assignmentNotifier) receive(psc *redis.PubSubConn) {
+	for {
+		switch v := psc.Receive().(type) {
+		case redis.Message:
+			n.m.Lock()
+			n.locklessNotify(n.ticketID(v.Channel))
+			n.m.Unlock()
+		case redis.Subscription:
+			if v.Kind == "subscribe" {
+				n.m.Lock()
+				if n.psc == psc {
+					n.locklessSubscribed(n.ticketID(v.Channel))
+				}
+				n.m.Unlock()
+			}
+		case error:
+			n.m.Lock()
+			if n.psc == psc {
+				n.locklessDisconnect(v)
+			}
+			n.m.Unlock()
+			return
+		}
+	}
+}
+
+// ping keeps the connection from hitting its read timeout, and detects broken
+// connections while no updates are published.
+func (n *assignmentNotifier) ping(psc *redis.PubSubConn) {
+	ticker := time.NewTicker(n.pingInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		n.m.Lock()
+		if n.psc != psc {
+			n.m.Unlock()
+			return
+		}
+		err := n.locklessSend(func(psc *redis.PubSubConn) error {
+			return psc.Ping("")
+		})
+		n.m.Unlock()
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (n *assignmentNotifier) close() {
+	n.m.Lock()
+	defer n.m.Unlock()
+
+	n.closed = true
+	n.locklessDisconnect(errNotifierClosed)
+}
@@ -0,0 +1,83 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+)
+
+func TestAssignmentNotifier(t *testing.T) {
+	assert := assert.New(t)
+	mredis, err := miniredis.Run()
+	assert.Nil(err)
+	defer mredis.Close()
+
+	var netConn net.Conn
+	n := newAssignmentNotifier(func() (redis.Conn, error) {
+		return redis.Dial("tcp", mredis.Addr(), redis.DialNetDial(func(network, addr string) (net.Conn, error) {
+			var err error
+			netConn, err = net.Dial(network, addr)
+			return netConn, err
+		}))
+	}, time.Second)
+	defer n.close()
+
+	ctx := utilTesting.NewContext(t)
+	updated, stop, err := n.watch(ctx, "1")
+	assert.Nil(err)
+	other, stopOther, err := n.watch(ctx, "2")
+	assert.Nil(err)
+	defer stopOther()
+
+	assertNotified := func(c <-chan struct{}) {
+		select {
+		case <-c:
+		case <-time.After(5 * time.Second):
+			assert.Fail("expected a notification")
+		}
+	}
+	assertNotNotified := func(c <-chan struct{}) {
+		select {
+		case <-c:
+			assert.Fail("unexpected notification")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	mredis.Publish(assignmentChannelPrefix+"1", "")
+	assertNotified(updated)
+	assertNotNotified(other)
+
+	// Watchers are woken up after reconnecting, as updates may have been missed.
+	assert.Nil(netConn.Close())
+	assertNotified(updated)
+	assertNotified(other)
+
+	// Still subscribed after reconnecting.
+	time.Sleep(50 * time.Millisecond)
+	mredis.Publish(assignmentChannelPrefix+"2", "")
+	assertNotified(other)
+
+	stop()
+	mredis.Publish(assignmentChannelPrefix+"1", "")
+	assertNotNotified(updated)
+}
@@ -16,7 +16,9 @@ package statestore
 
 import (
 	"context"
+	"time"
 
+	"open-match.dev/open-match/internal/clock"
 	"open-match.dev/open-match/internal/config"
 	"open-match.dev/open-match/internal/telemetry"
 	"open-match.dev/open-match/pkg/pb"
@@ -24,50 +26,349 @@ import (
 
 // Service is a generic interface for talking to a storage backend.
 type Service interface {
-	// HealthCheck indicates if the database is reachable.
+	// HealthCheck indicates if the database is reachable.  Backends responding slower than their configured
+	// threshold return an error marked with telemetry.Degraded.
 	HealthCheck(ctx context.Context) error
 
 	// CreateTicket creates a new Ticket in the state storage. If the id already exists, it will be overwritten.
+	// Fails with ResourceExhausted if storing a new Ticket would exceed storage.ticketQuota.  Tickets count against
+	// the quota until they're deleted or expire.
 	CreateTicket(ctx context.Context, ticket *pb.Ticket) error
 
+	// CreateTickets creates multiple Tickets in a single round trip, as with CreateTicket.  Either all or none of the
+	// Tickets are created.
+	CreateTickets(ctx context.Context, tickets []*pb.Ticket) error
+
+	// CreateTicketsWithExpiration creates Tickets as with CreateTickets, which expire after expiration instead of the
+	// configured redis.expiration.  A zero expiration uses redis.expiration.
+	CreateTicketsWithExpiration(ctx context.Context, tickets []*pb.Ticket, expiration time.Duration) error
+
 	// GetTicket gets the Ticket with the specified id from state storage. This method fails if the Ticket does not exist.
 	GetTicket(ctx context.Context, id string) (*pb.Ticket, error)
 
+	// GetTicketWithVersion gets the Ticket with the specified id along with its version, which is incremented
+	// whenever the Ticket or its assignment is written.  This method fails if the Ticket does not exist.
+	GetTicketWithVersion(ctx context.Context, id string) (*pb.Ticket, int64, error)
+
+	// CompareAndSetTicket overwrites the Ticket if its version is still version, keeping its expiration, and returns
+	// its new version.  Fails with FailedPrecondition if the Ticket was written since, and with NotFound if it does
+	// not exist.  Tickets only present under the unprefixed keyspace of a previous release can't be compared and set.
+	CompareAndSetTicket(ctx context.Context, ticket *pb.Ticket, version int64) (int64, error)
+
+	// GetTicketState returns the lifecycle state of the Ticket with the specified id, which is PROPOSED while the
+	// Ticket is leased.  Expired Tickets are reported as EXPIRED for storage.expiredTicketStateTTL once
+	// CleanUpExpiredTickets removed them, and fail with NotFound afterwards, as do deleted Tickets.
+	GetTicketState(ctx context.Context, id string) (pb.Ticket_State, error)
+
+	// GetTicketStatus returns whether the Ticket with the specified id is indexed, leased to a proposal and assigned,
+	// in a single round trip.  Fails with NotFound if the Ticket doesn't exist.
+	GetTicketStatus(ctx context.Context, id string) (*TicketStatus, error)
+
 	// DeleteTicket removes the Ticket with the specified id from state storage. This method succeeds if the Ticket does not exist.
 	DeleteTicket(ctx context.Context, id string) error
 
+	// DeleteTickets removes the Tickets with the specified ids from state storage in a single round trip. This method
+	// succeeds if some of the Tickets do not exist.
+	DeleteTickets(ctx context.Context, ids []string) error
+
+	// ClaimIdempotencyKey records that the Ticket with the specified id is created with the idempotency key, for ttl.
+	// Returns the id of the Ticket the key was already claimed for, if any, in which case the key is left untouched.
+	ClaimIdempotencyKey(ctx context.Context, key string, id string, ttl time.Duration) (string, error)
+
+	// ReleaseIdempotencyKey deletes the idempotency key if it's still claimed for the Ticket with the specified id.
+	ReleaseIdempotencyKey(ctx context.Context, key string, id string) error
+
 	// IndexTicket adds the ticket to the index.
 	IndexTicket(ctx context.Context, ticket *pb.Ticket) error
 
+	// IndexTickets adds multiple tickets to the index in a single round trip.
+	IndexTickets(ctx context.Context, tickets []*pb.Ticket) error
+
 	// DeindexTicket removes specified ticket from the index. The Ticket continues to exist.
 	DeindexTicket(ctx context.Context, id string) error
 
+	// DeindexTickets removes the specified tickets from the index in a single transaction. The Tickets continue to
+	// exist.
+	DeindexTickets(ctx context.Context, ids []string) error
+
+	// RecordHeartbeats records a heartbeat for each of the indexed Tickets, so that DeindexLapsedTickets leaves them
+	// indexed.  Returns the ids of the Tickets which aren't indexed, which are left untouched.
+	RecordHeartbeats(ctx context.Context, ids []string) ([]string, error)
+
+	// DeindexLapsedTickets deindexes up to limit Tickets whose last heartbeat is older than timeout, and returns
+	// their ids.  Tickets without any recorded heartbeat are never deindexed.
+	DeindexLapsedTickets(ctx context.Context, timeout time.Duration, limit int) ([]string, error)
+
 	// GetIndexedIDSet returns the ids of all tickets currently indexed.
 	GetIndexedIDSet(ctx context.Context) (map[string]struct{}, error)
 
+	// TakeIndexSnapshot copies the ids of all tickets currently indexed under token, for ttl, so that every replica
+	// can read them back with GetIndexSnapshot.
+	TakeIndexSnapshot(ctx context.Context, token string, ttl time.Duration) error
+
+	// GetIndexSnapshot returns the ids copied by TakeIndexSnapshot under token.  Fails with NotFound if the snapshot
+	// doesn't exist or expired.
+	GetIndexSnapshot(ctx context.Context, token string) (map[string]struct{}, error)
+
+	// ScanIndexedIDs calls f with pages of the ids of all tickets currently indexed, without blocking the storage
+	// for long with huge pools.  An id may be passed more than once.  Errors returned by f stop the scan.
+	ScanIndexedIDs(ctx context.Context, f func([]string) error) error
+
+	// CountTickets returns the number of indexed Tickets matching all filters of the pool, including Tickets in the
+	// ignore list.  Tickets are counted using the field indices, without fetching them.
+	CountTickets(ctx context.Context, pool *pb.Pool) (int64, error)
+
 	// GetTickets returns multiple tickets from storage.  Missing tickets are
 	// silently ignored.
 	GetTickets(ctx context.Context, ids []string) ([]*pb.Ticket, error)
 
-	// UpdateAssignments update the match assignments for the input ticket ids
-	UpdateAssignments(ctx context.Context, ids []string, assignment *pb.Assignment) error
+	// UpdateAssignments update the match assignments for the input ticket ids.  Tickets which don't exist are skipped,
+	// and their ids returned, followed by the ids of the tickets which were already assigned, whose assignment is
+	// replaced.
+	UpdateAssignments(ctx context.Context, ids []string, assignment *pb.Assignment) (notFound []string, alreadyAssigned []string, err error)
+
+	// UpdateAssignmentGroups sets the assignment of each group on its tickets in a single transaction, skipping and
+	// returning the tickets which don't exist, like UpdateAssignments.
+	UpdateAssignmentGroups(ctx context.Context, groups []*pb.AssignmentGroup) (notFound []string, alreadyAssigned []string, err error)
+
+	// RequeueFailedAssignments clears the assignments of the Tickets, eg. because their game server couldn't be
+	// allocated, and indexes them again, counting the failures in their AssignmentFailures.  Tickets which already
+	// failed maxFailures times are left assigned, unless maxFailures isn't positive, and tickets which aren't assigned
+	// are left untouched.  Returns how many Tickets were requeued, the ids of the Tickets which don't exist, and of
+	// those which failed too often.
+	RequeueFailedAssignments(ctx context.Context, ids []string, maxFailures int) (requeued int64, notFound []string, exhausted []string, err error)
 
 	// GetAssignments returns the assignment associated with the input ticket id
 	GetAssignments(ctx context.Context, id string, callback func(*pb.Assignment) error) error
 
-	// AddProposedTickets appends new proposed tickets to the proposed sorted set with current timestamp
-	AddTicketsToIgnoreList(ctx context.Context, ids []string) error
+	// AcquireTicketLease leases the tickets to owner, excluding them from GetIndexedIDSet until the lease is released
+	// or storage.ignoreListTTL passes without it being extended.  Tickets already leased to owner have their lease
+	// extended.  Returns the ids of the tickets leased to another owner, which are left untouched.
+	AcquireTicketLease(ctx context.Context, owner string, ids []string) ([]string, error)
+
+	// ExtendLease restarts the leases owner holds on the tickets.  Returns the ids of the tickets which owner doesn't
+	// hold an unexpired lease on, which are left untouched.
+	ExtendLease(ctx context.Context, owner string, ids []string) ([]string, error)
+
+	// ReleaseTicketLease releases the leases owner holds on the tickets, so that they're returned by queries again.
+	// Leases held by other owners are left untouched.
+	ReleaseTicketLease(ctx context.Context, owner string, ids []string) error
+
+	// RecordCycle records that the synchronizer cycle, the owner of the leases it acquires, is in flight until
+	// FinishCycle is called.
+	RecordCycle(ctx context.Context, cycleID string) error
+
+	// FinishCycle records that the synchronizer cycle is done with its leases.
+	FinishCycle(ctx context.Context, cycleID string) error
+
+	// ReleaseAbandonedCycles releases the leases of the cycles recorded as in flight since before startedBefore, which
+	// were abandoned by a synchronizer that stopped mid-cycle, and forgets them.  It returns their ids and the number
+	// of leases released.
+	ReleaseAbandonedCycles(ctx context.Context, startedBefore time.Time) ([]string, int, error)
 
-	// DeleteTicketsFromIgnoreList deletes tickets from the proposed sorted set
+	// DeleteTicketsFromIgnoreList releases the leases on the tickets, whoever owns them.
 	DeleteTicketsFromIgnoreList(ctx context.Context, ids []string) error
 
+	// CleanUpExpiredTickets removes Tickets from the index and the ignore list as soon as they expire, calling cleaned
+	// with the id of each, and frees up their quota, until ctx is done.  Expirations it misses, eg. while disconnected,
+	// are only deindexed and freed up by the next CollectGarbage.
+	CleanUpExpiredTickets(ctx context.Context, cleaned func(id string)) error
+
+	// ReleaseAllTickets empties the ignore list, returning every proposed or leased ticket to the pool, and returns
+	// how many tickets were released.
+	ReleaseAllTickets(ctx context.Context) (int, error)
+
+	// GetStorageUsage reports the number of tickets and indexed ids in state storage,
+	// and approximates the memory used from a sample of up to sampleSize tickets.
+	GetStorageUsage(ctx context.Context, sampleSize int) (*pb.StorageUsage, error)
+
+	// RewriteTickets calls rewrite on every Ticket in state storage, indexed or not, and saves the Tickets for
+	// which it returns true. Each Ticket is rewritten atomically.  Returns the number of Tickets scanned and rewritten.
+	RewriteTickets(ctx context.Context, rewrite func(*pb.Ticket) bool) (scanned int64, rewritten int64, err error)
+
+	// RewriteTicketsByID calls rewrite on each of the Tickets with the given ids, and saves the Tickets for which it
+	// returns true. Each Ticket is rewritten atomically, and moved to the field indices of its new SearchFields if
+	// indexed.  rewrite sees the current Assignment of the Ticket, but changes to it aren't saved.  Returns the number
+	// of Tickets rewritten and the ids not found.
+	RewriteTicketsByID(ctx context.Context, ids []string, rewrite func(*pb.Ticket) bool) (rewritten int64, notFound []string, err error)
+
+	// ExportTickets calls f with pages of snapshots of every Ticket in state storage, indexed or not.  Tickets written
+	// while exporting may or may not be included.
+	ExportTickets(ctx context.Context, f func([]*pb.TicketSnapshot) error) error
+
+	// ImportTickets saves the Tickets of the snapshots in a single transaction, overwriting Tickets with the same ids,
+	// and indexes those which were indexed.  It's meant to restore an export into an empty state storage, and doesn't
+	// enforce storage.ticketQuota, though the Tickets count against it.
+	ImportTickets(ctx context.Context, snapshots []*pb.TicketSnapshot) error
+
+	// GetTicketsRevision returns a number which changes whenever RewriteTickets modifies Tickets, so that caches
+	// know to refetch Tickets they already hold.
+	GetTicketsRevision(ctx context.Context) (int64, error)
+
+	// RecordProfile saves the profile in the profile registry, marking it as recently used.  Profiles which aren't
+	// recorded again within storage.profileRegistryTTL are dropped from the registry.
+	RecordProfile(ctx context.Context, profile *pb.MatchProfile) error
+
+	// GetProfiles returns the profiles recently recorded in the profile registry.
+	GetProfiles(ctx context.Context) ([]*pb.MatchProfile, error)
+
+	// PublishComponent saves the build version and config digest of a running component, replacing what the same
+	// instance published before.  Components which aren't published again within storage.componentRegistryTTL are
+	// dropped.
+	PublishComponent(ctx context.Context, component *ComponentInfo) error
+
+	// GetComponents returns the components recently published with PublishComponent.
+	GetComponents(ctx context.Context) ([]*ComponentInfo, error)
+
+	// SetFeatureGateOverride saves the override, replacing any override of the same gate and instance.
+	SetFeatureGateOverride(ctx context.Context, override *pb.FeatureGateOverride) error
+
+	// DeleteFeatureGateOverride removes the override of the gate for the instance, if any.
+	DeleteFeatureGateOverride(ctx context.Context, gate string, instance string) error
+
+	// GetFeatureGateOverrides returns all feature gate overrides.
+	GetFeatureGateOverrides(ctx context.Context) ([]*pb.FeatureGateOverride, error)
+
+	// CollectGarbage removes expired ticket leases, the indexed ids and field index entries of Tickets which no longer
+	// exist, the Tickets in orphanCandidates which are still neither indexed nor assigned, and the Backfills which
+	// weren't acknowledged within storage.backfillAckTimeout.  It recounts the stored Tickets enforcing
+	// storage.ticketQuota, so that expirations missed by CleanUpExpiredTickets free up their quota.  The Tickets currently
+	// neither indexed nor assigned are returned as the candidates of the next collection, so that Tickets are only
+	// deleted once they've been orphaned for a whole collection interval.
+	CollectGarbage(ctx context.Context, orphanCandidates map[string]struct{}) (*GarbageCollection, error)
+
+	// CreateBackfill creates a new Backfill in state storage, along with the ids of the Tickets already matched to it.
+	// The Backfill counts as acknowledged on creation.  Fails with AlreadyExists if the id exists.
+	CreateBackfill(ctx context.Context, backfill *pb.Backfill, ticketIDs []string) error
+
+	// GetBackfill gets the Backfill with the specified id, and the ids of its Tickets.  Fails with NotFound if it
+	// doesn't exist.
+	GetBackfill(ctx context.Context, id string) (*pb.Backfill, []string, error)
+
+	// UpdateBackfill overwrites the Backfill and the ids of its Tickets.  The generation is saved as given, callers are
+	// expected to increment it.  Fails with NotFound if the Backfill doesn't exist.
+	UpdateBackfill(ctx context.Context, backfill *pb.Backfill, ticketIDs []string) error
+
+	// DeleteBackfill removes the Backfill, its index entry and its acknowledgments.  Succeeds if it doesn't exist.
+	DeleteBackfill(ctx context.Context, id string) error
+
+	// AcknowledgeBackfill records that the game server of the Backfill still wants it filled.  Fails with NotFound if
+	// the Backfill doesn't exist.
+	AcknowledgeBackfill(ctx context.Context, id string) error
+
+	// GetExpiredBackfillIDs returns the ids of the Backfills which weren't acknowledged within
+	// storage.backfillAckTimeout.
+	GetExpiredBackfillIDs(ctx context.Context) ([]string, error)
+
+	// IndexBackfill makes the Backfill available to be filled, recording its generation.
+	IndexBackfill(ctx context.Context, backfill *pb.Backfill) error
+
+	// DeindexBackfill removes the Backfill from the index.  The Backfill continues to exist.
+	DeindexBackfill(ctx context.Context, id string) error
+
+	// GetIndexedBackfills returns the ids of the indexed Backfills, mapped to the generation they were indexed with.
+	GetIndexedBackfills(ctx context.Context) (map[string]int64, error)
+
 	// Closes the connection to the underlying storage.
 	Close() error
 }
 
+// ComponentInfo describes a running Open Match component, so that components
+// can detect when they're running different builds or configurations.
+type ComponentInfo struct {
+	// Name of the component, eg. "backend".
+	Name string `json:"name"`
+	// Instance uniquely identifies the process, eg. the pod name.
+	Instance string `json:"instance"`
+	// BuildVersion is the Open Match version the component was built from.
+	BuildVersion string `json:"buildVersion"`
+	// ConfigDigest is a hash of the component's configuration.
+	ConfigDigest string `json:"configDigest"`
+}
+
+// TicketStatus reports where a Ticket is in the matchmaking queue.
+type TicketStatus struct {
+	// Indexed is whether the Ticket is in the pool queried by match functions.
+	Indexed bool
+	// Proposed is whether the Ticket is leased to a proposal, ie. on the
+	// ignore list.
+	Proposed bool
+	// Assigned is whether the Ticket was assigned to a game server.
+	Assigned bool
+}
+
+// GarbageCollection reports the state removed by CollectGarbage.
+type GarbageCollection struct {
+	// ExpiredLeases is the number of expired ticket leases removed.
+	ExpiredLeases int64
+	// MissingTickets is the number of indexed ids removed because their
+	// Ticket no longer exists, eg. because it expired.
+	MissingTickets int64
+	// DanglingIndexEntries is the number of field index entries removed
+	// because their Ticket is no longer indexed.
+	DanglingIndexEntries int64
+	// OrphanedTickets is the number of Tickets deleted because they were
+	// neither indexed nor assigned since the previous collection.
+	OrphanedTickets int64
+	// ExpiredBackfills is the number of Backfills deleted because they weren't
+	// acknowledged within storage.backfillAckTimeout.
+	ExpiredBackfills int64
+	// StoredTicketsDrift is how many more Tickets the counter enforcing
+	// storage.ticketQuota held than were stored, eg. because their expiration
+	// was missed, before it was corrected.
+	StoredTicketsDrift int64
+	// OrphanCandidates are the ids of the Tickets currently neither indexed
+	// nor assigned.
+	OrphanCandidates map[string]struct{}
+	// TicketStates is the number of remaining Tickets in each lifecycle state,
+	// keyed by TicketSearching, TicketProposed, TicketAssigned and
+	// TicketPendingDelete.  It's only complete if the collection succeeded.
+	TicketStates map[string]int64
+}
+
+// The lifecycle states of Tickets reported by CollectGarbage.
+const (
+	// TicketSearching is the state of indexed Tickets which aren't in a proposal.
+	TicketSearching = "searching"
+	// TicketProposed is the state of indexed Tickets leased to a proposal.
+	TicketProposed = "proposed"
+	// TicketAssigned is the state of assigned Tickets.
+	TicketAssigned = "assigned"
+	// TicketPendingDelete is the state of Tickets neither indexed nor assigned,
+	// which are deleted by the next collection unless indexed or assigned again.
+	TicketPendingDelete = "pending-delete"
+)
+
+type replicaReadsKey struct{}
+
+// WithReplicaReads returns a context allowing GetTicket, GetTicketWithVersion,
+// GetTickets and GetIndexedIDSet calls made with it to read from the replica
+// configured with redis.readReplica, if any.  Such reads lag writes by the
+// replication delay, so callers must tolerate stale or missing Tickets.  The
+// delay is bounded by storage.allowStaleReads, if set: reads are made on the
+// primary while the replica lags more, or its lag is unknown.
+func WithReplicaReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replicaReadsKey{}, true)
+}
+
+// replicaReads returns whether ctx allows reading from a replica.
+func replicaReads(ctx context.Context) bool {
+	allowed, _ := ctx.Value(replicaReadsKey{}).(bool)
+	return allowed
+}
+
 // New creates a Service based on the configuration.
 func New(cfg config.View) Service {
-	s := newRedis(cfg)
+	return NewWithClock(cfg, clock.Real())
+}
+
+// NewWithClock creates a Service based on the configuration which uses clk to
+// timestamp and expire entries in the ignore list.  The backend is selected by
+// storage.backend from those added with Register, and defaults to Redis.
+// storage.faults lists faults injected into calls to the backend for testing,
+// see ParseFaults.
+func NewWithClock(cfg config.View, clk clock.Clock) Service {
+	s := withFaultsFromConfig(cfg, newBackend(cfg, clk))
 	if cfg.GetBool(telemetry.ConfigNameEnableMetrics) {
 		return &instrumentedService{
 			s: s,
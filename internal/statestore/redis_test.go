@@ -17,15 +17,19 @@ package statestore
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 	"time"
 
 	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/golang/protobuf/proto"
+	"github.com/gomodule/redigo/redis"
 	"github.com/rs/xid"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/clock"
 	"open-match.dev/open-match/internal/config"
 	"open-match.dev/open-match/internal/telemetry"
 	internalTesting "open-match.dev/open-match/internal/testing"
@@ -42,6 +46,22 @@ func TestStatestoreSetup(t *testing.T) {
 	defer service.Close()
 }
 
+func TestHealthCheckLatencyThreshold(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	service := New(cfg)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	assert.Nil(service.HealthCheck(ctx))
+
+	cfg.Set("redis.healthCheckLatencyThreshold", time.Nanosecond)
+	err := service.HealthCheck(ctx)
+	assert.NotNil(err)
+	assert.True(telemetry.IsDegraded(err))
+}
+
 func TestTicketLifecycle(t *testing.T) {
 	// Create State Store
 	assert := assert.New(t)
@@ -131,8 +151,8 @@ func TestIgnoreLists(t *testing.T) {
 	// Verify all tickets are created and returned
 	verifyTickets(service, len(tickets))
 
-	// Add the first three tickets to the ignore list and verify changes are reflected in the result
-	assert.Nil(service.AddTicketsToIgnoreList(ctx, ticketIds[:3]))
+	// Lease the first three tickets and verify changes are reflected in the result
+	leaseTickets(t, service, ticketIds[:3])
 	verifyTickets(service, len(tickets)-3)
 
 	// Sleep until the ignore list expired and verify we still have all the tickets
@@ -140,6 +160,515 @@ func TestIgnoreLists(t *testing.T) {
 	verifyTickets(service, len(tickets))
 }
 
+func TestIgnoreListsWithVirtualClock(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	clk := clock.NewVirtual(time.Unix(0, 0))
+	service := NewWithClock(cfg, clk)
+	assert.NotNil(service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	tickets := internalTesting.GenerateFloatRangeTickets(
+		internalTesting.Property{Name: "testindex1", Min: 0, Max: 10, Interval: 2},
+		internalTesting.Property{Name: "testindex2", Min: 0, Max: 10, Interval: 2},
+	)
+
+	ticketIds := []string{}
+	for _, ticket := range tickets {
+		assert.Nil(service.CreateTicket(ctx, ticket))
+		assert.Nil(service.IndexTicket(ctx, ticket))
+		ticketIds = append(ticketIds, ticket.GetId())
+	}
+
+	verifyTickets := func(expectLen int) {
+		ids, err := service.GetIndexedIDSet(ctx)
+		assert.Nil(err)
+		assert.Equal(expectLen, len(ids))
+	}
+
+	leaseTickets(t, service, ticketIds[:3])
+	verifyTickets(len(tickets) - 3)
+
+	// The ignore list only expires once the virtual clock passes the TTL.
+	ttl := cfg.GetDuration("storage.ignoreListTTL")
+	clk.Advance(ttl / 2)
+	verifyTickets(len(tickets) - 3)
+	clk.Advance(ttl)
+	verifyTickets(len(tickets))
+}
+
+func TestTicketLeases(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	clk := clock.NewVirtual(time.Unix(0, 0))
+	service := NewWithClock(cfg, clk)
+	assert.NotNil(service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	ids := []string{"a", "b", "c"}
+	for _, id := range ids {
+		assert.Nil(service.CreateTicket(ctx, &pb.Ticket{Id: id}))
+		assert.Nil(service.IndexTicket(ctx, &pb.Ticket{Id: id}))
+	}
+	verifyTickets := func(expected ...string) {
+		indexed, err := service.GetIndexedIDSet(ctx)
+		assert.Nil(err)
+		assert.Len(indexed, len(expected))
+		for _, id := range expected {
+			assert.Contains(indexed, id)
+		}
+	}
+
+	held, err := service.AcquireTicketLease(ctx, "cycle1", []string{"a", "b"})
+	assert.Nil(err)
+	assert.Empty(held)
+	verifyTickets("c")
+
+	// Tickets leased to another owner can't be acquired or extended.
+	held, err = service.AcquireTicketLease(ctx, "cycle2", []string{"b", "c"})
+	assert.Nil(err)
+	assert.Equal([]string{"b"}, held)
+	missing, err := service.ExtendLease(ctx, "cycle2", []string{"a", "c"})
+	assert.Nil(err)
+	assert.Equal([]string{"a"}, missing)
+	verifyTickets()
+
+	// Releasing only affects the owner's leases.
+	assert.Nil(service.ReleaseTicketLease(ctx, "cycle1", []string{"b", "c"}))
+	verifyTickets("b")
+
+	// Extended leases outlive those which aren't.
+	ttl := cfg.GetDuration("storage.ignoreListTTL")
+	clk.Advance(ttl / 2)
+	missing, err = service.ExtendLease(ctx, "cycle1", []string{"a"})
+	assert.Nil(err)
+	assert.Empty(missing)
+	clk.Advance(ttl / 2)
+	clk.Advance(time.Millisecond)
+	verifyTickets("b", "c")
+
+	// Expired leases may be acquired by anyone, but no longer extended.
+	held, err = service.AcquireTicketLease(ctx, "cycle3", []string{"c"})
+	assert.Nil(err)
+	assert.Empty(held)
+	missing, err = service.ExtendLease(ctx, "cycle2", []string{"c"})
+	assert.Nil(err)
+	assert.Equal([]string{"c"}, missing)
+
+	// Leases are released whoever owns them when deleting from the ignore list.
+	assert.Nil(service.DeleteTicketsFromIgnoreList(ctx, []string{"a", "c"}))
+	verifyTickets("a", "b", "c")
+}
+
+func TestReleaseAbandonedCycles(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	clk := clock.NewVirtual(time.Unix(0, 0))
+	service := NewWithClock(cfg, clk)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	tickets := []*pb.Ticket{{Id: "a"}, {Id: "b"}, {Id: "c"}, {Id: "d"}}
+	assert.Nil(service.CreateTickets(ctx, tickets))
+	assert.Nil(service.IndexTickets(ctx, tickets))
+
+	for i, cycle := range []string{"finished", "abandoned", "running"} {
+		assert.Nil(service.RecordCycle(ctx, cycle))
+		_, err := service.AcquireTicketLease(ctx, cycle, []string{tickets[i].Id})
+		assert.Nil(err)
+		clk.Advance(time.Millisecond)
+	}
+	assert.Nil(service.FinishCycle(ctx, "finished"))
+	_, err := service.AcquireTicketLease(ctx, "unrecorded", []string{"d"})
+	assert.Nil(err)
+
+	// Only the leases of cycles recorded before the time and not finished are
+	// released.
+	cycles, released, err := service.ReleaseAbandonedCycles(ctx, time.Unix(0, int64(2*time.Millisecond)))
+	assert.Nil(err)
+	assert.Equal([]string{"abandoned"}, cycles)
+	assert.Equal(1, released)
+	ids, err := service.GetIndexedIDSet(ctx)
+	assert.Nil(err)
+	assert.Equal(map[string]struct{}{"b": {}}, ids)
+
+	cycles, released, err = service.ReleaseAbandonedCycles(ctx, time.Unix(0, int64(2*time.Millisecond)))
+	assert.Nil(err)
+	assert.Empty(cycles)
+	assert.Equal(0, released)
+}
+
+func TestTicketQuota(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	cfg.Set("storage.ticketQuota", 2)
+	service := New(cfg)
+	assert.NotNil(service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	createAndIndex := func() (*pb.Ticket, error) {
+		ticket := &pb.Ticket{Id: xid.New().String()}
+		if err := service.CreateTicket(ctx, ticket); err != nil {
+			return nil, err
+		}
+		return ticket, service.IndexTicket(ctx, ticket)
+	}
+
+	first, err := createAndIndex()
+	assert.Nil(err)
+	_, err = createAndIndex()
+	assert.Nil(err)
+
+	_, err = createAndIndex()
+	assert.Equal(codes.ResourceExhausted, status.Convert(err).Code())
+
+	// Existing tickets can still be assigned.
+	_, _, err = service.UpdateAssignments(ctx, []string{first.GetId()}, &pb.Assignment{Connection: "1.2.3.4:5678"})
+	assert.Nil(err)
+
+	// Tickets count until they're deleted, not just while in matchmaking.
+	assert.Nil(service.DeindexTicket(ctx, first.GetId()))
+	_, err = createAndIndex()
+	assert.Equal(codes.ResourceExhausted, status.Convert(err).Code())
+	assert.Nil(service.DeleteTicket(ctx, first.GetId()))
+	_, err = createAndIndex()
+	assert.Nil(err)
+
+	// Deleting tickets twice, or ones which don't exist, frees up nothing.
+	assert.Nil(service.DeleteTickets(ctx, []string{first.GetId(), xid.New().String()}))
+	_, err = createAndIndex()
+	assert.Equal(codes.ResourceExhausted, status.Convert(err).Code())
+}
+
+func TestCreateTickets(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	cfg.Set("storage.ticketQuota", 3)
+	service := New(cfg)
+	assert.NotNil(service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	tickets := []*pb.Ticket{
+		{Id: xid.New().String(), SearchFields: &pb.SearchFields{Tags: []string{"a"}}},
+		{Id: xid.New().String(), SearchFields: &pb.SearchFields{Tags: []string{"b"}}},
+	}
+	assert.Nil(service.CreateTickets(ctx, tickets))
+	assert.Nil(service.IndexTickets(ctx, tickets))
+
+	ids, err := service.GetIndexedIDSet(ctx)
+	assert.Nil(err)
+	assert.Len(ids, 2)
+	got, err := service.GetTickets(ctx, []string{tickets[0].GetId(), tickets[1].GetId()})
+	assert.Nil(err)
+	assert.Len(got, 2)
+
+	// A batch exceeding the quota creates none of its tickets.
+	extra := []*pb.Ticket{{Id: xid.New().String()}, {Id: xid.New().String()}}
+	err = service.CreateTickets(ctx, extra)
+	assert.Equal(codes.ResourceExhausted, status.Convert(err).Code())
+	got, err = service.GetTickets(ctx, []string{extra[0].GetId(), extra[1].GetId()})
+	assert.Nil(err)
+	assert.Empty(got)
+
+	// Overwriting existing tickets along with new ones only counts the new ones.
+	assert.Nil(service.CreateTickets(ctx, append(tickets, extra[0])))
+}
+
+func TestCreateTicketsWithExpiration(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	cfg.Set("redis.expiration", 3600)
+	service := New(cfg)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	assert.Nil(service.CreateTicketsWithExpiration(ctx, []*pb.Ticket{{Id: "short"}}, time.Minute))
+	assert.Nil(service.CreateTicketsWithExpiration(ctx, []*pb.Ticket{{Id: "default"}}, 0))
+
+	rb := newRedis(cfg, clock.Real()).(*redisBackend)
+	defer rb.Close()
+	conn, err := rb.connect(ctx)
+	assert.Nil(err)
+	defer conn.Close()
+	ttl, err := redis.Int64(conn.Do("PTTL", rb.keys.ticket("short")))
+	assert.Nil(err)
+	assert.True(ttl > 0 && ttl <= time.Minute.Milliseconds(), "ttl: %d", ttl)
+	ttl, err = redis.Int64(conn.Do("PTTL", rb.keys.version("short")))
+	assert.Nil(err)
+	assert.True(ttl > 0 && ttl <= time.Minute.Milliseconds(), "ttl: %d", ttl)
+	ttl, err = redis.Int64(conn.Do("TTL", rb.keys.ticket("default")))
+	assert.Nil(err)
+	assert.Equal(int64(3600), ttl)
+}
+
+func TestScanIndexedIDs(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	cfg.Set("storage.indexedIDPageSize", 10)
+	service := New(cfg)
+	assert.NotNil(service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	tickets := []*pb.Ticket{}
+	for i := 0; i < 25; i++ {
+		tickets = append(tickets, &pb.Ticket{Id: xid.New().String()})
+	}
+	assert.Nil(service.CreateTickets(ctx, tickets))
+	assert.Nil(service.IndexTickets(ctx, tickets))
+	leaseTickets(t, service, []string{tickets[0].GetId(), tickets[1].GetId()})
+
+	scanned := map[string]struct{}{}
+	err := service.ScanIndexedIDs(ctx, func(ids []string) error {
+		for _, id := range ids {
+			scanned[id] = struct{}{}
+		}
+		return nil
+	})
+	assert.Nil(err)
+	assert.Len(scanned, 23)
+	assert.NotContains(scanned, tickets[0].GetId())
+	assert.NotContains(scanned, tickets[1].GetId())
+
+	ids, err := service.GetIndexedIDSet(ctx)
+	assert.Nil(err)
+	assert.Equal(scanned, ids)
+
+	// Errors of the callback stop the scan.
+	err = service.ScanIndexedIDs(ctx, func([]string) error {
+		return status.Error(codes.Aborted, "stop")
+	})
+	assert.Equal(codes.Aborted, status.Convert(err).Code())
+}
+
+func TestGetStorageUsage(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	service := New(cfg)
+	assert.NotNil(service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	usage, err := service.GetStorageUsage(ctx, 10)
+	assert.Nil(err)
+	assert.Equal(&pb.StorageUsage{}, usage)
+
+	var ids []string
+	for i := 0; i < 4; i++ {
+		ticket := &pb.Ticket{Id: xid.New().String()}
+		assert.Nil(service.CreateTicket(ctx, ticket))
+		ids = append(ids, ticket.GetId())
+	}
+	for _, id := range ids[:3] {
+		assert.Nil(service.IndexTicket(ctx, &pb.Ticket{Id: id}))
+	}
+	leaseTickets(t, service, ids[:1])
+
+	usage, err = service.GetStorageUsage(ctx, 2)
+	assert.Nil(err)
+	assert.Equal(int64(4), usage.GetTicketCount())
+	assert.Equal(int64(3), usage.GetIndexedTicketCount())
+	assert.Equal(int64(1), usage.GetIgnoreListSize())
+	assert.Equal(int64(2), usage.GetSampledTicketCount())
+	assert.True(usage.GetApproximateBytes() > 0)
+}
+
+func TestTicketVersions(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	service := New(cfg)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	ticket := &pb.Ticket{Id: "1"}
+	assert.Nil(service.CreateTicket(ctx, ticket))
+	_, created, err := service.GetTicketWithVersion(ctx, "1")
+	assert.Nil(err)
+	assert.True(created > 0)
+
+	// Setting the assignment bumps the version.
+	_, _, err = service.UpdateAssignments(ctx, []string{"1"}, &pb.Assignment{Connection: "localhost"})
+	assert.Nil(err)
+	got, assigned, err := service.GetTicketWithVersion(ctx, "1")
+	assert.Nil(err)
+	assert.Equal("localhost", got.GetAssignment().GetConnection())
+	assert.True(assigned > created)
+
+	// A write based on a stale read fails.
+	ticket.SearchFields = &pb.SearchFields{Tags: []string{"stale"}}
+	_, err = service.CompareAndSetTicket(ctx, ticket, created)
+	assert.Equal(codes.FailedPrecondition, status.Code(err))
+
+	// A write based on the latest read succeeds and bumps the version.
+	got.SearchFields = &pb.SearchFields{Tags: []string{"fresh"}}
+	version, err := service.CompareAndSetTicket(ctx, got, assigned)
+	assert.Nil(err)
+	assert.True(version > assigned)
+	got, current, err := service.GetTicketWithVersion(ctx, "1")
+	assert.Nil(err)
+	assert.Equal(version, current)
+	assert.Equal([]string{"fresh"}, got.GetSearchFields().GetTags())
+	assert.Equal("localhost", got.GetAssignment().GetConnection())
+
+	_, err = service.CompareAndSetTicket(ctx, &pb.Ticket{Id: "missing"}, 0)
+	assert.Equal(codes.NotFound, status.Code(err))
+	_, _, err = service.GetTicketWithVersion(ctx, "missing")
+	assert.Equal(codes.NotFound, status.Code(err))
+
+	// Deleting the ticket resets its version.
+	assert.Nil(service.DeleteTicket(ctx, "1"))
+	assert.Nil(service.CreateTicket(ctx, &pb.Ticket{Id: "1"}))
+	_, recreated, err := service.GetTicketWithVersion(ctx, "1")
+	assert.Nil(err)
+	assert.Equal(created, recreated)
+}
+
+func TestRewriteTickets(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	service := New(cfg)
+	assert.NotNil(service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	revision, err := service.GetTicketsRevision(ctx)
+	assert.Nil(err)
+
+	indexed := &pb.Ticket{Id: xid.New().String(), SearchFields: &pb.SearchFields{Tags: []string{"old"}}}
+	assert.Nil(service.CreateTicket(ctx, indexed))
+	assert.Nil(service.IndexTicket(ctx, indexed))
+	unindexed := &pb.Ticket{Id: xid.New().String(), SearchFields: &pb.SearchFields{Tags: []string{"old"}}}
+	assert.Nil(service.CreateTicket(ctx, unindexed))
+	untouched := &pb.Ticket{Id: xid.New().String()}
+	assert.Nil(service.CreateTicket(ctx, untouched))
+	leaseTickets(t, service, []string{indexed.GetId()})
+
+	scanned, rewritten, err := service.RewriteTickets(ctx, func(t *pb.Ticket) bool {
+		if len(t.GetSearchFields().GetTags()) == 0 {
+			return false
+		}
+		t.SearchFields.Tags = []string{"new"}
+		return true
+	})
+	assert.Nil(err)
+	assert.Equal(int64(3), scanned)
+	assert.Equal(int64(2), rewritten)
+
+	for _, id := range []string{indexed.GetId(), unindexed.GetId()} {
+		ticket, err := service.GetTicket(ctx, id)
+		assert.Nil(err)
+		assert.Equal([]string{"new"}, ticket.GetSearchFields().GetTags())
+	}
+
+	newRevision, err := service.GetTicketsRevision(ctx)
+	assert.Nil(err)
+	assert.NotEqual(revision, newRevision)
+
+	// Tickets keep their expiration.
+	rb := newRedis(cfg, clock.Real()).(*redisBackend)
+	defer rb.Close()
+	conn, err := rb.connect(ctx)
+	assert.Nil(err)
+	defer conn.Close()
+	ttl, err := redis.Int64(conn.Do("TTL", indexed.GetId()))
+	assert.Nil(err)
+	assert.True(ttl > 0)
+
+	// Nothing to rewrite leaves the revision alone.
+	_, rewritten, err = service.RewriteTickets(ctx, func(*pb.Ticket) bool { return false })
+	assert.Nil(err)
+	assert.Equal(int64(0), rewritten)
+	revision, err = service.GetTicketsRevision(ctx)
+	assert.Nil(err)
+	assert.Equal(newRevision, revision)
+}
+
+func TestProfileRegistry(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	cfg.Set("storage.profileRegistryTTL", "1m")
+	clk := clock.NewVirtual(time.Unix(0, 0))
+	service := NewWithClock(cfg, clk)
+	assert.NotNil(service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	profiles, err := service.GetProfiles(ctx)
+	assert.Nil(err)
+	assert.Empty(profiles)
+
+	old := &pb.MatchProfile{Name: "old", Pools: []*pb.Pool{{Name: "pool"}}}
+	assert.Nil(service.RecordProfile(ctx, old))
+	clk.Advance(40 * time.Second)
+	recent := &pb.MatchProfile{Name: "recent"}
+	assert.Nil(service.RecordProfile(ctx, recent))
+
+	profiles, err = service.GetProfiles(ctx)
+	assert.Nil(err)
+	assert.Len(profiles, 2)
+
+	// Recording again replaces the profile and keeps it alive.
+	updated := &pb.MatchProfile{Name: "recent", Pools: []*pb.Pool{{Name: "new-pool"}}}
+	clk.Advance(40 * time.Second)
+	assert.Nil(service.RecordProfile(ctx, updated))
+
+	profiles, err = service.GetProfiles(ctx)
+	assert.Nil(err)
+	if assert.Len(profiles, 1) {
+		assert.True(proto.Equal(updated, profiles[0]))
+	}
+}
+
+func TestFeatureGateOverrides(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	service := New(cfg)
+	assert.NotNil(service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	overrides, err := service.GetFeatureGateOverrides(ctx)
+	assert.Nil(err)
+	assert.Empty(overrides)
+
+	global := &pb.FeatureGateOverride{Gate: "gate", Enabled: true}
+	canary := &pb.FeatureGateOverride{Gate: "gate", Instance: "host/backend", Enabled: false}
+	assert.Nil(service.SetFeatureGateOverride(ctx, global))
+	assert.Nil(service.SetFeatureGateOverride(ctx, canary))
+
+	// Setting again replaces the override of the same gate and instance.
+	canary = &pb.FeatureGateOverride{Gate: "gate", Instance: "host/backend", Enabled: true}
+	assert.Nil(service.SetFeatureGateOverride(ctx, canary))
+
+	overrides, err = service.GetFeatureGateOverrides(ctx)
+	assert.Nil(err)
+	assert.Len(overrides, 2)
+
+	assert.Nil(service.DeleteFeatureGateOverride(ctx, "gate", ""))
+	assert.Nil(service.DeleteFeatureGateOverride(ctx, "missing", ""))
+	overrides, err = service.GetFeatureGateOverrides(ctx)
+	assert.Nil(err)
+	if assert.Len(overrides, 1) {
+		assert.True(proto.Equal(canary, overrides[0]))
+	}
+}
+
 func TestDeleteTicketsFromIgnoreList(t *testing.T) {
 	// Create State Store
 	assert := assert.New(t)
@@ -171,14 +700,40 @@ func TestDeleteTicketsFromIgnoreList(t *testing.T) {
 	// Verify all tickets are created and returned
 	verifyTickets(service, len(tickets))
 
-	// Add the first three tickets to the ignore list and verify changes are reflected in the result
-	assert.Nil(service.AddTicketsToIgnoreList(ctx, ticketIds[:3]))
+	// Lease the first three tickets and verify changes are reflected in the result
+	leaseTickets(t, service, ticketIds[:3])
 	verifyTickets(service, len(tickets)-3)
 
 	assert.Nil(service.DeleteTicketsFromIgnoreList(ctx, ticketIds[:3]))
 	verifyTickets(service, len(tickets))
 }
 
+func TestReleaseAllTickets(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	service := New(cfg)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	tickets := []*pb.Ticket{{Id: "a"}, {Id: "b"}, {Id: "c"}}
+	assert.Nil(service.CreateTickets(ctx, tickets))
+	assert.Nil(service.IndexTickets(ctx, tickets))
+
+	released, err := service.ReleaseAllTickets(ctx)
+	assert.Nil(err)
+	assert.Equal(0, released)
+
+	leaseTickets(t, service, []string{"a", "b"})
+	released, err = service.ReleaseAllTickets(ctx)
+	assert.Nil(err)
+	assert.Equal(2, released)
+
+	ids, err := service.GetIndexedIDSet(ctx)
+	assert.Nil(err)
+	assert.Len(ids, 3)
+}
+
 func TestGetAssignmentBeforeSet(t *testing.T) {
 	// Create State Store
 	assert := assert.New(t)
@@ -200,7 +755,7 @@ func TestGetAssignmentBeforeSet(t *testing.T) {
 	assert.Nil(assignmentResp)
 }
 
-func TestUpdateAssignmentFatal(t *testing.T) {
+func TestUpdateAssignmentNotFound(t *testing.T) {
 	// Create State Store
 	assert := assert.New(t)
 	cfg, closer := createRedis(t)
@@ -210,8 +765,6 @@ func TestUpdateAssignmentFatal(t *testing.T) {
 	defer service.Close()
 	ctx := utilTesting.NewContext(t)
 
-	var assignmentResp *pb.Assignment
-
 	// Now create a ticket in the state store service
 	err := service.CreateTicket(ctx, &pb.Ticket{
 		Id:         "1",
@@ -220,10 +773,20 @@ func TestUpdateAssignmentFatal(t *testing.T) {
 	assert.Nil(err)
 
 	// Try to update the assignmets with the ticket created and some non-existed tickets
-	err = service.UpdateAssignments(ctx, []string{"1", "2", "3"}, &pb.Assignment{Connection: "localhost"})
-	// UpdateAssignment failed because the ticket does not exists
-	assert.Equal(codes.NotFound, status.Convert(err).Code())
-	assert.Nil(assignmentResp)
+	notFound, _, err := service.UpdateAssignments(ctx, []string{"1", "2", "3"}, &pb.Assignment{Connection: "localhost"})
+	// The tickets which don't exist are skipped and reported
+	assert.Nil(err)
+	assert.Equal([]string{"2", "3"}, notFound)
+
+	// The existing ticket is assigned.
+	ticket, err := service.GetTicket(ctx, "1")
+	assert.Nil(err)
+	assert.Equal("localhost", ticket.GetAssignment().GetConnection())
+
+	// The skipped tickets aren't created.
+	tickets, err := service.GetTickets(ctx, []string{"2", "3"})
+	assert.Nil(err)
+	assert.Empty(tickets)
 }
 
 func TestGetAssignmentNormal(t *testing.T) {
@@ -246,6 +809,7 @@ func TestGetAssignmentNormal(t *testing.T) {
 	ctx, cancel := context.WithCancel(ctx)
 	callbackCount := 0
 	returnedErr := errors.New("some errors")
+	var updates sync.WaitGroup
 
 	err = service.GetAssignments(ctx, "1", func(assignment *pb.Assignment) error {
 		assignmentResp = assignment
@@ -259,14 +823,52 @@ func TestGetAssignmentNormal(t *testing.T) {
 		}
 
 		callbackCount++
+		// Each published update calls back again.
+		updates.Add(1)
+		go func() {
+			defer updates.Done()
+			_, _, err := service.UpdateAssignments(ctx, []string{"1"}, &pb.Assignment{Connection: "2"})
+			assert.Nil(err)
+		}()
 		return nil
 	})
+	updates.Wait()
 
-	// Test GetAssignments was retried for 5 times and returned with expected error
+	// Test GetAssignments was called back 5 times and returned with expected error
 	assert.Equal(5, callbackCount)
 	assert.Equal(returnedErr, err)
 }
 
+func TestGetAssignmentsReadsOnlyAssignment(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	service := New(cfg)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	assert.Nil(service.CreateTicket(ctx, &pb.Ticket{Id: "1"}))
+	_, _, err := service.UpdateAssignments(ctx, []string{"1"}, &pb.Assignment{Connection: "localhost"})
+	assert.Nil(err)
+
+	// Once assigned, the ticket itself isn't read back.
+	rb := newRedis(cfg, clock.Real()).(*redisBackend)
+	defer rb.Close()
+	conn, err := rb.connect(ctx)
+	assert.Nil(err)
+	defer conn.Close()
+	_, err = conn.Do("SET", rb.keys.ticket("1"), "not a ticket")
+	assert.Nil(err)
+
+	var got *pb.Assignment
+	err = service.GetAssignments(ctx, "1", func(assignment *pb.Assignment) error {
+		got = assignment
+		return errors.New("done")
+	})
+	assert.EqualError(err, "done")
+	assert.Equal("localhost", got.GetConnection())
+}
+
 func TestUpdateAssignmentNormal(t *testing.T) {
 	// Create State Store
 	assert := assert.New(t)
@@ -290,8 +892,10 @@ func TestUpdateAssignmentNormal(t *testing.T) {
 	assert.Nil(err)
 
 	fakeAssignment := &pb.Assignment{Connection: "Halo"}
-	err = service.UpdateAssignments(ctx, []string{"1", "3"}, fakeAssignment)
+	notFound, alreadyAssigned, err := service.UpdateAssignments(ctx, []string{"1", "3"}, fakeAssignment)
 	assert.Nil(err)
+	assert.Empty(notFound)
+	assert.Equal([]string{"3"}, alreadyAssigned)
 	// Verify the transaction behavior of the UpdateAssignment.
 	ticket, err := service.GetTicket(ctx, "1")
 	assert.Equal(fakeAssignment.Connection, ticket.Assignment.Connection)
@@ -300,6 +904,38 @@ func TestUpdateAssignmentNormal(t *testing.T) {
 	ticket, err = service.GetTicket(ctx, "3")
 	assert.Equal(fakeAssignment.Connection, ticket.Assignment.Connection)
 	assert.Nil(err)
+	// Assigning again reports the ticket as already assigned.
+	_, alreadyAssigned, err = service.UpdateAssignments(ctx, []string{"1"}, fakeAssignment)
+	assert.Nil(err)
+	assert.Equal([]string{"1"}, alreadyAssigned)
+}
+
+func TestUpdateAssignmentSurvivesOverwrite(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	service := New(cfg)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	ticket := &pb.Ticket{Id: "1"}
+	assert.Nil(service.CreateTicket(ctx, ticket))
+	_, _, err := service.UpdateAssignments(ctx, []string{"1"}, &pb.Assignment{Connection: "localhost"})
+	assert.Nil(err)
+
+	// Writing back a ticket read before the assignment was set keeps it.
+	assert.Nil(service.CreateTicket(ctx, ticket))
+	tickets, err := service.GetTickets(ctx, []string{"1"})
+	assert.Nil(err)
+	assert.Len(tickets, 1)
+	assert.Equal("localhost", tickets[0].GetAssignment().GetConnection())
+
+	// Deleting the ticket deletes its assignment.
+	assert.Nil(service.DeleteTicket(ctx, "1"))
+	assert.Nil(service.CreateTicket(ctx, ticket))
+	got, err := service.GetTicket(ctx, "1")
+	assert.Nil(err)
+	assert.Nil(got.GetAssignment())
 }
 
 func TestConnect(t *testing.T) {
@@ -322,7 +958,64 @@ func TestConnect(t *testing.T) {
 	assert.Nil(conn)
 }
 
-func createRedis(t *testing.T) (config.View, func()) {
+// leaseTickets leases the tickets, which must not be leased by anyone else.
+func leaseTickets(t *testing.T, service Service, ids []string) {
+	held, err := service.AcquireTicketLease(utilTesting.NewContext(t), "owner", ids)
+	assert.Nil(t, err)
+	assert.Empty(t, held)
+}
+
+func TestReadReplica(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	replica, err := miniredis.Run()
+	assert.Nil(err)
+	cfg.Set("redis.readReplica.hostname", replica.Host())
+	cfg.Set("redis.readReplica.port", replica.Port())
+	service := New(cfg)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+	replicaCtx := WithReplicaReads(ctx)
+
+	// Writes go to the primary, which the unreplicated replica doesn't see.
+	ticket := &pb.Ticket{Id: "1"}
+	assert.Nil(service.CreateTicket(ctx, ticket))
+	assert.Nil(service.IndexTicket(ctx, ticket))
+
+	_, err = service.GetTicket(ctx, "1")
+	assert.Nil(err)
+	_, err = service.GetTicket(replicaCtx, "1")
+	assert.Equal(codes.NotFound, status.Code(err))
+	tickets, err := service.GetTickets(replicaCtx, []string{"1"})
+	assert.Nil(err)
+	assert.Empty(tickets)
+	ids, err := service.GetIndexedIDSet(replicaCtx)
+	assert.Nil(err)
+	assert.Empty(ids)
+
+	// Replicate the writes.
+	value, err := proto.Marshal(ticket)
+	assert.Nil(err)
+	assert.Nil(replica.Set("1", string(value)))
+	_, err = replica.SetAdd("allTickets", "1")
+	assert.Nil(err)
+
+	_, err = service.GetTicket(replicaCtx, "1")
+	assert.Nil(err)
+	ids, err = service.GetIndexedIDSet(replicaCtx)
+	assert.Nil(err)
+	assert.Contains(ids, "1")
+
+	// Reads fall back to the primary if the replica is unreachable.
+	replica.Close()
+	service = New(cfg)
+	defer service.Close()
+	_, err = service.GetTicket(replicaCtx, "1")
+	assert.Nil(err)
+}
+
+func createRedis(t *testing.T) (config.Mutable, func()) {
 	cfg := viper.New()
 	mredis, err := miniredis.Run()
 	if err != nil {
@@ -348,3 +1041,34 @@ func createRedis(t *testing.T) (config.View, func()) {
 }
 
 // TODO: test Redis connection with Auth
+
+func TestIdempotencyKey(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	service := New(cfg)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	claimedID, err := service.ClaimIdempotencyKey(ctx, "retry", "1", time.Minute)
+	assert.Nil(err)
+	assert.Equal("", claimedID)
+	claimedID, err = service.ClaimIdempotencyKey(ctx, "retry", "2", time.Minute)
+	assert.Nil(err)
+	assert.Equal("1", claimedID)
+
+	// Only the ticket the key was claimed for releases it.
+	assert.Nil(service.ReleaseIdempotencyKey(ctx, "retry", "2"))
+	claimedID, err = service.ClaimIdempotencyKey(ctx, "retry", "2", time.Minute)
+	assert.Nil(err)
+	assert.Equal("1", claimedID)
+	assert.Nil(service.ReleaseIdempotencyKey(ctx, "retry", "1"))
+	claimedID, err = service.ClaimIdempotencyKey(ctx, "retry", "2", time.Minute)
+	assert.Nil(err)
+	assert.Equal("", claimedID)
+
+	// Idempotency keys are neither tickets nor counted as such.
+	usage, err := service.GetStorageUsage(ctx, 10)
+	assert.Nil(err)
+	assert.Equal(int64(0), usage.GetTicketCount())
+}
@@ -0,0 +1,100 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"open-match.dev/open-match/internal/clock"
+	"open-match.dev/open-match/internal/config"
+)
+
+const (
+	// configNameBackend selects the registered backend used by New.
+	configNameBackend = "storage.backend"
+	defaultBackend    = "redis"
+)
+
+var (
+	logger = logrus.WithFields(logrus.Fields{
+		"app":       "openmatch",
+		"component": "statestore",
+	})
+
+	backendsMutex sync.RWMutex
+	backends      = make(map[string]Factory)
+)
+
+// Factory creates a Service from the configuration.  The clock is used to
+// timestamp and expire entries in the ignore list.
+type Factory func(cfg config.View, clk clock.Clock) Service
+
+func init() {
+	Register(defaultBackend, newRedis)
+}
+
+// Register makes a storage backend available to New under name, selected by
+// setting storage.backend.  It is meant to be called from the init function of
+// the package implementing the backend, and panics if name is already
+// registered or factory is nil.
+func Register(name string, factory Factory) {
+	backendsMutex.Lock()
+	defer backendsMutex.Unlock()
+
+	if factory == nil {
+		panic("statestore: Register factory is nil")
+	}
+	if _, dup := backends[name]; dup {
+		panic(fmt.Sprintf("statestore: Register called twice for backend %q", name))
+	}
+	backends[name] = factory
+}
+
+// Backends returns the sorted names of the registered storage backends.
+func Backends() []string {
+	backendsMutex.RLock()
+	defer backendsMutex.RUnlock()
+
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newBackend creates the Service for the backend selected by storage.backend,
+// defaulting to Redis.
+func newBackend(cfg config.View, clk clock.Clock) Service {
+	name := defaultBackend
+	if cfg.IsSet(configNameBackend) {
+		name = cfg.GetString(configNameBackend)
+	}
+
+	backendsMutex.RLock()
+	factory, ok := backends[name]
+	backendsMutex.RUnlock()
+
+	if !ok {
+		logger.WithFields(logrus.Fields{
+			"backend":    name,
+			"registered": Backends(),
+		}).Fatal("Unknown storage backend.")
+	}
+	return factory(cfg, clk)
+}
@@ -0,0 +1,58 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"open-match.dev/open-match/internal/clock"
+	"open-match.dev/open-match/internal/config"
+)
+
+type fakeBackend struct {
+	Service
+	cfg config.View
+}
+
+func TestRegisterBackend(t *testing.T) {
+	assert := assert.New(t)
+	Register("fake", func(cfg config.View, clk clock.Clock) Service {
+		return &fakeBackend{cfg: cfg}
+	})
+	assert.Contains(Backends(), "fake")
+	assert.Contains(Backends(), "redis")
+
+	cfg := viper.New()
+	cfg.Set("storage.backend", "fake")
+	s := New(cfg)
+	if assert.IsType(&fakeBackend{}, s) {
+		assert.Equal(cfg, s.(*fakeBackend).cfg)
+	}
+
+	assert.Panics(func() {
+		Register("fake", func(config.View, clock.Clock) Service { return nil })
+	})
+	assert.Panics(func() {
+		Register("nil", nil)
+	})
+}
+
+func TestDefaultBackendIsRedis(t *testing.T) {
+	s := New(viper.New())
+	defer s.Close()
+	assert.IsType(t, &redisBackend{}, s)
+}
@@ -0,0 +1,141 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/telemetry"
+)
+
+const (
+	// replicationHeartbeat holds the time it was last written on the primary,
+	// so that the replica's lag can be told from its copy.
+	replicationHeartbeat = "replicationHeartbeat"
+	// unknownLag is the lag of a replica whose heartbeat can't be read.
+	unknownLag = int64(-1)
+)
+
+var (
+	mReplicationLagMs = telemetry.Gauge("redis/replicationlag", "age in ms of the newest heartbeat replicated to the read replica, -1 if unknown")
+)
+
+// replicationMonitor measures how far the read replica lags the primary, eg.
+// when it's in another region.  Every interval it reads the heartbeat from the
+// replica, then writes the current time to the primary.  The lag is the age of
+// the heartbeat read, an upper bound of the actual lag to within the interval.
+type replicationMonitor struct {
+	primary  *redis.Pool
+	replica  *redis.Pool
+	key      string
+	interval time.Duration
+
+	start sync.Once
+	stop  chan struct{}
+	// lag is the last measured lag in nanoseconds, or unknownLag.
+	lag int64
+}
+
+func newReplicationMonitor(cfg config.View, primary, replica *redis.Pool, key string) *replicationMonitor {
+	interval := time.Second
+	if cfg.IsSet("redis.readReplica.lagCheckInterval") {
+		interval = cfg.GetDuration("redis.readReplica.lagCheckInterval")
+	}
+	return &replicationMonitor{
+		primary:  primary,
+		replica:  replica,
+		key:      key,
+		interval: interval,
+		stop:     make(chan struct{}),
+		lag:      unknownLag,
+	}
+}
+
+// within returns whether the replica is known to lag by at most bound, or
+// true if bound isn't positive.  It starts monitoring the replica on first
+// use, so that processes which never read from it don't write heartbeats.
+func (m *replicationMonitor) within(bound time.Duration) bool {
+	m.start.Do(func() {
+		go m.run()
+	})
+	if bound <= 0 {
+		return true
+	}
+	lag := atomic.LoadInt64(&m.lag)
+	return lag != unknownLag && time.Duration(lag) <= bound
+}
+
+func (m *replicationMonitor) run() {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		m.check()
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check measures the lag of the replica, and writes the next heartbeat.
+func (m *replicationMonitor) check() {
+	ctx, cancel := context.WithTimeout(context.Background(), m.interval)
+	defer cancel()
+
+	lag := unknownLag
+	heartbeat, err := redis.Int64(m.do(ctx, m.replica, "GET", m.key))
+	switch {
+	case err == nil:
+		lag = time.Now().UnixNano() - heartbeat
+		if lag < 0 {
+			lag = 0
+		}
+		telemetry.SetGauge(ctx, mReplicationLagMs, time.Duration(lag).Milliseconds())
+	case err == redis.ErrNil:
+		telemetry.SetGauge(ctx, mReplicationLagMs, unknownLag)
+	default:
+		redisLogger.WithError(err).Warning("failed to read the replication heartbeat from the read replica")
+		telemetry.SetGauge(ctx, mReplicationLagMs, unknownLag)
+	}
+	atomic.StoreInt64(&m.lag, lag)
+
+	if _, err = m.do(ctx, m.primary, "SET", m.key, time.Now().UnixNano()); err != nil {
+		redisLogger.WithError(err).Warning("failed to write the replication heartbeat")
+	}
+}
+
+func (m *replicationMonitor) do(ctx context.Context, pool *redis.Pool, cmd string, args ...interface{}) (interface{}, error) {
+	redisConn, err := pool.GetContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer handleConnectionClose(&redisConn)
+	return redisConn.Do(cmd, args...)
+}
+
+func (m *replicationMonitor) close() {
+	m.start.Do(func() {})
+	select {
+	case <-m.stop:
+	default:
+		close(m.stop)
+	}
+}
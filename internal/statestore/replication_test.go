@@ -0,0 +1,66 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestAllowStaleReads(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	replica, err := miniredis.Run()
+	assert.Nil(err)
+	defer replica.Close()
+	cfg.Set("redis.readReplica.hostname", replica.Host())
+	cfg.Set("redis.readReplica.port", replica.Port())
+	cfg.Set("redis.readReplica.lagCheckInterval", 10*time.Millisecond)
+	cfg.Set("storage.allowStaleReads", time.Minute)
+	service := New(cfg)
+	defer service.Close()
+	replicaCtx := WithReplicaReads(utilTesting.NewContext(t))
+
+	// The ticket is only on the replica, so finding it tells replica reads.
+	value, err := proto.Marshal(&pb.Ticket{Id: "1"})
+	assert.Nil(err)
+	assert.Nil(replica.Set("1", string(value)))
+	readsReplica := func() bool {
+		_, err := service.GetTicket(replicaCtx, "1")
+		if err != nil {
+			assert.Equal(codes.NotFound, status.Code(err))
+		}
+		return err == nil
+	}
+
+	// Without a replicated heartbeat, the lag is unknown.
+	assert.False(readsReplica())
+
+	assert.Nil(replica.Set(replicationHeartbeat, strconv.FormatInt(time.Now().UnixNano(), 10)))
+	assert.Eventually(readsReplica, 5*time.Second, 10*time.Millisecond)
+
+	assert.Nil(replica.Set(replicationHeartbeat, strconv.FormatInt(time.Now().Add(-time.Hour).UnixNano(), 10)))
+	assert.Eventually(func() bool { return !readsReplica() }, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,187 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// requeueResult is what happened to a ticket reported by
+// RequeueFailedAssignments.
+type requeueResult int
+
+const (
+	requeued requeueResult = iota
+	requeueNotFound
+	requeueNotAssigned
+	requeueExhausted
+)
+
+// RequeueFailedAssignments clears the assignments of the tickets, counts the
+// failures in their AssignmentFailures, and indexes them again, as long as
+// they failed fewer than maxFailures times before, or any number of times if
+// maxFailures isn't positive.  Returns how many tickets were requeued, the ids
+// of the tickets which don't exist, and of those which failed too often and
+// are left assigned.  Tickets which aren't assigned are left untouched, so
+// that reports can be retried.
+func (rb *redisBackend) RequeueFailedAssignments(ctx context.Context, ids []string, maxFailures int) (requeuedCount int64, notFound []string, exhausted []string, err error) {
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	for _, id := range ids {
+		if err = ctx.Err(); err != nil {
+			return requeuedCount, notFound, exhausted, err
+		}
+		result, err := rb.requeueTicket(redisConn, id, maxFailures)
+		if err != nil {
+			return requeuedCount, notFound, exhausted, err
+		}
+		switch result {
+		case requeued:
+			requeuedCount++
+		case requeueNotFound:
+			notFound = append(notFound, id)
+		case requeueExhausted:
+			exhausted = append(exhausted, id)
+		}
+	}
+	return requeuedCount, notFound, exhausted, nil
+}
+
+// requeueTicket clears the assignment of the ticket and indexes it again in a
+// single transaction, retrying if the ticket or its assignment changes in the
+// meantime.  The ticket's expiration is kept.
+func (rb *redisBackend) requeueTicket(redisConn redis.Conn, id string, maxFailures int) (requeueResult, error) {
+	key := rb.keys.ticket(id)
+	for attempt := 0; attempt < maxRewriteAttempts; attempt++ {
+		if _, err := redisConn.Do("WATCH", key, rb.keys.assignment(id)); err != nil {
+			redisLogger.WithError(err).Error("failed to watch ticket")
+			return requeueNotFound, status.Errorf(codes.Internal, "%v", err)
+		}
+
+		values, err := redis.ByteSlices(redisConn.Do("MGET", key, rb.keys.assignment(id)))
+		if err != nil {
+			redisLogger.WithFields(logrus.Fields{
+				"cmd":   "MGET",
+				"key":   id,
+				"error": err.Error(),
+			}).Error("failed to get the ticket from state storage")
+			return requeueNotFound, status.Errorf(codes.Internal, "%v", err)
+		}
+		if values[0] == nil {
+			_, err = redisConn.Do("UNWATCH")
+			return requeueNotFound, err
+		}
+
+		ticket := &pb.Ticket{}
+		if err = unmarshalTicket(values[0], ticket); err != nil {
+			redisLogger.WithFields(logrus.Fields{
+				"key":   id,
+				"error": err.Error(),
+			}).Error("failed to unmarshal the ticket proto")
+			return requeueNotFound, status.Errorf(codes.Internal, "%v", err)
+		}
+		if values[1] == nil && ticket.GetAssignment() == nil {
+			_, err = redisConn.Do("UNWATCH")
+			return requeueNotAssigned, err
+		}
+		if maxFailures > 0 && int(ticket.GetAssignmentFailures()) >= maxFailures {
+			_, err = redisConn.Do("UNWATCH")
+			return requeueExhausted, err
+		}
+
+		ticket.Assignment = nil
+		ticket.AssignmentFailures++
+		value, err := rb.serializer.marshal(ticket)
+		if err != nil {
+			redisLogger.WithFields(logrus.Fields{
+				"key":   id,
+				"error": err.Error(),
+			}).Error("failed to marshal the ticket proto")
+			return requeueNotFound, status.Errorf(codes.Internal, "%v", err)
+		}
+
+		ttl, err := redis.Int64(redisConn.Do("PTTL", key))
+		if err != nil {
+			redisLogger.WithError(err).Error("failed to get ticket expiration")
+			return requeueNotFound, status.Errorf(codes.Internal, "%v", err)
+		}
+		var expiration []interface{}
+		if ttl > 0 {
+			expiration = []interface{}{"PX", ttl}
+		}
+
+		err = redisConn.Send("MULTI")
+		if err == nil {
+			err = redisConn.Send("SET", append([]interface{}{key, value}, expiration...)...)
+		}
+		if err == nil {
+			err = redisConn.Send("DEL", rb.keys.assignment(id))
+		}
+		if err == nil {
+			err = redisConn.Send("SET", append([]interface{}{rb.keys.state(id), pb.Ticket_SEARCHING.String()}, expiration...)...)
+		}
+		if err == nil {
+			err = redisConn.Send("INCR", rb.keys.version(id))
+		}
+		if err == nil && ttl > 0 {
+			err = redisConn.Send("PEXPIRE", rb.keys.version(id), ttl)
+		}
+		if err == nil {
+			err = redisConn.Send("ZREM", rb.keys.ignoreList(), id)
+		}
+		if err == nil {
+			err = redisConn.Send("HDEL", rb.keys.leaseOwners(), id)
+		}
+		if err == nil {
+			err = redisConn.Send("SADD", rb.keys.allTickets(), id)
+		}
+		if err == nil {
+			err = sendFieldIndexAdd(redisConn, rb.keys, ticket)
+		}
+		if err == nil {
+			err = redisConn.Send("PUBLISH", rb.keys.assignmentChannelPrefix()+id, "")
+		}
+		if err != nil {
+			return requeueNotFound, status.Errorf(codes.Internal, "%v", err)
+		}
+
+		_, err = redis.Values(redisConn.Do("EXEC"))
+		if err == redis.ErrNil {
+			// The ticket was modified concurrently, try again.
+			continue
+		}
+		if err != nil {
+			redisLogger.WithFields(logrus.Fields{
+				"cmd":   "EXEC",
+				"key":   id,
+				"error": err.Error(),
+			}).Error("failed to requeue the ticket in state storage")
+			return requeueNotFound, status.Errorf(codes.Internal, "%v", err)
+		}
+		return requeued, nil
+	}
+
+	return requeueNotFound, status.Errorf(codes.Aborted, "ticket %s was concurrently modified %d times while requeueing", id, maxRewriteAttempts)
+}
@@ -0,0 +1,77 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestRequeueFailedAssignments(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	service := New(cfg)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	tickets := []*pb.Ticket{
+		{Id: "assigned"},
+		{Id: "embedded", Assignment: &pb.Assignment{Connection: "embedded"}},
+		{Id: "exhausted", AssignmentFailures: 2},
+		{Id: "unassigned"},
+	}
+	assert.Nil(service.CreateTickets(ctx, tickets))
+	_, _, err := service.UpdateAssignments(ctx, []string{"assigned", "exhausted"}, &pb.Assignment{Connection: "gameserver"})
+	assert.Nil(err)
+	leaseTickets(t, service, []string{"assigned"})
+	assert.Nil(service.DeindexTickets(ctx, []string{"assigned", "embedded", "exhausted"}))
+
+	requeued, notFound, exhausted, err := service.RequeueFailedAssignments(ctx, []string{"assigned", "embedded", "exhausted", "unassigned", "missing"}, 2)
+	assert.Nil(err)
+	assert.Equal(int64(2), requeued)
+	assert.Equal([]string{"missing"}, notFound)
+	assert.Equal([]string{"exhausted"}, exhausted)
+
+	for _, id := range []string{"assigned", "embedded"} {
+		ticket, err := service.GetTicket(ctx, id)
+		assert.Nil(err)
+		assert.Nil(ticket.GetAssignment())
+		assert.Equal(int32(1), ticket.GetAssignmentFailures())
+
+		status, err := service.GetTicketStatus(ctx, id)
+		assert.Nil(err)
+		assert.Equal(&TicketStatus{Indexed: true}, status)
+	}
+
+	ticket, err := service.GetTicket(ctx, "unassigned")
+	assert.Nil(err)
+	assert.Equal(int32(0), ticket.GetAssignmentFailures())
+
+	status, err := service.GetTicketStatus(ctx, "exhausted")
+	assert.Nil(err)
+	assert.Equal(&TicketStatus{Assigned: true}, status)
+
+	// A repeated report leaves requeued tickets alone.
+	requeued, _, _, err = service.RequeueFailedAssignments(ctx, []string{"assigned"}, 2)
+	assert.Nil(err)
+	assert.Equal(int64(0), requeued)
+	ticket, err = service.GetTicket(ctx, "assigned")
+	assert.Nil(err)
+	assert.Equal(int32(1), ticket.GetAssignmentFailures())
+}
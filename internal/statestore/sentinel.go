@@ -0,0 +1,187 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/sirupsen/logrus"
+	"open-match.dev/open-match/internal/config"
+)
+
+var errFailedOver = errors.New("redis master failed over, connection discarded")
+
+// sentinel discovers the current Redis master through Redis Sentinel, following
+// https://redis.io/topics/sentinel-clients.  It's configured with
+// redis.sentinel.masterName and redis.sentinel.addresses.
+type sentinel struct {
+	masterName string
+
+	m sync.Mutex
+	// addresses of the sentinels, the last one to answer is moved to the front.
+	addresses []string
+}
+
+func newSentinel(cfg config.View) *sentinel {
+	return &sentinel{
+		masterName: cfg.GetString("redis.sentinel.masterName"),
+		addresses:  cfg.GetStringSlice("redis.sentinel.addresses"),
+	}
+}
+
+// masterAddress asks the sentinels in turn for the address of the master.
+func (s *sentinel) masterAddress(timeout time.Duration) (string, error) {
+	s.m.Lock()
+	addresses := append([]string{}, s.addresses...)
+	s.m.Unlock()
+
+	var errs []string
+	for i, sentinelAddress := range addresses {
+		addr, err := s.queryMaster(sentinelAddress, timeout)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", sentinelAddress, err))
+			continue
+		}
+
+		if i > 0 {
+			s.promote(sentinelAddress)
+		}
+		return addr, nil
+	}
+	return "", fmt.Errorf("no sentinel knows the address of master %s: %s", s.masterName, strings.Join(errs, "; "))
+}
+
+func (s *sentinel) queryMaster(sentinelAddress string, timeout time.Duration) (string, error) {
+	conn, err := redis.Dial("tcp", sentinelAddress, redis.DialConnectTimeout(timeout), redis.DialReadTimeout(timeout), redis.DialWriteTimeout(timeout))
+	if err != nil {
+		return "", err
+	}
+	defer handleConnectionClose(&conn)
+
+	hostPort, err := redis.Strings(conn.Do("SENTINEL", "get-master-addr-by-name", s.masterName))
+	if err == redis.ErrNil {
+		return "", fmt.Errorf("unknown master")
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(hostPort) != 2 {
+		return "", fmt.Errorf("unexpected reply %v", hostPort)
+	}
+	return net.JoinHostPort(hostPort[0], hostPort[1]), nil
+}
+
+// promote moves the sentinel to the front, so it's asked first next time.
+func (s *sentinel) promote(sentinelAddress string) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	for i, a := range s.addresses {
+		if a == sentinelAddress {
+			copy(s.addresses[1:i+1], s.addresses[:i])
+			s.addresses[0] = sentinelAddress
+			return
+		}
+	}
+}
+
+// dial connects to the current master, and checks that it still is the master
+// as the sentinels may lag behind a failover.
+func (s *sentinel) dial(timeout time.Duration, options ...redis.DialOption) (redis.Conn, error) {
+	addr, err := s.masterAddress(timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := redis.Dial("tcp", addr, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := redis.Values(conn.Do("ROLE"))
+	if rerr, ok := err.(redis.Error); ok && strings.Contains(strings.ToLower(rerr.Error()), "unknown command") {
+		// Redis compatible servers which don't implement ROLE are trusted.
+		return &masterConn{Conn: conn}, nil
+	}
+	if err == nil && len(role) > 0 {
+		var r string
+		r, err = redis.String(role[0], nil)
+		if err == nil && r != "master" {
+			err = fmt.Errorf("%s has role %s", addr, r)
+		}
+	}
+	if err != nil {
+		handleConnectionClose(&conn)
+		redisLogger.WithFields(logrus.Fields{
+			"address": addr,
+			"error":   err.Error(),
+		}).Warning("redis sentinel returned an address which is not the master")
+		return nil, err
+	}
+
+	return &masterConn{Conn: conn}, nil
+}
+
+// masterConn is a connection to the Redis master.  Once the master is demoted
+// to a replica, writes fail with READONLY and the connection reports an error
+// so the pool discards it and dials the new master.
+type masterConn struct {
+	redis.Conn
+	failedOver bool
+}
+
+func (c *masterConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	reply, err := c.Conn.Do(commandName, args...)
+	c.check(reply, err)
+	return reply, err
+}
+
+func (c *masterConn) Receive() (interface{}, error) {
+	reply, err := c.Conn.Receive()
+	c.check(reply, err)
+	return reply, err
+}
+
+func (c *masterConn) Err() error {
+	if c.failedOver {
+		return errFailedOver
+	}
+	return c.Conn.Err()
+}
+
+func (c *masterConn) check(reply interface{}, err error) {
+	if isReadOnlyError(err) {
+		c.failedOver = true
+	}
+	// Errors of queued commands are returned in the reply to EXEC.
+	if values, isArray := reply.([]interface{}); isArray {
+		for _, v := range values {
+			if verr, isErr := v.(redis.Error); isErr && isReadOnlyError(verr) {
+				c.failedOver = true
+			}
+		}
+	}
+}
+
+func isReadOnlyError(err error) bool {
+	rerr, ok := err.(redis.Error)
+	return ok && strings.HasPrefix(string(rerr), "READONLY")
+}
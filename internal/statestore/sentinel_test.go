@@ -0,0 +1,184 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"open-match.dev/open-match/internal/clock"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// fakeSentinel answers SENTINEL get-master-addr-by-name for a single master.
+type fakeSentinel struct {
+	ln         net.Listener
+	masterName string
+
+	m      sync.Mutex
+	master *miniredis.Miniredis
+}
+
+func newFakeSentinel(t *testing.T, masterName string, master *miniredis.Miniredis) *fakeSentinel {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	s := &fakeSentinel{ln: ln, masterName: masterName, master: master}
+	go s.serve()
+	return s
+}
+
+func (s *fakeSentinel) setMaster(master *miniredis.Miniredis) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.master = master
+}
+
+func (s *fakeSentinel) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeSentinel) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		if len(args) == 3 && strings.EqualFold(args[0], "SENTINEL") && args[2] == s.masterName {
+			s.m.Lock()
+			host, port := s.master.Host(), s.master.Port()
+			s.m.Unlock()
+			fmt.Fprintf(conn, "*2\r\n$%d\r\n%s\r\n$%d\r\n%s\r\n", len(host), host, len(port), port)
+		} else {
+			fmt.Fprint(conn, "*-1\r\n")
+		}
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line)[1:])
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		if _, err = r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		if args[i], err = r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSpace(args[i])
+	}
+	return args, nil
+}
+
+func TestSentinelFollowsFailover(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+
+	first, err := miniredis.Run()
+	require.Nil(t, err)
+	defer first.Close()
+	second, err := miniredis.Run()
+	require.Nil(t, err)
+	defer second.Close()
+
+	s := newFakeSentinel(t, "mymaster", first)
+	defer s.ln.Close()
+
+	// The first sentinel is down, the second one answers.
+	down, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	down.Close()
+
+	cfg.Set("redis.sentinel.masterName", "mymaster")
+	cfg.Set("redis.sentinel.addresses", []string{down.Addr().String(), s.ln.Addr().String()})
+	cfg.Set("redis.pool.maxIdle", 0)
+	service := newRedis(cfg, clock.Real())
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	assert.Nil(service.HealthCheck(ctx))
+	assert.Nil(service.CreateTicket(ctx, &pb.Ticket{Id: "1"}))
+	assert.True(first.Exists("1"))
+
+	s.setMaster(second)
+	assert.Nil(service.CreateTicket(ctx, &pb.Ticket{Id: "2"}))
+	assert.True(second.Exists("2"))
+	assert.False(first.Exists("2"))
+
+	// The sentinel which answered is asked first from now on.
+	sentinel := newSentinel(cfg)
+	addr, err := sentinel.masterAddress(time.Second)
+	assert.Nil(err)
+	assert.Equal(second.Addr(), addr)
+	assert.Equal([]string{s.ln.Addr().String(), down.Addr().String()}, sentinel.addresses)
+
+	cfg.Set("redis.sentinel.masterName", "unknown")
+	_, err = newSentinel(cfg).masterAddress(time.Second)
+	assert.NotNil(err)
+}
+
+type fakeConn struct {
+	redis.Conn
+	reply interface{}
+	err   error
+}
+
+func (c *fakeConn) Do(string, ...interface{}) (interface{}, error) { return c.reply, c.err }
+func (c *fakeConn) Err() error                                     { return nil }
+
+func TestMasterConnDetectsReadOnly(t *testing.T) {
+	assert := assert.New(t)
+
+	c := &masterConn{Conn: &fakeConn{reply: "OK"}}
+	_, err := c.Do("SET", "a", "b")
+	assert.Nil(err)
+	assert.Nil(c.Err())
+
+	c = &masterConn{Conn: &fakeConn{err: redis.Error("READONLY You can't write against a read only replica.")}}
+	_, err = c.Do("SET", "a", "b")
+	assert.NotNil(err)
+	assert.Equal(errFailedOver, c.Err())
+
+	c = &masterConn{Conn: &fakeConn{reply: []interface{}{redis.Error("READONLY You can't write against a read only replica.")}}}
+	_, err = c.Do("EXEC")
+	assert.Nil(err)
+	assert.Equal(errFailedOver, c.Err())
+}
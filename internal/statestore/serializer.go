@@ -0,0 +1,140 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/sirupsen/logrus"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+const (
+	// configNameEncoding selects the serializer Tickets are written with.
+	configNameEncoding = "storage.encoding"
+	defaultEncoding    = "proto"
+)
+
+// serializer encodes Tickets into the values stored in Redis.
+type serializer interface {
+	marshal(ticket *pb.Ticket) ([]byte, error)
+	unmarshal(value []byte, ticket *pb.Ticket) error
+}
+
+// serializers are the encodings storage.encoding may select.
+var serializers = map[string]serializer{
+	"proto":      protoSerializer{},
+	"protojson":  jsonSerializer{},
+	"gzip-proto": gzipSerializer{},
+}
+
+// newSerializer returns the serializer selected by storage.encoding,
+// defaulting to proto.
+func newSerializer(cfg config.View) serializer {
+	name := defaultEncoding
+	if cfg.IsSet(configNameEncoding) {
+		name = cfg.GetString(configNameEncoding)
+	}
+	s, ok := serializers[name]
+	if !ok {
+		redisLogger.WithFields(logrus.Fields{
+			"encoding": name,
+		}).Fatal("Unknown storage encoding.")
+	}
+	return s
+}
+
+// unmarshalTicket decodes a Ticket written with any of the serializers, so
+// that changing storage.encoding doesn't strand the Tickets already stored.
+// The encoding is told apart by the first bytes: neither the gzip magic number
+// nor an opening brace can start a serialized Ticket proto.
+func unmarshalTicket(value []byte, ticket *pb.Ticket) error {
+	switch {
+	case len(value) >= 2 && value[0] == 0x1f && value[1] == 0x8b:
+		return gzipSerializer{}.unmarshal(value, ticket)
+	case len(value) >= 1 && value[0] == '{':
+		return jsonSerializer{}.unmarshal(value, ticket)
+	default:
+		return protoSerializer{}.unmarshal(value, ticket)
+	}
+}
+
+// protoSerializer stores Tickets as their proto wire format, the most compact
+// uncompressed encoding.
+type protoSerializer struct{}
+
+func (protoSerializer) marshal(ticket *pb.Ticket) ([]byte, error) {
+	return proto.Marshal(ticket)
+}
+
+func (protoSerializer) unmarshal(value []byte, ticket *pb.Ticket) error {
+	return proto.Unmarshal(value, ticket)
+}
+
+// jsonSerializer stores Tickets as JSON, which can be inspected with
+// redis-cli.  Tickets with extensions of types unknown to Open Match can't be
+// rendered as JSON, and are stored as proto instead.
+type jsonSerializer struct{}
+
+func (jsonSerializer) marshal(ticket *pb.Ticket) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := (&jsonpb.Marshaler{}).Marshal(&buf, ticket); err != nil {
+		return proto.Marshal(ticket)
+	}
+	return buf.Bytes(), nil
+}
+
+func (jsonSerializer) unmarshal(value []byte, ticket *pb.Ticket) error {
+	u := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	return u.Unmarshal(bytes.NewReader(value), ticket)
+}
+
+// gzipSerializer stores Tickets as gzip compressed proto, trading CPU for
+// memory with Tickets carrying large extensions.
+type gzipSerializer struct{}
+
+func (gzipSerializer) marshal(ticket *pb.Ticket) ([]byte, error) {
+	value, err := proto.Marshal(ticket)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err = w.Write(value); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gzipSerializer) unmarshal(value []byte, ticket *pb.Ticket) error {
+	r, err := gzip.NewReader(bytes.NewReader(value))
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	value, err = ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(value, ticket)
+}
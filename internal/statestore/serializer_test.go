@@ -0,0 +1,90 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+	"open-match.dev/open-match/internal/clock"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestSerializers(t *testing.T) {
+	known, err := ptypes.MarshalAny(&wrappers.StringValue{Value: "eu"})
+	assert.Nil(t, err)
+	tickets := []*pb.Ticket{
+		{Id: "1"},
+		{
+			Id:           "2",
+			SearchFields: &pb.SearchFields{DoubleArgs: map[string]float64{"mmr": 1500}, Tags: []string{"ranked"}},
+			Extensions:   map[string]*any.Any{"region": known},
+		},
+		// Extensions of unknown types can't be rendered as JSON.
+		{Id: "3", Extensions: map[string]*any.Any{"custom": {TypeUrl: "example.com/Custom", Value: []byte{1, 2, 3}}}},
+	}
+
+	for name, s := range serializers {
+		for _, ticket := range tickets {
+			value, err := s.marshal(ticket)
+			assert.Nil(t, err, name)
+			got := &pb.Ticket{}
+			assert.Nil(t, unmarshalTicket(value, got), name)
+			assert.True(t, proto.Equal(ticket, got), name)
+		}
+	}
+
+	value, err := jsonSerializer{}.marshal(tickets[0])
+	assert.Nil(t, err)
+	assert.Equal(t, `{"id":"1"}`, string(value))
+}
+
+func TestEncodingChange(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	ctx := utilTesting.NewContext(t)
+
+	cfg.Set("storage.encoding", "gzip-proto")
+	gzipService := New(cfg)
+	defer gzipService.Close()
+	assert.Nil(gzipService.CreateTicket(ctx, &pb.Ticket{Id: "1"}))
+
+	cfg.Set("storage.encoding", "protojson")
+	rb := newRedis(cfg, clock.Real()).(*redisBackend)
+	defer rb.Close()
+	assert.Nil(rb.CreateTicket(ctx, &pb.Ticket{Id: "2"}))
+
+	redisConn, err := rb.connect(ctx)
+	assert.Nil(err)
+	defer redisConn.Close()
+	value, err := redis.String(redisConn.Do("GET", "2"))
+	assert.Nil(err)
+	assert.Equal(`{"id":"2"}`, value)
+
+	// Tickets written with the previous encoding remain readable.
+	tickets, err := rb.GetTickets(ctx, []string{"1", "2"})
+	assert.Nil(err)
+	assert.Len(tickets, 2)
+	ticket, err := gzipService.GetTicket(ctx, "2")
+	assert.Nil(err)
+	assert.Equal("2", ticket.GetId())
+}
@@ -0,0 +1,209 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/gomodule/redigo/redis"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// ExportTickets calls f with pages of snapshots of every Ticket in state storage, indexed or not.  Tickets written
+// while exporting may or may not be included.
+func (rb *redisBackend) ExportTickets(ctx context.Context, f func([]*pb.TicketSnapshot) error) error {
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	err = scanKeys(ctx, redisConn, rb.keys.pattern("*"), func(scanned []string) error {
+		ids := []string{}
+		for _, key := range scanned {
+			if id, ok := rb.keys.ticketID(key); ok {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		for _, id := range ids {
+			if err := redisConn.Send("GET", rb.keys.ticket(id)); err != nil {
+				return err
+			}
+			if err := redisConn.Send("GET", rb.keys.assignment(id)); err != nil {
+				return err
+			}
+			if err := redisConn.Send("PTTL", rb.keys.ticket(id)); err != nil {
+				return err
+			}
+			if err := redisConn.Send("SISMEMBER", rb.keys.allTickets(), id); err != nil {
+				return err
+			}
+		}
+		if err := redisConn.Flush(); err != nil {
+			return err
+		}
+
+		snapshots := make([]*pb.TicketSnapshot, 0, len(ids))
+		for _, id := range ids {
+			value, err := redis.Bytes(redisConn.Receive())
+			if err != nil && err != redis.ErrNil {
+				return err
+			}
+			assignmentValue, err := redis.Bytes(redisConn.Receive())
+			if err != nil && err != redis.ErrNil {
+				return err
+			}
+			ttl, err := redis.Int64(redisConn.Receive())
+			if err != nil {
+				return err
+			}
+			indexed, err := redis.Bool(redisConn.Receive())
+			if err != nil {
+				return err
+			}
+			// Tickets may be deleted between the scan and the read.
+			if value == nil {
+				continue
+			}
+
+			ticket := &pb.Ticket{}
+			if err = unmarshalTicket(value, ticket); err != nil {
+				redisLogger.WithFields(logrus.Fields{
+					"key":   id,
+					"error": err.Error(),
+				}).Error("failed to unmarshal the ticket proto")
+				return status.Errorf(codes.Internal, "%v", err)
+			}
+			if err = mergeAssignment(ticket, assignmentValue); err != nil {
+				return err
+			}
+
+			snapshot := &pb.TicketSnapshot{Ticket: ticket, Indexed: indexed}
+			if ttl > 0 {
+				snapshot.Expiration = ptypes.DurationProto(time.Duration(ttl) * time.Millisecond)
+			}
+			snapshots = append(snapshots, snapshot)
+		}
+		if len(snapshots) == 0 {
+			return nil
+		}
+		return f(snapshots)
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); !ok {
+			redisLogger.WithError(err).Error("failed to export tickets")
+			err = status.Errorf(codes.Internal, "%v", err)
+		}
+		return err
+	}
+	return nil
+}
+
+// ImportTickets saves the Tickets of the snapshots in a single transaction, overwriting Tickets with the same ids,
+// and indexes those which were indexed.  Tickets keep the expiration of their snapshot, or get redis.expiration if
+// it's unset.
+func (rb *redisBackend) ImportTickets(ctx context.Context, snapshots []*pb.TicketSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	tickets := make([]*pb.Ticket, len(snapshots))
+	expirations := make([]time.Duration, len(snapshots))
+	for i, snapshot := range snapshots {
+		tickets[i] = snapshot.GetTicket()
+		if tickets[i].GetId() == "" {
+			return status.Errorf(codes.InvalidArgument, "snapshot %d has no ticket id", i)
+		}
+		if snapshot.GetExpiration() != nil {
+			expiration, err := ptypes.Duration(snapshot.GetExpiration())
+			if err != nil || expiration <= 0 {
+				return status.Errorf(codes.InvalidArgument, "snapshot %d has an invalid expiration", i)
+			}
+			expirations[i] = expiration
+		}
+	}
+	// Imports restore tickets which were already admitted, so they aren't
+	// held to the quota, but still count against it.
+	args, err := rb.createTicketsArgs(tickets, expirations, 0)
+	if err != nil {
+		return err
+	}
+
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	err = redisConn.Send("MULTI")
+	if err != nil {
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+
+	for _, ticket := range tickets {
+		// The assignment is saved with the ticket, clear any left over from a
+		// previous ticket with the same id.
+		err = redisConn.Send("DEL", rb.keys.assignment(ticket.GetId()))
+		if err != nil {
+			return status.Errorf(codes.Internal, "%v", err)
+		}
+	}
+
+	err = createTicketsScript.Send(redisConn, args...)
+	if err != nil {
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+
+	for _, snapshot := range snapshots {
+		if !snapshot.GetIndexed() {
+			continue
+		}
+		ticket := snapshot.GetTicket()
+		err = redisConn.Send("SADD", rb.keys.allTickets(), ticket.GetId())
+		if err != nil {
+			return status.Errorf(codes.Internal, "%v", err)
+		}
+		err = sendFieldIndexAdd(redisConn, rb.keys, ticket)
+		if err != nil {
+			return status.Errorf(codes.Internal, "%v", err)
+		}
+	}
+
+	// Caches may hold previous Tickets with the same ids.
+	err = redisConn.Send("INCR", rb.keys.ticketsRevision())
+	if err != nil {
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+
+	_, err = redisConn.Do("EXEC")
+	if err != nil {
+		redisLogger.WithFields(logrus.Fields{
+			"cmd":   "EXEC",
+			"count": len(snapshots),
+			"error": err.Error(),
+		}).Error("failed to import tickets")
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestExportImportTickets(t *testing.T) {
+	assert := assert.New(t)
+	ctx := utilTesting.NewContext(t)
+
+	srcCfg, srcCloser := createRedis(t)
+	defer srcCloser()
+	src := New(srcCfg)
+	defer src.Close()
+
+	queued := &pb.Ticket{Id: "queued", SearchFields: &pb.SearchFields{Tags: []string{"beta"}}}
+	assigned := &pb.Ticket{Id: "assigned"}
+	assert.Nil(src.CreateTicketsWithExpiration(ctx, []*pb.Ticket{queued}, time.Minute))
+	assert.Nil(src.IndexTicket(ctx, queued))
+	assert.Nil(src.CreateTicket(ctx, assigned))
+	_, _, err := src.UpdateAssignments(ctx, []string{"assigned"}, &pb.Assignment{Connection: "1.2.3.4:5678"})
+	assert.Nil(err)
+	// Backfills and other keys aren't exported.
+	assert.Nil(src.CreateBackfill(ctx, &pb.Backfill{Id: "backfill"}, nil))
+
+	snapshots := map[string]*pb.TicketSnapshot{}
+	var pages []*pb.TicketSnapshot
+	assert.Nil(src.ExportTickets(ctx, func(page []*pb.TicketSnapshot) error {
+		for _, s := range page {
+			snapshots[s.GetTicket().GetId()] = s
+		}
+		pages = append(pages, page...)
+		return nil
+	}))
+	assert.Len(snapshots, 2)
+	assert.True(snapshots["queued"].GetIndexed())
+	expiration, err := ptypes.Duration(snapshots["queued"].GetExpiration())
+	assert.Nil(err)
+	assert.True(expiration > 59*time.Second && expiration <= time.Minute, expiration)
+	assert.False(snapshots["assigned"].GetIndexed())
+	assert.Equal("1.2.3.4:5678", snapshots["assigned"].GetTicket().GetAssignment().GetConnection())
+
+	dstCfg, dstCloser := createRedis(t)
+	defer dstCloser()
+	dst := New(dstCfg)
+	defer dst.Close()
+
+	assert.Nil(dst.ImportTickets(ctx, pages))
+
+	got, err := dst.GetTicket(ctx, "queued")
+	assert.Nil(err)
+	assert.True(proto.Equal(queued, got))
+	got, err = dst.GetTicket(ctx, "assigned")
+	assert.Nil(err)
+	assert.Equal("1.2.3.4:5678", got.GetAssignment().GetConnection())
+
+	ids, err := dst.GetIndexedIDSet(ctx)
+	assert.Nil(err)
+	assert.Equal(map[string]struct{}{"queued": {}}, ids)
+	count, err := dst.CountTickets(ctx, &pb.Pool{TagPresentFilters: []*pb.TagPresentFilter{{Tag: "beta"}}})
+	assert.Nil(err)
+	assert.Equal(int64(1), count)
+
+	errStop := errors.New("stop")
+	var sent *pb.Assignment
+	err = dst.GetAssignments(ctx, "assigned", func(a *pb.Assignment) error {
+		sent = a
+		return errStop
+	})
+	assert.Equal(errStop, err)
+	assert.Equal("1.2.3.4:5678", sent.GetConnection())
+
+	err = dst.ImportTickets(ctx, []*pb.TicketSnapshot{{Ticket: &pb.Ticket{}}})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,185 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// Tickets move between states as they're written: CreateTicket stores them as
+// SEARCHING, or ASSIGNED if created with an assignment, UpdateAssignments
+// moves them to ASSIGNED and CleanUpExpiredTickets to EXPIRED.  A SEARCHING
+// ticket is reported as PROPOSED while it's leased to a proposal, since leases
+// expire without being written to.
+
+// GetTicketState returns the lifecycle state of the ticket.  Expired tickets
+// are reported as EXPIRED for storage.expiredTicketStateTTL once cleaned up,
+// and aren't found afterwards.
+func (rb *redisBackend) GetTicketState(ctx context.Context, id string) (pb.Ticket_State, error) {
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return pb.Ticket_STATE_UNSPECIFIED, err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	keys := append(rb.ticketKeys(id), rb.keys.state(id))
+	err = redisConn.Send("MGET", keys...)
+	if err == nil {
+		err = redisConn.Send("ZSCORE", rb.keys.ignoreList(), id)
+	}
+	if err == nil {
+		err = redisConn.Flush()
+	}
+	if err != nil {
+		redisLogger.WithFields(logrus.Fields{
+			"key":   id,
+			"error": err.Error(),
+		}).Error("failed to pipeline commands for GetTicketState")
+		return pb.Ticket_STATE_UNSPECIFIED, status.Errorf(codes.Internal, "%v", err)
+	}
+	values, err := redis.ByteSlices(redisConn.Receive())
+	if err != nil {
+		redisLogger.WithFields(logrus.Fields{
+			"cmd":   "MGET",
+			"key":   id,
+			"error": err.Error(),
+		}).Error("failed to get the ticket state from state storage")
+		return pb.Ticket_STATE_UNSPECIFIED, status.Errorf(codes.Internal, "%v", err)
+	}
+	leased, leaseErr := redis.Float64(redisConn.Receive())
+	if leaseErr != nil && leaseErr != redis.ErrNil {
+		redisLogger.WithFields(logrus.Fields{
+			"cmd":   "ZSCORE",
+			"key":   id,
+			"error": leaseErr.Error(),
+		}).Error("failed to get the ticket lease from state storage")
+		return pb.Ticket_STATE_UNSPECIFIED, status.Errorf(codes.Internal, "%v", leaseErr)
+	}
+
+	value, assignmentValue := pickTicket(values[:len(values)-1])
+	stored, ok := pb.Ticket_State_value[string(values[len(values)-1])]
+	state := pb.Ticket_State(stored)
+	if value == nil {
+		if state == pb.Ticket_EXPIRED {
+			return state, nil
+		}
+		return pb.Ticket_STATE_UNSPECIFIED, status.Errorf(codes.NotFound, "Ticket id:%s not found", id)
+	}
+	if !ok {
+		// The ticket was written before states were stored.
+		ticket := &pb.Ticket{}
+		err = unmarshalTicket(value, ticket)
+		if err != nil {
+			redisLogger.WithFields(logrus.Fields{
+				"key":   id,
+				"error": err.Error(),
+			}).Error("failed to unmarshal the ticket proto")
+			return pb.Ticket_STATE_UNSPECIFIED, status.Errorf(codes.Internal, "%v", err)
+		}
+		state = pb.Ticket_SEARCHING
+		if assignmentValue != nil || ticket.GetAssignment() != nil {
+			state = pb.Ticket_ASSIGNED
+		}
+	}
+	expiredBefore := rb.clk.Now().Add(-rb.cfg.GetDuration("storage.ignoreListTTL"))
+	if state == pb.Ticket_SEARCHING && leaseErr == nil && leased > float64(expiredBefore.UnixNano()) {
+		state = pb.Ticket_PROPOSED
+	}
+	return state, nil
+}
+
+// GetTicketStatus returns where the ticket is in the queue, from its keys, the
+// indexed ids and the ignore list, pipelined in a single round trip.
+func (rb *redisBackend) GetTicketStatus(ctx context.Context, id string) (*TicketStatus, error) {
+	redisConn, err := rb.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer handleConnectionClose(&redisConn)
+
+	err = redisConn.Send("MGET", rb.ticketKeys(id)...)
+	if err == nil {
+		err = redisConn.Send("SISMEMBER", rb.keys.allTickets(), id)
+	}
+	if err == nil {
+		err = redisConn.Send("ZSCORE", rb.keys.ignoreList(), id)
+	}
+	if err == nil {
+		err = redisConn.Flush()
+	}
+	if err != nil {
+		redisLogger.WithFields(logrus.Fields{
+			"key":   id,
+			"error": err.Error(),
+		}).Error("failed to pipeline commands for GetTicketStatus")
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	values, err := redis.ByteSlices(redisConn.Receive())
+	indexed, indexedErr := redis.Bool(redisConn.Receive())
+	leased, leaseErr := redis.Float64(redisConn.Receive())
+	if err == nil {
+		err = indexedErr
+	}
+	if err == nil && leaseErr != redis.ErrNil {
+		err = leaseErr
+	}
+	if err != nil {
+		redisLogger.WithFields(logrus.Fields{
+			"key":   id,
+			"error": err.Error(),
+		}).Error("failed to get the ticket status from state storage")
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	value, assignmentValue := pickTicket(values)
+	if value == nil {
+		return nil, status.Errorf(codes.NotFound, "Ticket id:%s not found", id)
+	}
+	ticket := &pb.Ticket{}
+	err = unmarshalTicket(value, ticket)
+	if err != nil {
+		redisLogger.WithFields(logrus.Fields{
+			"key":   id,
+			"error": err.Error(),
+		}).Error("failed to unmarshal the ticket proto")
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	expiredBefore := rb.clk.Now().Add(-rb.cfg.GetDuration("storage.ignoreListTTL"))
+	return &TicketStatus{
+		Indexed:  indexed,
+		Proposed: leaseErr == nil && leased > float64(expiredBefore.UnixNano()),
+		Assigned: assignmentValue != nil || ticket.GetAssignment() != nil,
+	}, nil
+}
+
+func (rb *redisBackend) expiredTicketStateTTL() time.Duration {
+	const (
+		name       = "storage.expiredTicketStateTTL"
+		defaultTTL = 10 * time.Minute
+	)
+
+	if !rb.cfg.IsSet(name) {
+		return defaultTTL
+	}
+	return rb.cfg.GetDuration(name)
+}
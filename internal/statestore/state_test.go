@@ -0,0 +1,120 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/clock"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestGetTicketState(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	service := New(cfg)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	requireState := func(id string, want pb.Ticket_State) {
+		t.Helper()
+		state, err := service.GetTicketState(ctx, id)
+		assert.Nil(err)
+		assert.Equal(want, state)
+	}
+
+	_, err := service.GetTicketState(ctx, "1")
+	assert.Equal(codes.NotFound, status.Code(err))
+
+	assert.Nil(service.CreateTicket(ctx, &pb.Ticket{Id: "1"}))
+	requireState("1", pb.Ticket_SEARCHING)
+
+	leaseTickets(t, service, []string{"1"})
+	requireState("1", pb.Ticket_PROPOSED)
+	assert.Nil(service.ReleaseTicketLease(ctx, "owner", []string{"1"}))
+	requireState("1", pb.Ticket_SEARCHING)
+
+	leaseTickets(t, service, []string{"1"})
+	_, _, err = service.UpdateAssignments(ctx, []string{"1"}, &pb.Assignment{Connection: "1.2.3.4:1234"})
+	assert.Nil(err)
+	requireState("1", pb.Ticket_ASSIGNED)
+
+	assert.Nil(service.DeleteTicket(ctx, "1"))
+	_, err = service.GetTicketState(ctx, "1")
+	assert.Equal(codes.NotFound, status.Code(err))
+
+	assert.Nil(service.CreateTicket(ctx, &pb.Ticket{Id: "2", Assignment: &pb.Assignment{Connection: "1.2.3.4:1234"}}))
+	requireState("2", pb.Ticket_ASSIGNED)
+
+	// Miniredis doesn't publish expired key events, expire ticket 3 by hand.
+	assert.Nil(service.CreateTicket(ctx, &pb.Ticket{Id: "3"}))
+	rb := newRedis(cfg, clock.Real()).(*redisBackend)
+	defer rb.Close()
+	redisConn, err := rb.connect(ctx)
+	assert.Nil(err)
+	defer redisConn.Close()
+	_, err = redisConn.Do("DEL", "3")
+	assert.Nil(err)
+	rb.cleanUpExpiredKey(ctx, "3", nil)
+	requireState("3", pb.Ticket_EXPIRED)
+
+	assert.Nil(service.CreateTicket(ctx, &pb.Ticket{Id: "3"}))
+	requireState("3", pb.Ticket_SEARCHING)
+
+	// The states of tickets stored before states were are derived from their assignment.
+	_, err = redisConn.Do("DEL", "2:state", "3:state")
+	assert.Nil(err)
+	requireState("2", pb.Ticket_ASSIGNED)
+	requireState("3", pb.Ticket_SEARCHING)
+}
+
+func TestGetTicketStatus(t *testing.T) {
+	assert := assert.New(t)
+	cfg, closer := createRedis(t)
+	defer closer()
+	service := New(cfg)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	_, err := service.GetTicketStatus(ctx, "1")
+	assert.Equal(codes.NotFound, status.Code(err))
+
+	ticket := &pb.Ticket{Id: "1"}
+	assert.Nil(service.CreateTicket(ctx, ticket))
+	requireStatus := func(want TicketStatus) {
+		t.Helper()
+		got, err := service.GetTicketStatus(ctx, "1")
+		assert.Nil(err)
+		assert.Equal(&want, got)
+	}
+	requireStatus(TicketStatus{})
+
+	assert.Nil(service.IndexTicket(ctx, ticket))
+	requireStatus(TicketStatus{Indexed: true})
+
+	leaseTickets(t, service, []string{"1"})
+	requireStatus(TicketStatus{Indexed: true, Proposed: true})
+
+	_, _, err = service.UpdateAssignments(ctx, []string{"1"}, &pb.Assignment{Connection: "1.2.3.4:1234"})
+	assert.Nil(err)
+	assert.Nil(service.DeindexTicket(ctx, "1"))
+	assert.Nil(service.DeleteTicketsFromIgnoreList(ctx, []string{"1"}))
+	requireStatus(TicketStatus{Assigned: true})
+}
@@ -30,13 +30,43 @@ const (
 	healthStateFirstProbe = int32(0)
 	healthStateHealthy    = int32(1)
 	healthStateUnhealthy  = int32(2)
+	healthStateDegraded   = int32(3)
 )
 
 var (
 	successKey       = tag.MustNewKey("success")
 	mReadinessProbes = Counter("health/readiness", "readiness probes", successKey)
+	mDegradedProbes  = Counter("health/degraded", "readiness probes which passed with a degraded dependency")
 )
 
+// degradedError is returned by probes whose dependency works, but too slowly.
+type degradedError struct {
+	err error
+}
+
+func (e *degradedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *degradedError) Unwrap() error {
+	return e.err
+}
+
+// Degraded marks the error of a probe as a degradation rather than an outage,
+// eg. a dependency which responds slower than its objective.  Degraded probes
+// don't fail readiness checks, so that a slow dependency doesn't get every
+// replica taken out of service, but are reported as "degraded" by the health
+// check endpoint.
+func Degraded(err error) error {
+	return &degradedError{err: err}
+}
+
+// IsDegraded returns whether err was marked with Degraded.
+func IsDegraded(err error) bool {
+	_, ok := err.(*degradedError)
+	return ok
+}
+
 type statefulProbe struct {
 	healthState *int32
 	probes      []func(context.Context) error
@@ -48,8 +78,15 @@ func (sp *statefulProbe) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	if len(req.URL.Query()) > 0 {
 		// Readiness probe are triggered if there's a query (ie "?" in the url).
 		// If so then scan all the probes.
+		var degraded error
 		for _, probe := range sp.probes {
 			err := probe(req.Context())
+			if IsDegraded(err) {
+				if degraded == nil {
+					degraded = err
+				}
+				continue
+			}
 			if err != nil {
 				old := atomic.SwapInt32(sp.healthState, healthStateUnhealthy)
 				if old == healthStateUnhealthy {
@@ -63,8 +100,18 @@ func (sp *statefulProbe) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 			}
 		}
 		RecordUnitMeasurement(req.Context(), mReadinessProbes, tag.Insert(successKey, "true"))
+		if degraded != nil {
+			RecordUnitMeasurement(req.Context(), mDegradedProbes)
+			old := atomic.SwapInt32(sp.healthState, healthStateDegraded)
+			if old != healthStateDegraded {
+				logger.WithError(degraded).Warningf("%s health check is degraded.", HealthCheckEndpoint)
+			}
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, "degraded: %v", degraded)
+			return
+		}
 		old := atomic.SwapInt32(sp.healthState, healthStateHealthy)
-		if old == healthStateUnhealthy {
+		if old == healthStateUnhealthy || old == healthStateDegraded {
 			logger.Infof("%s is healthy again.", HealthCheckEndpoint)
 		} else if old == healthStateFirstProbe {
 			logger.Infof("%s is reporting healthy.", HealthCheckEndpoint)
@@ -33,6 +33,30 @@ func happyHealthCheck(context.Context) error {
 	return nil
 }
 
+func slowHealthCheck(context.Context) error {
+	return Degraded(fmt.Errorf("I'm slow"))
+}
+
+func TestDegradedHealthCheck(t *testing.T) {
+	assert := assert.New(t)
+	hc := NewHealthCheck([]func(context.Context) error{happyHealthCheck, slowHealthCheck})
+	sp := hc.(*statefulProbe)
+	hcFunc := func(w http.ResponseWriter, r *http.Request) {
+		hc.ServeHTTP(w, r)
+	}
+
+	assert.HTTPSuccess(hcFunc, http.MethodGet, "/", url.Values{"readiness": []string{"true"}}, "degraded: I'm slow")
+	assert.HTTPBodyContains(hcFunc, http.MethodGet, "/", url.Values{"readiness": []string{"true"}}, "degraded: I'm slow")
+	assert.Equal(healthStateDegraded, atomic.LoadInt32(sp.healthState))
+
+	// Outages still fail the readiness check.
+	hc = NewHealthCheck([]func(context.Context) error{slowHealthCheck, angryHealthCheck})
+	hcFunc = func(w http.ResponseWriter, r *http.Request) {
+		hc.ServeHTTP(w, r)
+	}
+	assert.HTTPError(hcFunc, http.MethodGet, "/", url.Values{"readiness": []string{"true"}}, "I'm angry")
+}
+
 func TestAlwaysReadyHealthCheck(t *testing.T) {
 	assertHealthCheck(t, NewAlwaysReadyHealthCheck(), "")
 }
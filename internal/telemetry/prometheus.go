@@ -16,7 +16,11 @@ package telemetry
 
 // Taken from https://opencensus.io/quickstart/go/metrics/#1
 import (
+	"context"
+	"fmt"
+	"net"
 	"net/http"
+	"time"
 
 	ocPrometheus "contrib.go.opencensus.io/exporter/prometheus"
 	"github.com/prometheus/client_golang/prometheus"
@@ -28,38 +32,89 @@ import (
 const (
 	// ConfigNameEnableMetrics indicates that telemetry is enabled.
 	ConfigNameEnableMetrics = "telemetry.prometheus.enable"
+
+	defaultPrometheusEndpoint = "/metrics"
+	prometheusShutdownTimeout = 5 * time.Second
 )
 
-func bindPrometheus(mux *http.ServeMux, cfg config.View) {
+// bindPrometheus serves the Prometheus scrape endpoint. By default it is
+// mounted on the service's HTTP mux; if telemetry.prometheus.port is set it
+// gets a dedicated listener instead, so scraping can be exposed separately
+// from the API. The returned func stops that listener.
+func bindPrometheus(mux *http.ServeMux, cfg config.View) func() {
 	if !cfg.GetBool("telemetry.prometheus.enable") {
 		logger.Info("Prometheus Metrics: Disabled")
-		return
+		return func() {}
 	}
 
 	endpoint := cfg.GetString("telemetry.prometheus.endpoint")
+	if endpoint == "" {
+		endpoint = defaultPrometheusEndpoint
+	}
+	namespace := cfg.GetString("telemetry.prometheus.namespace")
+	port := cfg.GetInt("telemetry.prometheus.port")
+
+	promExporter := newPrometheusExporter(namespace)
+
+	// Register the Prometheus exporters as a stats exporter.
+	view.RegisterExporter(promExporter)
+
+	fields := logrus.Fields{
+		"endpoint":  endpoint,
+		"namespace": namespace,
+	}
+	if port <= 0 {
+		mux.Handle(endpoint, promExporter)
+		logger.WithFields(fields).Info("Prometheus Metrics: ENABLED")
+		return func() {}
+	}
+
+	fields["port"] = port
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		fields["error"] = err
+		logger.WithFields(fields).Fatal("Failed to listen for Prometheus scrapes")
+	}
+	stop := servePrometheus(lis, endpoint, promExporter)
+	logger.WithFields(fields).Info("Prometheus Metrics: ENABLED")
+	return stop
+}
+
+func newPrometheusExporter(namespace string) *ocPrometheus.Exporter {
 	registry := prometheus.NewRegistry()
 	// Register standard prometheus instrumentation.
 	registry.MustRegister(prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}))
 	registry.MustRegister(prometheus.NewGoCollector())
 	promExporter, err := ocPrometheus.NewExporter(
 		ocPrometheus.Options{
-			Namespace: "",
+			Namespace: namespace,
 			Registry:  registry,
 		})
 	if err != nil {
 		logger.WithFields(logrus.Fields{
-			"error":    err,
-			"endpoint": endpoint,
+			"error":     err,
+			"namespace": namespace,
 		}).Fatal(
 			"Failed to initialize OpenCensus exporter to Prometheus")
 	}
+	return promExporter
+}
 
-	// Register the Prometheus exporters as a stats exporter.
-	view.RegisterExporter(promExporter)
-
-	mux.Handle(endpoint, promExporter)
-
-	logger.WithFields(logrus.Fields{
-		"endpoint": endpoint,
-	}).Info("Prometheus Metrics: ENABLED")
+// servePrometheus serves handler at endpoint on lis until the returned func is called.
+func servePrometheus(lis net.Listener, endpoint string, handler http.Handler) func() {
+	mux := http.NewServeMux()
+	mux.Handle(endpoint, handler)
+	srv := &http.Server{Handler: mux}
+	go func() {
+		if err := srv.Serve(lis); err != nil && err != http.ErrServerClosed {
+			logger.WithError(err).Error("Prometheus metrics server stopped unexpectedly")
+		}
+	}()
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), prometheusShutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			logger.WithError(err).Warning("Failed to shut down Prometheus metrics server")
+		}
+	}
 }
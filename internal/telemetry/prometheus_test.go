@@ -0,0 +1,85 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+)
+
+func TestPrometheusNamespace(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	m := stats.Int64("prometheus_test/count", "count used by the prometheus exporter test", stats.UnitDimensionless)
+	v := &view.View{Name: m.Name(), Measure: m, Aggregation: view.Count()}
+	require.Nil(view.Register(v))
+	defer view.Unregister(v)
+	stats.Record(context.Background(), m.M(1))
+
+	exporter := newPrometheusExporter("open_match")
+	scrape := func() string {
+		rec := httptest.NewRecorder()
+		exporter.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		assert.Equal(http.StatusOK, rec.Code)
+		return rec.Body.String()
+	}
+	// Recorded stats reach the view worker asynchronously.
+	assert.Eventually(func() bool {
+		return strings.Contains(scrape(), "open_match_prometheus_test_count")
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Contains(scrape(), "go_goroutines")
+}
+
+func TestServePrometheus(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	lis, err := net.Listen("tcp", ":0")
+	require.Nil(err)
+	stop := servePrometheus(lis, "/custom-metrics", newPrometheusExporter(""))
+	defer stop()
+
+	client := &http.Client{Timeout: time.Second}
+	base := "http://" + lis.Addr().String()
+
+	resp, err := client.Get(base + "/custom-metrics")
+	require.Nil(err)
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.Nil(err)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Contains(string(body), "go_goroutines")
+
+	resp, err = client.Get(base + "/metrics")
+	require.Nil(err)
+	resp.Body.Close()
+	assert.Equal(http.StatusNotFound, resp.StatusCode)
+
+	stop()
+	_, err = client.Get(base + "/custom-metrics")
+	assert.NotNil(err)
+}
@@ -45,7 +45,7 @@ func Setup(servicePrefix string, mux *http.ServeMux, cfg config.View) func() {
 	}
 
 	bindJaeger(servicePrefix, cfg)
-	bindPrometheus(mux, cfg)
+	mc.AddCloseFunc(bindPrometheus(mux, cfg))
 	mc.AddCloseFunc(bindStackDriverMetrics(cfg))
 	mc.AddCloseWithErrorFunc(bindOpenCensusAgent(cfg))
 	bindZpages(mux, cfg)
@@ -135,11 +135,15 @@ func createMinimatchForTest(t *testing.T, evalTc *rpcTesting.TestContext) *rpcTe
 	cfg.Set("api.synchronizer.hostname", tc.GetHostname())
 	cfg.Set("api.synchronizer.grpcport", tc.GetGRPCPort())
 	cfg.Set("api.synchronizer.httpport", tc.GetHTTPPort())
+	cfg.Set("api.query.hostname", tc.GetHostname())
+	cfg.Set("api.query.grpcport", tc.GetGRPCPort())
+	cfg.Set("api.query.httpport", tc.GetHTTPPort())
 	cfg.Set("synchronizer.registrationIntervalMs", "200ms")
 	cfg.Set("synchronizer.proposalCollectionIntervalMs", "200ms")
 	cfg.Set("api.evaluator.hostname", evalTc.GetHostname())
 	cfg.Set("api.evaluator.grpcport", evalTc.GetGRPCPort())
 	cfg.Set("api.evaluator.httpport", evalTc.GetHTTPPort())
+	cfg.Set("synchronizer.evaluatorChunkSize", 10)
 	cfg.Set("synchronizer.enabled", true)
 	cfg.Set(rpc.ConfigNameEnableRPCLogging, *testOnlyEnableRPCLoggingFlag)
 	cfg.Set("logging.level", *testOnlyLoggingLevel)
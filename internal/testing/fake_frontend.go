@@ -36,6 +36,11 @@ func (s *FakeFrontend) CreateTicket(ctx context.Context, req *pb.CreateTicketReq
 	return &pb.CreateTicketResponse{}, nil
 }
 
+// CreateTickets creates multiple Tickets at once.
+func (s *FakeFrontend) CreateTickets(ctx context.Context, req *pb.CreateTicketsRequest) (*pb.CreateTicketsResponse, error) {
+	return &pb.CreateTicketsResponse{}, nil
+}
+
 // DeleteTicket removes the Ticket from state storage and from corresponding
 // configured indices. Deleting the ticket stops the ticket from being
 // considered for future matchmaking requests.
@@ -43,13 +48,48 @@ func (s *FakeFrontend) DeleteTicket(ctx context.Context, req *pb.DeleteTicketReq
 	return nil, status.Error(codes.Unimplemented, "not implemented")
 }
 
+// UpdateTicket replaces the search fields, extensions, player ids and avoid ids
+// of the ticket associated with the specified Ticket id.
+func (s *FakeFrontend) UpdateTicket(ctx context.Context, req *pb.UpdateTicketRequest) (*pb.UpdateTicketResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "not implemented")
+}
+
 // GetTicket fetches the ticket associated with the specified Ticket id.
 func (s *FakeFrontend) GetTicket(ctx context.Context, req *pb.GetTicketRequest) (*pb.Ticket, error) {
 	return nil, status.Error(codes.Unimplemented, "not implemented")
 }
 
+// DeleteTickets removes the Tickets from state storage and from corresponding
+// configured indices, as with DeleteTicket.
+func (s *FakeFrontend) DeleteTickets(ctx context.Context, req *pb.DeleteTicketsRequest) (*pb.DeleteTicketsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "not implemented")
+}
+
+// Heartbeat keeps the Tickets in matchmaking while heartbeats are required.
+func (s *FakeFrontend) Heartbeat(ctx context.Context, req *pb.HeartbeatRequest) (*pb.HeartbeatResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "not implemented")
+}
+
+// GetTicketState fetches the lifecycle state of the ticket associated with the
+// specified Ticket id.
+func (s *FakeFrontend) GetTicketState(ctx context.Context, req *pb.GetTicketStateRequest) (*pb.GetTicketStateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "not implemented")
+}
+
+// GetTicketStatus fetches queue diagnostics of the ticket associated with the
+// specified Ticket id.
+func (s *FakeFrontend) GetTicketStatus(ctx context.Context, req *pb.GetTicketStatusRequest) (*pb.GetTicketStatusResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "not implemented")
+}
+
 // GetAssignments streams matchmaking results from Open Match for the
 // provided Ticket id.
 func (s *FakeFrontend) GetAssignments(req *pb.GetAssignmentsRequest, stream pb.FrontendService_GetAssignmentsServer) error {
 	return status.Error(codes.Unimplemented, "not implemented")
 }
+
+// WaitForAssignment waits for the Assignment of the provided Ticket id to
+// change.
+func (s *FakeFrontend) WaitForAssignment(ctx context.Context, req *pb.WaitForAssignmentRequest) (*pb.WaitForAssignmentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "not implemented")
+}
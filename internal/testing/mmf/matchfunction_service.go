@@ -24,6 +24,8 @@ import (
 	"google.golang.org/grpc"
 	"open-match.dev/open-match/internal/config"
 	"open-match.dev/open-match/internal/rpc"
+	"open-match.dev/open-match/internal/util"
+	"open-match.dev/open-match/pkg/matchfunction"
 	"open-match.dev/open-match/pkg/pb"
 )
 
@@ -78,6 +80,7 @@ func (s *matchFunctionService) Run(req *pb.RunRequest, stream pb.MatchFunction_R
 		Logger: logrus.WithFields(logrus.Fields{
 			"app":       "openmatch",
 			"component": "matchfunction.implementation",
+			"cycleId":   util.GetSynchronizerContextID(stream.Context()),
 		}),
 		ProfileName:       req.GetProfile().GetName(),
 		Extensions:        req.GetProfile().GetExtensions(),
@@ -112,13 +115,25 @@ func newMatchFunctionService(cfg config.View, fs *FunctionSettings) (*matchFunct
 	return mmfService, nil
 }
 
-// getMatchManifest fetches all the data needed from the queryService API.
+// getMatchManifest fetches all the data needed from the queryService API,
+// unless the backend passed the tickets of every pool in the request.
 func (s *matchFunctionService) getMatchManifest(ctx context.Context, req *pb.RunRequest) (map[string][]*pb.Ticket, error) {
 	poolNameToTickets := make(map[string][]*pb.Ticket)
 	filterPools := req.GetProfile().GetPools()
 
 	for _, pool := range filterPools {
-		qtClient, err := s.queryServiceClient.QueryTickets(ctx, &pb.QueryTicketsRequest{Pool: pool}, grpc.WaitForReady(true))
+		passed, ok := req.GetPoolTickets()[pool.GetName()]
+		if !ok {
+			break
+		}
+		poolNameToTickets[pool.GetName()] = passed.GetTickets()
+	}
+	if len(poolNameToTickets) == len(filterPools) {
+		return poolNameToTickets, nil
+	}
+
+	for _, pool := range filterPools {
+		qtClient, err := s.queryServiceClient.QueryTickets(matchfunction.ForwardToken(ctx), &pb.QueryTicketsRequest{Pool: pool}, grpc.WaitForReady(true))
 		if err != nil {
 			logger.WithError(err).Error("Failed to get queryTicketClient from queryService.")
 			return nil, err
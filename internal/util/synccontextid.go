@@ -17,7 +17,9 @@ package util
 
 import (
 	"context"
+	"net/http"
 
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc/metadata"
 )
@@ -62,6 +64,21 @@ func GetSynchronizerContextID(ctx context.Context) string {
 	return ""
 }
 
+// SetSynchronizerContextIDHeader copies the synchronizer context id from the
+// outgoing context metadata onto an HTTP request.  The grpc-gateway forwards the
+// header to the gRPC handler as metadata, where GetSynchronizerContextID reads it.
+func SetSynchronizerContextIDHeader(ctx context.Context, req *http.Request) {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if !ok {
+		return
+	}
+
+	values := getSynchronizerContextIDFromMetadata(md)
+	if len(values) == 1 {
+		req.Header.Set(runtime.MetadataHeaderPrefix+metadataNameSynchronizerContextID, values[0])
+	}
+}
+
 func getSynchronizerContextIDFromMetadata(md metadata.MD) []string {
 	values := md.Get(metadataNameSynchronizerContextID)
 	if len(values) > 0 {
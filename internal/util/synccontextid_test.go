@@ -0,0 +1,52 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestAppendSynchronizerContextID(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, err := AppendSynchronizerContextID(context.Background(), "cycle")
+	assert.Nil(err)
+	ctx, err = AppendSynchronizerContextID(ctx, "cycle")
+	assert.Nil(err)
+	_, err = AppendSynchronizerContextID(ctx, "other")
+	assert.NotNil(err)
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	assert.Equal("cycle", GetSynchronizerContextID(metadata.NewIncomingContext(context.Background(), md)))
+}
+
+func TestSetSynchronizerContextIDHeader(t *testing.T) {
+	assert := assert.New(t)
+
+	req, err := http.NewRequest("POST", "http://localhost", nil)
+	assert.Nil(err)
+	SetSynchronizerContextIDHeader(context.Background(), req)
+	assert.Empty(req.Header)
+
+	ctx, err := AppendSynchronizerContextID(context.Background(), "cycle")
+	assert.Nil(err)
+	SetSynchronizerContextIDHeader(ctx, req)
+	assert.Equal("cycle", req.Header.Get("Grpc-Metadata-Synchronizer-Context-Id"))
+}
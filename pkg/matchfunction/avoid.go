@@ -0,0 +1,68 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matchfunction
+
+import (
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// Avoids returns true if either Ticket has the other Ticket, or one of its
+// players, on its avoid list.
+func Avoids(a, b *pb.Ticket) bool {
+	return avoidsOneWay(a, b) || avoidsOneWay(b, a)
+}
+
+func avoidsOneWay(from, to *pb.Ticket) bool {
+	for _, id := range from.GetAvoidIds() {
+		if id == to.GetId() {
+			return true
+		}
+		for _, player := range to.GetPlayerIds() {
+			if id == player {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Compatible returns true if no two of the given Tickets avoid each other.
+func Compatible(tickets []*pb.Ticket) bool {
+	for i := range tickets {
+		for j := i + 1; j < len(tickets); j++ {
+			if Avoids(tickets[i], tickets[j]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// FilterAvoided returns the candidates which may be added to a match already
+// containing selected without violating any avoid list. The order of
+// candidates is preserved.
+func FilterAvoided(candidates []*pb.Ticket, selected []*pb.Ticket) []*pb.Ticket {
+	var result []*pb.Ticket
+candidates:
+	for _, c := range candidates {
+		for _, s := range selected {
+			if Avoids(c, s) {
+				continue candidates
+			}
+		}
+		result = append(result, c)
+	}
+	return result
+}
@@ -0,0 +1,65 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matchfunction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestAvoids(t *testing.T) {
+	assert := assert.New(t)
+
+	alice := &pb.Ticket{Id: "a", PlayerIds: []string{"alice"}}
+	bob := &pb.Ticket{Id: "b", PlayerIds: []string{"bob"}, AvoidIds: []string{"alice"}}
+	carol := &pb.Ticket{Id: "c", PlayerIds: []string{"carol", "dave"}, AvoidIds: []string{"a"}}
+	erin := &pb.Ticket{Id: "e", AvoidIds: []string{"dave"}}
+
+	assert.True(Avoids(alice, bob), "player id on avoid list")
+	assert.True(Avoids(bob, alice), "avoid lists are symmetric")
+	assert.True(Avoids(carol, alice), "ticket id on avoid list")
+	assert.True(Avoids(erin, carol), "any player of a group ticket")
+	assert.False(Avoids(bob, carol))
+	assert.False(Avoids(alice, erin))
+	assert.False(Avoids(nil, alice))
+}
+
+func TestCompatible(t *testing.T) {
+	assert := assert.New(t)
+
+	a := &pb.Ticket{Id: "a"}
+	b := &pb.Ticket{Id: "b"}
+	c := &pb.Ticket{Id: "c", AvoidIds: []string{"a"}}
+
+	assert.True(Compatible(nil))
+	assert.True(Compatible([]*pb.Ticket{a, b}))
+	assert.False(Compatible([]*pb.Ticket{a, b, c}))
+	assert.True(Compatible([]*pb.Ticket{b, c}))
+}
+
+func TestFilterAvoided(t *testing.T) {
+	assert := assert.New(t)
+
+	a := &pb.Ticket{Id: "a", PlayerIds: []string{"alice"}}
+	b := &pb.Ticket{Id: "b", AvoidIds: []string{"alice"}}
+	c := &pb.Ticket{Id: "c"}
+	d := &pb.Ticket{Id: "d"}
+	selected := []*pb.Ticket{a, {Id: "x", AvoidIds: []string{"d"}}}
+
+	assert.Equal([]*pb.Ticket{c}, FilterAvoided([]*pb.Ticket{b, c, d}, selected))
+	assert.Equal([]*pb.Ticket{b, c, d}, FilterAvoided([]*pb.Ticket{b, c, d}, nil))
+}
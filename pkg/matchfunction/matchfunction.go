@@ -20,12 +20,37 @@ import (
 	"fmt"
 	"io"
 
+	"google.golang.org/grpc/metadata"
 	"open-match.dev/open-match/pkg/pb"
 )
 
+// TokenMetadataName is the gRPC metadata key of the token the backend passes to
+// a match function when mmf authentication is enabled. The match function must
+// present the token back to the query service.
+const TokenMetadataName = "openmatch-mmf-token"
+
+// ForwardToken returns a context for calling the query service which carries
+// the token the backend passed to the match function in the incoming ctx. The
+// context is returned unchanged if there is no token.
+func ForwardToken(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	values := md.Get(TokenMetadataName)
+	if len(values) != 1 {
+		return ctx
+	}
+	if out, ok := metadata.FromOutgoingContext(ctx); ok && len(out.Get(TokenMetadataName)) > 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, TokenMetadataName, values[0])
+}
+
 // QueryPool queries queryService and returns the tickets that belong to the specified pool.
+// Any token the backend passed to the match function in ctx is forwarded to the queryService.
 func QueryPool(ctx context.Context, mml pb.QueryServiceClient, pool *pb.Pool) ([]*pb.Ticket, error) {
-	query, err := mml.QueryTickets(ctx, &pb.QueryTicketsRequest{Pool: pool})
+	query, err := mml.QueryTickets(ForwardToken(ctx), &pb.QueryTicketsRequest{Pool: pool})
 	if err != nil {
 		return nil, fmt.Errorf("error calling queryService.QueryTickets: %w", err)
 	}
@@ -45,6 +70,29 @@ func QueryPool(ctx context.Context, mml pb.QueryServiceClient, pool *pb.Pool) ([
 	}
 }
 
+// QueryPoolIds queries queryService and returns the ids of the tickets that belong to the specified pool.
+// Any token the backend passed to the match function in ctx is forwarded to the queryService.
+func QueryPoolIds(ctx context.Context, mml pb.QueryServiceClient, pool *pb.Pool) ([]string, error) {
+	query, err := mml.QueryTicketIds(ForwardToken(ctx), &pb.QueryTicketIdsRequest{Pool: pool})
+	if err != nil {
+		return nil, fmt.Errorf("error calling queryService.QueryTicketIds: %w", err)
+	}
+
+	var ids []string
+	for {
+		resp, err := query.Recv()
+		if err == io.EOF {
+			return ids, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("error receiving ticket ids from queryService.QueryTicketIds: %w", err)
+		}
+
+		ids = append(ids, resp.Ids...)
+	}
+}
+
 // QueryPools queries queryService and returns the a map of pool names to the tickets belonging to those pools.
 func QueryPools(ctx context.Context, mml pb.QueryServiceClient, pools []*pb.Pool) (map[string][]*pb.Ticket, error) {
 	ctx, cancel := context.WithCancel(ctx)
@@ -85,3 +133,22 @@ func QueryPools(ctx context.Context, mml pb.QueryServiceClient, pools []*pb.Pool
 
 	return poolMap, nil
 }
+
+// PoolTickets returns a map of pool names to the tickets belonging to those pools.  The tickets the backend passed
+// in req are used if it queried the pools itself, see FunctionConfig.pass_pool_tickets, otherwise queryService is
+// queried as by QueryPools.
+func PoolTickets(ctx context.Context, mml pb.QueryServiceClient, req *pb.RunRequest) (map[string][]*pb.Ticket, error) {
+	pools := req.GetProfile().GetPools()
+	passed := req.GetPoolTickets()
+	for _, pool := range pools {
+		if _, ok := passed[pool.GetName()]; !ok {
+			return QueryPools(ctx, mml, pools)
+		}
+	}
+
+	poolMap := make(map[string][]*pb.Ticket, len(pools))
+	for _, pool := range pools {
+		poolMap[pool.GetName()] = passed[pool.GetName()].GetTickets()
+	}
+	return poolMap, nil
+}
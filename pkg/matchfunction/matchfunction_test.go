@@ -0,0 +1,59 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matchfunction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestForwardToken(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := context.Background()
+	assert.Equal(ctx, ForwardToken(ctx))
+
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(TokenMetadataName, "token"))
+	ctx = ForwardToken(ctx)
+	md, ok := metadata.FromOutgoingContext(ctx)
+	assert.True(ok)
+	assert.Equal([]string{"token"}, md.Get(TokenMetadataName))
+
+	// Forwarding twice doesn't duplicate the token.
+	md, _ = metadata.FromOutgoingContext(ForwardToken(ctx))
+	assert.Equal([]string{"token"}, md.Get(TokenMetadataName))
+}
+
+func TestPoolTicketsPassed(t *testing.T) {
+	assert := assert.New(t)
+
+	ticket := &pb.Ticket{Id: "1"}
+	req := &pb.RunRequest{
+		Profile: &pb.MatchProfile{Pools: []*pb.Pool{{Name: "a"}, {Name: "b"}}},
+		PoolTickets: map[string]*pb.PoolTickets{
+			"a": {Tickets: []*pb.Ticket{ticket}},
+			"b": {},
+		},
+	}
+
+	// The query service client isn't needed when the backend passed the tickets.
+	poolMap, err := PoolTickets(context.Background(), nil, req)
+	assert.Nil(err)
+	assert.Equal(map[string][]*pb.Ticket{"a": {ticket}, "b": nil}, poolMap)
+}
@@ -0,0 +1,1396 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: api/admin.proto
+
+package pb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	duration "github.com/golang/protobuf/ptypes/duration"
+	_ "github.com/grpc-ecosystem/grpc-gateway/protoc-gen-swagger/options"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// StorageUsage describes how much of the state storage Open Match is using.
+type StorageUsage struct {
+	// Number of Ticket keys in state storage, including Tickets which are no
+	// longer indexed for matchmaking.
+	TicketCount int64 `protobuf:"varint,1,opt,name=ticket_count,json=ticketCount,proto3" json:"ticket_count,omitempty"`
+	// Number of Tickets indexed for matchmaking.
+	IndexedTicketCount int64 `protobuf:"varint,2,opt,name=indexed_ticket_count,json=indexedTicketCount,proto3" json:"indexed_ticket_count,omitempty"`
+	// Number of Tickets in the ignore list, including expired entries which
+	// have not been cleaned up yet.
+	IgnoreListSize int64 `protobuf:"varint,3,opt,name=ignore_list_size,json=ignoreListSize,proto3" json:"ignore_list_size,omitempty"`
+	// Approximate number of bytes used by Tickets and indexes, extrapolated
+	// from the memory usage of a sample of Tickets.
+	ApproximateBytes int64 `protobuf:"varint,4,opt,name=approximate_bytes,json=approximateBytes,proto3" json:"approximate_bytes,omitempty"`
+	// Number of Tickets sampled to compute approximate_bytes.
+	SampledTicketCount   int64    `protobuf:"varint,5,opt,name=sampled_ticket_count,json=sampledTicketCount,proto3" json:"sampled_ticket_count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StorageUsage) Reset()         { *m = StorageUsage{} }
+func (m *StorageUsage) String() string { return proto.CompactTextString(m) }
+func (*StorageUsage) ProtoMessage()    {}
+func (*StorageUsage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_109d096f4b62305b, []int{0}
+}
+
+func (m *StorageUsage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StorageUsage.Unmarshal(m, b)
+}
+func (m *StorageUsage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StorageUsage.Marshal(b, m, deterministic)
+}
+func (m *StorageUsage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StorageUsage.Merge(m, src)
+}
+func (m *StorageUsage) XXX_Size() int {
+	return xxx_messageInfo_StorageUsage.Size(m)
+}
+func (m *StorageUsage) XXX_DiscardUnknown() {
+	xxx_messageInfo_StorageUsage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StorageUsage proto.InternalMessageInfo
+
+func (m *StorageUsage) GetTicketCount() int64 {
+	if m != nil {
+		return m.TicketCount
+	}
+	return 0
+}
+
+func (m *StorageUsage) GetIndexedTicketCount() int64 {
+	if m != nil {
+		return m.IndexedTicketCount
+	}
+	return 0
+}
+
+func (m *StorageUsage) GetIgnoreListSize() int64 {
+	if m != nil {
+		return m.IgnoreListSize
+	}
+	return 0
+}
+
+func (m *StorageUsage) GetApproximateBytes() int64 {
+	if m != nil {
+		return m.ApproximateBytes
+	}
+	return 0
+}
+
+func (m *StorageUsage) GetSampledTicketCount() int64 {
+	if m != nil {
+		return m.SampledTicketCount
+	}
+	return 0
+}
+
+type GetStorageUsageRequest struct {
+	// Number of Tickets to sample when approximating memory usage. Larger
+	// samples are more accurate but put more load on state storage.
+	// Optional, defaults to 100.
+	SampleSize           int32    `protobuf:"varint,1,opt,name=sample_size,json=sampleSize,proto3" json:"sample_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStorageUsageRequest) Reset()         { *m = GetStorageUsageRequest{} }
+func (m *GetStorageUsageRequest) String() string { return proto.CompactTextString(m) }
+func (*GetStorageUsageRequest) ProtoMessage()    {}
+func (*GetStorageUsageRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_109d096f4b62305b, []int{1}
+}
+
+func (m *GetStorageUsageRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStorageUsageRequest.Unmarshal(m, b)
+}
+func (m *GetStorageUsageRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStorageUsageRequest.Marshal(b, m, deterministic)
+}
+func (m *GetStorageUsageRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStorageUsageRequest.Merge(m, src)
+}
+func (m *GetStorageUsageRequest) XXX_Size() int {
+	return xxx_messageInfo_GetStorageUsageRequest.Size(m)
+}
+func (m *GetStorageUsageRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStorageUsageRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStorageUsageRequest proto.InternalMessageInfo
+
+func (m *GetStorageUsageRequest) GetSampleSize() int32 {
+	if m != nil {
+		return m.SampleSize
+	}
+	return 0
+}
+
+type GetStorageUsageResponse struct {
+	// Current usage of the state storage.
+	Usage                *StorageUsage `protobuf:"bytes,1,opt,name=usage,proto3" json:"usage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *GetStorageUsageResponse) Reset()         { *m = GetStorageUsageResponse{} }
+func (m *GetStorageUsageResponse) String() string { return proto.CompactTextString(m) }
+func (*GetStorageUsageResponse) ProtoMessage()    {}
+func (*GetStorageUsageResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_109d096f4b62305b, []int{2}
+}
+
+func (m *GetStorageUsageResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStorageUsageResponse.Unmarshal(m, b)
+}
+func (m *GetStorageUsageResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStorageUsageResponse.Marshal(b, m, deterministic)
+}
+func (m *GetStorageUsageResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStorageUsageResponse.Merge(m, src)
+}
+func (m *GetStorageUsageResponse) XXX_Size() int {
+	return xxx_messageInfo_GetStorageUsageResponse.Size(m)
+}
+func (m *GetStorageUsageResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStorageUsageResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStorageUsageResponse proto.InternalMessageInfo
+
+func (m *GetStorageUsageResponse) GetUsage() *StorageUsage {
+	if m != nil {
+		return m.Usage
+	}
+	return nil
+}
+
+// SearchFieldRename renames a key in the SearchFields of stored Tickets.
+type SearchFieldRename struct {
+	// Current name of the double arg, string arg or tag.
+	From string `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	// New name of the double arg, string arg or tag. If empty, the field is
+	// removed from Tickets. If a Ticket already has a field with this name, its
+	// value is kept and the field being renamed is dropped.
+	To                   string   `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SearchFieldRename) Reset()         { *m = SearchFieldRename{} }
+func (m *SearchFieldRename) String() string { return proto.CompactTextString(m) }
+func (*SearchFieldRename) ProtoMessage()    {}
+func (*SearchFieldRename) Descriptor() ([]byte, []int) {
+	return fileDescriptor_109d096f4b62305b, []int{3}
+}
+
+func (m *SearchFieldRename) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchFieldRename.Unmarshal(m, b)
+}
+func (m *SearchFieldRename) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchFieldRename.Marshal(b, m, deterministic)
+}
+func (m *SearchFieldRename) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchFieldRename.Merge(m, src)
+}
+func (m *SearchFieldRename) XXX_Size() int {
+	return xxx_messageInfo_SearchFieldRename.Size(m)
+}
+func (m *SearchFieldRename) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchFieldRename.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchFieldRename proto.InternalMessageInfo
+
+func (m *SearchFieldRename) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *SearchFieldRename) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+type MigrateSearchFieldsRequest struct {
+	// Renames to apply, in order, to every Ticket in state storage.
+	Renames []*SearchFieldRename `protobuf:"bytes,1,rep,name=renames,proto3" json:"renames,omitempty"`
+	// If true, counts the Tickets which would be migrated without changing them.
+	DryRun               bool     `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MigrateSearchFieldsRequest) Reset()         { *m = MigrateSearchFieldsRequest{} }
+func (m *MigrateSearchFieldsRequest) String() string { return proto.CompactTextString(m) }
+func (*MigrateSearchFieldsRequest) ProtoMessage()    {}
+func (*MigrateSearchFieldsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_109d096f4b62305b, []int{4}
+}
+
+func (m *MigrateSearchFieldsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MigrateSearchFieldsRequest.Unmarshal(m, b)
+}
+func (m *MigrateSearchFieldsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MigrateSearchFieldsRequest.Marshal(b, m, deterministic)
+}
+func (m *MigrateSearchFieldsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MigrateSearchFieldsRequest.Merge(m, src)
+}
+func (m *MigrateSearchFieldsRequest) XXX_Size() int {
+	return xxx_messageInfo_MigrateSearchFieldsRequest.Size(m)
+}
+func (m *MigrateSearchFieldsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MigrateSearchFieldsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MigrateSearchFieldsRequest proto.InternalMessageInfo
+
+func (m *MigrateSearchFieldsRequest) GetRenames() []*SearchFieldRename {
+	if m != nil {
+		return m.Renames
+	}
+	return nil
+}
+
+func (m *MigrateSearchFieldsRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+type MigrateSearchFieldsResponse struct {
+	// Number of Tickets examined.
+	ScannedTicketCount int64 `protobuf:"varint,1,opt,name=scanned_ticket_count,json=scannedTicketCount,proto3" json:"scanned_ticket_count,omitempty"`
+	// Number of Tickets which were, or for a dry run would have been, changed.
+	MigratedTicketCount  int64    `protobuf:"varint,2,opt,name=migrated_ticket_count,json=migratedTicketCount,proto3" json:"migrated_ticket_count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MigrateSearchFieldsResponse) Reset()         { *m = MigrateSearchFieldsResponse{} }
+func (m *MigrateSearchFieldsResponse) String() string { return proto.CompactTextString(m) }
+func (*MigrateSearchFieldsResponse) ProtoMessage()    {}
+func (*MigrateSearchFieldsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_109d096f4b62305b, []int{5}
+}
+
+func (m *MigrateSearchFieldsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MigrateSearchFieldsResponse.Unmarshal(m, b)
+}
+func (m *MigrateSearchFieldsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MigrateSearchFieldsResponse.Marshal(b, m, deterministic)
+}
+func (m *MigrateSearchFieldsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MigrateSearchFieldsResponse.Merge(m, src)
+}
+func (m *MigrateSearchFieldsResponse) XXX_Size() int {
+	return xxx_messageInfo_MigrateSearchFieldsResponse.Size(m)
+}
+func (m *MigrateSearchFieldsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MigrateSearchFieldsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MigrateSearchFieldsResponse proto.InternalMessageInfo
+
+func (m *MigrateSearchFieldsResponse) GetScannedTicketCount() int64 {
+	if m != nil {
+		return m.ScannedTicketCount
+	}
+	return 0
+}
+
+func (m *MigrateSearchFieldsResponse) GetMigratedTicketCount() int64 {
+	if m != nil {
+		return m.MigratedTicketCount
+	}
+	return 0
+}
+
+// TicketSnapshot is the state of a Ticket exported from state storage.
+type TicketSnapshot struct {
+	// The Ticket, including its Assignment.
+	Ticket *Ticket `protobuf:"bytes,1,opt,name=ticket,proto3" json:"ticket,omitempty"`
+	// Whether the Ticket is indexed for matchmaking.
+	Indexed bool `protobuf:"varint,2,opt,name=indexed,proto3" json:"indexed,omitempty"`
+	// How long the Ticket had left before expiring when exported, unset if it
+	// doesn't expire.
+	Expiration           *duration.Duration `protobuf:"bytes,3,opt,name=expiration,proto3" json:"expiration,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *TicketSnapshot) Reset()         { *m = TicketSnapshot{} }
+func (m *TicketSnapshot) String() string { return proto.CompactTextString(m) }
+func (*TicketSnapshot) ProtoMessage()    {}
+func (*TicketSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_109d096f4b62305b, []int{6}
+}
+
+func (m *TicketSnapshot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TicketSnapshot.Unmarshal(m, b)
+}
+func (m *TicketSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TicketSnapshot.Marshal(b, m, deterministic)
+}
+func (m *TicketSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TicketSnapshot.Merge(m, src)
+}
+func (m *TicketSnapshot) XXX_Size() int {
+	return xxx_messageInfo_TicketSnapshot.Size(m)
+}
+func (m *TicketSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_TicketSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TicketSnapshot proto.InternalMessageInfo
+
+func (m *TicketSnapshot) GetTicket() *Ticket {
+	if m != nil {
+		return m.Ticket
+	}
+	return nil
+}
+
+func (m *TicketSnapshot) GetIndexed() bool {
+	if m != nil {
+		return m.Indexed
+	}
+	return false
+}
+
+func (m *TicketSnapshot) GetExpiration() *duration.Duration {
+	if m != nil {
+		return m.Expiration
+	}
+	return nil
+}
+
+type ExportTicketsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExportTicketsRequest) Reset()         { *m = ExportTicketsRequest{} }
+func (m *ExportTicketsRequest) String() string { return proto.CompactTextString(m) }
+func (*ExportTicketsRequest) ProtoMessage()    {}
+func (*ExportTicketsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_109d096f4b62305b, []int{7}
+}
+
+func (m *ExportTicketsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportTicketsRequest.Unmarshal(m, b)
+}
+func (m *ExportTicketsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportTicketsRequest.Marshal(b, m, deterministic)
+}
+func (m *ExportTicketsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportTicketsRequest.Merge(m, src)
+}
+func (m *ExportTicketsRequest) XXX_Size() int {
+	return xxx_messageInfo_ExportTicketsRequest.Size(m)
+}
+func (m *ExportTicketsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportTicketsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportTicketsRequest proto.InternalMessageInfo
+
+type ExportTicketsResponse struct {
+	// A page of the Tickets in state storage.
+	Snapshots            []*TicketSnapshot `protobuf:"bytes,1,rep,name=snapshots,proto3" json:"snapshots,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ExportTicketsResponse) Reset()         { *m = ExportTicketsResponse{} }
+func (m *ExportTicketsResponse) String() string { return proto.CompactTextString(m) }
+func (*ExportTicketsResponse) ProtoMessage()    {}
+func (*ExportTicketsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_109d096f4b62305b, []int{8}
+}
+
+func (m *ExportTicketsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportTicketsResponse.Unmarshal(m, b)
+}
+func (m *ExportTicketsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportTicketsResponse.Marshal(b, m, deterministic)
+}
+func (m *ExportTicketsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportTicketsResponse.Merge(m, src)
+}
+func (m *ExportTicketsResponse) XXX_Size() int {
+	return xxx_messageInfo_ExportTicketsResponse.Size(m)
+}
+func (m *ExportTicketsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportTicketsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportTicketsResponse proto.InternalMessageInfo
+
+func (m *ExportTicketsResponse) GetSnapshots() []*TicketSnapshot {
+	if m != nil {
+		return m.Snapshots
+	}
+	return nil
+}
+
+type ImportTicketsRequest struct {
+	// Tickets to save in state storage, as returned by ExportTickets.
+	Snapshots            []*TicketSnapshot `protobuf:"bytes,1,rep,name=snapshots,proto3" json:"snapshots,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ImportTicketsRequest) Reset()         { *m = ImportTicketsRequest{} }
+func (m *ImportTicketsRequest) String() string { return proto.CompactTextString(m) }
+func (*ImportTicketsRequest) ProtoMessage()    {}
+func (*ImportTicketsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_109d096f4b62305b, []int{9}
+}
+
+func (m *ImportTicketsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportTicketsRequest.Unmarshal(m, b)
+}
+func (m *ImportTicketsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportTicketsRequest.Marshal(b, m, deterministic)
+}
+func (m *ImportTicketsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportTicketsRequest.Merge(m, src)
+}
+func (m *ImportTicketsRequest) XXX_Size() int {
+	return xxx_messageInfo_ImportTicketsRequest.Size(m)
+}
+func (m *ImportTicketsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportTicketsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportTicketsRequest proto.InternalMessageInfo
+
+func (m *ImportTicketsRequest) GetSnapshots() []*TicketSnapshot {
+	if m != nil {
+		return m.Snapshots
+	}
+	return nil
+}
+
+type ImportTicketsResponse struct {
+	// Number of Tickets saved.
+	ImportedTicketCount  int64    `protobuf:"varint,1,opt,name=imported_ticket_count,json=importedTicketCount,proto3" json:"imported_ticket_count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImportTicketsResponse) Reset()         { *m = ImportTicketsResponse{} }
+func (m *ImportTicketsResponse) String() string { return proto.CompactTextString(m) }
+func (*ImportTicketsResponse) ProtoMessage()    {}
+func (*ImportTicketsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_109d096f4b62305b, []int{10}
+}
+
+func (m *ImportTicketsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportTicketsResponse.Unmarshal(m, b)
+}
+func (m *ImportTicketsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportTicketsResponse.Marshal(b, m, deterministic)
+}
+func (m *ImportTicketsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportTicketsResponse.Merge(m, src)
+}
+func (m *ImportTicketsResponse) XXX_Size() int {
+	return xxx_messageInfo_ImportTicketsResponse.Size(m)
+}
+func (m *ImportTicketsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportTicketsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportTicketsResponse proto.InternalMessageInfo
+
+func (m *ImportTicketsResponse) GetImportedTicketCount() int64 {
+	if m != nil {
+		return m.ImportedTicketCount
+	}
+	return 0
+}
+
+// FeatureGateOverride turns a FeatureGate on or off at runtime, overriding the
+// configuration.
+type FeatureGateOverride struct {
+	// Name of the FeatureGate, which is also its configuration key.
+	Gate string `protobuf:"bytes,1,opt,name=gate,proto3" json:"gate,omitempty"`
+	// Hostname of the replica the override applies to, ie. its pod name. If
+	// empty, the override applies to all replicas which don't have their own.
+	Instance             string   `protobuf:"bytes,2,opt,name=instance,proto3" json:"instance,omitempty"`
+	Enabled              bool     `protobuf:"varint,3,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FeatureGateOverride) Reset()         { *m = FeatureGateOverride{} }
+func (m *FeatureGateOverride) String() string { return proto.CompactTextString(m) }
+func (*FeatureGateOverride) ProtoMessage()    {}
+func (*FeatureGateOverride) Descriptor() ([]byte, []int) {
+	return fileDescriptor_109d096f4b62305b, []int{11}
+}
+
+func (m *FeatureGateOverride) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FeatureGateOverride.Unmarshal(m, b)
+}
+func (m *FeatureGateOverride) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FeatureGateOverride.Marshal(b, m, deterministic)
+}
+func (m *FeatureGateOverride) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FeatureGateOverride.Merge(m, src)
+}
+func (m *FeatureGateOverride) XXX_Size() int {
+	return xxx_messageInfo_FeatureGateOverride.Size(m)
+}
+func (m *FeatureGateOverride) XXX_DiscardUnknown() {
+	xxx_messageInfo_FeatureGateOverride.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FeatureGateOverride proto.InternalMessageInfo
+
+func (m *FeatureGateOverride) GetGate() string {
+	if m != nil {
+		return m.Gate
+	}
+	return ""
+}
+
+func (m *FeatureGateOverride) GetInstance() string {
+	if m != nil {
+		return m.Instance
+	}
+	return ""
+}
+
+func (m *FeatureGateOverride) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+// FeatureGate is a boolean setting which can safely be toggled at runtime,
+// because components read it whenever it's used.
+type FeatureGate struct {
+	// Name of the FeatureGate, which is also its configuration key.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// What the FeatureGate controls.
+	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// Value of the FeatureGate in the configuration, used by replicas without
+	// an override.
+	Configured bool `protobuf:"varint,3,opt,name=configured,proto3" json:"configured,omitempty"`
+	// Overrides of the FeatureGate persisted in state storage.
+	Overrides            []*FeatureGateOverride `protobuf:"bytes,4,rep,name=overrides,proto3" json:"overrides,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *FeatureGate) Reset()         { *m = FeatureGate{} }
+func (m *FeatureGate) String() string { return proto.CompactTextString(m) }
+func (*FeatureGate) ProtoMessage()    {}
+func (*FeatureGate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_109d096f4b62305b, []int{12}
+}
+
+func (m *FeatureGate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FeatureGate.Unmarshal(m, b)
+}
+func (m *FeatureGate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FeatureGate.Marshal(b, m, deterministic)
+}
+func (m *FeatureGate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FeatureGate.Merge(m, src)
+}
+func (m *FeatureGate) XXX_Size() int {
+	return xxx_messageInfo_FeatureGate.Size(m)
+}
+func (m *FeatureGate) XXX_DiscardUnknown() {
+	xxx_messageInfo_FeatureGate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FeatureGate proto.InternalMessageInfo
+
+func (m *FeatureGate) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *FeatureGate) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *FeatureGate) GetConfigured() bool {
+	if m != nil {
+		return m.Configured
+	}
+	return false
+}
+
+func (m *FeatureGate) GetOverrides() []*FeatureGateOverride {
+	if m != nil {
+		return m.Overrides
+	}
+	return nil
+}
+
+type GetConfigRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetConfigRequest) Reset()         { *m = GetConfigRequest{} }
+func (m *GetConfigRequest) String() string { return proto.CompactTextString(m) }
+func (*GetConfigRequest) ProtoMessage()    {}
+func (*GetConfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_109d096f4b62305b, []int{13}
+}
+
+func (m *GetConfigRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetConfigRequest.Unmarshal(m, b)
+}
+func (m *GetConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetConfigRequest.Marshal(b, m, deterministic)
+}
+func (m *GetConfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetConfigRequest.Merge(m, src)
+}
+func (m *GetConfigRequest) XXX_Size() int {
+	return xxx_messageInfo_GetConfigRequest.Size(m)
+}
+func (m *GetConfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetConfigRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetConfigRequest proto.InternalMessageInfo
+
+type GetConfigResponse struct {
+	// Effective configuration of the replica serving the request, keyed by
+	// setting, excluding FeatureGate overrides.
+	Settings map[string]string `protobuf:"bytes,1,rep,name=settings,proto3" json:"settings,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// FeatureGates which can be toggled with SetFeatureGate.
+	FeatureGates         []*FeatureGate `protobuf:"bytes,2,rep,name=feature_gates,json=featureGates,proto3" json:"feature_gates,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *GetConfigResponse) Reset()         { *m = GetConfigResponse{} }
+func (m *GetConfigResponse) String() string { return proto.CompactTextString(m) }
+func (*GetConfigResponse) ProtoMessage()    {}
+func (*GetConfigResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_109d096f4b62305b, []int{14}
+}
+
+func (m *GetConfigResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetConfigResponse.Unmarshal(m, b)
+}
+func (m *GetConfigResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetConfigResponse.Marshal(b, m, deterministic)
+}
+func (m *GetConfigResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetConfigResponse.Merge(m, src)
+}
+func (m *GetConfigResponse) XXX_Size() int {
+	return xxx_messageInfo_GetConfigResponse.Size(m)
+}
+func (m *GetConfigResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetConfigResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetConfigResponse proto.InternalMessageInfo
+
+func (m *GetConfigResponse) GetSettings() map[string]string {
+	if m != nil {
+		return m.Settings
+	}
+	return nil
+}
+
+func (m *GetConfigResponse) GetFeatureGates() []*FeatureGate {
+	if m != nil {
+		return m.FeatureGates
+	}
+	return nil
+}
+
+type SetFeatureGateRequest struct {
+	// Override to persist, replacing any override of the same gate and instance.
+	Override             *FeatureGateOverride `protobuf:"bytes,1,opt,name=override,proto3" json:"override,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *SetFeatureGateRequest) Reset()         { *m = SetFeatureGateRequest{} }
+func (m *SetFeatureGateRequest) String() string { return proto.CompactTextString(m) }
+func (*SetFeatureGateRequest) ProtoMessage()    {}
+func (*SetFeatureGateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_109d096f4b62305b, []int{15}
+}
+
+func (m *SetFeatureGateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetFeatureGateRequest.Unmarshal(m, b)
+}
+func (m *SetFeatureGateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetFeatureGateRequest.Marshal(b, m, deterministic)
+}
+func (m *SetFeatureGateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetFeatureGateRequest.Merge(m, src)
+}
+func (m *SetFeatureGateRequest) XXX_Size() int {
+	return xxx_messageInfo_SetFeatureGateRequest.Size(m)
+}
+func (m *SetFeatureGateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetFeatureGateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetFeatureGateRequest proto.InternalMessageInfo
+
+func (m *SetFeatureGateRequest) GetOverride() *FeatureGateOverride {
+	if m != nil {
+		return m.Override
+	}
+	return nil
+}
+
+type SetFeatureGateResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetFeatureGateResponse) Reset()         { *m = SetFeatureGateResponse{} }
+func (m *SetFeatureGateResponse) String() string { return proto.CompactTextString(m) }
+func (*SetFeatureGateResponse) ProtoMessage()    {}
+func (*SetFeatureGateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_109d096f4b62305b, []int{16}
+}
+
+func (m *SetFeatureGateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetFeatureGateResponse.Unmarshal(m, b)
+}
+func (m *SetFeatureGateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetFeatureGateResponse.Marshal(b, m, deterministic)
+}
+func (m *SetFeatureGateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetFeatureGateResponse.Merge(m, src)
+}
+func (m *SetFeatureGateResponse) XXX_Size() int {
+	return xxx_messageInfo_SetFeatureGateResponse.Size(m)
+}
+func (m *SetFeatureGateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetFeatureGateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetFeatureGateResponse proto.InternalMessageInfo
+
+type ClearFeatureGateRequest struct {
+	// Name of the FeatureGate.
+	Gate string `protobuf:"bytes,1,opt,name=gate,proto3" json:"gate,omitempty"`
+	// Instance of the override to remove, or empty for the override applying to
+	// all replicas.
+	Instance             string   `protobuf:"bytes,2,opt,name=instance,proto3" json:"instance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClearFeatureGateRequest) Reset()         { *m = ClearFeatureGateRequest{} }
+func (m *ClearFeatureGateRequest) String() string { return proto.CompactTextString(m) }
+func (*ClearFeatureGateRequest) ProtoMessage()    {}
+func (*ClearFeatureGateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_109d096f4b62305b, []int{17}
+}
+
+func (m *ClearFeatureGateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClearFeatureGateRequest.Unmarshal(m, b)
+}
+func (m *ClearFeatureGateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClearFeatureGateRequest.Marshal(b, m, deterministic)
+}
+func (m *ClearFeatureGateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClearFeatureGateRequest.Merge(m, src)
+}
+func (m *ClearFeatureGateRequest) XXX_Size() int {
+	return xxx_messageInfo_ClearFeatureGateRequest.Size(m)
+}
+func (m *ClearFeatureGateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClearFeatureGateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClearFeatureGateRequest proto.InternalMessageInfo
+
+func (m *ClearFeatureGateRequest) GetGate() string {
+	if m != nil {
+		return m.Gate
+	}
+	return ""
+}
+
+func (m *ClearFeatureGateRequest) GetInstance() string {
+	if m != nil {
+		return m.Instance
+	}
+	return ""
+}
+
+type ClearFeatureGateResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClearFeatureGateResponse) Reset()         { *m = ClearFeatureGateResponse{} }
+func (m *ClearFeatureGateResponse) String() string { return proto.CompactTextString(m) }
+func (*ClearFeatureGateResponse) ProtoMessage()    {}
+func (*ClearFeatureGateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_109d096f4b62305b, []int{18}
+}
+
+func (m *ClearFeatureGateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClearFeatureGateResponse.Unmarshal(m, b)
+}
+func (m *ClearFeatureGateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClearFeatureGateResponse.Marshal(b, m, deterministic)
+}
+func (m *ClearFeatureGateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClearFeatureGateResponse.Merge(m, src)
+}
+func (m *ClearFeatureGateResponse) XXX_Size() int {
+	return xxx_messageInfo_ClearFeatureGateResponse.Size(m)
+}
+func (m *ClearFeatureGateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClearFeatureGateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClearFeatureGateResponse proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*StorageUsage)(nil), "openmatch.StorageUsage")
+	proto.RegisterType((*GetStorageUsageRequest)(nil), "openmatch.GetStorageUsageRequest")
+	proto.RegisterType((*GetStorageUsageResponse)(nil), "openmatch.GetStorageUsageResponse")
+	proto.RegisterType((*SearchFieldRename)(nil), "openmatch.SearchFieldRename")
+	proto.RegisterType((*MigrateSearchFieldsRequest)(nil), "openmatch.MigrateSearchFieldsRequest")
+	proto.RegisterType((*MigrateSearchFieldsResponse)(nil), "openmatch.MigrateSearchFieldsResponse")
+	proto.RegisterType((*TicketSnapshot)(nil), "openmatch.TicketSnapshot")
+	proto.RegisterType((*ExportTicketsRequest)(nil), "openmatch.ExportTicketsRequest")
+	proto.RegisterType((*ExportTicketsResponse)(nil), "openmatch.ExportTicketsResponse")
+	proto.RegisterType((*ImportTicketsRequest)(nil), "openmatch.ImportTicketsRequest")
+	proto.RegisterType((*ImportTicketsResponse)(nil), "openmatch.ImportTicketsResponse")
+	proto.RegisterType((*FeatureGateOverride)(nil), "openmatch.FeatureGateOverride")
+	proto.RegisterType((*FeatureGate)(nil), "openmatch.FeatureGate")
+	proto.RegisterType((*GetConfigRequest)(nil), "openmatch.GetConfigRequest")
+	proto.RegisterType((*GetConfigResponse)(nil), "openmatch.GetConfigResponse")
+	proto.RegisterMapType((map[string]string)(nil), "openmatch.GetConfigResponse.SettingsEntry")
+	proto.RegisterType((*SetFeatureGateRequest)(nil), "openmatch.SetFeatureGateRequest")
+	proto.RegisterType((*SetFeatureGateResponse)(nil), "openmatch.SetFeatureGateResponse")
+	proto.RegisterType((*ClearFeatureGateRequest)(nil), "openmatch.ClearFeatureGateRequest")
+	proto.RegisterType((*ClearFeatureGateResponse)(nil), "openmatch.ClearFeatureGateResponse")
+}
+
+func init() { proto.RegisterFile("api/admin.proto", fileDescriptor_109d096f4b62305b) }
+
+var fileDescriptor_109d096f4b62305b = []byte{
+	// 1327 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0xcd, 0x6f, 0xdc, 0x44,
+	0x14, 0x97, 0x37, 0xcd, 0xd7, 0xcb, 0x47, 0x93, 0xc9, 0xd7, 0x76, 0x5b, 0x25, 0x8e, 0x4b, 0x4b,
+	0x58, 0x9a, 0x75, 0xba, 0x54, 0x94, 0x6e, 0x41, 0xea, 0x57, 0x5a, 0x22, 0x5a, 0x8a, 0xbc, 0xc0,
+	0x81, 0xcb, 0xca, 0x6b, 0xbf, 0x78, 0x4d, 0xd7, 0x1e, 0x33, 0x33, 0x4e, 0x93, 0x4a, 0x20, 0x44,
+	0x2f, 0x48, 0x5c, 0x10, 0x48, 0x1c, 0xb8, 0x71, 0xe1, 0xc0, 0x85, 0x3f, 0x06, 0xc1, 0x5f, 0xc0,
+	0x91, 0x3f, 0x02, 0xcd, 0x8c, 0x9d, 0x78, 0x77, 0x9d, 0x54, 0x70, 0xda, 0x9d, 0x79, 0xbf, 0x79,
+	0xbf, 0xdf, 0x7b, 0xf3, 0xde, 0x1b, 0xc3, 0x79, 0x37, 0x09, 0x6d, 0xd7, 0x8f, 0xc2, 0xb8, 0x91,
+	0x30, 0x2a, 0x28, 0x99, 0xa6, 0x09, 0xc6, 0x91, 0x2b, 0xbc, 0x5e, 0x8d, 0x48, 0x5b, 0x84, 0x9c,
+	0xbb, 0x01, 0x72, 0x6d, 0xae, 0x5d, 0x0a, 0x28, 0x0d, 0xfa, 0x68, 0xab, 0x63, 0x71, 0x4c, 0x85,
+	0x2b, 0x42, 0x1a, 0xe7, 0xd6, 0xf5, 0xcc, 0xaa, 0x56, 0xdd, 0x74, 0xdf, 0xf6, 0x53, 0xa6, 0x00,
+	0x99, 0xfd, 0x9a, 0xfa, 0xf1, 0xb6, 0x03, 0x8c, 0xb7, 0xf9, 0x73, 0x37, 0x08, 0x90, 0xd9, 0x34,
+	0x51, 0x1e, 0x46, 0xbd, 0x59, 0xff, 0x18, 0x30, 0xdb, 0x16, 0x94, 0xb9, 0x01, 0x7e, 0x22, 0x35,
+	0x90, 0x4d, 0x98, 0x15, 0xa1, 0xf7, 0x0c, 0x45, 0xc7, 0xa3, 0x69, 0x2c, 0xaa, 0x86, 0x69, 0x6c,
+	0x8d, 0x39, 0x33, 0x7a, 0xef, 0xbe, 0xdc, 0x22, 0x3b, 0xb0, 0x1c, 0xc6, 0x3e, 0x1e, 0xa2, 0xdf,
+	0x19, 0x80, 0x56, 0x14, 0x94, 0x64, 0xb6, 0x8f, 0x0b, 0x27, 0xb6, 0x60, 0x21, 0x0c, 0x62, 0xca,
+	0xb0, 0xd3, 0x0f, 0xb9, 0xe8, 0xf0, 0xf0, 0x05, 0x56, 0xc7, 0x14, 0x7a, 0x5e, 0xef, 0x3f, 0x0e,
+	0xb9, 0x68, 0x87, 0x2f, 0x90, 0xbc, 0x09, 0x8b, 0x6e, 0x92, 0x30, 0x7a, 0x18, 0x46, 0xae, 0xc0,
+	0x4e, 0xf7, 0x48, 0x20, 0xaf, 0x9e, 0x53, 0xd0, 0x85, 0x82, 0xe1, 0x9e, 0xdc, 0x97, 0x42, 0xb8,
+	0x1b, 0x25, 0xfd, 0x61, 0x21, 0xe3, 0x5a, 0x48, 0x66, 0x2b, 0x08, 0xb1, 0x6e, 0xc1, 0xea, 0x23,
+	0x14, 0xc5, 0x80, 0x1d, 0xfc, 0x22, 0x45, 0x2e, 0xc8, 0x06, 0xcc, 0x68, 0xbc, 0x56, 0x27, 0xc3,
+	0x1e, 0x77, 0x40, 0x6f, 0x49, 0x65, 0xd6, 0xfb, 0xb0, 0x36, 0x72, 0x94, 0x27, 0x34, 0xe6, 0x48,
+	0xb6, 0x61, 0x3c, 0x95, 0x1b, 0xea, 0xd4, 0x4c, 0x73, 0xad, 0x71, 0x7c, 0xbf, 0x8d, 0x01, 0xbc,
+	0x46, 0x59, 0x37, 0x61, 0xb1, 0x8d, 0x2e, 0xf3, 0x7a, 0x0f, 0x43, 0xec, 0xfb, 0x0e, 0xc6, 0x6e,
+	0x84, 0x84, 0xc0, 0xb9, 0x7d, 0x46, 0x23, 0xe5, 0x62, 0xda, 0x51, 0xff, 0xc9, 0x3c, 0x54, 0x04,
+	0x55, 0x69, 0x9d, 0x76, 0x2a, 0x82, 0x5a, 0x11, 0xd4, 0x9e, 0x84, 0x01, 0x73, 0x05, 0x16, 0xce,
+	0xf3, 0x3c, 0x82, 0xb7, 0x61, 0x92, 0x29, 0x5f, 0xbc, 0x6a, 0x98, 0x63, 0x5b, 0x33, 0xcd, 0x4b,
+	0x45, 0x1d, 0xc3, 0x84, 0x4e, 0x0e, 0x26, 0x6b, 0x30, 0xe9, 0xb3, 0xa3, 0x0e, 0x4b, 0x63, 0x45,
+	0x35, 0xe5, 0x4c, 0xf8, 0xec, 0xc8, 0x49, 0x63, 0xeb, 0xa5, 0x01, 0x17, 0x4b, 0xf9, 0xb2, 0xb0,
+	0x65, 0xfa, 0x3d, 0x37, 0x8e, 0x87, 0xd3, 0x6f, 0x64, 0xe9, 0xd7, 0xb6, 0x62, 0x1d, 0x34, 0x61,
+	0x25, 0xd2, 0x0e, 0x4b, 0x4b, 0x67, 0x29, 0x37, 0x16, 0xaf, 0xec, 0x7b, 0x03, 0xe6, 0xf5, 0xba,
+	0x1d, 0xbb, 0x09, 0xef, 0x51, 0x41, 0xde, 0x80, 0x09, 0x7d, 0x3a, 0x4b, 0xf8, 0x62, 0x21, 0x50,
+	0x0d, 0x75, 0x32, 0x00, 0xa9, 0xc2, 0x64, 0x56, 0x8f, 0x59, 0x70, 0xf9, 0x92, 0xdc, 0x02, 0xc0,
+	0xc3, 0x24, 0xd4, 0xbd, 0xa3, 0xaa, 0x71, 0xa6, 0x79, 0xa1, 0xa1, 0x9b, 0xab, 0x91, 0x37, 0x57,
+	0xe3, 0x41, 0xd6, 0x5c, 0x4e, 0x01, 0x6c, 0xad, 0xc2, 0xf2, 0xee, 0x61, 0x42, 0x99, 0xd0, 0x64,
+	0xf9, 0x0d, 0x58, 0x1f, 0xc1, 0xca, 0xd0, 0x7e, 0x96, 0xa9, 0x9b, 0x30, 0xcd, 0x33, 0xf1, 0xf9,
+	0xe5, 0x5c, 0x18, 0xd1, 0x9c, 0x87, 0xe7, 0x9c, 0x60, 0xad, 0xa7, 0xb0, 0xbc, 0x17, 0x8d, 0x32,
+	0xfd, 0x7f, 0x87, 0x1f, 0xc0, 0xca, 0x5e, 0x54, 0x26, 0xb1, 0x09, 0x2b, 0xa1, 0x32, 0x94, 0xdf,
+	0xe6, 0x52, 0x6e, 0x2c, 0x5e, 0x4d, 0x07, 0x96, 0x1e, 0xa2, 0x2b, 0x52, 0x86, 0x8f, 0x5c, 0x81,
+	0x4f, 0x0f, 0x90, 0xb1, 0xd0, 0x57, 0xa5, 0x1c, 0xb8, 0x02, 0xf3, 0x52, 0x96, 0xff, 0x49, 0x0d,
+	0xa6, 0xc2, 0x98, 0x0b, 0x37, 0xf6, 0x30, 0x2b, 0xe8, 0xe3, 0xb5, 0xbc, 0x23, 0x8c, 0xdd, 0x6e,
+	0x1f, 0x7d, 0x75, 0x0d, 0x53, 0x4e, 0xbe, 0xb4, 0x7e, 0x31, 0x60, 0xa6, 0xc0, 0x20, 0x3d, 0xcb,
+	0x9a, 0xcd, 0x3d, 0xcb, 0xff, 0xc4, 0x84, 0x19, 0x1f, 0xb9, 0xc7, 0x42, 0x35, 0xe3, 0x32, 0xe7,
+	0xc5, 0x2d, 0xb2, 0x0e, 0xe0, 0xd1, 0x78, 0x3f, 0x0c, 0x52, 0x76, 0x4c, 0x51, 0xd8, 0x21, 0xef,
+	0xc2, 0x34, 0xcd, 0xb4, 0xcb, 0x59, 0x23, 0x93, 0xb9, 0x5e, 0x48, 0x66, 0x49, 0x88, 0xce, 0xc9,
+	0x01, 0x8b, 0xc0, 0xc2, 0x23, 0x99, 0x10, 0xe9, 0x2e, 0x2f, 0x84, 0x3f, 0x0d, 0x58, 0x2c, 0x6c,
+	0x66, 0x29, 0x7e, 0x08, 0x53, 0x1c, 0x85, 0x08, 0xe3, 0x20, 0xbf, 0xb3, 0x7a, 0x81, 0x66, 0x04,
+	0xdf, 0x68, 0x67, 0xe0, 0xdd, 0x58, 0xb0, 0x23, 0xe7, 0xf8, 0x2c, 0xb9, 0x0d, 0x73, 0xfb, 0x5a,
+	0x53, 0x47, 0xe6, 0x96, 0x57, 0x2b, 0xca, 0xd9, 0x6a, 0xb9, 0x66, 0x67, 0x76, 0xff, 0x64, 0xc1,
+	0x6b, 0xb7, 0x61, 0x6e, 0xc0, 0x2f, 0x59, 0x80, 0xb1, 0x67, 0x78, 0x94, 0xa5, 0x54, 0xfe, 0x25,
+	0xcb, 0x30, 0x7e, 0xe0, 0xf6, 0xd3, 0xfc, 0xa2, 0xf4, 0xa2, 0x55, 0x79, 0xc7, 0xb0, 0xda, 0xb0,
+	0xd2, 0x46, 0x51, 0x74, 0x9e, 0xd5, 0x63, 0x0b, 0xa6, 0xf2, 0x8c, 0x64, 0x3d, 0xf9, 0xaa, 0x0c,
+	0x1e, 0xe3, 0xad, 0x2a, 0xac, 0x0e, 0x3b, 0xd5, 0x09, 0xb0, 0xf6, 0x60, 0xed, 0x7e, 0x1f, 0x5d,
+	0x56, 0x42, 0xf8, 0x1f, 0x6b, 0xcc, 0xaa, 0x41, 0x75, 0xd4, 0x95, 0xa6, 0x69, 0xfe, 0x3a, 0x09,
+	0xb3, 0x77, 0xe5, 0xf3, 0xdc, 0x46, 0x76, 0x10, 0x7a, 0x48, 0xbe, 0x36, 0xe0, 0xfc, 0xd0, 0xac,
+	0x27, 0x9b, 0x83, 0x57, 0x55, 0xf2, 0x84, 0xd4, 0xac, 0xb3, 0x20, 0x59, 0x48, 0x57, 0xbf, 0xf9,
+	0xe3, 0xef, 0x1f, 0x2b, 0x26, 0x59, 0xb7, 0x0f, 0xae, 0xeb, 0x6f, 0x02, 0xae, 0x49, 0x6d, 0xae,
+	0xe1, 0xb6, 0x7a, 0x23, 0xc8, 0x4f, 0x06, 0x2c, 0x95, 0xcc, 0x5e, 0x72, 0xa5, 0xc0, 0x71, 0xfa,
+	0x5b, 0x50, 0xbb, 0xfa, 0x2a, 0x58, 0x26, 0x67, 0x47, 0xc9, 0xa9, 0x5b, 0x57, 0x46, 0xe5, 0x28,
+	0xf8, 0xbe, 0x82, 0xb7, 0xb2, 0xb9, 0xdc, 0x32, 0xea, 0xe4, 0x4b, 0x98, 0x1b, 0x98, 0x71, 0x64,
+	0xa3, 0x40, 0x55, 0x36, 0x15, 0x6b, 0xe6, 0xe9, 0x80, 0x4c, 0xc5, 0xeb, 0x4a, 0xc5, 0x26, 0xd9,
+	0x18, 0x51, 0xa1, 0x27, 0x11, 0x6f, 0xa1, 0x3a, 0xb7, 0x63, 0x90, 0xaf, 0x60, 0x6e, 0x2f, 0x3a,
+	0x8d, 0x7e, 0x2f, 0x7a, 0x05, 0x7d, 0xe9, 0xe8, 0xb3, 0xea, 0x8a, 0xfe, 0x35, 0xeb, 0x74, 0x7a,
+	0x3d, 0xfc, 0x64, 0xf8, 0x01, 0x4c, 0x1f, 0x37, 0x2a, 0xb9, 0x58, 0xde, 0xbe, 0x9a, 0xf7, 0xd2,
+	0x59, 0xbd, 0x6d, 0x6d, 0x28, 0xce, 0x0b, 0x64, 0x6d, 0x84, 0x53, 0x0f, 0x26, 0xf2, 0xd2, 0x80,
+	0xf9, 0xc1, 0xb6, 0x20, 0xe6, 0xc0, 0x7b, 0x5e, 0xd2, 0x86, 0xb5, 0xcd, 0x33, 0x10, 0x19, 0xf1,
+	0x35, 0x45, 0x7c, 0xd5, 0xda, 0x1c, 0x21, 0xce, 0xc6, 0x84, 0x1a, 0x29, 0x2d, 0x8e, 0x2a, 0xdc,
+	0xef, 0x0c, 0x58, 0x18, 0xee, 0x1b, 0x52, 0xac, 0xf3, 0x53, 0xfa, 0xb3, 0x76, 0xf9, 0x4c, 0x4c,
+	0xa6, 0xa5, 0xa1, 0xb4, 0x6c, 0x59, 0x97, 0xcf, 0xd6, 0xe2, 0xc9, 0xf3, 0x2d, 0xa3, 0x7e, 0xef,
+	0xdb, 0xb1, 0x1f, 0xee, 0xfe, 0x55, 0x21, 0xbf, 0x1b, 0x30, 0xae, 0xfa, 0xd5, 0xda, 0x03, 0x78,
+	0x9a, 0x60, 0x6c, 0x3e, 0x91, 0x3c, 0x64, 0xb5, 0x27, 0x44, 0xc2, 0x5b, 0xb6, 0x2d, 0xa9, 0xb7,
+	0x35, 0xb7, 0x8f, 0x07, 0xb5, 0xcb, 0x27, 0xeb, 0x6d, 0x3f, 0xe4, 0x5e, 0xca, 0xf9, 0x1d, 0xfd,
+	0xce, 0x07, 0x8c, 0xa6, 0x09, 0x6f, 0x78, 0x34, 0xaa, 0x7f, 0x0a, 0xe4, 0x6e, 0xe2, 0x7a, 0x3d,
+	0x34, 0x9b, 0x8d, 0x1d, 0xf3, 0x71, 0xe8, 0xa1, 0x1c, 0xd9, 0x77, 0x72, 0x97, 0x41, 0x28, 0x7a,
+	0x69, 0x57, 0x22, 0x6d, 0x7d, 0x74, 0x9f, 0xb2, 0xc0, 0x8d, 0x90, 0x17, 0xc8, 0xec, 0x6e, 0x9f,
+	0x76, 0xed, 0xc8, 0xe5, 0x02, 0x99, 0xfd, 0x78, 0xef, 0xfe, 0xee, 0x87, 0xed, 0xdd, 0xe6, 0xd8,
+	0xf5, 0xc6, 0x4e, 0xbd, 0x62, 0x54, 0x9a, 0xf2, 0xf3, 0xb5, 0x1f, 0x7a, 0xea, 0x0b, 0xc2, 0xfe,
+	0x9c, 0xd3, 0xb8, 0x35, 0xb2, 0xe3, 0xdc, 0x86, 0xb1, 0x1b, 0x3b, 0x37, 0xc8, 0x0d, 0xa8, 0x3b,
+	0x28, 0x52, 0x16, 0xa3, 0x6f, 0x3e, 0xef, 0x61, 0x6c, 0x8a, 0x1e, 0x9a, 0x0c, 0x39, 0x4d, 0x99,
+	0x87, 0xa6, 0x4f, 0x91, 0x9b, 0x31, 0x15, 0x26, 0x1e, 0x86, 0x5c, 0x34, 0xc8, 0x04, 0x9c, 0xfb,
+	0xb9, 0x62, 0x4c, 0xb2, 0xf7, 0xa0, 0x7a, 0x92, 0x0c, 0xf3, 0x01, 0xf5, 0xd2, 0x08, 0x63, 0xfd,
+	0xb5, 0x4f, 0x36, 0xcb, 0x53, 0x63, 0xf3, 0x50, 0xa0, 0xed, 0x53, 0x8f, 0xdb, 0x9f, 0x99, 0x43,
+	0xa6, 0x42, 0x5c, 0xc9, 0xb3, 0xc0, 0x4e, 0xba, 0xbf, 0x55, 0xa6, 0xa5, 0x7f, 0xe5, 0xbe, 0x3b,
+	0xa1, 0xbe, 0x90, 0xde, 0xfa, 0x77, 0x00, 0x5a, 0x7d, 0x5a, 0x86, 0xdc, 0x0c, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AdminServiceClient interface {
+	// GetStorageUsage reports ticket counts, index cardinality, ignore list size
+	// and approximate memory used in state storage, for capacity planning.
+	GetStorageUsage(ctx context.Context, in *GetStorageUsageRequest, opts ...grpc.CallOption) (*GetStorageUsageResponse, error)
+	// MigrateSearchFields rewrites the SearchFields of all stored Tickets, for
+	// example when a game renames "mmr" to "skill", without draining the queue.
+	// Query services pick up migrated Tickets on their next cache update.
+	// Tickets created with the old names after the migration are not changed, so
+	// Game Frontends should switch to the new names first.
+	MigrateSearchFields(ctx context.Context, in *MigrateSearchFieldsRequest, opts ...grpc.CallOption) (*MigrateSearchFieldsResponse, error)
+	// ExportTickets streams back a snapshot of every Ticket in state storage,
+	// indexed or not, for example before migrating to a new Redis instance.
+	// Tickets proposed in matches are exported as available, since their
+	// proposals don't survive the migration.
+	ExportTickets(ctx context.Context, in *ExportTicketsRequest, opts ...grpc.CallOption) (AdminService_ExportTicketsClient, error)
+	// ImportTickets saves Tickets exported by ExportTickets, overwriting Tickets
+	// with the same ids, and indexes those which were indexed.  It's meant to
+	// restore an export into an empty state storage, one call per exported page.
+	// The ticket quota isn't enforced.
+	ImportTickets(ctx context.Context, in *ImportTicketsRequest, opts ...grpc.CallOption) (*ImportTicketsResponse, error)
+	// GetConfig returns the effective configuration, and the FeatureGates which
+	// can be toggled at runtime along with their overrides.
+	GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*GetConfigResponse, error)
+	// SetFeatureGate overrides a FeatureGate for all replicas or for a single
+	// one, eg. to canary a feature without a rollout. Overrides are persisted in
+	// state storage, and picked up by components within
+	// featureGates.refreshInterval.
+	SetFeatureGate(ctx context.Context, in *SetFeatureGateRequest, opts ...grpc.CallOption) (*SetFeatureGateResponse, error)
+	// ClearFeatureGate removes an override set with SetFeatureGate, reverting
+	// to the configured value.
+	ClearFeatureGate(ctx context.Context, in *ClearFeatureGateRequest, opts ...grpc.CallOption) (*ClearFeatureGateResponse, error)
+}
+
+type adminServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAdminServiceClient(cc *grpc.ClientConn) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) GetStorageUsage(ctx context.Context, in *GetStorageUsageRequest, opts ...grpc.CallOption) (*GetStorageUsageResponse, error) {
+	out := new(GetStorageUsageResponse)
+	err := c.cc.Invoke(ctx, "/openmatch.AdminService/GetStorageUsage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) MigrateSearchFields(ctx context.Context, in *MigrateSearchFieldsRequest, opts ...grpc.CallOption) (*MigrateSearchFieldsResponse, error) {
+	out := new(MigrateSearchFieldsResponse)
+	err := c.cc.Invoke(ctx, "/openmatch.AdminService/MigrateSearchFields", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ExportTickets(ctx context.Context, in *ExportTicketsRequest, opts ...grpc.CallOption) (AdminService_ExportTicketsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_AdminService_serviceDesc.Streams[0], "/openmatch.AdminService/ExportTickets", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &adminServiceExportTicketsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AdminService_ExportTicketsClient interface {
+	Recv() (*ExportTicketsResponse, error)
+	grpc.ClientStream
+}
+
+type adminServiceExportTicketsClient struct {
+	grpc.ClientStream
+}
+
+func (x *adminServiceExportTicketsClient) Recv() (*ExportTicketsResponse, error) {
+	m := new(ExportTicketsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *adminServiceClient) ImportTickets(ctx context.Context, in *ImportTicketsRequest, opts ...grpc.CallOption) (*ImportTicketsResponse, error) {
+	out := new(ImportTicketsResponse)
+	err := c.cc.Invoke(ctx, "/openmatch.AdminService/ImportTickets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*GetConfigResponse, error) {
+	out := new(GetConfigResponse)
+	err := c.cc.Invoke(ctx, "/openmatch.AdminService/GetConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetFeatureGate(ctx context.Context, in *SetFeatureGateRequest, opts ...grpc.CallOption) (*SetFeatureGateResponse, error) {
+	out := new(SetFeatureGateResponse)
+	err := c.cc.Invoke(ctx, "/openmatch.AdminService/SetFeatureGate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ClearFeatureGate(ctx context.Context, in *ClearFeatureGateRequest, opts ...grpc.CallOption) (*ClearFeatureGateResponse, error) {
+	out := new(ClearFeatureGateResponse)
+	err := c.cc.Invoke(ctx, "/openmatch.AdminService/ClearFeatureGate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+type AdminServiceServer interface {
+	// GetStorageUsage reports ticket counts, index cardinality, ignore list size
+	// and approximate memory used in state storage, for capacity planning.
+	GetStorageUsage(context.Context, *GetStorageUsageRequest) (*GetStorageUsageResponse, error)
+	// MigrateSearchFields rewrites the SearchFields of all stored Tickets, for
+	// example when a game renames "mmr" to "skill", without draining the queue.
+	// Query services pick up migrated Tickets on their next cache update.
+	// Tickets created with the old names after the migration are not changed, so
+	// Game Frontends should switch to the new names first.
+	MigrateSearchFields(context.Context, *MigrateSearchFieldsRequest) (*MigrateSearchFieldsResponse, error)
+	// ExportTickets streams back a snapshot of every Ticket in state storage,
+	// indexed or not, for example before migrating to a new Redis instance.
+	// Tickets proposed in matches are exported as available, since their
+	// proposals don't survive the migration.
+	ExportTickets(*ExportTicketsRequest, AdminService_ExportTicketsServer) error
+	// ImportTickets saves Tickets exported by ExportTickets, overwriting Tickets
+	// with the same ids, and indexes those which were indexed.  It's meant to
+	// restore an export into an empty state storage, one call per exported page.
+	// The ticket quota isn't enforced.
+	ImportTickets(context.Context, *ImportTicketsRequest) (*ImportTicketsResponse, error)
+	// GetConfig returns the effective configuration, and the FeatureGates which
+	// can be toggled at runtime along with their overrides.
+	GetConfig(context.Context, *GetConfigRequest) (*GetConfigResponse, error)
+	// SetFeatureGate overrides a FeatureGate for all replicas or for a single
+	// one, eg. to canary a feature without a rollout. Overrides are persisted in
+	// state storage, and picked up by components within
+	// featureGates.refreshInterval.
+	SetFeatureGate(context.Context, *SetFeatureGateRequest) (*SetFeatureGateResponse, error)
+	// ClearFeatureGate removes an override set with SetFeatureGate, reverting
+	// to the configured value.
+	ClearFeatureGate(context.Context, *ClearFeatureGateRequest) (*ClearFeatureGateResponse, error)
+}
+
+// UnimplementedAdminServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedAdminServiceServer struct {
+}
+
+func (*UnimplementedAdminServiceServer) GetStorageUsage(ctx context.Context, req *GetStorageUsageRequest) (*GetStorageUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStorageUsage not implemented")
+}
+func (*UnimplementedAdminServiceServer) MigrateSearchFields(ctx context.Context, req *MigrateSearchFieldsRequest) (*MigrateSearchFieldsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MigrateSearchFields not implemented")
+}
+func (*UnimplementedAdminServiceServer) ExportTickets(req *ExportTicketsRequest, srv AdminService_ExportTicketsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportTickets not implemented")
+}
+func (*UnimplementedAdminServiceServer) ImportTickets(ctx context.Context, req *ImportTicketsRequest) (*ImportTicketsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportTickets not implemented")
+}
+func (*UnimplementedAdminServiceServer) GetConfig(ctx context.Context, req *GetConfigRequest) (*GetConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfig not implemented")
+}
+func (*UnimplementedAdminServiceServer) SetFeatureGate(ctx context.Context, req *SetFeatureGateRequest) (*SetFeatureGateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetFeatureGate not implemented")
+}
+func (*UnimplementedAdminServiceServer) ClearFeatureGate(ctx context.Context, req *ClearFeatureGateRequest) (*ClearFeatureGateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearFeatureGate not implemented")
+}
+
+func RegisterAdminServiceServer(s *grpc.Server, srv AdminServiceServer) {
+	s.RegisterService(&_AdminService_serviceDesc, srv)
+}
+
+func _AdminService_GetStorageUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStorageUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetStorageUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/openmatch.AdminService/GetStorageUsage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetStorageUsage(ctx, req.(*GetStorageUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_MigrateSearchFields_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MigrateSearchFieldsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).MigrateSearchFields(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/openmatch.AdminService/MigrateSearchFields",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).MigrateSearchFields(ctx, req.(*MigrateSearchFieldsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ExportTickets_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportTicketsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdminServiceServer).ExportTickets(m, &adminServiceExportTicketsServer{stream})
+}
+
+type AdminService_ExportTicketsServer interface {
+	Send(*ExportTicketsResponse) error
+	grpc.ServerStream
+}
+
+type adminServiceExportTicketsServer struct {
+	grpc.ServerStream
+}
+
+func (x *adminServiceExportTicketsServer) Send(m *ExportTicketsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AdminService_ImportTickets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportTicketsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ImportTickets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/openmatch.AdminService/ImportTickets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ImportTickets(ctx, req.(*ImportTicketsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/openmatch.AdminService/GetConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetConfig(ctx, req.(*GetConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetFeatureGate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetFeatureGateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetFeatureGate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/openmatch.AdminService/SetFeatureGate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetFeatureGate(ctx, req.(*SetFeatureGateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ClearFeatureGate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearFeatureGateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ClearFeatureGate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/openmatch.AdminService/ClearFeatureGate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ClearFeatureGate(ctx, req.(*ClearFeatureGateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _AdminService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "openmatch.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStorageUsage",
+			Handler:    _AdminService_GetStorageUsage_Handler,
+		},
+		{
+			MethodName: "MigrateSearchFields",
+			Handler:    _AdminService_MigrateSearchFields_Handler,
+		},
+		{
+			MethodName: "ImportTickets",
+			Handler:    _AdminService_ImportTickets_Handler,
+		},
+		{
+			MethodName: "GetConfig",
+			Handler:    _AdminService_GetConfig_Handler,
+		},
+		{
+			MethodName: "SetFeatureGate",
+			Handler:    _AdminService_SetFeatureGate_Handler,
+		},
+		{
+			MethodName: "ClearFeatureGate",
+			Handler:    _AdminService_ClearFeatureGate_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportTickets",
+			Handler:       _AdminService_ExportTickets_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/admin.proto",
+}
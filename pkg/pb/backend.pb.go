@@ -7,6 +7,8 @@ import (
 	context "context"
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	duration "github.com/golang/protobuf/ptypes/duration"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	_ "github.com/grpc-ecosystem/grpc-gateway/protoc-gen-swagger/options"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	grpc "google.golang.org/grpc"
@@ -30,6 +32,8 @@ type FunctionConfig_Type int32
 
 const (
 	FunctionConfig_GRPC FunctionConfig_Type = 0
+	// The MatchFunction is called with a JSON POST to /v1/matchfunction:run, and streams its
+	// RunResponses back as newline delimited {"result": ...} objects, like the gRPC gateway does.
 	FunctionConfig_REST FunctionConfig_Type = 1
 )
 
@@ -51,14 +55,52 @@ func (FunctionConfig_Type) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_8dab762378f455cd, []int{0, 0}
 }
 
+type AssignmentResult_Status int32
+
+const (
+	// The Ticket was assigned.
+	AssignmentResult_ASSIGNED AssignmentResult_Status = 0
+	// The Ticket doesn't exist and wasn't assigned.
+	AssignmentResult_NOT_FOUND AssignmentResult_Status = 1
+	// The Ticket already had an Assignment, which was replaced.
+	AssignmentResult_ALREADY_ASSIGNED AssignmentResult_Status = 2
+)
+
+var AssignmentResult_Status_name = map[int32]string{
+	0: "ASSIGNED",
+	1: "NOT_FOUND",
+	2: "ALREADY_ASSIGNED",
+}
+
+var AssignmentResult_Status_value = map[string]int32{
+	"ASSIGNED":         0,
+	"NOT_FOUND":        1,
+	"ALREADY_ASSIGNED": 2,
+}
+
+func (x AssignmentResult_Status) String() string {
+	return proto.EnumName(AssignmentResult_Status_name, int32(x))
+}
+
+func (AssignmentResult_Status) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_8dab762378f455cd, []int{7, 0}
+}
+
 // FunctionConfig specifies a MMF address and client type for Backend to establish connections with the MMF
 type FunctionConfig struct {
-	Host                 string              `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
-	Port                 int32               `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
-	Type                 FunctionConfig_Type `protobuf:"varint,3,opt,name=type,proto3,enum=openmatch.FunctionConfig_Type" json:"type,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
-	XXX_unrecognized     []byte              `json:"-"`
-	XXX_sizecache        int32               `json:"-"`
+	// Host of the MatchFunction.  REST MatchFunctions may be given with an http:// or https:// scheme.
+	// gRPC MatchFunctions served on a unix socket, e.g. by a sidecar, are given as unix:///path/to/socket,
+	// and the port is ignored.
+	Host string              `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Port int32               `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	Type FunctionConfig_Type `protobuf:"varint,3,opt,name=type,proto3,enum=openmatch.FunctionConfig_Type" json:"type,omitempty"`
+	// If true, the Backend queries the Profile's Pools itself and passes their Tickets to the MatchFunction in
+	// RunRequest.pool_tickets, so that simple MatchFunctions needn't call the QueryService.
+	// Tickets are only passed if no Pool has more than backend.maxPassedPoolSize Tickets.
+	PassPoolTickets      bool     `protobuf:"varint,4,opt,name=pass_pool_tickets,json=passPoolTickets,proto3" json:"pass_pool_tickets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *FunctionConfig) Reset()         { *m = FunctionConfig{} }
@@ -107,14 +149,31 @@ func (m *FunctionConfig) GetType() FunctionConfig_Type {
 	return FunctionConfig_GRPC
 }
 
+func (m *FunctionConfig) GetPassPoolTickets() bool {
+	if m != nil {
+		return m.PassPoolTickets
+	}
+	return false
+}
+
 type FetchMatchesRequest struct {
 	// A configuration for the MatchFunction server of this FetchMatches call.
 	Config *FunctionConfig `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
 	// A MatchProfile that will be sent to the MatchFunction server of this FetchMatches call.
-	Profile              *MatchProfile `protobuf:"bytes,2,opt,name=profile,proto3" json:"profile,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+	Profile *MatchProfile `protobuf:"bytes,2,opt,name=profile,proto3" json:"profile,omitempty"`
+	// More MatchProfiles to run in this FetchMatches call, each with its own MatchFunction call, so that
+	// their proposals are evaluated in the same synchronizer cycle.  Profile names must be unique.  Every
+	// returned Match has match_profile set to the name of the profile which proposed it.
+	Profiles []*MatchProfile `protobuf:"bytes,3,rep,name=profiles,proto3" json:"profiles,omitempty"`
+	// How long the MatchFunction may run, from the start of this call, before it's canceled and the call
+	// fails with DeadlineExceeded.  Capped at backend.mmfTimeout if that is set.
+	MmfTimeout *duration.Duration `protobuf:"bytes,4,opt,name=mmf_timeout,json=mmfTimeout,proto3" json:"mmf_timeout,omitempty"`
+	// If true, the Matches proposed before the MatchFunction timed out are still evaluated and returned
+	// before the call fails.  Otherwise they are dropped and their Tickets released.
+	IncludePartialResults bool     `protobuf:"varint,5,opt,name=include_partial_results,json=includePartialResults,proto3" json:"include_partial_results,omitempty"`
+	XXX_NoUnkeyedLiteral  struct{} `json:"-"`
+	XXX_unrecognized      []byte   `json:"-"`
+	XXX_sizecache         int32    `json:"-"`
 }
 
 func (m *FetchMatchesRequest) Reset()         { *m = FetchMatchesRequest{} }
@@ -156,6 +215,27 @@ func (m *FetchMatchesRequest) GetProfile() *MatchProfile {
 	return nil
 }
 
+func (m *FetchMatchesRequest) GetProfiles() []*MatchProfile {
+	if m != nil {
+		return m.Profiles
+	}
+	return nil
+}
+
+func (m *FetchMatchesRequest) GetMmfTimeout() *duration.Duration {
+	if m != nil {
+		return m.MmfTimeout
+	}
+	return nil
+}
+
+func (m *FetchMatchesRequest) GetIncludePartialResults() bool {
+	if m != nil {
+		return m.IncludePartialResults
+	}
+	return false
+}
+
 type FetchMatchesResponse struct {
 	// A Match generated by the user-defined MMF with the specified MatchProfiles.
 	// A valid Match response will contain at least one ticket.
@@ -269,7 +349,8 @@ func (m *ReleaseTicketsResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ReleaseTicketsResponse proto.InternalMessageInfo
 
-type AssignTicketsRequest struct {
+// AssignmentGroup is a set of Tickets which get the same Assignment, eg. the roster of one team.
+type AssignmentGroup struct {
 	// TicketIds is a list of strings representing Open Match generated Ids which apply to an Assignment.
 	TicketIds []string `protobuf:"bytes,1,rep,name=ticket_ids,json=ticketIds,proto3" json:"ticket_ids,omitempty"`
 	// An Assignment specifies game connection related information to be associated with the TicketIds.
@@ -279,11 +360,63 @@ type AssignTicketsRequest struct {
 	XXX_sizecache        int32       `json:"-"`
 }
 
+func (m *AssignmentGroup) Reset()         { *m = AssignmentGroup{} }
+func (m *AssignmentGroup) String() string { return proto.CompactTextString(m) }
+func (*AssignmentGroup) ProtoMessage()    {}
+func (*AssignmentGroup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8dab762378f455cd, []int{5}
+}
+
+func (m *AssignmentGroup) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AssignmentGroup.Unmarshal(m, b)
+}
+func (m *AssignmentGroup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AssignmentGroup.Marshal(b, m, deterministic)
+}
+func (m *AssignmentGroup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AssignmentGroup.Merge(m, src)
+}
+func (m *AssignmentGroup) XXX_Size() int {
+	return xxx_messageInfo_AssignmentGroup.Size(m)
+}
+func (m *AssignmentGroup) XXX_DiscardUnknown() {
+	xxx_messageInfo_AssignmentGroup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AssignmentGroup proto.InternalMessageInfo
+
+func (m *AssignmentGroup) GetTicketIds() []string {
+	if m != nil {
+		return m.TicketIds
+	}
+	return nil
+}
+
+func (m *AssignmentGroup) GetAssignment() *Assignment {
+	if m != nil {
+		return m.Assignment
+	}
+	return nil
+}
+
+type AssignTicketsRequest struct {
+	// TicketIds is a list of strings representing Open Match generated Ids which apply to an Assignment.
+	TicketIds []string `protobuf:"bytes,1,rep,name=ticket_ids,json=ticketIds,proto3" json:"ticket_ids,omitempty"`
+	// An Assignment specifies game connection related information to be associated with the TicketIds.
+	Assignment *Assignment `protobuf:"bytes,2,opt,name=assignment,proto3" json:"assignment,omitempty"`
+	// More groups of TicketIds with their own Assignment, eg. one per team of a match, all applied
+	// together with ticket_ids in a single transaction.  A TicketId may only be in one group.
+	Assignments          []*AssignmentGroup `protobuf:"bytes,3,rep,name=assignments,proto3" json:"assignments,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
 func (m *AssignTicketsRequest) Reset()         { *m = AssignTicketsRequest{} }
 func (m *AssignTicketsRequest) String() string { return proto.CompactTextString(m) }
 func (*AssignTicketsRequest) ProtoMessage()    {}
 func (*AssignTicketsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8dab762378f455cd, []int{5}
+	return fileDescriptor_8dab762378f455cd, []int{6}
 }
 
 func (m *AssignTicketsRequest) XXX_Unmarshal(b []byte) error {
@@ -318,17 +451,81 @@ func (m *AssignTicketsRequest) GetAssignment() *Assignment {
 	return nil
 }
 
+func (m *AssignTicketsRequest) GetAssignments() []*AssignmentGroup {
+	if m != nil {
+		return m.Assignments
+	}
+	return nil
+}
+
+// The outcome of assigning one Ticket in AssignTickets.
+type AssignmentResult struct {
+	// Id of the requested Ticket.
+	TicketId             string                  `protobuf:"bytes,1,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	Status               AssignmentResult_Status `protobuf:"varint,2,opt,name=status,proto3,enum=openmatch.AssignmentResult_Status" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *AssignmentResult) Reset()         { *m = AssignmentResult{} }
+func (m *AssignmentResult) String() string { return proto.CompactTextString(m) }
+func (*AssignmentResult) ProtoMessage()    {}
+func (*AssignmentResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8dab762378f455cd, []int{7}
+}
+
+func (m *AssignmentResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AssignmentResult.Unmarshal(m, b)
+}
+func (m *AssignmentResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AssignmentResult.Marshal(b, m, deterministic)
+}
+func (m *AssignmentResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AssignmentResult.Merge(m, src)
+}
+func (m *AssignmentResult) XXX_Size() int {
+	return xxx_messageInfo_AssignmentResult.Size(m)
+}
+func (m *AssignmentResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_AssignmentResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AssignmentResult proto.InternalMessageInfo
+
+func (m *AssignmentResult) GetTicketId() string {
+	if m != nil {
+		return m.TicketId
+	}
+	return ""
+}
+
+func (m *AssignmentResult) GetStatus() AssignmentResult_Status {
+	if m != nil {
+		return m.Status
+	}
+	return AssignmentResult_ASSIGNED
+}
+
 type AssignTicketsResponse struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	// Ids of the requested Tickets which don't exist, eg. because they were deleted
+	// after being returned in a match, and weren't assigned.  The remaining Tickets
+	// of their matches may need to be released with ReleaseTickets to be matched
+	// again.
+	NotFoundTicketIds []string `protobuf:"bytes,1,rep,name=not_found_ticket_ids,json=notFoundTicketIds,proto3" json:"not_found_ticket_ids,omitempty"`
+	// The outcome for each requested Ticket, in the order of the request, so that
+	// only the Tickets which weren't assigned need to be handled again.
+	Results              []*AssignmentResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
 }
 
 func (m *AssignTicketsResponse) Reset()         { *m = AssignTicketsResponse{} }
 func (m *AssignTicketsResponse) String() string { return proto.CompactTextString(m) }
 func (*AssignTicketsResponse) ProtoMessage()    {}
 func (*AssignTicketsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8dab762378f455cd, []int{6}
+	return fileDescriptor_8dab762378f455cd, []int{8}
 }
 
 func (m *AssignTicketsResponse) XXX_Unmarshal(b []byte) error {
@@ -349,69 +546,382 @@ func (m *AssignTicketsResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_AssignTicketsResponse proto.InternalMessageInfo
 
+func (m *AssignTicketsResponse) GetNotFoundTicketIds() []string {
+	if m != nil {
+		return m.NotFoundTicketIds
+	}
+	return nil
+}
+
+func (m *AssignTicketsResponse) GetResults() []*AssignmentResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+type RequeueTicketsRequest struct {
+	// TicketIds is a list of strings representing Open Match generated Ids of assigned Tickets whose
+	// Assignment failed, eg. because their game server couldn't be allocated.
+	TicketIds            []string `protobuf:"bytes,1,rep,name=ticket_ids,json=ticketIds,proto3" json:"ticket_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RequeueTicketsRequest) Reset()         { *m = RequeueTicketsRequest{} }
+func (m *RequeueTicketsRequest) String() string { return proto.CompactTextString(m) }
+func (*RequeueTicketsRequest) ProtoMessage()    {}
+func (*RequeueTicketsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8dab762378f455cd, []int{9}
+}
+
+func (m *RequeueTicketsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RequeueTicketsRequest.Unmarshal(m, b)
+}
+func (m *RequeueTicketsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RequeueTicketsRequest.Marshal(b, m, deterministic)
+}
+func (m *RequeueTicketsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RequeueTicketsRequest.Merge(m, src)
+}
+func (m *RequeueTicketsRequest) XXX_Size() int {
+	return xxx_messageInfo_RequeueTicketsRequest.Size(m)
+}
+func (m *RequeueTicketsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RequeueTicketsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RequeueTicketsRequest proto.InternalMessageInfo
+
+func (m *RequeueTicketsRequest) GetTicketIds() []string {
+	if m != nil {
+		return m.TicketIds
+	}
+	return nil
+}
+
+type RequeueTicketsResponse struct {
+	// Ids of the requested Tickets which don't exist.
+	NotFoundTicketIds []string `protobuf:"bytes,1,rep,name=not_found_ticket_ids,json=notFoundTicketIds,proto3" json:"not_found_ticket_ids,omitempty"`
+	// Ids of the requested Tickets which weren't requeued because their Assignment already failed
+	// backend.maxAssignmentFailures times.  They keep their Assignment.
+	ExhaustedTicketIds   []string `protobuf:"bytes,2,rep,name=exhausted_ticket_ids,json=exhaustedTicketIds,proto3" json:"exhausted_ticket_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RequeueTicketsResponse) Reset()         { *m = RequeueTicketsResponse{} }
+func (m *RequeueTicketsResponse) String() string { return proto.CompactTextString(m) }
+func (*RequeueTicketsResponse) ProtoMessage()    {}
+func (*RequeueTicketsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8dab762378f455cd, []int{10}
+}
+
+func (m *RequeueTicketsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RequeueTicketsResponse.Unmarshal(m, b)
+}
+func (m *RequeueTicketsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RequeueTicketsResponse.Marshal(b, m, deterministic)
+}
+func (m *RequeueTicketsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RequeueTicketsResponse.Merge(m, src)
+}
+func (m *RequeueTicketsResponse) XXX_Size() int {
+	return xxx_messageInfo_RequeueTicketsResponse.Size(m)
+}
+func (m *RequeueTicketsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RequeueTicketsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RequeueTicketsResponse proto.InternalMessageInfo
+
+func (m *RequeueTicketsResponse) GetNotFoundTicketIds() []string {
+	if m != nil {
+		return m.NotFoundTicketIds
+	}
+	return nil
+}
+
+func (m *RequeueTicketsResponse) GetExhaustedTicketIds() []string {
+	if m != nil {
+		return m.ExhaustedTicketIds
+	}
+	return nil
+}
+
+type ReleaseAllTicketsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReleaseAllTicketsRequest) Reset()         { *m = ReleaseAllTicketsRequest{} }
+func (m *ReleaseAllTicketsRequest) String() string { return proto.CompactTextString(m) }
+func (*ReleaseAllTicketsRequest) ProtoMessage()    {}
+func (*ReleaseAllTicketsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8dab762378f455cd, []int{11}
+}
+
+func (m *ReleaseAllTicketsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReleaseAllTicketsRequest.Unmarshal(m, b)
+}
+func (m *ReleaseAllTicketsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReleaseAllTicketsRequest.Marshal(b, m, deterministic)
+}
+func (m *ReleaseAllTicketsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReleaseAllTicketsRequest.Merge(m, src)
+}
+func (m *ReleaseAllTicketsRequest) XXX_Size() int {
+	return xxx_messageInfo_ReleaseAllTicketsRequest.Size(m)
+}
+func (m *ReleaseAllTicketsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReleaseAllTicketsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReleaseAllTicketsRequest proto.InternalMessageInfo
+
+type ReleaseAllTicketsResponse struct {
+	// Number of proposed Tickets returned to the matchmaking pool.
+	ReleasedTicketCount  int64    `protobuf:"varint,1,opt,name=released_ticket_count,json=releasedTicketCount,proto3" json:"released_ticket_count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReleaseAllTicketsResponse) Reset()         { *m = ReleaseAllTicketsResponse{} }
+func (m *ReleaseAllTicketsResponse) String() string { return proto.CompactTextString(m) }
+func (*ReleaseAllTicketsResponse) ProtoMessage()    {}
+func (*ReleaseAllTicketsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8dab762378f455cd, []int{12}
+}
+
+func (m *ReleaseAllTicketsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReleaseAllTicketsResponse.Unmarshal(m, b)
+}
+func (m *ReleaseAllTicketsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReleaseAllTicketsResponse.Marshal(b, m, deterministic)
+}
+func (m *ReleaseAllTicketsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReleaseAllTicketsResponse.Merge(m, src)
+}
+func (m *ReleaseAllTicketsResponse) XXX_Size() int {
+	return xxx_messageInfo_ReleaseAllTicketsResponse.Size(m)
+}
+func (m *ReleaseAllTicketsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReleaseAllTicketsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReleaseAllTicketsResponse proto.InternalMessageInfo
+
+func (m *ReleaseAllTicketsResponse) GetReleasedTicketCount() int64 {
+	if m != nil {
+		return m.ReleasedTicketCount
+	}
+	return 0
+}
+
+type ReserveTicketsRequest struct {
+	// Identifies the scheduled event, eg. a tournament round.  Required.
+	EventId string `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// When the event window opens.  Required.
+	StartTime *timestamp.Timestamp `protobuf:"bytes,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// TicketIds of the generated Tickets to reserve.
+	TicketIds            []string `protobuf:"bytes,3,rep,name=ticket_ids,json=ticketIds,proto3" json:"ticket_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReserveTicketsRequest) Reset()         { *m = ReserveTicketsRequest{} }
+func (m *ReserveTicketsRequest) String() string { return proto.CompactTextString(m) }
+func (*ReserveTicketsRequest) ProtoMessage()    {}
+func (*ReserveTicketsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8dab762378f455cd, []int{13}
+}
+
+func (m *ReserveTicketsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReserveTicketsRequest.Unmarshal(m, b)
+}
+func (m *ReserveTicketsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReserveTicketsRequest.Marshal(b, m, deterministic)
+}
+func (m *ReserveTicketsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReserveTicketsRequest.Merge(m, src)
+}
+func (m *ReserveTicketsRequest) XXX_Size() int {
+	return xxx_messageInfo_ReserveTicketsRequest.Size(m)
+}
+func (m *ReserveTicketsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReserveTicketsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReserveTicketsRequest proto.InternalMessageInfo
+
+func (m *ReserveTicketsRequest) GetEventId() string {
+	if m != nil {
+		return m.EventId
+	}
+	return ""
+}
+
+func (m *ReserveTicketsRequest) GetStartTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.StartTime
+	}
+	return nil
+}
+
+func (m *ReserveTicketsRequest) GetTicketIds() []string {
+	if m != nil {
+		return m.TicketIds
+	}
+	return nil
+}
+
+type ReserveTicketsResponse struct {
+	// TicketIds of the request which weren't found in state storage.
+	NotFoundTicketIds    []string `protobuf:"bytes,1,rep,name=not_found_ticket_ids,json=notFoundTicketIds,proto3" json:"not_found_ticket_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReserveTicketsResponse) Reset()         { *m = ReserveTicketsResponse{} }
+func (m *ReserveTicketsResponse) String() string { return proto.CompactTextString(m) }
+func (*ReserveTicketsResponse) ProtoMessage()    {}
+func (*ReserveTicketsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8dab762378f455cd, []int{14}
+}
+
+func (m *ReserveTicketsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReserveTicketsResponse.Unmarshal(m, b)
+}
+func (m *ReserveTicketsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReserveTicketsResponse.Marshal(b, m, deterministic)
+}
+func (m *ReserveTicketsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReserveTicketsResponse.Merge(m, src)
+}
+func (m *ReserveTicketsResponse) XXX_Size() int {
+	return xxx_messageInfo_ReserveTicketsResponse.Size(m)
+}
+func (m *ReserveTicketsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReserveTicketsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReserveTicketsResponse proto.InternalMessageInfo
+
+func (m *ReserveTicketsResponse) GetNotFoundTicketIds() []string {
+	if m != nil {
+		return m.NotFoundTicketIds
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("openmatch.FunctionConfig_Type", FunctionConfig_Type_name, FunctionConfig_Type_value)
+	proto.RegisterEnum("openmatch.AssignmentResult_Status", AssignmentResult_Status_name, AssignmentResult_Status_value)
 	proto.RegisterType((*FunctionConfig)(nil), "openmatch.FunctionConfig")
 	proto.RegisterType((*FetchMatchesRequest)(nil), "openmatch.FetchMatchesRequest")
 	proto.RegisterType((*FetchMatchesResponse)(nil), "openmatch.FetchMatchesResponse")
 	proto.RegisterType((*ReleaseTicketsRequest)(nil), "openmatch.ReleaseTicketsRequest")
 	proto.RegisterType((*ReleaseTicketsResponse)(nil), "openmatch.ReleaseTicketsResponse")
+	proto.RegisterType((*AssignmentGroup)(nil), "openmatch.AssignmentGroup")
 	proto.RegisterType((*AssignTicketsRequest)(nil), "openmatch.AssignTicketsRequest")
+	proto.RegisterType((*AssignmentResult)(nil), "openmatch.AssignmentResult")
 	proto.RegisterType((*AssignTicketsResponse)(nil), "openmatch.AssignTicketsResponse")
+	proto.RegisterType((*RequeueTicketsRequest)(nil), "openmatch.RequeueTicketsRequest")
+	proto.RegisterType((*RequeueTicketsResponse)(nil), "openmatch.RequeueTicketsResponse")
+	proto.RegisterType((*ReleaseAllTicketsRequest)(nil), "openmatch.ReleaseAllTicketsRequest")
+	proto.RegisterType((*ReleaseAllTicketsResponse)(nil), "openmatch.ReleaseAllTicketsResponse")
+	proto.RegisterType((*ReserveTicketsRequest)(nil), "openmatch.ReserveTicketsRequest")
+	proto.RegisterType((*ReserveTicketsResponse)(nil), "openmatch.ReserveTicketsResponse")
 }
 
 func init() { proto.RegisterFile("api/backend.proto", fileDescriptor_8dab762378f455cd) }
 
 var fileDescriptor_8dab762378f455cd = []byte{
-	// 753 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0xcd, 0x6e, 0x2b, 0x35,
-	0x18, 0xc5, 0x49, 0x6e, 0x4b, 0x7c, 0x21, 0x0a, 0xe6, 0xf6, 0xde, 0x10, 0x01, 0xd7, 0x1d, 0x44,
-	0x89, 0x42, 0x33, 0x4e, 0x86, 0xc2, 0x22, 0x08, 0xd4, 0xb4, 0xb4, 0xa8, 0x52, 0x81, 0x6a, 0x5a,
-	0xb1, 0x60, 0x83, 0x26, 0x9e, 0x2f, 0x33, 0x43, 0x13, 0xdb, 0x8c, 0x3d, 0x2d, 0x15, 0x12, 0x42,
-	0x88, 0x05, 0x62, 0x09, 0xbb, 0x3e, 0x02, 0x3b, 0x9e, 0x85, 0x0d, 0x0f, 0xc0, 0x83, 0xa0, 0xf1,
-	0x24, 0x6d, 0x7e, 0xda, 0x4a, 0x77, 0x15, 0xe7, 0x3b, 0xe7, 0x3b, 0xe7, 0xf8, 0xb3, 0xc7, 0xf8,
-	0xb5, 0x40, 0x25, 0x6c, 0x18, 0xf0, 0x73, 0x10, 0xa1, 0xab, 0x52, 0x69, 0x24, 0xa9, 0x4a, 0x05,
-	0x62, 0x12, 0x18, 0x1e, 0x37, 0x49, 0x8e, 0x4e, 0x40, 0xeb, 0x20, 0x02, 0x5d, 0xc0, 0xcd, 0x37,
-	0x23, 0x29, 0xa3, 0x31, 0xb0, 0x1c, 0x0a, 0x84, 0x90, 0x26, 0x30, 0x89, 0x14, 0x33, 0x74, 0xdb,
-	0xfe, 0xf0, 0x4e, 0x04, 0xa2, 0xa3, 0x2f, 0x83, 0x28, 0x82, 0x94, 0x49, 0x65, 0x19, 0xab, 0x6c,
-	0xe7, 0x37, 0x84, 0x6b, 0x87, 0x99, 0xe0, 0x79, 0x6d, 0x5f, 0x8a, 0x51, 0x12, 0x11, 0x82, 0x2b,
-	0xb1, 0xd4, 0xa6, 0x81, 0x28, 0x6a, 0x55, 0x7d, 0xbb, 0xce, 0x6b, 0x4a, 0xa6, 0xa6, 0x51, 0xa2,
-	0xa8, 0xf5, 0xc8, 0xb7, 0x6b, 0xe2, 0xe1, 0x8a, 0xb9, 0x52, 0xd0, 0x28, 0x53, 0xd4, 0xaa, 0x79,
-	0x6f, 0xbb, 0x37, 0xa1, 0xdd, 0x45, 0x41, 0xf7, 0xec, 0x4a, 0x81, 0x6f, 0xb9, 0x4e, 0x13, 0x57,
-	0xf2, 0x7f, 0xe4, 0x65, 0x5c, 0xf9, 0xdc, 0x3f, 0xd9, 0xaf, 0xbf, 0x94, 0xaf, 0xfc, 0x83, 0xd3,
-	0xb3, 0x3a, 0x72, 0x7e, 0xc4, 0xaf, 0x1f, 0x82, 0xe1, 0xf1, 0x17, 0xb9, 0x06, 0x68, 0x1f, 0xbe,
-	0xcf, 0x40, 0x1b, 0xd2, 0xc3, 0x6b, 0xdc, 0xea, 0xd8, 0x40, 0x8f, 0xbd, 0x37, 0xee, 0x35, 0xf2,
-	0xa7, 0x44, 0xd2, 0xc3, 0xeb, 0x2a, 0x95, 0xa3, 0x64, 0x0c, 0x36, 0xf0, 0x63, 0xef, 0xd9, 0x5c,
-	0x8f, 0x95, 0x3f, 0x29, 0x60, 0x7f, 0xc6, 0x73, 0x3e, 0xc5, 0x4f, 0x16, 0xcd, 0xb5, 0x92, 0x42,
-	0x03, 0xd9, 0xc2, 0x8f, 0x6c, 0xdb, 0xd4, 0xbc, 0xbe, 0x2c, 0xe4, 0x17, 0xb0, 0xf3, 0x11, 0xde,
-	0xf0, 0x61, 0x0c, 0x81, 0x86, 0xb3, 0x84, 0x9f, 0x83, 0xb9, 0x89, 0xff, 0x16, 0xc6, 0xc6, 0x56,
-	0xbe, 0x4d, 0x42, 0xdd, 0x40, 0xb4, 0xdc, 0xaa, 0xfa, 0xd5, 0xa2, 0x72, 0x14, 0x6a, 0xa7, 0x81,
-	0x9f, 0x2e, 0xf7, 0x15, 0xce, 0xce, 0x18, 0x3f, 0x19, 0x68, 0x9d, 0x44, 0xe2, 0x85, 0x04, 0xc9,
-	0x87, 0x18, 0x07, 0xb6, 0x6d, 0x02, 0xc2, 0x4c, 0xb7, 0xbf, 0x31, 0x97, 0x7a, 0x70, 0x03, 0xfa,
-	0x73, 0x44, 0xe7, 0x19, 0xde, 0x58, 0x72, 0x2b, 0x62, 0x78, 0xd7, 0x65, 0x5c, 0xdb, 0x2b, 0x6e,
-	0xe7, 0x29, 0xa4, 0x17, 0x09, 0x07, 0xf2, 0x13, 0x7e, 0x65, 0x7e, 0x56, 0x64, 0xe1, 0xe8, 0x57,
-	0x4f, 0xb0, 0xf9, 0xfc, 0x5e, 0x7c, 0xba, 0xd5, 0xf7, 0x7f, 0xf9, 0xe7, 0xbf, 0x3f, 0x4b, 0xef,
-	0x3a, 0x94, 0x5d, 0xf4, 0x66, 0x9f, 0x82, 0x2e, 0xcc, 0xd8, 0xa4, 0xe0, 0xf6, 0x47, 0x79, 0x63,
-	0x1f, 0xb5, 0xbb, 0x88, 0xfc, 0x8c, 0xf0, 0xab, 0x0b, 0x61, 0xc9, 0xf3, 0x95, 0x0d, 0x2e, 0x0e,
-	0xad, 0x49, 0xef, 0x27, 0x4c, 0x33, 0x6c, 0xdb, 0x0c, 0x5b, 0xce, 0xe6, 0x1d, 0x19, 0x8a, 0xe9,
-	0xea, 0x7e, 0x31, 0xaf, 0x3e, 0x6a, 0x93, 0x5f, 0x11, 0xae, 0x2d, 0x9e, 0x1b, 0x99, 0xb7, 0xb8,
-	0xf3, 0x2a, 0x34, 0x37, 0x1f, 0x60, 0x4c, 0x53, 0x74, 0x6c, 0x8a, 0xf7, 0x1c, 0xe7, 0x81, 0x14,
-	0x69, 0xd1, 0xda, 0x47, 0xed, 0xbd, 0xdf, 0xcb, 0x7f, 0x0c, 0xfe, 0x2d, 0x91, 0xbf, 0x11, 0x5e,
-	0x9f, 0x9e, 0x91, 0x73, 0x84, 0xf1, 0x57, 0x0a, 0x04, 0xb5, 0x33, 0x26, 0x4f, 0x63, 0x63, 0x94,
-	0xee, 0x33, 0x96, 0x3b, 0x77, 0x0a, 0xeb, 0x10, 0x2e, 0x9a, 0xef, 0xdc, 0xfe, 0xef, 0x84, 0x89,
-	0xe6, 0x99, 0xd6, 0xbb, 0xc5, 0xab, 0x12, 0xa5, 0x32, 0x53, 0xda, 0xe5, 0x72, 0xd2, 0xfe, 0x1a,
-	0x93, 0x81, 0x0a, 0x78, 0x0c, 0xd4, 0x73, 0xbb, 0xf4, 0x38, 0xe1, 0x90, 0x7f, 0x11, 0xbb, 0x33,
-	0xc9, 0x28, 0x31, 0x71, 0x36, 0xcc, 0x99, 0xac, 0x68, 0x1d, 0xc9, 0x34, 0x0a, 0x26, 0xa0, 0xe7,
-	0xcc, 0xd8, 0x70, 0x2c, 0x87, 0x6c, 0x12, 0x68, 0x03, 0x29, 0x3b, 0x3e, 0xda, 0x3f, 0xf8, 0xf2,
-	0xf4, 0xc0, 0x2b, 0xf7, 0xdc, 0x6e, 0xbb, 0x84, 0x4a, 0x5e, 0x3d, 0x50, 0x6a, 0x9c, 0x70, 0xfb,
-	0x20, 0xb1, 0xef, 0xb4, 0x14, 0xfd, 0x95, 0x8a, 0xff, 0x31, 0x2e, 0xef, 0x74, 0x77, 0xc8, 0x0e,
-	0x6e, 0xfb, 0x60, 0xb2, 0x54, 0x40, 0x48, 0x2f, 0x63, 0x10, 0xd4, 0xc4, 0x40, 0x53, 0xd0, 0x32,
-	0x4b, 0x39, 0xd0, 0x50, 0x82, 0xa6, 0x42, 0x1a, 0x0a, 0x3f, 0x24, 0xda, 0xb8, 0x64, 0x0d, 0x57,
-	0xae, 0x4b, 0x68, 0x3d, 0xfd, 0x04, 0x37, 0x6e, 0x87, 0x41, 0x3f, 0x93, 0x3c, 0xcb, 0x2f, 0xbb,
-	0x55, 0x27, 0x9b, 0x77, 0x8f, 0x86, 0xe9, 0xc4, 0x00, 0x0b, 0x25, 0xd7, 0xec, 0x1b, 0xba, 0x04,
-	0xcd, 0xed, 0x4b, 0x9d, 0x47, 0x4c, 0x0d, 0xff, 0x2a, 0x55, 0x73, 0x7d, 0x2b, 0x3f, 0x5c, 0xb3,
-	0x2f, 0xea, 0x07, 0xff, 0x07, 0x00, 0x00, 0xff, 0xff, 0x81, 0xb8, 0x32, 0x4c, 0xd1, 0x05, 0x00,
-	0x00,
+	// 1239 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0x4f, 0x6f, 0x1b, 0x45,
+	0x14, 0xef, 0xda, 0x6e, 0x12, 0xbf, 0xb4, 0xa9, 0x33, 0x4d, 0x52, 0x77, 0x0b, 0xed, 0x66, 0x4b,
+	0x4b, 0x64, 0x1a, 0x6f, 0xe2, 0x96, 0x4a, 0xb8, 0x80, 0xea, 0xe6, 0x4f, 0x15, 0xa9, 0x24, 0xd1,
+	0xc6, 0x20, 0xc1, 0xc5, 0x5a, 0xaf, 0xc7, 0xeb, 0xa5, 0xbb, 0x3b, 0xdb, 0x9d, 0xd9, 0xb4, 0x15,
+	0x12, 0x20, 0xc4, 0x09, 0x71, 0x40, 0x70, 0xe3, 0xcc, 0x01, 0xf5, 0x86, 0xc4, 0x8d, 0x8f, 0xc1,
+	0x85, 0x0f, 0xc0, 0x07, 0x41, 0x3b, 0x33, 0xeb, 0xac, 0xd7, 0x8e, 0x51, 0x72, 0xf2, 0xce, 0xfc,
+	0x7e, 0xef, 0xbd, 0xdf, 0xbc, 0x79, 0x6f, 0x9e, 0x61, 0xd1, 0x0a, 0x5d, 0xa3, 0x6b, 0xd9, 0xcf,
+	0x71, 0xd0, 0xab, 0x87, 0x11, 0x61, 0x04, 0x95, 0x49, 0x88, 0x03, 0xdf, 0x62, 0xf6, 0x40, 0x45,
+	0x09, 0xea, 0x63, 0x4a, 0x2d, 0x07, 0x53, 0x01, 0xab, 0x6f, 0x39, 0x84, 0x38, 0x1e, 0x36, 0x12,
+	0xc8, 0x0a, 0x02, 0xc2, 0x2c, 0xe6, 0x92, 0x20, 0x45, 0x6f, 0x4a, 0x94, 0xaf, 0xba, 0x71, 0xdf,
+	0xe8, 0xc5, 0x11, 0x27, 0x48, 0xfc, 0x56, 0x1e, 0x67, 0xae, 0x8f, 0x29, 0xb3, 0xfc, 0x50, 0x12,
+	0xee, 0xf1, 0x1f, 0x7b, 0xdd, 0xc1, 0xc1, 0x3a, 0x7d, 0x69, 0x39, 0x0e, 0x8e, 0x0c, 0x12, 0xf2,
+	0x10, 0xe3, 0xe1, 0xf4, 0x3f, 0x15, 0x58, 0xd8, 0x8d, 0x03, 0x3b, 0xd9, 0xdb, 0x22, 0x41, 0xdf,
+	0x75, 0x10, 0x82, 0xd2, 0x80, 0x50, 0x56, 0x55, 0x34, 0x65, 0xad, 0x6c, 0xf2, 0xef, 0x64, 0x2f,
+	0x24, 0x11, 0xab, 0x16, 0x34, 0x65, 0xed, 0xa2, 0xc9, 0xbf, 0x51, 0x03, 0x4a, 0xec, 0x75, 0x88,
+	0xab, 0x45, 0x4d, 0x59, 0x5b, 0x68, 0xdc, 0xac, 0x0f, 0x4f, 0x5d, 0x1f, 0x75, 0x58, 0x6f, 0xbf,
+	0x0e, 0xb1, 0xc9, 0xb9, 0xa8, 0x06, 0x8b, 0xa1, 0x45, 0x69, 0x27, 0x24, 0xc4, 0xeb, 0x30, 0xd7,
+	0x7e, 0x8e, 0x19, 0xad, 0x96, 0x34, 0x65, 0x6d, 0xce, 0xbc, 0x92, 0x00, 0x87, 0x84, 0x78, 0x6d,
+	0xb1, 0xad, 0xab, 0x50, 0x4a, 0x2c, 0xd1, 0x1c, 0x94, 0x9e, 0x9a, 0x87, 0x5b, 0x95, 0x0b, 0xc9,
+	0x97, 0xb9, 0x73, 0xd4, 0xae, 0x28, 0xfa, 0x6f, 0x05, 0xb8, 0xba, 0x8b, 0x99, 0x3d, 0xf8, 0x24,
+	0x09, 0x88, 0xa9, 0x89, 0x5f, 0xc4, 0x98, 0x32, 0xb4, 0x09, 0x33, 0x36, 0x0f, 0xca, 0xd5, 0xcf,
+	0x37, 0xae, 0x9f, 0xaa, 0xca, 0x94, 0x44, 0xb4, 0x09, 0xb3, 0x61, 0x44, 0xfa, 0xae, 0x87, 0xf9,
+	0xe9, 0xe6, 0x1b, 0xd7, 0x32, 0x36, 0xdc, 0xfd, 0xa1, 0x80, 0xcd, 0x94, 0x87, 0xee, 0xc3, 0x9c,
+	0xfc, 0xa4, 0xd5, 0xa2, 0x56, 0x9c, 0x66, 0x33, 0x24, 0xa2, 0x26, 0xcc, 0xfb, 0x7e, 0xbf, 0x93,
+	0x5c, 0x17, 0x89, 0x59, 0xb5, 0x24, 0xf5, 0x89, 0xeb, 0xac, 0xa7, 0xd7, 0x59, 0xdf, 0x96, 0xd7,
+	0x6d, 0x82, 0xef, 0xf7, 0xdb, 0x82, 0x8c, 0x1e, 0xc2, 0x35, 0x37, 0xb0, 0xbd, 0xb8, 0x87, 0x3b,
+	0xa1, 0x15, 0x31, 0xd7, 0xf2, 0x3a, 0x11, 0xa6, 0xb1, 0xc7, 0x68, 0xf5, 0x22, 0x4f, 0xde, 0xb2,
+	0x84, 0x0f, 0x05, 0x6a, 0x0a, 0x50, 0xff, 0x18, 0x96, 0x46, 0xb3, 0x44, 0x43, 0x12, 0x50, 0x8c,
+	0xee, 0xc2, 0x45, 0xae, 0x55, 0x66, 0xa9, 0x92, 0x57, 0x6f, 0x0a, 0x58, 0x7f, 0x08, 0xcb, 0x26,
+	0xf6, 0xb0, 0x45, 0xb1, 0xbc, 0x94, 0x34, 0xcf, 0x6f, 0x03, 0x88, 0xdb, 0xeb, 0xb8, 0x3d, 0x5a,
+	0x55, 0xb4, 0xe2, 0x5a, 0xd9, 0x2c, 0x8b, 0x9d, 0xbd, 0x1e, 0xd5, 0xab, 0xb0, 0x92, 0xb7, 0x13,
+	0x91, 0x75, 0x07, 0xae, 0xb4, 0x28, 0x75, 0x9d, 0xc0, 0xc7, 0x01, 0x7b, 0x1a, 0x91, 0x38, 0xfc,
+	0x1f, 0x5f, 0xe8, 0x7d, 0x00, 0x6b, 0x68, 0x21, 0xaf, 0x68, 0x39, 0x23, 0xf8, 0xc4, 0x9d, 0x99,
+	0x21, 0xea, 0x6f, 0x14, 0x58, 0x12, 0xd0, 0x99, 0xa4, 0x9f, 0x33, 0x1c, 0xfa, 0x10, 0xe6, 0x4f,
+	0x56, 0x69, 0x55, 0xa8, 0x13, 0xed, 0xf8, 0xa9, 0xcd, 0x2c, 0x5d, 0xff, 0x5d, 0x81, 0x4a, 0xc6,
+	0x31, 0xbf, 0x3d, 0x74, 0x03, 0xca, 0x43, 0xa1, 0xb2, 0x19, 0xe7, 0x52, 0x9d, 0xa8, 0x09, 0x33,
+	0x94, 0x59, 0x2c, 0xa6, 0x5c, 0xe2, 0x42, 0x43, 0x9f, 0x2c, 0x91, 0x7b, 0xaa, 0x1f, 0x71, 0xa6,
+	0x29, 0x2d, 0xf4, 0x47, 0x30, 0x23, 0x76, 0xd0, 0x25, 0x98, 0x6b, 0x1d, 0x1d, 0xed, 0x3d, 0xdd,
+	0xdf, 0xd9, 0xae, 0x5c, 0x40, 0x97, 0xa1, 0xbc, 0x7f, 0xd0, 0xee, 0xec, 0x1e, 0x7c, 0xba, 0xbf,
+	0x5d, 0x51, 0xd0, 0x12, 0x54, 0x5a, 0xcf, 0xcc, 0x9d, 0xd6, 0xf6, 0xe7, 0x9d, 0x21, 0xa9, 0xa0,
+	0x7f, 0x03, 0xcb, 0xb9, 0xb4, 0xca, 0x9a, 0x32, 0x60, 0x29, 0x20, 0xac, 0xd3, 0x27, 0x71, 0xd0,
+	0xeb, 0x8c, 0x65, 0x78, 0x31, 0x20, 0x6c, 0x37, 0x81, 0xda, 0x99, 0x4c, 0xcf, 0xa6, 0x45, 0x5c,
+	0xe0, 0xe9, 0xba, 0x31, 0xe5, 0x0c, 0x66, 0xca, 0x15, 0x35, 0xf9, 0x22, 0xc6, 0xf1, 0x19, 0x6b,
+	0xf2, 0x2b, 0x58, 0xc9, 0xdb, 0x9d, 0x57, 0xf9, 0x06, 0x2c, 0xe1, 0x57, 0x03, 0x2b, 0xa6, 0x0c,
+	0x8f, 0x18, 0x14, 0xb8, 0x01, 0x1a, 0x62, 0x43, 0x0b, 0x5d, 0x85, 0xaa, 0x6c, 0x88, 0x96, 0xe7,
+	0x8d, 0xea, 0xd6, 0x0f, 0xe0, 0xfa, 0x04, 0x4c, 0x6a, 0x6b, 0xc0, 0x72, 0x24, 0xc0, 0x61, 0x24,
+	0x9b, 0xc4, 0x81, 0x78, 0x9d, 0x8b, 0xe6, 0xd5, 0x14, 0x14, 0x76, 0x5b, 0x09, 0xa4, 0xff, 0xa8,
+	0x24, 0x29, 0xa2, 0x38, 0x3a, 0xce, 0xa7, 0xe8, 0x3a, 0xcc, 0xe1, 0x63, 0x1c, 0x64, 0x2a, 0x6a,
+	0x96, 0xaf, 0xf7, 0x7a, 0xe8, 0x03, 0x00, 0xca, 0xac, 0x88, 0xf1, 0x07, 0x4a, 0xd6, 0xbd, 0x3a,
+	0xf6, 0x3a, 0xb5, 0xd3, 0x61, 0x63, 0x96, 0x39, 0x3b, 0x59, 0xe7, 0x12, 0x5f, 0xcc, 0x27, 0x7e,
+	0x0f, 0x56, 0xf2, 0x6a, 0xce, 0x99, 0xf8, 0xc6, 0x5f, 0x33, 0xb0, 0xf0, 0x44, 0xcc, 0xda, 0x23,
+	0x1c, 0x1d, 0xbb, 0x36, 0x46, 0x5f, 0xc3, 0xa5, 0xec, 0x13, 0x87, 0x46, 0xe6, 0xd0, 0xf8, 0x84,
+	0x50, 0x6f, 0x9d, 0x8a, 0xcb, 0x17, 0xea, 0xbd, 0xef, 0xfe, 0xfe, 0xf7, 0x97, 0xc2, 0x1d, 0x5d,
+	0x33, 0x8e, 0x37, 0xd3, 0xc1, 0x4e, 0x45, 0x30, 0xc3, 0x17, 0xdc, 0x66, 0x3f, 0x31, 0x6c, 0x2a,
+	0xb5, 0x0d, 0x05, 0x7d, 0xab, 0xc0, 0xe5, 0x91, 0x86, 0x40, 0xb7, 0xc6, 0xca, 0x78, 0xf4, 0x16,
+	0x54, 0xed, 0x74, 0x82, 0xd4, 0x70, 0x8f, 0x6b, 0xb8, 0xab, 0xaf, 0x4e, 0xd0, 0x20, 0xa7, 0x66,
+	0x53, 0xbc, 0x1f, 0x4d, 0xa5, 0x86, 0x7e, 0x52, 0x60, 0x71, 0xac, 0x82, 0xd0, 0xed, 0x4c, 0x94,
+	0xd3, 0x6a, 0x4f, 0x7d, 0x67, 0x3a, 0x49, 0xca, 0xd9, 0xe0, 0x72, 0x6a, 0xfa, 0x9d, 0x29, 0x72,
+	0x64, 0x21, 0x5a, 0x9e, 0x97, 0x48, 0xfa, 0x5e, 0x81, 0x85, 0xd1, 0x6e, 0x43, 0xda, 0x48, 0xa8,
+	0x09, 0x0d, 0xac, 0xae, 0x4e, 0x61, 0x48, 0x25, 0xeb, 0x5c, 0xc9, 0xbb, 0xba, 0x3e, 0x55, 0x09,
+	0x37, 0x3d, 0x91, 0x91, 0x1d, 0x44, 0x39, 0x19, 0x13, 0x66, 0x9b, 0xba, 0x3a, 0x85, 0x71, 0x26,
+	0x19, 0xdc, 0xf4, 0x44, 0x46, 0xb6, 0x05, 0x72, 0x32, 0x26, 0xf4, 0xaa, 0xba, 0x3a, 0x85, 0x71,
+	0x26, 0x19, 0xdc, 0xb4, 0xa9, 0xd4, 0x9e, 0xfc, 0x50, 0xfc, 0xb9, 0xf5, 0x4f, 0x01, 0xfd, 0xa1,
+	0xc0, 0xac, 0x6c, 0x22, 0x7d, 0x0f, 0xe0, 0x20, 0xc4, 0x81, 0xc6, 0x9b, 0x00, 0xad, 0x0c, 0x18,
+	0x0b, 0x69, 0xd3, 0x30, 0x92, 0xc8, 0xeb, 0x22, 0x74, 0x0f, 0x1f, 0xab, 0xb7, 0x4f, 0xd6, 0xeb,
+	0x3d, 0x97, 0xda, 0x31, 0xa5, 0x8f, 0xc5, 0xcb, 0xe0, 0x24, 0xb3, 0x8c, 0xd6, 0x6d, 0xe2, 0xd7,
+	0x3e, 0x03, 0xd4, 0x0a, 0x2d, 0x7b, 0x80, 0xb5, 0x46, 0x7d, 0x43, 0x7b, 0xe6, 0xda, 0x38, 0x69,
+	0xf1, 0xc7, 0xa9, 0x4b, 0xc7, 0x65, 0x83, 0xb8, 0x9b, 0x30, 0x0d, 0x61, 0xda, 0x27, 0x91, 0x63,
+	0xf9, 0x98, 0x66, 0x82, 0x19, 0x5d, 0x8f, 0x74, 0x0d, 0xdf, 0xa2, 0x0c, 0x47, 0xc6, 0xb3, 0xbd,
+	0xad, 0x9d, 0xfd, 0xa3, 0x9d, 0x46, 0x71, 0xb3, 0xbe, 0x51, 0x2b, 0x28, 0x85, 0x46, 0xc5, 0x0a,
+	0x43, 0xcf, 0xb5, 0xf9, 0xbf, 0x23, 0xe3, 0x4b, 0x4a, 0x82, 0xe6, 0xd8, 0x8e, 0xf9, 0x08, 0x8a,
+	0x0f, 0x36, 0x1e, 0xa0, 0x07, 0x50, 0x33, 0x31, 0x8b, 0xa3, 0x00, 0xf7, 0xb4, 0x97, 0x03, 0x1c,
+	0x68, 0x6c, 0x80, 0xb5, 0x08, 0x53, 0x12, 0x47, 0x36, 0xd6, 0x7a, 0x04, 0x53, 0x2d, 0x20, 0x4c,
+	0xc3, 0xaf, 0x5c, 0xca, 0xea, 0x68, 0x06, 0x4a, 0xbf, 0x16, 0x94, 0xd9, 0xe8, 0x23, 0xa8, 0x9e,
+	0x24, 0x43, 0xdb, 0x26, 0x76, 0x9c, 0xcc, 0x1f, 0xee, 0x1d, 0xad, 0x4e, 0x4e, 0x8d, 0x41, 0x5d,
+	0x86, 0x8d, 0x1e, 0xb1, 0xa9, 0xf1, 0x85, 0x96, 0x83, 0x32, 0xe7, 0x0a, 0x9f, 0x3b, 0x46, 0xd8,
+	0x7d, 0x53, 0x28, 0x27, 0xfe, 0xb9, 0xfb, 0xee, 0x0c, 0x7f, 0x53, 0xef, 0xff, 0x37, 0x00, 0x55,
+	0x88, 0xdc, 0x08, 0x40, 0x0c, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -429,9 +939,24 @@ type BackendServiceClient interface {
 	// FetchMatches triggers a MatchFunction with the specified MatchProfile and returns a set of match proposals that
 	// match the description of that MatchProfile.
 	// FetchMatches immediately returns an error if it encounters any execution failures.
+	// Matches are streamed back as they are evaluated, with at most backend.maxInFlightMatches held for a
+	// caller which doesn't keep up.
 	FetchMatches(ctx context.Context, in *FetchMatchesRequest, opts ...grpc.CallOption) (BackendService_FetchMatchesClient, error)
 	// AssignTickets overwrites the Assignment field of the input TicketIds.
+	//   - TicketIds which don't exist are skipped and listed in the response, rather than failing the whole call.
+	//   - The response has the outcome for each TicketId, including whether it was already assigned.
+	//   - Groups of TicketIds with different Assignments are assigned in one call with assignments.
 	AssignTickets(ctx context.Context, in *AssignTicketsRequest, opts ...grpc.CallOption) (*AssignTicketsResponse, error)
+	// ReleaseAllTickets removes every ticket from the list that prevents tickets that are awaiting
+	// assignment from appearing in MMF queries, eg. to recover after a director crashed without
+	// releasing the tickets of the matches it discarded.
+	//   - Fails with FailedPrecondition unless backend.releaseAllTickets.enabled is set.
+	ReleaseAllTickets(ctx context.Context, in *ReleaseAllTicketsRequest, opts ...grpc.CallOption) (*ReleaseAllTicketsResponse, error)
+	// RequeueTickets clears the Assignment of the input TicketIds and puts them back into the matchmaking
+	// pool, incrementing their assignment_failures.
+	//   - TicketIds which aren't assigned are skipped, so that failures can be reported again safely.
+	//   - TicketIds whose Assignment failed too many times keep it and are listed in the response.
+	RequeueTickets(ctx context.Context, in *RequeueTicketsRequest, opts ...grpc.CallOption) (*RequeueTicketsResponse, error)
 	// ReleaseTickets removes the submitted tickets from the list that prevents tickets
 	// that are awaiting assignment from appearing in MMF queries, effectively putting them back into
 	// the matchmaking pool
@@ -439,6 +964,12 @@ type BackendServiceClient interface {
 	// BETA FEATURE WARNING:  This call and the associated Request and Response
 	// messages are not finalized and still subject to possible change or removal.
 	ReleaseTickets(ctx context.Context, in *ReleaseTicketsRequest, opts ...grpc.CallOption) (*ReleaseTicketsResponse, error)
+	// ReserveTickets reserves Tickets for a scheduled event.
+	//   - Until the start_time, the Tickets are excluded from every Pool queried.
+	//   - From the start_time, the Tickets are only included in Pools with a TagPresentFilter on the event's tag,
+	//     "openmatch.event:" followed by the event_id, which the event's dedicated MatchProfile should use.
+	//   - Reserving a Ticket again replaces its previous reservation.
+	ReserveTickets(ctx context.Context, in *ReserveTicketsRequest, opts ...grpc.CallOption) (*ReserveTicketsResponse, error)
 }
 
 type backendServiceClient struct {
@@ -490,6 +1021,24 @@ func (c *backendServiceClient) AssignTickets(ctx context.Context, in *AssignTick
 	return out, nil
 }
 
+func (c *backendServiceClient) ReleaseAllTickets(ctx context.Context, in *ReleaseAllTicketsRequest, opts ...grpc.CallOption) (*ReleaseAllTicketsResponse, error) {
+	out := new(ReleaseAllTicketsResponse)
+	err := c.cc.Invoke(ctx, "/openmatch.BackendService/ReleaseAllTickets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *backendServiceClient) RequeueTickets(ctx context.Context, in *RequeueTicketsRequest, opts ...grpc.CallOption) (*RequeueTicketsResponse, error) {
+	out := new(RequeueTicketsResponse)
+	err := c.cc.Invoke(ctx, "/openmatch.BackendService/RequeueTickets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *backendServiceClient) ReleaseTickets(ctx context.Context, in *ReleaseTicketsRequest, opts ...grpc.CallOption) (*ReleaseTicketsResponse, error) {
 	out := new(ReleaseTicketsResponse)
 	err := c.cc.Invoke(ctx, "/openmatch.BackendService/ReleaseTickets", in, out, opts...)
@@ -499,14 +1048,38 @@ func (c *backendServiceClient) ReleaseTickets(ctx context.Context, in *ReleaseTi
 	return out, nil
 }
 
+func (c *backendServiceClient) ReserveTickets(ctx context.Context, in *ReserveTicketsRequest, opts ...grpc.CallOption) (*ReserveTicketsResponse, error) {
+	out := new(ReserveTicketsResponse)
+	err := c.cc.Invoke(ctx, "/openmatch.BackendService/ReserveTickets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BackendServiceServer is the server API for BackendService service.
 type BackendServiceServer interface {
 	// FetchMatches triggers a MatchFunction with the specified MatchProfile and returns a set of match proposals that
 	// match the description of that MatchProfile.
 	// FetchMatches immediately returns an error if it encounters any execution failures.
+	// Matches are streamed back as they are evaluated, with at most backend.maxInFlightMatches held for a
+	// caller which doesn't keep up.
 	FetchMatches(*FetchMatchesRequest, BackendService_FetchMatchesServer) error
 	// AssignTickets overwrites the Assignment field of the input TicketIds.
+	//   - TicketIds which don't exist are skipped and listed in the response, rather than failing the whole call.
+	//   - The response has the outcome for each TicketId, including whether it was already assigned.
+	//   - Groups of TicketIds with different Assignments are assigned in one call with assignments.
 	AssignTickets(context.Context, *AssignTicketsRequest) (*AssignTicketsResponse, error)
+	// ReleaseAllTickets removes every ticket from the list that prevents tickets that are awaiting
+	// assignment from appearing in MMF queries, eg. to recover after a director crashed without
+	// releasing the tickets of the matches it discarded.
+	//   - Fails with FailedPrecondition unless backend.releaseAllTickets.enabled is set.
+	ReleaseAllTickets(context.Context, *ReleaseAllTicketsRequest) (*ReleaseAllTicketsResponse, error)
+	// RequeueTickets clears the Assignment of the input TicketIds and puts them back into the matchmaking
+	// pool, incrementing their assignment_failures.
+	//   - TicketIds which aren't assigned are skipped, so that failures can be reported again safely.
+	//   - TicketIds whose Assignment failed too many times keep it and are listed in the response.
+	RequeueTickets(context.Context, *RequeueTicketsRequest) (*RequeueTicketsResponse, error)
 	// ReleaseTickets removes the submitted tickets from the list that prevents tickets
 	// that are awaiting assignment from appearing in MMF queries, effectively putting them back into
 	// the matchmaking pool
@@ -514,6 +1087,12 @@ type BackendServiceServer interface {
 	// BETA FEATURE WARNING:  This call and the associated Request and Response
 	// messages are not finalized and still subject to possible change or removal.
 	ReleaseTickets(context.Context, *ReleaseTicketsRequest) (*ReleaseTicketsResponse, error)
+	// ReserveTickets reserves Tickets for a scheduled event.
+	//   - Until the start_time, the Tickets are excluded from every Pool queried.
+	//   - From the start_time, the Tickets are only included in Pools with a TagPresentFilter on the event's tag,
+	//     "openmatch.event:" followed by the event_id, which the event's dedicated MatchProfile should use.
+	//   - Reserving a Ticket again replaces its previous reservation.
+	ReserveTickets(context.Context, *ReserveTicketsRequest) (*ReserveTicketsResponse, error)
 }
 
 // UnimplementedBackendServiceServer can be embedded to have forward compatible implementations.
@@ -526,9 +1105,18 @@ func (*UnimplementedBackendServiceServer) FetchMatches(req *FetchMatchesRequest,
 func (*UnimplementedBackendServiceServer) AssignTickets(ctx context.Context, req *AssignTicketsRequest) (*AssignTicketsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AssignTickets not implemented")
 }
+func (*UnimplementedBackendServiceServer) ReleaseAllTickets(ctx context.Context, req *ReleaseAllTicketsRequest) (*ReleaseAllTicketsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseAllTickets not implemented")
+}
+func (*UnimplementedBackendServiceServer) RequeueTickets(ctx context.Context, req *RequeueTicketsRequest) (*RequeueTicketsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequeueTickets not implemented")
+}
 func (*UnimplementedBackendServiceServer) ReleaseTickets(ctx context.Context, req *ReleaseTicketsRequest) (*ReleaseTicketsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ReleaseTickets not implemented")
 }
+func (*UnimplementedBackendServiceServer) ReserveTickets(ctx context.Context, req *ReserveTicketsRequest) (*ReserveTicketsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReserveTickets not implemented")
+}
 
 func RegisterBackendServiceServer(s *grpc.Server, srv BackendServiceServer) {
 	s.RegisterService(&_BackendService_serviceDesc, srv)
@@ -573,6 +1161,42 @@ func _BackendService_AssignTickets_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BackendService_ReleaseAllTickets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseAllTicketsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BackendServiceServer).ReleaseAllTickets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/openmatch.BackendService/ReleaseAllTickets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BackendServiceServer).ReleaseAllTickets(ctx, req.(*ReleaseAllTicketsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BackendService_RequeueTickets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequeueTicketsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BackendServiceServer).RequeueTickets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/openmatch.BackendService/RequeueTickets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BackendServiceServer).RequeueTickets(ctx, req.(*RequeueTicketsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _BackendService_ReleaseTickets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ReleaseTicketsRequest)
 	if err := dec(in); err != nil {
@@ -591,6 +1215,24 @@ func _BackendService_ReleaseTickets_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BackendService_ReserveTickets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReserveTicketsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BackendServiceServer).ReserveTickets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/openmatch.BackendService/ReserveTickets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BackendServiceServer).ReserveTickets(ctx, req.(*ReserveTicketsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _BackendService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "openmatch.BackendService",
 	HandlerType: (*BackendServiceServer)(nil),
@@ -599,10 +1241,22 @@ var _BackendService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "AssignTickets",
 			Handler:    _BackendService_AssignTickets_Handler,
 		},
+		{
+			MethodName: "ReleaseAllTickets",
+			Handler:    _BackendService_ReleaseAllTickets_Handler,
+		},
+		{
+			MethodName: "RequeueTickets",
+			Handler:    _BackendService_RequeueTickets_Handler,
+		},
 		{
 			MethodName: "ReleaseTickets",
 			Handler:    _BackendService_ReleaseTickets_Handler,
 		},
+		{
+			MethodName: "ReserveTickets",
+			Handler:    _BackendService_ReserveTickets_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
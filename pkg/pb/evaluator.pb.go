@@ -28,7 +28,11 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type EvaluateRequest struct {
 	// A Matches proposed by the Match Function representing a candidate of the final results.
-	Match                *Match   `protobuf:"bytes,1,opt,name=match,proto3" json:"match,omitempty"`
+	Match *Match `protobuf:"bytes,1,opt,name=match,proto3" json:"match,omitempty"`
+	// Matches proposed by the Match Function, sent in chunks instead of one per
+	// request to reduce the number of stream messages.  Evaluators receiving
+	// chunks reply with chunks of match_ids.
+	Matches              []*Match `protobuf:"bytes,2,rep,name=matches,proto3" json:"matches,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -66,9 +70,19 @@ func (m *EvaluateRequest) GetMatch() *Match {
 	return nil
 }
 
+func (m *EvaluateRequest) GetMatches() []*Match {
+	if m != nil {
+		return m.Matches
+	}
+	return nil
+}
+
 type EvaluateResponse struct {
 	// A Match ID representing a shortlisted match returned by the evaluator as the final result.
-	MatchId              string   `protobuf:"bytes,2,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	MatchId string `protobuf:"bytes,2,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	// Match IDs of shortlisted matches, sent in chunks in reply to chunked
+	// requests.
+	MatchIds             []string `protobuf:"bytes,3,rep,name=match_ids,json=matchIds,proto3" json:"match_ids,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -106,6 +120,13 @@ func (m *EvaluateResponse) GetMatchId() string {
 	return ""
 }
 
+func (m *EvaluateResponse) GetMatchIds() []string {
+	if m != nil {
+		return m.MatchIds
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*EvaluateRequest)(nil), "openmatch.EvaluateRequest")
 	proto.RegisterType((*EvaluateResponse)(nil), "openmatch.EvaluateResponse")
@@ -114,38 +135,40 @@ func init() {
 func init() { proto.RegisterFile("api/evaluator.proto", fileDescriptor_8c58cb7dff9acb0f) }
 
 var fileDescriptor_8c58cb7dff9acb0f = []byte{
-	// 492 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0x31, 0x8f, 0xd3, 0x30,
-	0x14, 0xc7, 0x95, 0xf4, 0xb8, 0x6b, 0xcd, 0x40, 0x65, 0x24, 0x54, 0x0a, 0x42, 0xa6, 0x27, 0xa1,
-	0x52, 0xd1, 0xb8, 0xd7, 0xeb, 0x42, 0x11, 0xd2, 0x1d, 0xd0, 0xa1, 0xe8, 0x00, 0xa9, 0x48, 0x0c,
-	0x2c, 0xc8, 0x49, 0x1e, 0x49, 0xa0, 0xf1, 0x33, 0x7e, 0x4e, 0x8f, 0x0d, 0x89, 0x99, 0x09, 0x36,
-	0x3e, 0x02, 0x2b, 0x1f, 0x85, 0x8d, 0x99, 0x0f, 0x82, 0x92, 0x50, 0x5a, 0xdd, 0xdd, 0x92, 0xc8,
-	0xfe, 0x3d, 0xff, 0xff, 0xef, 0xfd, 0x6d, 0x76, 0x55, 0x99, 0x4c, 0xc2, 0x4a, 0x2d, 0x0b, 0xe5,
-	0xd0, 0x06, 0xc6, 0xa2, 0x43, 0xde, 0x42, 0x03, 0x3a, 0x57, 0x2e, 0x4a, 0xbb, 0xbc, 0xe4, 0x39,
-	0x10, 0xa9, 0x04, 0xa8, 0xc6, 0xdd, 0x9b, 0x09, 0x62, 0xb2, 0x04, 0x59, 0x22, 0xa5, 0x35, 0x3a,
-	0xe5, 0x32, 0xd4, 0x6b, 0x7a, 0xaf, 0xfa, 0x45, 0xc3, 0x04, 0xf4, 0x90, 0x4e, 0x55, 0x92, 0x80,
-	0x95, 0x68, 0xaa, 0x8a, 0xf3, 0xd5, 0xbd, 0xfb, 0xec, 0xca, 0xac, 0x76, 0x87, 0x05, 0x7c, 0x28,
-	0x80, 0x1c, 0xbf, 0xc3, 0x2e, 0x55, 0xde, 0x1d, 0x4f, 0x78, 0xfd, 0xcb, 0xe3, 0x76, 0xf0, 0xbf,
-	0x9b, 0xe0, 0x59, 0xf9, 0x5d, 0xd4, 0xb8, 0x77, 0xc8, 0xda, 0x9b, 0xa3, 0x64, 0x50, 0x13, 0xf0,
-	0xeb, 0xac, 0x59, 0xc1, 0x37, 0x59, 0xdc, 0xf1, 0x85, 0xd7, 0x6f, 0x2d, 0xf6, 0xaa, 0xf5, 0x3c,
-	0x7e, 0xba, 0xd3, 0xf4, 0xda, 0xfe, 0xf8, 0x13, 0x6b, 0xcd, 0xd6, 0xd3, 0x72, 0xcb, 0x9a, 0x6b,
-	0x05, 0xde, 0xdd, 0xb2, 0x39, 0xd3, 0x51, 0xf7, 0xc6, 0x85, 0xac, 0xb6, 0xec, 0xdd, 0xfd, 0xfc,
-	0xeb, 0xcf, 0x37, 0x7f, 0xbf, 0x77, 0x4b, 0xae, 0x0e, 0x36, 0x49, 0xca, 0xaa, 0x1a, 0x68, 0xfa,
-	0x6f, 0x07, 0xa6, 0xde, 0xa0, 0xef, 0x8d, 0xbc, 0x47, 0x5f, 0x1a, 0x5f, 0x8f, 0x7f, 0xfb, 0xfc,
-	0xa7, 0xb7, 0xd5, 0x48, 0x6f, 0xce, 0xd8, 0x0b, 0x03, 0x5a, 0x54, 0xf3, 0xf1, 0x6b, 0xa9, 0x73,
-	0x86, 0xa6, 0x52, 0x96, 0xae, 0xc3, 0xda, 0x36, 0x86, 0x55, 0x77, 0x7f, 0xb3, 0x1e, 0xc6, 0x19,
-	0x45, 0x05, 0xd1, 0x51, 0x7d, 0x15, 0x89, 0xc5, 0xc2, 0x50, 0x10, 0x61, 0x3e, 0x78, 0xc5, 0xf8,
-	0xb1, 0x51, 0x51, 0x0a, 0x62, 0x1c, 0x8c, 0xc4, 0x49, 0x16, 0x41, 0x99, 0xcb, 0xd1, 0x5a, 0x32,
-	0xc9, 0x5c, 0x5a, 0x84, 0x65, 0xa5, 0xac, 0x8f, 0xbe, 0x45, 0x9b, 0xa8, 0x1c, 0x68, 0xcb, 0x4c,
-	0x86, 0x4b, 0x0c, 0x65, 0xae, 0xc8, 0x81, 0x95, 0x27, 0xf3, 0xc7, 0xb3, 0xe7, 0x2f, 0x67, 0xe3,
-	0xc6, 0x41, 0x30, 0x1a, 0xf8, 0x9e, 0x3f, 0x6e, 0x2b, 0x63, 0x96, 0x59, 0x54, 0xdd, 0xa2, 0x7c,
-	0x47, 0xa8, 0xa7, 0xe7, 0x76, 0x16, 0x0f, 0x58, 0x63, 0x32, 0x9a, 0xf0, 0x09, 0x1b, 0x2c, 0xc0,
-	0x15, 0x56, 0x43, 0x2c, 0x4e, 0x53, 0xd0, 0xc2, 0xa5, 0x20, 0x2c, 0x10, 0x16, 0x36, 0x02, 0x11,
-	0x23, 0x90, 0xd0, 0xe8, 0x04, 0x7c, 0xcc, 0xc8, 0x05, 0x7c, 0x97, 0xed, 0x7c, 0xf7, 0xbd, 0x3d,
-	0xfb, 0x90, 0x75, 0x36, 0x61, 0x88, 0x27, 0x18, 0x15, 0x39, 0xe8, 0xfa, 0xd5, 0xf0, 0xdb, 0x17,
-	0x47, 0x23, 0x29, 0x73, 0x20, 0x63, 0x8c, 0x48, 0xbe, 0x16, 0x67, 0xd0, 0xd6, 0x5c, 0xe6, 0x7d,
-	0x22, 0x4d, 0xf8, 0xc3, 0x6f, 0x95, 0xfa, 0x95, 0x7c, 0xb8, 0x5b, 0x3d, 0xc3, 0xc3, 0xbf, 0x01,
-	0x00, 0x00, 0xff, 0xff, 0xf4, 0x55, 0x7c, 0x81, 0x08, 0x03, 0x00, 0x00,
+	// 514 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0xbd, 0x8e, 0xd3, 0x4c,
+	0x14, 0x86, 0x65, 0x7b, 0xbf, 0xdd, 0x78, 0xbe, 0x82, 0x68, 0x90, 0x50, 0xc8, 0x22, 0x34, 0x64,
+	0x25, 0x14, 0x22, 0xe2, 0xc9, 0x86, 0x54, 0x41, 0x48, 0xbb, 0x40, 0x8a, 0xa0, 0xe5, 0x47, 0x41,
+	0xa2, 0xa0, 0x41, 0x13, 0xfb, 0x60, 0x1b, 0xe2, 0x99, 0x61, 0xce, 0x38, 0x4b, 0x87, 0x44, 0x4d,
+	0x05, 0x1d, 0x97, 0x40, 0xcb, 0xa5, 0xd0, 0x51, 0x73, 0x21, 0xc8, 0x76, 0x8c, 0xa3, 0xdd, 0x34,
+	0xb6, 0x67, 0x9e, 0xd7, 0xe7, 0x3d, 0x7f, 0xe4, 0xaa, 0xd0, 0x29, 0x87, 0xb5, 0x58, 0xe5, 0xc2,
+	0x2a, 0x13, 0x68, 0xa3, 0xac, 0xa2, 0xbe, 0xd2, 0x20, 0x33, 0x61, 0xc3, 0xa4, 0x4b, 0x0b, 0x9e,
+	0x01, 0xa2, 0x88, 0x01, 0x2b, 0xdc, 0xbd, 0x11, 0x2b, 0x15, 0xaf, 0x80, 0x17, 0x48, 0x48, 0xa9,
+	0xac, 0xb0, 0xa9, 0x92, 0x35, 0xbd, 0x5b, 0xbe, 0xc2, 0x61, 0x0c, 0x72, 0x88, 0xe7, 0x22, 0x8e,
+	0xc1, 0x70, 0xa5, 0x4b, 0xc5, 0x65, 0x75, 0x0f, 0xc8, 0x95, 0x59, 0xe5, 0x0e, 0x0b, 0xf8, 0x90,
+	0x03, 0x5a, 0x7a, 0x9b, 0xfc, 0x57, 0x7a, 0x77, 0x1c, 0xe6, 0xf4, 0xff, 0x1f, 0xb7, 0x83, 0x7f,
+	0xd9, 0x04, 0x4f, 0x8b, 0xe7, 0xa2, 0xc2, 0x74, 0x40, 0x0e, 0xca, 0x0f, 0xc0, 0x8e, 0xcb, 0xbc,
+	0x9d, 0xca, 0x5a, 0xd0, 0x7b, 0x41, 0xda, 0x8d, 0x0d, 0x6a, 0x25, 0x11, 0xe8, 0x75, 0xd2, 0x2a,
+	0xf1, 0x9b, 0x34, 0xea, 0xb8, 0xcc, 0xe9, 0xfb, 0x1b, 0xf9, 0x3c, 0xa2, 0x87, 0xc4, 0xaf, 0x11,
+	0x76, 0x3c, 0xe6, 0xf5, 0xfd, 0x45, 0x6b, 0xc3, 0xf0, 0xc9, 0x5e, 0xcb, 0x69, 0xbb, 0xe3, 0x4f,
+	0xc4, 0x9f, 0xd5, 0x6d, 0xa3, 0x86, 0xb4, 0xea, 0xf0, 0xb4, 0xbb, 0x95, 0xc5, 0x85, 0xd2, 0xba,
+	0x87, 0x3b, 0x59, 0x95, 0x4f, 0xef, 0xce, 0xe7, 0x5f, 0x7f, 0xbe, 0xb9, 0x47, 0xbd, 0x9b, 0x7c,
+	0x7d, 0xdc, 0x8c, 0x84, 0x6f, 0x4a, 0x98, 0x6e, 0x6e, 0x60, 0xea, 0x0c, 0xfa, 0xce, 0xc8, 0x79,
+	0xf8, 0xc5, 0xfb, 0x7a, 0xfa, 0xdb, 0xa5, 0x3f, 0x9d, 0xad, 0x44, 0x7a, 0x73, 0x42, 0x9e, 0x6b,
+	0x90, 0xac, 0x2c, 0x9f, 0x5e, 0x4b, 0xac, 0xd5, 0x38, 0xe5, 0xbc, 0x70, 0x1d, 0x56, 0xb6, 0x11,
+	0xac, 0xbb, 0x47, 0xcd, 0x79, 0x18, 0xa5, 0x18, 0xe6, 0x88, 0x27, 0xd5, 0x4c, 0x63, 0xa3, 0x72,
+	0x8d, 0x41, 0xa8, 0xb2, 0xc1, 0x2b, 0x42, 0x4f, 0xb5, 0x08, 0x13, 0x60, 0xe3, 0x60, 0xc4, 0xce,
+	0xd2, 0x10, 0x8a, 0xa6, 0x9d, 0xd4, 0x21, 0xe3, 0xd4, 0x26, 0xf9, 0xb2, 0x50, 0xf2, 0xea, 0xd7,
+	0xb7, 0xca, 0xc4, 0x22, 0x03, 0xdc, 0x32, 0xe3, 0xcb, 0x95, 0x5a, 0xf2, 0x4c, 0xa0, 0x05, 0xc3,
+	0xcf, 0xe6, 0x8f, 0x66, 0xcf, 0x5e, 0xce, 0xc6, 0xde, 0x71, 0x30, 0x1a, 0xb8, 0x8e, 0x3b, 0x6e,
+	0x0b, 0xad, 0x57, 0x69, 0x58, 0xae, 0x03, 0x7f, 0x87, 0x4a, 0x4e, 0x2f, 0xdd, 0x2c, 0xee, 0x13,
+	0x6f, 0x32, 0x9a, 0xd0, 0x09, 0x19, 0x2c, 0xc0, 0xe6, 0x46, 0x42, 0xc4, 0xce, 0x13, 0x90, 0xcc,
+	0x26, 0xc0, 0x0c, 0xa0, 0xca, 0x4d, 0x08, 0x2c, 0x52, 0x80, 0x4c, 0x2a, 0xcb, 0xe0, 0x63, 0x8a,
+	0x36, 0xa0, 0xfb, 0x64, 0xef, 0xbb, 0xeb, 0x1c, 0x98, 0x07, 0xa4, 0xd3, 0x34, 0x83, 0x3d, 0x56,
+	0x61, 0x9e, 0x81, 0xac, 0xd6, 0x8f, 0xde, 0xda, 0xdd, 0x1a, 0x8e, 0xa9, 0x05, 0x1e, 0xa9, 0x10,
+	0xf9, 0x6b, 0x76, 0x01, 0x35, 0x47, 0xae, 0xdf, 0xc7, 0x5c, 0x2f, 0x7f, 0xb8, 0x7e, 0x11, 0xbf,
+	0x0c, 0xbf, 0xdc, 0x2f, 0xf7, 0xf9, 0xde, 0xdf, 0x01, 0x00, 0x51, 0x4c, 0x16, 0x27, 0x51, 0x03,
+	0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
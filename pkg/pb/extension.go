@@ -0,0 +1,66 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pb
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+)
+
+// Keys of the well-known extensions in extensions.proto.
+const (
+	// EvaluationInputExtensionKey holds the DefaultEvaluationCriteria of a Match.
+	EvaluationInputExtensionKey = "evaluation_input"
+	// BackfillLinkExtensionKey holds the BackfillLink of a MatchProfile or Match.
+	BackfillLinkExtensionKey = "backfill_link"
+	// EvaluatorShardExtensionKey holds a google.protobuf.StringValue naming
+	// the synchronizer.evaluatorShards entry which evaluates a Match.
+	EvaluatorShardExtensionKey = "evaluator_shard"
+	// QueueTimeHintExtensionKey holds the QueueTimeHint of a MatchProfile or
+	// Assignment.
+	QueueTimeHintExtensionKey = "queue_time_hint"
+)
+
+// SetExtension packs msg into extensions under key, replacing any previous
+// value.  It returns the updated map, which is allocated if extensions is nil,
+// eg.
+//   m.Extensions, err = pb.SetExtension(m.Extensions, pb.EvaluationInputExtensionKey, criteria)
+func SetExtension(extensions map[string]*any.Any, key string, msg proto.Message) (map[string]*any.Any, error) {
+	a, err := ptypes.MarshalAny(msg)
+	if err != nil {
+		return extensions, fmt.Errorf("failed to pack extension %q: %w", key, err)
+	}
+	if extensions == nil {
+		extensions = make(map[string]*any.Any)
+	}
+	extensions[key] = a
+	return extensions, nil
+}
+
+// GetExtension unpacks the extension under key into msg, returning false if
+// there's none.  It fails if the extension isn't of the type of msg.
+func GetExtension(extensions map[string]*any.Any, key string, msg proto.Message) (bool, error) {
+	a, ok := extensions[key]
+	if !ok {
+		return false, nil
+	}
+	if err := ptypes.UnmarshalAny(a, msg); err != nil {
+		return false, fmt.Errorf("failed to unpack extension %q: %w", key, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtensions(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &Match{}
+	var err error
+	m.Extensions, err = SetExtension(m.Extensions, EvaluationInputExtensionKey, &DefaultEvaluationCriteria{Score: 10})
+	assert.Nil(err)
+	m.Extensions, err = SetExtension(m.Extensions, BackfillLinkExtensionKey, &BackfillLink{GameId: "game", OpenSlots: 2})
+	assert.Nil(err)
+
+	criteria := &DefaultEvaluationCriteria{}
+	ok, err := GetExtension(m.Extensions, EvaluationInputExtensionKey, criteria)
+	assert.True(ok)
+	assert.Nil(err)
+	assert.Equal(10.0, criteria.Score)
+
+	link := &BackfillLink{}
+	ok, err = GetExtension(m.Extensions, BackfillLinkExtensionKey, link)
+	assert.True(ok)
+	assert.Nil(err)
+	assert.True(proto.Equal(&BackfillLink{GameId: "game", OpenSlots: 2}, link))
+
+	ok, err = GetExtension(m.Extensions, QueueTimeHintExtensionKey, &QueueTimeHint{})
+	assert.False(ok)
+	assert.Nil(err)
+
+	// The wrong type.
+	ok, err = GetExtension(m.Extensions, BackfillLinkExtensionKey, &QueueTimeHint{})
+	assert.False(ok)
+	assert.NotNil(err)
+
+	// Replaced.
+	hint := &QueueTimeHint{ExpectedWait: ptypes.DurationProto(time.Minute)}
+	m.Extensions, err = SetExtension(m.Extensions, BackfillLinkExtensionKey, hint)
+	assert.Nil(err)
+	got := &QueueTimeHint{}
+	ok, err = GetExtension(m.Extensions, BackfillLinkExtensionKey, got)
+	assert.True(ok)
+	assert.Nil(err)
+	assert.True(proto.Equal(hint, got))
+}
@@ -6,6 +6,7 @@ package pb
 import (
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	duration "github.com/golang/protobuf/ptypes/duration"
 	math "math"
 )
 
@@ -61,21 +62,140 @@ func (m *DefaultEvaluationCriteria) GetScore() float64 {
 	return 0
 }
 
+// A BackfillLink marks a Match as filling the open slots of a game already in
+// progress rather than starting a new one, under the "backfill_link" key.  It
+// is set on the MatchProfile by the director and copied to the Matches made for
+// it by the match function, so that the evaluator can rank backfills and the
+// director can route their Tickets to the game.
+type BackfillLink struct {
+	// Id of the game in progress, as known to the director.
+	GameId string `protobuf:"bytes,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	// Number of players the game still needs.
+	OpenSlots            int32    `protobuf:"varint,2,opt,name=open_slots,json=openSlots,proto3" json:"open_slots,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BackfillLink) Reset()         { *m = BackfillLink{} }
+func (m *BackfillLink) String() string { return proto.CompactTextString(m) }
+func (*BackfillLink) ProtoMessage()    {}
+func (*BackfillLink) Descriptor() ([]byte, []int) {
+	return fileDescriptor_09e3e066475ff045, []int{1}
+}
+
+func (m *BackfillLink) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BackfillLink.Unmarshal(m, b)
+}
+func (m *BackfillLink) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BackfillLink.Marshal(b, m, deterministic)
+}
+func (m *BackfillLink) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BackfillLink.Merge(m, src)
+}
+func (m *BackfillLink) XXX_Size() int {
+	return xxx_messageInfo_BackfillLink.Size(m)
+}
+func (m *BackfillLink) XXX_DiscardUnknown() {
+	xxx_messageInfo_BackfillLink.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BackfillLink proto.InternalMessageInfo
+
+func (m *BackfillLink) GetGameId() string {
+	if m != nil {
+		return m.GameId
+	}
+	return ""
+}
+
+func (m *BackfillLink) GetOpenSlots() int32 {
+	if m != nil {
+		return m.OpenSlots
+	}
+	return 0
+}
+
+// A QueueTimeHint tells how long Tickets are expected to wait for a match,
+// under the "queue_time_hint" key.  On a MatchProfile, it lets match functions
+// relax their criteria for Tickets waiting longer than expected.  On an
+// Assignment, it lets clients show the wait to players.
+type QueueTimeHint struct {
+	// Typical wait before a Ticket is matched.
+	ExpectedWait *duration.Duration `protobuf:"bytes,1,opt,name=expected_wait,json=expectedWait,proto3" json:"expected_wait,omitempty"`
+	// Wait after which Tickets should be matched with relaxed criteria.
+	RelaxAfter           *duration.Duration `protobuf:"bytes,2,opt,name=relax_after,json=relaxAfter,proto3" json:"relax_after,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *QueueTimeHint) Reset()         { *m = QueueTimeHint{} }
+func (m *QueueTimeHint) String() string { return proto.CompactTextString(m) }
+func (*QueueTimeHint) ProtoMessage()    {}
+func (*QueueTimeHint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_09e3e066475ff045, []int{2}
+}
+
+func (m *QueueTimeHint) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueueTimeHint.Unmarshal(m, b)
+}
+func (m *QueueTimeHint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueueTimeHint.Marshal(b, m, deterministic)
+}
+func (m *QueueTimeHint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueueTimeHint.Merge(m, src)
+}
+func (m *QueueTimeHint) XXX_Size() int {
+	return xxx_messageInfo_QueueTimeHint.Size(m)
+}
+func (m *QueueTimeHint) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueueTimeHint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueueTimeHint proto.InternalMessageInfo
+
+func (m *QueueTimeHint) GetExpectedWait() *duration.Duration {
+	if m != nil {
+		return m.ExpectedWait
+	}
+	return nil
+}
+
+func (m *QueueTimeHint) GetRelaxAfter() *duration.Duration {
+	if m != nil {
+		return m.RelaxAfter
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*DefaultEvaluationCriteria)(nil), "openmatch.DefaultEvaluationCriteria")
+	proto.RegisterType((*BackfillLink)(nil), "openmatch.BackfillLink")
+	proto.RegisterType((*QueueTimeHint)(nil), "openmatch.QueueTimeHint")
 }
 
 func init() { proto.RegisterFile("api/extensions.proto", fileDescriptor_09e3e066475ff045) }
 
 var fileDescriptor_09e3e066475ff045 = []byte{
-	// 142 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x49, 0x2c, 0xc8, 0xd4,
-	0x4f, 0xad, 0x28, 0x49, 0xcd, 0x2b, 0xce, 0xcc, 0xcf, 0x2b, 0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9,
-	0x17, 0xe2, 0xcc, 0x2f, 0x48, 0xcd, 0xcb, 0x4d, 0x2c, 0x49, 0xce, 0x50, 0x32, 0xe4, 0x92, 0x74,
-	0x49, 0x4d, 0x4b, 0x2c, 0xcd, 0x29, 0x71, 0x2d, 0x4b, 0xcc, 0x29, 0x4d, 0x2c, 0xc9, 0xcc, 0xcf,
-	0x73, 0x2e, 0xca, 0x2c, 0x49, 0x2d, 0xca, 0x4c, 0x14, 0x12, 0xe1, 0x62, 0x2d, 0x4e, 0xce, 0x2f,
-	0x4a, 0x95, 0x60, 0x54, 0x60, 0xd4, 0x60, 0x0c, 0x82, 0x70, 0x9c, 0xf4, 0xa2, 0x14, 0x40, 0xfa,
-	0x75, 0xc1, 0x06, 0xe8, 0xa5, 0xa4, 0x96, 0xe9, 0x23, 0xb8, 0xfa, 0x05, 0xd9, 0xe9, 0xfa, 0x05,
-	0x49, 0xab, 0x98, 0x38, 0xfd, 0x0b, 0x52, 0xf3, 0x7c, 0x41, 0x42, 0x49, 0x6c, 0x60, 0x4b, 0x8d,
-	0x01, 0x01, 0x00, 0x00, 0xff, 0xff, 0xb6, 0x69, 0xf6, 0x37, 0x8c, 0x00, 0x00, 0x00,
+	// 291 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x90, 0xc1, 0x4a, 0xf3, 0x40,
+	0x14, 0x85, 0x49, 0xa1, 0xfd, 0xe9, 0xb4, 0xdd, 0x84, 0xc2, 0xdf, 0x0a, 0x4a, 0xe9, 0xaa, 0x1b,
+	0x13, 0xd4, 0x9d, 0x0b, 0xc1, 0x5a, 0x45, 0x41, 0x11, 0xa3, 0x20, 0xb8, 0x09, 0xb7, 0xc9, 0x4d,
+	0xbc, 0x74, 0x3a, 0x33, 0x4c, 0xee, 0xd4, 0xbe, 0x83, 0x6f, 0xe2, 0x53, 0xca, 0x4c, 0x2d, 0x2e,
+	0x5d, 0x9e, 0x7b, 0xbe, 0x39, 0x9c, 0x33, 0x62, 0x08, 0x86, 0x52, 0xdc, 0x32, 0xaa, 0x86, 0xb4,
+	0x6a, 0x12, 0x63, 0x35, 0xeb, 0xb8, 0xab, 0x0d, 0xaa, 0x35, 0x70, 0xf1, 0x7e, 0x70, 0x54, 0x6b,
+	0x5d, 0x4b, 0x4c, 0x83, 0xb1, 0x74, 0x55, 0x5a, 0x3a, 0x0b, 0x4c, 0x5a, 0xed, 0xd0, 0xe9, 0x89,
+	0x18, 0x2f, 0xb0, 0x02, 0x27, 0xf9, 0x7a, 0x03, 0xd2, 0x05, 0xeb, 0xca, 0x12, 0xa3, 0x25, 0x88,
+	0x87, 0xa2, 0xdd, 0x14, 0xda, 0xe2, 0x28, 0x9a, 0x44, 0xb3, 0x28, 0xdb, 0x89, 0xe9, 0x8d, 0xe8,
+	0xcf, 0xa1, 0x58, 0x55, 0x24, 0xe5, 0x3d, 0xa9, 0x55, 0xfc, 0x5f, 0xfc, 0xab, 0x61, 0x8d, 0x39,
+	0x95, 0x81, 0xeb, 0x66, 0x1d, 0x2f, 0xef, 0xca, 0xf8, 0x50, 0x08, 0x5f, 0x24, 0x6f, 0xa4, 0xe6,
+	0x66, 0xd4, 0x9a, 0x44, 0xb3, 0x76, 0x16, 0xaa, 0x3d, 0xfb, 0xc3, 0xf4, 0x33, 0x12, 0x83, 0x27,
+	0x87, 0x0e, 0x5f, 0x68, 0x8d, 0xb7, 0xa4, 0x38, 0xbe, 0x10, 0x03, 0xdc, 0x1a, 0x2c, 0x18, 0xcb,
+	0xfc, 0x03, 0x88, 0x43, 0x5e, 0xef, 0x74, 0x9c, 0xec, 0x46, 0x24, 0xfb, 0x11, 0xc9, 0xe2, 0x67,
+	0x44, 0xd6, 0xdf, 0xf3, 0xaf, 0x40, 0x1c, 0x9f, 0x8b, 0x9e, 0x45, 0x09, 0xdb, 0x1c, 0x2a, 0x46,
+	0x3b, 0x6a, 0xfd, 0xf5, 0x5a, 0x04, 0xfa, 0xd2, 0xc3, 0xf3, 0xe4, 0x6d, 0xe2, 0xab, 0x1d, 0x87,
+	0x6f, 0x4b, 0x4a, 0xdc, 0xa4, 0xbf, 0x32, 0x35, 0xab, 0x3a, 0x35, 0xcb, 0xaf, 0x56, 0xf7, 0xd1,
+	0xa0, 0x7a, 0xf0, 0xa7, 0x65, 0x27, 0xc4, 0x9d, 0x7d, 0x0f, 0x00, 0x0e, 0xb8, 0x54, 0xa6, 0x82,
+	0x01, 0x00, 0x00,
 }
@@ -7,6 +7,7 @@ import (
 	context "context"
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	duration "github.com/golang/protobuf/ptypes/duration"
 	_ "github.com/grpc-ecosystem/grpc-gateway/protoc-gen-swagger/options"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	grpc "google.golang.org/grpc"
@@ -28,7 +29,21 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type CreateTicketRequest struct {
 	// A Ticket object with SearchFields defined.
-	Ticket               *Ticket  `protobuf:"bytes,1,opt,name=ticket,proto3" json:"ticket,omitempty"`
+	Ticket *Ticket `protobuf:"bytes,1,opt,name=ticket,proto3" json:"ticket,omitempty"`
+	// If true, the response lists the pools of recently used MatchProfiles which
+	// the Ticket falls into.
+	// Optional, defaults to false.
+	IncludeMatchedPools bool `protobuf:"varint,2,opt,name=include_matched_pools,json=includeMatchedPools,proto3" json:"include_matched_pools,omitempty"`
+	// How long the Ticket is kept in state storage, eg. short for tickets of a
+	// timed event queue and long for party tickets.
+	// Optional, defaults to the configured redis.expiration.
+	Expiration *duration.Duration `protobuf:"bytes,3,opt,name=expiration,proto3" json:"expiration,omitempty"`
+	// A key chosen by the client, eg. a UUID, which is the same for retries of
+	// the request.  Retries within the configured frontend.idempotencyWindow
+	// return the Ticket created by the first request rather than creating
+	// another one.  Retries which race the first request fail with Aborted.
+	// Optional, at most 256 bytes.
+	IdempotencyKey       string   `protobuf:"bytes,4,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -66,19 +81,95 @@ func (m *CreateTicketRequest) GetTicket() *Ticket {
 	return nil
 }
 
-type CreateTicketResponse struct {
-	// A Ticket object with TicketId generated.
-	Ticket               *Ticket  `protobuf:"bytes,1,opt,name=ticket,proto3" json:"ticket,omitempty"`
+func (m *CreateTicketRequest) GetIncludeMatchedPools() bool {
+	if m != nil {
+		return m.IncludeMatchedPools
+	}
+	return false
+}
+
+func (m *CreateTicketRequest) GetExpiration() *duration.Duration {
+	if m != nil {
+		return m.Expiration
+	}
+	return nil
+}
+
+func (m *CreateTicketRequest) GetIdempotencyKey() string {
+	if m != nil {
+		return m.IdempotencyKey
+	}
+	return ""
+}
+
+// MatchedPool identifies a Pool of a MatchProfile.
+type MatchedPool struct {
+	// Name of the MatchProfile.
+	Profile string `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	// Name of the Pool within the MatchProfile.
+	Pool                 string   `protobuf:"bytes,2,opt,name=pool,proto3" json:"pool,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
+func (m *MatchedPool) Reset()         { *m = MatchedPool{} }
+func (m *MatchedPool) String() string { return proto.CompactTextString(m) }
+func (*MatchedPool) ProtoMessage()    {}
+func (*MatchedPool) Descriptor() ([]byte, []int) {
+	return fileDescriptor_06c902cf58d2ae57, []int{1}
+}
+
+func (m *MatchedPool) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MatchedPool.Unmarshal(m, b)
+}
+func (m *MatchedPool) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MatchedPool.Marshal(b, m, deterministic)
+}
+func (m *MatchedPool) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MatchedPool.Merge(m, src)
+}
+func (m *MatchedPool) XXX_Size() int {
+	return xxx_messageInfo_MatchedPool.Size(m)
+}
+func (m *MatchedPool) XXX_DiscardUnknown() {
+	xxx_messageInfo_MatchedPool.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MatchedPool proto.InternalMessageInfo
+
+func (m *MatchedPool) GetProfile() string {
+	if m != nil {
+		return m.Profile
+	}
+	return ""
+}
+
+func (m *MatchedPool) GetPool() string {
+	if m != nil {
+		return m.Pool
+	}
+	return ""
+}
+
+type CreateTicketResponse struct {
+	// A Ticket object with TicketId generated.
+	Ticket *Ticket `protobuf:"bytes,1,opt,name=ticket,proto3" json:"ticket,omitempty"`
+	// Pools the Ticket falls into, out of those of the MatchProfiles passed to
+	// FetchMatches recently, when include_matched_pools is set.  An empty list
+	// means no recently used profile would consider the Ticket, which usually
+	// points to a misconfigured Ticket or profile.
+	MatchedPools         []*MatchedPool `protobuf:"bytes,2,rep,name=matched_pools,json=matchedPools,proto3" json:"matched_pools,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
 func (m *CreateTicketResponse) Reset()         { *m = CreateTicketResponse{} }
 func (m *CreateTicketResponse) String() string { return proto.CompactTextString(m) }
 func (*CreateTicketResponse) ProtoMessage()    {}
 func (*CreateTicketResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_06c902cf58d2ae57, []int{1}
+	return fileDescriptor_06c902cf58d2ae57, []int{2}
 }
 
 func (m *CreateTicketResponse) XXX_Unmarshal(b []byte) error {
@@ -106,6 +197,185 @@ func (m *CreateTicketResponse) GetTicket() *Ticket {
 	return nil
 }
 
+func (m *CreateTicketResponse) GetMatchedPools() []*MatchedPool {
+	if m != nil {
+		return m.MatchedPools
+	}
+	return nil
+}
+
+type CreateTicketsRequest struct {
+	// Ticket objects with SearchFields defined.
+	Tickets []*Ticket `protobuf:"bytes,1,rep,name=tickets,proto3" json:"tickets,omitempty"`
+	// How long the Tickets are kept in state storage.
+	// Optional, defaults to the configured redis.expiration.
+	Expiration           *duration.Duration `protobuf:"bytes,2,opt,name=expiration,proto3" json:"expiration,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *CreateTicketsRequest) Reset()         { *m = CreateTicketsRequest{} }
+func (m *CreateTicketsRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateTicketsRequest) ProtoMessage()    {}
+func (*CreateTicketsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_06c902cf58d2ae57, []int{3}
+}
+
+func (m *CreateTicketsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateTicketsRequest.Unmarshal(m, b)
+}
+func (m *CreateTicketsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateTicketsRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateTicketsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateTicketsRequest.Merge(m, src)
+}
+func (m *CreateTicketsRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateTicketsRequest.Size(m)
+}
+func (m *CreateTicketsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateTicketsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateTicketsRequest proto.InternalMessageInfo
+
+func (m *CreateTicketsRequest) GetTickets() []*Ticket {
+	if m != nil {
+		return m.Tickets
+	}
+	return nil
+}
+
+func (m *CreateTicketsRequest) GetExpiration() *duration.Duration {
+	if m != nil {
+		return m.Expiration
+	}
+	return nil
+}
+
+type CreateTicketsResponse struct {
+	// The Ticket objects with TicketIds generated, in the order of the request.
+	Tickets              []*Ticket `protobuf:"bytes,1,rep,name=tickets,proto3" json:"tickets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *CreateTicketsResponse) Reset()         { *m = CreateTicketsResponse{} }
+func (m *CreateTicketsResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateTicketsResponse) ProtoMessage()    {}
+func (*CreateTicketsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_06c902cf58d2ae57, []int{4}
+}
+
+func (m *CreateTicketsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateTicketsResponse.Unmarshal(m, b)
+}
+func (m *CreateTicketsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateTicketsResponse.Marshal(b, m, deterministic)
+}
+func (m *CreateTicketsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateTicketsResponse.Merge(m, src)
+}
+func (m *CreateTicketsResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateTicketsResponse.Size(m)
+}
+func (m *CreateTicketsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateTicketsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateTicketsResponse proto.InternalMessageInfo
+
+func (m *CreateTicketsResponse) GetTickets() []*Ticket {
+	if m != nil {
+		return m.Tickets
+	}
+	return nil
+}
+
+type UpdateTicketRequest struct {
+	// The updated Ticket, with the TicketId of a generated Ticket.  Its
+	// SearchFields, extensions, player_ids and avoid_ids replace those stored.
+	// The Assignment must not be set.
+	Ticket               *Ticket  `protobuf:"bytes,1,opt,name=ticket,proto3" json:"ticket,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateTicketRequest) Reset()         { *m = UpdateTicketRequest{} }
+func (m *UpdateTicketRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateTicketRequest) ProtoMessage()    {}
+func (*UpdateTicketRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_06c902cf58d2ae57, []int{5}
+}
+
+func (m *UpdateTicketRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateTicketRequest.Unmarshal(m, b)
+}
+func (m *UpdateTicketRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateTicketRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateTicketRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateTicketRequest.Merge(m, src)
+}
+func (m *UpdateTicketRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateTicketRequest.Size(m)
+}
+func (m *UpdateTicketRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateTicketRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateTicketRequest proto.InternalMessageInfo
+
+func (m *UpdateTicketRequest) GetTicket() *Ticket {
+	if m != nil {
+		return m.Ticket
+	}
+	return nil
+}
+
+type UpdateTicketResponse struct {
+	// The Ticket as stored after the update.
+	Ticket               *Ticket  `protobuf:"bytes,1,opt,name=ticket,proto3" json:"ticket,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateTicketResponse) Reset()         { *m = UpdateTicketResponse{} }
+func (m *UpdateTicketResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateTicketResponse) ProtoMessage()    {}
+func (*UpdateTicketResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_06c902cf58d2ae57, []int{6}
+}
+
+func (m *UpdateTicketResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateTicketResponse.Unmarshal(m, b)
+}
+func (m *UpdateTicketResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateTicketResponse.Marshal(b, m, deterministic)
+}
+func (m *UpdateTicketResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateTicketResponse.Merge(m, src)
+}
+func (m *UpdateTicketResponse) XXX_Size() int {
+	return xxx_messageInfo_UpdateTicketResponse.Size(m)
+}
+func (m *UpdateTicketResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateTicketResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateTicketResponse proto.InternalMessageInfo
+
+func (m *UpdateTicketResponse) GetTicket() *Ticket {
+	if m != nil {
+		return m.Ticket
+	}
+	return nil
+}
+
 type DeleteTicketRequest struct {
 	// A TicketId of a generated Ticket to be deleted.
 	TicketId             string   `protobuf:"bytes,1,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
@@ -118,7 +388,7 @@ func (m *DeleteTicketRequest) Reset()         { *m = DeleteTicketRequest{} }
 func (m *DeleteTicketRequest) String() string { return proto.CompactTextString(m) }
 func (*DeleteTicketRequest) ProtoMessage()    {}
 func (*DeleteTicketRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_06c902cf58d2ae57, []int{2}
+	return fileDescriptor_06c902cf58d2ae57, []int{7}
 }
 
 func (m *DeleteTicketRequest) XXX_Unmarshal(b []byte) error {
@@ -156,7 +426,7 @@ func (m *DeleteTicketResponse) Reset()         { *m = DeleteTicketResponse{} }
 func (m *DeleteTicketResponse) String() string { return proto.CompactTextString(m) }
 func (*DeleteTicketResponse) ProtoMessage()    {}
 func (*DeleteTicketResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_06c902cf58d2ae57, []int{3}
+	return fileDescriptor_06c902cf58d2ae57, []int{8}
 }
 
 func (m *DeleteTicketResponse) XXX_Unmarshal(b []byte) error {
@@ -177,6 +447,158 @@ func (m *DeleteTicketResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_DeleteTicketResponse proto.InternalMessageInfo
 
+type DeleteTicketsRequest struct {
+	// TicketIds of generated Tickets to be deleted.
+	TicketIds            []string `protobuf:"bytes,1,rep,name=ticket_ids,json=ticketIds,proto3" json:"ticket_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteTicketsRequest) Reset()         { *m = DeleteTicketsRequest{} }
+func (m *DeleteTicketsRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteTicketsRequest) ProtoMessage()    {}
+func (*DeleteTicketsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_06c902cf58d2ae57, []int{9}
+}
+
+func (m *DeleteTicketsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteTicketsRequest.Unmarshal(m, b)
+}
+func (m *DeleteTicketsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteTicketsRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteTicketsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteTicketsRequest.Merge(m, src)
+}
+func (m *DeleteTicketsRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteTicketsRequest.Size(m)
+}
+func (m *DeleteTicketsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteTicketsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteTicketsRequest proto.InternalMessageInfo
+
+func (m *DeleteTicketsRequest) GetTicketIds() []string {
+	if m != nil {
+		return m.TicketIds
+	}
+	return nil
+}
+
+type DeleteTicketsResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteTicketsResponse) Reset()         { *m = DeleteTicketsResponse{} }
+func (m *DeleteTicketsResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteTicketsResponse) ProtoMessage()    {}
+func (*DeleteTicketsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_06c902cf58d2ae57, []int{10}
+}
+
+func (m *DeleteTicketsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteTicketsResponse.Unmarshal(m, b)
+}
+func (m *DeleteTicketsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteTicketsResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteTicketsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteTicketsResponse.Merge(m, src)
+}
+func (m *DeleteTicketsResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteTicketsResponse.Size(m)
+}
+func (m *DeleteTicketsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteTicketsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteTicketsResponse proto.InternalMessageInfo
+
+type HeartbeatRequest struct {
+	// TicketIds of generated Tickets to be kept in matchmaking.
+	TicketIds            []string `protobuf:"bytes,1,rep,name=ticket_ids,json=ticketIds,proto3" json:"ticket_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HeartbeatRequest) Reset()         { *m = HeartbeatRequest{} }
+func (m *HeartbeatRequest) String() string { return proto.CompactTextString(m) }
+func (*HeartbeatRequest) ProtoMessage()    {}
+func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_06c902cf58d2ae57, []int{11}
+}
+
+func (m *HeartbeatRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HeartbeatRequest.Unmarshal(m, b)
+}
+func (m *HeartbeatRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HeartbeatRequest.Marshal(b, m, deterministic)
+}
+func (m *HeartbeatRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HeartbeatRequest.Merge(m, src)
+}
+func (m *HeartbeatRequest) XXX_Size() int {
+	return xxx_messageInfo_HeartbeatRequest.Size(m)
+}
+func (m *HeartbeatRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_HeartbeatRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HeartbeatRequest proto.InternalMessageInfo
+
+func (m *HeartbeatRequest) GetTicketIds() []string {
+	if m != nil {
+		return m.TicketIds
+	}
+	return nil
+}
+
+type HeartbeatResponse struct {
+	// TicketIds of the requested Tickets which are no longer in matchmaking, eg.
+	// because they were assigned, deleted or their heartbeat lapsed.
+	NotIndexedTicketIds  []string `protobuf:"bytes,1,rep,name=not_indexed_ticket_ids,json=notIndexedTicketIds,proto3" json:"not_indexed_ticket_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HeartbeatResponse) Reset()         { *m = HeartbeatResponse{} }
+func (m *HeartbeatResponse) String() string { return proto.CompactTextString(m) }
+func (*HeartbeatResponse) ProtoMessage()    {}
+func (*HeartbeatResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_06c902cf58d2ae57, []int{12}
+}
+
+func (m *HeartbeatResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HeartbeatResponse.Unmarshal(m, b)
+}
+func (m *HeartbeatResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HeartbeatResponse.Marshal(b, m, deterministic)
+}
+func (m *HeartbeatResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HeartbeatResponse.Merge(m, src)
+}
+func (m *HeartbeatResponse) XXX_Size() int {
+	return xxx_messageInfo_HeartbeatResponse.Size(m)
+}
+func (m *HeartbeatResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_HeartbeatResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HeartbeatResponse proto.InternalMessageInfo
+
+func (m *HeartbeatResponse) GetNotIndexedTicketIds() []string {
+	if m != nil {
+		return m.NotIndexedTicketIds
+	}
+	return nil
+}
+
 type GetTicketRequest struct {
 	// A TicketId of a generated Ticket.
 	TicketId             string   `protobuf:"bytes,1,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
@@ -189,7 +611,7 @@ func (m *GetTicketRequest) Reset()         { *m = GetTicketRequest{} }
 func (m *GetTicketRequest) String() string { return proto.CompactTextString(m) }
 func (*GetTicketRequest) ProtoMessage()    {}
 func (*GetTicketRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_06c902cf58d2ae57, []int{4}
+	return fileDescriptor_06c902cf58d2ae57, []int{13}
 }
 
 func (m *GetTicketRequest) XXX_Unmarshal(b []byte) error {
@@ -217,6 +639,195 @@ func (m *GetTicketRequest) GetTicketId() string {
 	return ""
 }
 
+type GetTicketStateRequest struct {
+	// A TicketId of a generated Ticket.
+	TicketId             string   `protobuf:"bytes,1,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetTicketStateRequest) Reset()         { *m = GetTicketStateRequest{} }
+func (m *GetTicketStateRequest) String() string { return proto.CompactTextString(m) }
+func (*GetTicketStateRequest) ProtoMessage()    {}
+func (*GetTicketStateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_06c902cf58d2ae57, []int{14}
+}
+
+func (m *GetTicketStateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTicketStateRequest.Unmarshal(m, b)
+}
+func (m *GetTicketStateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTicketStateRequest.Marshal(b, m, deterministic)
+}
+func (m *GetTicketStateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTicketStateRequest.Merge(m, src)
+}
+func (m *GetTicketStateRequest) XXX_Size() int {
+	return xxx_messageInfo_GetTicketStateRequest.Size(m)
+}
+func (m *GetTicketStateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTicketStateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTicketStateRequest proto.InternalMessageInfo
+
+func (m *GetTicketStateRequest) GetTicketId() string {
+	if m != nil {
+		return m.TicketId
+	}
+	return ""
+}
+
+type GetTicketStateResponse struct {
+	// The current state of the requested Ticket.
+	State                Ticket_State `protobuf:"varint,1,opt,name=state,proto3,enum=openmatch.Ticket_State" json:"state,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *GetTicketStateResponse) Reset()         { *m = GetTicketStateResponse{} }
+func (m *GetTicketStateResponse) String() string { return proto.CompactTextString(m) }
+func (*GetTicketStateResponse) ProtoMessage()    {}
+func (*GetTicketStateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_06c902cf58d2ae57, []int{15}
+}
+
+func (m *GetTicketStateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTicketStateResponse.Unmarshal(m, b)
+}
+func (m *GetTicketStateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTicketStateResponse.Marshal(b, m, deterministic)
+}
+func (m *GetTicketStateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTicketStateResponse.Merge(m, src)
+}
+func (m *GetTicketStateResponse) XXX_Size() int {
+	return xxx_messageInfo_GetTicketStateResponse.Size(m)
+}
+func (m *GetTicketStateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTicketStateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTicketStateResponse proto.InternalMessageInfo
+
+func (m *GetTicketStateResponse) GetState() Ticket_State {
+	if m != nil {
+		return m.State
+	}
+	return Ticket_STATE_UNSPECIFIED
+}
+
+type GetTicketStatusRequest struct {
+	// A TicketId of a generated Ticket.
+	TicketId             string   `protobuf:"bytes,1,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetTicketStatusRequest) Reset()         { *m = GetTicketStatusRequest{} }
+func (m *GetTicketStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetTicketStatusRequest) ProtoMessage()    {}
+func (*GetTicketStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_06c902cf58d2ae57, []int{16}
+}
+
+func (m *GetTicketStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTicketStatusRequest.Unmarshal(m, b)
+}
+func (m *GetTicketStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTicketStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *GetTicketStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTicketStatusRequest.Merge(m, src)
+}
+func (m *GetTicketStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_GetTicketStatusRequest.Size(m)
+}
+func (m *GetTicketStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTicketStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTicketStatusRequest proto.InternalMessageInfo
+
+func (m *GetTicketStatusRequest) GetTicketId() string {
+	if m != nil {
+		return m.TicketId
+	}
+	return ""
+}
+
+type GetTicketStatusResponse struct {
+	// Whether the Ticket is in the pool queried by match functions.
+	Indexed bool `protobuf:"varint,1,opt,name=indexed,proto3" json:"indexed,omitempty"`
+	// Whether the Ticket is in a proposed match which isn't assigned yet, ie. on
+	// the ignore list.
+	Proposed bool `protobuf:"varint,2,opt,name=proposed,proto3" json:"proposed,omitempty"`
+	// Whether the Ticket was assigned to a game server.
+	Assigned bool `protobuf:"varint,3,opt,name=assigned,proto3" json:"assigned,omitempty"`
+	// Time since the Ticket was created, at a resolution of one second.  Unset
+	// if the TicketId wasn't generated by Open Match, eg. for imported Tickets.
+	Age                  *duration.Duration `protobuf:"bytes,4,opt,name=age,proto3" json:"age,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *GetTicketStatusResponse) Reset()         { *m = GetTicketStatusResponse{} }
+func (m *GetTicketStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*GetTicketStatusResponse) ProtoMessage()    {}
+func (*GetTicketStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_06c902cf58d2ae57, []int{17}
+}
+
+func (m *GetTicketStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTicketStatusResponse.Unmarshal(m, b)
+}
+func (m *GetTicketStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTicketStatusResponse.Marshal(b, m, deterministic)
+}
+func (m *GetTicketStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTicketStatusResponse.Merge(m, src)
+}
+func (m *GetTicketStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_GetTicketStatusResponse.Size(m)
+}
+func (m *GetTicketStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTicketStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTicketStatusResponse proto.InternalMessageInfo
+
+func (m *GetTicketStatusResponse) GetIndexed() bool {
+	if m != nil {
+		return m.Indexed
+	}
+	return false
+}
+
+func (m *GetTicketStatusResponse) GetProposed() bool {
+	if m != nil {
+		return m.Proposed
+	}
+	return false
+}
+
+func (m *GetTicketStatusResponse) GetAssigned() bool {
+	if m != nil {
+		return m.Assigned
+	}
+	return false
+}
+
+func (m *GetTicketStatusResponse) GetAge() *duration.Duration {
+	if m != nil {
+		return m.Age
+	}
+	return nil
+}
+
 type GetAssignmentsRequest struct {
 	// A TicketId of a generated Ticket to get updates on.
 	TicketId             string   `protobuf:"bytes,1,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
@@ -229,7 +840,7 @@ func (m *GetAssignmentsRequest) Reset()         { *m = GetAssignmentsRequest{} }
 func (m *GetAssignmentsRequest) String() string { return proto.CompactTextString(m) }
 func (*GetAssignmentsRequest) ProtoMessage()    {}
 func (*GetAssignmentsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_06c902cf58d2ae57, []int{5}
+	return fileDescriptor_06c902cf58d2ae57, []int{18}
 }
 
 func (m *GetAssignmentsRequest) XXX_Unmarshal(b []byte) error {
@@ -269,7 +880,7 @@ func (m *GetAssignmentsResponse) Reset()         { *m = GetAssignmentsResponse{}
 func (m *GetAssignmentsResponse) String() string { return proto.CompactTextString(m) }
 func (*GetAssignmentsResponse) ProtoMessage()    {}
 func (*GetAssignmentsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_06c902cf58d2ae57, []int{6}
+	return fileDescriptor_06c902cf58d2ae57, []int{19}
 }
 
 func (m *GetAssignmentsResponse) XXX_Unmarshal(b []byte) error {
@@ -297,60 +908,228 @@ func (m *GetAssignmentsResponse) GetAssignment() *Assignment {
 	return nil
 }
 
+// The FrontendService implements APIs to manage and query status of a Tickets.
+type WaitForAssignmentRequest struct {
+	// A TicketId of a generated Ticket to wait on.
+	TicketId string `protobuf:"bytes,1,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	// The assignment_fingerprint from the previous WaitForAssignmentResponse.
+	// The call returns as soon as the Assignment no longer matches it.  Leave it
+	// empty to wait for the Ticket to be assigned.
+	AssignmentFingerprint string `protobuf:"bytes,2,opt,name=assignment_fingerprint,json=assignmentFingerprint,proto3" json:"assignment_fingerprint,omitempty"`
+	// How long to wait for a change before returning the current Assignment.
+	// Optional, defaults to and is capped by the server's configured maximum.
+	TimeoutSeconds       int32    `protobuf:"varint,3,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WaitForAssignmentRequest) Reset()         { *m = WaitForAssignmentRequest{} }
+func (m *WaitForAssignmentRequest) String() string { return proto.CompactTextString(m) }
+func (*WaitForAssignmentRequest) ProtoMessage()    {}
+func (*WaitForAssignmentRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_06c902cf58d2ae57, []int{20}
+}
+
+func (m *WaitForAssignmentRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WaitForAssignmentRequest.Unmarshal(m, b)
+}
+func (m *WaitForAssignmentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WaitForAssignmentRequest.Marshal(b, m, deterministic)
+}
+func (m *WaitForAssignmentRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WaitForAssignmentRequest.Merge(m, src)
+}
+func (m *WaitForAssignmentRequest) XXX_Size() int {
+	return xxx_messageInfo_WaitForAssignmentRequest.Size(m)
+}
+func (m *WaitForAssignmentRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WaitForAssignmentRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WaitForAssignmentRequest proto.InternalMessageInfo
+
+func (m *WaitForAssignmentRequest) GetTicketId() string {
+	if m != nil {
+		return m.TicketId
+	}
+	return ""
+}
+
+func (m *WaitForAssignmentRequest) GetAssignmentFingerprint() string {
+	if m != nil {
+		return m.AssignmentFingerprint
+	}
+	return ""
+}
+
+func (m *WaitForAssignmentRequest) GetTimeoutSeconds() int32 {
+	if m != nil {
+		return m.TimeoutSeconds
+	}
+	return 0
+}
+
+type WaitForAssignmentResponse struct {
+	// The current Assignment of the requested Ticket, unset if the Ticket isn't
+	// assigned.
+	Assignment *Assignment `protobuf:"bytes,1,opt,name=assignment,proto3" json:"assignment,omitempty"`
+	// Identifies the returned Assignment, pass it to the next
+	// WaitForAssignmentRequest to wait for a further change.
+	AssignmentFingerprint string   `protobuf:"bytes,2,opt,name=assignment_fingerprint,json=assignmentFingerprint,proto3" json:"assignment_fingerprint,omitempty"`
+	XXX_NoUnkeyedLiteral  struct{} `json:"-"`
+	XXX_unrecognized      []byte   `json:"-"`
+	XXX_sizecache         int32    `json:"-"`
+}
+
+func (m *WaitForAssignmentResponse) Reset()         { *m = WaitForAssignmentResponse{} }
+func (m *WaitForAssignmentResponse) String() string { return proto.CompactTextString(m) }
+func (*WaitForAssignmentResponse) ProtoMessage()    {}
+func (*WaitForAssignmentResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_06c902cf58d2ae57, []int{21}
+}
+
+func (m *WaitForAssignmentResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WaitForAssignmentResponse.Unmarshal(m, b)
+}
+func (m *WaitForAssignmentResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WaitForAssignmentResponse.Marshal(b, m, deterministic)
+}
+func (m *WaitForAssignmentResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WaitForAssignmentResponse.Merge(m, src)
+}
+func (m *WaitForAssignmentResponse) XXX_Size() int {
+	return xxx_messageInfo_WaitForAssignmentResponse.Size(m)
+}
+func (m *WaitForAssignmentResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WaitForAssignmentResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WaitForAssignmentResponse proto.InternalMessageInfo
+
+func (m *WaitForAssignmentResponse) GetAssignment() *Assignment {
+	if m != nil {
+		return m.Assignment
+	}
+	return nil
+}
+
+func (m *WaitForAssignmentResponse) GetAssignmentFingerprint() string {
+	if m != nil {
+		return m.AssignmentFingerprint
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*CreateTicketRequest)(nil), "openmatch.CreateTicketRequest")
+	proto.RegisterType((*MatchedPool)(nil), "openmatch.MatchedPool")
 	proto.RegisterType((*CreateTicketResponse)(nil), "openmatch.CreateTicketResponse")
+	proto.RegisterType((*CreateTicketsRequest)(nil), "openmatch.CreateTicketsRequest")
+	proto.RegisterType((*CreateTicketsResponse)(nil), "openmatch.CreateTicketsResponse")
+	proto.RegisterType((*UpdateTicketRequest)(nil), "openmatch.UpdateTicketRequest")
+	proto.RegisterType((*UpdateTicketResponse)(nil), "openmatch.UpdateTicketResponse")
 	proto.RegisterType((*DeleteTicketRequest)(nil), "openmatch.DeleteTicketRequest")
 	proto.RegisterType((*DeleteTicketResponse)(nil), "openmatch.DeleteTicketResponse")
+	proto.RegisterType((*DeleteTicketsRequest)(nil), "openmatch.DeleteTicketsRequest")
+	proto.RegisterType((*DeleteTicketsResponse)(nil), "openmatch.DeleteTicketsResponse")
+	proto.RegisterType((*HeartbeatRequest)(nil), "openmatch.HeartbeatRequest")
+	proto.RegisterType((*HeartbeatResponse)(nil), "openmatch.HeartbeatResponse")
 	proto.RegisterType((*GetTicketRequest)(nil), "openmatch.GetTicketRequest")
+	proto.RegisterType((*GetTicketStateRequest)(nil), "openmatch.GetTicketStateRequest")
+	proto.RegisterType((*GetTicketStateResponse)(nil), "openmatch.GetTicketStateResponse")
+	proto.RegisterType((*GetTicketStatusRequest)(nil), "openmatch.GetTicketStatusRequest")
+	proto.RegisterType((*GetTicketStatusResponse)(nil), "openmatch.GetTicketStatusResponse")
 	proto.RegisterType((*GetAssignmentsRequest)(nil), "openmatch.GetAssignmentsRequest")
 	proto.RegisterType((*GetAssignmentsResponse)(nil), "openmatch.GetAssignmentsResponse")
+	proto.RegisterType((*WaitForAssignmentRequest)(nil), "openmatch.WaitForAssignmentRequest")
+	proto.RegisterType((*WaitForAssignmentResponse)(nil), "openmatch.WaitForAssignmentResponse")
 }
 
 func init() { proto.RegisterFile("api/frontend.proto", fileDescriptor_06c902cf58d2ae57) }
 
 var fileDescriptor_06c902cf58d2ae57 = []byte{
-	// 640 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0x4b, 0x4f, 0x13, 0x51,
-	0x14, 0xce, 0x14, 0x82, 0xf4, 0x48, 0x14, 0x2f, 0x8f, 0x90, 0x62, 0xf4, 0x52, 0x13, 0x95, 0xc6,
-	0xce, 0x2d, 0xa5, 0x6c, 0x20, 0x26, 0x54, 0x40, 0x42, 0x82, 0x92, 0x14, 0xe3, 0xc2, 0x8d, 0x99,
-	0xce, 0x1c, 0xa6, 0x23, 0xed, 0xbd, 0xe3, 0x9c, 0x3b, 0x60, 0x62, 0x4c, 0x8c, 0x5b, 0x77, 0xe8,
-	0xca, 0x9f, 0xe0, 0xd2, 0xbf, 0xe2, 0xca, 0xbd, 0x3f, 0xc4, 0xcc, 0xa3, 0xed, 0xf4, 0x21, 0x81,
-	0xd5, 0x64, 0xce, 0xf9, 0xce, 0xf7, 0x9d, 0xc7, 0x97, 0x0b, 0xcc, 0xf2, 0x3d, 0x71, 0x12, 0x28,
-	0xa9, 0x51, 0x3a, 0xa6, 0x1f, 0x28, 0xad, 0x58, 0x5e, 0xf9, 0x28, 0x3b, 0x96, 0xb6, 0x5b, 0x85,
-	0x38, 0xdd, 0x41, 0x22, 0xcb, 0x45, 0x4a, 0xd2, 0x85, 0xbb, 0xae, 0x52, 0x6e, 0x1b, 0x45, 0x94,
-	0xb2, 0xa4, 0x54, 0xda, 0xd2, 0x9e, 0x92, 0xdd, 0xec, 0x93, 0xf8, 0x63, 0x97, 0x5d, 0x94, 0x65,
-	0x3a, 0xb7, 0x5c, 0x17, 0x03, 0xa1, 0xfc, 0x18, 0x31, 0x8a, 0x2e, 0x6e, 0xc3, 0xdc, 0x4e, 0x80,
-	0x96, 0xc6, 0x57, 0x9e, 0x7d, 0x8a, 0xba, 0x81, 0xef, 0x43, 0x24, 0xcd, 0x56, 0x61, 0x4a, 0xc7,
-	0x81, 0x25, 0x83, 0x1b, 0x8f, 0x6f, 0x56, 0xef, 0x98, 0xbd, 0x96, 0xcc, 0x14, 0x99, 0x02, 0x8a,
-	0x75, 0x98, 0x1f, 0x64, 0x20, 0x5f, 0x49, 0xc2, 0xeb, 0x50, 0x54, 0x61, 0x6e, 0x17, 0xdb, 0x38,
-	0xdc, 0xc4, 0x32, 0xe4, 0x13, 0xc0, 0x5b, 0xcf, 0x89, 0x49, 0xf2, 0x8d, 0xe9, 0x24, 0x70, 0xe0,
-	0x14, 0x17, 0x61, 0x7e, 0xb0, 0x26, 0x91, 0x2d, 0x0a, 0x98, 0xdd, 0x47, 0x7d, 0x0d, 0xa2, 0x1a,
-	0x2c, 0xec, 0xa3, 0xae, 0x13, 0x79, 0xae, 0xec, 0xa0, 0xd4, 0x74, 0xa5, 0xaa, 0x23, 0x58, 0x1c,
-	0xae, 0x4a, 0xe7, 0xde, 0x00, 0xb0, 0x7a, 0xe1, 0x74, 0xf6, 0x85, 0xcc, 0xec, 0xfd, 0x9a, 0x46,
-	0x06, 0x58, 0xbd, 0x98, 0x84, 0xdb, 0xcf, 0x53, 0x1b, 0x1c, 0x63, 0x70, 0xe6, 0xd9, 0xc8, 0xce,
-	0x61, 0x26, 0xbb, 0x5a, 0x76, 0x2f, 0x43, 0x33, 0xe6, 0x6a, 0x85, 0xfb, 0xff, 0xcd, 0xa7, 0xcb,
-	0x79, 0xf8, 0xe5, 0xf7, 0xdf, 0x6f, 0x39, 0x5e, 0x5c, 0x16, 0x67, 0x6b, 0x3d, 0xd3, 0x51, 0xa2,
-	0x26, 0x92, 0xd9, 0x68, 0xd3, 0x28, 0xb1, 0xcf, 0x06, 0xcc, 0x64, 0xb7, 0x3b, 0xa0, 0x3c, 0xe6,
-	0x54, 0x03, 0xca, 0xe3, 0xcf, 0x12, 0x2b, 0xaf, 0x96, 0x1e, 0x5d, 0xa2, 0x2c, 0x3e, 0xf6, 0xf6,
-	0xfd, 0x89, 0xb5, 0x21, 0xdf, 0xbb, 0x23, 0x5b, 0xce, 0xd0, 0x0f, 0x5f, 0xb7, 0x30, 0x6a, 0xac,
-	0xae, 0x1a, 0xbb, 0xb2, 0xda, 0x77, 0x03, 0x6e, 0x0d, 0xde, 0x93, 0xf1, 0x41, 0xcd, 0x51, 0x83,
-	0x14, 0x56, 0x2e, 0x41, 0xa4, 0x63, 0x6f, 0xc5, 0x8d, 0x6c, 0xb0, 0xf5, 0x2b, 0x36, 0x22, 0xfa,
-	0x8e, 0xa0, 0x8a, 0xf1, 0xec, 0xeb, 0xc4, 0x45, 0xfd, 0x4f, 0x8e, 0xfd, 0x32, 0x60, 0xba, 0xeb,
-	0x8d, 0xe2, 0x01, 0xc0, 0x91, 0x8f, 0x92, 0xbf, 0x88, 0x74, 0xd9, 0x62, 0x4b, 0x6b, 0x9f, 0x36,
-	0x85, 0x88, 0x5a, 0x29, 0x27, 0xbd, 0x38, 0x78, 0x56, 0x78, 0xd0, 0xff, 0x2f, 0x3b, 0x1e, 0xd9,
-	0x21, 0xd1, 0x76, 0xf2, 0x6c, 0xb8, 0x81, 0x0a, 0x7d, 0x32, 0x6d, 0xd5, 0x29, 0xbd, 0x06, 0x56,
-	0xf7, 0x2d, 0xbb, 0x85, 0xbc, 0x6a, 0x56, 0xf8, 0xa1, 0x67, 0x63, 0x64, 0xe0, 0xed, 0x2e, 0xa5,
-	0xeb, 0xe9, 0x56, 0xd8, 0x8c, 0x90, 0x22, 0x29, 0x3d, 0x51, 0x81, 0x6b, 0x75, 0x90, 0x32, 0x62,
-	0xa2, 0xd9, 0x56, 0x4d, 0xd1, 0xb1, 0x48, 0x63, 0x20, 0x0e, 0x0f, 0x76, 0xf6, 0x5e, 0x1e, 0xef,
-	0x55, 0x27, 0xd6, 0xcc, 0x4a, 0x29, 0x67, 0xe4, 0xaa, 0xb3, 0x96, 0xef, 0xb7, 0x3d, 0x3b, 0x7e,
-	0x71, 0xc4, 0x3b, 0x52, 0x72, 0x73, 0x24, 0xd2, 0xd8, 0x82, 0x89, 0x5a, 0xa5, 0xc6, 0x6a, 0x50,
-	0x6a, 0xa0, 0x0e, 0x03, 0x89, 0x0e, 0x3f, 0x6f, 0xa1, 0xe4, 0xba, 0x85, 0x3c, 0x40, 0x52, 0x61,
-	0x60, 0x23, 0x77, 0x14, 0x12, 0x97, 0x4a, 0x73, 0xfc, 0xe0, 0x91, 0x36, 0xd9, 0x14, 0x4c, 0xfe,
-	0xc8, 0x19, 0x37, 0x82, 0xa7, 0xb0, 0xd4, 0x5f, 0x06, 0xdf, 0x55, 0x76, 0x18, 0xad, 0x2e, 0x66,
-	0x67, 0x2b, 0xe3, 0x57, 0x23, 0xc8, 0xd3, 0x28, 0x1c, 0x65, 0x93, 0x78, 0xc3, 0x87, 0x52, 0x99,
-	0xb9, 0xfc, 0x53, 0x57, 0xf8, 0xcd, 0x9f, 0xb9, 0x7c, 0xc4, 0x1f, 0xd3, 0x37, 0xa7, 0xe2, 0x27,
-	0x73, 0xfd, 0x5f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xb6, 0x27, 0x68, 0xa0, 0xb3, 0x05, 0x00, 0x00,
+	// 1278 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x57, 0x5d, 0x6f, 0xdc, 0x44,
+	0x17, 0x96, 0x77, 0xd3, 0x26, 0x7b, 0x92, 0xa6, 0xed, 0xa4, 0xd9, 0x6e, 0xdd, 0xbe, 0xad, 0xeb,
+	0xbe, 0xef, 0xdb, 0x34, 0xed, 0xda, 0xe9, 0x36, 0x41, 0x22, 0x11, 0x52, 0x43, 0xd3, 0x8f, 0x88,
+	0x42, 0x91, 0x53, 0x40, 0xe2, 0x66, 0xe5, 0xb5, 0x4f, 0xbc, 0xa6, 0xbb, 0x1e, 0xe3, 0x19, 0xb7,
+	0xa9, 0x50, 0x11, 0x1f, 0x12, 0x12, 0x82, 0x0b, 0x04, 0x48, 0xa0, 0xfe, 0x04, 0x2e, 0xf9, 0x2b,
+	0xdc, 0xc0, 0x0d, 0x57, 0xfc, 0x10, 0xe4, 0xf1, 0x78, 0xd7, 0xeb, 0xfd, 0x60, 0x97, 0x5e, 0x65,
+	0x67, 0xce, 0x39, 0xcf, 0xf3, 0xcc, 0x33, 0xf6, 0x39, 0x0e, 0x10, 0x3b, 0xf4, 0xcd, 0xc3, 0x88,
+	0x06, 0x1c, 0x03, 0xd7, 0x08, 0x23, 0xca, 0x29, 0xa9, 0xd0, 0x10, 0x83, 0xae, 0xcd, 0x9d, 0xb6,
+	0x2a, 0xc2, 0x5d, 0x64, 0xcc, 0xf6, 0x90, 0xa5, 0x61, 0xf5, 0x82, 0x47, 0xa9, 0xd7, 0x41, 0x33,
+	0x09, 0xd9, 0x41, 0x40, 0xb9, 0xcd, 0x7d, 0x1a, 0x64, 0xd1, 0x8b, 0x32, 0x2a, 0x56, 0xad, 0xf8,
+	0xd0, 0x74, 0xe3, 0x48, 0x24, 0xc8, 0xf8, 0x0d, 0xf1, 0xc7, 0xa9, 0x7b, 0x18, 0xd4, 0xd9, 0x33,
+	0xdb, 0xf3, 0x30, 0x32, 0x69, 0x28, 0x10, 0x86, 0xd1, 0xf4, 0xdf, 0x15, 0x58, 0xb9, 0x13, 0xa1,
+	0xcd, 0xf1, 0xb1, 0xef, 0x3c, 0x41, 0x6e, 0xe1, 0xc7, 0x31, 0x32, 0x4e, 0xae, 0xc1, 0x71, 0x2e,
+	0x36, 0x6a, 0x8a, 0xa6, 0xac, 0x2d, 0x36, 0x4e, 0x1b, 0x3d, 0xcd, 0x86, 0xcc, 0x94, 0x09, 0xa4,
+	0x01, 0xab, 0x7e, 0xe0, 0x74, 0x62, 0x17, 0x9b, 0x22, 0x8e, 0x6e, 0x33, 0xa4, 0xb4, 0xc3, 0x6a,
+	0x25, 0x4d, 0x59, 0x5b, 0xb0, 0x56, 0x64, 0xf0, 0xed, 0x34, 0xf6, 0x6e, 0x12, 0x22, 0xaf, 0x03,
+	0xe0, 0x51, 0xe8, 0xa7, 0xc2, 0x6b, 0x65, 0x41, 0x71, 0xce, 0x48, 0x4f, 0x66, 0x64, 0x27, 0x33,
+	0xf6, 0xe4, 0xc9, 0xac, 0x5c, 0x32, 0xb9, 0x0a, 0x27, 0x7d, 0x17, 0xbb, 0x21, 0xe5, 0x18, 0x38,
+	0xcf, 0x9b, 0x4f, 0xf0, 0x79, 0x6d, 0x4e, 0x53, 0xd6, 0x2a, 0xd6, 0x72, 0x6e, 0xfb, 0x2d, 0x7c,
+	0xae, 0xef, 0xc0, 0x62, 0x8e, 0x93, 0xd4, 0x60, 0x3e, 0x8c, 0xe8, 0xa1, 0xdf, 0x41, 0x71, 0xa4,
+	0x8a, 0x95, 0x2d, 0x09, 0x81, 0xb9, 0x44, 0xb0, 0xd0, 0x5b, 0xb1, 0xc4, 0x6f, 0xfd, 0x53, 0x38,
+	0x33, 0x68, 0x0b, 0x0b, 0x69, 0xc0, 0x70, 0x16, 0x5f, 0x76, 0xe0, 0x44, 0xd1, 0x8f, 0xf2, 0xda,
+	0x62, 0xa3, 0x9a, 0xab, 0xc8, 0xe9, 0xb3, 0x96, 0xba, 0xfd, 0x05, 0x2b, 0xf2, 0xb3, 0xec, 0x5e,
+	0xae, 0xc3, 0x7c, 0x0a, 0xcf, 0x6a, 0x8a, 0x56, 0x1e, 0x2d, 0x20, 0xcb, 0x28, 0xb8, 0x5c, 0x9a,
+	0xc1, 0x65, 0x7d, 0x0f, 0x56, 0x0b, 0xfc, 0xd2, 0x80, 0x59, 0x04, 0xe8, 0xb7, 0x61, 0xe5, 0xbd,
+	0xd0, 0x7d, 0x85, 0x87, 0x4b, 0xdf, 0x85, 0x33, 0x83, 0x08, 0x33, 0xdf, 0x83, 0xde, 0x80, 0x95,
+	0x3d, 0xec, 0x60, 0x51, 0xc4, 0x79, 0xa8, 0xa4, 0x09, 0x4d, 0xdf, 0x95, 0x4f, 0xc4, 0x42, 0xba,
+	0xb1, 0xef, 0xea, 0x55, 0x38, 0x33, 0x58, 0x93, 0xd2, 0xea, 0x5b, 0x83, 0xfb, 0xbd, 0x6b, 0xf9,
+	0x0f, 0x40, 0x0f, 0x2c, 0x35, 0xa6, 0x62, 0x55, 0x32, 0x34, 0xa6, 0x9f, 0x85, 0xd5, 0x42, 0x99,
+	0xc4, 0xbb, 0x09, 0xa7, 0x1e, 0xa0, 0x1d, 0xf1, 0x16, 0xda, 0x7c, 0x4a, 0xac, 0x07, 0x70, 0x3a,
+	0x57, 0x22, 0xed, 0xb8, 0x05, 0xd5, 0x80, 0xf2, 0xa6, 0x1f, 0xb8, 0x78, 0x84, 0x6e, 0x73, 0xa8,
+	0x7e, 0x25, 0xa0, 0x7c, 0x3f, 0x0d, 0x3e, 0xee, 0x21, 0x99, 0x70, 0xea, 0x3e, 0xf2, 0x19, 0x5c,
+	0xd9, 0x84, 0xd5, 0x5e, 0xc1, 0x01, 0xb7, 0x39, 0x4e, 0x55, 0x75, 0x1f, 0xaa, 0xc5, 0x2a, 0xa9,
+	0xba, 0x0e, 0xc7, 0x58, 0xb2, 0x21, 0x4a, 0x96, 0x1b, 0x67, 0x87, 0xee, 0xd0, 0x48, 0xf3, 0xd3,
+	0x2c, 0x7d, 0xab, 0x00, 0x14, 0xb3, 0xa9, 0xf8, 0x7f, 0x56, 0xe0, 0xec, 0x50, 0x9d, 0x54, 0x50,
+	0x83, 0x79, 0xe9, 0x99, 0x28, 0x5b, 0xb0, 0xb2, 0x25, 0x51, 0x61, 0x21, 0x8c, 0x68, 0x48, 0x19,
+	0xba, 0xb2, 0x91, 0xf5, 0xd6, 0x49, 0xcc, 0x66, 0xcc, 0xf7, 0x02, 0x74, 0x45, 0xef, 0x5a, 0xb0,
+	0x7a, 0x6b, 0x72, 0x1d, 0xca, 0xb6, 0x87, 0xb5, 0xb9, 0x7f, 0x7a, 0xd9, 0x92, 0x2c, 0x69, 0xe8,
+	0xae, 0xa8, 0xed, 0x62, 0xc0, 0xa7, 0x3b, 0xd0, 0x23, 0xa8, 0x16, 0xab, 0xe4, 0x71, 0xb6, 0x00,
+	0xec, 0xde, 0xb6, 0x7c, 0x33, 0x56, 0x73, 0xae, 0xf6, 0x6b, 0xac, 0x5c, 0xa2, 0xfe, 0x93, 0x02,
+	0xb5, 0x0f, 0x6c, 0x9f, 0xdf, 0xa3, 0x51, 0x2e, 0x63, 0x0a, 0x29, 0x64, 0x0b, 0xaa, 0x7d, 0x9c,
+	0xe6, 0xa1, 0x1f, 0x78, 0x18, 0x85, 0x91, 0x1f, 0x70, 0xd9, 0x4c, 0x57, 0xfb, 0xd1, 0x7b, 0xfd,
+	0x60, 0xd2, 0xc3, 0xb9, 0xdf, 0x45, 0x1a, 0xf3, 0x26, 0x43, 0x87, 0x06, 0x2e, 0x13, 0x3e, 0x1e,
+	0xb3, 0x96, 0xe5, 0xf6, 0x41, 0xba, 0xab, 0x7f, 0xad, 0xc0, 0xb9, 0x11, 0xca, 0x5e, 0xe9, 0xb8,
+	0xff, 0x52, 0x74, 0xe3, 0xcf, 0x45, 0x38, 0x79, 0x4f, 0x0e, 0xf2, 0x03, 0x8c, 0x9e, 0xfa, 0x0e,
+	0x92, 0x67, 0xb0, 0x94, 0x6f, 0x93, 0xe4, 0x62, 0x8e, 0x7d, 0xc4, 0x58, 0x55, 0x2f, 0x8d, 0x8d,
+	0xcb, 0x86, 0xf0, 0xff, 0x2f, 0x7e, 0xfb, 0xeb, 0x87, 0x92, 0xa6, 0x9f, 0x37, 0x9f, 0xde, 0xec,
+	0x7d, 0x36, 0xb0, 0x94, 0xcd, 0x94, 0x6d, 0x75, 0x5b, 0x59, 0x27, 0x5f, 0x29, 0x70, 0x22, 0x0f,
+	0xc0, 0xc8, 0x38, 0xe8, 0xec, 0x99, 0x52, 0xb5, 0xf1, 0x09, 0x92, 0xbc, 0x21, 0xc8, 0x6f, 0xe8,
+	0x57, 0x27, 0x91, 0xb7, 0x12, 0x80, 0xb4, 0x3e, 0x11, 0xf2, 0xa5, 0x02, 0x4b, 0xf9, 0x0e, 0x3d,
+	0x60, 0xc1, 0x88, 0xe6, 0xaf, 0x5e, 0x1a, 0x1b, 0x1f, 0x54, 0xd1, 0x98, 0xa4, 0xc2, 0xfc, 0x24,
+	0xfd, 0x61, 0xf8, 0xee, 0x8b, 0x44, 0xc5, 0x67, 0x0a, 0x2c, 0xe5, 0x3b, 0xec, 0x80, 0x8a, 0x11,
+	0xdd, 0x5f, 0xbd, 0x34, 0x36, 0x2e, 0x55, 0x98, 0x42, 0xc5, 0xb5, 0xf5, 0x69, 0x54, 0x34, 0x7d,
+	0xf7, 0x05, 0xf9, 0x5c, 0x81, 0x13, 0x79, 0xa4, 0xc1, 0x1b, 0x19, 0x35, 0x35, 0x54, 0x6d, 0x7c,
+	0x82, 0x54, 0x51, 0x17, 0x2a, 0xae, 0xea, 0xfa, 0xa4, 0x1b, 0x71, 0x45, 0x69, 0x62, 0xc3, 0x11,
+	0x54, 0x7a, 0xb3, 0x81, 0x9c, 0xcf, 0xa1, 0x17, 0x87, 0x8c, 0x7a, 0x61, 0x74, 0x50, 0xd2, 0x6e,
+	0x08, 0xda, 0x75, 0xfd, 0x7f, 0x93, 0x68, 0xdb, 0x59, 0x59, 0xc2, 0xdc, 0x81, 0x4a, 0xaf, 0xc7,
+	0x0e, 0x30, 0x17, 0x27, 0x8c, 0x3a, 0x3c, 0xa9, 0x33, 0xaf, 0xc9, 0xd4, 0x5e, 0x7f, 0xab, 0xc0,
+	0xf2, 0xe0, 0x4c, 0x21, 0xda, 0x28, 0xce, 0xfc, 0x90, 0x52, 0x2f, 0x4f, 0xc8, 0xc8, 0xc6, 0xbb,
+	0x10, 0x62, 0x92, 0xfa, 0x94, 0x42, 0x4c, 0x31, 0x98, 0xc8, 0x77, 0x0a, 0x9c, 0x2c, 0x4c, 0x18,
+	0x32, 0x96, 0xad, 0x37, 0xb5, 0x54, 0x7d, 0x52, 0x8a, 0x54, 0xf4, 0x9a, 0x50, 0xb4, 0x41, 0x8c,
+	0x59, 0x14, 0xc5, 0x8c, 0xfc, 0x98, 0x3a, 0x94, 0x1b, 0x12, 0x45, 0x87, 0x86, 0xa7, 0x8e, 0x7a,
+	0x79, 0x42, 0x86, 0xd4, 0xb3, 0x23, 0xf4, 0x6c, 0x91, 0x5b, 0xd3, 0xea, 0xe9, 0xf7, 0x52, 0xb6,
+	0xa1, 0x90, 0x97, 0x0a, 0x9c, 0x1e, 0xea, 0xe7, 0xe4, 0x4a, 0x8e, 0x77, 0xdc, 0x1c, 0x52, 0xff,
+	0x3b, 0x39, 0x49, 0xea, 0xdb, 0x16, 0xfa, 0x36, 0x49, 0x63, 0x76, 0x7d, 0x6f, 0x7e, 0x53, 0xfe,
+	0x7e, 0xf7, 0x8f, 0x12, 0xf9, 0x55, 0x81, 0x85, 0xac, 0xcf, 0xeb, 0xfb, 0x00, 0x8f, 0x42, 0x0c,
+	0x34, 0xf1, 0xa5, 0x4e, 0xaa, 0x6d, 0xce, 0x43, 0xb6, 0x6d, 0x9a, 0x89, 0x94, 0x7a, 0xaa, 0xc5,
+	0xc5, 0xa7, 0xea, 0x95, 0xfe, 0xba, 0xee, 0xfa, 0xcc, 0x89, 0x19, 0xbb, 0x9d, 0x4e, 0x7e, 0x2f,
+	0xa2, 0x71, 0xc8, 0x0c, 0x87, 0x76, 0xd7, 0xdf, 0x07, 0xb2, 0x1b, 0xda, 0x4e, 0x1b, 0xb5, 0x86,
+	0xb1, 0xa1, 0x3d, 0xf4, 0x1d, 0x4c, 0x66, 0xd8, 0xed, 0x0c, 0xd2, 0xf3, 0x79, 0x3b, 0x6e, 0x25,
+	0x99, 0x66, 0x5a, 0x7a, 0x48, 0x23, 0xcf, 0xee, 0x22, 0xcb, 0x91, 0x99, 0xad, 0x0e, 0x6d, 0x99,
+	0x5d, 0x9b, 0x71, 0x8c, 0xcc, 0x87, 0xfb, 0x77, 0xee, 0xbe, 0x73, 0x70, 0xb7, 0x51, 0xbe, 0x69,
+	0x6c, 0xac, 0x97, 0x94, 0x52, 0xe3, 0x94, 0x1d, 0x86, 0x1d, 0xdf, 0x11, 0xdf, 0x17, 0xe6, 0x47,
+	0x8c, 0x06, 0xdb, 0x43, 0x3b, 0xd6, 0x0e, 0x94, 0x37, 0x37, 0x36, 0xc9, 0x26, 0xac, 0x5b, 0xc8,
+	0xe3, 0x28, 0x40, 0x57, 0x7b, 0xd6, 0xc6, 0x40, 0xe3, 0x6d, 0xd4, 0x22, 0x64, 0x34, 0x8e, 0x1c,
+	0xd4, 0x5c, 0x8a, 0x4c, 0x0b, 0x28, 0xd7, 0xf0, 0xc8, 0x67, 0xdc, 0x20, 0xc7, 0x61, 0xee, 0x65,
+	0x49, 0x99, 0x8f, 0xde, 0x80, 0x5a, 0xdf, 0x0c, 0x6d, 0x8f, 0x3a, 0x71, 0xe2, 0x5b, 0xfa, 0x6f,
+	0xd9, 0xe5, 0xd1, 0xd6, 0x98, 0xcc, 0xe7, 0x68, 0xba, 0xd4, 0x61, 0xe6, 0x87, 0x5a, 0x21, 0x94,
+	0x3b, 0x57, 0xf8, 0xc4, 0x33, 0xc3, 0xd6, 0x2f, 0xa5, 0x4a, 0x82, 0x2f, 0xe0, 0x5b, 0xc7, 0xc5,
+	0x37, 0xd3, 0xad, 0xbf, 0x07, 0x00, 0xb7, 0x96, 0x72, 0x77, 0x41, 0x0f, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -369,17 +1148,48 @@ type FrontendServiceClient interface {
 	// A ticket is considered as ready for matchmaking once it is created.
 	//   - If a TicketId exists in a Ticket request, an auto-generated TicketId will override this field.
 	//   - If SearchFields exist in a Ticket, CreateTicket will also index these fields such that one can query the ticket with query.QueryTickets function.
+	//   - Tickets breaking the rules configured under frontend.validation, or rejected by its TicketValidator webhook,
+	//     fail with InvalidArgument, as do CreateTickets and UpdateTicket.
 	CreateTicket(ctx context.Context, in *CreateTicketRequest, opts ...grpc.CallOption) (*CreateTicketResponse, error)
+	// CreateTickets creates multiple Tickets at once, as with CreateTicket.  Either all or none of the Tickets are created.
+	//   - Saves a round trip to state storage per Ticket when creating Tickets in bulk, eg. in load tests.
+	CreateTickets(ctx context.Context, in *CreateTicketsRequest, opts ...grpc.CallOption) (*CreateTicketsResponse, error)
+	// UpdateTicket replaces the search fields, extensions, player ids and avoid ids of a waiting Ticket, eg. when the
+	// player's latencies or party change, without it losing its place in the queue.
+	//   - The Ticket is rewritten and moved to the indices of its new SearchFields atomically.
+	//   - Tickets which are already assigned fail with FailedPrecondition.
+	//   - A reservation made with BackendService.ReserveTickets is kept.
+	UpdateTicket(ctx context.Context, in *UpdateTicketRequest, opts ...grpc.CallOption) (*UpdateTicketResponse, error)
 	// DeleteTicket immediately stops Open Match from using the Ticket for matchmaking and removes the Ticket from state storage.
 	// The client must delete the Ticket when finished matchmaking with it.
 	//   - If SearchFields exist in a Ticket, DeleteTicket will deindex the fields lazily.
 	// Users may still be able to assign/get a ticket after calling DeleteTicket on it.
 	DeleteTicket(ctx context.Context, in *DeleteTicketRequest, opts ...grpc.CallOption) (*DeleteTicketResponse, error)
+	// DeleteTickets deletes multiple Tickets at once, as with DeleteTicket, eg. when a party disbands.
+	//   - The Tickets are deindexed in a single transaction, and deleted from state storage lazily in a single round trip.
+	DeleteTickets(ctx context.Context, in *DeleteTicketsRequest, opts ...grpc.CallOption) (*DeleteTicketsResponse, error)
+	// Heartbeat keeps Tickets in matchmaking while frontend.heartbeat.timeout is set.  Tickets must then be heartbeated
+	// more often than the timeout, or they're removed from the index, so that Tickets of crashed clients don't linger
+	// in the pool until they expire.  UpdateTicket counts as a heartbeat.
+	//   - Tickets which are no longer in matchmaking are returned, and must be recreated to be matched.
+	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
 	// GetTicket get the Ticket associated with the specified TicketId.
 	GetTicket(ctx context.Context, in *GetTicketRequest, opts ...grpc.CallOption) (*Ticket, error)
+	// GetTicketState gets the lifecycle state of the specified TicketId, so that clients can tell a Ticket waiting
+	// for a match from one proposed in a match which isn't assigned yet.
+	//   - Tickets which expired are reported as EXPIRED for storage.expiredTicketStateTTL, if janitor.cleanUpExpiredTickets
+	//     is enabled, and are not found afterwards.
+	GetTicketState(ctx context.Context, in *GetTicketStateRequest, opts ...grpc.CallOption) (*GetTicketStateResponse, error)
+	// GetTicketStatus gets queue diagnostics of the specified TicketId, eg. to estimate wait times or debug Tickets
+	// which are never matched, without access to state storage.
+	GetTicketStatus(ctx context.Context, in *GetTicketStatusRequest, opts ...grpc.CallOption) (*GetTicketStatusResponse, error)
 	// GetAssignments stream back Assignment of the specified TicketId if it is updated.
-	//   - If the Assignment is not updated, GetAssignment will retry using the configured backoff strategy.
+	//   - If the Assignment is not updated, GetAssignment waits for an update to be published.
 	GetAssignments(ctx context.Context, in *GetAssignmentsRequest, opts ...grpc.CallOption) (FrontendService_GetAssignmentsClient, error)
+	// WaitForAssignment waits for the Assignment of the specified TicketId to change, then returns it.
+	// It is a long-polling alternative to GetAssignments for clients which can't hold a stream open.
+	//   - If the Assignment doesn't change within the timeout, the current Assignment is returned.
+	WaitForAssignment(ctx context.Context, in *WaitForAssignmentRequest, opts ...grpc.CallOption) (*WaitForAssignmentResponse, error)
 }
 
 type frontendServiceClient struct {
@@ -399,6 +1209,24 @@ func (c *frontendServiceClient) CreateTicket(ctx context.Context, in *CreateTick
 	return out, nil
 }
 
+func (c *frontendServiceClient) CreateTickets(ctx context.Context, in *CreateTicketsRequest, opts ...grpc.CallOption) (*CreateTicketsResponse, error) {
+	out := new(CreateTicketsResponse)
+	err := c.cc.Invoke(ctx, "/openmatch.FrontendService/CreateTickets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *frontendServiceClient) UpdateTicket(ctx context.Context, in *UpdateTicketRequest, opts ...grpc.CallOption) (*UpdateTicketResponse, error) {
+	out := new(UpdateTicketResponse)
+	err := c.cc.Invoke(ctx, "/openmatch.FrontendService/UpdateTicket", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *frontendServiceClient) DeleteTicket(ctx context.Context, in *DeleteTicketRequest, opts ...grpc.CallOption) (*DeleteTicketResponse, error) {
 	out := new(DeleteTicketResponse)
 	err := c.cc.Invoke(ctx, "/openmatch.FrontendService/DeleteTicket", in, out, opts...)
@@ -408,6 +1236,24 @@ func (c *frontendServiceClient) DeleteTicket(ctx context.Context, in *DeleteTick
 	return out, nil
 }
 
+func (c *frontendServiceClient) DeleteTickets(ctx context.Context, in *DeleteTicketsRequest, opts ...grpc.CallOption) (*DeleteTicketsResponse, error) {
+	out := new(DeleteTicketsResponse)
+	err := c.cc.Invoke(ctx, "/openmatch.FrontendService/DeleteTickets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *frontendServiceClient) Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error) {
+	out := new(HeartbeatResponse)
+	err := c.cc.Invoke(ctx, "/openmatch.FrontendService/Heartbeat", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *frontendServiceClient) GetTicket(ctx context.Context, in *GetTicketRequest, opts ...grpc.CallOption) (*Ticket, error) {
 	out := new(Ticket)
 	err := c.cc.Invoke(ctx, "/openmatch.FrontendService/GetTicket", in, out, opts...)
@@ -417,6 +1263,24 @@ func (c *frontendServiceClient) GetTicket(ctx context.Context, in *GetTicketRequ
 	return out, nil
 }
 
+func (c *frontendServiceClient) GetTicketState(ctx context.Context, in *GetTicketStateRequest, opts ...grpc.CallOption) (*GetTicketStateResponse, error) {
+	out := new(GetTicketStateResponse)
+	err := c.cc.Invoke(ctx, "/openmatch.FrontendService/GetTicketState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *frontendServiceClient) GetTicketStatus(ctx context.Context, in *GetTicketStatusRequest, opts ...grpc.CallOption) (*GetTicketStatusResponse, error) {
+	out := new(GetTicketStatusResponse)
+	err := c.cc.Invoke(ctx, "/openmatch.FrontendService/GetTicketStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *frontendServiceClient) GetAssignments(ctx context.Context, in *GetAssignmentsRequest, opts ...grpc.CallOption) (FrontendService_GetAssignmentsClient, error) {
 	stream, err := c.cc.NewStream(ctx, &_FrontendService_serviceDesc.Streams[0], "/openmatch.FrontendService/GetAssignments", opts...)
 	if err != nil {
@@ -449,23 +1313,63 @@ func (x *frontendServiceGetAssignmentsClient) Recv() (*GetAssignmentsResponse, e
 	return m, nil
 }
 
+func (c *frontendServiceClient) WaitForAssignment(ctx context.Context, in *WaitForAssignmentRequest, opts ...grpc.CallOption) (*WaitForAssignmentResponse, error) {
+	out := new(WaitForAssignmentResponse)
+	err := c.cc.Invoke(ctx, "/openmatch.FrontendService/WaitForAssignment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // FrontendServiceServer is the server API for FrontendService service.
 type FrontendServiceServer interface {
 	// CreateTicket assigns an unique TicketId to the input Ticket and record it in state storage.
 	// A ticket is considered as ready for matchmaking once it is created.
 	//   - If a TicketId exists in a Ticket request, an auto-generated TicketId will override this field.
 	//   - If SearchFields exist in a Ticket, CreateTicket will also index these fields such that one can query the ticket with query.QueryTickets function.
+	//   - Tickets breaking the rules configured under frontend.validation, or rejected by its TicketValidator webhook,
+	//     fail with InvalidArgument, as do CreateTickets and UpdateTicket.
 	CreateTicket(context.Context, *CreateTicketRequest) (*CreateTicketResponse, error)
+	// CreateTickets creates multiple Tickets at once, as with CreateTicket.  Either all or none of the Tickets are created.
+	//   - Saves a round trip to state storage per Ticket when creating Tickets in bulk, eg. in load tests.
+	CreateTickets(context.Context, *CreateTicketsRequest) (*CreateTicketsResponse, error)
+	// UpdateTicket replaces the search fields, extensions, player ids and avoid ids of a waiting Ticket, eg. when the
+	// player's latencies or party change, without it losing its place in the queue.
+	//   - The Ticket is rewritten and moved to the indices of its new SearchFields atomically.
+	//   - Tickets which are already assigned fail with FailedPrecondition.
+	//   - A reservation made with BackendService.ReserveTickets is kept.
+	UpdateTicket(context.Context, *UpdateTicketRequest) (*UpdateTicketResponse, error)
 	// DeleteTicket immediately stops Open Match from using the Ticket for matchmaking and removes the Ticket from state storage.
 	// The client must delete the Ticket when finished matchmaking with it.
 	//   - If SearchFields exist in a Ticket, DeleteTicket will deindex the fields lazily.
 	// Users may still be able to assign/get a ticket after calling DeleteTicket on it.
 	DeleteTicket(context.Context, *DeleteTicketRequest) (*DeleteTicketResponse, error)
+	// DeleteTickets deletes multiple Tickets at once, as with DeleteTicket, eg. when a party disbands.
+	//   - The Tickets are deindexed in a single transaction, and deleted from state storage lazily in a single round trip.
+	DeleteTickets(context.Context, *DeleteTicketsRequest) (*DeleteTicketsResponse, error)
+	// Heartbeat keeps Tickets in matchmaking while frontend.heartbeat.timeout is set.  Tickets must then be heartbeated
+	// more often than the timeout, or they're removed from the index, so that Tickets of crashed clients don't linger
+	// in the pool until they expire.  UpdateTicket counts as a heartbeat.
+	//   - Tickets which are no longer in matchmaking are returned, and must be recreated to be matched.
+	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
 	// GetTicket get the Ticket associated with the specified TicketId.
 	GetTicket(context.Context, *GetTicketRequest) (*Ticket, error)
+	// GetTicketState gets the lifecycle state of the specified TicketId, so that clients can tell a Ticket waiting
+	// for a match from one proposed in a match which isn't assigned yet.
+	//   - Tickets which expired are reported as EXPIRED for storage.expiredTicketStateTTL, if janitor.cleanUpExpiredTickets
+	//     is enabled, and are not found afterwards.
+	GetTicketState(context.Context, *GetTicketStateRequest) (*GetTicketStateResponse, error)
+	// GetTicketStatus gets queue diagnostics of the specified TicketId, eg. to estimate wait times or debug Tickets
+	// which are never matched, without access to state storage.
+	GetTicketStatus(context.Context, *GetTicketStatusRequest) (*GetTicketStatusResponse, error)
 	// GetAssignments stream back Assignment of the specified TicketId if it is updated.
-	//   - If the Assignment is not updated, GetAssignment will retry using the configured backoff strategy.
+	//   - If the Assignment is not updated, GetAssignment waits for an update to be published.
 	GetAssignments(*GetAssignmentsRequest, FrontendService_GetAssignmentsServer) error
+	// WaitForAssignment waits for the Assignment of the specified TicketId to change, then returns it.
+	// It is a long-polling alternative to GetAssignments for clients which can't hold a stream open.
+	//   - If the Assignment doesn't change within the timeout, the current Assignment is returned.
+	WaitForAssignment(context.Context, *WaitForAssignmentRequest) (*WaitForAssignmentResponse, error)
 }
 
 // UnimplementedFrontendServiceServer can be embedded to have forward compatible implementations.
@@ -475,15 +1379,36 @@ type UnimplementedFrontendServiceServer struct {
 func (*UnimplementedFrontendServiceServer) CreateTicket(ctx context.Context, req *CreateTicketRequest) (*CreateTicketResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateTicket not implemented")
 }
+func (*UnimplementedFrontendServiceServer) CreateTickets(ctx context.Context, req *CreateTicketsRequest) (*CreateTicketsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTickets not implemented")
+}
+func (*UnimplementedFrontendServiceServer) UpdateTicket(ctx context.Context, req *UpdateTicketRequest) (*UpdateTicketResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTicket not implemented")
+}
 func (*UnimplementedFrontendServiceServer) DeleteTicket(ctx context.Context, req *DeleteTicketRequest) (*DeleteTicketResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteTicket not implemented")
 }
+func (*UnimplementedFrontendServiceServer) DeleteTickets(ctx context.Context, req *DeleteTicketsRequest) (*DeleteTicketsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTickets not implemented")
+}
+func (*UnimplementedFrontendServiceServer) Heartbeat(ctx context.Context, req *HeartbeatRequest) (*HeartbeatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
+}
 func (*UnimplementedFrontendServiceServer) GetTicket(ctx context.Context, req *GetTicketRequest) (*Ticket, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetTicket not implemented")
 }
+func (*UnimplementedFrontendServiceServer) GetTicketState(ctx context.Context, req *GetTicketStateRequest) (*GetTicketStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTicketState not implemented")
+}
+func (*UnimplementedFrontendServiceServer) GetTicketStatus(ctx context.Context, req *GetTicketStatusRequest) (*GetTicketStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTicketStatus not implemented")
+}
 func (*UnimplementedFrontendServiceServer) GetAssignments(req *GetAssignmentsRequest, srv FrontendService_GetAssignmentsServer) error {
 	return status.Errorf(codes.Unimplemented, "method GetAssignments not implemented")
 }
+func (*UnimplementedFrontendServiceServer) WaitForAssignment(ctx context.Context, req *WaitForAssignmentRequest) (*WaitForAssignmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WaitForAssignment not implemented")
+}
 
 func RegisterFrontendServiceServer(s *grpc.Server, srv FrontendServiceServer) {
 	s.RegisterService(&_FrontendService_serviceDesc, srv)
@@ -507,6 +1432,42 @@ func _FrontendService_CreateTicket_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FrontendService_CreateTickets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTicketsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FrontendServiceServer).CreateTickets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/openmatch.FrontendService/CreateTickets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FrontendServiceServer).CreateTickets(ctx, req.(*CreateTicketsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FrontendService_UpdateTicket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTicketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FrontendServiceServer).UpdateTicket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/openmatch.FrontendService/UpdateTicket",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FrontendServiceServer).UpdateTicket(ctx, req.(*UpdateTicketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _FrontendService_DeleteTicket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteTicketRequest)
 	if err := dec(in); err != nil {
@@ -525,6 +1486,42 @@ func _FrontendService_DeleteTicket_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FrontendService_DeleteTickets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTicketsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FrontendServiceServer).DeleteTickets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/openmatch.FrontendService/DeleteTickets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FrontendServiceServer).DeleteTickets(ctx, req.(*DeleteTicketsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FrontendService_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FrontendServiceServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/openmatch.FrontendService/Heartbeat",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FrontendServiceServer).Heartbeat(ctx, req.(*HeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _FrontendService_GetTicket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetTicketRequest)
 	if err := dec(in); err != nil {
@@ -543,6 +1540,42 @@ func _FrontendService_GetTicket_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FrontendService_GetTicketState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTicketStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FrontendServiceServer).GetTicketState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/openmatch.FrontendService/GetTicketState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FrontendServiceServer).GetTicketState(ctx, req.(*GetTicketStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FrontendService_GetTicketStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTicketStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FrontendServiceServer).GetTicketStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/openmatch.FrontendService/GetTicketStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FrontendServiceServer).GetTicketStatus(ctx, req.(*GetTicketStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _FrontendService_GetAssignments_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(GetAssignmentsRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -564,6 +1597,24 @@ func (x *frontendServiceGetAssignmentsServer) Send(m *GetAssignmentsResponse) er
 	return x.ServerStream.SendMsg(m)
 }
 
+func _FrontendService_WaitForAssignment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WaitForAssignmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FrontendServiceServer).WaitForAssignment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/openmatch.FrontendService/WaitForAssignment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FrontendServiceServer).WaitForAssignment(ctx, req.(*WaitForAssignmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _FrontendService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "openmatch.FrontendService",
 	HandlerType: (*FrontendServiceServer)(nil),
@@ -572,14 +1623,42 @@ var _FrontendService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CreateTicket",
 			Handler:    _FrontendService_CreateTicket_Handler,
 		},
+		{
+			MethodName: "CreateTickets",
+			Handler:    _FrontendService_CreateTickets_Handler,
+		},
+		{
+			MethodName: "UpdateTicket",
+			Handler:    _FrontendService_UpdateTicket_Handler,
+		},
 		{
 			MethodName: "DeleteTicket",
 			Handler:    _FrontendService_DeleteTicket_Handler,
 		},
+		{
+			MethodName: "DeleteTickets",
+			Handler:    _FrontendService_DeleteTickets_Handler,
+		},
+		{
+			MethodName: "Heartbeat",
+			Handler:    _FrontendService_Heartbeat_Handler,
+		},
 		{
 			MethodName: "GetTicket",
 			Handler:    _FrontendService_GetTicket_Handler,
 		},
+		{
+			MethodName: "GetTicketState",
+			Handler:    _FrontendService_GetTicketState_Handler,
+		},
+		{
+			MethodName: "GetTicketStatus",
+			Handler:    _FrontendService_GetTicketStatus_Handler,
+		},
+		{
+			MethodName: "WaitForAssignment",
+			Handler:    _FrontendService_WaitForAssignment_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
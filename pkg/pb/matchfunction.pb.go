@@ -28,10 +28,14 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type RunRequest struct {
 	// A MatchProfile defines constraints of Tickets in a Match and shapes the Match proposed by the MatchFunction.
-	Profile              *MatchProfile `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+	Profile *MatchProfile `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	// The Tickets of each of the Profile's Pools, keyed by Pool name.
+	// Only set if the FunctionConfig of the FetchMatches call has pass_pool_tickets set, and the Backend could query
+	// every Pool. Otherwise the MatchFunction has to query the QueryService for the Tickets itself.
+	PoolTickets          map[string]*PoolTickets `protobuf:"bytes,2,rep,name=pool_tickets,json=poolTickets,proto3" json:"pool_tickets,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
 }
 
 func (m *RunRequest) Reset()         { *m = RunRequest{} }
@@ -66,6 +70,53 @@ func (m *RunRequest) GetProfile() *MatchProfile {
 	return nil
 }
 
+func (m *RunRequest) GetPoolTickets() map[string]*PoolTickets {
+	if m != nil {
+		return m.PoolTickets
+	}
+	return nil
+}
+
+// PoolTickets holds the Tickets of a Pool.
+type PoolTickets struct {
+	Tickets              []*Ticket `protobuf:"bytes,1,rep,name=tickets,proto3" json:"tickets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *PoolTickets) Reset()         { *m = PoolTickets{} }
+func (m *PoolTickets) String() string { return proto.CompactTextString(m) }
+func (*PoolTickets) ProtoMessage()    {}
+func (*PoolTickets) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b5069a21f149a55, []int{1}
+}
+
+func (m *PoolTickets) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PoolTickets.Unmarshal(m, b)
+}
+func (m *PoolTickets) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PoolTickets.Marshal(b, m, deterministic)
+}
+func (m *PoolTickets) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PoolTickets.Merge(m, src)
+}
+func (m *PoolTickets) XXX_Size() int {
+	return xxx_messageInfo_PoolTickets.Size(m)
+}
+func (m *PoolTickets) XXX_DiscardUnknown() {
+	xxx_messageInfo_PoolTickets.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PoolTickets proto.InternalMessageInfo
+
+func (m *PoolTickets) GetTickets() []*Ticket {
+	if m != nil {
+		return m.Tickets
+	}
+	return nil
+}
+
 type RunResponse struct {
 	// A Proposal represents a Match candidate that satifies the constraints defined in the input Profile.
 	// A valid Proposal response will contain at least one ticket.
@@ -79,7 +130,7 @@ func (m *RunResponse) Reset()         { *m = RunResponse{} }
 func (m *RunResponse) String() string { return proto.CompactTextString(m) }
 func (*RunResponse) ProtoMessage()    {}
 func (*RunResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2b5069a21f149a55, []int{1}
+	return fileDescriptor_2b5069a21f149a55, []int{2}
 }
 
 func (m *RunResponse) XXX_Unmarshal(b []byte) error {
@@ -109,44 +160,52 @@ func (m *RunResponse) GetProposal() *Match {
 
 func init() {
 	proto.RegisterType((*RunRequest)(nil), "openmatch.RunRequest")
+	proto.RegisterMapType((map[string]*PoolTickets)(nil), "openmatch.RunRequest.PoolTicketsEntry")
+	proto.RegisterType((*PoolTickets)(nil), "openmatch.PoolTickets")
 	proto.RegisterType((*RunResponse)(nil), "openmatch.RunResponse")
 }
 
 func init() { proto.RegisterFile("api/matchfunction.proto", fileDescriptor_2b5069a21f149a55) }
 
 var fileDescriptor_2b5069a21f149a55 = []byte{
-	// 486 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x52, 0x4d, 0x6e, 0x13, 0x31,
-	0x18, 0xd5, 0x4c, 0x50, 0x4b, 0x5d, 0x81, 0x2a, 0x4b, 0xfd, 0x51, 0xc4, 0xc2, 0x84, 0x0d, 0x8a,
-	0x9a, 0x71, 0x1a, 0xba, 0x4a, 0x85, 0x68, 0x81, 0x22, 0x55, 0x2a, 0x3f, 0x1a, 0x24, 0x84, 0xd8,
-	0x39, 0xce, 0xd7, 0x99, 0x81, 0xc4, 0x9f, 0xf1, 0x67, 0xb7, 0xac, 0xb9, 0x01, 0xb0, 0xe3, 0x08,
-	0x9c, 0x80, 0x7b, 0x70, 0x00, 0x36, 0x1c, 0x04, 0x8d, 0xa7, 0x69, 0x42, 0xca, 0x66, 0x46, 0x7e,
-	0xef, 0x7d, 0xef, 0xd9, 0xcf, 0x66, 0xdb, 0xca, 0x56, 0x72, 0xaa, 0xbc, 0x2e, 0xcf, 0x82, 0xd1,
-	0xbe, 0x42, 0x93, 0x59, 0x87, 0x1e, 0xf9, 0x1a, 0x5a, 0x30, 0x91, 0x68, 0xf3, 0xa8, 0x01, 0x22,
-	0x55, 0x00, 0x35, 0x74, 0xfb, 0x4e, 0x81, 0x58, 0x4c, 0x40, 0xd6, 0x94, 0x32, 0x06, 0xbd, 0xaa,
-	0x67, 0x67, 0xec, 0x6e, 0xfc, 0xe9, 0x5e, 0x01, 0xa6, 0x47, 0x17, 0xaa, 0x28, 0xc0, 0x49, 0xb4,
-	0x51, 0x71, 0x5d, 0xdd, 0x79, 0xc4, 0x58, 0x1e, 0x4c, 0x0e, 0x1f, 0x03, 0x90, 0xe7, 0x7b, 0x6c,
-	0xd5, 0x3a, 0x3c, 0xab, 0x26, 0xb0, 0x93, 0x88, 0xe4, 0xfe, 0xfa, 0x60, 0x3b, 0xbb, 0xda, 0x4a,
-	0xf6, 0xbc, 0xfe, 0xbe, 0x6a, 0xe8, 0x7c, 0xa6, 0xeb, 0x1c, 0xb0, 0xf5, 0x68, 0x40, 0x16, 0x0d,
-	0x01, 0xdf, 0x65, 0x37, 0xad, 0x43, 0x8b, 0xa4, 0x26, 0x97, 0x16, 0x1b, 0xcb, 0x16, 0xf9, 0x95,
-	0x62, 0x50, 0xb1, 0x5b, 0x11, 0x7a, 0x76, 0x79, 0x7e, 0xfe, 0x96, 0xb5, 0xf2, 0x60, 0xf8, 0xe6,
-	0xc2, 0xcc, 0x7c, 0x7b, 0xed, 0xad, 0x65, 0xb8, 0x09, 0xed, 0x88, 0xcf, 0xbf, 0xfe, 0x7c, 0x4b,
-	0xdb, 0x9d, 0x4d, 0x79, 0xbe, 0xf7, 0x6f, 0xa1, 0x43, 0x17, 0xcc, 0x30, 0xe9, 0xf6, 0x93, 0xc7,
-	0x5f, 0x5a, 0x5f, 0x8f, 0x7e, 0xa7, 0xfc, 0x67, 0xc2, 0x6e, 0xc7, 0x48, 0x31, 0xcb, 0xec, 0x9c,
-	0x30, 0xf6, 0xd2, 0x82, 0x11, 0x11, 0xe6, 0x5b, 0xa5, 0xf7, 0x96, 0x86, 0x52, 0xd6, 0x51, 0xbd,
-	0x26, 0x6b, 0x0c, 0xe7, 0xed, 0x7b, 0xf3, 0x75, 0x6f, 0x5c, 0x91, 0x0e, 0x44, 0x87, 0xcd, 0x3d,
-	0x14, 0x0e, 0x83, 0xa5, 0x4c, 0xe3, 0xb4, 0xfb, 0x86, 0xf1, 0x23, 0xab, 0x74, 0x09, 0x62, 0x90,
-	0xf5, 0xc5, 0x69, 0xa5, 0xa1, 0xee, 0xe4, 0x70, 0x66, 0x59, 0x54, 0xbe, 0x0c, 0xa3, 0x5a, 0x29,
-	0x9b, 0xd1, 0x33, 0x74, 0x85, 0x9a, 0x02, 0x2d, 0x84, 0xc9, 0xd1, 0x04, 0x47, 0x72, 0xaa, 0xc8,
-	0x83, 0x93, 0xa7, 0x27, 0x4f, 0x8e, 0x5f, 0xbc, 0x3e, 0x1e, 0xb4, 0xf6, 0xb2, 0x7e, 0x37, 0x4d,
-	0xd2, 0xc1, 0x86, 0xb2, 0x76, 0x52, 0xe9, 0x78, 0x85, 0xf2, 0x3d, 0xa1, 0x19, 0x5e, 0x43, 0xf2,
-	0x03, 0xd6, 0xda, 0xef, 0xef, 0xf3, 0x7d, 0xd6, 0xcd, 0xc1, 0x07, 0x67, 0x60, 0x2c, 0x2e, 0x4a,
-	0x30, 0xc2, 0x97, 0x20, 0x1c, 0x10, 0x06, 0xa7, 0x41, 0x8c, 0x11, 0x48, 0x18, 0xf4, 0x02, 0x3e,
-	0x55, 0xe4, 0x33, 0xbe, 0xc2, 0x6e, 0x7c, 0x4f, 0x93, 0x55, 0xf7, 0x90, 0xed, 0xcc, 0xcb, 0x10,
-	0x4f, 0x51, 0x87, 0x29, 0x98, 0xe6, 0xc9, 0xf0, 0xbb, 0xff, 0xaf, 0x46, 0x52, 0xe5, 0x41, 0x8e,
-	0x51, 0x93, 0x7c, 0x27, 0x96, 0xa8, 0x85, 0x73, 0xd9, 0x0f, 0x85, 0xb4, 0xa3, 0x1f, 0xe9, 0x5a,
-	0xed, 0x1f, 0xed, 0x47, 0x2b, 0xf1, 0x0d, 0x3e, 0xf8, 0x1b, 0x00, 0x00, 0xff, 0xff, 0x79, 0x14,
-	0x90, 0xfd, 0x09, 0x03, 0x00, 0x00,
+	// 585 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x53, 0x5d, 0x6e, 0xd3, 0x4c,
+	0x14, 0x95, 0x9d, 0xef, 0x6b, 0xe9, 0x04, 0x50, 0x18, 0xa9, 0x6d, 0x14, 0xf1, 0x30, 0x04, 0x09,
+	0x55, 0xa1, 0xf1, 0xa4, 0xa1, 0x0f, 0x28, 0x15, 0x52, 0x0b, 0x14, 0xa9, 0x52, 0x81, 0xca, 0xa0,
+	0x0a, 0xf1, 0x82, 0x9c, 0xc9, 0xad, 0x63, 0xea, 0xcc, 0x1d, 0xe6, 0xa7, 0xa5, 0xaf, 0xec, 0x00,
+	0x78, 0x63, 0x09, 0xac, 0x80, 0x7d, 0xb0, 0x00, 0x24, 0xc4, 0x42, 0x90, 0xc7, 0x4d, 0x63, 0xd2,
+	0xbe, 0xd8, 0x9e, 0x7b, 0xce, 0xbd, 0xe7, 0xe8, 0xf8, 0x0e, 0x59, 0x4d, 0x54, 0xc6, 0x27, 0x89,
+	0x15, 0xe3, 0x23, 0x27, 0x85, 0xcd, 0x50, 0x46, 0x4a, 0xa3, 0x45, 0xba, 0x84, 0x0a, 0xa4, 0x07,
+	0x5a, 0xd4, 0x73, 0xc0, 0x98, 0x24, 0x05, 0x53, 0xc2, 0xad, 0xdb, 0x29, 0x62, 0x9a, 0x03, 0x2f,
+	0xa0, 0x44, 0x4a, 0xb4, 0x49, 0xd1, 0x3b, 0x45, 0xd7, 0xfd, 0x4b, 0x74, 0x53, 0x90, 0x5d, 0x73,
+	0x9a, 0xa4, 0x29, 0x68, 0x8e, 0xca, 0x33, 0x2e, 0xb3, 0xdb, 0xbf, 0x03, 0x42, 0x62, 0x27, 0x63,
+	0xf8, 0xe0, 0xc0, 0x58, 0xba, 0x41, 0x16, 0x95, 0xc6, 0xa3, 0x2c, 0x87, 0x66, 0xc0, 0x82, 0xb5,
+	0x7a, 0x7f, 0x35, 0xba, 0xf0, 0x12, 0x3d, 0x2f, 0x9e, 0x07, 0x25, 0x1c, 0x4f, 0x79, 0x74, 0x8f,
+	0x5c, 0x57, 0x88, 0xf9, 0x3b, 0x9b, 0x89, 0x63, 0xb0, 0xa6, 0x19, 0xb2, 0xda, 0x5a, 0xbd, 0x7f,
+	0xaf, 0xd2, 0x37, 0x9b, 0x1f, 0x1d, 0x20, 0xe6, 0xaf, 0x4b, 0xe2, 0xae, 0xb4, 0xfa, 0x2c, 0xae,
+	0xab, 0x59, 0xa5, 0x75, 0x48, 0x1a, 0xf3, 0x04, 0xda, 0x20, 0xb5, 0x63, 0x38, 0xf3, 0x6e, 0x96,
+	0xe2, 0xe2, 0x93, 0xae, 0x93, 0xff, 0x4f, 0x92, 0xdc, 0x41, 0x33, 0xf4, 0x0e, 0x57, 0x2a, 0x4a,
+	0x95, 0xee, 0xb8, 0x24, 0x0d, 0xc2, 0x87, 0x41, 0x7b, 0x40, 0xea, 0x15, 0x84, 0xde, 0x27, 0x8b,
+	0x53, 0xb3, 0x81, 0x37, 0x7b, 0xab, 0x32, 0xa2, 0x24, 0xc5, 0x53, 0x46, 0x7b, 0x8b, 0xd4, 0xbd,
+	0x7f, 0xa3, 0x50, 0x1a, 0xa0, 0xeb, 0xe4, 0x9a, 0xd2, 0xa8, 0xd0, 0x24, 0xf9, 0x79, 0x42, 0x8d,
+	0xf9, 0x84, 0xe2, 0x0b, 0x46, 0x3f, 0x23, 0x37, 0x7c, 0xe9, 0xd9, 0xf9, 0xff, 0xa5, 0x6f, 0x48,
+	0x2d, 0x76, 0x92, 0x2e, 0x5f, 0x99, 0x4e, 0x6b, 0x65, 0xbe, 0x5c, 0x8a, 0xb6, 0xd9, 0xa7, 0x9f,
+	0x7f, 0xbe, 0x86, 0xad, 0xf6, 0x32, 0x3f, 0xd9, 0xf8, 0x77, 0x61, 0x06, 0xda, 0xc9, 0x41, 0xd0,
+	0xe9, 0x05, 0x8f, 0x3f, 0xd7, 0xbe, 0xec, 0xfc, 0x0a, 0xe9, 0x8f, 0x80, 0xdc, 0xf4, 0x92, 0x6c,
+	0xaa, 0xd9, 0xde, 0x23, 0xe4, 0xa5, 0x02, 0xc9, 0x7c, 0x99, 0xae, 0x8c, 0xad, 0x55, 0x66, 0xc0,
+	0x79, 0x21, 0xd5, 0x2d, 0xb5, 0x46, 0x70, 0xd2, 0xba, 0x3b, 0x3b, 0x77, 0x47, 0x99, 0x11, 0xce,
+	0x98, 0xed, 0x72, 0xcf, 0x52, 0x8d, 0x4e, 0x99, 0x48, 0xe0, 0xa4, 0x73, 0x48, 0xe8, 0x8e, 0x4a,
+	0xc4, 0x18, 0x58, 0x3f, 0xea, 0xb1, 0xfd, 0x4c, 0x40, 0x91, 0xc9, 0xf6, 0x74, 0x64, 0x9a, 0xd9,
+	0xb1, 0x1b, 0x16, 0x4c, 0x5e, 0xb6, 0x1e, 0xa1, 0x4e, 0x93, 0x09, 0x98, 0x8a, 0x18, 0x1f, 0xe6,
+	0x38, 0xe4, 0x93, 0xc4, 0x58, 0xd0, 0x7c, 0x7f, 0xef, 0xc9, 0xee, 0x8b, 0x57, 0xbb, 0xfd, 0xda,
+	0x46, 0xd4, 0xeb, 0x84, 0x41, 0xd8, 0x6f, 0x24, 0x4a, 0xe5, 0x99, 0xf0, 0x2b, 0xca, 0xdf, 0x1b,
+	0x94, 0x83, 0x4b, 0x95, 0x78, 0x8b, 0xd4, 0x36, 0x7b, 0x9b, 0x74, 0x93, 0x74, 0x62, 0xb0, 0x4e,
+	0x4b, 0x18, 0xb1, 0xd3, 0x31, 0x48, 0x66, 0xc7, 0xc0, 0x34, 0x18, 0x74, 0x5a, 0x00, 0x1b, 0x21,
+	0x18, 0x26, 0xd1, 0x32, 0xf8, 0x98, 0x19, 0x1b, 0xd1, 0x05, 0xf2, 0xdf, 0xb7, 0x30, 0x58, 0xd4,
+	0x8f, 0x48, 0x73, 0x16, 0x06, 0x7b, 0x8a, 0xc2, 0x4d, 0x40, 0x96, 0x57, 0x82, 0xde, 0xb9, 0x3a,
+	0x1a, 0x6e, 0x32, 0x0b, 0x7c, 0x84, 0xc2, 0xf0, 0xb7, 0x6c, 0x0e, 0x9a, 0x1d, 0xb9, 0x3a, 0x4e,
+	0xb9, 0x1a, 0x7e, 0x0f, 0x97, 0x8a, 0xf9, 0x7e, 0xfc, 0x70, 0xc1, 0xdf, 0xb1, 0x07, 0x7f, 0x07,
+	0x00, 0x4c, 0x51, 0x89, 0xf7, 0xe9, 0x03, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
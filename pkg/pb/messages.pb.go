@@ -7,6 +7,7 @@ import (
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
 	any "github.com/golang/protobuf/ptypes/any"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	_ "google.golang.org/genproto/googleapis/rpc/status"
 	math "math"
 )
@@ -22,6 +23,46 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// The lifecycle states of a Ticket, as reported by FrontendService.GetTicketState.
+type Ticket_State int32
+
+const (
+	// The state is unknown.
+	Ticket_STATE_UNSPECIFIED Ticket_State = 0
+	// The Ticket is waiting to be proposed in a match.
+	Ticket_SEARCHING Ticket_State = 1
+	// The Ticket was proposed in a match which isn't assigned yet.
+	Ticket_PROPOSED Ticket_State = 2
+	// The Ticket was assigned to a game server.
+	Ticket_ASSIGNED Ticket_State = 3
+	// The Ticket expired before it was deleted.
+	Ticket_EXPIRED Ticket_State = 4
+)
+
+var Ticket_State_name = map[int32]string{
+	0: "STATE_UNSPECIFIED",
+	1: "SEARCHING",
+	2: "PROPOSED",
+	3: "ASSIGNED",
+	4: "EXPIRED",
+}
+
+var Ticket_State_value = map[string]int32{
+	"STATE_UNSPECIFIED": 0,
+	"SEARCHING":         1,
+	"PROPOSED":          2,
+	"ASSIGNED":          3,
+	"EXPIRED":           4,
+}
+
+func (x Ticket_State) String() string {
+	return proto.EnumName(Ticket_State_name, int32(x))
+}
+
+func (Ticket_State) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_cb9fb1f207fd5b8c, []int{0, 0}
+}
+
 // A Ticket is a basic matchmaking entity in Open Match. A Ticket represents either an
 // individual 'Player' or a 'Group' of players. Open Match will not interpret
 // what the Ticket represents but just treat it as a matchmaking unit with a set
@@ -39,10 +80,32 @@ type Ticket struct {
 	// Customized information not inspected by Open Match, to be used by the match
 	// making function, evaluator, and components making calls to Open Match.
 	// Optional, depending on the requirements of the connected systems.
-	Extensions           map[string]*any.Any `protobuf:"bytes,5,rep,name=extensions,proto3" json:"extensions,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
-	XXX_unrecognized     []byte              `json:"-"`
-	XXX_sizecache        int32               `json:"-"`
+	// When synchronizer.verifyTicketsBeforeEvaluation is enabled, Tickets in
+	// proposals sent to the evaluator carry a google.protobuf.BoolValue under the
+	// "openmatch.ticket_valid" key, which is false if the Ticket was deleted or
+	// assigned after the proposal was made.
+	// When frontend.clientMetadata.annotateTickets is enabled, created Tickets
+	// carry the version and platform of the client which created them as a
+	// google.protobuf.Struct under the "openmatch.client_metadata" key.
+	Extensions map[string]*any.Any `protobuf:"bytes,5,rep,name=extensions,proto3" json:"extensions,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Ids of the players represented by this Ticket. Used to apply the avoid
+	// lists of other Tickets.
+	// Optional.
+	PlayerIds []string `protobuf:"bytes,6,rep,name=player_ids,json=playerIds,proto3" json:"player_ids,omitempty"`
+	// Ticket or player ids this Ticket should not be matched with, such as
+	// recent opponents or blocked players. Open Match does not enforce avoid
+	// lists, see the matchfunction package for helpers to apply them.
+	// Optional.
+	AvoidIds []string `protobuf:"bytes,7,rep,name=avoid_ids,json=avoidIds,proto3" json:"avoid_ids,omitempty"`
+	// Number of times an Assignment of this Ticket failed, eg. because the game
+	// server couldn't be allocated, and the Ticket was returned to the pool with
+	// BackendService.RequeueTickets.  Set by Open Match.
+	AssignmentFailures int32 `protobuf:"varint,8,opt,name=assignment_failures,json=assignmentFailures,proto3" json:"assignment_failures,omitempty"`
+	// Time the Ticket was created, populated by Open Match.
+	CreateTime           *timestamp.Timestamp `protobuf:"bytes,9,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
 func (m *Ticket) Reset()         { *m = Ticket{} }
@@ -98,6 +161,34 @@ func (m *Ticket) GetExtensions() map[string]*any.Any {
 	return nil
 }
 
+func (m *Ticket) GetPlayerIds() []string {
+	if m != nil {
+		return m.PlayerIds
+	}
+	return nil
+}
+
+func (m *Ticket) GetAvoidIds() []string {
+	if m != nil {
+		return m.AvoidIds
+	}
+	return nil
+}
+
+func (m *Ticket) GetAssignmentFailures() int32 {
+	if m != nil {
+		return m.AssignmentFailures
+	}
+	return 0
+}
+
+func (m *Ticket) GetCreateTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreateTime
+	}
+	return nil
+}
+
 // Search fields are the fields which Open Match is aware of, and can be used
 // when specifying filters.
 type SearchFields struct {
@@ -386,24 +477,123 @@ func (m *TagPresentFilter) GetTag() string {
 	return ""
 }
 
+// Combines Filters with boolean logic.  A FilterExpression matches a Ticket if
+// every criterion set on it does, and an empty one matches every Ticket.
+//   (region EU or NA) and not tag in_penalty
+// is expressed as:
+//   all: [
+//     {any: [{string_equals_filter: {string_arg: "region", value: "EU"}},
+//            {string_equals_filter: {string_arg: "region", value: "NA"}}]},
+//     {not: {tag_present_filter: {tag: "in_penalty"}}}
+//   ]
+type FilterExpression struct {
+	// Matches if every expression matches.
+	All []*FilterExpression `protobuf:"bytes,1,rep,name=all,proto3" json:"all,omitempty"`
+	// Matches if at least one expression matches.  Ignored if empty.
+	Any []*FilterExpression `protobuf:"bytes,2,rep,name=any,proto3" json:"any,omitempty"`
+	// Matches if the expression doesn't.
+	Not                  *FilterExpression   `protobuf:"bytes,3,opt,name=not,proto3" json:"not,omitempty"`
+	DoubleRangeFilter    *DoubleRangeFilter  `protobuf:"bytes,4,opt,name=double_range_filter,json=doubleRangeFilter,proto3" json:"double_range_filter,omitempty"`
+	StringEqualsFilter   *StringEqualsFilter `protobuf:"bytes,5,opt,name=string_equals_filter,json=stringEqualsFilter,proto3" json:"string_equals_filter,omitempty"`
+	TagPresentFilter     *TagPresentFilter   `protobuf:"bytes,6,opt,name=tag_present_filter,json=tagPresentFilter,proto3" json:"tag_present_filter,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *FilterExpression) Reset()         { *m = FilterExpression{} }
+func (m *FilterExpression) String() string { return proto.CompactTextString(m) }
+func (*FilterExpression) ProtoMessage()    {}
+func (*FilterExpression) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb9fb1f207fd5b8c, []int{6}
+}
+
+func (m *FilterExpression) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FilterExpression.Unmarshal(m, b)
+}
+func (m *FilterExpression) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FilterExpression.Marshal(b, m, deterministic)
+}
+func (m *FilterExpression) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FilterExpression.Merge(m, src)
+}
+func (m *FilterExpression) XXX_Size() int {
+	return xxx_messageInfo_FilterExpression.Size(m)
+}
+func (m *FilterExpression) XXX_DiscardUnknown() {
+	xxx_messageInfo_FilterExpression.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FilterExpression proto.InternalMessageInfo
+
+func (m *FilterExpression) GetAll() []*FilterExpression {
+	if m != nil {
+		return m.All
+	}
+	return nil
+}
+
+func (m *FilterExpression) GetAny() []*FilterExpression {
+	if m != nil {
+		return m.Any
+	}
+	return nil
+}
+
+func (m *FilterExpression) GetNot() *FilterExpression {
+	if m != nil {
+		return m.Not
+	}
+	return nil
+}
+
+func (m *FilterExpression) GetDoubleRangeFilter() *DoubleRangeFilter {
+	if m != nil {
+		return m.DoubleRangeFilter
+	}
+	return nil
+}
+
+func (m *FilterExpression) GetStringEqualsFilter() *StringEqualsFilter {
+	if m != nil {
+		return m.StringEqualsFilter
+	}
+	return nil
+}
+
+func (m *FilterExpression) GetTagPresentFilter() *TagPresentFilter {
+	if m != nil {
+		return m.TagPresentFilter
+	}
+	return nil
+}
+
 type Pool struct {
 	// A developer-chosen human-readable name for this Pool.
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	// Set of Filters indicating the filtering criteria. Selected players must
 	// match every Filter.
-	DoubleRangeFilters   []*DoubleRangeFilter  `protobuf:"bytes,2,rep,name=double_range_filters,json=doubleRangeFilters,proto3" json:"double_range_filters,omitempty"`
-	StringEqualsFilters  []*StringEqualsFilter `protobuf:"bytes,4,rep,name=string_equals_filters,json=stringEqualsFilters,proto3" json:"string_equals_filters,omitempty"`
-	TagPresentFilters    []*TagPresentFilter   `protobuf:"bytes,5,rep,name=tag_present_filters,json=tagPresentFilters,proto3" json:"tag_present_filters,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
-	XXX_unrecognized     []byte                `json:"-"`
-	XXX_sizecache        int32                 `json:"-"`
+	DoubleRangeFilters  []*DoubleRangeFilter  `protobuf:"bytes,2,rep,name=double_range_filters,json=doubleRangeFilters,proto3" json:"double_range_filters,omitempty"`
+	StringEqualsFilters []*StringEqualsFilter `protobuf:"bytes,4,rep,name=string_equals_filters,json=stringEqualsFilters,proto3" json:"string_equals_filters,omitempty"`
+	TagPresentFilters   []*TagPresentFilter   `protobuf:"bytes,5,rep,name=tag_present_filters,json=tagPresentFilters,proto3" json:"tag_present_filters,omitempty"`
+	// If specified, only Tickets created before the specified time are selected.
+	CreatedBefore *timestamp.Timestamp `protobuf:"bytes,6,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	// If specified, only Tickets created after the specified time are selected,
+	// eg. to relax the criteria of Tickets waiting longer than 30s.
+	CreatedAfter *timestamp.Timestamp `protobuf:"bytes,7,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	// If specified, only Tickets matching the expression are selected, in
+	// addition to the other Filters, eg. for criteria needing OR or NOT.
+	FilterExpression     *FilterExpression `protobuf:"bytes,8,opt,name=filter_expression,json=filterExpression,proto3" json:"filter_expression,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *Pool) Reset()         { *m = Pool{} }
 func (m *Pool) String() string { return proto.CompactTextString(m) }
 func (*Pool) ProtoMessage()    {}
 func (*Pool) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cb9fb1f207fd5b8c, []int{6}
+	return fileDescriptor_cb9fb1f207fd5b8c, []int{7}
 }
 
 func (m *Pool) XXX_Unmarshal(b []byte) error {
@@ -452,6 +642,27 @@ func (m *Pool) GetTagPresentFilters() []*TagPresentFilter {
 	return nil
 }
 
+func (m *Pool) GetCreatedBefore() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedBefore
+	}
+	return nil
+}
+
+func (m *Pool) GetCreatedAfter() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAfter
+	}
+	return nil
+}
+
+func (m *Pool) GetFilterExpression() *FilterExpression {
+	if m != nil {
+		return m.FilterExpression
+	}
+	return nil
+}
+
 // A MatchProfile is Open Match's representation of a Match specification. It is
 // used to indicate the criteria for selecting players for a match. A
 // MatchProfile is the input to the API to get matches and is passed to the
@@ -465,17 +676,26 @@ type MatchProfile struct {
 	// Customized information not inspected by Open Match, to be used by the match
 	// making function, evaluator, and components making calls to Open Match.
 	// Optional, depending on the requirements of the connected systems.
-	Extensions           map[string]*any.Any `protobuf:"bytes,5,rep,name=extensions,proto3" json:"extensions,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
-	XXX_unrecognized     []byte              `json:"-"`
-	XXX_sizecache        int32               `json:"-"`
+	Extensions map[string]*any.Any `protobuf:"bytes,5,rep,name=extensions,proto3" json:"extensions,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Maximum number of tickets a single Match proposed for this MatchProfile may
+	// contain. Larger proposals are rejected by the backend before evaluation.
+	// Optional, 0 means no limit.
+	MaxTicketsPerMatch int32 `protobuf:"varint,6,opt,name=max_tickets_per_match,json=maxTicketsPerMatch,proto3" json:"max_tickets_per_match,omitempty"`
+	// Maximum number of Match proposals accepted from the match function for this
+	// MatchProfile in a single FetchMatches call. Further proposals are rejected by
+	// the backend before evaluation.
+	// Optional, 0 means no limit.
+	MaxMatchesPerCycle   int32    `protobuf:"varint,7,opt,name=max_matches_per_cycle,json=maxMatchesPerCycle,proto3" json:"max_matches_per_cycle,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *MatchProfile) Reset()         { *m = MatchProfile{} }
 func (m *MatchProfile) String() string { return proto.CompactTextString(m) }
 func (*MatchProfile) ProtoMessage()    {}
 func (*MatchProfile) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cb9fb1f207fd5b8c, []int{7}
+	return fileDescriptor_cb9fb1f207fd5b8c, []int{8}
 }
 
 func (m *MatchProfile) XXX_Unmarshal(b []byte) error {
@@ -517,6 +737,102 @@ func (m *MatchProfile) GetExtensions() map[string]*any.Any {
 	return nil
 }
 
+func (m *MatchProfile) GetMaxTicketsPerMatch() int32 {
+	if m != nil {
+		return m.MaxTicketsPerMatch
+	}
+	return 0
+}
+
+func (m *MatchProfile) GetMaxMatchesPerCycle() int32 {
+	if m != nil {
+		return m.MaxMatchesPerCycle
+	}
+	return 0
+}
+
+// A Backfill represents a game in progress with open slots, eg. to replace
+// players who left, which Open Match can fill with Tickets.
+type Backfill struct {
+	// Id represents an auto-generated Id issued by Open Match.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Search fields are the fields which Open Match is aware of, and can be used
+	// when specifying filters.
+	SearchFields *SearchFields `protobuf:"bytes,2,opt,name=search_fields,json=searchFields,proto3" json:"search_fields,omitempty"`
+	// Customized information not inspected by Open Match, to be used by the match
+	// making function, evaluator, and components making calls to Open Match.
+	// Optional, depending on the requirements of the connected systems.
+	Extensions map[string]*any.Any `protobuf:"bytes,3,rep,name=extensions,proto3" json:"extensions,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Time the Backfill was created, populated by Open Match.
+	CreateTime *timestamp.Timestamp `protobuf:"bytes,4,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// Incremented by Open Match whenever the Backfill is updated, so that stale
+	// copies can be detected.
+	Generation           int64    `protobuf:"varint,5,opt,name=generation,proto3" json:"generation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Backfill) Reset()         { *m = Backfill{} }
+func (m *Backfill) String() string { return proto.CompactTextString(m) }
+func (*Backfill) ProtoMessage()    {}
+func (*Backfill) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb9fb1f207fd5b8c, []int{9}
+}
+
+func (m *Backfill) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Backfill.Unmarshal(m, b)
+}
+func (m *Backfill) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Backfill.Marshal(b, m, deterministic)
+}
+func (m *Backfill) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Backfill.Merge(m, src)
+}
+func (m *Backfill) XXX_Size() int {
+	return xxx_messageInfo_Backfill.Size(m)
+}
+func (m *Backfill) XXX_DiscardUnknown() {
+	xxx_messageInfo_Backfill.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Backfill proto.InternalMessageInfo
+
+func (m *Backfill) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Backfill) GetSearchFields() *SearchFields {
+	if m != nil {
+		return m.SearchFields
+	}
+	return nil
+}
+
+func (m *Backfill) GetExtensions() map[string]*any.Any {
+	if m != nil {
+		return m.Extensions
+	}
+	return nil
+}
+
+func (m *Backfill) GetCreateTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreateTime
+	}
+	return nil
+}
+
+func (m *Backfill) GetGeneration() int64 {
+	if m != nil {
+		return m.Generation
+	}
+	return 0
+}
+
 // A Match is used to represent a completed match object. It can be generated by
 // a MatchFunction as a proposal or can be returned by OpenMatch as a result in
 // response to the FetchMatches call.
@@ -534,6 +850,12 @@ type Match struct {
 	// Customized information not inspected by Open Match, to be used by the match
 	// making function, evaluator, and components making calls to Open Match.
 	// Optional, depending on the requirements of the connected systems.
+	// Matches returned by FetchMatches carry the id of the synchronizer cycle
+	// which produced them as a google.protobuf.StringValue under the
+	// "openmatch.synchronizer_cycle_id" key.  Matches with Tickets which were deleted before
+	// FetchMatches returned them carry a google.protobuf.BoolValue false under
+	// the "openmatch.match_valid" key.  Their remaining Tickets aren't held back
+	// from other matches, and shouldn't be assigned.
 	Extensions           map[string]*any.Any `protobuf:"bytes,7,rep,name=extensions,proto3" json:"extensions,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
 	XXX_unrecognized     []byte              `json:"-"`
@@ -544,7 +866,7 @@ func (m *Match) Reset()         { *m = Match{} }
 func (m *Match) String() string { return proto.CompactTextString(m) }
 func (*Match) ProtoMessage()    {}
 func (*Match) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cb9fb1f207fd5b8c, []int{8}
+	return fileDescriptor_cb9fb1f207fd5b8c, []int{10}
 }
 
 func (m *Match) XXX_Unmarshal(b []byte) error {
@@ -601,6 +923,7 @@ func (m *Match) GetExtensions() map[string]*any.Any {
 }
 
 func init() {
+	proto.RegisterEnum("openmatch.Ticket_State", Ticket_State_name, Ticket_State_value)
 	proto.RegisterType((*Ticket)(nil), "openmatch.Ticket")
 	proto.RegisterMapType((map[string]*any.Any)(nil), "openmatch.Ticket.ExtensionsEntry")
 	proto.RegisterType((*SearchFields)(nil), "openmatch.SearchFields")
@@ -611,9 +934,12 @@ func init() {
 	proto.RegisterType((*DoubleRangeFilter)(nil), "openmatch.DoubleRangeFilter")
 	proto.RegisterType((*StringEqualsFilter)(nil), "openmatch.StringEqualsFilter")
 	proto.RegisterType((*TagPresentFilter)(nil), "openmatch.TagPresentFilter")
+	proto.RegisterType((*FilterExpression)(nil), "openmatch.FilterExpression")
 	proto.RegisterType((*Pool)(nil), "openmatch.Pool")
 	proto.RegisterType((*MatchProfile)(nil), "openmatch.MatchProfile")
 	proto.RegisterMapType((map[string]*any.Any)(nil), "openmatch.MatchProfile.ExtensionsEntry")
+	proto.RegisterType((*Backfill)(nil), "openmatch.Backfill")
+	proto.RegisterMapType((map[string]*any.Any)(nil), "openmatch.Backfill.ExtensionsEntry")
 	proto.RegisterType((*Match)(nil), "openmatch.Match")
 	proto.RegisterMapType((map[string]*any.Any)(nil), "openmatch.Match.ExtensionsEntry")
 }
@@ -621,52 +947,79 @@ func init() {
 func init() { proto.RegisterFile("api/messages.proto", fileDescriptor_cb9fb1f207fd5b8c) }
 
 var fileDescriptor_cb9fb1f207fd5b8c = []byte{
-	// 747 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x55, 0xdd, 0x6e, 0xd3, 0x30,
-	0x14, 0x56, 0x7e, 0xfa, 0x77, 0xda, 0x6d, 0x99, 0xb7, 0x69, 0x5d, 0x61, 0xa8, 0x04, 0x26, 0x2a,
-	0x10, 0xa9, 0x34, 0x84, 0x84, 0x10, 0x48, 0x14, 0xd1, 0xc1, 0x86, 0x80, 0x91, 0x4d, 0x5c, 0x70,
-	0x53, 0xb9, 0x8d, 0x9b, 0x45, 0x4b, 0x9d, 0x10, 0xbb, 0xd3, 0xfa, 0x16, 0x3c, 0x07, 0xdc, 0x72,
-	0xcd, 0x53, 0xf0, 0x26, 0xbc, 0x00, 0x8a, 0x9d, 0xa6, 0x5e, 0x5b, 0xe0, 0x72, 0x77, 0xce, 0xf9,
-	0xf9, 0xce, 0x77, 0xbe, 0x73, 0xec, 0x00, 0xc2, 0x71, 0xd0, 0x1e, 0x11, 0xc6, 0xb0, 0x4f, 0x98,
-	0x13, 0x27, 0x11, 0x8f, 0x50, 0x25, 0x8a, 0x09, 0x1d, 0x61, 0x3e, 0x38, 0x6b, 0x6c, 0xfb, 0x51,
-	0xe4, 0x87, 0xa4, 0x9d, 0xc4, 0x83, 0x36, 0xe3, 0x98, 0x8f, 0xb3, 0x98, 0xc6, 0x4e, 0xe6, 0x10,
-	0x5f, 0xfd, 0xf1, 0xb0, 0x8d, 0xe9, 0x44, 0xba, 0xec, 0xef, 0x3a, 0x14, 0x4f, 0x83, 0xc1, 0x39,
-	0xe1, 0x68, 0x15, 0xf4, 0xc0, 0xab, 0x6b, 0x4d, 0xad, 0x55, 0x71, 0xf5, 0xc0, 0x43, 0x8f, 0x01,
-	0x30, 0x63, 0x81, 0x4f, 0x47, 0x84, 0xf2, 0xba, 0xd1, 0xd4, 0x5a, 0xd5, 0xfd, 0x2d, 0x27, 0x2f,
-	0xe7, 0x74, 0x72, 0xa7, 0xab, 0x04, 0xa2, 0x67, 0xb0, 0xc2, 0x08, 0x4e, 0x06, 0x67, 0xbd, 0x61,
-	0x40, 0x42, 0x8f, 0xd5, 0x4d, 0x91, 0xb9, 0xad, 0x64, 0x9e, 0x08, 0xff, 0x81, 0x70, 0xbb, 0x35,
-	0xa6, 0x7c, 0xa1, 0x0e, 0x00, 0xb9, 0xe4, 0x84, 0xb2, 0x20, 0xa2, 0xac, 0x5e, 0x68, 0x1a, 0xad,
-	0xea, 0xfe, 0x6d, 0x25, 0x55, 0x72, 0x75, 0xba, 0x79, 0x4c, 0x97, 0xf2, 0x64, 0xe2, 0x2a, 0x49,
-	0x8d, 0x13, 0x58, 0x9b, 0x73, 0x23, 0x0b, 0x8c, 0x73, 0x32, 0xc9, 0x7a, 0x4b, 0x8f, 0xe8, 0x3e,
-	0x14, 0x2e, 0x70, 0x38, 0x26, 0x75, 0x5d, 0xb0, 0xdb, 0x74, 0xa4, 0x44, 0xce, 0x54, 0x22, 0xa7,
-	0x43, 0x27, 0xae, 0x0c, 0x79, 0xaa, 0x3f, 0xd1, 0x8e, 0xcc, 0xb2, 0x6e, 0x19, 0xf6, 0x0f, 0x1d,
-	0x6a, 0x2a, 0x79, 0xf4, 0x06, 0xaa, 0x5e, 0x34, 0xee, 0x87, 0xa4, 0x87, 0x13, 0x9f, 0xd5, 0x35,
-	0xc1, 0xf7, 0xde, 0x5f, 0x5a, 0x75, 0x5e, 0x89, 0xd0, 0x4e, 0xe2, 0x4f, 0x59, 0x7b, 0xb9, 0x21,
-	0x45, 0x62, 0x3c, 0x09, 0xa8, 0x2f, 0x91, 0xf4, 0x7f, 0x23, 0x9d, 0x88, 0x50, 0x05, 0x89, 0xe5,
-	0x06, 0x84, 0xc0, 0xe4, 0xd8, 0x67, 0x75, 0xa3, 0x69, 0xb4, 0x2a, 0xae, 0x38, 0x37, 0x9e, 0xc3,
-	0xda, 0x5c, 0xf1, 0x25, 0x9a, 0x6c, 0xaa, 0x9a, 0x68, 0x4a, 0xf7, 0x69, 0xfa, 0x5c, 0xc5, 0xff,
-	0xa5, 0x57, 0x94, 0x74, 0xfb, 0x97, 0x06, 0x30, 0xdb, 0x16, 0x74, 0x0b, 0x60, 0x10, 0x51, 0x4a,
-	0x06, 0x3c, 0x88, 0x68, 0x86, 0xa0, 0x58, 0x50, 0xf7, 0xca, 0x0e, 0x98, 0x42, 0x89, 0xbd, 0xa5,
-	0x8b, 0x77, 0x4d, 0x7b, 0x70, 0x64, 0x96, 0x0d, 0xcb, 0xb4, 0x3f, 0xc1, 0xba, 0x14, 0xd5, 0xc5,
-	0xd4, 0x27, 0x07, 0x41, 0xc8, 0x49, 0x82, 0x76, 0x01, 0x66, 0x1b, 0x91, 0x55, 0xaa, 0xe4, 0x73,
-	0x4e, 0x19, 0x8c, 0xf0, 0x65, 0xa6, 0x70, 0x7a, 0x14, 0x96, 0x80, 0x8a, 0xfb, 0x95, 0x5a, 0x02,
-	0x6a, 0x1f, 0x02, 0x92, 0x6a, 0x77, 0xbf, 0x8c, 0x71, 0xc8, 0x66, 0xc0, 0xb3, 0x05, 0x99, 0x02,
-	0xe7, 0x63, 0x5f, 0xae, 0xbe, 0x7d, 0x17, 0xac, 0x53, 0xec, 0x1f, 0x27, 0x84, 0x11, 0xca, 0x33,
-	0x20, 0x0b, 0x0c, 0x8e, 0xa7, 0x08, 0xe9, 0xd1, 0xfe, 0xaa, 0x83, 0x79, 0x1c, 0x45, 0x61, 0xba,
-	0x3a, 0x14, 0x8f, 0x48, 0xe6, 0x13, 0x67, 0xf4, 0x1e, 0x36, 0xb3, 0x86, 0x92, 0xb4, 0xcd, 0xde,
-	0x50, 0xa0, 0x4c, 0x37, 0xf4, 0xa6, 0x32, 0x97, 0x05, 0x31, 0x5c, 0xe4, 0xcd, 0x9b, 0x18, 0xfa,
-	0x08, 0x5b, 0x59, 0x1f, 0x44, 0xb4, 0x97, 0x03, 0xca, 0x41, 0xef, 0xaa, 0x2b, 0xbf, 0xa0, 0x82,
-	0xbb, 0xc1, 0x16, 0x6c, 0x0c, 0xbd, 0x85, 0x0d, 0x8e, 0xfd, 0x5e, 0x2c, 0xdb, 0xcc, 0x01, 0xe5,
-	0xeb, 0x71, 0x43, 0x7d, 0x3d, 0xe6, 0xb4, 0x70, 0xd7, 0xf9, 0x9c, 0x85, 0x65, 0xb3, 0xfd, 0xad,
-	0x41, 0xed, 0x5d, 0x9a, 0x73, 0x9c, 0x44, 0xc3, 0x20, 0x24, 0x4b, 0xa5, 0xd9, 0x83, 0x42, 0x1c,
-	0x45, 0xa1, 0xbc, 0x6a, 0xd5, 0xfd, 0x35, 0xa5, 0x52, 0x2a, 0xa7, 0x2b, 0xbd, 0xe8, 0xf5, 0x92,
-	0x37, 0x4d, 0xbd, 0xd9, 0x6a, 0x9d, 0xeb, 0xdb, 0x68, 0xd3, 0x2a, 0xd8, 0x3f, 0x75, 0x28, 0x08,
-	0x36, 0x68, 0x07, 0xca, 0x82, 0x5c, 0x2f, 0xff, 0x25, 0x94, 0xc4, 0xf7, 0xa1, 0x87, 0xee, 0xc0,
-	0x8a, 0x74, 0xc5, 0x92, 0x72, 0xb6, 0x71, 0xb5, 0x91, 0x2a, 0xd7, 0x1e, 0xac, 0xca, 0xa0, 0xe1,
-	0x98, 0xca, 0x7b, 0x6e, 0x88, 0x28, 0x99, 0x7a, 0x90, 0x19, 0xd1, 0x03, 0x28, 0x71, 0xf1, 0xa2,
-	0x4f, 0xc7, 0xbf, 0xbe, 0xf0, 0xd6, 0xbb, 0xd3, 0x08, 0xf4, 0xe2, 0x8a, 0x8e, 0x25, 0x11, 0xdf,
-	0x9c, 0xd7, 0xf1, 0x3a, 0x04, 0x2c, 0x58, 0xc5, 0x23, 0xb3, 0x5c, 0xb4, 0x4a, 0x2f, 0x9d, 0xcf,
-	0xcd, 0x94, 0xcf, 0x43, 0x49, 0xc8, 0x23, 0x17, 0xed, 0xd9, 0x67, 0x3b, 0x3e, 0xf7, 0xdb, 0x71,
-	0xff, 0x9b, 0x5e, 0xf9, 0x10, 0x13, 0x2a, 0xc8, 0xf6, 0x8b, 0x02, 0xf4, 0xd1, 0x9f, 0x00, 0x00,
-	0x00, 0xff, 0xff, 0x57, 0x22, 0xdc, 0xde, 0xda, 0x07, 0x00, 0x00,
+	// 1175 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x56, 0xdd, 0x6e, 0xe3, 0x44,
+	0x14, 0x26, 0xb6, 0xf3, 0x77, 0x92, 0xb6, 0xce, 0xb4, 0xd5, 0x7a, 0xbb, 0x2c, 0x84, 0x2c, 0x15,
+	0x15, 0x68, 0x13, 0x51, 0x84, 0x84, 0xf8, 0x11, 0xa4, 0xad, 0xdb, 0xa6, 0xb0, 0x6d, 0x70, 0xca,
+	0x0a, 0x71, 0x63, 0x4d, 0xe3, 0x89, 0xd7, 0xaa, 0x33, 0x36, 0x1e, 0xa7, 0x6a, 0x1e, 0x81, 0x57,
+	0x41, 0xe2, 0x8e, 0x6b, 0x5e, 0x81, 0x1b, 0x6e, 0xb8, 0xe1, 0x59, 0xd0, 0xcc, 0xd8, 0x89, 0x1b,
+	0x87, 0x94, 0xbd, 0xa8, 0xf6, 0xce, 0x3e, 0xe7, 0x3b, 0x67, 0x66, 0xbe, 0xef, 0xcc, 0x9c, 0x03,
+	0x08, 0x87, 0x5e, 0x67, 0x4c, 0x18, 0xc3, 0x2e, 0x61, 0xed, 0x30, 0x0a, 0xe2, 0x00, 0x55, 0x83,
+	0x90, 0xd0, 0x31, 0x8e, 0x87, 0xaf, 0x76, 0x1e, 0xb9, 0x41, 0xe0, 0xfa, 0xa4, 0x13, 0x85, 0xc3,
+	0x0e, 0x8b, 0x71, 0x3c, 0x49, 0x30, 0x3b, 0x8f, 0x13, 0x87, 0xf8, 0xbb, 0x9a, 0x8c, 0x3a, 0x98,
+	0x4e, 0x13, 0xd7, 0xbb, 0x8b, 0xae, 0xd8, 0x1b, 0x13, 0x16, 0xe3, 0x71, 0x28, 0x01, 0xad, 0xdf,
+	0x34, 0x28, 0x5d, 0x7a, 0xc3, 0x6b, 0x12, 0xa3, 0x75, 0x50, 0x3c, 0xc7, 0x28, 0x34, 0x0b, 0x7b,
+	0x55, 0x4b, 0xf1, 0x1c, 0xf4, 0x29, 0x00, 0x66, 0xcc, 0x73, 0xe9, 0x98, 0xd0, 0xd8, 0x50, 0x9b,
+	0x85, 0xbd, 0xda, 0xfe, 0x76, 0x7b, 0xb6, 0x9f, 0x76, 0x77, 0xe6, 0xb4, 0x32, 0x40, 0xf4, 0x25,
+	0xac, 0x31, 0x82, 0xa3, 0xe1, 0x2b, 0x7b, 0xe4, 0x11, 0xdf, 0x61, 0x86, 0x26, 0x22, 0x1f, 0x65,
+	0x22, 0x07, 0xc2, 0x7f, 0x2c, 0xdc, 0x56, 0x9d, 0x65, 0xfe, 0x50, 0x17, 0x80, 0xdc, 0xc6, 0x84,
+	0x32, 0x2f, 0xa0, 0xcc, 0x28, 0x36, 0xd5, 0xbd, 0xda, 0xfe, 0x7b, 0x99, 0x50, 0xb9, 0xd7, 0xb6,
+	0x39, 0xc3, 0x98, 0x34, 0x8e, 0xa6, 0x56, 0x26, 0x08, 0x3d, 0x05, 0x08, 0x7d, 0x3c, 0x25, 0x91,
+	0xed, 0x39, 0xcc, 0x28, 0x35, 0xd5, 0xbd, 0xaa, 0x55, 0x95, 0x96, 0x9e, 0xc3, 0xd0, 0x13, 0xa8,
+	0xe2, 0x9b, 0xc0, 0x73, 0x84, 0xb7, 0x2c, 0xbc, 0x15, 0x61, 0xe0, 0xce, 0x0e, 0x6c, 0xce, 0x8f,
+	0x62, 0x8f, 0xb0, 0xe7, 0x4f, 0x22, 0xc2, 0x8c, 0x4a, 0xb3, 0xb0, 0x57, 0xb4, 0xd0, 0xdc, 0x75,
+	0x9c, 0x78, 0xd0, 0x17, 0x50, 0x1b, 0x46, 0x04, 0xc7, 0xc4, 0xe6, 0xcc, 0x1a, 0x55, 0x71, 0xd6,
+	0x9d, 0xb6, 0xa4, 0xbd, 0x9d, 0xd2, 0xde, 0xbe, 0x4c, 0x69, 0xb7, 0x40, 0xc2, 0xb9, 0x61, 0x67,
+	0x00, 0x1b, 0x0b, 0x07, 0x41, 0x3a, 0xa8, 0xd7, 0x64, 0x9a, 0xa8, 0xc0, 0x3f, 0xd1, 0x87, 0x50,
+	0xbc, 0xc1, 0xfe, 0x84, 0x18, 0x8a, 0xc8, 0xbd, 0x95, 0xcb, 0xdd, 0xa5, 0x53, 0x4b, 0x42, 0x3e,
+	0x57, 0x3e, 0x2b, 0xb4, 0x5e, 0x42, 0x71, 0x10, 0xe3, 0x98, 0xa0, 0x6d, 0x68, 0x0c, 0x2e, 0xbb,
+	0x97, 0xa6, 0xfd, 0xc3, 0xf9, 0xa0, 0x6f, 0x1e, 0xf6, 0x8e, 0x7b, 0xe6, 0x91, 0xfe, 0x16, 0x5a,
+	0x83, 0xea, 0xc0, 0xec, 0x5a, 0x87, 0xa7, 0xbd, 0xf3, 0x13, 0xbd, 0x80, 0xea, 0x50, 0xe9, 0x5b,
+	0x17, 0xfd, 0x8b, 0x81, 0x79, 0xa4, 0x2b, 0xfc, 0xaf, 0x3b, 0x18, 0xf4, 0x4e, 0xce, 0xcd, 0x23,
+	0x5d, 0x45, 0x35, 0x28, 0x9b, 0x3f, 0xf6, 0x7b, 0x96, 0x79, 0xa4, 0x6b, 0x67, 0x5a, 0x45, 0xd1,
+	0xd5, 0xd6, 0xef, 0x0a, 0xd4, 0xb3, 0xf2, 0xa1, 0x53, 0xa8, 0x39, 0xc1, 0xe4, 0xca, 0x27, 0x36,
+	0x8e, 0x5c, 0x66, 0x14, 0x84, 0x62, 0x1f, 0xfc, 0x87, 0xd8, 0xed, 0x23, 0x01, 0xed, 0x46, 0x6e,
+	0xaa, 0x9b, 0x33, 0x33, 0xf0, 0x4c, 0x2c, 0x8e, 0x3c, 0xea, 0xca, 0x4c, 0xca, 0xea, 0x4c, 0x03,
+	0x01, 0xcd, 0x64, 0x62, 0x33, 0x03, 0x42, 0xa0, 0xc5, 0xd8, 0x65, 0x86, 0x2a, 0xd4, 0x15, 0xdf,
+	0x3b, 0x5f, 0xc1, 0xc6, 0xc2, 0xe2, 0x4b, 0xb8, 0xde, 0xca, 0x72, 0x5d, 0xc8, 0xb0, 0xca, 0xc3,
+	0x17, 0x56, 0xbc, 0x2f, 0xbc, 0x9a, 0x15, 0xe5, 0xaf, 0x02, 0xc0, 0xfc, 0xbe, 0xa0, 0x77, 0x00,
+	0x86, 0x01, 0xa5, 0x64, 0x18, 0x7b, 0x01, 0x4d, 0x32, 0x64, 0x2c, 0xc8, 0xbc, 0x73, 0x0b, 0x34,
+	0xc1, 0xc4, 0xee, 0xd2, 0xab, 0xb7, 0xea, 0x26, 0x3c, 0x48, 0x7d, 0xc9, 0x3a, 0x38, 0xd3, 0x2a,
+	0xaa, 0xae, 0xb5, 0x5e, 0x42, 0x43, 0x92, 0x6a, 0x61, 0xea, 0x92, 0x63, 0xcf, 0x8f, 0x49, 0xc4,
+	0xef, 0xdf, 0xbc, 0x22, 0x92, 0x95, 0xaa, 0x33, 0x9d, 0xf9, 0x0e, 0xc6, 0xf8, 0x36, 0x61, 0x98,
+	0x7f, 0x0a, 0x8b, 0x47, 0x0d, 0x35, 0xb1, 0x78, 0xb4, 0xd5, 0x03, 0x24, 0xd9, 0x36, 0x7f, 0x9e,
+	0x60, 0x9f, 0xcd, 0x13, 0xcf, 0x0b, 0x24, 0x4d, 0x3c, 0x93, 0x7d, 0x39, 0xfb, 0xad, 0xf7, 0x41,
+	0xbf, 0xc4, 0x6e, 0x3f, 0x22, 0x8c, 0x5f, 0x5b, 0x99, 0x48, 0x07, 0x35, 0xc6, 0x69, 0x06, 0xfe,
+	0xd9, 0xfa, 0x45, 0x05, 0x5d, 0x3a, 0xcd, 0xdb, 0x30, 0x22, 0x8c, 0x33, 0x86, 0x9e, 0x83, 0x8a,
+	0x7d, 0x3f, 0x29, 0xe9, 0x27, 0x19, 0xfa, 0x17, 0x91, 0x16, 0xc7, 0x09, 0x38, 0x9d, 0x1a, 0xca,
+	0xff, 0x81, 0xd3, 0x29, 0x87, 0xd3, 0x20, 0x7d, 0x57, 0x57, 0xc3, 0x69, 0x10, 0xa3, 0xef, 0x60,
+	0x33, 0x61, 0x35, 0xe2, 0x5c, 0xdb, 0x23, 0x81, 0x4a, 0x1e, 0xd7, 0xb7, 0x33, 0xe1, 0x39, 0x41,
+	0xac, 0x86, 0x93, 0xd3, 0xe8, 0x02, 0xb6, 0x12, 0x2a, 0x89, 0x60, 0x38, 0x4d, 0x57, 0x14, 0xe9,
+	0x9e, 0x66, 0x2f, 0x5d, 0x4e, 0x07, 0x0b, 0xb1, 0xbc, 0x36, 0x3d, 0x40, 0x31, 0x76, 0xed, 0x50,
+	0xf2, 0x9c, 0xa6, 0x2b, 0xe5, 0x0e, 0xb7, 0xa8, 0x85, 0xa5, 0xc7, 0x0b, 0x96, 0xd6, 0x3f, 0x2a,
+	0x68, 0xfd, 0x20, 0xf0, 0xf9, 0x35, 0xa6, 0x78, 0x4c, 0x12, 0x9d, 0xc4, 0x37, 0x3a, 0x87, 0xad,
+	0x25, 0x34, 0xa4, 0xaf, 0xc5, 0x6a, 0x1e, 0x50, 0x8e, 0x07, 0x86, 0xbe, 0x87, 0xed, 0x65, 0x44,
+	0xa4, 0x97, 0xee, 0x1e, 0x26, 0x36, 0xf3, 0x4c, 0x30, 0xf4, 0x2d, 0x6c, 0xe6, 0xa9, 0x48, 0x7b,
+	0xd9, 0x4a, 0x2e, 0x1a, 0x8b, 0x5c, 0xf0, 0x7e, 0xb8, 0x2e, 0x1b, 0x86, 0x63, 0x5f, 0x91, 0x51,
+	0x10, 0x11, 0xa3, 0x74, 0x6f, 0x8b, 0x59, 0x4b, 0x22, 0x0e, 0x44, 0x00, 0xfa, 0x1a, 0x52, 0x83,
+	0x8d, 0x47, 0x5c, 0x95, 0xf2, 0xbd, 0x19, 0xea, 0x49, 0x40, 0x97, 0xe3, 0xd1, 0x29, 0x34, 0xe4,
+	0x21, 0x6c, 0x32, 0x2b, 0x4a, 0xd1, 0x12, 0xef, 0xa9, 0x5b, 0x7d, 0xb4, 0x60, 0x49, 0x5e, 0x8d,
+	0xbf, 0x15, 0xa8, 0xbf, 0xe0, 0x21, 0xfd, 0x28, 0x18, 0x79, 0x3e, 0x59, 0x2a, 0xf4, 0x2e, 0x14,
+	0xc3, 0x20, 0xf0, 0xe5, 0x23, 0x5e, 0xdb, 0xdf, 0xc8, 0x2c, 0xc4, 0x8b, 0xc3, 0x92, 0x5e, 0x74,
+	0xb2, 0x64, 0x5e, 0xc8, 0xf6, 0x8c, 0xec, 0x3a, 0x2b, 0xa7, 0x86, 0x8f, 0x61, 0x7b, 0x8c, 0x6f,
+	0xed, 0x58, 0xcc, 0x17, 0xcc, 0x0e, 0x49, 0x64, 0x8b, 0x0c, 0x82, 0xef, 0xa2, 0x85, 0xc6, 0xf8,
+	0x56, 0xce, 0x1e, 0xac, 0x4f, 0x22, 0x91, 0x35, 0x0d, 0x11, 0x30, 0x22, 0x43, 0x86, 0xd3, 0xa1,
+	0x4f, 0x8c, 0xf2, 0x2c, 0xe4, 0x85, 0xf4, 0xf5, 0x49, 0x74, 0xc8, 0x3d, 0x0f, 0xfb, 0x22, 0x6b,
+	0x7a, 0xb1, 0xf5, 0xa7, 0x02, 0x95, 0x03, 0x3c, 0xbc, 0x1e, 0x79, 0xbe, 0x9f, 0x9b, 0xe8, 0x72,
+	0xa3, 0x99, 0xf2, 0x3a, 0xa3, 0xd9, 0xe1, 0x1d, 0xaa, 0xa5, 0x2c, 0xcf, 0x32, 0xa1, 0xe9, 0xb2,
+	0x2b, 0x69, 0x5e, 0x98, 0x97, 0xb4, 0xd7, 0x99, 0x97, 0x78, 0xdb, 0x74, 0x09, 0x25, 0x11, 0x16,
+	0x6d, 0x93, 0xbf, 0x55, 0xaa, 0x95, 0xb1, 0x3c, 0xcc, 0x3c, 0xf5, 0x87, 0x02, 0x45, 0xa9, 0xf7,
+	0x63, 0xa8, 0x88, 0x93, 0xda, 0x33, 0x52, 0xcb, 0xe2, 0xbf, 0xe7, 0xa0, 0x67, 0xb0, 0x26, 0x5d,
+	0xa1, 0x2c, 0xb5, 0xa4, 0x07, 0xd5, 0xc7, 0xd9, 0x32, 0xdf, 0x85, 0x75, 0x09, 0x1a, 0x4d, 0xa8,
+	0xec, 0xfc, 0xaa, 0x40, 0xc9, 0xd0, 0xe3, 0xc4, 0x88, 0x3e, 0x82, 0x72, 0x52, 0x85, 0xc9, 0x23,
+	0xd4, 0xc8, 0xcd, 0xbf, 0x56, 0x8a, 0x40, 0xdf, 0xdc, 0x11, 0xa5, 0x2c, 0xf0, 0xcd, 0xc5, 0xfa,
+	0x7f, 0x13, 0x43, 0x42, 0x51, 0x2f, 0x9d, 0x69, 0x95, 0x92, 0x5e, 0x3e, 0x68, 0xff, 0xd4, 0xe4,
+	0xfb, 0x79, 0x2e, 0x37, 0xe4, 0x90, 0x9b, 0xce, 0xfc, 0xb7, 0x13, 0x5e, 0xbb, 0x9d, 0xf0, 0xea,
+	0x57, 0xa5, 0x7a, 0x11, 0x12, 0x2a, 0x36, 0x7b, 0x55, 0x12, 0x49, 0x3f, 0xf9, 0x77, 0x00, 0xb9,
+	0x8a, 0xd0, 0xa2, 0x0f, 0x0d, 0x00, 0x00,
 }
@@ -9,6 +9,7 @@ import (
 	proto "github.com/golang/protobuf/proto"
 	_ "github.com/grpc-ecosystem/grpc-gateway/protoc-gen-swagger/options"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
+	field_mask "google.golang.org/genproto/protobuf/field_mask"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -28,7 +29,27 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type QueryTicketsRequest struct {
 	// A Pool is consists of a set of Filters.
-	Pool                 *Pool    `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	Pool *Pool `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	// The Ticket fields to return, eg. `search_fields.double_args`, `assignment`.
+	// Paths are Ticket fields or fields of its search_fields, and the id is
+	// always returned.  Optional, all fields are returned if unset.
+	FieldMask *field_mask.FieldMask `protobuf:"bytes,2,opt,name=field_mask,json=fieldMask,proto3" json:"field_mask,omitempty"`
+	// The order to return the Tickets in, before paging them.  Optional, the
+	// order is unspecified if unset.
+	OrderBy *TicketOrder `protobuf:"bytes,3,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	// Number of Tickets per streamed response, at most storage.page.maxSize.
+	// Optional, storage.page.size is used if unset.
+	PageSize int32 `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Maximum number of Tickets returned, eg. the first Tickets of order_by.
+	// Optional, all Tickets in the Pool are returned if unset.
+	MaxResults int32 `protobuf:"varint,5,opt,name=max_results,json=maxResults,proto3" json:"max_results,omitempty"`
+	// Snapshot the Tickets the query is evaluated against, returning a
+	// consistency_token for further queries to see the same Tickets.
+	TakeSnapshot bool `protobuf:"varint,6,opt,name=take_snapshot,json=takeSnapshot,proto3" json:"take_snapshot,omitempty"`
+	// Evaluate the query against the Tickets of the snapshot taken by an earlier
+	// query, eg. so that every Pool of a match function run sees the same
+	// Tickets.  Snapshots expire after query.snapshotTTL.
+	ConsistencyToken     string   `protobuf:"bytes,7,opt,name=consistency_token,json=consistencyToken,proto3" json:"consistency_token,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -66,19 +87,125 @@ func (m *QueryTicketsRequest) GetPool() *Pool {
 	return nil
 }
 
+func (m *QueryTicketsRequest) GetFieldMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.FieldMask
+	}
+	return nil
+}
+
+func (m *QueryTicketsRequest) GetOrderBy() *TicketOrder {
+	if m != nil {
+		return m.OrderBy
+	}
+	return nil
+}
+
+func (m *QueryTicketsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *QueryTicketsRequest) GetMaxResults() int32 {
+	if m != nil {
+		return m.MaxResults
+	}
+	return 0
+}
+
+func (m *QueryTicketsRequest) GetTakeSnapshot() bool {
+	if m != nil {
+		return m.TakeSnapshot
+	}
+	return false
+}
+
+func (m *QueryTicketsRequest) GetConsistencyToken() string {
+	if m != nil {
+		return m.ConsistencyToken
+	}
+	return ""
+}
+
+// TicketOrder sorts the Tickets returned by QueryTickets by a single key, and
+// then by id.  Exactly one of double_arg and create_time must be set.
+type TicketOrder struct {
+	// Name of the search_fields.double_args to sort by.  Tickets without the arg,
+	// or with a NaN value, are returned last.
+	DoubleArg string `protobuf:"bytes,1,opt,name=double_arg,json=doubleArg,proto3" json:"double_arg,omitempty"`
+	// Sort by the time the Tickets were created.
+	CreateTime bool `protobuf:"varint,2,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// Sort in descending rather than ascending order.
+	Descending           bool     `protobuf:"varint,3,opt,name=descending,proto3" json:"descending,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TicketOrder) Reset()         { *m = TicketOrder{} }
+func (m *TicketOrder) String() string { return proto.CompactTextString(m) }
+func (*TicketOrder) ProtoMessage()    {}
+func (*TicketOrder) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ec7651f31a90698, []int{1}
+}
+
+func (m *TicketOrder) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TicketOrder.Unmarshal(m, b)
+}
+func (m *TicketOrder) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TicketOrder.Marshal(b, m, deterministic)
+}
+func (m *TicketOrder) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TicketOrder.Merge(m, src)
+}
+func (m *TicketOrder) XXX_Size() int {
+	return xxx_messageInfo_TicketOrder.Size(m)
+}
+func (m *TicketOrder) XXX_DiscardUnknown() {
+	xxx_messageInfo_TicketOrder.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TicketOrder proto.InternalMessageInfo
+
+func (m *TicketOrder) GetDoubleArg() string {
+	if m != nil {
+		return m.DoubleArg
+	}
+	return ""
+}
+
+func (m *TicketOrder) GetCreateTime() bool {
+	if m != nil {
+		return m.CreateTime
+	}
+	return false
+}
+
+func (m *TicketOrder) GetDescending() bool {
+	if m != nil {
+		return m.Descending
+	}
+	return false
+}
+
 type QueryTicketsResponse struct {
 	// Tickets that satisfy all the filtering criteria.
-	Tickets              []*Ticket `protobuf:"bytes,1,rep,name=tickets,proto3" json:"tickets,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
-	XXX_unrecognized     []byte    `json:"-"`
-	XXX_sizecache        int32     `json:"-"`
+	Tickets []*Ticket `protobuf:"bytes,1,rep,name=tickets,proto3" json:"tickets,omitempty"`
+	// Token of the snapshot the query was evaluated against, if any.  Set on
+	// every response of the stream, which holds at least one response then.
+	ConsistencyToken     string   `protobuf:"bytes,2,opt,name=consistency_token,json=consistencyToken,proto3" json:"consistency_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *QueryTicketsResponse) Reset()         { *m = QueryTicketsResponse{} }
 func (m *QueryTicketsResponse) String() string { return proto.CompactTextString(m) }
 func (*QueryTicketsResponse) ProtoMessage()    {}
 func (*QueryTicketsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ec7651f31a90698, []int{1}
+	return fileDescriptor_5ec7651f31a90698, []int{2}
 }
 
 func (m *QueryTicketsResponse) XXX_Unmarshal(b []byte) error {
@@ -106,48 +233,560 @@ func (m *QueryTicketsResponse) GetTickets() []*Ticket {
 	return nil
 }
 
+func (m *QueryTicketsResponse) GetConsistencyToken() string {
+	if m != nil {
+		return m.ConsistencyToken
+	}
+	return ""
+}
+
+type QueryTicketIdsRequest struct {
+	// A Pool is consists of a set of Filters.
+	Pool *Pool `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	// As QueryTicketsRequest.take_snapshot.
+	TakeSnapshot bool `protobuf:"varint,2,opt,name=take_snapshot,json=takeSnapshot,proto3" json:"take_snapshot,omitempty"`
+	// As QueryTicketsRequest.consistency_token.
+	ConsistencyToken     string   `protobuf:"bytes,3,opt,name=consistency_token,json=consistencyToken,proto3" json:"consistency_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryTicketIdsRequest) Reset()         { *m = QueryTicketIdsRequest{} }
+func (m *QueryTicketIdsRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryTicketIdsRequest) ProtoMessage()    {}
+func (*QueryTicketIdsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ec7651f31a90698, []int{3}
+}
+
+func (m *QueryTicketIdsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryTicketIdsRequest.Unmarshal(m, b)
+}
+func (m *QueryTicketIdsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryTicketIdsRequest.Marshal(b, m, deterministic)
+}
+func (m *QueryTicketIdsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryTicketIdsRequest.Merge(m, src)
+}
+func (m *QueryTicketIdsRequest) XXX_Size() int {
+	return xxx_messageInfo_QueryTicketIdsRequest.Size(m)
+}
+func (m *QueryTicketIdsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryTicketIdsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryTicketIdsRequest proto.InternalMessageInfo
+
+func (m *QueryTicketIdsRequest) GetPool() *Pool {
+	if m != nil {
+		return m.Pool
+	}
+	return nil
+}
+
+func (m *QueryTicketIdsRequest) GetTakeSnapshot() bool {
+	if m != nil {
+		return m.TakeSnapshot
+	}
+	return false
+}
+
+func (m *QueryTicketIdsRequest) GetConsistencyToken() string {
+	if m != nil {
+		return m.ConsistencyToken
+	}
+	return ""
+}
+
+type QueryTicketIdsResponse struct {
+	// Ids of the Tickets that satisfy all the filtering criteria.
+	Ids []string `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	// As QueryTicketsResponse.consistency_token.
+	ConsistencyToken     string   `protobuf:"bytes,2,opt,name=consistency_token,json=consistencyToken,proto3" json:"consistency_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryTicketIdsResponse) Reset()         { *m = QueryTicketIdsResponse{} }
+func (m *QueryTicketIdsResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryTicketIdsResponse) ProtoMessage()    {}
+func (*QueryTicketIdsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ec7651f31a90698, []int{4}
+}
+
+func (m *QueryTicketIdsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryTicketIdsResponse.Unmarshal(m, b)
+}
+func (m *QueryTicketIdsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryTicketIdsResponse.Marshal(b, m, deterministic)
+}
+func (m *QueryTicketIdsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryTicketIdsResponse.Merge(m, src)
+}
+func (m *QueryTicketIdsResponse) XXX_Size() int {
+	return xxx_messageInfo_QueryTicketIdsResponse.Size(m)
+}
+func (m *QueryTicketIdsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryTicketIdsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryTicketIdsResponse proto.InternalMessageInfo
+
+func (m *QueryTicketIdsResponse) GetIds() []string {
+	if m != nil {
+		return m.Ids
+	}
+	return nil
+}
+
+func (m *QueryTicketIdsResponse) GetConsistencyToken() string {
+	if m != nil {
+		return m.ConsistencyToken
+	}
+	return ""
+}
+
+type GetPoolStatsRequest struct {
+	// A Pool is consists of a set of Filters.
+	Pool *Pool `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	// Name of the search_fields.double_args to build a histogram of.  Optional,
+	// no histogram is returned if unset.
+	HistogramDoubleArg string `protobuf:"bytes,2,opt,name=histogram_double_arg,json=histogramDoubleArg,proto3" json:"histogram_double_arg,omitempty"`
+	// Strictly ascending bucket boundaries of the histogram.  n boundaries make
+	// n+1 buckets, the first and last being unbounded.
+	HistogramBounds      []float64 `protobuf:"fixed64,3,rep,packed,name=histogram_bounds,json=histogramBounds,proto3" json:"histogram_bounds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *GetPoolStatsRequest) Reset()         { *m = GetPoolStatsRequest{} }
+func (m *GetPoolStatsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetPoolStatsRequest) ProtoMessage()    {}
+func (*GetPoolStatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ec7651f31a90698, []int{5}
+}
+
+func (m *GetPoolStatsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPoolStatsRequest.Unmarshal(m, b)
+}
+func (m *GetPoolStatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPoolStatsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetPoolStatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPoolStatsRequest.Merge(m, src)
+}
+func (m *GetPoolStatsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetPoolStatsRequest.Size(m)
+}
+func (m *GetPoolStatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPoolStatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPoolStatsRequest proto.InternalMessageInfo
+
+func (m *GetPoolStatsRequest) GetPool() *Pool {
+	if m != nil {
+		return m.Pool
+	}
+	return nil
+}
+
+func (m *GetPoolStatsRequest) GetHistogramDoubleArg() string {
+	if m != nil {
+		return m.HistogramDoubleArg
+	}
+	return ""
+}
+
+func (m *GetPoolStatsRequest) GetHistogramBounds() []float64 {
+	if m != nil {
+		return m.HistogramBounds
+	}
+	return nil
+}
+
+// HistogramBucket counts the Tickets whose value is in [min, max).
+type HistogramBucket struct {
+	// Inclusive lower bound, -Infinity for the first bucket.
+	Min float64 `protobuf:"fixed64,1,opt,name=min,proto3" json:"min,omitempty"`
+	// Exclusive upper bound, Infinity for the last bucket.
+	Max                  float64  `protobuf:"fixed64,2,opt,name=max,proto3" json:"max,omitempty"`
+	TicketCount          int64    `protobuf:"varint,3,opt,name=ticket_count,json=ticketCount,proto3" json:"ticket_count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HistogramBucket) Reset()         { *m = HistogramBucket{} }
+func (m *HistogramBucket) String() string { return proto.CompactTextString(m) }
+func (*HistogramBucket) ProtoMessage()    {}
+func (*HistogramBucket) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ec7651f31a90698, []int{6}
+}
+
+func (m *HistogramBucket) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistogramBucket.Unmarshal(m, b)
+}
+func (m *HistogramBucket) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistogramBucket.Marshal(b, m, deterministic)
+}
+func (m *HistogramBucket) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistogramBucket.Merge(m, src)
+}
+func (m *HistogramBucket) XXX_Size() int {
+	return xxx_messageInfo_HistogramBucket.Size(m)
+}
+func (m *HistogramBucket) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistogramBucket.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistogramBucket proto.InternalMessageInfo
+
+func (m *HistogramBucket) GetMin() float64 {
+	if m != nil {
+		return m.Min
+	}
+	return 0
+}
+
+func (m *HistogramBucket) GetMax() float64 {
+	if m != nil {
+		return m.Max
+	}
+	return 0
+}
+
+func (m *HistogramBucket) GetTicketCount() int64 {
+	if m != nil {
+		return m.TicketCount
+	}
+	return 0
+}
+
+type GetPoolStatsResponse struct {
+	// Number of indexed Tickets that satisfy all the filtering criteria,
+	// including Tickets currently proposed in matches unless the Pool has a
+	// filter_expression or a histogram is requested.
+	TicketCount int64 `protobuf:"varint,1,opt,name=ticket_count,json=ticketCount,proto3" json:"ticket_count,omitempty"`
+	// Counts of the Tickets per bucket of the requested histogram.
+	Histogram []*HistogramBucket `protobuf:"bytes,2,rep,name=histogram,proto3" json:"histogram,omitempty"`
+	// Number of Tickets in the Pool without the histogram's double arg, which
+	// aren't in any bucket.
+	HistogramMissingCount int64    `protobuf:"varint,3,opt,name=histogram_missing_count,json=histogramMissingCount,proto3" json:"histogram_missing_count,omitempty"`
+	XXX_NoUnkeyedLiteral  struct{} `json:"-"`
+	XXX_unrecognized      []byte   `json:"-"`
+	XXX_sizecache         int32    `json:"-"`
+}
+
+func (m *GetPoolStatsResponse) Reset()         { *m = GetPoolStatsResponse{} }
+func (m *GetPoolStatsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetPoolStatsResponse) ProtoMessage()    {}
+func (*GetPoolStatsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ec7651f31a90698, []int{7}
+}
+
+func (m *GetPoolStatsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPoolStatsResponse.Unmarshal(m, b)
+}
+func (m *GetPoolStatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPoolStatsResponse.Marshal(b, m, deterministic)
+}
+func (m *GetPoolStatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPoolStatsResponse.Merge(m, src)
+}
+func (m *GetPoolStatsResponse) XXX_Size() int {
+	return xxx_messageInfo_GetPoolStatsResponse.Size(m)
+}
+func (m *GetPoolStatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPoolStatsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPoolStatsResponse proto.InternalMessageInfo
+
+func (m *GetPoolStatsResponse) GetTicketCount() int64 {
+	if m != nil {
+		return m.TicketCount
+	}
+	return 0
+}
+
+func (m *GetPoolStatsResponse) GetHistogram() []*HistogramBucket {
+	if m != nil {
+		return m.Histogram
+	}
+	return nil
+}
+
+func (m *GetPoolStatsResponse) GetHistogramMissingCount() int64 {
+	if m != nil {
+		return m.HistogramMissingCount
+	}
+	return 0
+}
+
+type ExplainTicketRequest struct {
+	// A TicketId of a generated Ticket.
+	TicketId string `protobuf:"bytes,1,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	// The Pool to check the Ticket against.
+	Pool                 *Pool    `protobuf:"bytes,2,opt,name=pool,proto3" json:"pool,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExplainTicketRequest) Reset()         { *m = ExplainTicketRequest{} }
+func (m *ExplainTicketRequest) String() string { return proto.CompactTextString(m) }
+func (*ExplainTicketRequest) ProtoMessage()    {}
+func (*ExplainTicketRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ec7651f31a90698, []int{8}
+}
+
+func (m *ExplainTicketRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExplainTicketRequest.Unmarshal(m, b)
+}
+func (m *ExplainTicketRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExplainTicketRequest.Marshal(b, m, deterministic)
+}
+func (m *ExplainTicketRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExplainTicketRequest.Merge(m, src)
+}
+func (m *ExplainTicketRequest) XXX_Size() int {
+	return xxx_messageInfo_ExplainTicketRequest.Size(m)
+}
+func (m *ExplainTicketRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExplainTicketRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExplainTicketRequest proto.InternalMessageInfo
+
+func (m *ExplainTicketRequest) GetTicketId() string {
+	if m != nil {
+		return m.TicketId
+	}
+	return ""
+}
+
+func (m *ExplainTicketRequest) GetPool() *Pool {
+	if m != nil {
+		return m.Pool
+	}
+	return nil
+}
+
+// FilterResult reports whether a Ticket passes one criterion of a Pool.
+type FilterResult struct {
+	// Describes the criterion, eg. `double_range_filter mmr [1000, 2000]`.
+	Filter string `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Whether the Ticket passes the criterion.
+	Passed bool `protobuf:"varint,2,opt,name=passed,proto3" json:"passed,omitempty"`
+	// The Ticket's value of the field the criterion checks, eg. `1500`.  Empty if
+	// the Ticket doesn't have the field.
+	TicketValue          string   `protobuf:"bytes,3,opt,name=ticket_value,json=ticketValue,proto3" json:"ticket_value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FilterResult) Reset()         { *m = FilterResult{} }
+func (m *FilterResult) String() string { return proto.CompactTextString(m) }
+func (*FilterResult) ProtoMessage()    {}
+func (*FilterResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ec7651f31a90698, []int{9}
+}
+
+func (m *FilterResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FilterResult.Unmarshal(m, b)
+}
+func (m *FilterResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FilterResult.Marshal(b, m, deterministic)
+}
+func (m *FilterResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FilterResult.Merge(m, src)
+}
+func (m *FilterResult) XXX_Size() int {
+	return xxx_messageInfo_FilterResult.Size(m)
+}
+func (m *FilterResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_FilterResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FilterResult proto.InternalMessageInfo
+
+func (m *FilterResult) GetFilter() string {
+	if m != nil {
+		return m.Filter
+	}
+	return ""
+}
+
+func (m *FilterResult) GetPassed() bool {
+	if m != nil {
+		return m.Passed
+	}
+	return false
+}
+
+func (m *FilterResult) GetTicketValue() string {
+	if m != nil {
+		return m.TicketValue
+	}
+	return ""
+}
+
+type ExplainTicketResponse struct {
+	// Whether the Ticket passes every criterion of the Pool.
+	InPool bool `protobuf:"varint,1,opt,name=in_pool,json=inPool,proto3" json:"in_pool,omitempty"`
+	// Whether the Ticket is indexed and not proposed in a match, ie. whether
+	// QueryTickets returns it for Pools it's in.
+	Available bool `protobuf:"varint,2,opt,name=available,proto3" json:"available,omitempty"`
+	// The result of each Filter of the Pool, followed by those of the deployment
+	// wide default filters and of the Ticket's event reservation, if any.
+	FilterResults        []*FilterResult `protobuf:"bytes,3,rep,name=filter_results,json=filterResults,proto3" json:"filter_results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ExplainTicketResponse) Reset()         { *m = ExplainTicketResponse{} }
+func (m *ExplainTicketResponse) String() string { return proto.CompactTextString(m) }
+func (*ExplainTicketResponse) ProtoMessage()    {}
+func (*ExplainTicketResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ec7651f31a90698, []int{10}
+}
+
+func (m *ExplainTicketResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExplainTicketResponse.Unmarshal(m, b)
+}
+func (m *ExplainTicketResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExplainTicketResponse.Marshal(b, m, deterministic)
+}
+func (m *ExplainTicketResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExplainTicketResponse.Merge(m, src)
+}
+func (m *ExplainTicketResponse) XXX_Size() int {
+	return xxx_messageInfo_ExplainTicketResponse.Size(m)
+}
+func (m *ExplainTicketResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExplainTicketResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExplainTicketResponse proto.InternalMessageInfo
+
+func (m *ExplainTicketResponse) GetInPool() bool {
+	if m != nil {
+		return m.InPool
+	}
+	return false
+}
+
+func (m *ExplainTicketResponse) GetAvailable() bool {
+	if m != nil {
+		return m.Available
+	}
+	return false
+}
+
+func (m *ExplainTicketResponse) GetFilterResults() []*FilterResult {
+	if m != nil {
+		return m.FilterResults
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*QueryTicketsRequest)(nil), "openmatch.QueryTicketsRequest")
+	proto.RegisterType((*TicketOrder)(nil), "openmatch.TicketOrder")
 	proto.RegisterType((*QueryTicketsResponse)(nil), "openmatch.QueryTicketsResponse")
+	proto.RegisterType((*QueryTicketIdsRequest)(nil), "openmatch.QueryTicketIdsRequest")
+	proto.RegisterType((*QueryTicketIdsResponse)(nil), "openmatch.QueryTicketIdsResponse")
+	proto.RegisterType((*GetPoolStatsRequest)(nil), "openmatch.GetPoolStatsRequest")
+	proto.RegisterType((*HistogramBucket)(nil), "openmatch.HistogramBucket")
+	proto.RegisterType((*GetPoolStatsResponse)(nil), "openmatch.GetPoolStatsResponse")
+	proto.RegisterType((*ExplainTicketRequest)(nil), "openmatch.ExplainTicketRequest")
+	proto.RegisterType((*FilterResult)(nil), "openmatch.FilterResult")
+	proto.RegisterType((*ExplainTicketResponse)(nil), "openmatch.ExplainTicketResponse")
 }
 
 func init() { proto.RegisterFile("api/query.proto", fileDescriptor_5ec7651f31a90698) }
 
 var fileDescriptor_5ec7651f31a90698 = []byte{
-	// 516 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x52, 0xcd, 0x6e, 0x13, 0x3d,
-	0x14, 0xd5, 0x4c, 0xaa, 0x56, 0x75, 0x3f, 0xa9, 0x1f, 0xe6, 0x47, 0x55, 0x84, 0x8a, 0x49, 0x37,
-	0x69, 0x20, 0xe3, 0x74, 0xc8, 0x2a, 0x08, 0xa9, 0xa5, 0xed, 0xa2, 0x52, 0xc2, 0x4f, 0x8a, 0x58,
-	0xb0, 0x73, 0x9c, 0xcb, 0x8c, 0x69, 0xc6, 0xd7, 0xb5, 0x3d, 0x29, 0x95, 0x58, 0xb1, 0x66, 0x05,
-	0x1b, 0xc4, 0x23, 0xf0, 0x12, 0x3c, 0x04, 0xaf, 0x80, 0x78, 0x0e, 0x34, 0x33, 0x09, 0x0d, 0xb4,
-	0xac, 0x46, 0xbe, 0xe7, 0xdc, 0x73, 0xce, 0x1c, 0x9b, 0xac, 0x0b, 0xa3, 0xf8, 0x69, 0x0e, 0xf6,
-	0x3c, 0x32, 0x16, 0x3d, 0xd2, 0x55, 0x34, 0xa0, 0x33, 0xe1, 0x65, 0x5a, 0xa7, 0x05, 0x96, 0x81,
-	0x73, 0x22, 0x01, 0x57, 0xc1, 0xf5, 0xdb, 0x09, 0x62, 0x32, 0x01, 0x5e, 0x40, 0x42, 0x6b, 0xf4,
-	0xc2, 0x2b, 0xd4, 0x73, 0xf4, 0x7e, 0xf9, 0x91, 0xed, 0x04, 0x74, 0xdb, 0x9d, 0x89, 0x24, 0x01,
-	0xcb, 0xd1, 0x94, 0x8c, 0xcb, 0xec, 0x46, 0x8f, 0x5c, 0x7f, 0x5e, 0x38, 0xbf, 0x50, 0xf2, 0x04,
-	0xbc, 0x1b, 0xc2, 0x69, 0x0e, 0xce, 0xd3, 0x2d, 0xb2, 0x64, 0x10, 0x27, 0x1b, 0x01, 0x0b, 0x9a,
-	0x6b, 0xf1, 0x7a, 0xf4, 0x3b, 0x50, 0xf4, 0x0c, 0x71, 0x32, 0x2c, 0xc1, 0xc6, 0x3e, 0xb9, 0xf1,
-	0xe7, 0xae, 0x33, 0xa8, 0x1d, 0xd0, 0x7b, 0x64, 0xc5, 0x57, 0xa3, 0x8d, 0x80, 0xd5, 0x9a, 0x6b,
-	0xf1, 0xb5, 0x85, 0xfd, 0x8a, 0x3c, 0x9c, 0x33, 0xe2, 0x0f, 0x01, 0xf9, 0xaf, 0x54, 0x39, 0x06,
-	0x3b, 0x55, 0x12, 0xe8, 0xbb, 0xd9, 0x79, 0xa6, 0x4a, 0x37, 0x17, 0x96, 0xaf, 0x88, 0x5a, 0xbf,
-	0xf3, 0x4f, 0xbc, 0x8a, 0xd3, 0xd8, 0x7e, 0xff, 0xfd, 0xc7, 0xa7, 0x70, 0xab, 0xb1, 0xc9, 0xa7,
-	0x3b, 0x55, 0xcd, 0xae, 0xb2, 0xe2, 0xb3, 0x0c, 0xbd, 0x72, 0xd8, 0x0b, 0x5a, 0x9d, 0xe0, 0xf1,
-	0xe7, 0xda, 0xc7, 0xbd, 0x9f, 0x21, 0xfd, 0x16, 0x90, 0x9b, 0x83, 0x01, 0xeb, 0x63, 0xa2, 0x24,
-	0x6b, 0x1e, 0x08, 0x2f, 0x58, 0x5f, 0x9c, 0x83, 0xdd, 0x6e, 0x1c, 0x11, 0xf2, 0xd4, 0x80, 0x66,
-	0x83, 0xc2, 0x90, 0xde, 0x4a, 0xbd, 0x37, 0xae, 0xc7, 0x79, 0x91, 0xa1, 0x5d, 0x85, 0x18, 0xc3,
-	0xb4, 0xbe, 0x75, 0x71, 0x6e, 0x8f, 0x95, 0x93, 0xb9, 0x73, 0xbb, 0xd5, 0xad, 0x25, 0x16, 0x73,
-	0xe3, 0x22, 0x89, 0x59, 0xeb, 0x25, 0xa1, 0x7b, 0x46, 0xc8, 0x14, 0x58, 0x1c, 0x75, 0x58, 0x5f,
-	0x49, 0x28, 0xda, 0xdb, 0x9d, 0x4b, 0x26, 0xca, 0xa7, 0xf9, 0xa8, 0x60, 0xf2, 0x6a, 0xf5, 0x35,
-	0xda, 0x44, 0x64, 0xe0, 0x16, 0xcc, 0xf8, 0x68, 0x82, 0x23, 0x9e, 0x09, 0xe7, 0xc1, 0xf2, 0xfe,
-	0xd1, 0xfe, 0xe1, 0x93, 0xe3, 0xc3, 0xb8, 0xb6, 0x13, 0x75, 0x5a, 0x61, 0x10, 0xc6, 0xff, 0x0b,
-	0x63, 0x26, 0x4a, 0x96, 0x17, 0xce, 0xdf, 0x38, 0xd4, 0xbd, 0x4b, 0x93, 0xe1, 0x43, 0x52, 0xeb,
-	0x76, 0xba, 0xb4, 0x4b, 0x5a, 0x43, 0xf0, 0xb9, 0xd5, 0x30, 0x66, 0x67, 0x29, 0x68, 0xe6, 0x53,
-	0x60, 0x16, 0x1c, 0xe6, 0x56, 0x02, 0x1b, 0x23, 0x38, 0xa6, 0xd1, 0x33, 0x78, 0xab, 0x9c, 0x8f,
-	0xe8, 0x32, 0x59, 0xfa, 0x12, 0x06, 0x2b, 0xf6, 0x11, 0xd9, 0xb8, 0x28, 0x83, 0x1d, 0xa0, 0xcc,
-	0x33, 0xd0, 0xd5, 0x03, 0xa3, 0x77, 0xaf, 0xae, 0x86, 0x3b, 0xe5, 0x81, 0x8f, 0x51, 0x3a, 0xfe,
-	0x8a, 0xfd, 0x05, 0x2d, 0xfc, 0x97, 0x39, 0x49, 0xb8, 0x19, 0x7d, 0x0d, 0x57, 0x0b, 0xfd, 0x52,
-	0x7e, 0xb4, 0x5c, 0xbe, 0xd8, 0x07, 0xbf, 0x02, 0x00, 0x00, 0xff, 0xff, 0xaa, 0x04, 0x46, 0x55,
-	0x2f, 0x03, 0x00, 0x00,
+	// 1179 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x56, 0xdf, 0x6e, 0x1b, 0xc5,
+	0x17, 0xd6, 0xae, 0xd3, 0xc4, 0x3e, 0x49, 0x9b, 0x74, 0x9a, 0xa6, 0x96, 0xdb, 0x5f, 0xbb, 0x71,
+	0xf4, 0x13, 0x6e, 0xda, 0x78, 0x93, 0x50, 0xf1, 0xc7, 0x08, 0xd4, 0xff, 0x50, 0x29, 0xa1, 0xb0,
+	0xa9, 0x4a, 0xc5, 0x8d, 0x35, 0xde, 0x3d, 0x59, 0x0f, 0xd9, 0x9d, 0xd9, 0xee, 0xcc, 0xa6, 0x4e,
+	0x81, 0x1b, 0xc4, 0x1d, 0x12, 0x17, 0x70, 0x01, 0x82, 0x37, 0x80, 0x17, 0xe0, 0x8e, 0x87, 0xe0,
+	0x15, 0x10, 0xcf, 0x81, 0x66, 0x76, 0x63, 0xaf, 0x13, 0xbb, 0xa2, 0x57, 0xde, 0xf9, 0xce, 0x99,
+	0x73, 0xbe, 0xf3, 0xcd, 0x39, 0xe3, 0x81, 0x45, 0x9a, 0x30, 0xf7, 0x79, 0x86, 0xe9, 0x51, 0x3b,
+	0x49, 0x85, 0x12, 0xa4, 0x26, 0x12, 0xe4, 0x31, 0x55, 0x7e, 0xbf, 0x41, 0xb4, 0x2d, 0x46, 0x29,
+	0x69, 0x88, 0x32, 0x37, 0x37, 0xae, 0x84, 0x42, 0x84, 0x11, 0xba, 0xda, 0x44, 0x39, 0x17, 0x8a,
+	0x2a, 0x26, 0xf8, 0xb1, 0xd5, 0x29, 0xac, 0x66, 0xd5, 0xcb, 0xf6, 0xdd, 0x7d, 0x86, 0x51, 0xd0,
+	0x8d, 0xa9, 0x3c, 0x28, 0x3c, 0x6e, 0x9a, 0x1f, 0x7f, 0x23, 0x44, 0xbe, 0x21, 0x5f, 0xd0, 0x30,
+	0xc4, 0xd4, 0x15, 0x89, 0x89, 0x71, 0x3a, 0x5e, 0xf3, 0x0f, 0x1b, 0x2e, 0x7c, 0xaa, 0xc9, 0x3d,
+	0x61, 0xfe, 0x01, 0x2a, 0xe9, 0xe1, 0xf3, 0x0c, 0xa5, 0x22, 0x6b, 0x30, 0x93, 0x08, 0x11, 0xd5,
+	0x2d, 0xc7, 0x6a, 0xcd, 0x6f, 0x2f, 0xb6, 0x87, 0x9c, 0xdb, 0x9f, 0x08, 0x11, 0x79, 0xc6, 0x48,
+	0xde, 0x05, 0x18, 0xa5, 0xaf, 0xdb, 0xc6, 0xb5, 0xd1, 0xce, 0x19, 0xb6, 0x8f, 0x19, 0xb6, 0x1f,
+	0x6a, 0x97, 0x5d, 0x2a, 0x0f, 0xbc, 0xda, 0xfe, 0xf1, 0x27, 0xd9, 0x82, 0xaa, 0x48, 0x03, 0x4c,
+	0xbb, 0xbd, 0xa3, 0x7a, 0xc5, 0x6c, 0x5c, 0x29, 0xe5, 0xc8, 0xc9, 0x3c, 0xd6, 0x0e, 0xde, 0x9c,
+	0xf1, 0xbb, 0x7b, 0x44, 0x2e, 0x43, 0x2d, 0xa1, 0x21, 0x76, 0x25, 0x7b, 0x89, 0xf5, 0x19, 0xc7,
+	0x6a, 0x9d, 0xf1, 0xaa, 0x1a, 0xd8, 0x63, 0x2f, 0x91, 0x5c, 0x83, 0xf9, 0x98, 0x0e, 0xba, 0x29,
+	0xca, 0x2c, 0x52, 0xb2, 0x7e, 0xc6, 0x98, 0x21, 0xa6, 0x03, 0x2f, 0x47, 0xc8, 0x1a, 0x9c, 0x55,
+	0xf4, 0x00, 0xbb, 0x92, 0xd3, 0x44, 0xf6, 0x85, 0xaa, 0xcf, 0x3a, 0x56, 0xab, 0xea, 0x2d, 0x68,
+	0x70, 0xaf, 0xc0, 0xc8, 0x0d, 0x38, 0xef, 0x0b, 0x2e, 0x99, 0x54, 0xc8, 0xfd, 0xa3, 0xae, 0x12,
+	0x07, 0xc8, 0xeb, 0x73, 0x8e, 0xd5, 0xaa, 0x79, 0x4b, 0x25, 0xc3, 0x13, 0x8d, 0x37, 0x63, 0x98,
+	0x2f, 0xf1, 0x24, 0xff, 0x03, 0x08, 0x44, 0xd6, 0x8b, 0xb0, 0x4b, 0xd3, 0xd0, 0xe8, 0x56, 0xf3,
+	0x6a, 0x39, 0x72, 0x27, 0x0d, 0x35, 0x41, 0x3f, 0x45, 0xaa, 0xb0, 0xab, 0x58, 0x8c, 0x46, 0xac,
+	0xaa, 0x07, 0x39, 0xf4, 0x84, 0xc5, 0x48, 0xae, 0x02, 0x04, 0x28, 0x7d, 0xe4, 0x01, 0xe3, 0xa1,
+	0xd1, 0xa4, 0xea, 0x95, 0x90, 0x66, 0x02, 0xcb, 0xe3, 0x07, 0x25, 0x13, 0xc1, 0x25, 0x92, 0x1b,
+	0x30, 0xa7, 0x72, 0xa8, 0x6e, 0x39, 0x95, 0xd6, 0xfc, 0xf6, 0xf9, 0x53, 0x42, 0x7a, 0xc7, 0x1e,
+	0x93, 0x0b, 0xb4, 0xa7, 0x14, 0xf8, 0xbd, 0x05, 0x17, 0x4b, 0x29, 0x1f, 0x05, 0xaf, 0xd7, 0x1d,
+	0xa7, 0x14, 0xb7, 0xff, 0xab, 0xe2, 0x95, 0x29, 0x84, 0x3e, 0x83, 0x95, 0x93, 0x7c, 0x0a, 0x11,
+	0x96, 0xa0, 0xc2, 0x82, 0x5c, 0x80, 0x9a, 0xa7, 0x3f, 0x5f, 0xaf, 0xd2, 0x9f, 0x2c, 0xb8, 0xf0,
+	0x21, 0x2a, 0x4d, 0x7e, 0x4f, 0xd1, 0xd7, 0x9c, 0x82, 0x4d, 0x58, 0xee, 0x33, 0xa9, 0x44, 0x98,
+	0xd2, 0xb8, 0x5b, 0x6a, 0x81, 0x3c, 0x19, 0x19, 0xda, 0xee, 0x0f, 0x7b, 0xe1, 0x3a, 0x2c, 0x8d,
+	0x76, 0xf4, 0x44, 0xc6, 0x03, 0x59, 0xaf, 0x38, 0x95, 0x96, 0xe5, 0x2d, 0x0e, 0xf1, 0xbb, 0x06,
+	0x6e, 0x3e, 0x83, 0xc5, 0x8f, 0x86, 0x50, 0xa6, 0xcb, 0xd6, 0xb5, 0xc6, 0x8c, 0x1b, 0x4e, 0x96,
+	0xa7, 0x3f, 0x0d, 0x42, 0x07, 0x75, 0xbb, 0x40, 0xe8, 0x80, 0xac, 0xc2, 0x42, 0x7e, 0xe4, 0x5d,
+	0x5f, 0x64, 0x5c, 0x19, 0x45, 0x2b, 0xde, 0x7c, 0x8e, 0xdd, 0xd3, 0x50, 0xf3, 0x77, 0x0b, 0x96,
+	0xc7, 0x6b, 0x2e, 0xb4, 0x3c, 0xb9, 0xd7, 0x3a, 0xb5, 0x97, 0xbc, 0x03, 0xb5, 0x21, 0xd1, 0xba,
+	0x6d, 0xba, 0xae, 0x51, 0x12, 0xe7, 0x04, 0x63, 0x6f, 0xe4, 0x4c, 0xde, 0x82, 0x4b, 0xa3, 0xd2,
+	0x63, 0x26, 0x25, 0xe3, 0xe1, 0x18, 0xc7, 0x8b, 0x43, 0xf3, 0x6e, 0x6e, 0xcd, 0xd9, 0x3e, 0x83,
+	0xe5, 0x07, 0x83, 0x24, 0xa2, 0x8c, 0x17, 0x2d, 0x5d, 0x9c, 0xd0, 0x65, 0xa8, 0x15, 0x64, 0x59,
+	0x50, 0x0c, 0x5d, 0x55, 0x15, 0xed, 0x31, 0x3c, 0x3e, 0xfb, 0x15, 0xc7, 0xd7, 0xa4, 0xb0, 0xf0,
+	0x90, 0x45, 0x0a, 0xd3, 0xfc, 0xa6, 0x20, 0x2b, 0x30, 0xbb, 0x6f, 0xd6, 0x45, 0xb8, 0x62, 0xa5,
+	0xf1, 0x84, 0x4a, 0x89, 0x41, 0xd1, 0xc7, 0xc5, 0xaa, 0x24, 0xd7, 0x21, 0x8d, 0x32, 0x2c, 0x9a,
+	0xb7, 0x90, 0xeb, 0xa9, 0x86, 0xcc, 0x20, 0x9d, 0x60, 0x5f, 0x68, 0x7d, 0x09, 0xe6, 0x18, 0xef,
+	0x0e, 0x7b, 0xac, 0xea, 0xcd, 0x32, 0xae, 0xb9, 0x91, 0x2b, 0x50, 0xa3, 0x87, 0x94, 0x45, 0xb4,
+	0x17, 0x1d, 0x5f, 0x16, 0x23, 0x80, 0x7c, 0x00, 0xe7, 0x72, 0x56, 0xc3, 0x0b, 0xaf, 0x62, 0x0e,
+	0xe1, 0x52, 0xa9, 0xc4, 0x72, 0x51, 0xde, 0xd9, 0xfd, 0xd2, 0x4a, 0x6e, 0xff, 0x3a, 0x03, 0x0b,
+	0x66, 0x92, 0xf6, 0x30, 0x3d, 0x64, 0x3e, 0x92, 0xaf, 0x8a, 0x75, 0x71, 0xb9, 0x90, 0xab, 0xa5,
+	0x40, 0x13, 0xfe, 0x1e, 0x1a, 0xd7, 0xa6, 0xda, 0xf3, 0xc2, 0x9a, 0xd7, 0xbf, 0xf9, 0xeb, 0xef,
+	0x1f, 0xed, 0xb5, 0xe6, 0x55, 0xf7, 0x70, 0x2b, 0xff, 0xf7, 0x93, 0x79, 0x2a, 0xb7, 0xb8, 0x8a,
+	0x3a, 0x06, 0xec, 0x58, 0xeb, 0x9b, 0x16, 0xf9, 0xd6, 0x82, 0x73, 0xe3, 0x83, 0x4d, 0x9c, 0xc9,
+	0x09, 0x46, 0x77, 0x50, 0x63, 0xf5, 0x15, 0x1e, 0x05, 0x89, 0x1b, 0x86, 0xc4, 0xff, 0x9b, 0xce,
+	0x14, 0x12, 0x2c, 0x18, 0xa3, 0x31, 0x80, 0x85, 0xf2, 0x40, 0x8c, 0x89, 0x30, 0xe1, 0x76, 0x68,
+	0x5c, 0x9b, 0x6a, 0x2f, 0xf2, 0xbf, 0x61, 0xf2, 0xaf, 0x36, 0xaf, 0x9c, 0xca, 0xaf, 0x4f, 0x5c,
+	0x76, 0xa4, 0xf6, 0xee, 0x58, 0xeb, 0xe4, 0x3b, 0x0b, 0xce, 0x8e, 0x35, 0x08, 0x29, 0xc7, 0x9e,
+	0xd4, 0xf8, 0x0d, 0x67, 0xba, 0x43, 0x91, 0xfd, 0x6d, 0x93, 0x7d, 0xab, 0x79, 0x73, 0xda, 0x11,
+	0xb8, 0x5f, 0x0e, 0x47, 0xe7, 0xeb, 0x0e, 0xe6, 0x31, 0x3a, 0xd6, 0xfa, 0xdd, 0x9f, 0x2b, 0x3f,
+	0xdc, 0xf9, 0xc7, 0x26, 0x7f, 0x5a, 0x70, 0x71, 0x77, 0xd7, 0xd9, 0x11, 0x21, 0xf3, 0x9d, 0xd6,
+	0x7d, 0xaa, 0xa8, 0xb3, 0x43, 0x8f, 0x30, 0xbd, 0xde, 0x7c, 0x04, 0xf0, 0x38, 0x41, 0xee, 0xec,
+	0xea, 0xec, 0x64, 0xa5, 0xaf, 0x54, 0x22, 0x3b, 0xae, 0xab, 0x09, 0x6d, 0xe4, 0x8c, 0x02, 0x3c,
+	0x6c, 0xac, 0x8d, 0xd6, 0x1b, 0x01, 0x93, 0x7e, 0x26, 0xe5, 0xed, 0xfc, 0x6d, 0x10, 0xa6, 0x22,
+	0x4b, 0x64, 0xdb, 0x17, 0xf1, 0xfa, 0x53, 0x20, 0x77, 0x12, 0xea, 0xf7, 0xd1, 0xd9, 0x6e, 0x6f,
+	0x3a, 0x3b, 0xcc, 0x47, 0x3d, 0x16, 0xb7, 0x8f, 0x43, 0x86, 0x4c, 0xf5, 0xb3, 0x9e, 0xf6, 0x74,
+	0xf3, 0xad, 0xfb, 0x22, 0x0d, 0x69, 0x8c, 0xb2, 0x94, 0xcc, 0xed, 0x45, 0xa2, 0xe7, 0xc6, 0x54,
+	0x2a, 0x4c, 0xdd, 0x9d, 0x47, 0xf7, 0x1e, 0x7c, 0xbc, 0xf7, 0x60, 0xbb, 0xb2, 0xd5, 0xde, 0x5c,
+	0xb7, 0x2d, 0x7b, 0x7b, 0x89, 0x26, 0x49, 0xc4, 0x7c, 0xf3, 0xe8, 0x71, 0xbf, 0x90, 0x82, 0x77,
+	0x4e, 0x21, 0xde, 0x7b, 0x50, 0xb9, 0xb5, 0x79, 0x8b, 0xdc, 0x82, 0x75, 0x0f, 0x55, 0x96, 0x72,
+	0x0c, 0x9c, 0x17, 0x7d, 0xe4, 0x8e, 0xea, 0xa3, 0x93, 0xa2, 0x14, 0x59, 0xea, 0xa3, 0x13, 0x08,
+	0x94, 0x0e, 0x17, 0xca, 0xc1, 0x01, 0x93, 0xaa, 0x4d, 0x66, 0x61, 0xe6, 0x17, 0xdb, 0x9a, 0x4b,
+	0xdf, 0x87, 0xfa, 0x48, 0x0c, 0xe7, 0xbe, 0xf0, 0xb3, 0x18, 0x79, 0xfe, 0xc8, 0x22, 0xab, 0x93,
+	0xa5, 0x71, 0x25, 0x53, 0xe8, 0x06, 0xc2, 0x97, 0xee, 0xe7, 0xce, 0x09, 0x53, 0xa9, 0xae, 0xe4,
+	0x20, 0x74, 0x93, 0xde, 0x6f, 0x76, 0x4d, 0xc7, 0x37, 0xe1, 0x7b, 0xb3, 0xe6, 0x55, 0xf5, 0xe6,
+	0xbf, 0x03, 0x00, 0xbe, 0xfe, 0x48, 0xcc, 0x55, 0x0a, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -167,6 +806,16 @@ type QueryServiceClient interface {
 	// QueryTickets pages the Tickets by `storage.pool.size` and stream back response.
 	//   - storage.pool.size is default to 1000 if not set, and has a mininum of 10 and maximum of 10000
 	QueryTickets(ctx context.Context, in *QueryTicketsRequest, opts ...grpc.CallOption) (QueryService_QueryTicketsClient, error)
+	// QueryTicketIds gets the ids of the Tickets that match all Filters of the input Pool.
+	//   - Intended for match functions which only need ids, it pages and streams them back like QueryTickets.
+	QueryTicketIds(ctx context.Context, in *QueryTicketIdsRequest, opts ...grpc.CallOption) (QueryService_QueryTicketIdsClient, error)
+	// GetPoolStats counts the Tickets that match all Filters of the input Pool, without fetching them.
+	//   - Intended for dashboards, wait time estimates and autoscalers, which would otherwise page through QueryTickets.
+	//   - Optionally returns a histogram of the Tickets' values of a double arg, eg. their MMR.
+	GetPoolStats(ctx context.Context, in *GetPoolStatsRequest, opts ...grpc.CallOption) (*GetPoolStatsResponse, error)
+	// ExplainTicket reports which criteria of the input Pool the Ticket passes or fails, with the Ticket's values.
+	//   - Intended for debugging Tickets which never match, it checks the same criteria as QueryTickets.
+	ExplainTicket(ctx context.Context, in *ExplainTicketRequest, opts ...grpc.CallOption) (*ExplainTicketResponse, error)
 }
 
 type queryServiceClient struct {
@@ -209,6 +858,56 @@ func (x *queryServiceQueryTicketsClient) Recv() (*QueryTicketsResponse, error) {
 	return m, nil
 }
 
+func (c *queryServiceClient) QueryTicketIds(ctx context.Context, in *QueryTicketIdsRequest, opts ...grpc.CallOption) (QueryService_QueryTicketIdsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_QueryService_serviceDesc.Streams[1], "/openmatch.QueryService/QueryTicketIds", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &queryServiceQueryTicketIdsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type QueryService_QueryTicketIdsClient interface {
+	Recv() (*QueryTicketIdsResponse, error)
+	grpc.ClientStream
+}
+
+type queryServiceQueryTicketIdsClient struct {
+	grpc.ClientStream
+}
+
+func (x *queryServiceQueryTicketIdsClient) Recv() (*QueryTicketIdsResponse, error) {
+	m := new(QueryTicketIdsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *queryServiceClient) GetPoolStats(ctx context.Context, in *GetPoolStatsRequest, opts ...grpc.CallOption) (*GetPoolStatsResponse, error) {
+	out := new(GetPoolStatsResponse)
+	err := c.cc.Invoke(ctx, "/openmatch.QueryService/GetPoolStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryServiceClient) ExplainTicket(ctx context.Context, in *ExplainTicketRequest, opts ...grpc.CallOption) (*ExplainTicketResponse, error) {
+	out := new(ExplainTicketResponse)
+	err := c.cc.Invoke(ctx, "/openmatch.QueryService/ExplainTicket", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // QueryServiceServer is the server API for QueryService service.
 type QueryServiceServer interface {
 	// QueryTickets gets a list of Tickets that match all Filters of the input Pool.
@@ -216,6 +915,16 @@ type QueryServiceServer interface {
 	// QueryTickets pages the Tickets by `storage.pool.size` and stream back response.
 	//   - storage.pool.size is default to 1000 if not set, and has a mininum of 10 and maximum of 10000
 	QueryTickets(*QueryTicketsRequest, QueryService_QueryTicketsServer) error
+	// QueryTicketIds gets the ids of the Tickets that match all Filters of the input Pool.
+	//   - Intended for match functions which only need ids, it pages and streams them back like QueryTickets.
+	QueryTicketIds(*QueryTicketIdsRequest, QueryService_QueryTicketIdsServer) error
+	// GetPoolStats counts the Tickets that match all Filters of the input Pool, without fetching them.
+	//   - Intended for dashboards, wait time estimates and autoscalers, which would otherwise page through QueryTickets.
+	//   - Optionally returns a histogram of the Tickets' values of a double arg, eg. their MMR.
+	GetPoolStats(context.Context, *GetPoolStatsRequest) (*GetPoolStatsResponse, error)
+	// ExplainTicket reports which criteria of the input Pool the Ticket passes or fails, with the Ticket's values.
+	//   - Intended for debugging Tickets which never match, it checks the same criteria as QueryTickets.
+	ExplainTicket(context.Context, *ExplainTicketRequest) (*ExplainTicketResponse, error)
 }
 
 // UnimplementedQueryServiceServer can be embedded to have forward compatible implementations.
@@ -225,6 +934,15 @@ type UnimplementedQueryServiceServer struct {
 func (*UnimplementedQueryServiceServer) QueryTickets(req *QueryTicketsRequest, srv QueryService_QueryTicketsServer) error {
 	return status.Errorf(codes.Unimplemented, "method QueryTickets not implemented")
 }
+func (*UnimplementedQueryServiceServer) QueryTicketIds(req *QueryTicketIdsRequest, srv QueryService_QueryTicketIdsServer) error {
+	return status.Errorf(codes.Unimplemented, "method QueryTicketIds not implemented")
+}
+func (*UnimplementedQueryServiceServer) GetPoolStats(ctx context.Context, req *GetPoolStatsRequest) (*GetPoolStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPoolStats not implemented")
+}
+func (*UnimplementedQueryServiceServer) ExplainTicket(ctx context.Context, req *ExplainTicketRequest) (*ExplainTicketResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExplainTicket not implemented")
+}
 
 func RegisterQueryServiceServer(s *grpc.Server, srv QueryServiceServer) {
 	s.RegisterService(&_QueryService_serviceDesc, srv)
@@ -251,16 +969,87 @@ func (x *queryServiceQueryTicketsServer) Send(m *QueryTicketsResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _QueryService_QueryTicketIds_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(QueryTicketIdsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(QueryServiceServer).QueryTicketIds(m, &queryServiceQueryTicketIdsServer{stream})
+}
+
+type QueryService_QueryTicketIdsServer interface {
+	Send(*QueryTicketIdsResponse) error
+	grpc.ServerStream
+}
+
+type queryServiceQueryTicketIdsServer struct {
+	grpc.ServerStream
+}
+
+func (x *queryServiceQueryTicketIdsServer) Send(m *QueryTicketIdsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _QueryService_GetPoolStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPoolStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).GetPoolStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/openmatch.QueryService/GetPoolStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).GetPoolStats(ctx, req.(*GetPoolStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_ExplainTicket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExplainTicketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).ExplainTicket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/openmatch.QueryService/ExplainTicket",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).ExplainTicket(ctx, req.(*ExplainTicketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _QueryService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "openmatch.QueryService",
 	HandlerType: (*QueryServiceServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPoolStats",
+			Handler:    _QueryService_GetPoolStats_Handler,
+		},
+		{
+			MethodName: "ExplainTicket",
+			Handler:    _QueryService_ExplainTicket_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "QueryTickets",
 			Handler:       _QueryService_QueryTickets_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "QueryTicketIds",
+			Handler:       _QueryService_QueryTicketIds_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "api/query.proto",
 }
@@ -0,0 +1,77 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pb
+
+import (
+	"strings"
+	"time"
+)
+
+// SearchFields marking a Ticket reserved for a scheduled event, see
+// BackendService.ReserveTickets.
+const (
+	// EventTagPrefix prefixes the tag of the event a Ticket is reserved for.
+	EventTagPrefix = "openmatch.event:"
+	// EventStartArg is the DoubleArg holding the start of the event a Ticket
+	// is reserved for, in seconds since the Unix epoch.
+	EventStartArg = "openmatch.event_start"
+)
+
+// EventTag returns the tag of the Tickets reserved for the event.  Pools of
+// the event's dedicated MatchProfile select them with a TagPresentFilter on
+// it, eg.
+//
+//	pool.TagPresentFilters = append(pool.TagPresentFilters, &pb.TagPresentFilter{Tag: pb.EventTag(id)})
+func EventTag(eventID string) string {
+	return EventTagPrefix + eventID
+}
+
+// ReserveForEvent marks the ticket reserved for the event starting at start,
+// replacing any previous reservation.
+func ReserveForEvent(ticket *Ticket, eventID string, start time.Time) {
+	if ticket.SearchFields == nil {
+		ticket.SearchFields = &SearchFields{}
+	}
+	sf := ticket.SearchFields
+
+	tags := sf.Tags[:0]
+	for _, tag := range sf.Tags {
+		if !strings.HasPrefix(tag, EventTagPrefix) {
+			tags = append(tags, tag)
+		}
+	}
+	sf.Tags = append(tags, EventTag(eventID))
+
+	if sf.DoubleArgs == nil {
+		sf.DoubleArgs = make(map[string]float64)
+	}
+	sf.DoubleArgs[EventStartArg] = float64(start.UnixNano()) / float64(time.Second)
+}
+
+// ReservedEvent returns the event the ticket is reserved for and its start,
+// or false if the ticket isn't reserved.
+func ReservedEvent(ticket *Ticket) (eventID string, start time.Time, ok bool) {
+	seconds, ok := ticket.GetSearchFields().GetDoubleArgs()[EventStartArg]
+	if !ok {
+		return "", time.Time{}, false
+	}
+	for _, tag := range ticket.GetSearchFields().GetTags() {
+		if strings.HasPrefix(tag, EventTagPrefix) {
+			eventID = strings.TrimPrefix(tag, EventTagPrefix)
+			break
+		}
+	}
+	return eventID, time.Unix(0, int64(seconds*float64(time.Second))), true
+}
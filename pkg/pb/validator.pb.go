@@ -0,0 +1,233 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: api/validator.proto
+
+package pb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	_ "github.com/grpc-ecosystem/grpc-gateway/protoc-gen-swagger/options"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type ValidateTicketRequest struct {
+	// A Ticket about to be created or updated through the FrontendService.  Its
+	// TicketId is only set for updates.
+	Ticket               *Ticket  `protobuf:"bytes,1,opt,name=ticket,proto3" json:"ticket,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ValidateTicketRequest) Reset()         { *m = ValidateTicketRequest{} }
+func (m *ValidateTicketRequest) String() string { return proto.CompactTextString(m) }
+func (*ValidateTicketRequest) ProtoMessage()    {}
+func (*ValidateTicketRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b256a5cf8949de6b, []int{0}
+}
+
+func (m *ValidateTicketRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValidateTicketRequest.Unmarshal(m, b)
+}
+func (m *ValidateTicketRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValidateTicketRequest.Marshal(b, m, deterministic)
+}
+func (m *ValidateTicketRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValidateTicketRequest.Merge(m, src)
+}
+func (m *ValidateTicketRequest) XXX_Size() int {
+	return xxx_messageInfo_ValidateTicketRequest.Size(m)
+}
+func (m *ValidateTicketRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValidateTicketRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ValidateTicketRequest proto.InternalMessageInfo
+
+func (m *ValidateTicketRequest) GetTicket() *Ticket {
+	if m != nil {
+		return m.Ticket
+	}
+	return nil
+}
+
+type ValidateTicketResponse struct {
+	// Why the Ticket is rejected, returned to the client in an InvalidArgument
+	// error.  Empty if the Ticket is valid.
+	Violations           []string `protobuf:"bytes,1,rep,name=violations,proto3" json:"violations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ValidateTicketResponse) Reset()         { *m = ValidateTicketResponse{} }
+func (m *ValidateTicketResponse) String() string { return proto.CompactTextString(m) }
+func (*ValidateTicketResponse) ProtoMessage()    {}
+func (*ValidateTicketResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b256a5cf8949de6b, []int{1}
+}
+
+func (m *ValidateTicketResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValidateTicketResponse.Unmarshal(m, b)
+}
+func (m *ValidateTicketResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValidateTicketResponse.Marshal(b, m, deterministic)
+}
+func (m *ValidateTicketResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValidateTicketResponse.Merge(m, src)
+}
+func (m *ValidateTicketResponse) XXX_Size() int {
+	return xxx_messageInfo_ValidateTicketResponse.Size(m)
+}
+func (m *ValidateTicketResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValidateTicketResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ValidateTicketResponse proto.InternalMessageInfo
+
+func (m *ValidateTicketResponse) GetViolations() []string {
+	if m != nil {
+		return m.Violations
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ValidateTicketRequest)(nil), "openmatch.ValidateTicketRequest")
+	proto.RegisterType((*ValidateTicketResponse)(nil), "openmatch.ValidateTicketResponse")
+}
+
+func init() { proto.RegisterFile("api/validator.proto", fileDescriptor_b256a5cf8949de6b) }
+
+var fileDescriptor_b256a5cf8949de6b = []byte{
+	// 492 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x52, 0x4d, 0x6e, 0xd3, 0x40,
+	0x18, 0x95, 0x1d, 0x54, 0x94, 0x41, 0x82, 0x32, 0x88, 0x2a, 0x8a, 0x10, 0x9a, 0x06, 0x16, 0x6d,
+	0x44, 0x3c, 0x69, 0xc8, 0x02, 0x05, 0x21, 0xb5, 0x85, 0x2e, 0x2a, 0x15, 0x90, 0x02, 0xca, 0x82,
+	0xdd, 0x64, 0xfc, 0x61, 0x0f, 0xb5, 0x67, 0x06, 0x7f, 0xe3, 0x94, 0x35, 0x0b, 0x0e, 0x40, 0x77,
+	0x3d, 0x02, 0x47, 0xe0, 0x1a, 0x9c, 0x00, 0x89, 0x83, 0x20, 0x7b, 0xf2, 0x47, 0x5b, 0xb1, 0xb2,
+	0xbe, 0xef, 0xbd, 0x79, 0xef, 0xe9, 0xf9, 0x23, 0xf7, 0x84, 0x55, 0x7c, 0x26, 0x32, 0x15, 0x0b,
+	0x67, 0x8a, 0xc8, 0x16, 0xc6, 0x19, 0xda, 0x34, 0x16, 0x74, 0x2e, 0x9c, 0x4c, 0xdb, 0xb4, 0xc2,
+	0x73, 0x40, 0x14, 0x09, 0xa0, 0x87, 0xdb, 0x0f, 0x12, 0x63, 0x92, 0x0c, 0x78, 0x05, 0x09, 0xad,
+	0x8d, 0x13, 0x4e, 0x19, 0xbd, 0x40, 0x9f, 0xd4, 0x1f, 0xd9, 0x4b, 0x40, 0xf7, 0xf0, 0x4c, 0x24,
+	0x09, 0x14, 0xdc, 0xd8, 0x9a, 0x71, 0x95, 0xdd, 0x39, 0x24, 0xf7, 0x27, 0xde, 0x1d, 0xde, 0x2b,
+	0x79, 0x0a, 0x6e, 0x0c, 0x9f, 0x4b, 0x40, 0x47, 0x77, 0xc9, 0x86, 0xab, 0x17, 0xad, 0x80, 0x05,
+	0x3b, 0xb7, 0x06, 0x77, 0xa3, 0x65, 0xa8, 0x68, 0xce, 0x9c, 0x13, 0x3a, 0xcf, 0xc8, 0xd6, 0x65,
+	0x0d, 0xb4, 0x46, 0x23, 0xd0, 0x87, 0x84, 0xcc, 0x94, 0xc9, 0xbc, 0x63, 0x2b, 0x60, 0x8d, 0x9d,
+	0xe6, 0x78, 0x6d, 0x33, 0xb8, 0x08, 0xc8, 0x1d, 0xff, 0x64, 0xb2, 0xa8, 0x80, 0x7e, 0x0b, 0xc8,
+	0xed, 0x7f, 0xe5, 0x28, 0x5b, 0xf3, 0xbe, 0x36, 0x6d, 0x7b, 0xfb, 0x3f, 0x0c, 0x9f, 0xa5, 0xc3,
+	0xbf, 0xfe, 0xfa, 0x73, 0x1e, 0xee, 0x76, 0x1e, 0xf3, 0xd9, 0x1e, 0xf7, 0xc9, 0x97, 0xbd, 0xcf,
+	0x67, 0x1c, 0xcd, 0x37, 0x30, 0x0a, 0xba, 0x87, 0xe7, 0x8d, 0xef, 0x07, 0xbf, 0x43, 0xfa, 0x33,
+	0x20, 0x9b, 0x5e, 0x8a, 0x2d, 0x43, 0x76, 0x8e, 0x09, 0x79, 0x6b, 0x41, 0xb3, 0xd7, 0x95, 0x23,
+	0xdd, 0x4a, 0x9d, 0xb3, 0x38, 0xe2, 0xbc, 0x0a, 0xd1, 0xf3, 0x29, 0x62, 0x98, 0xb5, 0x1f, 0xad,
+	0xe6, 0x5e, 0xac, 0x50, 0x96, 0x88, 0xfb, 0xfe, 0xdf, 0x25, 0x85, 0x29, 0x2d, 0x46, 0xd2, 0xe4,
+	0xdd, 0x09, 0xa1, 0x07, 0x56, 0xc8, 0x14, 0xd8, 0x20, 0xea, 0xb3, 0x13, 0x25, 0xa1, 0x6a, 0x6e,
+	0x7f, 0x21, 0x99, 0x28, 0x97, 0x96, 0xd3, 0x8a, 0xc9, 0xfd, 0xd3, 0x8f, 0xa6, 0x48, 0x44, 0x0e,
+	0xb8, 0x66, 0xc6, 0xa7, 0x99, 0x99, 0xf2, 0x5c, 0xa0, 0x83, 0x82, 0x9f, 0x1c, 0xbf, 0x3c, 0x7a,
+	0xf3, 0xee, 0x68, 0xd0, 0xd8, 0x8b, 0xfa, 0xdd, 0x30, 0x08, 0x07, 0x9b, 0xc2, 0xda, 0x4c, 0xc9,
+	0xba, 0x72, 0xfe, 0x09, 0x8d, 0x1e, 0x5d, 0xd9, 0x8c, 0x9f, 0x93, 0xc6, 0xb0, 0x3f, 0xa4, 0x43,
+	0xd2, 0x1d, 0x83, 0x2b, 0x0b, 0x0d, 0x31, 0x3b, 0x4b, 0x41, 0x33, 0x97, 0x02, 0x2b, 0x00, 0x4d,
+	0x59, 0x48, 0x60, 0xb1, 0x01, 0x64, 0xda, 0x38, 0x06, 0x5f, 0x14, 0xba, 0x88, 0x6e, 0x90, 0x1b,
+	0x17, 0x61, 0x70, 0xb3, 0x78, 0x41, 0x5a, 0xab, 0x32, 0xd8, 0x2b, 0x23, 0xcb, 0x1c, 0xb4, 0x3f,
+	0x33, 0xba, 0x7d, 0x7d, 0x35, 0x1c, 0x95, 0x03, 0x1e, 0x1b, 0x89, 0xfc, 0x03, 0xbb, 0x04, 0xad,
+	0x46, 0x6e, 0x4f, 0x13, 0x6e, 0xa7, 0x3f, 0xc2, 0x66, 0xa5, 0x5f, 0xcb, 0x4f, 0x37, 0xea, 0xbb,
+	0x7d, 0xfa, 0x77, 0x00, 0x02, 0x75, 0x60, 0x42, 0x39, 0x03, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// TicketValidatorClient is the client API for TicketValidator service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type TicketValidatorClient interface {
+	// ValidateTicket returns why the Ticket must be rejected, if it must be.
+	ValidateTicket(ctx context.Context, in *ValidateTicketRequest, opts ...grpc.CallOption) (*ValidateTicketResponse, error)
+}
+
+type ticketValidatorClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewTicketValidatorClient(cc *grpc.ClientConn) TicketValidatorClient {
+	return &ticketValidatorClient{cc}
+}
+
+func (c *ticketValidatorClient) ValidateTicket(ctx context.Context, in *ValidateTicketRequest, opts ...grpc.CallOption) (*ValidateTicketResponse, error) {
+	out := new(ValidateTicketResponse)
+	err := c.cc.Invoke(ctx, "/openmatch.TicketValidator/ValidateTicket", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TicketValidatorServer is the server API for TicketValidator service.
+type TicketValidatorServer interface {
+	// ValidateTicket returns why the Ticket must be rejected, if it must be.
+	ValidateTicket(context.Context, *ValidateTicketRequest) (*ValidateTicketResponse, error)
+}
+
+// UnimplementedTicketValidatorServer can be embedded to have forward compatible implementations.
+type UnimplementedTicketValidatorServer struct {
+}
+
+func (*UnimplementedTicketValidatorServer) ValidateTicket(ctx context.Context, req *ValidateTicketRequest) (*ValidateTicketResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateTicket not implemented")
+}
+
+func RegisterTicketValidatorServer(s *grpc.Server, srv TicketValidatorServer) {
+	s.RegisterService(&_TicketValidator_serviceDesc, srv)
+}
+
+func _TicketValidator_ValidateTicket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateTicketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketValidatorServer).ValidateTicket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/openmatch.TicketValidator/ValidateTicket",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketValidatorServer).ValidateTicket(ctx, req.(*ValidateTicketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _TicketValidator_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "openmatch.TicketValidator",
+	HandlerType: (*TicketValidatorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ValidateTicket",
+			Handler:    _TicketValidator_ValidateTicket_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/validator.proto",
+}
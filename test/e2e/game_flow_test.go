@@ -20,8 +20,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/any"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	statestoreTesting "open-match.dev/open-match/internal/statestore/testing"
 	"open-match.dev/open-match/internal/testing/e2e"
 	"open-match.dev/open-match/pkg/pb"
@@ -199,17 +203,108 @@ func TestGameMatchWorkFlow(t *testing.T) {
 	validateFetchMatchesResponse(ctx, t, matches, be, fmReq)
 }
 
+func TestFetchMatchesPassPoolTickets(t *testing.T) {
+	om, closer := e2e.New(t)
+	defer closer()
+	fe := om.MustFrontendGRPC()
+	be := om.MustBackendGRPC()
+	ctx := om.Context()
+
+	ctResp, err := fe.CreateTicket(ctx, &pb.CreateTicketRequest{Ticket: &pb.Ticket{
+		SearchFields: &pb.SearchFields{
+			DoubleArgs: map[string]float64{
+				e2e.DoubleArgMMR:   20,
+				e2e.DoubleArgLevel: 20,
+			},
+		},
+	}}, grpc.WaitForReady(true))
+	require.Nil(t, err)
+
+	// The backend queries the pool and passes its tickets to the match function.
+	mmfCfg := om.MustMmfConfigGRPC()
+	mmfCfg.PassPoolTickets = true
+	fmReq := &pb.FetchMatchesRequest{
+		Config: mmfCfg,
+		Profile: &pb.MatchProfile{
+			Name: "test-profile",
+			Pools: []*pb.Pool{
+				{
+					Name:               "ticket5",
+					DoubleRangeFilters: []*pb.DoubleRangeFilter{{DoubleArg: e2e.DoubleArgMMR, Min: 0, Max: 100}, {DoubleArg: e2e.DoubleArgLevel, Min: 17, Max: 25}},
+				},
+			},
+		},
+	}
+	validateFetchMatchesResponse(ctx, t, []*pb.Match{MustMakeMatch(ctResp.GetTicket())}, be, fmReq)
+}
+
+func TestFetchMatchesMultipleProfiles(t *testing.T) {
+	om, closer := e2e.New(t)
+	defer closer()
+	fe := om.MustFrontendGRPC()
+	be := om.MustBackendGRPC()
+	ctx := om.Context()
+
+	createTicket := func(mmr float64) *pb.Ticket {
+		resp, err := fe.CreateTicket(ctx, &pb.CreateTicketRequest{Ticket: &pb.Ticket{
+			SearchFields: &pb.SearchFields{DoubleArgs: map[string]float64{e2e.DoubleArgMMR: mmr}},
+		}}, grpc.WaitForReady(true))
+		require.Nil(t, err)
+		return resp.GetTicket()
+	}
+	low := createTicket(10)
+	high := createTicket(90)
+
+	profile := func(name string, min, max float64) *pb.MatchProfile {
+		return &pb.MatchProfile{
+			Name:  name,
+			Pools: []*pb.Pool{{Name: name, DoubleRangeFilters: []*pb.DoubleRangeFilter{{DoubleArg: e2e.DoubleArgMMR, Min: min, Max: max}}}},
+		}
+	}
+
+	// Both profiles are evaluated in one cycle, each match tagged with its profile.
+	lowMatch, err := mmf.MakeMatch("low", low)
+	require.Nil(t, err)
+	highMatch, err := mmf.MakeMatch("high", high)
+	require.Nil(t, err)
+	fmReq := &pb.FetchMatchesRequest{
+		Config:   om.MustMmfConfigGRPC(),
+		Profile:  profile("low", 0, 50),
+		Profiles: []*pb.MatchProfile{profile("high", 50, 100)},
+	}
+	validateFetchMatchesResponse(ctx, t, []*pb.Match{lowMatch, highMatch}, be, fmReq)
+
+	// Profile names must be unique.
+	fmReq.Profiles = append(fmReq.Profiles, profile("low", 0, 100))
+	stream, err := be.FetchMatches(ctx, fmReq, grpc.WaitForReady(true))
+	require.Nil(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
 func validateFetchMatchesResponse(ctx context.Context, t *testing.T, expectedMatches []*pb.Match, be pb.BackendServiceClient, fmReq *pb.FetchMatchesRequest) {
 	stream, err := be.FetchMatches(ctx, fmReq, grpc.WaitForReady(true))
 	require.Nil(t, err)
 	matches := make([]*pb.Match, 0)
+	var cycleID *any.Any
 	for {
 		resp, err := stream.Recv()
 		if err == io.EOF {
 			break
 		}
 		require.Nil(t, err)
-		matches = append(matches, resp.GetMatch())
+
+		// All matches of a FetchMatches call come from a single synchronizer cycle.
+		match := resp.GetMatch()
+		id, ok := match.GetExtensions()["openmatch.synchronizer_cycle_id"]
+		require.True(t, ok)
+		if cycleID == nil {
+			cycleID = id
+		}
+		require.True(t, proto.Equal(cycleID, id))
+		delete(match.Extensions, "openmatch.synchronizer_cycle_id")
+
+		matches = append(matches, match)
 	}
 
 	require.ElementsMatch(t, expectedMatches, matches)
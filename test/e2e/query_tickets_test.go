@@ -107,6 +107,113 @@ func TestPaging(t *testing.T) {
 	require.Nil(t, resp)
 }
 
+func TestQueryTicketsPageSizeAndMaxResults(t *testing.T) {
+	om, closer := e2e.New(t)
+	defer closer()
+
+	fe := om.MustFrontendGRPC()
+	for i := 0; i < 5; i++ {
+		_, err := fe.CreateTicket(context.Background(), &pb.CreateTicketRequest{Ticket: &pb.Ticket{
+			SearchFields: &pb.SearchFields{DoubleArgs: map[string]float64{"mmr": float64(i)}},
+		}})
+		require.Nil(t, err)
+	}
+
+	q := om.MustQueryServiceGRPC()
+	stream, err := q.QueryTickets(context.Background(), &pb.QueryTicketsRequest{
+		Pool:       &pb.Pool{},
+		OrderBy:    &pb.TicketOrder{DoubleArg: "mmr", Descending: true},
+		PageSize:   2,
+		MaxResults: 3,
+	})
+	require.Nil(t, err)
+
+	var pages [][]float64
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.Nil(t, err)
+		var page []float64
+		for _, ticket := range resp.Tickets {
+			page = append(page, ticket.SearchFields.DoubleArgs["mmr"])
+		}
+		pages = append(pages, page)
+	}
+	require.Equal(t, [][]float64{{4, 3}, {2}}, pages)
+}
+
+func TestQueryTicketsSnapshot(t *testing.T) {
+	om, closer := e2e.New(t)
+	defer closer()
+
+	fe := om.MustFrontendGRPC()
+	q := om.MustQueryServiceGRPC()
+	query := func(req *pb.QueryTicketsRequest) (ids []string, token string) {
+		stream, err := q.QueryTickets(context.Background(), req)
+		require.Nil(t, err)
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				return ids, token
+			}
+			require.Nil(t, err)
+			token = resp.ConsistencyToken
+			for _, ticket := range resp.Tickets {
+				ids = append(ids, ticket.Id)
+			}
+		}
+	}
+
+	// The snapshot's token is returned even without tickets.
+	ids, token := query(&pb.QueryTicketsRequest{Pool: &pb.Pool{}, TakeSnapshot: true})
+	require.Empty(t, ids)
+	require.NotEmpty(t, token)
+
+	resp, err := fe.CreateTicket(context.Background(), &pb.CreateTicketRequest{Ticket: &pb.Ticket{}})
+	require.Nil(t, err)
+
+	ids, _ = query(&pb.QueryTicketsRequest{Pool: &pb.Pool{}, ConsistencyToken: token})
+	require.Empty(t, ids)
+	ids, _ = query(&pb.QueryTicketsRequest{Pool: &pb.Pool{}})
+	require.Equal(t, []string{resp.Ticket.Id}, ids)
+}
+
+func TestQueryTicketIds(t *testing.T) {
+	om, closer := e2e.New(t)
+	defer closer()
+
+	pageSize := 10
+	expectedIds := map[string]struct{}{}
+	fe := om.MustFrontendGRPC()
+	for i := 0; i < pageSize+1; i++ {
+		resp, err := fe.CreateTicket(context.Background(), &pb.CreateTicketRequest{Ticket: &pb.Ticket{}})
+		require.Nil(t, err)
+		expectedIds[resp.Ticket.Id] = struct{}{}
+	}
+
+	q := om.MustQueryServiceGRPC()
+	stream, err := q.QueryTicketIds(context.Background(), &pb.QueryTicketIdsRequest{Pool: &pb.Pool{}})
+	require.Nil(t, err)
+
+	foundIds := map[string]struct{}{}
+	pages := 0
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.Nil(t, err)
+		pages++
+		for _, id := range resp.Ids {
+			foundIds[id] = struct{}{}
+		}
+	}
+	require.Equal(t, 2, pages)
+	require.Equal(t, expectedIds, foundIds)
+}
+
 func TestTicketFound(t *testing.T) {
 	for _, tc := range testcases.IncludedTestCases() {
 		tc := tc
@@ -160,5 +267,10 @@ func returnedByQuery(t *testing.T, tc testcases.TestCase) (found bool) {
 		require.Fail(t, "More than one ticket found")
 	}
 
+	// The indices must agree with the filters.
+	stats, err := q.GetPoolStats(context.Background(), &pb.GetPoolStatsRequest{Pool: tc.Pool})
+	require.Nil(t, err)
+	require.Equal(t, int64(len(tickets)), stats.TicketCount)
+
 	return len(tickets) == 1
 }
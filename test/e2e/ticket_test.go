@@ -44,6 +44,7 @@ func TestAssignTickets(t *testing.T) {
 		ticketIds      []string
 		assignment     *pb.Assignment
 		wantAssignment *pb.Assignment
+		wantNotFound   []string
 		wantCode       codes.Code
 	}{
 		{
@@ -51,6 +52,7 @@ func TestAssignTickets(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 			codes.InvalidArgument,
 		},
 		{
@@ -58,27 +60,31 @@ func TestAssignTickets(t *testing.T) {
 			[]string{"1"},
 			nil,
 			nil,
+			nil,
 			codes.InvalidArgument,
 		},
 		{
-			"expects not found code since ticket id does not exist in the statestore",
+			"expects ticket id to be reported not found since it does not exist in the statestore",
 			[]string{"2"},
 			&pb.Assignment{Connection: "localhost"},
 			nil,
-			codes.NotFound,
+			[]string{"2"},
+			codes.OK,
 		},
 		{
-			"expects not found code since ticket id 'unknown id' does not exist in the statestore",
+			"expects ticket id 'unknown id' to be skipped since it does not exist in the statestore",
 			[]string{ctResp.GetTicket().GetId(), "unknown id"},
 			&pb.Assignment{Connection: "localhost"},
-			nil,
-			codes.NotFound,
+			&pb.Assignment{Connection: "localhost"},
+			[]string{"unknown id"},
+			codes.OK,
 		},
 		{
 			"expects ok code",
 			[]string{ctResp.GetTicket().GetId()},
 			&pb.Assignment{Connection: "localhost"},
 			&pb.Assignment{Connection: "localhost"},
+			nil,
 			codes.OK,
 		},
 	}
@@ -89,13 +95,18 @@ func TestAssignTickets(t *testing.T) {
 			t.Run(test.description, func(t *testing.T) {
 				t.Parallel()
 				ctx := om.Context()
-				_, err := be.AssignTickets(ctx, &pb.AssignTicketsRequest{TicketIds: test.ticketIds, Assignment: test.assignment})
+				resp, err := be.AssignTickets(ctx, &pb.AssignTicketsRequest{TicketIds: test.ticketIds, Assignment: test.assignment})
 				assert.Equal(t, test.wantCode, status.Convert(err).Code())
 
 				// If assign ticket succeeds, validate the assignment
 				if err == nil {
+					assert.Equal(t, test.wantNotFound, resp.GetNotFoundTicketIds())
 					for _, id := range test.ticketIds {
 						gtResp, err := fe.GetTicket(ctx, &pb.GetTicketRequest{TicketId: id})
+						if contains(test.wantNotFound, id) {
+							assert.Equal(t, codes.NotFound, status.Convert(err).Code())
+							continue
+						}
 						assert.Nil(t, err)
 						// grpc will write something to the reserved fields of this protobuf object, so we have to do comparisons fields by fields.
 						assert.Equal(t, test.wantAssignment.GetConnection(), gtResp.GetAssignment().GetConnection())
@@ -106,6 +117,15 @@ func TestAssignTickets(t *testing.T) {
 	})
 }
 
+func contains(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
 // TestTicketLifeCycle tests creating, getting and deleting a ticket using Frontend service.
 func TestTicketLifeCycle(t *testing.T) {
 	assert := assert.New(t)
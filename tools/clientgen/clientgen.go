@@ -0,0 +1,64 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main is the clientgen tool which generates and packages the C# and Node clients of the Open Match API,
+// along with its OpenAPI specs, as release artifacts.
+package main
+
+import (
+	"flag"
+	"log"
+	"strings"
+
+	clientgenInternal "open-match.dev/open-match/tools/clientgen/internal"
+)
+
+var (
+	// dependencyList is the list of protos outside of api/ which the API imports.
+	dependencyList = []string{
+		"google/api/annotations.proto",
+		"google/api/http.proto",
+		"google/rpc/status.proto",
+		"protoc-gen-swagger/options/annotations.proto",
+		"protoc-gen-swagger/options/openapiv2.proto",
+	}
+	versionFlag      = flag.String("version", "0.0.0-dev", "Version of the generated packages.")
+	protocFlag       = flag.String("protoc", "protoc", "Path to protoc.")
+	rootFlag         = flag.String("root", ".", "Path to the repository root.")
+	includesFlag     = flag.String("includes", "third_party", "Comma separated list of additional protoc include paths.")
+	dependenciesFlag = flag.String("dependencies", strings.Join(dependencyList, ","), "Comma separated list of imported protos to generate along with the API.")
+	csharpPluginFlag = flag.String("csharp_plugin", "grpc_csharp_plugin", "Path to the gRPC C# protoc plugin.")
+	nodePluginFlag   = flag.String("node_plugin", "grpc_node_plugin", "Path to the gRPC Node protoc plugin.")
+	outputFlag       = flag.String("output", "build/release/clients", "Directory the packages are written to.")
+)
+
+func main() {
+	flag.Parse()
+	packages, err := clientgenInternal.Generate(&clientgenInternal.Params{
+		Version:      *versionFlag,
+		Protoc:       *protocFlag,
+		Root:         *rootFlag,
+		Includes:     strings.Split(*includesFlag, ","),
+		Dependencies: strings.Split(*dependenciesFlag, ","),
+		CSharpPlugin: *csharpPluginFlag,
+		NodePlugin:   *nodePluginFlag,
+		OutputDir:    *outputFlag,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, p := range packages {
+		log.Printf("Created %s", p)
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package internal holds the internal details of generating and packaging the Open Match API clients.
+package internal
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Params for generating the clients of the Open Match API.
+type Params struct {
+	// Version of the generated packages, eg. 0.9.0.
+	Version string
+
+	// Path to protoc.
+	Protoc string
+	// Repository root, holding the api/ directory.  Protos are compiled relative to it.
+	Root string
+	// Additional include paths of protoc, eg. third_party/.
+	Includes []string
+	// Protos outside of api/ which the API imports, and must be generated along with it, relative to an include path.
+	Dependencies []string
+
+	// Path to the gRPC C# protoc plugin, grpc_csharp_plugin.
+	CSharpPlugin string
+	// Path to the gRPC Node protoc plugin, grpc_node_plugin.
+	NodePlugin string
+
+	// Directory the packages are written to.
+	OutputDir string
+}
+
+// language generates the client for one language.
+type language struct {
+	name string
+	// protocArgs are the arguments of protoc, besides include paths and protos, to generate into dir.
+	protocArgs func(params *Params, dir string) []string
+	// manifest is the name and content of the package manifest.
+	manifest func(params *Params) (string, string)
+}
+
+var languages = []*language{
+	{
+		name: "csharp",
+		protocArgs: func(params *Params, dir string) []string {
+			return []string{
+				"--plugin=protoc-gen-grpc=" + params.CSharpPlugin,
+				"--csharp_out=" + dir,
+				"--grpc_out=" + dir,
+			}
+		},
+		manifest: func(params *Params) (string, string) {
+			return "OpenMatch.csproj", fmt.Sprintf(csharpProject, params.Version)
+		},
+	},
+	{
+		name: "node",
+		protocArgs: func(params *Params, dir string) []string {
+			return []string{
+				"--plugin=protoc-gen-grpc=" + params.NodePlugin,
+				"--js_out=import_style=commonjs,binary:" + dir,
+				"--grpc_out=grpc_js:" + dir,
+			}
+		},
+		manifest: func(params *Params) (string, string) {
+			return "package.json", fmt.Sprintf(nodePackage, params.Version)
+		},
+	},
+}
+
+const csharpProject = `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFramework>netstandard2.0</TargetFramework>
+    <PackageId>OpenMatch</PackageId>
+    <Version>%s</Version>
+    <Authors>Open Match</Authors>
+    <Description>gRPC client of the Open Match API.</Description>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="Google.Protobuf" Version="3.10.1" />
+    <PackageReference Include="Grpc.Core" Version="2.25.0" />
+  </ItemGroup>
+</Project>
+`
+
+const nodePackage = `{
+  "name": "@open-match/client",
+  "version": "%s",
+  "description": "gRPC client of the Open Match API.",
+  "license": "Apache-2.0",
+  "dependencies": {
+    "@grpc/grpc-js": "^0.6.9",
+    "google-protobuf": "^3.10.0"
+  }
+}
+`
+
+// Generate generates the clients of every language and packages them, along
+// with the OpenAPI specs, into OutputDir.  It returns the paths of the packages.
+func Generate(params *Params) ([]string, error) {
+	protos, err := apiProtos(params.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	packages := []string{}
+	for _, l := range languages {
+		pkg, err := generateLanguage(params, l, protos)
+		if err != nil {
+			return nil, fmt.Errorf("cannot generate the %s client: %w", l.name, err)
+		}
+		packages = append(packages, pkg)
+	}
+
+	specs, err := filepath.Glob(filepath.Join(params.Root, "api", "*.swagger.json"))
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := packageFiles(params, "openapi", specs)
+	if err != nil {
+		return nil, fmt.Errorf("cannot package the OpenAPI specs: %w", err)
+	}
+	return append(packages, pkg), nil
+}
+
+// apiProtos returns the protos of api/, relative to root.
+func apiProtos(root string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(root, "api", "*.proto"))
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no protos found in %s", filepath.Join(root, "api"))
+	}
+	protos := []string{}
+	for _, m := range matches {
+		rel, err := filepath.Rel(root, m)
+		if err != nil {
+			return nil, err
+		}
+		protos = append(protos, filepath.ToSlash(rel))
+	}
+	sort.Strings(protos)
+	return protos, nil
+}
+
+// protocCommand returns the arguments of protoc generating the language into dir.
+func protocCommand(params *Params, l *language, protos []string, dir string) []string {
+	args := []string{"-I", params.Root}
+	for _, include := range params.Includes {
+		args = append(args, "-I", include)
+	}
+	args = append(args, l.protocArgs(params, dir)...)
+	args = append(args, params.Dependencies...)
+	return append(args, protos...)
+}
+
+func generateLanguage(params *Params, l *language, protos []string) (string, error) {
+	dir, err := ioutil.TempDir("", "clientgen-"+l.name)
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	cmd := exec.Command(params.Protoc, protocCommand(params, l, protos, dir)...)
+	cmd.Dir = params.Root
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("protoc failed: %w\n%s", err, out)
+	}
+
+	name, content := l.manifest(params)
+	if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		return "", err
+	}
+
+	files := []string{}
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			files = append(files, path)
+		}
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return packageDir(params, l.name, dir, files)
+}
+
+// packageFiles packages the files, flattened to their base name.
+func packageFiles(params *Params, name string, files []string) (string, error) {
+	if len(files) == 0 {
+		return "", fmt.Errorf("no files to package")
+	}
+	return packageDir(params, name, filepath.Dir(files[0]), files)
+}
+
+// packageDir writes the files to OutputDir/open-match-<name>-<version>.tar.gz,
+// under an open-match-<name>-<version>/ directory, at their path relative to dir.
+func packageDir(params *Params, name string, dir string, files []string) (string, error) {
+	base := fmt.Sprintf("open-match-%s-%s", name, params.Version)
+	if err := os.MkdirAll(params.OutputDir, 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(params.OutputDir, base+".tar.gz")
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	sort.Strings(files)
+	for _, file := range files {
+		rel, err := filepath.Rel(dir, file)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			rel = filepath.Base(file)
+		}
+		if err := addFile(tw, file, base+"/"+filepath.ToSlash(rel)); err != nil {
+			return "", err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return "", err
+	}
+	if err := gz.Close(); err != nil {
+		return "", err
+	}
+	return path, f.Close()
+}
+
+func addFile(tw *tar.Writer, file string, name string) error {
+	in, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	err = tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, in)
+	return err
+}
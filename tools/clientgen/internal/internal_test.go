@@ -0,0 +1,102 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtocCommand(t *testing.T) {
+	params := &Params{
+		Root:         "/om",
+		Includes:     []string{"/om/third_party"},
+		Dependencies: []string{"google/rpc/status.proto"},
+		CSharpPlugin: "/bin/grpc_csharp_plugin",
+	}
+	assert.Equal(t, []string{
+		"-I", "/om",
+		"-I", "/om/third_party",
+		"--plugin=protoc-gen-grpc=/bin/grpc_csharp_plugin",
+		"--csharp_out=/out",
+		"--grpc_out=/out",
+		"google/rpc/status.proto",
+		"api/frontend.proto",
+	}, protocCommand(params, languages[0], []string{"api/frontend.proto"}, "/out"))
+}
+
+func TestAPIProtos(t *testing.T) {
+	assert := assert.New(t)
+	root, err := ioutil.TempDir("", "clientgen")
+	assert.Nil(err)
+	defer os.RemoveAll(root)
+
+	_, err = apiProtos(root)
+	assert.NotNil(err)
+
+	assert.Nil(os.Mkdir(filepath.Join(root, "api"), 0755))
+	for _, name := range []string{"query.proto", "frontend.proto", "frontend.swagger.json"} {
+		assert.Nil(ioutil.WriteFile(filepath.Join(root, "api", name), nil, 0644))
+	}
+	protos, err := apiProtos(root)
+	assert.Nil(err)
+	assert.Equal([]string{"api/frontend.proto", "api/query.proto"}, protos)
+}
+
+func TestPackageFiles(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := ioutil.TempDir("", "clientgen")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	specs := []string{filepath.Join(dir, "query.swagger.json"), filepath.Join(dir, "frontend.swagger.json")}
+	for _, s := range specs {
+		assert.Nil(ioutil.WriteFile(s, []byte(filepath.Base(s)), 0644))
+	}
+
+	path, err := packageFiles(&Params{Version: "1.2.3", OutputDir: filepath.Join(dir, "out")}, "openapi", specs)
+	assert.Nil(err)
+	assert.Equal(filepath.Join(dir, "out", "open-match-openapi-1.2.3.tar.gz"), path)
+
+	f, err := os.Open(path)
+	assert.Nil(err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	assert.Nil(err)
+	tr := tar.NewReader(gz)
+
+	contents := map[string]string{}
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(err)
+		b, err := ioutil.ReadAll(tr)
+		assert.Nil(err)
+		contents[h.Name] = string(b)
+	}
+	assert.Equal(map[string]string{
+		"open-match-openapi-1.2.3/frontend.swagger.json": "frontend.swagger.json",
+		"open-match-openapi-1.2.3/query.swagger.json":    "query.swagger.json",
+	}, contents)
+}